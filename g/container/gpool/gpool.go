@@ -70,9 +70,19 @@ func (p *Pool) Put(value interface{}) {
     p.list.PushBack(item)
 }
 
-// 清空对象池
+// 清空对象池，被清空的对象将会执行销毁方法
 func (p *Pool) Clear() {
-    p.list.RemoveAll()
+    if p.ExpireFunc == nil {
+        p.list.RemoveAll()
+        return
+    }
+    for {
+        if r := p.list.PopFront(); r != nil {
+            p.ExpireFunc(r.(*poolItem).value)
+        } else {
+            break
+        }
+    }
 }
 
 // 从池中获得一个临时对象
@@ -83,6 +93,9 @@ func (p *Pool) Get() (interface{}, error) {
             if f.expire == 0 || f.expire > gtime.Millisecond() {
                 return f.value, nil
             }
+            if p.ExpireFunc != nil {
+                p.ExpireFunc(f.value)
+            }
         } else {
             break
         }
@@ -4,4 +4,19 @@
 // If a copy of the MIT was not distributed with this file,
 // You can obtain one at https://github.com/gogf/gf.
 
+// Package crypto provides common helpers shared by the crypto packages.
 package crypto
+
+import (
+    "crypto/subtle"
+)
+
+// 常量时间比较两个[]byte是否相等(比较耗时与内容无关)，用于比较签名、token等敏感数据，防止时序攻击
+func Equal(a, b []byte) bool {
+    return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// 常量时间比较两个字符串是否相等，参考Equal
+func EqualString(a, b string) bool {
+    return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
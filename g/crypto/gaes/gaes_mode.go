@@ -0,0 +1,184 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gaes
+
+import (
+    "bytes"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+    "io"
+)
+
+// AES加密，使用CBC模式及PKCS7填充，key必须为16/24/32位长度。
+// 未指定iv时随机生成iv并放在密文的开头(推荐)，解密时使用DecryptCBC并同样不指定iv即可。
+func EncryptCBC(plainText []byte, key []byte, iv...[]byte) ([]byte, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    var (
+        ivValue []byte
+        prefix  []byte
+    )
+    if len(iv) > 0 {
+        ivValue = iv[0]
+    } else {
+        ivValue = make([]byte, block.BlockSize())
+        if _, err := io.ReadFull(rand.Reader, ivValue); err != nil {
+            return nil, err
+        }
+        prefix = ivValue
+    }
+    if len(ivValue) != block.BlockSize() {
+        return nil, errors.New("iv length must equal block size")
+    }
+    plainText  = PKCS7Padding(plainText, block.BlockSize())
+    cipherText := make([]byte, len(prefix) + len(plainText))
+    copy(cipherText, prefix)
+    cipher.NewCBCEncrypter(block, ivValue).CryptBlocks(cipherText[len(prefix) : ], plainText)
+    return cipherText, nil
+}
+
+// AES解密，使用CBC模式及PKCS7填充，未指定iv时从密文的开头读取iv(对应EncryptCBC未指定iv的情况)
+func DecryptCBC(cipherText []byte, key []byte, iv...[]byte) ([]byte, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    blockSize := block.BlockSize()
+    ivValue   := ([]byte)(nil)
+    if len(iv) > 0 {
+        ivValue = iv[0]
+    } else {
+        if len(cipherText) < blockSize {
+            return nil, errors.New("cipherText too short")
+        }
+        ivValue, cipherText = cipherText[ : blockSize], cipherText[blockSize : ]
+    }
+    if len(ivValue) != blockSize {
+        return nil, errors.New("iv length must equal block size")
+    }
+    if len(cipherText) == 0 || len(cipherText) % blockSize != 0 {
+        return nil, errors.New("cipherText is not a multiple of the block size")
+    }
+    plainText := make([]byte, len(cipherText))
+    cipher.NewCBCDecrypter(block, ivValue).CryptBlocks(plainText, cipherText)
+    return PKCS7UnPadding(plainText, blockSize)
+}
+
+// AES加密，使用GCM模式(带认证的加密)，key必须为16/24/32位长度，
+// 随机生成的nonce放在密文的开头，additionalData为可选的附加认证数据(解密时必须一致)。
+func EncryptGCM(plainText []byte, key []byte, additionalData...[]byte) ([]byte, error) {
+    gcm, err := newGCM(key)
+    if err != nil {
+        return nil, err
+    }
+    nonce := make([]byte, gcm.NonceSize())
+    if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+        return nil, err
+    }
+    return gcm.Seal(nonce, nonce, plainText, getAdditionalData(additionalData)), nil
+}
+
+// AES解密，使用GCM模式，密文被篡改或者key/additionalData不正确时返回错误
+func DecryptGCM(cipherText []byte, key []byte, additionalData...[]byte) ([]byte, error) {
+    gcm, err := newGCM(key)
+    if err != nil {
+        return nil, err
+    }
+    if len(cipherText) < gcm.NonceSize() + gcm.Overhead() {
+        return nil, errors.New("cipherText too short")
+    }
+    nonce := cipherText[ : gcm.NonceSize()]
+    return gcm.Open(nil, nonce, cipherText[gcm.NonceSize() : ], getAdditionalData(additionalData))
+}
+
+// 使用CBC模式加密字符串，返回base64编码的密文(随机iv)
+func EncryptCBCString(plainText string, key string) (string, error) {
+    b, err := EncryptCBC([]byte(plainText), []byte(key))
+    if err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// 使用CBC模式解密base64编码的密文(EncryptCBCString的结果)
+func DecryptCBCString(cipherText string, key string) (string, error) {
+    b, err := base64.StdEncoding.DecodeString(cipherText)
+    if err != nil {
+        return "", err
+    }
+    if b, err = DecryptCBC(b, []byte(key)); err != nil {
+        return "", err
+    }
+    return string(b), nil
+}
+
+// 使用GCM模式加密字符串，返回base64编码的密文
+func EncryptGCMString(plainText string, key string) (string, error) {
+    b, err := EncryptGCM([]byte(plainText), []byte(key))
+    if err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// 使用GCM模式解密base64编码的密文(EncryptGCMString的结果)
+func DecryptGCMString(cipherText string, key string) (string, error) {
+    b, err := base64.StdEncoding.DecodeString(cipherText)
+    if err != nil {
+        return "", err
+    }
+    if b, err = DecryptGCM(b, []byte(key)); err != nil {
+        return "", err
+    }
+    return string(b), nil
+}
+
+// PKCS7填充，blockSize为1-255
+func PKCS7Padding(src []byte, blockSize int) []byte {
+    padding := blockSize - len(src) % blockSize
+    result  := make([]byte, len(src), len(src) + padding)
+    copy(result, src)
+    return append(result, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+// 去掉PKCS7填充，填充数据不合法时返回错误(例如key不正确)
+func PKCS7UnPadding(src []byte, blockSize int) ([]byte, error) {
+    length := len(src)
+    if length == 0 || length % blockSize != 0 {
+        return nil, errors.New("invalid padding size")
+    }
+    padding := int(src[length - 1])
+    if padding == 0 || padding > blockSize || padding > length {
+        return nil, errors.New("invalid padding")
+    }
+    for _, b := range src[length - padding : ] {
+        if int(b) != padding {
+            return nil, errors.New("invalid padding")
+        }
+    }
+    return src[ : length - padding], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    return cipher.NewGCM(block)
+}
+
+func getAdditionalData(additionalData [][]byte) []byte {
+    if len(additionalData) > 0 {
+        return additionalData[0]
+    }
+    return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gaes_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/crypto/gaes"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_CBC(t *testing.T) {
+    gtest.Case(t, func() {
+        key  := []byte("1234567890123456")
+        data := []byte("GoFrame is a modular framework")
+        // 随机iv
+        b, err := gaes.EncryptCBC(data, key)
+        gtest.Assert(err, nil)
+        d, err := gaes.DecryptCBC(b, key)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(d), string(data))
+        // 指定iv
+        iv     := []byte("abcdefghijklmnop")
+        b, err  = gaes.EncryptCBC(data, key, iv)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(b), 32)
+        d, err  = gaes.DecryptCBC(b, key, iv)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(d), string(data))
+        // 错误的key
+        _, err  = gaes.DecryptCBC(b, []byte("6543210987654321"), iv)
+        gtest.AssertNE(err, nil)
+        // 字符串
+        s, err := gaes.EncryptCBCString("john", "1234567890123456")
+        gtest.Assert(err, nil)
+        r, err := gaes.DecryptCBCString(s, "1234567890123456")
+        gtest.Assert(err, nil)
+        gtest.Assert(r, "john")
+    })
+}
+
+func Test_GCM(t *testing.T) {
+    gtest.Case(t, func() {
+        key  := []byte("12345678901234567890123456789012")
+        data := []byte("GoFrame")
+        b, err := gaes.EncryptGCM(data, key, []byte("ad"))
+        gtest.Assert(err, nil)
+        d, err := gaes.DecryptGCM(b, key, []byte("ad"))
+        gtest.Assert(err, nil)
+        gtest.Assert(string(d), string(data))
+        // 附加数据不一致
+        _, err  = gaes.DecryptGCM(b, key)
+        gtest.AssertNE(err, nil)
+        // 密文被篡改
+        b[len(b) - 1] ^= 1
+        _, err  = gaes.DecryptGCM(b, key, []byte("ad"))
+        gtest.AssertNE(err, nil)
+        // 字符串
+        s, err := gaes.EncryptGCMString("john", string(key))
+        gtest.Assert(err, nil)
+        r, err := gaes.DecryptGCMString(s, string(key))
+        gtest.Assert(err, nil)
+        gtest.Assert(r, "john")
+    })
+}
+
+func Test_PKCS7(t *testing.T) {
+    gtest.Case(t, func() {
+        b := gaes.PKCS7Padding([]byte("abc"), 8)
+        gtest.Assert(b, []byte{'a', 'b', 'c', 5, 5, 5, 5, 5})
+        r, err := gaes.PKCS7UnPadding(b, 8)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(r), "abc")
+        gtest.Assert(len(gaes.PKCS7Padding([]byte("12345678"), 8)), 16)
+        _, err  = gaes.PKCS7UnPadding([]byte{'a', 'b', 'c', 5, 5, 5, 4, 5}, 8)
+        gtest.AssertNE(err, nil)
+        _, err  = gaes.PKCS7UnPadding([]byte{'a', 'b', 'c', 0, 0, 0, 0, 0}, 8)
+        gtest.AssertNE(err, nil)
+    })
+}
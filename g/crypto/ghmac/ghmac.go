@@ -0,0 +1,64 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package ghmac provides useful API for HMAC(SHA256/SHA512) message authentication.
+package ghmac
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/hex"
+    "hash"
+)
+
+// 计算HMAC-SHA256
+func SHA256(data []byte, key []byte) []byte {
+    return sum(sha256.New, data, key)
+}
+
+// 计算HMAC-SHA512
+func SHA512(data []byte, key []byte) []byte {
+    return sum(sha512.New, data, key)
+}
+
+// 计算HMAC-SHA256，返回十六进制字符串
+func SHA256String(data string, key string) string {
+    return hex.EncodeToString(SHA256([]byte(data), []byte(key)))
+}
+
+// 计算HMAC-SHA512，返回十六进制字符串
+func SHA512String(data string, key string) string {
+    return hex.EncodeToString(SHA512([]byte(data), []byte(key)))
+}
+
+// 校验HMAC-SHA256，使用常量时间比较防止时序攻击
+func VerifySHA256(data []byte, mac []byte, key []byte) bool {
+    return hmac.Equal(mac, SHA256(data, key))
+}
+
+// 校验HMAC-SHA512，使用常量时间比较防止时序攻击
+func VerifySHA512(data []byte, mac []byte, key []byte) bool {
+    return hmac.Equal(mac, SHA512(data, key))
+}
+
+// 校验十六进制字符串形式的HMAC-SHA256(不区分大小写)
+func VerifySHA256String(data string, mac string, key string) bool {
+    b, err := hex.DecodeString(mac)
+    return err == nil && VerifySHA256([]byte(data), b, []byte(key))
+}
+
+// 校验十六进制字符串形式的HMAC-SHA512(不区分大小写)
+func VerifySHA512String(data string, mac string, key string) bool {
+    b, err := hex.DecodeString(mac)
+    return err == nil && VerifySHA512([]byte(data), b, []byte(key))
+}
+
+func sum(h func() hash.Hash, data []byte, key []byte) []byte {
+    mac := hmac.New(h, key)
+    mac.Write(data)
+    return mac.Sum(nil)
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package ghmac_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/crypto/ghmac"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_HMAC(t *testing.T) {
+    gtest.Case(t, func() {
+        data := "The quick brown fox jumps over the lazy dog"
+        mac  := ghmac.SHA256String(data, "key")
+        gtest.Assert(mac, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")
+        gtest.Assert(ghmac.VerifySHA256String(data, mac, "key"), true)
+        gtest.Assert(ghmac.VerifySHA256String(data, mac, "key2"), false)
+        gtest.Assert(ghmac.VerifySHA256String(data, "invalid", "key"), false)
+        mac   = ghmac.SHA512String(data, "key")
+        gtest.Assert(mac, "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a")
+        gtest.Assert(ghmac.VerifySHA512String(data, mac, "key"), true)
+        gtest.Assert(len(ghmac.SHA512([]byte(data), []byte("key"))), 64)
+    })
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package grsa provides useful API for RSA encryption/decryption and signature algorithms.
+//
+// RSA加密/解密及签名/验签，加密使用OAEP(SHA256)填充，签名使用PKCS#1 v1.5(SHA256)，
+// 密钥支持PEM格式的PKCS#1/PKCS#8私钥及PKIX/PKCS#1公钥(以及X.509证书)。
+package grsa
+
+import (
+    "crypto"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/pem"
+    "errors"
+    "fmt"
+    "io/ioutil"
+)
+
+// 生成RSA密钥对，返回PEM格式的私钥(PKCS#1)及公钥(PKIX)，bits通常为2048或者4096
+func GenerateKey(bits int) (privateKey []byte, publicKey []byte, err error) {
+    key, err := rsa.GenerateKey(rand.Reader, bits)
+    if err != nil {
+        return nil, nil, err
+    }
+    publicBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+    if err != nil {
+        return nil, nil, err
+    }
+    privateKey = pem.EncodeToMemory(&pem.Block {
+        Type  : "RSA PRIVATE KEY",
+        Bytes : x509.MarshalPKCS1PrivateKey(key),
+    })
+    publicKey = pem.EncodeToMemory(&pem.Block {
+        Type  : "PUBLIC KEY",
+        Bytes : publicBytes,
+    })
+    return
+}
+
+// 解析PEM格式的私钥，支持PKCS#1(RSA PRIVATE KEY)及PKCS#8(PRIVATE KEY)格式
+func LoadPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, errors.New("invalid PEM private key")
+    }
+    if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+        return key, nil
+    }
+    key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+    if err != nil {
+        return nil, err
+    }
+    if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+        return rsaKey, nil
+    }
+    return nil, errors.New(fmt.Sprintf("not a RSA private key: %T", key))
+}
+
+// 解析PEM格式的公钥，支持PKIX(PUBLIC KEY)、PKCS#1(RSA PUBLIC KEY)格式及X.509证书(CERTIFICATE)
+func LoadPublicKey(data []byte) (*rsa.PublicKey, error) {
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, errors.New("invalid PEM public key")
+    }
+    var key interface{}
+    switch block.Type {
+        case "CERTIFICATE":
+            cert, err := x509.ParseCertificate(block.Bytes)
+            if err != nil {
+                return nil, err
+            }
+            key = cert.PublicKey
+        case "RSA PUBLIC KEY":
+            return x509.ParsePKCS1PublicKey(block.Bytes)
+        default:
+            k, err := x509.ParsePKIXPublicKey(block.Bytes)
+            if err != nil {
+                return nil, err
+            }
+            key = k
+    }
+    if rsaKey, ok := key.(*rsa.PublicKey); ok {
+        return rsaKey, nil
+    }
+    return nil, errors.New(fmt.Sprintf("not a RSA public key: %T", key))
+}
+
+// 从文件读取PEM格式的私钥
+func LoadPrivateKeyFile(path string) (*rsa.PrivateKey, error) {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return LoadPrivateKey(data)
+}
+
+// 从文件读取PEM格式的公钥
+func LoadPublicKeyFile(path string) (*rsa.PublicKey, error) {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return LoadPublicKey(data)
+}
+
+// 使用公钥加密(OAEP SHA256)，明文长度不能超过 密钥字节数-66
+func Encrypt(plainText []byte, key *rsa.PublicKey) ([]byte, error) {
+    return rsa.EncryptOAEP(sha256.New(), rand.Reader, key, plainText, nil)
+}
+
+// 使用私钥解密(OAEP SHA256)
+func Decrypt(cipherText []byte, key *rsa.PrivateKey) ([]byte, error) {
+    return rsa.DecryptOAEP(sha256.New(), rand.Reader, key, cipherText, nil)
+}
+
+// 使用公钥加密(PKCS#1 v1.5填充)，用于兼容旧系统，新系统建议使用Encrypt
+func EncryptPKCS1v15(plainText []byte, key *rsa.PublicKey) ([]byte, error) {
+    return rsa.EncryptPKCS1v15(rand.Reader, key, plainText)
+}
+
+// 使用私钥解密(PKCS#1 v1.5填充)
+func DecryptPKCS1v15(cipherText []byte, key *rsa.PrivateKey) ([]byte, error) {
+    return rsa.DecryptPKCS1v15(rand.Reader, key, cipherText)
+}
+
+// 使用私钥对数据进行签名(PKCS#1 v1.5 SHA256)
+func Sign(data []byte, key *rsa.PrivateKey) ([]byte, error) {
+    hash := sha256.Sum256(data)
+    return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+}
+
+// 使用公钥校验签名，校验失败时返回错误
+func Verify(data []byte, signature []byte, key *rsa.PublicKey) error {
+    hash := sha256.Sum256(data)
+    return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature)
+}
+
+// 使用PEM格式的公钥加密字符串，返回base64编码的密文
+func EncryptString(plainText string, publicKey string) (string, error) {
+    key, err := LoadPublicKey([]byte(publicKey))
+    if err != nil {
+        return "", err
+    }
+    b, err := Encrypt([]byte(plainText), key)
+    if err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// 使用PEM格式的私钥解密base64编码的密文
+func DecryptString(cipherText string, privateKey string) (string, error) {
+    key, err := LoadPrivateKey([]byte(privateKey))
+    if err != nil {
+        return "", err
+    }
+    b, err := base64.StdEncoding.DecodeString(cipherText)
+    if err != nil {
+        return "", err
+    }
+    if b, err = Decrypt(b, key); err != nil {
+        return "", err
+    }
+    return string(b), nil
+}
+
+// 使用PEM格式的私钥对字符串签名，返回base64编码的签名
+func SignString(data string, privateKey string) (string, error) {
+    key, err := LoadPrivateKey([]byte(privateKey))
+    if err != nil {
+        return "", err
+    }
+    b, err := Sign([]byte(data), key)
+    if err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// 使用PEM格式的公钥校验base64编码的签名，校验失败时返回错误
+func VerifyString(data string, signature string, publicKey string) error {
+    key, err := LoadPublicKey([]byte(publicKey))
+    if err != nil {
+        return err
+    }
+    b, err := base64.StdEncoding.DecodeString(signature)
+    if err != nil {
+        return err
+    }
+    return Verify([]byte(data), b, key)
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package grsa_test
+
+import (
+    "crypto/x509"
+    "encoding/pem"
+    "testing"
+    "github.com/gogf/gf/g/crypto/grsa"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_RSA(t *testing.T) {
+    gtest.Case(t, func() {
+        privatePem, publicPem, err := grsa.GenerateKey(1024)
+        gtest.Assert(err, nil)
+        privateKey, err := grsa.LoadPrivateKey(privatePem)
+        gtest.Assert(err, nil)
+        publicKey, err  := grsa.LoadPublicKey(publicPem)
+        gtest.Assert(err, nil)
+        // 加密/解密
+        b, err := grsa.Encrypt([]byte("GoFrame"), publicKey)
+        gtest.Assert(err, nil)
+        d, err := grsa.Decrypt(b, privateKey)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(d), "GoFrame")
+        b, err  = grsa.EncryptPKCS1v15([]byte("GoFrame"), publicKey)
+        gtest.Assert(err, nil)
+        d, err  = grsa.DecryptPKCS1v15(b, privateKey)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(d), "GoFrame")
+        // 签名/验签
+        sign, err := grsa.Sign([]byte("GoFrame"), privateKey)
+        gtest.Assert(err, nil)
+        gtest.Assert(grsa.Verify([]byte("GoFrame"), sign, publicKey), nil)
+        gtest.AssertNE(grsa.Verify([]byte("gf"), sign, publicKey), nil)
+        // 字符串
+        s, err := grsa.EncryptString("john", string(publicPem))
+        gtest.Assert(err, nil)
+        r, err := grsa.DecryptString(s, string(privatePem))
+        gtest.Assert(err, nil)
+        gtest.Assert(r, "john")
+        s, err  = grsa.SignString("john", string(privatePem))
+        gtest.Assert(err, nil)
+        gtest.Assert(grsa.VerifyString("john", s, string(publicPem)), nil)
+        gtest.AssertNE(grsa.VerifyString("smith", s, string(publicPem)), nil)
+        // PKCS#8私钥及PKCS#1公钥
+        pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+        gtest.Assert(err, nil)
+        key8, err  := grsa.LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type : "PRIVATE KEY", Bytes : pkcs8}))
+        gtest.Assert(err, nil)
+        gtest.Assert(key8.N.String(), privateKey.N.String())
+        pub1, err  := grsa.LoadPublicKey(pem.EncodeToMemory(&pem.Block{
+            Type  : "RSA PUBLIC KEY",
+            Bytes : x509.MarshalPKCS1PublicKey(publicKey),
+        }))
+        gtest.Assert(err, nil)
+        gtest.Assert(pub1.N.String(), publicKey.N.String())
+        // 非法的密钥
+        _, err = grsa.LoadPrivateKey([]byte("invalid"))
+        gtest.AssertNE(err, nil)
+    })
+}
@@ -11,13 +11,18 @@
 package gdb
 
 import (
+    "context"
     "database/sql"
     "errors"
     "fmt"
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/container/gring"
     "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/container/gvar"
     "github.com/gogf/gf/g/os/gcache"
+    "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/os/gtimer"
     "github.com/gogf/gf/g/util/grand"
     _ "github.com/gogf/gf/third/github.com/go-sql-driver/mysql"
     "time"
@@ -37,10 +42,11 @@ type DB interface {
     doQuery(link dbLink, query string, args ...interface{}) (rows *sql.Rows, err error)
     doExec(link dbLink, query string, args ...interface{}) (result sql.Result, err error)
     doPrepare(link dbLink, query string) (*sql.Stmt, error)
-    doInsert(link dbLink, table string, data interface{}, option int, batch...int) (result sql.Result, err error)
-    doBatchInsert(link dbLink, table string, list interface{}, option int, batch...int) (result sql.Result, err error)
+    doInsert(link dbLink, table string, data interface{}, option *insertOption) (result sql.Result, err error)
+    doBatchInsert(link dbLink, table string, list interface{}, option *insertOption) (result sql.Result, err error)
     doUpdate(link dbLink, table string, data interface{}, condition interface{}, args ...interface{}) (result sql.Result, err error)
     doDelete(link dbLink, table string, condition interface{}, args ...interface{}) (result sql.Result, err error)
+    doGetAll(link dbLink, query string, args ...interface{}) (result Result, err error)
 
 	// 数据库查询
 	GetAll(query string, args ...interface{}) (Result, error)
@@ -49,6 +55,11 @@ type DB interface {
     GetCount(query string, args ...interface{}) (int, error)
     GetStruct(obj interface{}, query string, args ...interface{}) error
 
+    // 数据库结构信息
+    Tables() ([]string, error)
+    TableFields(table string) ([]*TableField, error)
+    TableIndexes(table string) ([]*TableIndex, error)
+
     // 创建底层数据库master/slave链接对象
     Master() (*sql.DB, error)
     Slave() (*sql.DB, error)
@@ -57,6 +68,9 @@ type DB interface {
 	PingMaster() error
 	PingSlave() error
 
+    // 关闭数据库对象(停止健康检查任务并关闭底层链接池)
+    Close() error
+
 	// 开启事务操作
 	Begin() (*TX, error)
 
@@ -74,6 +88,9 @@ type DB interface {
 	Update(table string, data interface{}, condition interface{}, args ...interface{}) (sql.Result, error)
 	Delete(table string, condition interface{}, args ...interface{}) (sql.Result, error)
 
+	// 创建绑定上下文对象的数据库操作对象
+	Ctx(ctx context.Context) DB
+
 	// 创建链式操作对象(Table为From的别名)
 	Table(tables string) *Model
 	From(tables string) *Model
@@ -86,29 +103,52 @@ type DB interface {
     SetMaxIdleConns(n int)
     SetMaxOpenConns(n int)
     SetConnMaxLifetime(n int)
+    SetLoadBalance(strategy int)
+    SetHealthCheckInterval(n int)
+    Stats() []NodeStats
+    SetLogger(logger *glog.Logger)
+    SetSlowThreshold(ms int)
+    SetLogRedacted(enabled bool)
+    SetShardRule(table string, key string, rule ShardRule)
+    AddHook(event string, f HookFunc)
+    AddInterceptor(interceptor Interceptor)
+    SetQueryCache(cache QueryCache)
+    ClearCache(names...string)
+    ClearCacheByTable(tables...string)
+    SetDecimalType(t string)
 
 	// 内部方法接口
 	getCache() (*gcache.Cache)
+	getCtx() context.Context
 	getChars() (charLeft string, charRight string)
 	getDebug() bool
     filterFields(table string, data map[string]interface{}) map[string]interface{}
     convertValue(fieldValue interface{}, fieldType string) interface{}
+    convertParam(table string, field string, value interface{}) interface{}
     getTableFields(table string) (map[string]string, error)
     rowsToResult(rows *sql.Rows) (Result, error)
     handleSqlBeforeExec(sql string) string
+    formatUpsert(columns []string, option *insertOption) (string, error)
+    getShardConfig(table string) *shardConfig
+    getHooks(event string) []HookFunc
+    getQueryCache() QueryCache
+    getMaxBatchNum(columns int) int
 }
 
-// 执行底层数据库操作的核心接口
+// 执行底层数据库操作的核心接口(*sql.DB/*sql.Tx)
 type dbLink interface {
-    Query(query string, args ...interface{}) (*sql.Rows, error)
-    Exec(sql string, args ...interface{}) (sql.Result, error)
-    Prepare(sql string) (*sql.Stmt, error)
+    QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+    ExecContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error)
+    PrepareContext(ctx context.Context, sql string) (*sql.Stmt, error)
 }
 
 // 数据库链接对象
 type dbBase struct {
 	db               DB                           // 数据库对象
+	dbType           string                       // 数据库类型
 	group            string                       // 配置分组名称
+	ctx              context.Context              // 绑定的上下文对象
+    derived          bool                         // 是否为Ctx创建的派生对象(与原有对象共享底层链接池，Close时不做处理)
 	debug            *gtype.Bool                  // (默认关闭)是否开启调试模式，当开启时会启用一些调试特性
 	sqls             *gring.Ring                  // (debug=true时有效)已执行的SQL列表
 	cache            *gcache.Cache                // 数据库缓存，包括底层连接池对象缓存及查询缓存；需要注意的是，事务查询不支持查询缓存
@@ -117,16 +157,63 @@ type dbBase struct {
 	maxIdleConnCount *gtype.Int                   // 连接池最大限制的连接数
     maxOpenConnCount *gtype.Int                   // 连接池最大打开的连接数
     maxConnLifetime  *gtype.Int                   // (单位秒)连接对象可重复使用的时间长度
+    loadBalance      *gtype.Int                   // 集群节点的负载均衡策略
+    roundRobin       [2]*gtype.Int                // 轮询负载均衡策略的计数器(0:slave, 1:master)
+    healthInterval   *gtype.Int                   // (单位秒)集群节点健康检查的时间间隔
+    nodeHealth       *gmap.StringInterfaceMap     // 集群节点的健康检查状态
+    healthCheckTime  *gtype.Int64                 // (单位秒)最近一次定时健康检查的时间
+    healthEntry      *gtimer.Entry                // 定时健康检查任务(Close时停止)
+    logger           *gtype.Interface             // 自定义的SQL日志对象(*glog.Logger)
+    slowThreshold    *gtype.Int                   // (单位毫秒)慢查询日志阈值
+    logRedacted      *gtype.Bool                  // SQL日志是否对预处理参数进行脱敏
+    linkTags         *gmap.Map                    // 底层链接对象(*sql.DB)对应的标签名称
+    shardRules       *gmap.StringInterfaceMap     // 数据表的分片规则(逻辑表名称 => *shardConfig)
+    hooks            *gmap.StringInterfaceMap     // 全局钩子函数(事件名称 => []HookFunc)
+    interceptors     *garray.Array                // SQL语句拦截器列表([]Interceptor)
+    queryCache       *gtype.Interface             // 查询缓存对象(QueryCache)
+    decimalType      *gtype.String                // 高精度数值类型的转换类型(DECIMAL_TYPE_*)
 }
 
 // 执行的SQL对象
 type Sql struct {
-	Sql   string        // SQL语句(可能带有预处理占位符)
-	Args  []interface{} // 预处理参数值列表
-	Error error         // 执行结果(nil为成功)
-	Start int64         // 执行开始时间(毫秒)
-	End   int64         // 执行结束时间(毫秒)
-	Func  string        // 执行方法
+	Sql    string          // SQL语句(可能带有预处理占位符)
+	Args   []interface{}   // 预处理参数值列表
+	Error  error           // 执行结果(nil为成功)
+	Start  int64           // 执行开始时间(毫秒)
+	End    int64           // 执行结束时间(毫秒)
+	Func   string          // 执行方法
+	Group  string          // 数据库配置分组名称
+	Link   string          // 执行链接的标签名称(角色@地址，事务为tx)
+	Caller string          // 业务层调用位置(文件:行号)
+	Ctx    context.Context // 执行时绑定的上下文对象(没有绑定时为nil)
+}
+
+// 数据写入选项(Insert/Replace/Save及其批量操作)
+type insertOption struct {
+    option      int      // 写入方式：OPTION_INSERT/OPTION_REPLACE/OPTION_SAVE/OPTION_IGNORE
+    batch       int      // 批量写入时每批次的记录数量
+    onConflict  []string // (Save)冲突检测字段，用于PostgreSQL/SQLite的ON CONFLICT语句
+    onDuplicate []string // (Save)冲突时需要更新的字段，为空时更新所有写入的字段
+}
+
+// 数据表字段信息
+type TableField struct {
+    Index   int         // 字段在数据表中的顺序(从0开始)
+    Name    string      // 字段名称
+    Type    string      // 字段类型(数据库原始类型，例如: int(10) unsigned, varchar(45))
+    Null    bool        // 是否允许为NULL
+    Key     string      // 索引类型(PRI/UNI/MUL，不同数据库可能为空)
+    Default interface{} // 默认值
+    Extra   string      // 额外信息(例如: auto_increment)
+    Comment string      // 字段注释
+}
+
+// 数据表索引信息
+type TableIndex struct {
+    Name    string   // 索引名称
+    Unique  bool     // 是否唯一索引
+    Primary bool     // 是否主键
+    Columns []string // 索引字段列表(按照索引中的顺序)
 }
 
 // 返回数据表记录值
@@ -144,6 +231,13 @@ type Map  = map[string]interface{}
 // 关联数组列表(索引从0开始的数组)，绑定多条记录(使用别名)
 type List = []Map
 
+// 高精度数值类型(DECIMAL/NUMERIC)的转换类型
+const (
+    DECIMAL_TYPE_FLOAT64 = "float64" // 转换为float64(默认)
+    DECIMAL_TYPE_STRING  = "string"  // 转换为string，不损失精度
+    DECIMAL_TYPE_RAT     = "rat"     // 转换为*big.Rat，不损失精度并且可以直接进行计算
+)
+
 const (
     OPTION_INSERT  = 0
     OPTION_REPLACE = 1
@@ -153,9 +247,25 @@ const (
     gDEFAULT_BATCH_NUM          = 10
     // 默认的连接池连接存活时间(秒)
     gDEFAULT_CONN_MAX_LIFE_TIME = 30
+    // 默认的从库节点健康检查时间间隔(秒)
+    gDEFAULT_HEALTH_CHECK_INTERVAL = 10
+    // *big.Rat参数值格式化为字符串时的最大小数位数
+    gDECIMAL_RAT_PRECISION = 30
 
 )
 
+// 数据库类型与对应数据库操作对象的创建方法映射表，
+// 键名为配置节点中的Type值，需要注意的是，除MySQL以外的数据库需要手动import对应的数据库驱动。
+var driverMap = map[string]func(base *dbBase) DB {
+    "mysql"      : func(base *dbBase) DB { return &dbMysql{dbBase      : base} },
+    "pgsql"      : func(base *dbBase) DB { return &dbPgsql{dbBase      : base} },
+    "mssql"      : func(base *dbBase) DB { return &dbMssql{dbBase      : base} },
+    "sqlite"     : func(base *dbBase) DB { return &dbSqlite{dbBase     : base} },
+    "sqlite3"    : func(base *dbBase) DB { return &dbSqlite{dbBase     : base} },
+    "oracle"     : func(base *dbBase) DB { return &dbOracle{dbBase     : base} },
+    "clickhouse" : func(base *dbBase) DB { return &dbClickhouse{dbBase : base} },
+}
+
 // 使用默认/指定分组配置进行连接，数据库集群配置项：default
 func New(groupName ...string) (db DB, err error) {
 	group := config.d
@@ -171,28 +281,33 @@ func New(groupName ...string) (db DB, err error) {
 	if _, ok := config.c[group]; ok {
 	    if node, err := getConfigNodeByGroup(group, true); err == nil {
 	        base := &dbBase {
+                dbType           : node.Type,
                 group            : group,
                 debug            : gtype.NewBool(),
                 cache            : gcache.New(),
                 schema           : gtype.NewString(),
                 maxIdleConnCount : gtype.NewInt(),
                 maxOpenConnCount : gtype.NewInt(),
-                maxConnLifetime  : gtype.NewInt(gDEFAULT_CONN_MAX_LIFE_TIME),
+                maxConnLifetime  : gtype.NewInt(),
+                loadBalance      : gtype.NewInt(LOAD_BALANCE_WEIGHT),
+                roundRobin       : [2]*gtype.Int{gtype.NewInt(), gtype.NewInt()},
+                healthInterval   : gtype.NewInt(gDEFAULT_HEALTH_CHECK_INTERVAL),
+                nodeHealth       : gmap.NewStringInterfaceMap(),
+                healthCheckTime  : gtype.NewInt64(),
+                logger           : gtype.NewInterface(),
+                slowThreshold    : gtype.NewInt(),
+                logRedacted      : gtype.NewBool(),
+                linkTags         : gmap.New(),
+                shardRules       : gmap.NewStringInterfaceMap(),
+                hooks            : gmap.NewStringInterfaceMap(),
+                interceptors     : garray.New(),
+                queryCache       : gtype.NewInterface(NewMemQueryCache()),
+                decimalType      : gtype.NewString(node.DecimalType),
             }
-            switch node.Type {
-                case "mysql":
-                    base.db = &dbMysql{dbBase  : base}
-                case "pgsql":
-                    base.db = &dbPgsql{dbBase  : base}
-                case "mssql":
-                    base.db = &dbMssql{dbBase  : base}
-                case "sqlite":
-                    base.db = &dbSqlite{dbBase : base}
-                case "oracle":
-                    base.db = &dbOracle{dbBase : base}
-                default:
-                    return nil, errors.New(fmt.Sprintf(`unsupported database type "%s"`, node.Type))
+            if _, err := newDriverDb(node.Type, base); err != nil {
+                return nil, err
             }
+            base.healthEntry = gtimer.AddSingleton(time.Second, base.checkHealthPeriodically)
             return base.db, nil
         } else {
             return nil, err
@@ -202,17 +317,31 @@ func New(groupName ...string) (db DB, err error) {
 	}
 }
 
-// 获取指定数据库角色的一个配置项，内部根据权重计算负载均衡
-func getConfigNodeByGroup(group string, master bool) (*ConfigNode, error) {
+// 根据数据库类型创建对应的数据库操作对象，并绑定到base对象上
+func newDriverDb(dbType string, base *dbBase) (DB, error) {
+    if f, ok := driverMap[dbType]; ok {
+        base.db = f(base)
+        return base.db, nil
+    }
+    return nil, errors.New(fmt.Sprintf(`unsupported database type "%s"`, dbType))
+}
+
+// 获取指定数据库角色的配置项列表，当没有从库配置时，从库列表即为主库列表
+func getConfigNodesByGroup(group string, master bool) (ConfigGroup, error) {
     if list, ok := config.c[group]; ok {
         // 将master, slave集群列表拆分出来
         masterList := make(ConfigGroup, 0)
         slaveList  := make(ConfigGroup, 0)
         for i := 0; i < len(list); i++ {
-            if list[i].Role == "slave" {
-                slaveList = append(slaveList, list[i])
+            node := list[i]
+            // 默认值设定(节点配置字符串会作为链接池及健康检查状态的键名，因此需要提前设定)
+            if node.Charset == "" {
+                node.Charset = "utf8"
+            }
+            if node.Role == "slave" {
+                slaveList = append(slaveList, node)
             } else {
-                masterList = append(masterList, list[i])
+                masterList = append(masterList, node)
             }
         }
         if len(masterList) < 1 {
@@ -222,15 +351,24 @@ func getConfigNodeByGroup(group string, master bool) (*ConfigNode, error) {
             slaveList = masterList
         }
         if master {
-            return getConfigNodeByPriority(masterList), nil
+            return masterList, nil
         } else {
-            return getConfigNodeByPriority(slaveList), nil
+            return slaveList, nil
         }
     } else {
         return nil, errors.New(fmt.Sprintf("empty database configuration for item name '%s'", group))
     }
 }
 
+// 获取指定数据库角色的一个配置项，内部根据权重计算负载均衡
+func getConfigNodeByGroup(group string, master bool) (*ConfigNode, error) {
+    if list, err := getConfigNodesByGroup(group, master); err == nil {
+        return getConfigNodeByPriority(list), nil
+    } else {
+        return nil, err
+    }
+}
+
 // 按照负载均衡算法(优先级配置)从数据库集群中选择一个配置节点出来使用
 // 算法说明举例，
 // 1、假如2个节点的priority都是1，那么随机大小范围为[0, 199]；
@@ -271,13 +409,47 @@ func getConfigNodeByPriority(cg ConfigGroup) *ConfigNode {
 	return nil
 }
 
-// 获得底层数据库链接对象
+// 获得底层数据库链接对象。
+// 从库会按照负载均衡策略进行选择，并剔除健康检查失败的节点，当所有从库都不可用时自动切换到主库(故障转移)。
 func (bs *dbBase) getSqlDb(master bool) (sqlDb *sql.DB, err error) {
-    // 负载均衡
-    node, err := getConfigNodeByGroup(bs.group, master)
+    config.RLock()
+    nodes, err := getConfigNodesByGroup(bs.group, master)
+    config.RUnlock()
     if err != nil {
         return nil, err
     }
+    if !master {
+        if nodes = bs.filterHealthyNodes(nodes); len(nodes) == 0 {
+            return bs.getSqlDb(true)
+        }
+    } else if healthy := bs.filterHealthyNodes(nodes); len(healthy) > 0 {
+        // 主库只在存在健康节点时进行剔除，否则仍然使用原有的主库列表
+        nodes = healthy
+    }
+    for {
+        // 负载均衡
+        node := bs.selectConfigNode(nodes, master)
+        if sqlDb, err = bs.openConfigNode(node); err != nil {
+            return nil, err
+        }
+        if master || bs.checkNodeHealth(node, sqlDb) {
+            break
+        }
+        // 剔除故障节点后重新选择
+        nodes = removeConfigNode(nodes, node)
+        if len(nodes) == 0 {
+            return bs.getSqlDb(true)
+        }
+    }
+    // 是否手动选择数据库
+    if v := bs.schema.Val(); v != "" {
+        sqlDb.Exec("USE " + v)
+    }
+    return
+}
+
+// 打开指定配置节点的底层数据库链接对象(链接池)，相同配置节点的链接池对象会被缓存复用
+func (bs *dbBase) openConfigNode(node *ConfigNode) (sqlDb *sql.DB, err error) {
     // 默认值设定
     if node.Charset == "" {
         node.Charset = "utf8"
@@ -300,19 +472,22 @@ func (bs *dbBase) getSqlDb(master bool) (sqlDb *sql.DB, err error) {
             sqlDb.SetMaxOpenConns(node.MaxOpenConnCount)
         }
 
+        // 默认使用节点配置，节点没有配置时使用默认的存活时间
         if n := bs.maxConnLifetime.Val(); n > 0 {
             sqlDb.SetConnMaxLifetime(time.Duration(n) * time.Second)
         } else if node.MaxConnLifetime > 0 {
             sqlDb.SetConnMaxLifetime(time.Duration(node.MaxConnLifetime) * time.Second)
+        } else {
+            sqlDb.SetConnMaxLifetime(gDEFAULT_CONN_MAX_LIFE_TIME * time.Second)
         }
+        bs.linkTags.Set(sqlDb, getNodeLinkTag(node))
         return sqlDb
     }, 0)
     if v != nil && sqlDb == nil {
         sqlDb = v.(*sql.DB)
     }
-    // 是否手动选择数据库
-    if v := bs.schema.Val(); v != "" {
-        sqlDb.Exec("USE " + v)
+    if sqlDb == nil && err == nil {
+        err = errors.New(fmt.Sprintf(`cannot open database link for node "%s"`, node.String()))
     }
     return
 }
@@ -322,6 +497,12 @@ func (bs *dbBase) SetSchema(schema string) {
     bs.schema.Set(schema)
 }
 
+// 设置高精度数值类型(DECIMAL/NUMERIC)查询结果的转换类型，参数值为DECIMAL_TYPE_*常量，
+// 默认为DECIMAL_TYPE_FLOAT64，需要保证精度时可以设置为DECIMAL_TYPE_STRING或者DECIMAL_TYPE_RAT。
+func (bs *dbBase) SetDecimalType(t string) {
+    bs.decimalType.Set(t)
+}
+
 // 创建底层数据库master链接对象
 func (bs *dbBase) Master() (*sql.DB, error) {
 	return bs.getSqlDb(true)
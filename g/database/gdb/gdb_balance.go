@@ -0,0 +1,146 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "database/sql"
+    "github.com/gogf/gf/g/os/gtime"
+)
+
+const (
+    LOAD_BALANCE_WEIGHT      = 0 // 按照节点权重(Priority)随机选择(默认)
+    LOAD_BALANCE_ROUND_ROBIN = 1 // 按照节点配置顺序轮询选择
+)
+
+// 节点健康检查状态
+type nodeHealthStatus struct {
+    healthy   bool  // 最近一次检查是否健康
+    checkTime int64 // 最近一次检查的时间(秒)
+}
+
+// 设置集群节点的负载均衡策略，可选值：LOAD_BALANCE_WEIGHT, LOAD_BALANCE_ROUND_ROBIN
+func (bs *dbBase) SetLoadBalance(strategy int) {
+    bs.loadBalance.Set(strategy)
+}
+
+// 设置从库节点健康检查的时间间隔(秒)，检查失败的节点在该时间段内不会被选择，
+// 当n <= 0时表示关闭健康检查。
+func (bs *dbBase) SetHealthCheckInterval(n int) {
+    bs.healthInterval.Set(n)
+}
+
+// 按照负载均衡策略从节点列表中选择一个配置节点
+func (bs *dbBase) selectConfigNode(nodes ConfigGroup, master bool) *ConfigNode {
+    if len(nodes) == 1 {
+        return &nodes[0]
+    }
+    switch bs.loadBalance.Val() {
+        case LOAD_BALANCE_ROUND_ROBIN:
+            counter := bs.roundRobin[0]
+            if master {
+                counter = bs.roundRobin[1]
+            }
+            index := counter.Add(1) % len(nodes)
+            if index < 0 {
+                index = -index
+            }
+            return &nodes[index]
+        default:
+            return getConfigNodeByPriority(nodes)
+    }
+}
+
+// 过滤掉健康检查失败且未到重新检查时间的节点
+func (bs *dbBase) filterHealthyNodes(nodes ConfigGroup) ConfigGroup {
+    interval := int64(bs.healthInterval.Val())
+    if interval <= 0 {
+        return nodes
+    }
+    now     := gtime.Second()
+    healthy := make(ConfigGroup, 0, len(nodes))
+    for _, node := range nodes {
+        if v := bs.nodeHealth.Get(node.String()); v != nil {
+            status := v.(*nodeHealthStatus)
+            if !status.healthy && now - status.checkTime < interval {
+                continue
+            }
+        }
+        healthy = append(healthy, node)
+    }
+    return healthy
+}
+
+// 对节点执行健康检查(ping)，同一节点在检查间隔时间内只会检查一次，返回节点是否健康
+func (bs *dbBase) checkNodeHealth(node *ConfigNode, sqlDb *sql.DB) bool {
+    interval := int64(bs.healthInterval.Val())
+    if interval <= 0 {
+        return true
+    }
+    key := node.String()
+    now := gtime.Second()
+    if v := bs.nodeHealth.Get(key); v != nil {
+        status := v.(*nodeHealthStatus)
+        if now - status.checkTime < interval {
+            return status.healthy
+        }
+    }
+    healthy := sqlDb.Ping() == nil
+    bs.nodeHealth.Set(key, &nodeHealthStatus{
+        healthy   : healthy,
+        checkTime : now,
+    })
+    return healthy
+}
+
+// 定时对已建立链接的所有节点执行健康检查(由gtimer每秒调用，按照健康检查间隔执行)，
+// 检查失败的节点只会被标记为不健康，在检查间隔时间内不会被负载均衡选择，之后重新检查以便节点恢复后继续使用。
+// 节点的链接池可能仍然被调用方或者事务使用，因此不会被关闭，失效的链接由链接池自行丢弃。
+func (bs *dbBase) checkHealthPeriodically() {
+    interval := int64(bs.healthInterval.Val())
+    if interval <= 0 {
+        return
+    }
+    now := gtime.Second()
+    if now - bs.healthCheckTime.Val() < interval {
+        return
+    }
+    bs.healthCheckTime.Set(now)
+    for _, node := range GetConfig(bs.group) {
+        if node.Charset == "" {
+            node.Charset = "utf8"
+        }
+        key := node.String()
+        v   := bs.cache.Get(key)
+        if v == nil {
+            continue
+        }
+        bs.nodeHealth.Set(key, &nodeHealthStatus{
+            healthy   : v.(*sql.DB).Ping() == nil,
+            checkTime : now,
+        })
+    }
+}
+
+// 判断指定节点是否健康(没有健康检查记录的节点认为是健康的)
+func (bs *dbBase) isNodeHealthy(node *ConfigNode) bool {
+    if v := bs.nodeHealth.Get(node.String()); v != nil {
+        return v.(*nodeHealthStatus).healthy
+    }
+    return true
+}
+
+// 从节点列表中删除指定的节点，返回新的节点列表
+func removeConfigNode(nodes ConfigGroup, node *ConfigNode) ConfigGroup {
+    key    := node.String()
+    result := make(ConfigGroup, 0, len(nodes))
+    for _, v := range nodes {
+        if v.String() != key {
+            result = append(result, v)
+        }
+    }
+    return result
+}
@@ -8,10 +8,10 @@
 package gdb
 
 import (
+    "context"
     "database/sql"
     "errors"
     "fmt"
-    "github.com/gogf/gf/g/container/gvar"
     "github.com/gogf/gf/g/os/gcache"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/text/gregex"
@@ -48,6 +48,7 @@ func (bs *dbBase) PrintQueriedSqls() {
         fmt.Println(len(sqls) - k, ":")
         fmt.Println("    Sql  :", v.Sql)
         fmt.Println("    Args :", v.Args)
+        fmt.Println("    Link :", v.Link)
         fmt.Println("    Error:", v.Error)
         fmt.Println("    Start:", gtime.NewFromTimeStamp(v.Start).Format("Y-m-d H:i:s.u"))
         fmt.Println("    End  :", gtime.NewFromTimeStamp(v.End).Format("Y-m-d H:i:s.u"))
@@ -66,23 +67,30 @@ func (bs *dbBase) Query(query string, args ...interface{}) (rows *sql.Rows, err
 
 // 数据库sql查询操作，主要执行查询
 func (bs *dbBase) doQuery(link dbLink, query string, args ...interface{}) (rows *sql.Rows, err error) {
-    query = bs.db.handleSqlBeforeExec(query)
-    if bs.db.getDebug() {
-        mTime1    := gtime.Millisecond()
-        rows, err  = link.Query(query, args...)
-        mTime2    := gtime.Millisecond()
-        s         := &Sql {
-            Sql   : query,
-            Args  : args,
-            Error : err,
-            Start : mTime1,
-            End   : mTime2,
-        }
-        bs.sqls.Put(s)
-        printSql(s)
-    } else {
-        rows, err = link.Query(query, args ...)
+    if query, args, err = formatNamedParams(query, args); err != nil {
+        return nil, err
+    }
+    in, err := bs.interceptBefore(link, "Query", query, args)
+    if err != nil {
+        return nil, err
     }
+    if in != nil {
+        query, args = in.Sql, in.Args
+    }
+    query     = bs.db.handleSqlBeforeExec(query)
+    mTime1   := gtime.Millisecond()
+    rows, err = link.QueryContext(bs.getCtx(), query, args ...)
+    mTime2   := gtime.Millisecond()
+    bs.interceptAfter(in, nil, err)
+    bs.writeSqlLog(link, &Sql {
+        Sql   : query,
+        Args  : args,
+        Error : err,
+        Start : mTime1,
+        End   : mTime2,
+        Func  : "Query",
+        Ctx   : bs.ctx,
+    })
     if err == nil {
         return rows, nil
     } else {
@@ -102,22 +110,40 @@ func (bs *dbBase) Exec(query string, args ...interface{}) (result sql.Result, er
 
 // 执行一条sql，并返回执行情况，主要用于非查询操作
 func (bs *dbBase) doExec(link dbLink, query string, args ...interface{}) (result sql.Result, err error) {
-    query = bs.db.handleSqlBeforeExec(query)
-    if bs.db.getDebug() {
-        mTime1     := gtime.Millisecond()
-        result, err = link.Exec(query, args ...)
-        mTime2     := gtime.Millisecond()
-        s := &Sql{
-            Sql   : query,
-            Args  : args,
-            Error : err,
-            Start : mTime1,
-            End   : mTime2,
-        }
-        bs.sqls.Put(s)
-        printSql(s)
-    } else {
-        result, err = link.Exec(query, args ...)
+    return bs.doExecWith(link, func(ctx context.Context, query string, args []interface{}) (sql.Result, error) {
+        return link.ExecContext(ctx, query, args...)
+    }, query, args...)
+}
+
+// 执行写入操作，executor为实际执行SQL的方法(数据库驱动可以定制，例如PostgreSQL通过RETURNING子句获取写入记录的主键值)，
+// 命名参数、拦截器、SQL预处理及日志按照Exec操作统一处理
+func (bs *dbBase) doExecWith(link dbLink, executor func(ctx context.Context, query string, args []interface{}) (sql.Result, error), query string, args ...interface{}) (result sql.Result, err error) {
+    if query, args, err = formatNamedParams(query, args); err != nil {
+        return nil, err
+    }
+    in, err := bs.interceptBefore(link, "Exec", query, args)
+    if err != nil {
+        return nil, err
+    }
+    if in != nil {
+        query, args = in.Sql, in.Args
+    }
+    query       = bs.db.handleSqlBeforeExec(query)
+    mTime1     := gtime.Millisecond()
+    result, err = executor(bs.getCtx(), query, args)
+    mTime2     := gtime.Millisecond()
+    bs.interceptAfter(in, result, err)
+    bs.writeSqlLog(link, &Sql {
+        Sql   : query,
+        Args  : args,
+        Error : err,
+        Start : mTime1,
+        End   : mTime2,
+        Func  : "Exec",
+        Ctx   : bs.ctx,
+    })
+    if err == nil {
+        bs.clearCacheOnWrite(link, getTablesFromWriteSql(query)...)
     }
     return result, formatError(err, query, args...)
 }
@@ -140,12 +166,31 @@ func (bs *dbBase) Prepare(query string, execOnMaster...bool) (*sql.Stmt, error)
 
 // SQL预处理，执行完成后调用返回值sql.Stmt.Exec完成sql操作
 func (bs *dbBase) doPrepare(link dbLink, query string) (*sql.Stmt, error) {
-    return link.Prepare(query)
+    in, err := bs.interceptBefore(link, "Prepare", query, nil)
+    if err != nil {
+        return nil, err
+    }
+    if in != nil {
+        query = in.Sql
+    }
+    stmt, err := link.PrepareContext(bs.getCtx(), query)
+    bs.interceptAfter(in, nil, err)
+    return stmt, err
 }
 
 // 数据库查询，获取查询结果集，以列表结构返回
 func (bs *dbBase) GetAll(query string, args ...interface{}) (Result, error) {
-    rows, err := bs.Query(query, args ...)
+    return bs.db.doGetAll(nil, query, args ...)
+}
+
+// 数据库查询，获取查询结果集，以列表结构返回，link为nil时默认在Slave上执行
+func (bs *dbBase) doGetAll(link dbLink, query string, args ...interface{}) (result Result, err error) {
+    if link == nil {
+        if link, err = bs.db.Slave(); err != nil {
+            return nil, err
+        }
+    }
+    rows, err := bs.db.doQuery(link, query, args ...)
     if err != nil || rows == nil {
         return nil, err
     }
@@ -203,7 +248,7 @@ func (bs *dbBase) PingMaster() error {
     if master, err := bs.db.Master(); err != nil {
         return err
     } else {
-        return master.Ping()
+        return master.PingContext(bs.getCtx())
     }
 }
 
@@ -212,8 +257,28 @@ func (bs *dbBase) PingSlave() error {
     if slave, err := bs.db.Slave(); err != nil {
         return err
     } else {
-        return slave.Ping()
+        return slave.PingContext(bs.getCtx())
+    }
+}
+
+// 关闭数据库对象，停止定时健康检查任务并关闭所有已建立的底层链接池，关闭后该对象(包括Ctx创建的对象)不可再使用；
+// Ctx创建的对象与原有对象共享底层链接池，调用其Close方法不做任何处理，需要通过原有对象关闭。
+func (bs *dbBase) Close() (err error) {
+    if bs.derived {
+        return nil
     }
+    if bs.healthEntry != nil {
+        bs.healthEntry.Close()
+    }
+    bs.linkTags.Iterator(func(k interface{}, v interface{}) bool {
+        if e := k.(*sql.DB).Close(); e != nil && err == nil {
+            err = e
+        }
+        return true
+    })
+    bs.linkTags.Clear()
+    bs.cache.Close()
+    return
 }
 
 // 事务操作，开启，会返回一个底层的事务操作对象链接如需要嵌套事务，那么可以使用该对象，否则请忽略
@@ -222,12 +287,8 @@ func (bs *dbBase) Begin() (*TX, error) {
     if master, err := bs.db.Master(); err != nil {
         return nil, err
     } else {
-        if tx, err := master.Begin(); err == nil {
-            return &TX {
-                db     : bs.db,
-                tx     : tx,
-                master : master,
-            }, nil
+        if tx, err := master.BeginTx(bs.getCtx(), nil); err == nil {
+            return newTX(bs.db, tx, master), nil
         } else {
             return nil, err
         }
@@ -238,21 +299,21 @@ func (bs *dbBase) Begin() (*TX, error) {
 // 参数data支持map/struct/*struct/slice类型，
 // 当为slice(例如[]map/[]struct/[]*struct)类型时，batch参数生效，并自动切换为批量操作。
 func (bs *dbBase) Insert(table string, data interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doInsert(nil, table, data, OPTION_INSERT, batch...)
+    return bs.db.doInsert(nil, table, data, newInsertOption(OPTION_INSERT, batch...))
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条。
 // 参数data支持map/struct/*struct/slice类型，
 // 当为slice(例如[]map/[]struct/[]*struct)类型时，batch参数生效，并自动切换为批量操作。
 func (bs *dbBase) Replace(table string, data interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doInsert(nil, table, data, OPTION_REPLACE, batch...)
+    return bs.db.doInsert(nil, table, data, newInsertOption(OPTION_REPLACE, batch...))
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据。
 // 参数data支持map/struct/*struct/slice类型，
 // 当为slice(例如[]map/[]struct/[]*struct)类型时，batch参数生效，并自动切换为批量操作。
 func (bs *dbBase) Save(table string, data interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doInsert(nil, table, data, OPTION_SAVE, batch...)
+    return bs.db.doInsert(nil, table, data, newInsertOption(OPTION_SAVE, batch...))
 }
 
 // 支持insert、replace, save， ignore操作。
@@ -262,8 +323,8 @@ func (bs *dbBase) Save(table string, data interface{}, batch...int) (sql.Result,
 // 3: ignore:  如果数据存在(主键或者唯一索引)，那么什么也不做;
 //
 // 参数data支持map/struct/*struct/slice类型，
-// 当为slice(例如[]map/[]struct/[]*struct)类型时，batch参数生效，并自动切换为批量操作。
-func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option int, batch...int) (result sql.Result, err error) {
+// 当为slice(例如[]map/[]struct/[]*struct)类型时，option.batch参数生效，并自动切换为批量操作。
+func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option *insertOption) (result sql.Result, err error) {
     var fields  []string
     var values  []string
     var params  []interface{}
@@ -278,32 +339,25 @@ func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option i
     switch kind {
         case reflect.Slice: fallthrough
         case reflect.Array:
-            return bs.db.doBatchInsert(link, table, data, option, batch...)
+            return bs.db.doBatchInsert(link, table, data, option)
         case reflect.Map:   fallthrough
         case reflect.Struct:
-            dataMap = Map(gconv.Map(data))
+            dataMap = Map(dataToMap(data))
         default:
             return result, errors.New(fmt.Sprint("unsupported data type:", kind))
     }
     charL, charR := bs.db.getChars()
     for k, v := range dataMap {
-        fields = append(fields, charL + k + charR)
+        fields = append(fields, k)
         values = append(values, "?")
-        params = append(params, v)
+        params = append(params, bs.db.convertParam(table, k, v))
     }
-    operation := getInsertOperationByOption(option)
+    operation := getInsertOperationByOption(option.option)
     updateStr := ""
-    if option == OPTION_SAVE {
-        var updates []string
-        for k, _ := range dataMap {
-            updates = append(updates,
-                fmt.Sprintf("%s%s%s=VALUES(%s%s%s)",
-                    charL, k, charR,
-                    charL, k, charR,
-                ),
-            )
+    if option.option == OPTION_SAVE {
+        if updateStr, err = bs.db.formatUpsert(fields, option); err != nil {
+            return nil, err
         }
-        updateStr = fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ","))
     }
     if link == nil {
         if link, err = bs.db.Master(); err != nil {
@@ -311,58 +365,35 @@ func (bs *dbBase) doInsert(link dbLink, table string, data interface{}, option i
         }
     }
     return bs.db.doExec(link, fmt.Sprintf("%s INTO %s(%s) VALUES(%s) %s",
-        operation, table, strings.Join(fields, ","),
+        operation, quoteWord(charL, charR, table), charL + strings.Join(fields, charR + "," + charL) + charR,
         strings.Join(values, ","), updateStr),
         params...)
 }
 
 // CURD操作:批量数据指定批次量写入
 func (bs *dbBase) BatchInsert(table string, list interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doBatchInsert(nil, table, list, OPTION_INSERT, batch...)
+    return bs.db.doBatchInsert(nil, table, list, newInsertOption(OPTION_INSERT, batch...))
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条
 func (bs *dbBase) BatchReplace(table string, list interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doBatchInsert(nil, table, list, OPTION_REPLACE, batch...)
+    return bs.db.doBatchInsert(nil, table, list, newInsertOption(OPTION_REPLACE, batch...))
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据
 func (bs *dbBase) BatchSave(table string, list interface{}, batch...int) (sql.Result, error) {
-    return bs.db.doBatchInsert(nil, table, list, OPTION_SAVE, batch...)
+    return bs.db.doBatchInsert(nil, table, list, newInsertOption(OPTION_SAVE, batch...))
 }
 
 // 批量写入数据, 参数list支持slice类型，例如: []map/[]struct/[]*struct。
-func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, option int, batch...int) (result sql.Result, err error) {
+// 数据按照option.batch指定的数量分批次生成多行写入语句(INSERT INTO ... VALUES(...),(...))执行。
+func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, option *insertOption) (result sql.Result, err error) {
     var keys   []string
     var values []string
     var params []interface{}
-    listMap := (List)(nil)
-    switch v := list.(type) {
-        case List:
-            listMap = v
-        case Map:
-            listMap = List{v}
-        default:
-            rv   := reflect.ValueOf(list)
-            kind := rv.Kind()
-            if kind == reflect.Ptr {
-                rv   = rv.Elem()
-                kind = rv.Kind()
-            }
-            switch kind {
-                // 如果是slice，那么转换为List类型
-                case reflect.Slice: fallthrough
-                case reflect.Array:
-                    listMap = make(List, rv.Len())
-                    for i := 0; i < rv.Len(); i++ {
-                        listMap[i] = gconv.Map(rv.Index(i).Interface())
-                    }
-                case reflect.Map:   fallthrough
-                case reflect.Struct:
-                    listMap = List{Map(gconv.Map(list))}
-                default:
-                    return result, errors.New(fmt.Sprint("unsupported list type:", kind))
-            }
+    listMap, err := formatBatchList(list)
+    if err != nil {
+        return nil, err
     }
     // 判断长度
     if len(listMap) < 1 {
@@ -381,36 +412,32 @@ func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, opt
     }
     batchResult    := new(batchSqlResult)
     charL, charR   := bs.db.getChars()
-    keyStr         := charL + strings.Join(keys, charL + "," + charR) + charR
+    keyStr         := charL + strings.Join(keys, charR + "," + charL) + charR
     valueHolderStr := "(" + strings.Join(holders, ",") + ")"
     // 操作判断
-    operation := getInsertOperationByOption(option)
+    operation := getInsertOperationByOption(option.option)
     updateStr := ""
-    if option == OPTION_SAVE {
-        var updates []string
-        for _, k := range keys {
-            updates = append(updates,
-                fmt.Sprintf("%s%s%s=VALUES(%s%s%s)",
-                    charL, k, charR,
-                    charL, k, charR,
-                ),
-            )
+    if option.option == OPTION_SAVE {
+        if updateStr, err = bs.db.formatUpsert(keys, option); err != nil {
+            return nil, err
         }
-        updateStr = fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ","))
     }
     // 构造批量写入数据格式(注意map的遍历是无序的)
     batchNum := gDEFAULT_BATCH_NUM
-    if len(batch) > 0 {
-        batchNum = batch[0]
+    if option.batch > 0 {
+        batchNum = option.batch
+    }
+    if max := bs.db.getMaxBatchNum(len(keys)); max > 0 && batchNum > max {
+        batchNum = max
     }
     for i := 0; i < len(listMap); i++ {
         for _, k := range keys {
-            params = append(params, listMap[i][k])
+            params = append(params, bs.db.convertParam(table, k, listMap[i][k]))
         }
         values = append(values, valueHolderStr)
-        if len(values) == batchNum {
+        if len(values) == batchNum || i == len(listMap) - 1 {
             r, err := bs.db.doExec(link, fmt.Sprintf("%s INTO %s(%s) VALUES%s %s",
-                operation, table, keyStr, strings.Join(values, ","),
+                operation, quoteWord(charL, charR, table), keyStr, strings.Join(values, ","),
                 updateStr),
                 params...)
             if err != nil {
@@ -426,25 +453,31 @@ func (bs *dbBase) doBatchInsert(link dbLink, table string, list interface{}, opt
             values = values[:0]
         }
     }
-    // 处理最后不构成指定批量的数据
-    if len(values) > 0 {
-        r, err := bs.db.doExec(link, fmt.Sprintf("%s INTO %s(%s) VALUES%s %s",
-            operation, table, keyStr, strings.Join(values, ","),
-            updateStr),
-            params...)
-        if err != nil {
-            return r, err
-        }
-        if n, err := r.RowsAffected(); err != nil  {
-            return r, err
-        } else {
-            batchResult.lastResult    = r
-            batchResult.rowsAffected += n
-        }
-    }
     return batchResult, nil
 }
 
+// 获得批量写入时单条SQL语句允许的最大记录数(受限于数据库的参数数量或者语法限制)，参数columns为写入的字段数量，
+// 返回0表示不限制，不同数据库可覆盖该方法。
+func (bs *dbBase) getMaxBatchNum(columns int) int {
+    return 0
+}
+
+// 生成Save操作时的冲突更新语句，默认为MySQL的ON DUPLICATE KEY UPDATE语法，
+// 参数columns为写入的字段列表，当option没有指定需要更新的字段时，更新所有写入的字段。
+func (bs *dbBase) formatUpsert(columns []string, option *insertOption) (string, error) {
+    var updates  []string
+    charL, charR := bs.db.getChars()
+    for _, k := range option.getUpdateColumns(columns) {
+        updates = append(updates,
+            fmt.Sprintf("%s%s%s=VALUES(%s%s%s)",
+                charL, k, charR,
+                charL, k, charR,
+            ),
+        )
+    }
+    return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ",")), nil
+}
+
 // CURD操作:数据更新，统一采用sql预处理。
 // data参数支持string/map/struct/*struct类型。
 func (bs *dbBase) Update(table string, data interface{}, condition interface{}, args ...interface{}) (sql.Result, error) {
@@ -472,9 +505,14 @@ func (bs *dbBase) doUpdate(link dbLink, table string, data interface{}, conditio
         case reflect.Map:   fallthrough
         case reflect.Struct:
             var fields []string
-            for k, v := range gconv.Map(data) {
+            for k, v := range dataToMap(data) {
                 fields = append(fields, fmt.Sprintf("%s%s%s=?", charL, k, charR))
-                params = append(params, gconv.String(v))
+                // nil值(包括nil的JSON字段)保持为nil，以便写入NULL
+                if value := bs.db.convertParam(table, k, v); value != nil {
+                    params = append(params, gconv.String(value))
+                } else {
+                    params = append(params, nil)
+                }
             }
             updates = strings.Join(fields, ",")
         default:
@@ -488,8 +526,11 @@ func (bs *dbBase) doUpdate(link dbLink, table string, data interface{}, conditio
             return nil, err
         }
     }
-    newWhere, newArgs := formatCondition(condition, params)
-    return bs.db.doExec(link, fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, updates, newWhere), newArgs...)
+    newWhere, newArgs, err := formatCondition(bs.db, condition, params)
+    if err != nil {
+        return nil, err
+    }
+    return bs.db.doExec(link, fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteWord(charL, charR, table), updates, newWhere), newArgs...)
 }
 
 // CURD操作:删除数据
@@ -503,8 +544,31 @@ func (bs *dbBase) Delete(table string, condition interface{}, args ...interface{
 
 // CURD操作:删除数据
 func (bs *dbBase) doDelete(link dbLink, table string, condition interface{}, args ...interface{}) (result sql.Result, err error) {
-    newWhere, newArgs := formatCondition(condition, args)
-    return bs.db.doExec(link, fmt.Sprintf("DELETE FROM %s WHERE %s", table, newWhere), newArgs...)
+    charL, charR      := bs.db.getChars()
+    newWhere, newArgs, err := formatCondition(bs.db, condition, args)
+    if err != nil {
+        return nil, err
+    }
+    return bs.db.doExec(link, fmt.Sprintf("DELETE FROM %s WHERE %s", quoteWord(charL, charR, table), newWhere), newArgs...)
+}
+
+// 创建一个绑定上下文对象的数据库操作对象，该对象的所有SQL操作都将使用该上下文执行，
+// 当上下文被取消或者超时时，正在执行的SQL操作会被中断并返回错误。
+// 返回的对象与原有对象共享底层链接池及配置，原有对象不受影响。
+func (bs *dbBase) Ctx(ctx context.Context) DB {
+    base        := *bs
+    base.ctx     = ctx
+    base.derived = true
+    db, _       := newDriverDb(bs.dbType, &base)
+    return db
+}
+
+// 获得当前绑定的上下文对象，没有绑定时返回context.Background()
+func (bs *dbBase) getCtx() context.Context {
+    if bs.ctx != nil {
+        return bs.ctx
+    }
+    return context.Background()
 }
 
 // 获得缓存对象
@@ -514,37 +578,16 @@ func (bs *dbBase) getCache() *gcache.Cache {
 
 // 将数据查询的列表数据*sql.Rows转换为Result类型
 func (bs *dbBase) rowsToResult(rows *sql.Rows) (Result, error) {
-    // 列信息列表, 名称与类型
-    types          := make([]string, 0)
-    columns        := make([]string, 0)
-    columnTypes, _ := rows.ColumnTypes()
-    for _, t := range columnTypes {
-        types   = append(types, t.DatabaseTypeName())
-        columns = append(columns, t.Name())
-    }
-    // 返回结构组装
-    values   := make([]sql.RawBytes, len(columns))
-    scanArgs := make([]interface{}, len(values))
-    records  := make(Result, 0)
-    for i := range values {
-        scanArgs[i] = &values[i]
+    records      := make(Result, 0)
+    scanner, err := newRowScanner(bs.db, rows)
+    if err != nil {
+        return records, err
     }
     for rows.Next() {
-        if err := rows.Scan(scanArgs...); err != nil {
+        row, err := scanner.scan(rows)
+        if err != nil {
             return records, err
         }
-        row := make(Record)
-        // 注意col字段是一个[]byte类型(slice类型本身是一个指针)，多个记录循环时该变量指向的是同一个内存地址
-        for i, col := range values {
-            if col == nil {
-                row[columns[i]] = gvar.New(nil, true)
-            } else {
-                // 由于 sql.RawBytes 是slice类型, 这里必须使用值复制
-                v := make([]byte, len(col))
-                copy(v, col)
-                row[columns[i]] = gvar.New(bs.db.convertValue(v, types[i]), true)
-            }
-        }
         records = append(records, row)
     }
     return records, nil
@@ -0,0 +1,315 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "encoding/json"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gset"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/container/gvar"
+    "github.com/gogf/gf/g/crypto/gmd5"
+    "github.com/gogf/gf/g/database/gredis"
+    "github.com/gogf/gf/g/os/gcache"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gconv"
+    "strings"
+)
+
+const (
+    gQUERY_CACHE_KEY_PREFIX = "gdb:cache:"     // 查询缓存的键名前缀
+    gQUERY_CACHE_TAG_PREFIX = "gdb:cache:tag:" // 查询缓存数据表标签的键名前缀
+    gQUERY_CACHE_PRUNE_SIZE = 1024             // 内存查询缓存标签中的键名数量超过该值时清理已经过期的键名
+)
+
+var (
+    // 写入操作SQL语句中的数据表名称
+    writeSqlTablePatterns = []string {
+        `(?i)^\s*(?:INSERT|REPLACE)(?:\s+(?:LOW_PRIORITY|DELAYED|HIGH_PRIORITY|IGNORE))*(?:\s+INTO)?\s+([^\s(]+)`,
+        `(?i)^\s*UPDATE(?:\s+(?:LOW_PRIORITY|IGNORE))*\s+(.+?)\s+SET\s`,
+        `(?i)^\s*DELETE(?:\s+(?:LOW_PRIORITY|QUICK|IGNORE))*\s+FROM\s+([^\s]+)`,
+        `(?i)^\s*TRUNCATE(?:\s+TABLE)?\s+([^\s;]+)`,
+    }
+)
+
+// 查询缓存接口，用于保存链式操作的查询结果集，tags为查询涉及的数据表名称，
+// 当数据表发生写入操作时将会通过RemoveByTag清除该表相关的所有查询缓存。
+type QueryCache interface {
+    Get(key string) (Result, bool)                              // 获取缓存的结果集
+    Set(key string, result Result, expire int, tags...string)   // 缓存结果集，expire单位为毫秒，0表示不过期
+    Remove(keys...string)                                       // 删除指定的缓存
+    RemoveByTag(tags...string)                                  // 删除指定标签(数据表)下的所有缓存
+}
+
+// 基于进程内存(gcache)的查询缓存(默认)
+type memQueryCache struct {
+    cache *gcache.Cache            // 结果集缓存
+    tags  *gmap.StringInterfaceMap // 标签 => *memQueryCacheTag
+}
+
+// 内存查询缓存的标签
+type memQueryCacheTag struct {
+    keys      *gset.StringSet // 缓存键名集合
+    pruneSize *gtype.Int      // 键名数量超过该值时清理已经过期的键名
+}
+
+// 基于Redis的查询缓存，结果集以JSON格式保存，可在多进程间共享缓存及失效通知
+type redisQueryCache struct {
+    redis *gredis.Redis
+}
+
+// 创建基于内存的查询缓存对象
+func NewMemQueryCache() QueryCache {
+    return &memQueryCache {
+        cache : gcache.New(),
+        tags  : gmap.NewStringInterfaceMap(),
+    }
+}
+
+// 创建基于Redis的查询缓存对象
+func NewRedisQueryCache(redis *gredis.Redis) QueryCache {
+    return &redisQueryCache {
+        redis : redis,
+    }
+}
+
+// 设置查询缓存对象，默认使用内存缓存
+func (bs *dbBase) SetQueryCache(cache QueryCache) {
+    bs.queryCache.Set(cache)
+}
+
+// 获得查询缓存对象
+func (bs *dbBase) getQueryCache() QueryCache {
+    return bs.queryCache.Val().(QueryCache)
+}
+
+// 按照缓存名称清除查询缓存(名称通过Model.Cache方法指定)
+func (bs *dbBase) ClearCache(names...string) {
+    bs.getQueryCache().Remove(names...)
+}
+
+// 清除指定数据表相关的所有查询缓存
+func (bs *dbBase) ClearCacheByTable(tables...string) {
+    tags := make([]string, len(tables))
+    for i, table := range tables {
+        tags[i] = formatCacheTag(table)
+    }
+    bs.getQueryCache().RemoveByTag(tags...)
+}
+
+// 写入操作成功后清除数据表相关的查询缓存，事务中的写入操作记录到事务对象上，在事务提交成功后清除
+func (bs *dbBase) clearCacheOnWrite(link dbLink, tables...string) {
+    if len(tables) == 0 {
+        return
+    }
+    if tx, ok := link.(*txLink); ok {
+        tx.tables.Add(tables...)
+        return
+    }
+    bs.ClearCacheByTable(tables...)
+}
+
+// 获得写入操作SQL语句(INSERT/REPLACE/UPDATE/DELETE/TRUNCATE)涉及的数据表名称，用于清除查询缓存
+func getTablesFromWriteSql(query string) []string {
+    tables := make([]string, 0)
+    for _, pattern := range writeSqlTablePatterns {
+        match, _ := gregex.MatchString(pattern, query)
+        if len(match) < 2 {
+            continue
+        }
+        // UPDATE语句可能包含多个数据表(使用','或者JOIN连接)，
+        // 带有数据库名称的数据表同时清除不带数据库名称的标签(例如: db.user => db.user, user)
+        names, _ := gregex.ReplaceString(`(?i)\s+JOIN\s+`, ",", match[1])
+        for _, item := range strings.Split(names, ",") {
+            if array := strings.Fields(item); len(array) > 0 {
+                table := formatCacheTag(array[0])
+                tables = append(tables, table)
+                if pos := strings.LastIndex(table, "."); pos != -1 {
+                    tables = append(tables, table[pos + 1:])
+                }
+            }
+        }
+        break
+    }
+    return tables
+}
+
+// 复制查询结果集，查询缓存中保存及返回的均为副本，避免钩子函数等对返回结果的修改影响缓存内容
+func copyResult(result Result) Result {
+    if result == nil {
+        return nil
+    }
+    list := make(Result, len(result))
+    for i, record := range result {
+        r := make(Record, len(record))
+        for k, v := range record {
+            if v != nil {
+                v = gvar.New(v.Val(), true)
+            }
+            r[k] = v
+        }
+        list[i] = r
+    }
+    return list
+}
+
+// 生成查询缓存的键名，由SQL语句及参数计算得到，相同的查询将会得到相同的键名
+func makeQueryCacheKey(query string, args []interface{}) string {
+    return gQUERY_CACHE_KEY_PREFIX + gmd5.EncryptString(query + "/" + gconv.String(args))
+}
+
+// 生成数据表的缓存标签名称(去掉所有标识符引用符号，例如: `db`.`user` => db.user)
+func formatCacheTag(table string) string {
+    return strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(strings.TrimSpace(table))
+}
+
+// 获得链式操作涉及的所有数据表名称(包括联表)，用于查询缓存的标签
+func getTablesFromModel(md *Model) []string {
+    tables := make([]string, 0)
+    parts  := strings.Split(md.tables, " JOIN ")
+    for i, part := range parts {
+        if i == 0 {
+            for _, item := range strings.Split(part, ",") {
+                if array := strings.Fields(item); len(array) > 0 {
+                    tables = append(tables, formatCacheTag(array[0]))
+                }
+            }
+        } else if array := strings.Fields(part); len(array) > 0 {
+            tables = append(tables, formatCacheTag(array[0]))
+        }
+    }
+    if md.tablesInit != md.tables {
+        tables = append(tables, formatCacheTag(md.tablesInit))
+    }
+    return tables
+}
+
+// see QueryCache.Get
+func (c *memQueryCache) Get(key string) (Result, bool) {
+    if v := c.cache.Get(key); v != nil {
+        return v.(Result), true
+    }
+    return nil, false
+}
+
+// see QueryCache.Set
+func (c *memQueryCache) Set(key string, result Result, expire int, tags...string) {
+    c.cache.Set(key, result, expire)
+    for _, tag := range tags {
+        t := c.tags.GetOrSetFuncLock(tag, func() interface{} {
+            return &memQueryCacheTag {
+                keys      : gset.NewStringSet(),
+                pruneSize : gtype.NewInt(gQUERY_CACHE_PRUNE_SIZE),
+            }
+        }).(*memQueryCacheTag)
+        t.keys.Add(key)
+        if t.keys.Size() > t.pruneSize.Val() {
+            c.prune(t)
+        }
+    }
+}
+
+// 清理标签中已经过期的缓存键名，下次清理的阈值为剩余键名数量的两倍(不小于gQUERY_CACHE_PRUNE_SIZE)
+func (c *memQueryCache) prune(t *memQueryCacheTag) {
+    size := 0
+    t.keys.LockFunc(func(m map[string]struct{}) {
+        for key := range m {
+            if !c.cache.Contains(key) {
+                delete(m, key)
+            }
+        }
+        size = len(m)
+    })
+    if size * 2 > gQUERY_CACHE_PRUNE_SIZE {
+        t.pruneSize.Set(size * 2)
+    } else {
+        t.pruneSize.Set(gQUERY_CACHE_PRUNE_SIZE)
+    }
+}
+
+// see QueryCache.Remove
+func (c *memQueryCache) Remove(keys...string) {
+    for _, key := range keys {
+        c.cache.Remove(key)
+    }
+}
+
+// see QueryCache.RemoveByTag
+func (c *memQueryCache) RemoveByTag(tags...string) {
+    for _, tag := range tags {
+        if v := c.tags.Remove(tag); v != nil {
+            c.Remove(v.(*memQueryCacheTag).keys.Slice()...)
+        }
+    }
+}
+
+// see QueryCache.Get
+func (c *redisQueryCache) Get(key string) (Result, bool) {
+    v, err := c.redis.Do("GET", key)
+    if err != nil || v == nil {
+        return nil, false
+    }
+    list := make([]map[string]interface{}, 0)
+    if err := json.Unmarshal(gconv.Bytes(v), &list); err != nil {
+        return nil, false
+    }
+    result := make(Result, len(list))
+    for i, m := range list {
+        record := make(Record, len(m))
+        for k, v := range m {
+            record[k] = gvar.New(v, true)
+        }
+        result[i] = record
+    }
+    return result, true
+}
+
+// see QueryCache.Set
+func (c *redisQueryCache) Set(key string, result Result, expire int, tags...string) {
+    content, err := json.Marshal(result.ToList())
+    if err != nil {
+        return
+    }
+    if expire > 0 {
+        _, err = c.redis.Do("SET", key, content, "PX", expire)
+    } else {
+        _, err = c.redis.Do("SET", key, content)
+    }
+    if err != nil {
+        return
+    }
+    // 标签使用有序集合保存缓存键名，分值为缓存的过期时间(毫秒)，写入时清理已经过期的键名
+    now   := gtime.Millisecond()
+    score := "+inf"
+    if expire > 0 {
+        score = gconv.String(now + int64(expire))
+    }
+    for _, tag := range tags {
+        c.redis.Do("ZADD", gQUERY_CACHE_TAG_PREFIX + tag, score, key)
+        c.redis.Do("ZREMRANGEBYSCORE", gQUERY_CACHE_TAG_PREFIX + tag, "-inf", now)
+    }
+}
+
+// see QueryCache.Remove
+func (c *redisQueryCache) Remove(keys...string) {
+    if len(keys) > 0 {
+        c.redis.Do("DEL", gconv.Interfaces(keys)...)
+    }
+}
+
+// see QueryCache.RemoveByTag
+func (c *redisQueryCache) RemoveByTag(tags...string) {
+    for _, tag := range tags {
+        v, err := c.redis.Do("ZRANGE", gQUERY_CACHE_TAG_PREFIX + tag, 0, -1)
+        if err != nil {
+            continue
+        }
+        keys := gconv.Strings(v)
+        c.Remove(append(keys, gQUERY_CACHE_TAG_PREFIX + tag)...)
+    }
+}
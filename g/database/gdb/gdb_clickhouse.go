@@ -0,0 +1,267 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gconv"
+    "strings"
+)
+
+// ClickHouse的适配.
+// 使用时需要import:
+// _ "github.com/ClickHouse/clickhouse-go"
+// 说明：
+// 1. 只支持insert写入操作，不支持replace/save/ignore操作；
+// 2. 不支持LastInsertId方法；
+// 3. UPDATE/DELETE语句将会被转换为ALTER TABLE ... UPDATE/DELETE语句(异步mutation)；
+// 4. 不支持事务，事务仅用于批量写入数据；
+
+// 数据库链接对象
+type dbClickhouse struct {
+    *dbBase
+}
+
+// 创建SQL操作对象，内部采用了lazy link处理
+func (db *dbClickhouse) Open(config *ConfigNode) (*sql.DB, error) {
+    var source string
+    if config.Linkinfo != "" {
+        source = config.Linkinfo
+    } else {
+        source = fmt.Sprintf("tcp://%s:%s?username=%s&password=%s&database=%s",
+            config.Host, config.Port, config.User, config.Pass, config.Name)
+    }
+    if db, err := sql.Open("clickhouse", source); err == nil {
+        return db, nil
+    } else {
+        return nil, err
+    }
+}
+
+// 获得关键字操作符
+func (db *dbClickhouse) getChars() (charLeft string, charRight string) {
+    return "`", "`"
+}
+
+// 在执行sql之前对sql进行进一步处理，将UPDATE/DELETE语句转换为ClickHouse的ALTER TABLE语法
+func (db *dbClickhouse) handleSqlBeforeExec(query string) string {
+    query, _ = gregex.ReplaceString(`(?i)^\s*UPDATE\s+(\S+)\s+SET\s`, "ALTER TABLE $1 UPDATE ", query)
+    query, _ = gregex.ReplaceString(`(?i)^\s*DELETE\s+FROM\s+(\S+)\s+WHERE\s`, "ALTER TABLE $1 DELETE WHERE ", query)
+    return query
+}
+
+// 不支持冲突更新操作
+func (db *dbClickhouse) formatUpsert(columns []string, option *insertOption) (string, error) {
+    return "", errors.New("save operation is not supported by clickhouse")
+}
+
+// 单条数据写入，驱动要求写入操作必须通过事务中的预处理语句执行，因此统一使用批量写入实现
+func (db *dbClickhouse) doInsert(link dbLink, table string, data interface{}, option *insertOption) (result sql.Result, err error) {
+    return db.doBatchInsert(link, table, data, option)
+}
+
+// 批量写入数据，驱动在事务中通过预处理语句逐条缓存数据，并在提交事务时一次性批量发送到服务端，
+// 因此不需要生成多行VALUES语句，option.batch参数在这里无效。
+func (db *dbClickhouse) doBatchInsert(link dbLink, table string, list interface{}, option *insertOption) (result sql.Result, err error) {
+    if option.option != OPTION_INSERT {
+        return nil, errors.New("only insert operation is supported by clickhouse")
+    }
+    listMap, err := formatBatchList(list)
+    if err != nil {
+        return nil, err
+    }
+    if len(listMap) < 1 {
+        return nil, errors.New("empty data list")
+    }
+    if link == nil {
+        if link, err = db.Master(); err != nil {
+            return nil, err
+        }
+    }
+    var keys    []string
+    var holders []string
+    for k, _ := range listMap[0] {
+        keys    = append(keys,    k)
+        holders = append(holders, "?")
+    }
+    charL, charR := db.getChars()
+    query        := fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)",
+        quoteWord(charL, charR, table),
+        charL + strings.Join(keys, charR + "," + charL) + charR,
+        strings.Join(holders, ","),
+    )
+    mTime1      := gtime.Millisecond()
+    result, err  = db.doBatchInsertWithTx(link, table, query, keys, listMap)
+    mTime2      := gtime.Millisecond()
+    db.writeSqlLog(link, &Sql {
+        Sql   : query,
+        Args  : []interface{}{fmt.Sprintf("%d rows", len(listMap))},
+        Error : err,
+        Start : mTime1,
+        End   : mTime2,
+        Func  : "BatchInsert",
+        Ctx   : db.ctx,
+    })
+    if err != nil {
+        return nil, formatError(err, query)
+    }
+    db.clearCacheOnWrite(link, table)
+    return result, nil
+}
+
+// 在事务中通过预处理语句写入数据，当link本身为事务对象时直接使用该事务(由调用方负责提交)
+func (db *dbClickhouse) doBatchInsertWithTx(link dbLink, table string, query string, keys []string, list List) (sql.Result, error) {
+    var err error
+    tx    := (*sql.Tx)(nil)
+    l, ok := link.(*txLink)
+    if ok {
+        tx = l.Tx
+    } else {
+        sqlDb, ok := link.(*sql.DB)
+        if !ok {
+            return nil, errors.New("unsupported link type for clickhouse batch insert")
+        }
+        if tx, err = sqlDb.BeginTx(db.getCtx(), nil); err != nil {
+            return nil, err
+        }
+        defer func() {
+            if err != nil {
+                tx.Rollback()
+            }
+        }()
+    }
+    stmt, err := tx.PrepareContext(db.getCtx(), query)
+    if err != nil {
+        return nil, err
+    }
+    defer stmt.Close()
+    batchResult := new(batchSqlResult)
+    params      := make([]interface{}, len(keys))
+    for _, m := range list {
+        for i, k := range keys {
+            params[i] = db.convertParam(table, k, m[k])
+        }
+        if batchResult.lastResult, err = stmt.ExecContext(db.getCtx(), params...); err != nil {
+            return nil, err
+        }
+        batchResult.rowsAffected++
+    }
+    if !ok {
+        if err = tx.Commit(); err != nil {
+            return nil, err
+        }
+    }
+    return batchResult, nil
+}
+
+// 字段类型转换，ClickHouse的类型名称区分大小写并且可能被Nullable/LowCardinality包裹，
+// 例如: Nullable(UInt64), LowCardinality(String), 这里去掉包裹后按照基础类型进行转换。
+func (db *dbClickhouse) convertValue(fieldValue interface{}, fieldType string) interface{} {
+    t := fieldType
+    for {
+        if match, _ := gregex.MatchString(`^(?:Nullable|LowCardinality)\((.+)\)$`, t); len(match) > 1 {
+            t = match[1]
+        } else {
+            break
+        }
+    }
+    switch {
+        case strings.HasPrefix(t, "UInt"):
+            return gconv.Uint64(fieldValue)
+
+        case strings.HasPrefix(t, "Int"):
+            return gconv.Int64(fieldValue)
+
+        case strings.HasPrefix(t, "Float"):
+            return gconv.Float64(fieldValue)
+
+        case strings.HasPrefix(t, "Decimal"):
+            return db.dbBase.convertDecimal(fieldValue)
+
+        case strings.HasPrefix(t, "DateTime") || t == "Date" || t == "Date32":
+            return convertTimeValue(fieldValue)
+
+        case t == "String" || strings.HasPrefix(t, "FixedString") || strings.HasPrefix(t, "Enum"):
+            return gconv.String(fieldValue)
+
+        default:
+            return db.dbBase.convertValue(fieldValue, t)
+    }
+}
+
+// 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值为字段数据类型.
+func (db *dbClickhouse) getTableFields(table string) (fields map[string]string, err error) {
+    // 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
+    v := db.cache.GetOrSetFunc("table_fields_" + table, func() interface{} {
+        result       := (Result)(nil)
+        charL, charR := db.getChars()
+        result, err   = db.GetAll(fmt.Sprintf(`DESCRIBE TABLE %s`, quoteWord(charL, charR, table)))
+        if err != nil {
+            return nil
+        }
+        fields = make(map[string]string)
+        for _, m := range result {
+            fields[m["name"].String()] = m["type"].String()
+        }
+        return fields
+    }, 0)
+    if err == nil && v != nil {
+        fields = v.(map[string]string)
+    }
+    return
+}
+
+// 获取指定数据表的字段详细信息，Nullable类型的字段允许为NULL，主键字段标记为PRI
+func (db *dbClickhouse) TableFields(table string) (fields []*TableField, err error) {
+    result, err := db.GetAll(`SELECT name, type, default_expression, comment, is_in_primary_key FROM system.columns
+        WHERE database = currentDatabase() AND table = ? ORDER BY position`, table)
+    if err != nil {
+        return nil, err
+    }
+    fields = make([]*TableField, len(result))
+    for i, m := range result {
+        fields[i] = &TableField {
+            Index   : i,
+            Name    : m["name"].String(),
+            Type    : m["type"].String(),
+            Null    : strings.HasPrefix(m["type"].String(), "Nullable("),
+            Comment : m["comment"].String(),
+        }
+        if m["default_expression"].String() != "" {
+            fields[i].Default = m["default_expression"].String()
+        }
+        if m["is_in_primary_key"].Int() == 1 {
+            fields[i].Key = "PRI"
+        }
+    }
+    return fields, nil
+}
+
+// 获取指定数据表的索引信息，ClickHouse只有主键(稀疏索引，不保证唯一性)，这里只返回主键索引
+func (db *dbClickhouse) TableIndexes(table string) (indexes []*TableIndex, err error) {
+    value, err := db.GetValue(`SELECT primary_key FROM system.tables WHERE database = currentDatabase() AND name = ?`, table)
+    if err != nil {
+        return nil, err
+    }
+    indexes = make([]*TableIndex, 0)
+    if value != nil && value.String() != "" {
+        columns := strings.Split(value.String(), ",")
+        for i, column := range columns {
+            columns[i] = strings.TrimSpace(column)
+        }
+        indexes = append(indexes, &TableIndex {
+            Name    : "PRIMARY",
+            Primary : true,
+            Columns : columns,
+        })
+    }
+    return indexes, nil
+}
@@ -37,14 +37,15 @@ type ConfigNode  struct {
     User             string   // 账号
     Pass             string   // 密码
     Name             string   // 数据库名称
-    Type             string   // 数据库类型：mysql, sqlite, mssql, pgsql, oracle(目前仅支持mysql)
+    Type             string   // 数据库类型：mysql, sqlite(sqlite3), mssql, pgsql, oracle, clickhouse(除mysql外需要手动import对应的数据库驱动)
     Role             string   // (可选，默认为master)数据库的角色，用于主从操作分离，至少需要有一个master，参数值：master, slave
     Charset          string   // (可选，默认为 utf8)编码，默认为 utf8
     Priority         int      // (可选)用于负载均衡的权重计算，当集群中只有一个节点时，权重没有任何意义
     Linkinfo         string   // (可选)自定义链接信息，当该字段被设置值时，以上链接字段(Host,Port,User,Pass,Name)将失效(该字段是一个扩展功能)
     MaxIdleConnCount int      // (可选)连接池最大限制的连接数
     MaxOpenConnCount int      // (可选)连接池最大打开的连接数
-    MaxConnLifetime  int      // (可选，单位秒)连接对象可重复使用的时间长度
+    MaxConnLifetime  int      // (可选，单位秒)连接对象可重复使用的时间长度，默认为30秒
+    DecimalType      string   // (可选，默认为float64)DECIMAL/NUMERIC字段的转换类型：float64, string, rat(*big.Rat)
 }
 
 // 数据库集群配置示例，支持主从处理，多数据库集群支持
@@ -0,0 +1,70 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "github.com/gogf/gf/g/os/gcron"
+)
+
+const (
+    gDEFAULT_CRON_STORE_TABLE = "gcron_job" // 数据库存储默认的数据表名称
+)
+
+// 基于数据库的定时任务持久化存储(gcron.Store)，数据表需要包含name(主键)、pattern、last_run三个字段，例如(MySQL):
+// CREATE TABLE `gcron_job` (
+//     `name`     varchar(255) NOT NULL,
+//     `pattern`  varchar(255) NOT NULL DEFAULT '',
+//     `last_run` bigint       NOT NULL DEFAULT 0,
+//     PRIMARY KEY (`name`)
+// );
+type cronStore struct {
+    db    DB
+    table string
+}
+
+// 创建基于数据库的定时任务持久化存储，通过gcron.SetStore设置，table为数据表名称，默认为gcron_job。
+// 写入时使用Save操作(以name为冲突检测字段)，因此仅支持MySQL、PostgreSQL及SQLite，
+// 不支持Save操作的SQL Server、Oracle及ClickHouse无法使用该存储。
+func NewCronStore(db DB, table...string) gcron.Store {
+    s := &cronStore {
+        db    : db,
+        table : gDEFAULT_CRON_STORE_TABLE,
+    }
+    if len(table) > 0 && table[0] != "" {
+        s.table = table[0]
+    }
+    return s
+}
+
+func (s *cronStore) Get(name string) (*gcron.StoreRecord, error) {
+    r, err := s.db.Table(s.table).Where("name=?", name).One()
+    if err != nil {
+        return nil, err
+    }
+    if len(r) == 0 {
+        return nil, nil
+    }
+    return &gcron.StoreRecord {
+        Name    : r["name"].String(),
+        Pattern : r["pattern"].String(),
+        LastRun : r["last_run"].Int64(),
+    }, nil
+}
+
+func (s *cronStore) Set(record *gcron.StoreRecord) error {
+    _, err := s.db.Table(s.table).Data(Map {
+        "name"     : record.Name,
+        "pattern"  : record.Pattern,
+        "last_run" : record.LastRun,
+    }).OnConflict("name").Save()
+    return err
+}
+
+func (s *cronStore) Remove(name string) error {
+    _, err := s.db.Table(s.table).Where("name=?", name).Delete()
+    return err
+}
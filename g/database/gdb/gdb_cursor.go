@@ -0,0 +1,163 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gvar"
+)
+
+// 查询结果集游标对象，用于逐条读取查询结果，
+// 常用于大数据量的导出场景，内存中只会保留当前读取的一条记录，使用完毕后必须调用Close关闭。
+type Cursor struct {
+    rows    *sql.Rows   // 底层查询结果集
+    scanner *rowScanner // 记录转换对象
+    record  Record      // 当前读取的记录
+    err     error       // 读取过程中产生的错误
+}
+
+// 查询结果集的记录转换对象，按照字段类型将每一行数据转换为Record
+type rowScanner struct {
+    db       DB
+    types    []string
+    columns  []string
+    values   []sql.RawBytes
+    scanArgs []interface{}
+}
+
+// 创建记录转换对象
+func newRowScanner(db DB, rows *sql.Rows) (*rowScanner, error) {
+    columnTypes, err := rows.ColumnTypes()
+    if err != nil {
+        return nil, err
+    }
+    s := &rowScanner {
+        db       : db,
+        types    : make([]string, len(columnTypes)),
+        columns  : make([]string, len(columnTypes)),
+        values   : make([]sql.RawBytes, len(columnTypes)),
+        scanArgs : make([]interface{}, len(columnTypes)),
+    }
+    for i, t := range columnTypes {
+        s.types[i]    = t.DatabaseTypeName()
+        s.columns[i]  = t.Name()
+        s.scanArgs[i] = &s.values[i]
+    }
+    return s, nil
+}
+
+// 读取当前行数据并转换为Record
+func (s *rowScanner) scan(rows *sql.Rows) (Record, error) {
+    if err := rows.Scan(s.scanArgs...); err != nil {
+        return nil, err
+    }
+    row := make(Record, len(s.columns))
+    // 注意col字段是一个[]byte类型(slice类型本身是一个指针)，多个记录循环时该变量指向的是同一个内存地址
+    for i, col := range s.values {
+        if col == nil {
+            row[s.columns[i]] = gvar.New(nil, true)
+        } else {
+            // 由于 sql.RawBytes 是slice类型, 这里必须使用值复制
+            v := make([]byte, len(col))
+            copy(v, col)
+            row[s.columns[i]] = gvar.New(s.db.convertValue(v, s.types[i]), true)
+        }
+    }
+    return row, nil
+}
+
+// 链式操作，执行查询并返回结果集游标，通过游标逐条读取记录，不会一次性将所有记录加载到内存中。
+// 需要注意的是，游标查询不支持查询缓存及跨分片查询，并且不会执行AfterQuery钩子。
+func (md *Model) Cursor() (*Cursor, error) {
+    tables, err := md.getShardTablesByWhere()
+    if err != nil {
+        return nil, err
+    }
+    switch len(tables) {
+        case 0:
+        case 1:
+            md = md.withShardTable(tables[0])
+        default:
+            return nil, errors.New(fmt.Sprintf(`sharding key condition is required for cursor querying table "%s"`, md.tablesInit))
+    }
+    link := (dbLink)(nil)
+    if md.tx != nil {
+        link = md.tx.tx
+    } else if md.linkType == gLINK_TYPE_MASTER {
+        link, err = md.db.Master()
+    } else {
+        link, err = md.db.Slave()
+    }
+    if err != nil {
+        return nil, err
+    }
+    rows, err := md.db.doQuery(link, md.getFormattedSql(), md.whereArgs...)
+    if err != nil {
+        return nil, err
+    }
+    scanner, err := newRowScanner(md.db, rows)
+    if err != nil {
+        rows.Close()
+        return nil, err
+    }
+    return &Cursor {
+        rows    : rows,
+        scanner : scanner,
+    }, nil
+}
+
+// 链式操作，逐条遍历查询结果，回调函数返回false时停止遍历，遍历完成后自动关闭游标
+func (md *Model) Rows(f func(record Record) bool) error {
+    cursor, err := md.Cursor()
+    if err != nil {
+        return err
+    }
+    defer cursor.Close()
+    for cursor.Next() {
+        if !f(cursor.Record()) {
+            break
+        }
+    }
+    return cursor.Err()
+}
+
+// 读取下一条记录，没有更多记录或者读取失败时返回false(可通过Err获取错误信息)
+func (c *Cursor) Next() bool {
+    if c.err != nil || !c.rows.Next() {
+        return false
+    }
+    c.record, c.err = c.scanner.scan(c.rows)
+    return c.err == nil
+}
+
+// 获取当前读取的记录
+func (c *Cursor) Record() Record {
+    return c.record
+}
+
+// 将当前读取的记录映射到指定的struct对象中，注意参数应当是一个对象的指针
+func (c *Cursor) Struct(obj interface{}) error {
+    if c.record == nil {
+        return errors.New("no record available, Next should be called first")
+    }
+    return c.record.ToStruct(obj)
+}
+
+// 获取读取过程中产生的错误
+func (c *Cursor) Err() error {
+    if c.err != nil {
+        return c.err
+    }
+    return c.rows.Err()
+}
+
+// 关闭游标，释放底层的数据库链接
+func (c *Cursor) Close() error {
+    return c.rows.Close()
+}
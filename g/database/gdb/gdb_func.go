@@ -10,17 +10,24 @@ import (
     "bytes"
     "errors"
     "fmt"
-    "github.com/gogf/gf/g/os/glog"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/text/gregex"
     "github.com/gogf/gf/g/util/gconv"
     _ "github.com/gogf/gf/third/github.com/go-sql-driver/mysql"
     "reflect"
     "strings"
+    "time"
 )
 
-// 格式化SQL查询条件
-func formatCondition(where interface{}, args []interface{}) (newWhere string, newArgs []interface{}) {
+// 格式化SQL查询条件，当where为map/struct类型时，键名将会使用数据库的标识符引用符号进行包裹
+func formatCondition(db DB, where interface{}, args []interface{}) (newWhere string, newArgs []interface{}, err error) {
+    // 查询条件构造对象
+    if builder, ok := where.(*WhereBuilder); ok {
+        if newWhere, newArgs, err = builder.buildGroup(); err == nil && newWhere == "" {
+            newWhere = "1=1"
+        }
+        return
+    }
     // 条件字符串处理
     buffer := bytes.NewBuffer(nil)
     // 使用反射进行类型判断
@@ -34,11 +41,12 @@ func formatCondition(where interface{}, args []interface{}) (newWhere string, ne
         // 注意当where为map/struct类型时，args参数必须为空。
         case reflect.Map:   fallthrough
         case reflect.Struct:
+            charL, charR := db.getChars()
             for k, v := range gconv.Map(where) {
                 if buffer.Len() > 0 {
                     buffer.WriteString(" AND ")
                 }
-                buffer.WriteString(k + "=?")
+                buffer.WriteString(quoteWord(charL, charR, k) + "=?")
                 newArgs = append(newArgs, v)
             }
             newWhere = buffer.String()
@@ -48,8 +56,16 @@ func formatCondition(where interface{}, args []interface{}) (newWhere string, ne
     if buffer.Len() == 0 {
         buffer.WriteString("1=1")
     }
-    // 查询条件参数处理，主要处理slice参数类型
+    // 命名参数处理(例如: id=:id)，参数为单个map/struct
     newWhere = buffer.String()
+    if isNamedParams(newWhere, args) {
+        query, namedArgs, err := formatNamedParams(newWhere, args)
+        if err != nil {
+            return "", nil, err
+        }
+        return query, append(newArgs, namedArgs...), nil
+    }
+    // 查询条件参数处理，主要处理slice参数类型
     if len(args) > 0 {
         for index, arg := range args {
             rv   := reflect.ValueOf(arg)
@@ -82,22 +98,6 @@ func formatCondition(where interface{}, args []interface{}) (newWhere string, ne
     return
 }
 
-// 打印SQL对象(仅在debug=true时有效)
-func printSql(v *Sql) {
-    s := fmt.Sprintf("%s, %v, %s, %s, %d ms, %s", v.Sql, v.Args,
-        gtime.NewFromTimeStamp(v.Start).Format("Y-m-d H:i:s.u"),
-        gtime.NewFromTimeStamp(v.End).Format("Y-m-d H:i:s.u"),
-        v.End - v.Start,
-        v.Func,
-    )
-    if v.Error != nil {
-        s += "\nError: " + v.Error.Error()
-        glog.Backtrace(true, 2).Error(s)
-    } else {
-        glog.Debug(s)
-    }
-}
-
 // 格式化错误信息
 func formatError(err error, query string, args ...interface{}) error {
     if err != nil {
@@ -123,3 +123,276 @@ func getInsertOperationByOption(option int) string {
     }
     return operator
 }
+
+// 创建数据写入选项对象
+func newInsertOption(option int, batch...int) *insertOption {
+    o := &insertOption{
+        option : option,
+        batch  : gDEFAULT_BATCH_NUM,
+    }
+    if len(batch) > 0 && batch[0] > 0 {
+        o.batch = batch[0]
+    }
+    return o
+}
+
+// 获得冲突时需要更新的字段列表，没有指定时返回写入的所有字段
+func (o *insertOption) getUpdateColumns(columns []string) []string {
+    if len(o.onDuplicate) > 0 {
+        return o.onDuplicate
+    }
+    return columns
+}
+
+// 生成标准的ON CONFLICT DO UPDATE冲突更新语句(PostgreSQL/SQLite)，
+// 该语法必须给定冲突检测字段(主键或者唯一索引字段)。
+func formatOnConflictUpsert(charL, charR string, columns []string, option *insertOption) (string, error) {
+    if len(option.onConflict) == 0 {
+        return "", errors.New("conflict columns are required for saving, please specify them using OnConflict")
+    }
+    var updates []string
+    for _, k := range option.getUpdateColumns(columns) {
+        updates = append(updates,
+            fmt.Sprintf("%s%s%s=EXCLUDED.%s%s%s",
+                charL, k, charR,
+                charL, k, charR,
+            ),
+        )
+    }
+    return fmt.Sprintf("ON CONFLICT(%s%s%s) DO UPDATE SET %s",
+        charL, strings.Join(option.onConflict, charR + "," + charL), charR,
+        strings.Join(updates, ","),
+    ), nil
+}
+
+// 将以半角逗号连接的字段字符串拆分为字段列表
+func splitFields(fields string) []string {
+    array := make([]string, 0)
+    for _, v := range strings.Split(fields, ",") {
+        if v = strings.TrimSpace(v); v != "" {
+            array = append(array, v)
+        }
+    }
+    return array
+}
+
+// 使用数据库的标识符引用符号包裹字段名称或者表名称(支持schema.table格式)，
+// 非简单标识符(例如包含别名、函数、操作符或者已包裹的名称)原样返回。
+func quoteWord(charL, charR string, word string) string {
+    if !gregex.IsMatchString(`^[\w\.]+$`, word) {
+        return word
+    }
+    return charL + strings.Join(strings.Split(word, "."), charR + "." + charL) + charR
+}
+
+// 将批量写入的数据参数转换为List类型，参数list支持slice类型(例如: []map/[]struct/[]*struct)，也支持单条的map/struct
+func formatBatchList(list interface{}) (List, error) {
+    switch v := list.(type) {
+        case List:
+            return v, nil
+        case Map:
+            return List{v}, nil
+    }
+    rv   := reflect.ValueOf(list)
+    kind := rv.Kind()
+    if kind == reflect.Ptr {
+        rv   = rv.Elem()
+        kind = rv.Kind()
+    }
+    switch kind {
+        // 如果是slice，那么转换为List类型
+        case reflect.Slice: fallthrough
+        case reflect.Array:
+            listMap := make(List, rv.Len())
+            for i := 0; i < rv.Len(); i++ {
+                listMap[i] = dataToMap(rv.Index(i).Interface())
+            }
+            return listMap, nil
+        case reflect.Map:   fallthrough
+        case reflect.Struct:
+            return List{Map(dataToMap(list))}, nil
+        default:
+            return nil, errors.New(fmt.Sprint("unsupported list type:", kind))
+    }
+}
+
+// 将SQL中的'?'占位符按照顺序转换为数据库特定的占位符格式(例如: $1, @p1, :1)，
+// 字符串常量、引用标识符及注释中的'?'字符不做转换。
+// 这些数据库的字符串常量中反斜杠不是转义字符，PostgreSQL的E'...'字符串除外。
+func formatPlaceholder(query string, format func(index int) string) string {
+    index  := 0
+    buffer := bytes.NewBuffer(nil)
+    for i := 0; i < len(query); i++ {
+        c := query[i]
+        switch {
+            case c == '[':
+                // SQL Server的引用标识符
+                end := strings.IndexByte(query[i:], ']')
+                if end < 0 {
+                    end = len(query) - i - 1
+                }
+                buffer.WriteString(query[i : i + end + 1])
+                i += end
+                continue
+            case c == '?':
+                index++
+                buffer.WriteString(format(index))
+                continue
+        }
+        backslash := c == '\'' && i > 0 && (query[i - 1] == 'E' || query[i - 1] == 'e') && (i < 2 || !isNamedParamChar(query[i - 2]))
+        if end := skipSqlLiteral(query, i, backslash); end > i {
+            buffer.WriteString(query[i : end])
+            i = end - 1
+            continue
+        }
+        buffer.WriteByte(c)
+    }
+    return buffer.String()
+}
+
+// 获得SQL中从i开始的字符串常量、引用标识符或者注释(-- 及 /* */)的结束位置(不包含)，i处不是这些内容时返回i。
+// backslash表示字符串常量中的反斜杠是否为转义字符(MySQL)，连续的两个引号作为相邻的两个字符串常量处理，结果相同。
+func skipSqlLiteral(query string, i int, backslash bool) int {
+    c := query[i]
+    switch {
+        case c == '-' && i + 1 < len(query) && query[i + 1] == '-':
+            if end := strings.IndexByte(query[i : ], '\n'); end >= 0 {
+                return i + end + 1
+            }
+            return len(query)
+        case c == '/' && i + 1 < len(query) && query[i + 1] == '*':
+            if end := strings.Index(query[i + 2 : ], "*/"); end >= 0 {
+                return i + 2 + end + 2
+            }
+            return len(query)
+        case c == '\'' || c == '"' || c == '`':
+            escape := backslash && c != '`'
+            for j := i + 1; j < len(query); j++ {
+                if escape && query[j] == '\\' {
+                    j++
+                    continue
+                }
+                if query[j] == c {
+                    return j + 1
+                }
+            }
+            return len(query)
+    }
+    return i
+}
+
+// 判断是否为命名参数的SQL语句：参数为单个map/struct对象(time.Time等时间对象除外)，并且SQL中包含命名参数
+func isNamedParams(query string, args []interface{}) bool {
+    if len(args) != 1 || args[0] == nil {
+        return false
+    }
+    switch args[0].(type) {
+        case time.Time, *time.Time, gtime.Time, *gtime.Time:
+            return false
+    }
+    rv   := reflect.ValueOf(args[0])
+    kind := rv.Kind()
+    if kind == reflect.Ptr {
+        kind = rv.Elem().Kind()
+    }
+    if kind != reflect.Map && kind != reflect.Struct {
+        return false
+    }
+    found := false
+    parseNamedParams(query, func(name string) string {
+        found = true
+        return ""
+    })
+    return found
+}
+
+// 将SQL中的命名参数(例如: :id, :name)转换为'?'占位符，参数值从map/struct中按照名称获取，
+// 当参数值为slice类型时将会展开为多个'?'占位符(常用于IN查询)，参数值为空slice时返回错误。
+// 非命名参数的SQL语句原样返回。
+func formatNamedParams(query string, args []interface{}) (string, []interface{}, error) {
+    if !isNamedParams(query, args) {
+        return query, args, nil
+    }
+    params   := gconv.Map(args[0])
+    newArgs  := make([]interface{}, 0)
+    err      := (error)(nil)
+    newQuery := parseNamedParams(query, func(name string) string {
+        value, ok := params[name]
+        if !ok {
+            for k, v := range params {
+                if strings.EqualFold(k, name) {
+                    value, ok = v, true
+                    break
+                }
+            }
+        }
+        if !ok {
+            if err == nil {
+                err = errors.New(fmt.Sprintf(`named parameter ":%s" not found in arguments`, name))
+            }
+            return ":" + name
+        }
+        if _, ok := value.([]byte); !ok {
+            rv := reflect.ValueOf(value)
+            if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+                if rv.Len() == 0 {
+                    if err == nil {
+                        err = errors.New(fmt.Sprintf(`named parameter ":%s" is bound to an empty slice`, name))
+                    }
+                    return ":" + name
+                }
+                for i := 0; i < rv.Len(); i++ {
+                    newArgs = append(newArgs, rv.Index(i).Interface())
+                }
+                return "?" + strings.Repeat(",?", rv.Len() - 1)
+            }
+        }
+        newArgs = append(newArgs, value)
+        return "?"
+    })
+    if err != nil {
+        return query, args, err
+    }
+    return newQuery, newArgs, nil
+}
+
+// 遍历SQL中的命名参数并使用replace的返回值进行替换，字符串常量(反斜杠作为转义字符)、引用标识符、注释
+// 以及PostgreSQL的类型转换(::type)不做处理
+func parseNamedParams(query string, replace func(name string) string) string {
+    buffer := bytes.NewBuffer(nil)
+    for i := 0; i < len(query); i++ {
+        c := query[i]
+        switch {
+            case c == ':' && i + 1 < len(query) && query[i + 1] == ':':
+                // 类型转换符号(::)，连同后面的类型名称一起跳过
+                buffer.WriteString("::")
+                i++
+                continue
+            case c == ':' && i + 1 < len(query) && isNamedParamStart(query[i + 1]):
+                j := i + 1
+                for j < len(query) && isNamedParamChar(query[j]) {
+                    j++
+                }
+                buffer.WriteString(replace(query[i + 1 : j]))
+                i = j - 1
+                continue
+        }
+        if end := skipSqlLiteral(query, i, true); end > i {
+            buffer.WriteString(query[i : end])
+            i = end - 1
+            continue
+        }
+        buffer.WriteByte(c)
+    }
+    return buffer.String()
+}
+
+// 命名参数的首字符(字母或者下划线)
+func isNamedParamStart(c byte) bool {
+    return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// 命名参数的字符(字母、数字或者下划线)
+func isNamedParamChar(c byte) bool {
+    return isNamedParamStart(c) || (c >= '0' && c <= '9')
+}
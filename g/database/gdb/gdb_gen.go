@@ -0,0 +1,346 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/text/gregex"
+    "go/format"
+    "strings"
+)
+
+// 代码生成配置，用于根据数据表结构生成golang struct定义及DAO代码
+type GenConfig struct {
+    Package     string                    // 生成代码的包名，默认为model
+    TablePrefix string                    // 生成结构体名称时需要去掉的数据表名称前缀
+    Tags        []string                  // 属性需要生成的标签名称，默认为gconv和json，gconv标签值与字段名称一致，用于ORM映射
+    JsonCase    string                    // json标签的命名方式：snake(默认，与字段名称一致)/camel(小驼峰)/Camel(大驼峰)
+    StructName  func(table string) string // 自定义结构体命名规则，默认为去掉前缀后的大驼峰格式
+    FieldName   func(field string) string // 自定义属性命名规则，默认为大驼峰格式
+    TypeMap     map[string]string         // 字段类型映射，覆盖默认的类型转换规则，键名可以为"表名.字段名"、完整字段类型(例如: tinyint(1))或者基础字段类型(例如: decimal)
+    WithDao     bool                      // 是否同时生成DAO代码
+}
+
+// 根据数据表结构生成golang struct定义代码(不包含package及import部分)
+func GenerateStruct(db DB, table string, config...GenConfig) (string, error) {
+    c := getGenConfig(config...)
+    fields, err := db.TableFields(table)
+    if err != nil {
+        return "", err
+    }
+    if len(fields) == 0 {
+        return "", errors.New(fmt.Sprintf(`no fields found for table "%s"`, table))
+    }
+    buffer := bytes.NewBuffer(nil)
+    writeGenStruct(buffer, table, fields, c)
+    source, err := format.Source(buffer.Bytes())
+    if err != nil {
+        return "", err
+    }
+    return string(source), nil
+}
+
+// 根据数据表结构生成完整的golang代码文件内容，包括package、import、struct定义以及DAO代码(WithDao为true时)
+func GenerateCode(db DB, table string, config...GenConfig) (string, error) {
+    c := getGenConfig(config...)
+    fields, err := db.TableFields(table)
+    if err != nil {
+        return "", err
+    }
+    if len(fields) == 0 {
+        return "", errors.New(fmt.Sprintf(`no fields found for table "%s"`, table))
+    }
+    body := bytes.NewBuffer(nil)
+    writeGenStruct(body, table, fields, c)
+    if c.WithDao {
+        writeGenDao(body, table, fields, c)
+    }
+    imports := make([]string, 0)
+    for _, item := range []struct{ pkg, ident string } {
+        { "database/sql",                      "sql."   },
+        { "github.com/gogf/gf/g/database/gdb", "gdb."   },
+        { "github.com/gogf/gf/g/os/gtime",     "gtime." },
+    } {
+        if strings.Contains(body.String(), item.ident) {
+            imports = append(imports, fmt.Sprintf(`"%s"`, item.pkg))
+        }
+    }
+    buffer := bytes.NewBuffer(nil)
+    buffer.WriteString("// Code generated by gdb. DO NOT EDIT.\n\n")
+    buffer.WriteString(fmt.Sprintf("package %s\n\n", c.Package))
+    if len(imports) > 0 {
+        buffer.WriteString(fmt.Sprintf("import (\n%s\n)\n\n", strings.Join(imports, "\n")))
+    }
+    buffer.Write(body.Bytes())
+    source, err := format.Source(buffer.Bytes())
+    if err != nil {
+        return "", err
+    }
+    return string(source), nil
+}
+
+// 根据数据表结构生成代码文件到指定目录，文件名称为"表名.go"，tables为空时生成当前数据库的所有数据表
+func GenerateFiles(db DB, path string, config GenConfig, tables...string) error {
+    if len(tables) == 0 {
+        array, err := db.Tables()
+        if err != nil {
+            return err
+        }
+        tables = array
+    }
+    for _, table := range tables {
+        source, err := GenerateCode(db, table, config)
+        if err != nil {
+            return err
+        }
+        file := path + gfile.Separator + strings.Replace(table, ".", "_", -1) + ".go"
+        if err := gfile.PutContents(file, source); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 获得代码生成配置，并设置默认值
+func getGenConfig(config...GenConfig) GenConfig {
+    c := GenConfig{}
+    if len(config) > 0 {
+        c = config[0]
+    }
+    if c.Package == "" {
+        c.Package = "model"
+    }
+    if len(c.Tags) == 0 {
+        c.Tags = []string{"gconv", "json"}
+    }
+    if c.StructName == nil {
+        c.StructName = func(table string) string {
+            return genCamelName(strings.TrimPrefix(table, c.TablePrefix))
+        }
+    }
+    if c.FieldName == nil {
+        c.FieldName = genCamelName
+    }
+    return c
+}
+
+// 生成struct定义
+func writeGenStruct(buffer *bytes.Buffer, table string, fields []*TableField, c GenConfig) {
+    buffer.WriteString(fmt.Sprintf("// %s is the golang structure for table %s.\n", c.StructName(table), table))
+    buffer.WriteString(fmt.Sprintf("type %s struct {\n", c.StructName(table)))
+    for _, field := range fields {
+        tags := make([]string, len(c.Tags))
+        for i, tag := range c.Tags {
+            if tag == "json" {
+                tags[i] = fmt.Sprintf(`json:"%s"`, genJsonName(field.Name, c.JsonCase))
+            } else {
+                tags[i] = fmt.Sprintf(`%s:"%s"`, tag, field.Name)
+            }
+        }
+        line := fmt.Sprintf("%s %s `%s`", c.FieldName(field.Name), genGoType(table, field, c.TypeMap), strings.Join(tags, " "))
+        if field.Comment != "" {
+            line += " // " + strings.Replace(field.Comment, "\n", " ", -1)
+        }
+        buffer.WriteString(line + "\n")
+    }
+    buffer.WriteString("}\n\n")
+}
+
+// 生成DAO代码，包含常用的增删改查方法，存在单一主键时额外生成FindByPk方法
+func writeGenDao(buffer *bytes.Buffer, table string, fields []*TableField, c GenConfig) {
+    name := c.StructName(table)
+    dao  := name + "Dao"
+    pk   := (*TableField)(nil)
+    for _, field := range fields {
+        if field.Key == "PRI" {
+            if pk != nil {
+                pk = nil
+                break
+            }
+            pk = field
+        }
+    }
+    buffer.WriteString(fmt.Sprintf(`// %[1]s is the data access object for table %[3]s.
+type %[1]s struct {
+    db gdb.DB
+}
+
+// New%[1]s creates and returns a data access object for table %[3]s.
+func New%[1]s(db gdb.DB) *%[1]s {
+    return &%[1]s{db: db}
+}
+
+// Table returns the table name of the data access object.
+func (d *%[1]s) Table() string {
+    return %[4]q
+}
+
+// Model creates and returns a new chaining model for table %[3]s.
+func (d *%[1]s) Model() *gdb.Model {
+    return d.db.Table(d.Table())
+}
+
+// FindOne retrieves and returns a single record by given condition, it returns nil if no record found.
+func (d *%[1]s) FindOne(where interface{}, args ...interface{}) (*%[2]s, error) {
+    record, err := d.Model().Where(where, args...).One()
+    if err != nil || record == nil {
+        return nil, err
+    }
+    entity := new(%[2]s)
+    if err := record.ToStruct(entity); err != nil {
+        return nil, err
+    }
+    return entity, nil
+}
+
+// FindAll retrieves and returns records by given condition.
+func (d *%[1]s) FindAll(where interface{}, args ...interface{}) ([]*%[2]s, error) {
+    result, err := d.Model().Where(where, args...).All()
+    if err != nil {
+        return nil, err
+    }
+    entities := make([]*%[2]s, len(result))
+    for i, record := range result {
+        entities[i] = new(%[2]s)
+        if err := record.ToStruct(entities[i]); err != nil {
+            return nil, err
+        }
+    }
+    return entities, nil
+}
+
+// Insert inserts given entity into table %[3]s.
+func (d *%[1]s) Insert(entity *%[2]s) (sql.Result, error) {
+    return d.Model().Data(entity).Insert()
+}
+
+// Save inserts or updates given entity into table %[3]s.
+func (d *%[1]s) Save(entity *%[2]s) (sql.Result, error) {
+    return d.Model().Data(entity).Save()
+}
+
+// Update updates records by given data and condition.
+func (d *%[1]s) Update(data interface{}, where interface{}, args ...interface{}) (sql.Result, error) {
+    return d.Model().Data(data).Where(where, args...).Update()
+}
+
+// Delete deletes records by given condition.
+func (d *%[1]s) Delete(where interface{}, args ...interface{}) (sql.Result, error) {
+    return d.Model().Where(where, args...).Delete()
+}
+
+// Count returns the number of records by given condition.
+func (d *%[1]s) Count(where interface{}, args ...interface{}) (int, error) {
+    return d.Model().Where(where, args...).Count()
+}
+
+`, dao, name, table, table))
+    if pk != nil {
+        buffer.WriteString(fmt.Sprintf(`// FindByPk retrieves and returns a single record by primary key %[3]s.
+func (d *%[1]s) FindByPk(pk interface{}) (*%[2]s, error) {
+    return d.FindOne(%[4]q, pk)
+}
+
+`, dao, name, pk.Name, pk.Name + "=?"))
+    }
+}
+
+// 根据字段类型获得对应的golang变量类型
+func genGoType(table string, field *TableField, typeMap map[string]string) string {
+    fieldType := strings.ToLower(strings.TrimSpace(field.Type))
+    // 去掉ClickHouse的Nullable/LowCardinality类型包裹
+    for {
+        if match, _ := gregex.MatchString(`^(?:nullable|lowcardinality)\((.+)\)$`, fieldType); len(match) > 1 {
+            fieldType = match[1]
+        } else {
+            break
+        }
+    }
+    baseType, _ := gregex.ReplaceString(`\(.*\)`, "", fieldType)
+    baseType     = strings.TrimSpace(baseType)
+    unsigned    := strings.Contains(baseType, "unsigned")
+    if array := strings.Fields(baseType); len(array) > 0 {
+        baseType = array[0]
+    }
+    for _, key := range []string{table + "." + field.Name, fieldType, baseType} {
+        if t, ok := typeMap[key]; ok {
+            return t
+        }
+    }
+    switch {
+        case strings.HasPrefix(baseType, "_") || strings.HasPrefix(baseType, "array"):
+            return "[]interface{}"
+
+        case baseType == "bool" || baseType == "boolean" || fieldType == "tinyint(1)" || fieldType == "bit(1)":
+            return "bool"
+
+        case gregex.IsMatchString(`^(bigint|int8|int64|uint64|bigserial)$`, baseType):
+            if unsigned || baseType == "uint64" {
+                return "uint64"
+            }
+            return "int64"
+
+        case gregex.IsMatchString(`^(tinyint|smallint|mediumint|int|integer|int2|int4|int16|int32|uint8|uint16|uint32|serial|smallserial|bit|year)$`, baseType):
+            if unsigned || strings.HasPrefix(baseType, "uint") {
+                return "uint"
+            }
+            return "int"
+
+        case baseType == "number":
+            // Oracle的NUMBER类型没有指定精度或者小数位为0时为整型
+            if gregex.IsMatchString(`^number(\(\d+(,\s*0)?\))?$`, fieldType) {
+                return "int64"
+            }
+            return "float64"
+
+        case gregex.IsMatchString(`^(float|float4|float8|float32|float64|double|real|decimal|numeric|money)$`, baseType):
+            return "float64"
+
+        case strings.Contains(baseType, "blob") || strings.Contains(baseType, "binary") || baseType == "bytea" || baseType == "image":
+            return "[]byte"
+
+        case strings.Contains(baseType, "date") || strings.Contains(baseType, "time"):
+            return "gtime.Time"
+
+        default:
+            return "string"
+    }
+}
+
+// 将字段名称转换为json标签名称
+func genJsonName(name string, jsonCase string) string {
+    switch jsonCase {
+        case "camel":
+            s := genCamelName(name)
+            if len(s) > 0 {
+                s = strings.ToLower(s[:1]) + s[1:]
+            }
+            return s
+        case "Camel":
+            return genCamelName(name)
+        default:
+            return name
+    }
+}
+
+// 将名称转换为大驼峰格式(例如: user_detail => UserDetail)，非字母数字字符作为单词分隔符，以数字开头时增加前缀F
+func genCamelName(name string) string {
+    buffer := bytes.NewBuffer(nil)
+    for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+        return !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'))
+    }) {
+        buffer.WriteString(strings.ToUpper(word[:1]) + word[1:])
+    }
+    s := buffer.String()
+    if s == "" || (s[0] >= '0' && s[0] <= '9') {
+        s = "F" + s
+    }
+    return s
+}
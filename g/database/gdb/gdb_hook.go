@@ -0,0 +1,86 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "context"
+    "database/sql"
+)
+
+// 链式操作的钩子事件名称
+const (
+    HOOK_BEFORE_INSERT = "BeforeInsert"
+    HOOK_AFTER_INSERT  = "AfterInsert"
+    HOOK_BEFORE_UPDATE = "BeforeUpdate"
+    HOOK_AFTER_UPDATE  = "AfterUpdate"
+    HOOK_BEFORE_DELETE = "BeforeDelete"
+    HOOK_AFTER_DELETE  = "AfterDelete"
+    HOOK_AFTER_QUERY   = "AfterQuery"
+)
+
+// 钩子函数的输入参数，Before类钩子可以修改Data/Where/Args以改变即将执行的操作，
+// AfterQuery钩子可以修改Result以改变返回的查询结果(使用查询缓存时Result为缓存结果的副本，修改不会影响缓存)。
+type HookInput struct {
+    Ctx       context.Context // 当前操作绑定的上下文对象(未绑定时为context.Background())
+    Event     string          // 钩子事件名称
+    Table     string          // 操作的数据表(逻辑表名称)
+    Data      interface{}     // (Insert/Update)写入/更新的数据，Insert时为Map或者List，Update时为Map或者string
+    Where     string          // (Update/Delete/Query)操作条件
+    Args      []interface{}   // (Update/Delete/Query)操作条件参数
+    Result    Result          // (AfterQuery)查询结果
+    SqlResult sql.Result      // (AfterInsert/AfterUpdate/AfterDelete)执行结果
+    Model     *Model          // 当前链式操作对象
+}
+
+// 钩子函数，返回错误时将会中断当前操作并返回该错误
+type HookFunc func(in *HookInput) error
+
+// 注册全局钩子函数，对该数据库对象的所有链式操作生效，同一事件可以注册多个钩子函数，按照注册顺序执行。
+// 常用于审计日志、数据加解密、缓存清理等场景。
+func (bs *dbBase) AddHook(event string, f HookFunc) {
+    bs.hooks.LockFunc(func(m map[string]interface{}) {
+        hooks := make([]HookFunc, 0)
+        if v, ok := m[event]; ok {
+            hooks = append(hooks, v.([]HookFunc)...)
+        }
+        m[event] = append(hooks, f)
+    })
+}
+
+// 获得指定事件的全局钩子函数列表
+func (bs *dbBase) getHooks(event string) []HookFunc {
+    if v := bs.hooks.Get(event); v != nil {
+        return v.([]HookFunc)
+    }
+    return nil
+}
+
+// 链式操作，注册仅对当前链式操作对象生效的钩子函数，在全局钩子函数之后执行
+func (md *Model) Hook(event string, f HookFunc) *Model {
+    model      := md.Clone()
+    model.hooks = make(map[string][]HookFunc, len(md.hooks) + 1)
+    for k, v := range md.hooks {
+        model.hooks[k] = v
+    }
+    model.hooks[event] = append(append([]HookFunc{}, md.hooks[event]...), f)
+    return model
+}
+
+// 执行指定事件的钩子函数(全局钩子函数优先)
+func (md *Model) callHooks(in *HookInput) error {
+    in.Ctx   = md.db.getCtx()
+    in.Table = md.tablesInit
+    in.Model = md
+    for _, hooks := range [][]HookFunc{md.db.getHooks(in.Event), md.hooks[in.Event]} {
+        for _, f := range hooks {
+            if err := f(in); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
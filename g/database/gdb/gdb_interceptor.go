@@ -0,0 +1,100 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "context"
+    "database/sql"
+    "time"
+)
+
+// SQL语句拦截器，对该数据库对象执行的每一条SQL语句(包括事务中的语句)生效，
+// 常用于审计日志、自定义统计指标以及SQL改写(例如添加租户过滤条件)等场景。
+type Interceptor interface {
+    // 语句执行前调用，可以修改in.Sql/in.Args以改写即将执行的语句(使用?占位符)，返回错误时将中断执行并返回该错误
+    Before(in *InterceptorInput) error
+    // 语句执行后调用(执行失败时同样会调用)，此时in.Duration/in.RowsAffected/in.Error有效
+    After(in *InterceptorInput)
+}
+
+// 拦截器的输入参数
+type InterceptorInput struct {
+    Func         string          // 执行方法(Query/Exec/Prepare)
+    Sql          string          // SQL语句(?占位符，尚未转换为数据库特定的占位符格式)
+    Args         []interface{}   // 预处理参数值列表
+    Group        string          // 数据库配置分组名称
+    Link         string          // 执行链接的标签名称(角色@地址，事务为tx)
+    TxId         string          // 事务ID，非事务操作时为空
+    Ctx          context.Context // 执行时绑定的上下文对象(没有绑定时为nil)
+    Start        time.Time       // 执行开始时间
+    Duration     time.Duration   // (After)执行耗时
+    RowsAffected int64           // (After)影响的记录数，仅Exec执行成功时有效，其他情况为-1
+    Error        error           // (After)执行错误
+}
+
+// 注册SQL语句拦截器，多个拦截器按照注册顺序执行Before，按照注册的逆序执行After
+func (bs *dbBase) AddInterceptor(interceptor Interceptor) {
+    bs.interceptors.Append(interceptor)
+}
+
+// 获得已注册的拦截器列表
+func (bs *dbBase) getInterceptors() []Interceptor {
+    if bs.interceptors.Len() == 0 {
+        return nil
+    }
+    values       := bs.interceptors.Slice()
+    interceptors := make([]Interceptor, len(values))
+    for i, v := range values {
+        interceptors[i] = v.(Interceptor)
+    }
+    return interceptors
+}
+
+// 执行拦截器的Before方法，没有注册拦截器时返回nil
+func (bs *dbBase) interceptBefore(link dbLink, function string, query string, args []interface{}) (*InterceptorInput, error) {
+    interceptors := bs.getInterceptors()
+    if len(interceptors) == 0 {
+        return nil, nil
+    }
+    in := &InterceptorInput {
+        Func         : function,
+        Sql          : query,
+        Args         : args,
+        Group        : bs.group,
+        Link         : bs.getLinkTag(link),
+        Ctx          : bs.ctx,
+        RowsAffected : -1,
+    }
+    if tx, ok := link.(*txLink); ok {
+        in.TxId = tx.id
+    }
+    for _, interceptor := range interceptors {
+        if err := interceptor.Before(in); err != nil {
+            return nil, err
+        }
+    }
+    in.Start = time.Now()
+    return in, nil
+}
+
+// 执行拦截器的After方法，in为nil时(没有注册拦截器)不做处理
+func (bs *dbBase) interceptAfter(in *InterceptorInput, result sql.Result, err error) {
+    if in == nil {
+        return
+    }
+    in.Duration = time.Since(in.Start)
+    in.Error    = err
+    if err == nil && result != nil {
+        if n, e := result.RowsAffected(); e == nil {
+            in.RowsAffected = n
+        }
+    }
+    interceptors := bs.getInterceptors()
+    for i := len(interceptors) - 1; i >= 0; i-- {
+        interceptors[i].After(in)
+    }
+}
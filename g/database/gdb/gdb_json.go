@@ -0,0 +1,169 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "encoding/json"
+    "reflect"
+    "strings"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+const (
+    // JSON字段标签，struct属性使用 gdb:"json" 标签标识为JSON字段，例如：
+    // type User struct {
+    //     Id      int
+    //     Profile *Profile          `gdb:"json"`
+    //     Tags    []string          `gdb:"json"`
+    //     Extra   map[string]string `gconv:"extra" gdb:"json"`
+    // }
+    // 写入/更新时该属性将被编码为JSON字符串，查询结果转换为struct时将自动解码到该属性上，
+    // 适用于MySQL的JSON字段、PostgreSQL的json/jsonb字段，以及使用文本字段存储JSON内容的场景。
+    gTAG_NAME_GDB   = "gdb"
+    gTAG_VALUE_JSON = "json"
+)
+
+// 判断struct属性是否标识为JSON字段
+func isJsonField(field reflect.StructField) bool {
+    for _, v := range strings.Split(field.Tag.Get(gTAG_NAME_GDB), ",") {
+        if strings.TrimSpace(v) == gTAG_VALUE_JSON {
+            return true
+        }
+    }
+    return false
+}
+
+// 将写入/更新的数据转换为map，在gconv.Map的基础上将标识为JSON字段的struct属性编码为JSON字符串
+func dataToMap(data interface{}) map[string]interface{} {
+    m := gconv.Map(data)
+    if m == nil {
+        return nil
+    }
+    rv := reflect.ValueOf(data)
+    for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+        rv = rv.Elem()
+    }
+    if rv.Kind() != reflect.Struct {
+        return m
+    }
+    rt := rv.Type()
+    for i := 0; i < rt.NumField(); i++ {
+        field := rt.Field(i)
+        if field.PkgPath != "" || !isJsonField(field) {
+            continue
+        }
+        name := getMapKeyOfField(field)
+        if _, ok := m[name]; !ok {
+            continue
+        }
+        value, err := encodeJsonValue(rv.Field(i))
+        if err != nil {
+            // 编码失败时保留原值，由数据库驱动返回错误
+            continue
+        }
+        m[name] = value
+    }
+    return m
+}
+
+// 获得struct属性在gconv.Map转换结果中对应的键名(与gconv.Map的规则一致：优先使用gconv标签，其次json标签，最后为属性名称)
+func getMapKeyOfField(field reflect.StructField) string {
+    name := field.Tag.Get("gconv")
+    if name == "" {
+        name = field.Tag.Get("json")
+    }
+    if name = strings.TrimSpace(strings.Split(name, ",")[0]); name == "" {
+        name = strings.TrimSpace(field.Name)
+    }
+    return name
+}
+
+// 将属性值编码为JSON字符串，nil指针/slice/map编码为NULL
+func encodeJsonValue(rv reflect.Value) (interface{}, error) {
+    switch rv.Kind() {
+        case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+            if rv.IsNil() {
+                return nil, nil
+            }
+    }
+    b, err := json.Marshal(rv.Interface())
+    if err != nil {
+        return nil, err
+    }
+    return string(b), nil
+}
+
+// 将JSON字段的记录值解码到struct属性上，记录值为NULL时属性设置为零值。
+// 记录值可能为JSON字符串(MySQL)，也可能已被解析为map/slice等变量(PostgreSQL)。
+func bindJsonValueToField(fieldValue reflect.Value, value interface{}) error {
+    if value == nil {
+        fieldValue.Set(reflect.Zero(fieldValue.Type()))
+        return nil
+    }
+    var content []byte
+    switch v := value.(type) {
+        case []byte:
+            content = v
+        case string:
+            content = []byte(v)
+        default:
+            b, err := json.Marshal(v)
+            if err != nil {
+                return err
+            }
+            content = b
+    }
+    if len(content) == 0 {
+        fieldValue.Set(reflect.Zero(fieldValue.Type()))
+        return nil
+    }
+    pointer := reflect.New(fieldValue.Type())
+    if err := json.Unmarshal(content, pointer.Interface()); err != nil {
+        return err
+    }
+    fieldValue.Set(pointer.Elem())
+    return nil
+}
+
+// 判断参数值是否需要按照JSON字段编码(slice/map/struct类型，不包括[]byte及时间类型)
+func isJsonParamValue(value interface{}) bool {
+    if value == nil {
+        return false
+    }
+    if _, ok := value.([]byte); ok {
+        return false
+    }
+    rv := reflect.ValueOf(value)
+    if rv.Kind() == reflect.Ptr {
+        if rv.IsNil() {
+            return false
+        }
+        rv = rv.Elem()
+    }
+    switch rv.Kind() {
+        case reflect.Slice, reflect.Array, reflect.Map:
+            return true
+        case reflect.Struct:
+            t := rv.Type()
+            return t != timeType && t != gtimeType && t != ratType
+    }
+    return false
+}
+
+// 将参数值编码为JSON字符串，编码失败时返回原值
+func encodeJsonParam(value interface{}) interface{} {
+    if b, err := json.Marshal(value); err == nil {
+        return string(b)
+    }
+    return value
+}
+
+// 判断数据表字段类型是否为JSON类型(MySQL: json，PostgreSQL: json/jsonb)
+func isJsonFieldType(fieldType string) bool {
+    t := strings.ToLower(strings.TrimSpace(fieldType))
+    return t == "json" || t == "jsonb"
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "bytes"
+    "database/sql"
+    "fmt"
+    "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/text/gregex"
+    "runtime"
+)
+
+const (
+    gREDACTED_ARG_VALUE = "***" // 开启参数脱敏时参数的打印值
+    gLINK_TAG_TX        = "tx"  // 事务链接的标签名称
+)
+
+// 设置SQL日志输出使用的日志对象，默认使用glog的默认日志对象
+func (bs *dbBase) SetLogger(logger *glog.Logger) {
+    bs.logger.Set(logger)
+}
+
+// 设置慢查询阈值(单位毫秒)，执行时间大于等于该阈值的SQL将会以WARN级别输出到日志中(附带调用回溯信息)，
+// 该特性不依赖于debug模式，当ms <= 0时表示关闭慢查询日志。
+func (bs *dbBase) SetSlowThreshold(ms int) {
+    bs.slowThreshold.Set(ms)
+}
+
+// 设置SQL日志输出时是否对预处理参数进行脱敏处理(参数值统一输出为***)，避免敏感数据写入日志
+func (bs *dbBase) SetLogRedacted(enabled bool) {
+    bs.logRedacted.Set(enabled)
+}
+
+// 记录已执行的SQL，根据debug模式及慢查询阈值判断是否需要输出日志
+func (bs *dbBase) writeSqlLog(link dbLink, s *Sql) {
+    debug := bs.db.getDebug()
+    slow  := false
+    if ms := bs.slowThreshold.Val(); ms > 0 && s.End - s.Start >= int64(ms) {
+        slow = true
+    }
+    if !debug && !slow {
+        return
+    }
+    skip    := 0
+    s.Group  = bs.group
+    s.Link   = bs.getLinkTag(link)
+    s.Caller, skip = getSqlCaller()
+    if bs.logRedacted.Val() {
+        args := make([]interface{}, len(s.Args))
+        for i := range args {
+            args[i] = gREDACTED_ARG_VALUE
+        }
+        s.Args = args
+    }
+    if debug && bs.sqls != nil {
+        bs.sqls.Put(s)
+    }
+    logger := glog.Backtrace(false)
+    if v := bs.logger.Val(); v != nil {
+        logger = v.(*glog.Logger).Backtrace(false)
+    }
+    content := formatSqlLog(s)
+    switch {
+        case s.Error != nil:
+            // 执行失败的SQL无论是否为慢查询都以ERROR级别输出
+            if slow {
+                content = "[SLOW] " + content
+            }
+            logger.Backtrace(true, skip).Error(content + "\nError: " + s.Error.Error())
+        case slow:
+            logger.Backtrace(true, skip).Warning("[SLOW] " + content)
+        default:
+            logger.Debug(content)
+    }
+}
+
+// 生成结构化的SQL日志内容
+func formatSqlLog(s *Sql) string {
+    buffer := bytes.NewBuffer(nil)
+    buffer.WriteString(fmt.Sprintf(`[gdb] group=%s link=%s cost=%dms start=%s`,
+        s.Group, s.Link, s.End - s.Start,
+        gtime.NewFromTimeStamp(s.Start).Format("Y-m-d H:i:s.u"),
+    ))
+    if s.Func != "" {
+        buffer.WriteString(" func=" + s.Func)
+    }
+    if s.Caller != "" {
+        buffer.WriteString(" caller=" + s.Caller)
+    }
+    buffer.WriteString(fmt.Sprintf(` sql="%s"`, s.Sql))
+    if len(s.Args) > 0 {
+        buffer.WriteString(fmt.Sprintf(` args=%v`, s.Args))
+    }
+    return buffer.String()
+}
+
+// 获取链接对象的标签名称，用于区分SQL在哪个节点(角色@地址)或者事务中执行
+func (bs *dbBase) getLinkTag(link dbLink) string {
+    switch l := link.(type) {
+        case *txLink:
+            return gLINK_TAG_TX
+        case *sql.DB:
+            if v := bs.linkTags.Get(l); v != nil {
+                return v.(string)
+            }
+    }
+    return ""
+}
+
+// 生成配置节点的链接标签名称
+func getNodeLinkTag(node *ConfigNode) string {
+    role := node.Role
+    if role == "" {
+        role = "master"
+    }
+    if node.Host == "" {
+        return role + "@" + node.Name
+    }
+    return fmt.Sprintf(`%s@%s:%s`, role, node.Host, node.Port)
+}
+
+// 获取执行SQL的业务调用位置(跳过gdb包内部的调用)，同时返回相对于writeSqlLog的回溯skip数量
+func getSqlCaller() (caller string, skip int) {
+    for i := 2; i < 100; i++ {
+        if _, file, line, ok := runtime.Caller(i); ok {
+            if !gregex.IsMatchString(`/g/database/gdb/gdb(_[^/]+)?\.go$`, file) || gregex.IsMatchString(`_test\.go$`, file) {
+                return fmt.Sprintf(`%s:%d`, file, line), i - 1
+            }
+        } else {
+            break
+        }
+    }
+    return "", 0
+}
@@ -7,6 +7,7 @@
 package gdb
 
 import (
+	"context"
 	"fmt"
 	"errors"
 	"database/sql"
@@ -31,12 +32,25 @@ type Model struct {
 	limit        int           // 分页条数
 	data         interface{}   // 操作记录(支持Map/List/string类型)
 	batch        int           // 批量操作条数
+	onConflict   []string      // (Save)冲突检测字段(PostgreSQL/SQLite)
+	onDuplicate  []string      // (Save)冲突时需要更新的字段
 	filter       bool          // 是否按照表字段过滤data参数
 	cacheEnabled bool          // 当前SQL操作是否开启查询缓存功能
 	cacheTime    int           // 查询缓存时间
 	cacheName    string        // 查询缓存名称
+	linkType     int           // 查询操作使用的链接类型(默认从库)
+	shard        string        // 手动指定的分片物理表
+	hooks        map[string][]HookFunc // 当前链式操作的钩子函数
+	withs        []*withConfig // 关联数据预加载配置
+	skipCount    bool          // 分页查询时是否跳过总记录数统计
+	err          error         // 链式操作中产生的错误(例如条件参数错误)，在执行操作时返回
 }
 
+const (
+    gLINK_TYPE_SLAVE  = 0 // 查询操作使用从库链接
+    gLINK_TYPE_MASTER = 1 // 查询操作使用主库链接
+)
+
 // 链式操作，数据表字段，可支持多个表，以半角逗号连接
 func (bs *dbBase) Table(tables string) (*Model) {
 	return &Model {
@@ -116,21 +130,36 @@ func (md *Model) Filter() (*Model) {
 
 // 链式操作，condition，支持string & gdb.Map
 func (md *Model) Where(where interface{}, args ...interface{}) (*Model) {
-    model             := md.Clone()
-    newWhere, newArgs := formatCondition(where, args)
+    model                  := md.Clone()
+    newWhere, newArgs, err := formatCondition(md.db, where, args)
+    if err != nil {
+        model.setError(err)
+        return model
+    }
     model.where        = newWhere
     model.whereArgs    = append(model.whereArgs, newArgs...)
-	// 支持 Where("uid", 1)这种格式
-	if len(args) == 1 && strings.Index(model.where , "?") < 0 {
+	// 支持 Where("uid", 1)这种格式(命名参数除外)
+	if len(args) == 1 && strings.Index(model.where , "?") < 0 && !isNamedParams(gconv.String(where), args) {
         model.where += "=?"
     }
 	return model
 }
 
+// 记录链式操作中产生的错误(仅保留第一个错误)
+func (md *Model) setError(err error) {
+    if md.err == nil {
+        md.err = err
+    }
+}
+
 // 链式操作，添加AND条件到Where中
 func (md *Model) And(where interface{}, args ...interface{}) (*Model) {
-    model             := md.Clone()
-    newWhere, newArgs := formatCondition(where, args)
+    model                  := md.Clone()
+    newWhere, newArgs, err := formatCondition(md.db, where, args)
+    if err != nil {
+        model.setError(err)
+        return model
+    }
     model.where       += " AND " + newWhere
     model.whereArgs    = append(model.whereArgs, newArgs...)
 	return model
@@ -138,8 +167,12 @@ func (md *Model) And(where interface{}, args ...interface{}) (*Model) {
 
 // 链式操作，添加OR条件到Where中
 func (md *Model) Or(where interface{}, args ...interface{}) (*Model) {
-    model             := md.Clone()
-    newWhere, newArgs := formatCondition(where, args)
+    model                  := md.Clone()
+    newWhere, newArgs, err := formatCondition(md.db, where, args)
+    if err != nil {
+        model.setError(err)
+        return model
+    }
     model.where       += " OR " + newWhere
     model.whereArgs    = append(model.whereArgs, newArgs...)
 	return model
@@ -183,6 +216,48 @@ func (md *Model) Batch(batch int) *Model {
     return model
 }
 
+// 链式操作，绑定上下文对象，当前链式操作的所有SQL都将使用该上下文执行，
+// 可用于在请求取消或者超时时中断正在执行的SQL操作。
+func (md *Model) Ctx(ctx context.Context) *Model {
+    model   := md.Clone()
+    model.db = md.db.Ctx(ctx)
+    if md.tx != nil {
+        model.tx = md.tx.Ctx(ctx)
+    }
+    return model
+}
+
+// 链式操作，强制查询操作在主库上执行，
+// 常用于写入后需要立即读取数据的场景，避免主从同步延迟带来的影响。
+func (md *Model) Master() *Model {
+    model         := md.Clone()
+    model.linkType = gLINK_TYPE_MASTER
+    return model
+}
+
+// 链式操作，查询操作在从库上执行(默认)
+func (md *Model) Slave() *Model {
+    model         := md.Clone()
+    model.linkType = gLINK_TYPE_SLAVE
+    return model
+}
+
+// 链式操作，设置Save操作的冲突检测字段(主键或者唯一索引字段)，多个字段以半角逗号连接。
+// 该设置用于PostgreSQL/SQLite的ON CONFLICT语句，MySQL会自动根据主键及唯一索引进行检测，因此会忽略该设置。
+func (md *Model) OnConflict(columns string) *Model {
+    model           := md.Clone()
+    model.onConflict = splitFields(columns)
+    return model
+}
+
+// 链式操作，设置Save操作在数据冲突时需要更新的字段，多个字段以半角逗号连接，
+// 默认情况下会更新所有写入的字段。
+func (md *Model) OnDuplicate(columns string) *Model {
+    model            := md.Clone()
+    model.onDuplicate = splitFields(columns)
+    return model
+}
+
 // 查询缓存/清除缓存操作，需要注意的是，事务查询不支持缓存。
 // 当time < 0时表示清除缓存， time=0时表示不过期, time > 0时表示过期时间，time过期时间单位：秒；
 // name表示自定义的缓存名称，便于业务层精准定位缓存项(如果业务层需要手动清理时，必须指定缓存名称)，
@@ -229,12 +304,12 @@ func (md *Model) Data(data ...interface{}) *Model {
                     case reflect.Array:
                         list := make(List, rv.Len())
                         for i := 0; i < rv.Len(); i++ {
-                            list[i] = gconv.Map(rv.Index(i).Interface())
+                            list[i] = dataToMap(rv.Index(i).Interface())
                         }
                         model.data = list
                     case reflect.Map:   fallthrough
                     case reflect.Struct:
-                        model.data = Map(gconv.Map(data[0]))
+                        model.data = Map(dataToMap(data[0]))
                     default:
                         model.data = data[0]
                 }
@@ -247,123 +322,95 @@ func (md *Model) Data(data ...interface{}) *Model {
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
 // 如果Data方法传递的是slice类型，那么为批量操作。
 func (md *Model) Insert() (result sql.Result, err error) {
-	defer func() {
-		if err == nil {
-			md.checkAndRemoveCache()
-		}
-	}()
-	if md.data == nil {
-		return nil, errors.New("inserting into table with empty data")
-	}
-	// 批量操作
-	if list, ok := md.data.(List); ok {
-		batch := 10
-		if md.batch > 0 {
-			batch = md.batch
-		}
-		if md.filter {
-		    for k, m := range list {
-                list[k] = md.db.filterFields(md.tables, m)
-            }
-        }
-		if md.tx == nil {
-			return md.db.BatchInsert(md.tables, list, batch)
-		} else {
-			return md.tx.BatchInsert(md.tables, list, batch)
-		}
-	} else if data, ok := md.data.(Map); ok {
-        if md.filter {
-            data = md.db.filterFields(md.tables, data)
-        }
-		if md.tx == nil {
-			return md.db.Insert(md.tables, data)
-		} else {
-			return md.tx.Insert(md.tables, data)
-		}
-	}
-	return nil, errors.New("inserting into table with invalid data type")
+	return md.doInsertWithOption(OPTION_INSERT)
 }
 
 // 链式操作， CURD - Replace/BatchReplace。
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
 // 如果Data方法传递的是slice类型，那么为批量操作。
 func (md *Model) Replace() (result sql.Result, err error) {
-	defer func() {
-		if err == nil {
-			md.checkAndRemoveCache()
-		}
-	}()
-	if md.data == nil {
-		return nil, errors.New("replacing into table with empty data")
-	}
-	// 批量操作
-	if list, ok := md.data.(List); ok {
-		batch := 10
-		if md.batch > 0 {
-			batch = md.batch
-		}
-        if md.filter {
-            for k, m := range list {
-                list[k] = md.db.filterFields(md.tables, m)
-            }
-        }
-		if md.tx == nil {
-			return md.db.BatchReplace(md.tables, list, batch)
-		} else {
-			return md.tx.BatchReplace(md.tables, list, batch)
-		}
-	} else if data, ok := md.data.(Map); ok {
-        if md.filter {
-            data = md.db.filterFields(md.tables, data)
-        }
-		if md.tx == nil {
-			return md.db.Replace(md.tables, data)
-		} else {
-			return md.tx.Replace(md.tables, data)
-		}
-	}
-	return nil, errors.New("replacing into table with invalid data type")
+	return md.doInsertWithOption(OPTION_REPLACE)
 }
 
 // 链式操作， CURD - Save/BatchSave。
 // 根据Data方法传递的参数类型决定该操作是单条操作还是批量操作，
 // 如果Data方法传递的是slice类型，那么为批量操作。
+// 冲突时更新的字段可通过OnDuplicate指定，PostgreSQL/SQLite需要通过OnConflict指定冲突检测字段。
 func (md *Model) Save() (result sql.Result, err error) {
+	return md.doInsertWithOption(OPTION_SAVE)
+}
+
+// 根据写入方式执行单条或者批量写入操作，写入前后分别执行BeforeInsert/AfterInsert钩子
+func (md *Model) doInsertWithOption(option int) (result sql.Result, err error) {
 	defer func() {
 		if err == nil {
 			md.checkAndRemoveCache()
 		}
 	}()
 	if md.data == nil {
-		return nil, errors.New("replacing into table with empty data")
+		return nil, errors.New("inserting into table with empty data")
+	}
+	model := md.Clone()
+	in    := &HookInput{Event : HOOK_BEFORE_INSERT, Data : md.data}
+	if err = model.callHooks(in); err != nil {
+		return nil, err
+	}
+	model.data = in.Data
+	if result, err = model.doInsertData(option); err == nil {
+		err = model.callHooks(&HookInput{Event : HOOK_AFTER_INSERT, Data : model.data, SqlResult : result})
+	}
+	return
+}
+
+// 执行单条或者批量写入操作(按照分片规则路由到对应的物理表)
+func (md *Model) doInsertData(option int) (result sql.Result, err error) {
+	insertOption            := newInsertOption(option, md.batch)
+	insertOption.onConflict  = md.onConflict
+	insertOption.onDuplicate = md.onDuplicate
+	link := (dbLink)(nil)
+	if md.tx != nil {
+		link = md.tx.tx
 	}
 	// 批量操作
 	if list, ok := md.data.(List); ok {
-		batch := gDEFAULT_BATCH_NUM
-		if md.batch > 0 {
-			batch = md.batch
+		// 按照分片对数据进行分组，分别写入对应的物理表
+		tables, groups, err := md.groupListByShard(list)
+		if err != nil {
+			return nil, err
 		}
-        if md.filter {
-            for k, m := range list {
-                list[k] = md.db.filterFields(md.tables, m)
-            }
-        }
-		if md.tx == nil {
-			return md.db.BatchSave(md.tables, list, batch)
-		} else {
-			return md.tx.BatchSave(md.tables, list, batch)
+		batchResult := new(batchSqlResult)
+		for _, table := range tables {
+			if md.filter {
+				for k, m := range groups[table] {
+					groups[table][k] = md.db.filterFields(table, m)
+				}
+			}
+			r, err := md.db.doBatchInsert(link, table, groups[table], insertOption)
+			if err != nil {
+				return r, err
+			}
+			if len(tables) == 1 {
+				return r, nil
+			}
+			if n, err := r.RowsAffected(); err != nil {
+				return r, err
+			} else {
+				batchResult.lastResult    = r
+				batchResult.rowsAffected += n
+			}
 		}
+		return batchResult, nil
 	} else if data, ok := md.data.(Map); ok {
-        if md.filter {
-            data = md.db.filterFields(md.tables, data)
-        }
-		if md.tx == nil {
-			return md.db.Save(md.tables, data)
-		} else {
-			return md.tx.Save(md.tables, data)
+		table, err := md.getShardTableByData(data)
+		if err != nil {
+			return nil, err
+		}
+		if md.filter {
+			data = md.db.filterFields(table, data)
 		}
+		return md.db.doInsert(link, table, data, insertOption)
 	}
-	return nil, errors.New("saving into table with invalid data type")
+	return nil, errors.New("inserting into table with invalid data type")
 }
 
 // 链式操作， CURD - Update
@@ -373,20 +420,41 @@ func (md *Model) Update() (result sql.Result, err error) {
 			md.checkAndRemoveCache()
 		}
 	}()
+	if md.err != nil {
+		return nil, md.err
+	}
 	if md.data == nil {
 		return nil, errors.New("updating table with empty data")
 	}
+	model := md.Clone()
+	in    := &HookInput{Event : HOOK_BEFORE_UPDATE, Data : md.data, Where : md.where, Args : md.whereArgs}
+	if err = model.callHooks(in); err != nil {
+		return nil, err
+	}
+	model.data, model.where, model.whereArgs = in.Data, in.Where, in.Args
+	if result, err = model.doUpdate(); err == nil {
+		err = model.callHooks(&HookInput{Event : HOOK_AFTER_UPDATE, Data : model.data, Where : model.where, Args : model.whereArgs, SqlResult : result})
+	}
+	return
+}
+
+// 执行数据更新操作
+func (md *Model) doUpdate() (result sql.Result, err error) {
+	table, err := md.getShardTableForWrite()
+	if err != nil {
+		return nil, err
+	}
     if md.filter {
         if data, ok := md.data.(Map); ok {
             if md.filter {
-                md.data = md.db.filterFields(md.tables, data)
+                md.data = md.db.filterFields(table, data)
             }
         }
     }
 	if md.tx == nil {
-		return md.db.Update(md.tables, md.data, md.where, md.whereArgs ...)
+		return md.db.Update(table, md.data, md.where, md.whereArgs ...)
 	} else {
-		return md.tx.Update(md.tables, md.data, md.where, md.whereArgs ...)
+		return md.tx.Update(table, md.data, md.where, md.whereArgs ...)
 	}
 }
 
@@ -397,10 +465,31 @@ func (md *Model) Delete() (result sql.Result, err error) {
 			md.checkAndRemoveCache()
 		}
 	}()
+	if md.err != nil {
+		return nil, md.err
+	}
+	model := md.Clone()
+	in    := &HookInput{Event : HOOK_BEFORE_DELETE, Where : md.where, Args : md.whereArgs}
+	if err = model.callHooks(in); err != nil {
+		return nil, err
+	}
+	model.where, model.whereArgs = in.Where, in.Args
+	if result, err = model.doDelete(); err == nil {
+		err = model.callHooks(&HookInput{Event : HOOK_AFTER_DELETE, Where : model.where, Args : model.whereArgs, SqlResult : result})
+	}
+	return
+}
+
+// 执行数据删除操作
+func (md *Model) doDelete() (result sql.Result, err error) {
+	table, err := md.getShardTableForWrite()
+	if err != nil {
+		return nil, err
+	}
 	if md.tx == nil {
-		return md.db.Delete(md.tables, md.where, md.whereArgs...)
+		return md.db.Delete(table, md.where, md.whereArgs...)
 	} else {
-		return md.tx.Delete(md.tables, md.where, md.whereArgs...)
+		return md.tx.Delete(table, md.where, md.whereArgs...)
 	}
 }
 
@@ -409,8 +498,32 @@ func (md *Model) Select() (Result, error) {
 	return md.All()
 }
 
-// 链式操作，查询所有记录
+// 链式操作，查询所有记录，查询完成后执行AfterQuery钩子
 func (md *Model) All() (Result, error) {
+	result, err := md.doAll()
+	if err != nil {
+		return nil, err
+	}
+	in := &HookInput{Event : HOOK_AFTER_QUERY, Where : md.where, Args : md.whereArgs, Result : result}
+	if err := md.callHooks(in); err != nil {
+		return nil, err
+	}
+	return in.Result, nil
+}
+
+// 执行查询操作，存在分片规则时根据查询条件路由到对应的分片，无法确定分片时在所有分片上查询并合并结果
+func (md *Model) doAll() (Result, error) {
+	tables, err := md.getShardTablesByWhere()
+	if err != nil {
+		return nil, err
+	}
+	switch len(tables) {
+		case 0:
+		case 1:
+			md = md.withShardTable(tables[0])
+		default:
+			return md.getAllFromShards(tables)
+	}
 	return md.getAll(md.getFormattedSql(), md.whereArgs...)
 }
 
@@ -438,18 +551,78 @@ func (md *Model) Value() (Value, error) {
 	return nil, nil
 }
 
-// 链式操作，查询单条记录，并自动转换为struct对象
+// 链式操作，查询单条记录，并自动转换为struct对象，设置With预加载时同时加载关联数据
 func (md *Model) Struct(obj interface{}) error {
 	one, err := md.One()
 	if err != nil {
 		return err
 	}
-	return one.ToStruct(obj)
+	if err := one.ToStruct(obj); err != nil {
+		return err
+	}
+	if one == nil || len(md.withs) == 0 {
+		return nil
+	}
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return md.loadWith([]reflect.Value{rv.Elem()}, Result{one})
+}
+
+// 链式操作，查询多条记录，并自动转换为struct对象数组，参数应当为struct数组的指针(例如: *[]User, *[]*User)，
+// 设置With预加载时同时批量加载关联数据。
+func (md *Model) Structs(objPointerSlice interface{}) error {
+	rv := reflect.ValueOf(objPointerSlice)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("the parameter should be a pointer of struct slice, like: *[]User, *[]*User")
+	}
+	result, err := md.All()
+	if err != nil {
+		return err
+	}
+	sliceType := rv.Elem().Type()
+	elemType  := sliceType.Elem()
+	isPointer := elemType.Kind() == reflect.Ptr
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+	items := make([]reflect.Value, len(result))
+	for i, record := range result {
+		items[i] = reflect.New(elemType).Elem()
+		if err := record.ToStruct(items[i].Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	if err := md.loadWith(items, result); err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(sliceType, len(items), len(items))
+	for i, item := range items {
+		if isPointer {
+			slice.Index(i).Set(item.Addr())
+		} else {
+			slice.Index(i).Set(item)
+		}
+	}
+	rv.Elem().Set(slice)
+	return nil
 }
 
 // 链式操作，查询数量，fields可以为空，也可以自定义查询字段，
 // 当给定自定义查询字段时，该字段必须为数量结果，否则会引起歧义，使用如：md.Fields("COUNT(id)")
 func (md *Model) Count() (int, error) {
+	tables, err := md.getShardTablesByWhere()
+	if err != nil {
+		return 0, err
+	}
+	switch len(tables) {
+		case 0:
+		case 1:
+			md = md.withShardTable(tables[0])
+		default:
+			return md.countFromShards(tables)
+	}
     defer func(fields string) {
         md.fields = fields
     }(md.fields)
@@ -476,39 +649,52 @@ func (md *Model) Count() (int, error) {
 
 // 查询操作，对底层SQL操作的封装
 func (md *Model) getAll(query string, args ...interface{}) (result Result, err error) {
+	if md.err != nil {
+		return nil, md.err
+	}
 	cacheKey := ""
 	// 查询缓存查询处理
 	if md.cacheEnabled {
 		cacheKey = md.cacheName
 		if len(cacheKey) == 0 {
-			cacheKey = query + "/" + gconv.String(args)
+			cacheKey = makeQueryCacheKey(query, args)
 		}
-		if v := md.db.getCache().Get(cacheKey); v != nil {
-			return v.(Result), nil
+		if md.cacheTime >= 0 {
+			if v, ok := md.db.getQueryCache().Get(cacheKey); ok {
+				return copyResult(v), nil
+			}
 		}
 	}
 
-	if md.tx == nil {
-		result, err = md.db.GetAll(query, args...)
-	} else {
+	if md.tx != nil {
 		result, err = md.tx.GetAll(query, args...)
+	} else if md.linkType == gLINK_TYPE_MASTER {
+		link := (dbLink)(nil)
+		if link, err = md.db.Master(); err != nil {
+			return nil, err
+		}
+		result, err = md.db.doGetAll(link, query, args...)
+	} else {
+		result, err = md.db.GetAll(query, args...)
 	}
 	// 查询缓存保存处理
 	if len(cacheKey) > 0 && err == nil {
 		if md.cacheTime < 0 {
-			md.db.getCache().Remove(cacheKey)
+			md.db.getQueryCache().Remove(cacheKey)
 		} else {
-			md.db.getCache().Set(cacheKey, result, md.cacheTime*1000)
+			md.db.getQueryCache().Set(cacheKey, copyResult(result), md.cacheTime*1000, getTablesFromModel(md)...)
 		}
 	}
 	return result, err
 }
 
 // 检查是否需要查询查询缓存
+// 写入操作成功后，同时会清除当前操作数据表(逻辑表)相关的所有查询缓存。
 func (md *Model) checkAndRemoveCache() {
 	if md.cacheEnabled && md.cacheTime < 0 && len(md.cacheName) > 0 {
-		md.db.getCache().Remove(md.cacheName)
+		md.db.getQueryCache().Remove(md.cacheName)
 	}
+	md.db.ClearCacheByTable(md.tablesInit)
 }
 
 // 格式化当前输入参数，返回可执行的SQL语句（不带参数）
@@ -516,7 +702,8 @@ func (md *Model) getFormattedSql() string {
 	if md.fields == "" {
 		md.fields = "*"
 	}
-	s := fmt.Sprintf("SELECT %s FROM %s", md.fields, md.tables)
+	charL, charR := md.db.getChars()
+	s := fmt.Sprintf("SELECT %s FROM %s", md.fields, quoteWord(charL, charR, md.tables))
 	if md.where != "" {
 		s += " WHERE " + md.where
 	}
@@ -10,12 +10,15 @@
     1.需要导入sqlserver驱动： github.com/denisenkom/go-mssqldb
     2.不支持save/replace方法
     3.不支持LastInsertId方法
+    4.LIMIT语法转换需要SQL Server 2012及以上版本
 */
 package gdb
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/gogf/gf/g/container/gvar"
 	"github.com/gogf/gf/g/text/gregex"
 	"strconv"
 	"strings"
@@ -42,111 +45,53 @@ func (db *dbMssql) Open(config *ConfigNode) (*sql.DB, error) {
 	}
 }
 
-// 获得关键字操作符
+// 获得关键字操作符(使用方括号包裹标识符，不依赖QUOTED_IDENTIFIER设置)
 func (db *dbMssql) getChars() (charLeft string, charRight string) {
-	return "\"", "\""
+	return "[", "]"
 }
 
-// 在执行sql之前对sql进行进一步处理
+// 在执行sql之前对sql进行进一步处理，将'?'占位符转换为@pN占位符，并转换LIMIT语法
 func (db *dbMssql) handleSqlBeforeExec(query string) string {
-	index := 0
-	str, _ := gregex.ReplaceStringFunc("\\?", query, func(s string) string {
-		index++
+	query = formatPlaceholder(query, func(index int) string {
 		return fmt.Sprintf("@p%d", index)
 	})
-
-	str, _ = gregex.ReplaceString("\"", "", str)
-
-	return db.parseSql(str)
+	return db.parseSql(query)
 }
 
-//将MYSQL的SQL语法转换为MSSQL的语法
-//1.由于mssql不支持limit写法所以需要对mysql中的limit用法做转换
+// 将MYSQL的SQL语法转换为MSSQL的语法
+// 由于mssql不支持limit写法，这里将LIMIT m, n及LIMIT n转换为OFFSET m ROWS FETCH NEXT n ROWS ONLY写法(需要SQL Server 2012及以上版本)，
+// 该写法必须包含ORDER BY语句，没有排序时使用ORDER BY (SELECT NULL)。
 func (db *dbMssql) parseSql(sql string) string {
-	//下面的正则表达式匹配出SELECT和INSERT的关键字后分别做不同的处理，如有LIMIT则将LIMIT的关键字也匹配出
-	patten := `^\s*(?i)(SELECT)|(LIMIT\s*(\d+)\s*,\s*(\d+))`
-	if gregex.IsMatchString(patten, sql) == false {
-		fmt.Println("not matched..")
+	match, _ := gregex.MatchString(`(?is)^(\s*SELECT\s.+?)\s+LIMIT\s+(\d+)\s*(,\s*(\d+))?\s*$`, sql)
+	if len(match) == 0 {
 		return sql
 	}
-
-	res, err := gregex.MatchAllString(patten, sql)
-	if err != nil {
-		fmt.Println("MatchString error.", err)
-		return ""
+	first, limit := 0, 0
+	if match[4] != "" {
+		first, _ = strconv.Atoi(match[2])
+		limit, _ = strconv.Atoi(match[4])
+	} else {
+		limit, _ = strconv.Atoi(match[2])
 	}
+	sql = match[1]
+	if !gregex.IsMatchString(`(?i)\sORDER\s+BY\s`, sql) {
+		sql += " ORDER BY (SELECT NULL)"
+	}
+	return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", sql, first, limit)
+}
 
-	index := 0
-	keyword := strings.TrimSpace(res[index][0])
-	keyword = strings.ToUpper(keyword)
-
-	index++
-	switch keyword {
-	case "SELECT":
-		//不含LIMIT关键字则不处理
-		if len(res) < 2 || (strings.HasPrefix(res[index][0], "LIMIT") == false && strings.HasPrefix(res[index][0], "limit") == false) {
-			break
-		}
-
-		//不含LIMIT则不处理
-		if gregex.IsMatchString("((?i)SELECT)(.+)((?i)LIMIT)", sql) == false {
-			break
-		}
-
-		//判断SQL中是否含有order by
-		selectStr := ""
-		orderbyStr := ""
-		haveOrderby := gregex.IsMatchString("((?i)SELECT)(.+)((?i)ORDER BY)", sql)
-		if haveOrderby {
-			//取order by 前面的字符串
-			queryExpr, _ := gregex.MatchString("((?i)SELECT)(.+)((?i)ORDER BY)", sql)
-
-			if len(queryExpr) != 4 || strings.EqualFold(queryExpr[1], "SELECT") == false || strings.EqualFold(queryExpr[3], "ORDER BY") == false {
-				break
-			}
-			selectStr = queryExpr[2]
-
-			//取order by表达式的值
-			orderbyExpr, _ := gregex.MatchString("((?i)ORDER BY)(.+)((?i)LIMIT)", sql)
-			if len(orderbyExpr) != 4 || strings.EqualFold(orderbyExpr[1], "ORDER BY") == false || strings.EqualFold(orderbyExpr[3], "LIMIT") == false {
-				break
-			}
-			orderbyStr = orderbyExpr[2]
-		} else {
-			queryExpr, _ := gregex.MatchString("((?i)SELECT)(.+)((?i)LIMIT)", sql)
-			if len(queryExpr) != 4 || strings.EqualFold(queryExpr[1], "SELECT") == false || strings.EqualFold(queryExpr[3], "LIMIT") == false {
-				break
-			}
-			selectStr = queryExpr[2]
-		}
-
-		//取limit后面的取值范围
-		first, limit := 0, 0
-		for i := 1; i < len(res[index]); i++ {
-			if len(strings.TrimSpace(res[index][i])) == 0 {
-				continue
-			}
-
-			if strings.HasPrefix(res[index][i], "LIMIT") || strings.HasPrefix(res[index][i], "limit") {
-				first, _ = strconv.Atoi(res[index][i+1])
-				limit, _ = strconv.Atoi(res[index][i+2])
-				break
-			}
-		}
-
-		if haveOrderby {
-			sql = fmt.Sprintf("SELECT * FROM (SELECT ROW_NUMBER() OVER (ORDER BY %s) as ROWNUMBER_, %s   ) as TMP_ WHERE TMP_.ROWNUMBER_ > %d AND TMP_.ROWNUMBER_ <= %d", orderbyStr, selectStr, first, limit)
-		} else {
-			if first == 0 {
-				first = limit
-			} else {
-				first = limit - first
-			}
-			sql = fmt.Sprintf("SELECT * FROM (SELECT TOP %d * FROM (SELECT TOP %d %s) as TMP1_ ) as TMP2_ ", first, limit, selectStr)
-		}
-	default:
+// SQL Server单条INSERT语句最多支持1000行VALUES数据，并且最多支持2100个参数
+func (db *dbMssql) getMaxBatchNum(columns int) int {
+	max := 1000
+	if columns > 0 && 2100/columns < max {
+		max = 2100 / columns
 	}
-	return sql
+	return max
+}
+
+// 不支持MySQL的ON DUPLICATE KEY UPDATE语法，Save操作请使用MERGE语句自行实现
+func (db *dbMssql) formatUpsert(columns []string, option *insertOption) (string, error) {
+	return "", errors.New("save operation is not supported by mssql")
 }
 
 // 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值暂无用途(默认为字段数据类型).
@@ -175,3 +120,84 @@ func (db *dbMssql) getTableFields(table string) (fields map[string]string, err e
 	}
 	return
 }
+
+// 获取当前数据库的所有数据表名称(名称与getTableFields的字段名称处理保持一致，统一转换为小写)
+func (db *dbMssql) Tables() (tables []string, err error) {
+	result, err := db.GetAll(`SELECT name FROM sys.tables ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	tables = make([]string, len(result))
+	for i, m := range result {
+		tables[i] = strings.ToLower(m["name"].String())
+	}
+	return tables, nil
+}
+
+// 获取指定数据表的字段详细信息，字段注释读取自扩展属性MS_Description
+func (db *dbMssql) TableFields(table string) (fields []*TableField, err error) {
+	result, err := db.GetAll(`SELECT c.name AS FIELD, t.name AS TYPE, c.max_length AS LENGTH, c.precision AS PREC, c.scale AS SCALE,
+		c.is_nullable AS NULLABLE, c.is_identity AS IDENTITY, OBJECT_DEFINITION(c.default_object_id) AS DEFAULT_VALUE,
+		CAST(ep.value AS NVARCHAR(4000)) AS COMMENT
+		FROM sys.columns c
+		JOIN sys.types t ON t.user_type_id = c.user_type_id
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+		WHERE c.object_id = OBJECT_ID(?) ORDER BY c.column_id`, table)
+	if err != nil {
+		return nil, err
+	}
+	fields = make([]*TableField, len(result))
+	for i, m := range result {
+		fieldType := strings.ToLower(m["TYPE"].String())
+		switch fieldType {
+		case "decimal", "numeric":
+			fieldType += fmt.Sprintf("(%d,%d)", m["PREC"].Int(), m["SCALE"].Int())
+		case "char", "varchar", "binary", "varbinary", "nchar", "nvarchar":
+			length := m["LENGTH"].Int()
+			if length < 0 {
+				fieldType += "(max)"
+			} else {
+				if fieldType[0] == 'n' {
+					length /= 2
+				}
+				fieldType += fmt.Sprintf("(%d)", length)
+			}
+		}
+		fields[i] = &TableField {
+			Index   : i,
+			Name    : strings.ToLower(m["FIELD"].String()),
+			Type    : fieldType,
+			Null    : m["NULLABLE"].Bool(),
+			Default : m["DEFAULT_VALUE"].Val(),
+			Comment : m["COMMENT"].String(),
+		}
+		if m["IDENTITY"].Bool() {
+			fields[i].Extra = "auto_increment"
+		}
+	}
+	indexes, err := db.TableIndexes(table)
+	if err != nil {
+		return nil, err
+	}
+	fillTableFieldKeys(fields, indexes)
+	return fields, nil
+}
+
+// 获取指定数据表的索引信息(不包含INCLUDE字段)
+func (db *dbMssql) TableIndexes(table string) (indexes []*TableIndex, err error) {
+	result, err := db.GetAll(`SELECT i.name AS INDEX_NAME, c.name AS COLUMN_NAME, i.is_unique AS IS_UNIQUE, i.is_primary_key AS IS_PRIMARY
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(?) AND ic.is_included_column = 0
+		ORDER BY i.index_id, ic.key_ordinal`, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range result {
+		m["COLUMN_NAME"] = gvar.New(strings.ToLower(m["COLUMN_NAME"].String()), true)
+	}
+	return groupTableIndexes(result, "INDEX_NAME", "COLUMN_NAME", func(m Record) (bool, bool) {
+		return m["IS_UNIQUE"].Bool(), m["IS_PRIMARY"].Bool()
+	}), nil
+}
@@ -10,6 +10,7 @@ package gdb
 import (
     "fmt"
     "database/sql"
+    "strings"
 )
 
 // 数据库链接对象
@@ -41,4 +42,18 @@ func (db *dbMysql) getChars () (charLeft string, charRight string) {
 // 在执行sql之前对sql进行进一步处理
 func (db *dbMysql) handleSqlBeforeExec(query string) string {
     return query
-}
\ No newline at end of file
+}
+// 字段类型转换，MySQL的BIT类型返回的是大端序的二进制数据(例如b'101'为0x05)，这里转换为整型，
+// 其他类型按照默认规则进行转换。
+func (db *dbMysql) convertValue(fieldValue interface{}, fieldType string) interface{} {
+    if strings.EqualFold(fieldType, "BIT") {
+        if b, ok := fieldValue.([]byte); ok {
+            v := int64(0)
+            for _, c := range b {
+                v = v << 8 | int64(c)
+            }
+            return v
+        }
+    }
+    return db.dbBase.convertValue(fieldValue, fieldType)
+}
@@ -14,8 +14,11 @@
 package gdb
 
 import (
+	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/gogf/gf/g/container/gvar"
 	"github.com/gogf/gf/g/text/gregex"
 	"strconv"
 	"strings"
@@ -41,107 +44,64 @@ func (db *dbOracle) Open(config *ConfigNode) (*sql.DB, error) {
 	}
 }
 
-// 获得关键字操作符
+// 获得关键字操作符(ORACLE中使用双引号包裹的标识符区分大小写，这里不对标识符进行包裹，由数据库统一转换为大写)
 func (db *dbOracle) getChars() (charLeft string, charRight string) {
-	return "\"", "\""
+	return "", ""
 }
 
-// 在执行sql之前对sql进行进一步处理
+// 在执行sql之前对sql进行进一步处理，将'?'占位符转换为:N占位符，并转换LIMIT及批量写入语法
 func (db *dbOracle) handleSqlBeforeExec(query string) string {
-	index := 0
-	str, _ := gregex.ReplaceStringFunc("\\?", query, func(s string) string {
-		index++
+	query = formatPlaceholder(query, func(index int) string {
 		return fmt.Sprintf(":%d", index)
 	})
-
-	str, _ = gregex.ReplaceString("\"", "", str)
-
-	return db.parseSql(str)
+	return db.parseSql(query)
 }
 
-//由于ORACLE中对LIMIT和批量插入的语法与MYSQL不一致，所以这里需要对LIMIT和批量插入做语法上的转换
+// 由于ORACLE中对LIMIT和批量插入的语法与MYSQL不一致，所以这里需要对LIMIT和批量插入做语法上的转换:
+// 1. LIMIT m, n(或者LIMIT n)转换为ROWNUM分页查询；
+// 2. INSERT INTO t(...) VALUES(...),(...)转换为INSERT ALL INTO t(...) VALUES(...) INTO t(...) VALUES(...) SELECT 1 FROM DUAL；
 func (db *dbOracle) parseSql(sql string) string {
-	//下面的正则表达式匹配出SELECT和INSERT的关键字后分别做不同的处理，如有LIMIT则将LIMIT的关键字也匹配出
-	patten := `^\s*(?i)(SELECT)|(INSERT)|(LIMIT\s*(\d+)\s*,\s*(\d+))`
-	if gregex.IsMatchString(patten, sql) == false {
-		fmt.Println("not matched..")
-		return sql
-	}
-
-	res, err := gregex.MatchAllString(patten, sql)
-	if err != nil {
-		fmt.Println("MatchString error.", err)
-		return ""
-	}
-
-	index := 0
-	keyword := strings.TrimSpace(res[index][0])
-	keyword = strings.ToUpper(keyword)
-
-	index++
-	switch keyword {
-	case "SELECT":
-		//不含LIMIT关键字则不处理
-		if len(res) < 2 || (strings.HasPrefix(res[index][0], "LIMIT") == false && strings.HasPrefix(res[index][0], "limit") == false) {
-			break
-		}
-
-		//取limit前面的字符串
-		if gregex.IsMatchString("((?i)SELECT)(.+)((?i)LIMIT)", sql) == false {
-			break
-		}
-
-		queryExpr, _ := gregex.MatchString("((?i)SELECT)(.+)((?i)LIMIT)", sql)
-		if len(queryExpr) != 4 || strings.EqualFold(queryExpr[1], "SELECT") == false || strings.EqualFold(queryExpr[3], "LIMIT") == false {
-			break
-		}
-
-		//取limit后面的取值范围
+	if match, _ := gregex.MatchString(`(?is)^(\s*SELECT\s.+?)\s+LIMIT\s+(\d+)\s*(,\s*(\d+))?\s*$`, sql); len(match) > 0 {
 		first, limit := 0, 0
-		for i := 1; i < len(res[index]); i++ {
-			if len(strings.TrimSpace(res[index][i])) == 0 {
-				continue
-			}
-
-			if strings.HasPrefix(res[index][i], "LIMIT") || strings.HasPrefix(res[index][i], "limit") {
-				first, _ = strconv.Atoi(res[index][i+1])
-				limit, _ = strconv.Atoi(res[index][i+2])
-				break
-			}
-		}
-
-		//也可以使用between,据说这种写法的性能会比between好点,里层SQL中的ROWNUM_ >= limit可以缩小查询后的数据集规模
-		sql = fmt.Sprintf("SELECT * FROM (SELECT GFORM.*, ROWNUM ROWNUM_ FROM (%s %s) GFORM WHERE ROWNUM <= %d) WHERE ROWNUM_ >= %d", queryExpr[1], queryExpr[2], limit, first)
-	case "INSERT":
-		//获取VALUE的值，匹配所有带括号的值,会将INSERT INTO后的值匹配到，所以下面的判断语句会判断数组长度是否小于3
-		valueExpr, err := gregex.MatchAllString(`(\s*\(([^\(\)]*)\))`, sql)
-		if err != nil {
-			return sql
-		}
-
-		//判断VALUE后的值是否有多个，只有在批量插入的时候才需要做转换，如只有1个VALUE则不需要做转换
-		if len(valueExpr) < 3 {
-			break
+		if match[4] != "" {
+			first, _ = strconv.Atoi(match[2])
+			limit, _ = strconv.Atoi(match[4])
+		} else {
+			limit, _ = strconv.Atoi(match[2])
 		}
-
-		//获取INTO后面的值
-		tableExpr, err := gregex.MatchString(`(?i)\s*(INTO\s+\w+\(([^\(\)]*)\))`, sql)
-		if err != nil {
+		// 里层SQL中的ROWNUM <= first + limit可以缩小查询后的数据集规模
+		return fmt.Sprintf("SELECT * FROM (SELECT GFORM.*, ROWNUM ROWNUM_ FROM (%s) GFORM WHERE ROWNUM <= %d) WHERE ROWNUM_ > %d",
+			strings.TrimSpace(match[1]), first+limit, first)
+	}
+	if match, _ := gregex.MatchString(`(?is)^\s*INSERT\s+INTO\s+([\w\.]+)\s*(\([^\(\)]*\))\s*VALUES\s*(.+?)\s*$`, sql); len(match) > 0 {
+		// 只有在批量插入(多个VALUE)的时候才需要做转换
+		values, _ := gregex.MatchAllString(`\([^\(\)]*\)`, match[3])
+		if len(values) < 2 {
 			return sql
 		}
-		tableExpr[0] = strings.TrimSpace(tableExpr[0])
-
-		sql = "INSERT ALL"
-		for i := 1; i < len(valueExpr); i++ {
-			sql += fmt.Sprintf(" %s VALUES%s", tableExpr[0], strings.TrimSpace(valueExpr[i][0]))
+		buffer := bytes.NewBufferString("INSERT ALL")
+		for _, v := range values {
+			buffer.WriteString(fmt.Sprintf(" INTO %s%s VALUES%s", match[1], match[2], v[0]))
 		}
-		sql += " SELECT 1 FROM DUAL"
-
-	default:
+		buffer.WriteString(" SELECT 1 FROM DUAL")
+		return buffer.String()
 	}
 	return sql
 }
 
+// ORACLE的INSERT ALL语句最多支持1000个字段(所有INTO子句的字段总和)
+func (db *dbOracle) getMaxBatchNum(columns int) int {
+	if columns > 0 && columns < 1000 {
+		return 1000 / columns
+	}
+	return 1
+}
+
+// 不支持MySQL的ON DUPLICATE KEY UPDATE语法，Save操作请使用MERGE语句自行实现
+func (db *dbOracle) formatUpsert(columns []string, option *insertOption) (string, error) {
+	return "", errors.New("save operation is not supported by oracle")
+}
+
 // 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值暂无用途(默认为字段数据类型).
 func (db *dbOracle) getTableFields(table string) (fields map[string]string, err error) {
 	// 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
@@ -168,3 +128,76 @@ func (db *dbOracle) getTableFields(table string) (fields map[string]string, err
 	}
 	return
 }
+
+// 获取当前用户的所有数据表名称(ORACLE返回的名称默认都是大写的，统一转换为小写)
+func (db *dbOracle) Tables() (tables []string, err error) {
+	result, err := db.GetAll(`SELECT TABLE_NAME FROM USER_TABLES ORDER BY TABLE_NAME`)
+	if err != nil {
+		return nil, err
+	}
+	tables = make([]string, len(result))
+	for i, m := range result {
+		tables[i] = strings.ToLower(m["TABLE_NAME"].String())
+	}
+	return tables, nil
+}
+
+// 获取指定数据表的字段详细信息，字段注释读取自USER_COL_COMMENTS
+func (db *dbOracle) TableFields(table string) (fields []*TableField, err error) {
+	result, err := db.GetAll(`SELECT c.COLUMN_NAME, c.DATA_TYPE, c.DATA_LENGTH, c.DATA_PRECISION, c.DATA_SCALE,
+		c.NULLABLE, c.DATA_DEFAULT, m.COMMENTS
+		FROM USER_TAB_COLUMNS c
+		LEFT JOIN USER_COL_COMMENTS m ON m.TABLE_NAME = c.TABLE_NAME AND m.COLUMN_NAME = c.COLUMN_NAME
+		WHERE c.TABLE_NAME = ? ORDER BY c.COLUMN_ID`, strings.ToUpper(table))
+	if err != nil {
+		return nil, err
+	}
+	fields = make([]*TableField, len(result))
+	for i, m := range result {
+		fieldType := strings.ToLower(m["DATA_TYPE"].String())
+		switch fieldType {
+		case "number", "float":
+			if m["DATA_PRECISION"].String() != "" {
+				fieldType += fmt.Sprintf("(%d,%d)", m["DATA_PRECISION"].Int(), m["DATA_SCALE"].Int())
+			}
+		case "date", "clob", "blob", "nclob", "long":
+		default:
+			if !strings.HasPrefix(fieldType, "timestamp") {
+				fieldType += fmt.Sprintf("(%d)", m["DATA_LENGTH"].Int())
+			}
+		}
+		fields[i] = &TableField {
+			Index   : i,
+			Name    : strings.ToLower(m["COLUMN_NAME"].String()),
+			Type    : fieldType,
+			Null    : m["NULLABLE"].String() == "Y",
+			Default : m["DATA_DEFAULT"].Val(),
+			Comment : m["COMMENTS"].String(),
+		}
+	}
+	indexes, err := db.TableIndexes(table)
+	if err != nil {
+		return nil, err
+	}
+	fillTableFieldKeys(fields, indexes)
+	return fields, nil
+}
+
+// 获取指定数据表的索引信息
+func (db *dbOracle) TableIndexes(table string) (indexes []*TableIndex, err error) {
+	result, err := db.GetAll(`SELECT i.INDEX_NAME, ic.COLUMN_NAME, i.UNIQUENESS, c.CONSTRAINT_TYPE
+		FROM USER_INDEXES i
+		JOIN USER_IND_COLUMNS ic ON ic.INDEX_NAME = i.INDEX_NAME
+		LEFT JOIN USER_CONSTRAINTS c ON c.INDEX_NAME = i.INDEX_NAME AND c.CONSTRAINT_TYPE = 'P'
+		WHERE i.TABLE_NAME = ? ORDER BY i.INDEX_NAME, ic.COLUMN_POSITION`, strings.ToUpper(table))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range result {
+		m["INDEX_NAME"]  = gvar.New(strings.ToLower(m["INDEX_NAME"].String()), true)
+		m["COLUMN_NAME"] = gvar.New(strings.ToLower(m["COLUMN_NAME"].String()), true)
+	}
+	return groupTableIndexes(result, "INDEX_NAME", "COLUMN_NAME", func(m Record) (bool, bool) {
+		return m["UNIQUENESS"].String() == "UNIQUE", m["CONSTRAINT_TYPE"].String() == "P"
+	}), nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "errors"
+    "reflect"
+    "strings"
+)
+
+// 分页查询结果信息，属性与gpage.New的参数对应，可直接用于生成分页HTML内容：
+// gpage.New(p.TotalSize, p.PageSize, p.CurrentPage, url) 或者 gpage.NewFromPagination(p, url)
+type Pagination struct {
+    CurrentPage int  // 当前页码
+    PageSize    int  // 每页数量
+    TotalSize   int  // 总记录数(跳过数量统计时为-1)
+    TotalPage   int  // 总页数(跳过数量统计时为-1)
+    HasMore     bool // 是否存在下一页
+}
+
+// 获取分页信息，用于根据分页查询结果生成分页对象(实现gpage.Pagination接口)
+func (p *Pagination) PageInfo() (totalSize, pageSize, currentPage int, hasMore bool) {
+    return p.TotalSize, p.PageSize, p.CurrentPage, p.HasMore
+}
+
+// 链式操作，分页查询，page为页码(从1开始)，size为每页数量，
+// 配合ScanAndCount使用时将同时查询当前页数据及总记录数
+func (md *Model) Page(page, size int) *Model {
+    if page < 1 {
+        page = 1
+    }
+    return md.ForPage(page, size)
+}
+
+// 链式操作，分页查询时跳过总记录数的统计，常用于无限滚动加载等不需要总页数的场景，
+// 跳过统计时将多查询一条记录用于判断是否存在下一页
+func (md *Model) SkipCount() *Model {
+    model          := md.Clone()
+    model.skipCount = true
+    return model
+}
+
+// 执行分页查询，将当前页数据写入pointer(struct数组的指针，例如: *[]User, *[]*User，也可以为*Result)，
+// 并将总记录数写入total(可以为nil，跳过统计时写入-1)，返回分页信息。
+// 当前页数据不足一页时直接根据记录数计算总数，不再执行COUNT查询。
+func (md *Model) ScanAndCount(pointer interface{}, total *int) (*Pagination, error) {
+    if md.limit <= 0 {
+        return nil, errors.New("page size should be specified by Page before ScanAndCount")
+    }
+    rv := reflect.ValueOf(pointer)
+    if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+        return nil, errors.New("the parameter should be a pointer of struct slice, like: *[]User, *[]*User")
+    }
+    pagination := &Pagination {
+        CurrentPage : md.start / md.limit + 1,
+        PageSize    : md.limit,
+        TotalSize   : -1,
+        TotalPage   : -1,
+    }
+    // 跳过统计时多查询一条记录，用于判断是否存在下一页
+    model := md
+    if md.skipCount {
+        model       = md.Clone()
+        model.limit = md.limit + 1
+    }
+    if result, ok := pointer.(*Result); ok {
+        all, err := model.All()
+        if err != nil {
+            return nil, err
+        }
+        *result = all
+    } else if err := model.Structs(pointer); err != nil {
+        return nil, err
+    }
+    count := rv.Elem().Len()
+    if md.skipCount {
+        if count > md.limit {
+            count = md.limit
+            rv.Elem().Set(rv.Elem().Slice(0, count))
+            pagination.HasMore = true
+        }
+        if total != nil {
+            *total = -1
+        }
+        return pagination, nil
+    }
+    // 当前页存在数据并且不足一页时，可以直接计算出总记录数
+    if count > 0 && count < md.limit {
+        pagination.TotalSize = md.start + count
+    } else {
+        n, err := md.countModel().Count()
+        if err != nil {
+            return nil, err
+        }
+        pagination.TotalSize = n
+    }
+    pagination.TotalPage = (pagination.TotalSize + md.limit - 1) / md.limit
+    pagination.HasMore   = md.start + count < pagination.TotalSize
+    if total != nil {
+        *total = pagination.TotalSize
+    }
+    return pagination, nil
+}
+
+// 生成用于统计总记录数的查询对象，去掉分页、排序及关联预加载设置，查询字段非DISTINCT时使用COUNT(1)；
+// 存在GROUP BY时Count会将分组查询包装为子查询(SELECT COUNT(1) FROM (...))，统计的是分组数量而不是记录数量
+func (md *Model) countModel() *Model {
+    model        := md.Clone()
+    model.start   = 0
+    model.limit   = 0
+    model.orderBy = ""
+    model.withs   = nil
+    if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(model.fields)), "DISTINCT") {
+        model.fields = "*"
+    }
+    return model
+}
@@ -8,21 +8,43 @@
 package gdb
 
 import (
-    "fmt"
-    "regexp"
+    "bytes"
+    "context"
     "database/sql"
+    "encoding/json"
+    "fmt"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gconv"
+    "reflect"
+    "strings"
 )
 
 // PostgreSQL的适配.
 // 使用时需要import:
-// _ "github.com/gogf/gf/third/github.com/lib/pq"
-// @todo 需要完善replace和save的操作覆盖
+// _ "github.com/lib/pq"
+// @todo 需要完善replace操作的覆盖
 
 // 数据库链接对象
 type dbPgsql struct {
     *dbBase
 }
 
+// 写入操作的执行结果(通过RETURNING子句获取写入记录的主键值)
+type pgsqlResult struct {
+    lastInsertId int64
+    rowsAffected int64
+}
+
+// see sql.Result.LastInsertId
+func (r *pgsqlResult) LastInsertId() (int64, error) {
+    return r.lastInsertId, nil
+}
+
+// see sql.Result.RowsAffected
+func (r *pgsqlResult) RowsAffected() (int64, error) {
+    return r.rowsAffected, nil
+}
+
 // 创建SQL操作对象，内部采用了lazy link处理
 func (db *dbPgsql) Open (config *ConfigNode) (*sql.DB, error) {
     var source string
@@ -43,13 +65,310 @@ func (db *dbPgsql) getChars () (charLeft string, charRight string) {
     return "\"", "\""
 }
 
-// 在执行sql之前对sql进行进一步处理
+// 在执行sql之前对sql进行进一步处理：
+// 1. 将'?'占位符转换为PostgreSQL的$n占位符(忽略字符串常量及引用标识符中的'?'字符)；
+// 2. 将语句末尾MySQL风格的LIMIT m, n语法转换为LIMIT n OFFSET m语法(不处理字符串常量等其他位置的内容)；
 func (db *dbPgsql) handleSqlBeforeExec(query string) string {
-    reg   := regexp.MustCompile("\\?")
-    index := 0
-    str   := reg.ReplaceAllStringFunc(query, func (s string) string {
-        index ++
+    query = formatPlaceholder(query, func(index int) string {
         return fmt.Sprintf("$%d", index)
     })
-    return str
-}
\ No newline at end of file
+    query, _ = gregex.ReplaceString(`(?i)\sLIMIT\s+(\d+)\s*,\s*(\d+)\s*;?\s*$`, ` LIMIT $2 OFFSET $1`, query)
+    return query
+}
+
+// Save操作时使用ON CONFLICT DO UPDATE语法，需要通过OnConflict指定冲突检测字段
+func (db *dbPgsql) formatUpsert(columns []string, option *insertOption) (string, error) {
+    charL, charR := db.getChars()
+    return formatOnConflictUpsert(charL, charR, columns, option)
+}
+
+// 执行一条sql，由于PostgreSQL驱动不支持LastInsertId，
+// 当执行INSERT语句且数据表存在单一主键时，自动增加RETURNING子句获取写入记录的主键值(批量写入时为最后一条记录)，
+// 此时影响行数为返回的记录数量。拦截器及日志中仍然为Exec操作。
+func (db *dbPgsql) doExec(link dbLink, query string, args ...interface{}) (result sql.Result, err error) {
+    return db.doExecWith(link, func(ctx context.Context, query string, args []interface{}) (sql.Result, error) {
+        match, _ := gregex.MatchString(`(?i)^\s*INSERT\s+INTO\s+([^\s\(]+)`, query)
+        if len(match) < 2 || gregex.IsMatchString(`(?i)\sRETURNING\s`, query) {
+            return link.ExecContext(ctx, query, args...)
+        }
+        primaryKey := db.getPrimaryKey(strings.Replace(match[1], `"`, "", -1))
+        if primaryKey == "" {
+            return link.ExecContext(ctx, query, args...)
+        }
+        rows, err := link.QueryContext(ctx, fmt.Sprintf(`%s RETURNING "%s"`, strings.TrimSpace(query), primaryKey), args...)
+        if err != nil {
+            return nil, err
+        }
+        defer rows.Close()
+        r := new(pgsqlResult)
+        for rows.Next() {
+            var id interface{}
+            if err := rows.Scan(&id); err != nil {
+                return nil, err
+            }
+            r.lastInsertId  = gconv.Int64(id)
+            r.rowsAffected += 1
+        }
+        return r, rows.Err()
+    }, query, args...)
+}
+
+// 获得数据表的单一主键字段名称，联合主键或者没有主键时返回空字符串(结果缓存直至程序重启)
+func (db *dbPgsql) getPrimaryKey(table string) string {
+    v := db.cache.GetOrSetFunc("table_primary_key_" + table, func() interface{} {
+        result, err := db.GetAll(`SELECT a.attname AS field FROM pg_index i
+            JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+            WHERE i.indrelid = ?::regclass AND i.indisprimary`, quoteWord(`"`, `"`, table))
+        if err != nil {
+            return nil
+        }
+        if len(result) == 1 {
+            return result[0]["field"].String()
+        }
+        return ""
+    }, 0)
+    if v != nil {
+        return v.(string)
+    }
+    return ""
+}
+
+// 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值为字段数据类型(udt_name，数组类型以'_'开头，例如: _int4)，
+// 表名称支持schema.table格式，默认使用当前schema.
+func (db *dbPgsql) getTableFields(table string) (fields map[string]string, err error) {
+    // 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
+    v := db.cache.GetOrSetFunc("table_fields_" + table, func() interface{} {
+        result := (Result)(nil)
+        if array := strings.Split(table, "."); len(array) == 2 {
+            result, err = db.GetAll(`SELECT column_name, udt_name FROM information_schema.columns
+                WHERE table_schema = ? AND table_name = ?`, array[0], array[1])
+        } else {
+            result, err = db.GetAll(`SELECT column_name, udt_name FROM information_schema.columns
+                WHERE table_schema = current_schema() AND table_name = ?`, table)
+        }
+        if err != nil {
+            return nil
+        }
+        fields = make(map[string]string)
+        for _, m := range result {
+            fields[m["column_name"].String()] = strings.ToLower(m["udt_name"].String())
+        }
+        return fields
+    }, 0)
+    if err == nil && v != nil {
+        fields = v.(map[string]string)
+    }
+    return
+}
+
+// 字段类型转换，在默认转换规则的基础上增加PostgreSQL特有的类型支持：
+// 1. bool类型的文本格式为t/f；
+// 2. json/jsonb类型解析为对应的map/slice等golang变量；
+// 3. 数组类型(类型名称以'_'开头，例如: _int4, _text)解析为[]interface{}，元素按照元素类型进行转换，多维数组解析为嵌套的[]interface{}。
+func (db *dbPgsql) convertValue(fieldValue interface{}, fieldType string) interface{} {
+    t := strings.ToLower(fieldType)
+    switch {
+        case t == "bool":
+            return gconv.String(fieldValue) == "t"
+
+        case t == "json" || t == "jsonb":
+            var v interface{}
+            if err := json.Unmarshal(gconv.Bytes(fieldValue), &v); err == nil {
+                return v
+            }
+            return gconv.String(fieldValue)
+
+        case strings.HasPrefix(t, "_"):
+            elemType := t[1:]
+            s        := gconv.String(fieldValue)
+            pos      := strings.Index(s, "{")
+            if pos < 0 {
+                return s
+            }
+            return parsePgsqlArray(s, &pos, func(item string) interface{} {
+                return db.convertValue(item, elemType)
+            })
+
+        default:
+            return db.dbBase.convertValue(fieldValue, fieldType)
+    }
+}
+
+// 写入/更新数据时的参数值转换，根据表字段类型将slice/map/struct类型的参数值编码为PostgreSQL可识别的格式：
+// 1. json/jsonb字段编码为json字符串(由dbBase.convertParam处理)；
+// 2. 数组字段编码为数组字面量(例如: {1,2,3})；
+func (db *dbPgsql) convertParam(table string, field string, value interface{}) interface{} {
+    value = db.dbBase.convertParam(table, field, value)
+    rv   := reflect.ValueOf(value)
+    kind := rv.Kind()
+    if kind == reflect.Ptr {
+        rv   = rv.Elem()
+        kind = rv.Kind()
+    }
+    switch kind {
+        case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+        default:
+            return value
+    }
+    if _, ok := value.([]byte); ok {
+        return value
+    }
+    fields, err := db.getTableFields(table)
+    if err != nil {
+        return value
+    }
+    if t := fields[field]; strings.HasPrefix(t, "_") && (kind == reflect.Slice || kind == reflect.Array) {
+        return formatPgsqlArray(rv)
+    }
+    return value
+}
+
+// 解析PostgreSQL数组字面量(例如: {1,2,3}, {"a b","c\"d",NULL}, {{1,2},{3,4}})，pos为当前解析位置('{'字符位置)，
+// 元素值通过convert进行类型转换，NULL元素转换为nil。
+func parsePgsqlArray(s string, pos *int, convert func(item string) interface{}) []interface{} {
+    array := make([]interface{}, 0)
+    *pos++
+    for *pos < len(s) {
+        switch s[*pos] {
+            case '}':
+                *pos++
+                return array
+            case ',', ' ':
+                *pos++
+            case '{':
+                array = append(array, parsePgsqlArray(s, pos, convert))
+            case '"':
+                *pos++
+                buffer := bytes.NewBuffer(nil)
+                for *pos < len(s) && s[*pos] != '"' {
+                    if s[*pos] == '\\' && *pos + 1 < len(s) {
+                        *pos++
+                    }
+                    buffer.WriteByte(s[*pos])
+                    *pos++
+                }
+                *pos++
+                array = append(array, convert(buffer.String()))
+            default:
+                start := *pos
+                for *pos < len(s) && s[*pos] != ',' && s[*pos] != '}' {
+                    *pos++
+                }
+                if item := strings.TrimSpace(s[start : *pos]); strings.EqualFold(item, "NULL") {
+                    array = append(array, nil)
+                } else {
+                    array = append(array, convert(item))
+                }
+        }
+    }
+    return array
+}
+
+// 将slice/array编码为PostgreSQL数组字面量，字符串元素使用双引号包裹并转义，nil元素编码为NULL
+func formatPgsqlArray(rv reflect.Value) string {
+    buffer := bytes.NewBufferString("{")
+    for i := 0; i < rv.Len(); i++ {
+        if i > 0 {
+            buffer.WriteByte(',')
+        }
+        item := rv.Index(i)
+        for item.Kind() == reflect.Interface || item.Kind() == reflect.Ptr {
+            if item.IsNil() {
+                break
+            }
+            item = item.Elem()
+        }
+        switch item.Kind() {
+            case reflect.Interface, reflect.Ptr:
+                buffer.WriteString("NULL")
+            case reflect.Slice, reflect.Array:
+                if b, ok := item.Interface().([]byte); ok {
+                    buffer.WriteString(quotePgsqlArrayItem(string(b)))
+                } else {
+                    buffer.WriteString(formatPgsqlArray(item))
+                }
+            case reflect.Bool,
+                 reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+                 reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+                 reflect.Float32, reflect.Float64:
+                buffer.WriteString(gconv.String(item.Interface()))
+            default:
+                buffer.WriteString(quotePgsqlArrayItem(gconv.String(item.Interface())))
+        }
+    }
+    buffer.WriteByte('}')
+    return buffer.String()
+}
+
+// 使用双引号包裹数组字符串元素，并转义其中的'\'及'"'字符
+func quotePgsqlArrayItem(s string) string {
+    s = strings.Replace(s, `\`, `\\`, -1)
+    s = strings.Replace(s, `"`, `\"`, -1)
+    return `"` + s + `"`
+}
+
+// 获取当前schema下的所有数据表名称
+func (db *dbPgsql) Tables() (tables []string, err error) {
+    result, err := db.GetAll(`SELECT tablename FROM pg_tables WHERE schemaname = current_schema() ORDER BY tablename`)
+    if err != nil {
+        return nil, err
+    }
+    tables = make([]string, len(result))
+    for i, m := range result {
+        tables[i] = m["tablename"].String()
+    }
+    return tables, nil
+}
+
+// 获取指定数据表的字段详细信息，表名称支持schema.table格式，字段类型为udt_name(数组类型以'_'开头)
+func (db *dbPgsql) TableFields(table string) (fields []*TableField, err error) {
+    query := `SELECT c.column_name, c.udt_name, c.is_nullable, c.column_default, c.is_identity,
+        col_description(?::regclass, c.ordinal_position) AS column_comment
+        FROM information_schema.columns c WHERE %s AND c.table_name = ? ORDER BY c.ordinal_position`
+    result := (Result)(nil)
+    name   := quoteWord(`"`, `"`, table)
+    if array := strings.Split(table, "."); len(array) == 2 {
+        result, err = db.GetAll(fmt.Sprintf(query, "c.table_schema = ?"), name, array[0], array[1])
+    } else {
+        result, err = db.GetAll(fmt.Sprintf(query, "c.table_schema = current_schema()"), name, table)
+    }
+    if err != nil {
+        return nil, err
+    }
+    fields = make([]*TableField, len(result))
+    for i, m := range result {
+        fields[i] = &TableField {
+            Index   : i,
+            Name    : m["column_name"].String(),
+            Type    : m["udt_name"].String(),
+            Null    : m["is_nullable"].String() == "YES",
+            Default : m["column_default"].Val(),
+            Comment : m["column_comment"].String(),
+        }
+        if m["is_identity"].String() == "YES" || strings.HasPrefix(m["column_default"].String(), "nextval(") {
+            fields[i].Extra = "auto_increment"
+        }
+    }
+    indexes, err := db.TableIndexes(table)
+    if err != nil {
+        return nil, err
+    }
+    fillTableFieldKeys(fields, indexes)
+    return fields, nil
+}
+
+// 获取指定数据表的索引信息，表名称支持schema.table格式
+func (db *dbPgsql) TableIndexes(table string) (indexes []*TableIndex, err error) {
+    result, err := db.GetAll(`SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique, ix.indisprimary
+        FROM pg_index ix
+        JOIN pg_class i ON i.oid = ix.indexrelid
+        JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = ANY(ix.indkey)
+        WHERE ix.indrelid = ?::regclass
+        ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)`, quoteWord(`"`, `"`, table))
+    if err != nil {
+        return nil, err
+    }
+    return groupTableIndexes(result, "index_name", "column_name", func(m Record) (bool, bool) {
+        return m["indisunique"].Bool(), m["indisprimary"].Bool()
+    }), nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/crypto/gcrc32"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gconv"
+    "reflect"
+    "sort"
+    "strings"
+    "sync"
+)
+
+// 分片规则接口，用于将逻辑表按照分片键值路由到物理表(分表)或者不同schema下的表(分库，例如: db_1.user)
+type ShardRule interface {
+    // 根据分片键值计算目标物理表名称
+    Shard(table string, value interface{}) (string, error)
+    // 获得逻辑表的所有物理表名称(用于跨分片查询)
+    Shards(table string) []string
+}
+
+// 按照分片键值哈希取模的分片规则，键值转换为字符串后使用crc32计算哈希值后取模。
+// Format为物理表名称格式，参数依次为逻辑表名称及分片序号，默认为"%s_%d"，
+// 分库时可以使用例如"db_%[2]d.%[1]s"的格式。
+type HashShardRule struct {
+    Count  int    // 分片数量
+    Format string // 物理表名称格式
+}
+
+// 范围分片的区间定义，键值满足 Min <= value < Max 时路由到Table
+type ShardRange struct {
+    Min   int64
+    Max   int64
+    Table string
+}
+
+// 按照分片键值(整型)范围进行路由的分片规则
+type RangeShardRule struct {
+    Ranges []ShardRange
+}
+
+// 自定义分片规则，Func用于计算分片键值对应的物理表，Tables为所有的物理表名称
+type CustomShardRule struct {
+    Func   func(table string, value interface{}) (string, error)
+    Tables []string
+}
+
+// 数据表的分片配置
+type shardConfig struct {
+    key  string    // 分片键(字段名称)
+    rule ShardRule // 分片规则
+}
+
+// 设置逻辑表的分片规则，key为分片键(字段名称)，rule为nil时表示删除该表的分片规则。
+// 链式操作时将根据查询条件或者写入数据中的分片键值自动路由到对应的物理表，
+// 查询条件中不包含分片键时，查询操作将会在所有分片上执行并合并结果。
+func (bs *dbBase) SetShardRule(table string, key string, rule ShardRule) {
+    if rule == nil {
+        bs.shardRules.Remove(table)
+        return
+    }
+    bs.shardRules.Set(table, &shardConfig{
+        key  : key,
+        rule : rule,
+    })
+}
+
+// 获得逻辑表的分片配置，不存在时返回nil
+func (bs *dbBase) getShardConfig(table string) *shardConfig {
+    if v := bs.shardRules.Get(table); v != nil {
+        return v.(*shardConfig)
+    }
+    return nil
+}
+
+// see ShardRule.Shard
+func (r *HashShardRule) Shard(table string, value interface{}) (string, error) {
+    if r.Count <= 0 {
+        return "", errors.New("invalid shard count for hash shard rule")
+    }
+    // 所有类型的键值统一转换为字符串后计算哈希值，保证例如3与"3"路由到同一分片
+    index := gcrc32.EncryptString(gconv.String(value)) % uint32(r.Count)
+    return r.format(table, int(index)), nil
+}
+
+// see ShardRule.Shards
+func (r *HashShardRule) Shards(table string) []string {
+    tables := make([]string, r.Count)
+    for i := 0; i < r.Count; i++ {
+        tables[i] = r.format(table, i)
+    }
+    return tables
+}
+
+// 生成物理表名称
+func (r *HashShardRule) format(table string, index int) string {
+    if r.Format == "" {
+        return fmt.Sprintf("%s_%d", table, index)
+    }
+    return fmt.Sprintf(r.Format, table, index)
+}
+
+// see ShardRule.Shard
+func (r *RangeShardRule) Shard(table string, value interface{}) (string, error) {
+    v := gconv.Int64(value)
+    for _, item := range r.Ranges {
+        if v >= item.Min && v < item.Max {
+            return item.Table, nil
+        }
+    }
+    return "", errors.New(fmt.Sprintf(`no shard range found for value "%v" of table "%s"`, value, table))
+}
+
+// see ShardRule.Shards
+func (r *RangeShardRule) Shards(table string) []string {
+    tables := make([]string, len(r.Ranges))
+    for i, item := range r.Ranges {
+        tables[i] = item.Table
+    }
+    return tables
+}
+
+// see ShardRule.Shard
+func (r *CustomShardRule) Shard(table string, value interface{}) (string, error) {
+    return r.Func(table, value)
+}
+
+// see ShardRule.Shards
+func (r *CustomShardRule) Shards(table string) []string {
+    return r.Tables
+}
+
+// 链式操作，手动指定操作的物理表，将会忽略分片规则的自动路由
+func (md *Model) Shard(table string) *Model {
+    model      := md.Clone()
+    model.shard = table
+    return model
+}
+
+// 判断当前链式操作是否需要进行分片路由，只有单表操作才支持分片
+func (md *Model) getShardConfig() *shardConfig {
+    if md.tables != md.tablesInit {
+        return nil
+    }
+    return md.db.getShardConfig(md.tablesInit)
+}
+
+// 根据查询条件获得当前操作的物理表列表，没有分片规则时返回nil；
+// 条件中包含分片键的等值(或者IN)条件时路由到对应的分片，否则返回所有分片。
+func (md *Model) getShardTablesByWhere() ([]string, error) {
+    if md.shard != "" {
+        return []string{md.shard}, nil
+    }
+    config := md.getShardConfig()
+    if config == nil {
+        return nil, nil
+    }
+    values, ok := md.getShardValuesFromWhere(config.key)
+    if !ok {
+        return config.rule.Shards(md.tablesInit), nil
+    }
+    tables := make([]string, 0)
+    exists := make(map[string]struct{})
+    for _, v := range values {
+        table, err := config.rule.Shard(md.tablesInit, v)
+        if err != nil {
+            return nil, err
+        }
+        if _, ok := exists[table]; !ok {
+            exists[table] = struct{}{}
+            tables        = append(tables, table)
+        }
+    }
+    return tables, nil
+}
+
+// 从查询条件中解析分片键的值，支持 key=?, key=常量, key IN(?,?) 格式，
+// 条件中包含OR时无法确定分片，返回false。
+func (md *Model) getShardValuesFromWhere(key string) ([]interface{}, bool) {
+    where := md.where
+    if where == "" || gregex.IsMatchString(`(?i)\sOR\s`, where) {
+        return nil, false
+    }
+    name := fmt.Sprintf("(?:^|[\\s\\(,.])[`\"\\[]?%s[`\"\\]]?", gregex.Quote(key))
+    // key=?
+    if match, _ := gregex.MatchString(`(?i)^(.*?` + name + `\s*=\s*)\?`, where); len(match) > 1 {
+        if index := strings.Count(match[1], "?"); index < len(md.whereArgs) {
+            return []interface{}{md.whereArgs[index]}, true
+        }
+        return nil, false
+    }
+    // key=常量
+    if match, _ := gregex.MatchString(`(?i)` + name + `\s*=\s*('([^']*)'|(-?\d+))`, where); len(match) > 3 {
+        if match[3] != "" {
+            return []interface{}{gconv.Int64(match[3])}, true
+        }
+        return []interface{}{match[2]}, true
+    }
+    // key IN(?,?)
+    if match, _ := gregex.MatchString(`(?i)^(.*?` + name + `\s+IN\s*\()([\?,\s]+)\)`, where); len(match) > 2 {
+        index := strings.Count(match[1], "?")
+        count := strings.Count(match[2], "?")
+        if index + count <= len(md.whereArgs) {
+            return md.whereArgs[index : index + count], true
+        }
+    }
+    return nil, false
+}
+
+// 根据写入数据获得数据对应的物理表，没有分片规则时返回逻辑表名称
+func (md *Model) getShardTableByData(data Map) (string, error) {
+    if md.shard != "" {
+        return md.shard, nil
+    }
+    config := md.getShardConfig()
+    if config == nil {
+        return md.tables, nil
+    }
+    value, ok := data[config.key]
+    if !ok {
+        return "", errors.New(fmt.Sprintf(`sharding key "%s" is required in data for table "%s"`, config.key, md.tablesInit))
+    }
+    return config.rule.Shard(md.tablesInit, value)
+}
+
+// 按照分片对批量写入的数据进行分组，返回物理表列表(按照数据出现顺序)及对应的数据分组
+func (md *Model) groupListByShard(list List) ([]string, map[string]List, error) {
+    tables := make([]string, 0)
+    groups := make(map[string]List)
+    for _, m := range list {
+        table, err := md.getShardTableByData(m)
+        if err != nil {
+            return nil, nil, err
+        }
+        if _, ok := groups[table]; !ok {
+            tables = append(tables, table)
+        }
+        groups[table] = append(groups[table], m)
+    }
+    return tables, groups, nil
+}
+
+// 获得写入/修改操作的唯一物理表，无法确定唯一分片时返回错误
+func (md *Model) getShardTableForWrite() (string, error) {
+    tables, err := md.getShardTablesByWhere()
+    if err != nil {
+        return "", err
+    }
+    switch len(tables) {
+        case 0:
+            return md.tables, nil
+        case 1:
+            return tables[0], nil
+        default:
+            return "", errors.New(fmt.Sprintf(`sharding key condition is required for writing table "%s"`, md.tablesInit))
+    }
+}
+
+// 创建操作指定物理表的链式操作对象
+func (md *Model) withShardTable(table string) *Model {
+    model       := md.Clone()
+    model.tables = table
+    model.shard  = table
+    return model
+}
+
+// 在多个分片上并发执行查询，并按照排序及分页条件合并结果(仅支持简单查询，不支持GROUP BY)
+func (md *Model) getAllFromShards(tables []string) (Result, error) {
+    if md.groupBy != "" {
+        return nil, errors.New("group by is not supported for cross-shard queries")
+    }
+    // 每个分片查询前start+limit条记录，合并后再进行分页
+    start, limit := md.start, md.limit
+    results      := make([]Result, len(tables))
+    errs         := make([]error, len(tables))
+    wg           := sync.WaitGroup{}
+    for i, table := range tables {
+        wg.Add(1)
+        go func(i int, table string) {
+            defer wg.Done()
+            model := md.withShardTable(table)
+            if limit > 0 {
+                model.start = 0
+                model.limit = start + limit
+            }
+            results[i], errs[i] = model.getAll(model.getFormattedSql(), model.whereArgs...)
+        }(i, table)
+    }
+    wg.Wait()
+    merged := make(Result, 0)
+    for i := range tables {
+        if errs[i] != nil {
+            return nil, errs[i]
+        }
+        merged = append(merged, results[i]...)
+    }
+    if md.orderBy != "" {
+        sortResultByOrder(merged, md.orderBy)
+    }
+    if limit > 0 {
+        if start >= len(merged) {
+            return make(Result, 0), nil
+        }
+        if end := start + limit; end < len(merged) {
+            merged = merged[start : end]
+        } else {
+            merged = merged[start:]
+        }
+    }
+    return merged, nil
+}
+
+// 在多个分片上执行数量查询并求和
+func (md *Model) countFromShards(tables []string) (int, error) {
+    if md.groupBy != "" {
+        return 0, errors.New("group by is not supported for cross-shard queries")
+    }
+    total := 0
+    for _, table := range tables {
+        count, err := md.withShardTable(table).Count()
+        if err != nil {
+            return 0, err
+        }
+        total += count
+    }
+    return total, nil
+}
+
+// 按照ORDER BY语句(例如: "id DESC, name")对合并后的结果集进行排序
+func sortResultByOrder(result Result, orderBy string) {
+    type orderField struct {
+        name string
+        desc bool
+    }
+    fields := make([]orderField, 0)
+    for _, item := range strings.Split(orderBy, ",") {
+        array := strings.Fields(item)
+        if len(array) == 0 {
+            continue
+        }
+        name := array[len(array) - 1]
+        desc := false
+        if len(array) > 1 {
+            name = array[len(array) - 2]
+            desc = strings.EqualFold(array[len(array) - 1], "DESC")
+        }
+        // 去掉表前缀及引用符号
+        if pos := strings.LastIndex(name, "."); pos >= 0 {
+            name = name[pos + 1:]
+        }
+        fields = append(fields, orderField{strings.Trim(name, "`\"[]"), desc})
+    }
+    sort.SliceStable(result, func(i, j int) bool {
+        for _, f := range fields {
+            c := compareValue(result[i][f.name], result[j][f.name])
+            if c == 0 {
+                continue
+            }
+            if f.desc {
+                return c > 0
+            }
+            return c < 0
+        }
+        return false
+    })
+}
+
+// 比较两个字段值的大小，数值类型按照数值比较，其他类型按照字符串比较
+func compareValue(a, b Value) int {
+    if a == nil || b == nil {
+        switch {
+            case a == nil && b == nil: return 0
+            case a == nil:             return -1
+            default:                   return 1
+        }
+    }
+    switch reflect.ValueOf(a.Val()).Kind() {
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+             reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+             reflect.Float32, reflect.Float64:
+            x, y := a.Float64(), b.Float64()
+            switch {
+                case x < y: return -1
+                case x > y: return 1
+            }
+            return 0
+    }
+    return strings.Compare(a.String(), b.String())
+}
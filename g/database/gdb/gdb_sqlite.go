@@ -9,10 +9,15 @@ package gdb
 
 import (
 	"database/sql"
+	"fmt"
+	"github.com/gogf/gf/g/text/gregex"
+	"github.com/gogf/gf/g/util/gconv"
+	"strings"
 )
 
 // 使用时需要import:
-// _ "github.com/gogf/gf/third/github.com/mattn/go-sqlite3"
+// _ "github.com/mattn/go-sqlite3"
+// 配置节点的Type为sqlite(或者sqlite3)，Name为数据库文件路径(内存数据库可使用 :memory:)。
 
 // Sqlite接口对象
 // @author wxkj<wxscz@qq.com>
@@ -36,14 +41,157 @@ func (db *dbSqlite) Open(config *ConfigNode) (*sql.DB, error) {
 	}
 }
 
-// 获得关键字操作符
+// 获得关键字操作符(SQLite标准的标识符引用符号为双引号)
 func (db *dbSqlite) getChars () (charLeft string, charRight string) {
-	return "`", "`"
+	return "\"", "\""
 }
 
-// 在执行sql之前对sql进行进一步处理
-// @todo 需要增加对Save方法的支持，可使用正则来实现替换，
-// @todo 将ON DUPLICATE KEY UPDATE触发器修改为两条SQL语句(INSERT OR IGNORE & UPDATE)
+// 在执行sql之前对sql进行进一步处理，
+// 将MySQL的INSERT IGNORE语法转换为SQLite的INSERT OR IGNORE语法(REPLACE INTO语法SQLite原生支持)。
 func (db *dbSqlite) handleSqlBeforeExec(query string) string {
+	query, _ = gregex.ReplaceString(`(?i)^\s*INSERT\s+IGNORE\s+INTO`, "INSERT OR IGNORE INTO", query)
 	return query
-}
\ No newline at end of file
+}
+
+// Save操作时使用ON CONFLICT DO UPDATE语法(SQLite 3.24.0+)，需要通过OnConflict指定冲突检测字段
+func (db *dbSqlite) formatUpsert(columns []string, option *insertOption) (string, error) {
+	charL, charR := db.getChars()
+	return formatOnConflictUpsert(charL, charR, columns, option)
+}
+
+// 字段类型转换，SQLite使用动态类型，这里按照SQLite的类型亲和性(Type Affinity)规则进行转换：
+// 1. 类型名称包含INT为整型；
+// 2. 类型名称包含CHAR/CLOB/TEXT为字符串；
+// 3. 类型名称包含BLOB为二进制；
+// 4. 类型名称包含REAL/FLOA/DOUB为浮点型；
+// 5. 其他类型(例如NUMERIC/DECIMAL/BOOLEAN/DATETIME)按照原有的转换规则处理。
+func (db *dbSqlite) convertValue(fieldValue interface{}, fieldType string) interface{} {
+	t := strings.ToLower(fieldType)
+	switch {
+		case strings.Contains(t, "int"):
+			return gconv.Int64(fieldValue)
+
+		case strings.Contains(t, "char") || strings.Contains(t, "clob") || strings.Contains(t, "text"):
+			return gconv.String(fieldValue)
+
+		case strings.Contains(t, "blob"):
+			return gconv.Bytes(fieldValue)
+
+		case strings.Contains(t, "real") || strings.Contains(t, "floa") || strings.Contains(t, "doub"):
+			return gconv.Float64(fieldValue)
+
+		default:
+			return db.dbBase.convertValue(fieldValue, fieldType)
+	}
+}
+
+// 获得指定表表的数据结构，构造成map哈希表返回，其中键名为表字段名称，键值为字段数据类型.
+func (db *dbSqlite) getTableFields(table string) (fields map[string]string, err error) {
+	// 缓存不存在时会查询数据表结构，缓存后不过期，直至程序重启(重新部署)
+	v := db.cache.GetOrSetFunc("table_fields_" + table, func() interface{} {
+		result      := (Result)(nil)
+		result, err  = db.GetAll(fmt.Sprintf(`PRAGMA TABLE_INFO("%s")`, table))
+		if err != nil {
+			return nil
+		}
+		fields = make(map[string]string)
+		for _, m := range result {
+			fields[m["name"].String()] = strings.ToLower(m["type"].String())
+		}
+		return fields
+	}, 0)
+	if err == nil {
+		fields = v.(map[string]string)
+	}
+	return
+}
+
+// 获取当前数据库的所有数据表名称(不包含sqlite_开头的内部表)
+func (db *dbSqlite) Tables() (tables []string, err error) {
+	result, err := db.GetAll(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	tables = make([]string, len(result))
+	for i, m := range result {
+		tables[i] = m["name"].String()
+	}
+	return tables, nil
+}
+
+// 获取指定数据表的字段详细信息，单一的INTEGER PRIMARY KEY字段(rowid别名)标记为auto_increment
+func (db *dbSqlite) TableFields(table string) (fields []*TableField, err error) {
+	result, err := db.GetAll(fmt.Sprintf(`PRAGMA TABLE_INFO(%s)`, quoteWord(`"`, `"`, table)))
+	if err != nil {
+		return nil, err
+	}
+	pkCount := 0
+	fields   = make([]*TableField, len(result))
+	for i, m := range result {
+		fields[i] = &TableField {
+			Index   : i,
+			Name    : m["name"].String(),
+			Type    : strings.ToLower(m["type"].String()),
+			Null    : m["notnull"].Int() == 0 && m["pk"].Int() == 0,
+			Default : m["dflt_value"].Val(),
+		}
+		if m["pk"].Int() > 0 {
+			fields[i].Key = "PRI"
+			pkCount++
+		}
+	}
+	for _, field := range fields {
+		if pkCount == 1 && field.Key == "PRI" && field.Type == "integer" {
+			field.Extra = "auto_increment"
+		}
+	}
+	indexes, err := db.TableIndexes(table)
+	if err != nil {
+		return nil, err
+	}
+	fillTableFieldKeys(fields, indexes)
+	return fields, nil
+}
+
+// 获取指定数据表的索引信息，rowid别名主键没有对应的索引，这里根据字段信息补充主键索引
+func (db *dbSqlite) TableIndexes(table string) (indexes []*TableIndex, err error) {
+	list, err := db.GetAll(fmt.Sprintf(`PRAGMA INDEX_LIST(%s)`, quoteWord(`"`, `"`, table)))
+	if err != nil {
+		return nil, err
+	}
+	indexes    = make([]*TableIndex, 0)
+	hasPrimary := false
+	for _, item := range list {
+		columns, err := db.GetAll(fmt.Sprintf(`PRAGMA INDEX_INFO(%s)`, quoteWord(`"`, `"`, item["name"].String())))
+		if err != nil {
+			return nil, err
+		}
+		index := &TableIndex {
+			Name    : item["name"].String(),
+			Unique  : item["unique"].Int() == 1,
+			Primary : item["origin"].String() == "pk",
+			Columns : make([]string, len(columns)),
+		}
+		for i, m := range columns {
+			index.Columns[i] = m["name"].String()
+		}
+		hasPrimary = hasPrimary || index.Primary
+		indexes    = append(indexes, index)
+	}
+	if !hasPrimary {
+		result, err := db.GetAll(fmt.Sprintf(`PRAGMA TABLE_INFO(%s)`, quoteWord(`"`, `"`, table)))
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]string, 0)
+		for _, m := range result {
+			if m["pk"].Int() > 0 {
+				columns = append(columns, m["name"].String())
+			}
+		}
+		if len(columns) > 0 {
+			indexes = append([]*TableIndex{{Name : "PRIMARY", Unique : true, Primary : true, Columns : columns}}, indexes...)
+		}
+	}
+	return indexes, nil
+}
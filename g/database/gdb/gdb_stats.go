@@ -0,0 +1,64 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "database/sql"
+    "time"
+)
+
+// 数据库节点链接池统计信息，可用于导出到监控系统
+type NodeStats struct {
+    Host              string        // 节点地址
+    Port              string        // 节点端口
+    Name              string        // 数据库名称
+    Role              string        // 节点角色: master, slave
+    Healthy           bool          // 最近一次健康检查是否健康
+    MaxOpenConns      int           // 连接池最大打开的连接数
+    OpenConns         int           // 当前打开的连接数(使用中+空闲)
+    InUse             int           // 当前使用中的连接数
+    Idle              int           // 当前空闲的连接数
+    WaitCount         int64         // 等待获取连接的总次数
+    WaitDuration      time.Duration // 等待获取连接的总时长
+    MaxIdleClosed     int64         // 由于超过最大空闲连接数而关闭的连接总数
+    MaxLifetimeClosed int64         // 由于超过连接存活时间而关闭的连接总数
+}
+
+// 获取当前数据库分组下所有已建立链接池的节点统计信息
+func (bs *dbBase) Stats() []NodeStats {
+    array := make([]NodeStats, 0)
+    for _, node := range GetConfig(bs.group) {
+        if node.Charset == "" {
+            node.Charset = "utf8"
+        }
+        v := bs.cache.Get(node.String())
+        if v == nil {
+            continue
+        }
+        stats := v.(*sql.DB).Stats()
+        role  := node.Role
+        if role == "" {
+            role = "master"
+        }
+        array = append(array, NodeStats {
+            Host              : node.Host,
+            Port              : node.Port,
+            Name              : node.Name,
+            Role              : role,
+            Healthy           : bs.isNodeHealthy(&node),
+            MaxOpenConns      : stats.MaxOpenConnections,
+            OpenConns         : stats.OpenConnections,
+            InUse             : stats.InUse,
+            Idle              : stats.Idle,
+            WaitCount         : stats.WaitCount,
+            WaitDuration      : stats.WaitDuration,
+            MaxIdleClosed     : stats.MaxIdleClosed,
+            MaxLifetimeClosed : stats.MaxLifetimeClosed,
+        })
+    }
+    return array
+}
@@ -9,8 +9,10 @@ package gdb
 
 import (
     "fmt"
+    "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/util/gconv"
     "github.com/gogf/gf/g/text/gregex"
+    "math/big"
     "strings"
 )
 
@@ -24,26 +26,35 @@ func (bs *dbBase) syncTableStructure() {
 }
 */
 
-// 字段类型转换，将数据库字段类型转换为golang变量类型
+// 字段类型转换，将数据库字段类型转换为golang变量类型：
+// 1. 二进制类型(binary/blob等)转换为[]byte，文本类型转换为string；
+// 2. 日期时间类型(date/datetime/timestamp)转换为*gtime.Time，无法解析的值(例如0000-00-00)保持字符串；
+// 3. 高精度数值类型(decimal/numeric)按照SetDecimalType设置的类型进行转换，默认为float64。
 func (bs *dbBase) convertValue(fieldValue interface{}, fieldType string) interface{} {
     t, _ := gregex.ReplaceString(`\(.+\)`, "", fieldType)
-    t     = strings.ToLower(t)
+    t     = strings.ToLower(strings.TrimSpace(t))
     switch t {
-    case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+    case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "geometry", "bytea":
         return gconv.Bytes(fieldValue)
 
-    case "bit", "int", "tinyint", "small_int", "medium_int":
+    case "bit", "int", "tinyint", "smallint", "mediumint", "small_int", "medium_int", "integer":
         return gconv.Int(fieldValue)
 
-    case "big_int":
+    case "bigint", "big_int":
         return gconv.Int64(fieldValue)
 
-    case "float", "double", "decimal":
+    case "float", "double", "real":
         return gconv.Float64(fieldValue)
 
+    case "decimal", "numeric":
+        return bs.convertDecimal(fieldValue)
+
     case "bool":
         return gconv.Bool(fieldValue)
 
+    case "date", "datetime", "timestamp", "timestamptz", "smalldatetime", "datetime2", "datetimeoffset":
+        return convertTimeValue(fieldValue)
+
     default:
         // 自动识别类型, 以便默认支持更多数据库类型
         switch {
@@ -62,12 +73,76 @@ func (bs *dbBase) convertValue(fieldValue interface{}, fieldType string) interfa
             case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
                 return gconv.Bytes(fieldValue)
 
+            case strings.HasPrefix(t, "timestamp") || strings.HasPrefix(t, "datetime"):
+                return convertTimeValue(fieldValue)
+
             default:
                 return gconv.String(fieldValue)
         }
     }
 }
 
+// 高精度数值类型转换，按照设置的类型返回float64/string/*big.Rat，解析失败时返回原始字符串
+func (bs *dbBase) convertDecimal(fieldValue interface{}) interface{} {
+    switch bs.decimalType.Val() {
+        case DECIMAL_TYPE_STRING:
+            return gconv.String(fieldValue)
+
+        case DECIMAL_TYPE_RAT:
+            if r, ok := new(big.Rat).SetString(gconv.String(fieldValue)); ok {
+                return r
+            }
+            return gconv.String(fieldValue)
+
+        default:
+            return gconv.Float64(fieldValue)
+    }
+}
+
+// 将*big.Rat格式化为十进制字符串(去掉末尾多余的0)，用于DECIMAL字段的参数值
+func formatRat(r *big.Rat) string {
+    if r.IsInt() {
+        return r.RatString()
+    }
+    return strings.TrimRight(strings.TrimRight(r.FloatString(gDECIMAL_RAT_PRECISION), "0"), ".")
+}
+
+// 日期时间类型转换为*gtime.Time，无法解析或者为零值日期(0000-00-00)时返回原始字符串
+func convertTimeValue(fieldValue interface{}) interface{} {
+    s := gconv.String(fieldValue)
+    if s == "" || strings.HasPrefix(s, "0000-00-00") {
+        return s
+    }
+    if t, err := gtime.StrToTime(s); err == nil {
+        return t
+    }
+    return s
+}
+
+// 写入/更新数据时的参数值转换，不同数据库可覆盖该方法实现特定字段类型(例如数组、json)的参数编码，
+// 默认只将gtime.Time/big.Rat转换为驱动支持的time.Time/string类型。
+func (bs *dbBase) convertParam(table string, field string, value interface{}) interface{} {
+    switch v := value.(type) {
+        case *gtime.Time:
+            if v != nil {
+                return v.Time
+            }
+        case gtime.Time:
+            return v.Time
+        case *big.Rat:
+            if v != nil {
+                return formatRat(v)
+            }
+    }
+    // JSON类型字段(MySQL: json，PostgreSQL: json/jsonb)，slice/map/struct类型的参数值编码为JSON字符串
+    if isJsonParamValue(value) {
+        if fields, err := bs.db.getTableFields(table); err == nil && isJsonFieldType(fields[field]) {
+            return encodeJsonParam(value)
+        }
+    }
+    return value
+}
+
 // 将map的数据按照fields进行过滤，只保留与表字段同名的数据
 func (bs *dbBase) filterFields(table string, data map[string]interface{}) map[string]interface{} {
     if fields, err := bs.db.getTableFields(table); err == nil {
@@ -102,19 +177,104 @@ func (bs *dbBase) getTableFields(table string) (fields map[string]string, err er
     return
 }
 
-/*
-// 获取当前数据库所有的表结构
-func (bs *dbBase) getTables() []string {
-    if result, _ := bs.GetAll(`SHOW TABLES`); result != nil {
-        array := make([]string, len(result))
-        for i, m := range result {
-            for _, v := range m {
-                array[i] = v.String()
-                break
-            }
+// 获取当前数据库的所有数据表名称
+func (bs *dbBase) Tables() (tables []string, err error) {
+    result, err := bs.db.GetAll(`SHOW TABLES`)
+    if err != nil {
+        return nil, err
+    }
+    tables = make([]string, len(result))
+    for i, m := range result {
+        for _, v := range m {
+            tables[i] = v.String()
+            break
+        }
+    }
+    return tables, nil
+}
+
+// 获取指定数据表的字段详细信息，按照字段在数据表中的顺序返回
+func (bs *dbBase) TableFields(table string) (fields []*TableField, err error) {
+    charL, charR := bs.db.getChars()
+    result, err  := bs.db.GetAll(fmt.Sprintf(`SHOW FULL COLUMNS FROM %s`, quoteWord(charL, charR, table)))
+    if err != nil {
+        return nil, err
+    }
+    fields = make([]*TableField, len(result))
+    for i, m := range result {
+        fields[i] = &TableField {
+            Index   : i,
+            Name    : m["Field"].String(),
+            Type    : m["Type"].String(),
+            Null    : strings.EqualFold(m["Null"].String(), "YES"),
+            Key     : m["Key"].String(),
+            Default : m["Default"].Val(),
+            Extra   : m["Extra"].String(),
+            Comment : m["Comment"].String(),
+        }
+    }
+    return fields, nil
+}
+
+// 获取指定数据表的索引信息，联合索引的字段按照索引中的顺序返回
+func (bs *dbBase) TableIndexes(table string) (indexes []*TableIndex, err error) {
+    charL, charR := bs.db.getChars()
+    result, err  := bs.db.GetAll(fmt.Sprintf(`SHOW INDEX FROM %s`, quoteWord(charL, charR, table)))
+    if err != nil {
+        return nil, err
+    }
+    return groupTableIndexes(result, "Key_name", "Column_name", func(m Record) (bool, bool) {
+        return m["Non_unique"].Int() == 0, m["Key_name"].String() == "PRIMARY"
+    }), nil
+}
+
+// 将按行返回的索引字段信息(每行一个索引字段)按照索引名称进行合并，索引顺序与首次出现的顺序一致
+func groupTableIndexes(result Result, nameKey, columnKey string, attrs func(m Record) (unique bool, primary bool)) []*TableIndex {
+    indexes  := make([]*TableIndex, 0)
+    indexMap := make(map[string]*TableIndex)
+    for _, m := range result {
+        name := m[nameKey].String()
+        if index, ok := indexMap[name]; ok {
+            index.Columns = append(index.Columns, m[columnKey].String())
+            continue
+        }
+        index := &TableIndex {
+            Name    : name,
+            Columns : []string{m[columnKey].String()},
+        }
+        index.Unique, index.Primary = attrs(m)
+        indexMap[name] = index
+        indexes        = append(indexes, index)
+    }
+    return indexes
+}
+
+// 根据索引信息设置字段的索引类型(仅设置Key为空的字段)，规则与MySQL一致：
+// 主键字段为PRI，单字段唯一索引为UNI，其他索引的首个字段为MUL。
+func fillTableFieldKeys(fields []*TableField, indexes []*TableIndex) {
+    keys := make(map[string]string)
+    for _, index := range indexes {
+        if len(index.Columns) == 0 {
+            continue
+        }
+        switch {
+            case index.Primary:
+                for _, column := range index.Columns {
+                    keys[column] = "PRI"
+                }
+            case index.Unique && len(index.Columns) == 1:
+                if _, ok := keys[index.Columns[0]]; !ok {
+                    keys[index.Columns[0]] = "UNI"
+                }
+            default:
+                if _, ok := keys[index.Columns[0]]; !ok {
+                    keys[index.Columns[0]] = "MUL"
+                }
+        }
+    }
+    for _, field := range fields {
+        if field.Key == "" {
+            field.Key = keys[field.Name]
         }
-        return array
     }
-    return nil
 }
-*/
\ No newline at end of file
@@ -7,7 +7,11 @@
 package gdb
 
 import (
+    "context"
     "database/sql"
+    "fmt"
+    "github.com/gogf/gf/g/container/gset"
+    "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/text/gregex"
     _ "github.com/gogf/gf/third/github.com/go-sql-driver/mysql"
 )
@@ -15,13 +19,58 @@ import (
 // 数据库事务对象
 type TX struct {
     db     DB
-    tx     *sql.Tx
+    tx     *txLink
     master *sql.DB
 }
 
-// 事务操作，提交
+// 事务链接对象，在底层事务对象上记录事务ID，用于拦截器获取语句所属的事务，
+// 同时记录事务中写入的数据表，在事务提交成功后清除相关的查询缓存
+type txLink struct {
+    *sql.Tx
+    id     string
+    tables *gset.StringSet
+}
+
+var (
+    // 事务ID生成计数器
+    txIdCounter = gtype.NewInt64()
+)
+
+// 创建事务操作对象，并为该事务分配进程内唯一的事务ID
+func newTX(db DB, tx *sql.Tx, master *sql.DB) *TX {
+    return &TX {
+        db     : db,
+        tx     : &txLink {
+            Tx     : tx,
+            id     : fmt.Sprintf("tx-%d", txIdCounter.Add(1)),
+            tables : gset.NewStringSet(),
+        },
+        master : master,
+    }
+}
+
+// 获得事务ID(进程内唯一)
+func (tx *TX) Id() string {
+    return tx.tx.id
+}
+
+// 创建一个绑定上下文对象的事务操作对象，该对象的所有SQL操作都将使用该上下文执行，
+// 需要注意的是，事务的提交/回滚会受到开启事务(Begin)时绑定的上下文对象影响。
+func (tx *TX) Ctx(ctx context.Context) *TX {
+    return &TX {
+        db     : tx.db.Ctx(ctx),
+        tx     : tx.tx,
+        master : tx.master,
+    }
+}
+
+// 事务操作，提交，提交成功后清除事务中写入的数据表相关的查询缓存
 func (tx *TX) Commit() error {
-    return tx.tx.Commit()
+    if err := tx.tx.Commit(); err != nil {
+        return err
+    }
+    tx.db.ClearCacheByTable(tx.tx.tables.Slice()...)
+    return nil
 }
 
 // 事务操作，回滚
@@ -101,32 +150,32 @@ func (tx *TX) GetCount(query string, args ...interface{}) (int, error) {
 
 // CURD操作:单条数据写入, 仅仅执行写入操作，如果存在冲突的主键或者唯一索引，那么报错返回
 func (tx *TX) Insert(table string, data interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doInsert(tx.tx, table, data, OPTION_INSERT, batch...)
+    return tx.db.doInsert(tx.tx, table, data, newInsertOption(OPTION_INSERT, batch...))
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条
 func (tx *TX) Replace(table string, data interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doInsert(tx.tx, table, data, OPTION_REPLACE, batch...)
+    return tx.db.doInsert(tx.tx, table, data, newInsertOption(OPTION_REPLACE, batch...))
 }
 
 // CURD操作:单条数据写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据
 func (tx *TX) Save(table string, data interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doInsert(tx.tx, table, data, OPTION_SAVE, batch...)
+    return tx.db.doInsert(tx.tx, table, data, newInsertOption(OPTION_SAVE, batch...))
 }
 
 // CURD操作:批量数据指定批次量写入
 func (tx *TX) BatchInsert(table string, list interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doBatchInsert(tx.tx, table, list, OPTION_INSERT, batch...)
+    return tx.db.doBatchInsert(tx.tx, table, list, newInsertOption(OPTION_INSERT, batch...))
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么删除后重新写入一条
 func (tx *TX) BatchReplace(table string, list interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doBatchInsert(tx.tx, table, list, OPTION_REPLACE, batch...)
+    return tx.db.doBatchInsert(tx.tx, table, list, newInsertOption(OPTION_REPLACE, batch...))
 }
 
 // CURD操作:批量数据指定批次量写入, 如果数据存在(主键或者唯一索引)，那么更新，否则写入一条新数据
 func (tx *TX) BatchSave(table string, list interface{}, batch...int) (sql.Result, error) {
-    return tx.db.doBatchInsert(tx.tx, table, list, OPTION_SAVE, batch...)
+    return tx.db.doBatchInsert(tx.tx, table, list, newInsertOption(OPTION_SAVE, batch...))
 }
 
 // CURD操作:数据更新，统一采用sql预处理
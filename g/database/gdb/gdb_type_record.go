@@ -7,8 +7,15 @@
 package gdb
 
 import (
+    "errors"
+    "fmt"
     "github.com/gogf/gf/g/encoding/gparser"
+    "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/util/gconv"
+    "math/big"
+    "reflect"
+    "strings"
+    "time"
 )
 
 // 将记录结果转换为JSON字符串
@@ -32,11 +39,167 @@ func (r Record) ToMap() Map {
     return m
 }
 
-// 将Map变量映射到指定的struct对象中，注意参数应当是一个对象的指针
+// 将Map变量映射到指定的struct对象中，注意参数应当是一个对象的指针。
+// 在gconv.Struct的基础上增加了以下类型属性的处理：
+// 1. NULL值映射到指针/slice/map类型属性时为nil；
+// 2. 指针类型属性(例如*int, *string)自动创建对象并赋值；
+// 3. time.Time/gtime.Time及其指针类型属性；
+// 4. big.Rat及其指针类型属性(DECIMAL字段)；
+// 5. 使用 gdb:"json" 标签标识的JSON字段属性，自动解码JSON内容。
 func (r Record) ToStruct(obj interface{}) error {
     m := make(map[string]interface{})
     for k, v := range r {
         m[k] = v.Val()
     }
+    if err := bindRecordSpecialFields(m, obj); err != nil {
+        return err
+    }
     return gconv.Struct(m, obj)
 }
+
+var (
+    timeType  = reflect.TypeOf(time.Time{})
+    gtimeType = reflect.TypeOf(gtime.Time{})
+    ratType   = reflect.TypeOf(big.Rat{})
+)
+
+// 处理需要特殊转换的struct属性，处理成功的键值将会从m中删除，其余键值交给gconv.Struct处理
+func bindRecordSpecialFields(m map[string]interface{}, obj interface{}) error {
+    rv := reflect.ValueOf(obj)
+    if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+        return nil
+    }
+    elem     := rv.Elem()
+    elemType := elem.Type()
+    for i := 0; i < elemType.NumField(); i++ {
+        field := elemType.Field(i)
+        if field.PkgPath != "" || field.Anonymous {
+            continue
+        }
+        key := getRecordKeyOfField(m, field)
+        if key == "" {
+            continue
+        }
+        // JSON字段
+        if isJsonField(field) {
+            if err := bindJsonValueToField(elem.Field(i), m[key]); err != nil {
+                return errors.New(fmt.Sprintf(`bind json field "%s" to attribute "%s" failed: %s`, key, field.Name, err.Error()))
+            }
+            delete(m, key)
+            continue
+        }
+        handled, err := bindRecordValueToField(elem.Field(i), m[key])
+        if err != nil {
+            return errors.New(fmt.Sprintf(`bind field "%s" to attribute "%s" failed: %s`, key, field.Name, err.Error()))
+        }
+        if handled {
+            delete(m, key)
+        }
+    }
+    return nil
+}
+
+// 查找struct属性对应的键名，优先使用gconv/json标签，其次忽略大小写及下划线进行匹配
+func getRecordKeyOfField(m map[string]interface{}, field reflect.StructField) string {
+    for _, tagName := range []string{"gconv", "json"} {
+        if tag := field.Tag.Get(tagName); tag != "" {
+            for _, name := range strings.Split(tag, ",") {
+                if _, ok := m[strings.TrimSpace(name)]; ok {
+                    return strings.TrimSpace(name)
+                }
+            }
+        }
+    }
+    for k, _ := range m {
+        if strings.EqualFold(strings.Replace(k, "_", "", -1), strings.Replace(field.Name, "_", "", -1)) {
+            return k
+        }
+    }
+    return ""
+}
+
+// 将记录值绑定到struct属性上，返回是否已处理，未处理的属性由gconv.Struct执行默认的转换
+func bindRecordValueToField(fieldValue reflect.Value, value interface{}) (bool, error) {
+    fieldType := fieldValue.Type()
+    if value == nil {
+        switch fieldType.Kind() {
+            case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+                fieldValue.Set(reflect.Zero(fieldType))
+                return true, nil
+        }
+        if fieldType == timeType || fieldType == gtimeType || fieldType == ratType {
+            fieldValue.Set(reflect.Zero(fieldType))
+            return true, nil
+        }
+        return false, nil
+    }
+    switch fieldType {
+        case timeType:
+            fieldValue.Set(reflect.ValueOf(convertToGTime(value).Time))
+            return true, nil
+
+        case gtimeType:
+            fieldValue.Set(reflect.ValueOf(*convertToGTime(value)))
+            return true, nil
+
+        case ratType:
+            r, err := convertToRat(value)
+            if err != nil {
+                return false, err
+            }
+            fieldValue.Set(reflect.ValueOf(*r))
+            return true, nil
+    }
+    if fieldType.Kind() != reflect.Ptr {
+        return false, nil
+    }
+    // 指针类型属性，创建对象后按照指向的类型进行转换
+    e := reflect.New(fieldType.Elem())
+    if handled, err := bindRecordValueToField(e.Elem(), value); err != nil || handled {
+        if handled {
+            fieldValue.Set(e)
+        }
+        return handled, err
+    }
+    switch kind := fieldType.Elem().Kind(); kind {
+        case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+            reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+            reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            e.Elem().Set(reflect.ValueOf(gconv.Convert(value, kind.String())).Convert(fieldType.Elem()))
+            fieldValue.Set(e)
+            return true, nil
+    }
+    return false, nil
+}
+
+// 将记录值转换为*gtime.Time，空值及无法解析的值返回零值时间对象
+func convertToGTime(value interface{}) *gtime.Time {
+    switch v := value.(type) {
+        case *gtime.Time:
+            return v
+        case gtime.Time:
+            return &v
+        case time.Time:
+            return gtime.NewFromTime(v)
+    }
+    s := gconv.String(value)
+    if s == "" || strings.HasPrefix(s, "0000-00-00") {
+        return &gtime.Time{}
+    }
+    if t := gconv.GTime(s); t != nil {
+        return t
+    }
+    return &gtime.Time{}
+}
+
+// 将记录值转换为*big.Rat
+func convertToRat(value interface{}) (*big.Rat, error) {
+    if r, ok := value.(*big.Rat); ok {
+        return new(big.Rat).Set(r), nil
+    }
+    s := gconv.String(value)
+    if r, ok := new(big.Rat).SetString(s); ok {
+        return r, nil
+    }
+    return nil, errors.New(fmt.Sprintf(`cannot convert "%s" to big.Rat`, s))
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb_test
+
+import (
+    "database/sql"
+    "database/sql/driver"
+    "github.com/gogf/gf/g/database/gdb"
+    "github.com/gogf/gf/g/os/gcron"
+    "github.com/gogf/gf/g/test/gtest"
+    "io"
+    "strings"
+    "sync"
+    "testing"
+)
+
+// 测试使用的sqlite3驱动(仓库中没有引入SQLite驱动)，记录执行的SQL语句，查询返回空结果
+type sqliteTestDriver struct {
+    mu   sync.Mutex
+    sqls []string
+}
+
+type sqliteTestConn struct {
+    driver *sqliteTestDriver
+}
+
+type sqliteTestStmt struct {
+    driver *sqliteTestDriver
+    query  string
+}
+
+type sqliteTestRows struct{}
+
+var sqliteDriver = &sqliteTestDriver{}
+
+func init() {
+    sql.Register("sqlite3", sqliteDriver)
+}
+
+func (d *sqliteTestDriver) Open(name string) (driver.Conn, error) {
+    return &sqliteTestConn{driver : d}, nil
+}
+
+// 获取并清空已执行的SQL语句
+func (d *sqliteTestDriver) flush() []string {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    sqls  := d.sqls
+    d.sqls = nil
+    return sqls
+}
+
+func (c *sqliteTestConn) Prepare(query string) (driver.Stmt, error) {
+    return &sqliteTestStmt{driver : c.driver, query : query}, nil
+}
+
+func (c *sqliteTestConn) Close() error {
+    return nil
+}
+
+func (c *sqliteTestConn) Begin() (driver.Tx, error) {
+    return c, nil
+}
+
+func (c *sqliteTestConn) Commit() error {
+    return nil
+}
+
+func (c *sqliteTestConn) Rollback() error {
+    return nil
+}
+
+func (s *sqliteTestStmt) Close() error {
+    return nil
+}
+
+func (s *sqliteTestStmt) NumInput() int {
+    return -1
+}
+
+func (s *sqliteTestStmt) Exec(args []driver.Value) (driver.Result, error) {
+    s.driver.mu.Lock()
+    s.driver.sqls = append(s.driver.sqls, s.query)
+    s.driver.mu.Unlock()
+    return driver.RowsAffected(1), nil
+}
+
+func (s *sqliteTestStmt) Query(args []driver.Value) (driver.Rows, error) {
+    return sqliteTestRows{}, nil
+}
+
+func (r sqliteTestRows) Columns() []string {
+    return []string{}
+}
+
+func (r sqliteTestRows) Close() error {
+    return nil
+}
+
+func (r sqliteTestRows) Next(dest []driver.Value) error {
+    return io.EOF
+}
+
+func TestCronStore_Sqlite(t *testing.T) {
+    gtest.Case(t, func() {
+        gdb.AddConfigNode("cron_store_sqlite", gdb.ConfigNode {
+            Type : "sqlite",
+            Name : ":memory:",
+        })
+        db, err := gdb.New("cron_store_sqlite")
+        gtest.Assert(err, nil)
+        defer db.Close()
+        store := gdb.NewCronStore(db)
+        sqliteDriver.flush()
+        // 同一任务名称多次写入时通过ON CONFLICT更新已有记录
+        gtest.Assert(store.Set(&gcron.StoreRecord{Name : "job", Pattern : "* * * * * *", LastRun : 1}), nil)
+        gtest.Assert(store.Set(&gcron.StoreRecord{Name : "job", Pattern : "* * * * * *", LastRun : 2}), nil)
+        sqls := sqliteDriver.flush()
+        gtest.Assert(len(sqls), 2)
+        for _, s := range sqls {
+            gtest.Assert(strings.HasPrefix(s, `INSERT INTO "gcron_job"`), true)
+            gtest.Assert(strings.Contains(s, `ON CONFLICT("name") DO UPDATE SET`), true)
+        }
+    })
+}
@@ -3,9 +3,14 @@
 package gdb_test
 
 import (
+    "context"
+    "errors"
     "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/database/gdb"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/text/gregex"
+    "strings"
     "testing"
 )
 
@@ -27,6 +32,35 @@ func TestDbBase_Exec(t *testing.T) {
 	}
 }
 
+func TestDbBase_Ctx(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    if _, err := db.Ctx(ctx).Query("SELECT ?", 1); err != nil {
+        gtest.Fatal(err)
+    }
+    cancel()
+    if _, err := db.Ctx(ctx).Query("SELECT ?", 1); err == nil {
+        gtest.Fatal("FAIL")
+    }
+    if _, err := db.Query("SELECT ?", 1); err != nil {
+        gtest.Fatal(err)
+    }
+}
+
+func TestDbBase_Close(t *testing.T) {
+    gtest.Case(t, func() {
+        r, err := gdb.New()
+        gtest.Assert(err, nil)
+        gtest.Assert(r.PingMaster(), nil)
+        gtest.Assert(r.Close(), nil)
+        // 关闭其他对象不影响当前对象
+        gtest.Assert(db.PingMaster(), nil)
+        // 关闭Ctx创建的对象不关闭原有对象的底层链接池
+        gtest.Assert(db.Ctx(context.Background()).Close(), nil)
+        gtest.Assert(db.PingMaster(), nil)
+        gtest.Assert(db.PingSlave(),  nil)
+    })
+}
+
 func TestDbBase_Prepare(t *testing.T) {
     st, err := db.Prepare("SELECT 100")
     if err != nil {
@@ -281,6 +315,109 @@ func TestDbBase_GetStruct(t *testing.T) {
     }
 }
 
+func TestDbBase_NamedParams(t *testing.T) {
+    gtest.Case(t, func() {
+        result, err := db.GetAll("SELECT * FROM user WHERE id IN(:ids) AND passport<>:passport ORDER BY id", g.Map{
+            "ids"      : g.Slice{1, 2, 3},
+            "passport" : "t2",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(len(result), 2)
+        gtest.Assert(result[0]["id"].Int(), 1)
+        gtest.Assert(result[1]["id"].Int(), 3)
+
+        type Param struct {
+            Id       int
+            Nickname string
+        }
+        _, err = db.Exec("UPDATE user SET nickname=:nickname WHERE id=:id", Param{Id : 3, Nickname : "T3"})
+        gtest.Assert(err, nil)
+
+        count, err := db.Table("user").Where("id=:id", g.Map{"id" : 3}).And("nickname=?", "T3").Count()
+        gtest.Assert(err, nil)
+        gtest.Assert(count, 1)
+
+        _, err = db.GetAll("SELECT * FROM user WHERE id=:id", g.Map{"uid" : 1})
+        gtest.AssertNE(err, nil)
+
+        // 条件中的命名参数错误不会被忽略
+        _, err = db.Table("user").Where("id=:id", g.Map{"uid" : 1}).All()
+        gtest.AssertNE(err, nil)
+        _, err = db.Table("user").Where("id>?", 0).And("id=:id", g.Map{"uid" : 1}).Count()
+        gtest.AssertNE(err, nil)
+        _, err = db.Table("user").Data("nickname", "T3").Where("id=:id", g.Map{"uid" : 1}).Update()
+        gtest.AssertNE(err, nil)
+        _, err = db.Delete("user", "id=:id", g.Map{"uid" : 1})
+        gtest.AssertNE(err, nil)
+
+        // 命名参数绑定空slice时返回错误，而不是生成IN(NULL)条件
+        _, err = db.GetAll("SELECT * FROM user WHERE id IN(:ids)", g.Map{"ids" : g.Slice{}})
+        gtest.AssertNE(err, nil)
+        _, err = db.Table("user").Where("id IN(:ids)", g.Map{"ids" : []int{}}).All()
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestDbBase_SqlCaller(t *testing.T) {
+    gtest.Case(t, func() {
+        db.SetDebug(true)
+        defer db.SetDebug(false)
+        _, err := db.GetAll("SELECT * FROM user WHERE id=?", 1)
+        gtest.Assert(err, nil)
+        _, err = db.Table("user").Where("id", 1).All()
+        gtest.Assert(err, nil)
+        _, err = db.Table("user").Where("id", -1).Delete()
+        gtest.Assert(err, nil)
+        // 调用位置为业务代码(当前测试文件)，跳过gdb包内部的所有文件(包括gdb.go)
+        sqls := db.GetQueriedSqls()
+        gtest.Assert(len(sqls) >= 3, true)
+        for _, s := range sqls[:3] {
+            gtest.Assert(gregex.IsMatchString(`gdb_unit_method_test\.go:\d+$`, s.Caller), true)
+        }
+    })
+}
+
+func TestDbBase_TableFields(t *testing.T) {
+    gtest.Case(t, func() {
+        tables, err := db.Tables()
+        gtest.Assert(err, nil)
+        gtest.AssertIN("user", tables)
+
+        fields, err := db.TableFields("user")
+        gtest.Assert(err, nil)
+        gtest.Assert(len(fields), 5)
+        gtest.Assert(fields[0].Name, "id")
+        gtest.Assert(fields[0].Key, "PRI")
+        gtest.Assert(fields[0].Extra, "auto_increment")
+        gtest.Assert(fields[0].Comment, "用户ID")
+        gtest.Assert(fields[4].Name, "create_time")
+
+        indexes, err := db.TableIndexes("user")
+        gtest.Assert(err, nil)
+        gtest.Assert(len(indexes), 1)
+        gtest.Assert(indexes[0].Primary, true)
+        gtest.Assert(indexes[0].Columns, []string{"id"})
+    })
+}
+
+func TestDbBase_GenerateCode(t *testing.T) {
+    gtest.Case(t, func() {
+        source, err := gdb.GenerateCode(db, "user", gdb.GenConfig {
+            Package  : "entity",
+            JsonCase : "camel",
+            TypeMap  : map[string]string{"user.password": "[]byte"},
+            WithDao  : true,
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(strings.Contains(source, "package entity"), true)
+        gtest.Assert(strings.Contains(source, "type User struct"), true)
+        gtest.Assert(gregex.IsMatchString(`Id\s+uint\s+`+"`"+`gconv:"id" json:"id"`+"`", source), true)
+        gtest.Assert(gregex.IsMatchString(`CreateTime\s+gtime.Time\s+`+"`"+`gconv:"create_time" json:"createTime"`+"`", source), true)
+        gtest.Assert(gregex.IsMatchString(`Password\s+\[\]byte`, source), true)
+        gtest.Assert(strings.Contains(source, "func (d *UserDao) FindByPk(pk interface{}) (*User, error)"), true)
+    })
+}
+
 func TestDbBase_Delete(t *testing.T) {
     if result, err := db.Delete("user", nil); err != nil {
         gtest.Fatal(err)
@@ -290,3 +427,72 @@ func TestDbBase_Delete(t *testing.T) {
     }
 }
 
+
+type testInterceptor struct {
+    before []string
+    after  []*gdb.InterceptorInput
+}
+
+func (i *testInterceptor) Before(in *gdb.InterceptorInput) error {
+    if strings.Contains(in.Sql, "FORBIDDEN") {
+        return errors.New("forbidden")
+    }
+    // 改写语句，添加过滤条件
+    if strings.Contains(in.Sql, "/* tenant */") {
+        in.Sql  = strings.Replace(in.Sql, "/* tenant */", "AND passport=?", 1)
+        in.Args = append(in.Args, "t1")
+    }
+    i.before = append(i.before, in.Func)
+    return nil
+}
+
+func (i *testInterceptor) After(in *gdb.InterceptorInput) {
+    i.after = append(i.after, in)
+}
+
+func TestDbBase_Interceptor(t *testing.T) {
+    gtest.Case(t, func() {
+        d, err := gdb.New()
+        gtest.Assert(err, nil)
+        d.SetSchema("test")
+        interceptor := new(testInterceptor)
+        d.AddInterceptor(interceptor)
+
+        // 语句改写
+        result, err := d.GetAll("SELECT * FROM user WHERE id>? /* tenant */", 0)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(result), 1)
+        gtest.Assert(result[0]["id"].Int(), 1)
+        gtest.Assert(interceptor.before, g.Slice{"Query"})
+        gtest.Assert(len(interceptor.after), 1)
+        gtest.Assert(interceptor.after[0].Args, g.Slice{0, "t1"})
+        gtest.Assert(interceptor.after[0].Error, nil)
+        gtest.Assert(interceptor.after[0].TxId, "")
+
+        // 影响行数
+        _, err = d.Exec("UPDATE user SET nickname=? WHERE id=?", "T111", 1)
+        gtest.Assert(err, nil)
+        gtest.Assert(interceptor.after[1].Func, "Exec")
+        gtest.Assert(interceptor.after[1].RowsAffected >= 0, true)
+
+        // 中断执行
+        _, err = d.Query("SELECT 'FORBIDDEN'")
+        gtest.AssertNE(err, nil)
+        gtest.Assert(len(interceptor.after), 2)
+
+        // 事务ID
+        tx, err := d.Begin()
+        gtest.Assert(err, nil)
+        gtest.AssertNE(tx.Id(), "")
+        _, err = tx.GetAll("SELECT * FROM user WHERE id=?", 1)
+        gtest.Assert(err, nil)
+        gtest.Assert(interceptor.after[2].TxId, tx.Id())
+        gtest.Assert(tx.Commit(), nil)
+
+        // 执行错误
+        _, err = d.Exec("ERROR")
+        gtest.AssertNE(err, nil)
+        gtest.AssertNE(interceptor.after[3].Error, nil)
+        gtest.Assert(interceptor.after[3].RowsAffected, -1)
+    })
+}
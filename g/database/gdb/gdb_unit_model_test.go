@@ -3,10 +3,17 @@
 package gdb_test
 
 import (
+    "context"
+    "errors"
+    "fmt"
     "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/container/gvar"
+    "github.com/gogf/gf/g/database/gdb"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/test/gtest"
+    "math/big"
     "testing"
+    "time"
 )
 
 // 基本测试
@@ -145,6 +152,28 @@ func TestModel_Save(t *testing.T) {
     gtest.Assert(n, 2)
 }
 
+func TestModel_SaveOnDuplicate(t *testing.T) {
+    result, err := db.Table("user").Data(g.List{
+        {
+            "id"          : 1,
+            "passport"    : "t1111",
+            "password"    : "25d55ad283aa400af464c76d713c07ad",
+            "nickname"    : "T1111",
+            "create_time" : gtime.Now().String(),
+        },
+    }).OnDuplicate("passport").Batch(1).Save()
+    if err != nil {
+        gtest.Fatal(err)
+    }
+    n, _ := result.RowsAffected()
+    gtest.Assert(n, 2)
+
+    record, err := db.Table("user").Where("id", 1).One()
+    gtest.Assert(err, nil)
+    gtest.Assert(record["passport"].String(), "t1111")
+    gtest.Assert(record["nickname"].String(), "T111")
+}
+
 func TestModel_Update(t *testing.T) {
     result, err := db.Table("user").Data("passport", "t22").Where("passport=?", "t2").Update()
     if err != nil {
@@ -194,6 +223,135 @@ func TestModel_One(t *testing.T) {
     gtest.Assert(record["nickname"].String(), "T111")
 }
 
+func TestModel_Master(t *testing.T) {
+    record, err := db.Table("user").Master().Where("id", 1).One()
+    if err != nil {
+        gtest.Fatal(err)
+    }
+    if record == nil {
+        gtest.Fatal("FAIL")
+    }
+    gtest.Assert(record["nickname"].String(), "T111")
+}
+
+func TestModel_Hook(t *testing.T) {
+    gtest.Case(t, func() {
+        record, err := db.Table("user").Where("id", 1).Hook(gdb.HOOK_AFTER_QUERY, func(in *gdb.HookInput) error {
+            for _, r := range in.Result {
+                r["nickname"] = gvar.New("hooked", true)
+            }
+            return nil
+        }).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "hooked")
+
+        _, err = db.Table("user").Data("nickname", "T111").Where("id", 1).Hook(gdb.HOOK_BEFORE_UPDATE, func(in *gdb.HookInput) error {
+            return errors.New("update denied")
+        }).Update()
+        gtest.Assert(err, errors.New("update denied"))
+
+        record, err = db.Table("user").Where("id", 1).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+    })
+    // 钩子函数修改查询结果不影响查询缓存
+    gtest.Case(t, func() {
+        hook := func(in *gdb.HookInput) error {
+            for _, r := range in.Result {
+                r["nickname"] = gvar.New("hooked", true)
+            }
+            return nil
+        }
+        for i := 0; i < 2; i++ {
+            record, err := db.Table("user").Where("id", 1).Cache(60).Hook(gdb.HOOK_AFTER_QUERY, hook).One()
+            gtest.Assert(err, nil)
+            gtest.Assert(record["nickname"].String(), "hooked")
+        }
+        record, err := db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+        db.ClearCacheByTable("user")
+    })
+    // 钩子函数可以获得当前操作绑定的上下文对象
+    gtest.Case(t, func() {
+        ctx := context.WithValue(context.Background(), "hook", "ctx")
+        val := interface{}(nil)
+        _, err := db.Table("user").Ctx(ctx).Where("id", 1).Hook(gdb.HOOK_AFTER_QUERY, func(in *gdb.HookInput) error {
+            val = in.Ctx.Value("hook")
+            return nil
+        }).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(val, "ctx")
+    })
+}
+
+func TestModel_Cache(t *testing.T) {
+    gtest.Case(t, func() {
+        record, err := db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+
+        // 通过原生SQL写入数据表时同样清除该表的查询缓存
+        _, err = db.Exec("UPDATE `user` SET `nickname`='T_CACHE' WHERE `id`=1")
+        gtest.Assert(err, nil)
+        record, err = db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T_CACHE")
+
+        // 通过ORM写入数据表时自动清除该表的查询缓存
+        _, err = db.Table("user").Data("nickname", "T111").Where("id", 1).Update()
+        gtest.Assert(err, nil)
+        record, err = db.Table("user").Where("id", 1).Cache(60, "user_1").One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+
+        // 按照名称清除缓存
+        cache := gdb.NewMemQueryCache()
+        db.SetQueryCache(cache)
+        record, err = db.Table("user").Where("id", 1).Cache(60, "user_1").One()
+        gtest.Assert(err, nil)
+        _, ok := cache.Get("user_1")
+        gtest.Assert(ok, true)
+        db.ClearCache("user_1")
+        _, ok = cache.Get("user_1")
+        gtest.Assert(ok, false)
+        db.ClearCacheByTable("user")
+    })
+    // 事务中的写入操作在事务提交成功后清除查询缓存
+    gtest.Case(t, func() {
+        tx, err := db.Begin()
+        gtest.Assert(err, nil)
+        _, err = tx.Exec("UPDATE `user` SET `nickname`='T_CACHE' WHERE `id`=1")
+        gtest.Assert(err, nil)
+        record, err := db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+        gtest.Assert(tx.Commit(), nil)
+        record, err = db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T_CACHE")
+
+        _, err = db.Table("user").Data("nickname", "T111").Where("id", 1).Update()
+        gtest.Assert(err, nil)
+        db.ClearCacheByTable("user")
+    })
+    // 写入语句中带有数据库名称及别名的数据表
+    gtest.Case(t, func() {
+        record, err := db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T111")
+        _, err = db.Exec("UPDATE `test`.`user` u SET u.`nickname`='T_CACHE' WHERE u.`id`=1")
+        gtest.Assert(err, nil)
+        record, err = db.Table("user").Where("id", 1).Cache(60).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T_CACHE")
+
+        _, err = db.Table("user").Data("nickname", "T111").Where("id", 1).Update()
+        gtest.Assert(err, nil)
+        db.ClearCacheByTable("user")
+    })
+}
+
 func TestModel_Value(t *testing.T) {
     value, err := db.Table("user").Fields("nickname").Where("id", 1).Value()
     if err != nil {
@@ -237,6 +395,60 @@ func TestModel_Struct(t *testing.T) {
     gtest.Assert(user.NickName, "T111")
 }
 
+func TestModel_StructTypes(t *testing.T) {
+    gtest.Case(t, func() {
+        type User struct {
+            Id         *int
+            NickName   *string
+            Amount     *big.Rat
+            CreateTime time.Time
+            UpdateTime *gtime.Time
+        }
+        record, err := db.GetOne("SELECT id, NULL AS nickname, CAST(12.30 AS DECIMAL(10,2)) AS amount, create_time, create_time AS update_time FROM user WHERE id=1")
+        gtest.Assert(err, nil)
+        gtest.Assert(record["create_time"].GTime().String(), record["create_time"].String())
+
+        user := new(User)
+        gtest.Assert(record.ToStruct(user), nil)
+        gtest.Assert(*user.Id, 1)
+        gtest.Assert(user.NickName == nil, true)
+        gtest.Assert(user.Amount.FloatString(2), "12.30")
+        gtest.Assert(user.CreateTime.IsZero(), false)
+        gtest.Assert(user.UpdateTime.String(), record["create_time"].String())
+    })
+}
+
+func TestModel_Cursor(t *testing.T) {
+    gtest.Case(t, func() {
+        type User struct {
+            Id       int
+            NickName string
+        }
+        cursor, err := db.Table("user").OrderBy("id ASC").Cursor()
+        if err != nil {
+            gtest.Fatal(err)
+        }
+        users := make([]User, 0)
+        for cursor.Next() {
+            user := User{}
+            gtest.Assert(cursor.Struct(&user), nil)
+            users = append(users, user)
+        }
+        gtest.Assert(cursor.Err(), nil)
+        gtest.Assert(cursor.Close(), nil)
+        gtest.Assert(len(users), 3)
+        gtest.Assert(users[0].NickName, "T111")
+
+        ids := make([]int, 0)
+        err  = db.Table("user").OrderBy("id ASC").Rows(func(record gdb.Record) bool {
+            ids = append(ids, record["id"].Int())
+            return len(ids) < 2
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(ids, []int{1, 2})
+    })
+}
+
 func TestModel_OrderBy(t *testing.T) {
     result, err := db.Table("user").OrderBy("id DESC").Select()
     if err != nil {
@@ -319,6 +531,303 @@ func TestModel_WhereSlice2(t *testing.T) {
     gtest.Assert(result[0]["id"].Int(), 3)
 }
 
+// where builder
+func TestModel_WhereBuilder(t *testing.T) {
+    gtest.Case(t, func() {
+        b := db.Table("user").Builder().OmitEmpty()
+        b.Where(g.Map{"nickname" : "", "id" : g.Slice{1, 3}})
+        where, args, err := b.Build()
+        gtest.Assert(err, nil)
+        gtest.Assert(where, "`id` IN(?,?)")
+        gtest.Assert(args,  g.Slice{1, 3})
+
+        b.Or(b.New().Where("id>", 1).Where("nickname", "T2"))
+        result, err := db.Table("user").Where(b).OrderBy("id ASC").All()
+        if err != nil {
+            gtest.Fatal(err)
+        }
+        gtest.Assert(len(result), 3)
+        gtest.Assert(result[0]["id"].Int(), 1)
+        gtest.Assert(result[1]["id"].Int(), 2)
+        gtest.Assert(result[2]["id"].Int(), 3)
+    })
+    gtest.Case(t, func() {
+        type Query struct {
+            Id       *int    `json:"id"`
+            Nickname *string `json:"nickname"`
+        }
+        where, args, err := db.Table("user").Builder().Where(&Query{}).Build()
+        gtest.Assert(err, nil)
+        gtest.Assert(where, "")
+        gtest.Assert(len(args), 0)
+
+        where, args, err = db.Table("user").Builder().IncludeNull().Where(&Query{}).Build()
+        gtest.Assert(err, nil)
+        gtest.Assert(where, "`id` IS NULL AND `nickname` IS NULL")
+        gtest.Assert(len(args), 0)
+    })
+    // 命名参数错误在生成条件及执行查询时返回
+    gtest.Case(t, func() {
+        b := db.Table("user").Builder().Where("id=:id", g.Map{"uid" : 1})
+        _, _, err := b.Build()
+        gtest.AssertNE(err, nil)
+        _, err = db.Table("user").Where(b).All()
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestModel_Page(t *testing.T) {
+    type User struct {
+        Id       int
+        Nickname string
+    }
+    gtest.Case(t, func() {
+        users := ([]*User)(nil)
+        total := 0
+        p, err := db.Table("user").Fields("id,nickname").OrderBy("id ASC").Page(1, 2).ScanAndCount(&users, &total)
+        gtest.Assert(err,           nil)
+        gtest.Assert(total,         3)
+        gtest.Assert(len(users),    2)
+        gtest.Assert(users[0].Id,   1)
+        gtest.Assert(p.TotalPage,   2)
+        gtest.Assert(p.CurrentPage, 1)
+        gtest.Assert(p.HasMore,     true)
+
+        result := gdb.Result{}
+        p, err  = db.Table("user").OrderBy("id ASC").Page(2, 2).ScanAndCount(&result, nil)
+        gtest.Assert(err,                 nil)
+        gtest.Assert(len(result),         1)
+        gtest.Assert(result[0]["id"].Int(), 3)
+        gtest.Assert(p.TotalSize,         3)
+        gtest.Assert(p.HasMore,           false)
+    })
+    // 分组查询统计的是分组数量
+    gtest.Case(t, func() {
+        users := ([]*User)(nil)
+        total := 0
+        p, err := db.Table("user").Fields("MIN(id) AS id").GroupBy("id % 2").OrderBy("id ASC").Page(1, 1).ScanAndCount(&users, &total)
+        gtest.Assert(err,         nil)
+        gtest.Assert(len(users),  1)
+        gtest.Assert(total,       2)
+        gtest.Assert(p.TotalPage, 2)
+        gtest.Assert(p.HasMore,   true)
+    })
+    gtest.Case(t, func() {
+        users := ([]User)(nil)
+        total := 0
+        p, err := db.Table("user").OrderBy("id ASC").Page(1, 2).SkipCount().ScanAndCount(&users, &total)
+        gtest.Assert(err,         nil)
+        gtest.Assert(len(users),  2)
+        gtest.Assert(total,       -1)
+        gtest.Assert(p.TotalPage, -1)
+        gtest.Assert(p.HasMore,   true)
+
+        p, err  = db.Table("user").OrderBy("id ASC").Page(2, 2).SkipCount().ScanAndCount(&users, nil)
+        gtest.Assert(err,        nil)
+        gtest.Assert(len(users), 1)
+        gtest.Assert(p.HasMore,  false)
+    })
+}
+
+type UserScore struct {
+    Id    int
+    Uid   int
+    Score int
+}
+
+type UserDetail struct {
+    Uid     int
+    Address string
+}
+
+func TestModel_With(t *testing.T) {
+    gtest.Case(t, func() {
+        for _, sql := range []string{
+            "DROP TABLE IF EXISTS `user_detail`",
+            "CREATE TABLE `user_detail` (`uid` int(10) unsigned NOT NULL, `address` varchar(45) NOT NULL, PRIMARY KEY (`uid`))",
+            "DROP TABLE IF EXISTS `user_score`",
+            "CREATE TABLE `user_score` (`id` int(10) unsigned NOT NULL AUTO_INCREMENT, `uid` int(10) unsigned NOT NULL, `score` int(10) NOT NULL, PRIMARY KEY (`id`))",
+        } {
+            if _, err := db.Exec(sql); err != nil {
+                gtest.Fatal(err)
+            }
+        }
+        for i := 1; i <= 3; i++ {
+            _, err := db.Insert("user_detail", g.Map{"uid" : i, "address" : fmt.Sprintf("address_%d", i)})
+            gtest.Assert(err, nil)
+            for j := 1; j <= 3; j++ {
+                _, err := db.Insert("user_score", g.Map{"uid" : i, "score" : i * 10 + j})
+                gtest.Assert(err, nil)
+            }
+        }
+        type User struct {
+            Id         int
+            Nickname   string
+            UserDetail *UserDetail  `orm:"with:uid=id"`
+            UserScores []*UserScore `orm:"with:uid=id"`
+        }
+        users := make([]*User, 0)
+        err   := db.Table("user").Fields("id,nickname").OrderBy("id ASC").
+            With(UserDetail{}, "address").
+            With(UserScore{}).
+            WithCondition(UserScore{}, "score>?", 11).
+            WithOrder(UserScore{}, "score DESC").
+            Structs(&users)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(users), 3)
+        gtest.Assert(users[0].UserDetail.Address, "address_1")
+        gtest.Assert(len(users[0].UserScores), 2)
+        gtest.Assert(users[0].UserScores[0].Score, 13)
+        gtest.Assert(len(users[2].UserScores), 3)
+
+        user := new(User)
+        err   = db.Table("user").Where("id", 2).With(UserDetail{}).Struct(user)
+        gtest.Assert(err, nil)
+        gtest.Assert(user.UserDetail.Uid, 2)
+        gtest.Assert(user.UserScores == nil, true)
+    })
+}
+
+func TestModel_Shard(t *testing.T) {
+    gtest.Case(t, func() {
+        for _, table := range []string{"user_shard_0", "user_shard_1"} {
+            if _, err := db.Exec("DROP TABLE IF EXISTS `" + table + "`"); err != nil {
+                gtest.Fatal(err)
+            }
+            if _, err := db.Exec("CREATE TABLE `" + table + "` LIKE `user`"); err != nil {
+                gtest.Fatal(err)
+            }
+        }
+        db.SetShardRule("user_shard", "id", &gdb.HashShardRule{Count : 2})
+        defer db.SetShardRule("user_shard", "id", nil)
+
+        list := g.List{}
+        for i := 1; i <= 4; i++ {
+            list = append(list, g.Map{
+                "id"          : i,
+                "passport"    : fmt.Sprintf("t%d", i),
+                "password"    : "25d55ad283aa400af464c76d713c07ad",
+                "nickname"    : fmt.Sprintf("T%d", i),
+                "create_time" : gtime.Now().String(),
+            })
+        }
+        result, err := db.Table("user_shard").Data(list).Insert()
+        gtest.Assert(err, nil)
+        n, _ := result.RowsAffected()
+        gtest.Assert(n, 4)
+
+        count, err := db.Table("user_shard_1").Count()
+        gtest.Assert(err, nil)
+        gtest.Assert(count, 3)
+
+        count, err = db.Table("user_shard").Count()
+        gtest.Assert(err, nil)
+        gtest.Assert(count, 4)
+
+        record, err := db.Table("user_shard").Where("id", 3).One()
+        gtest.Assert(err, nil)
+        gtest.Assert(record["nickname"].String(), "T3")
+
+        all, err := db.Table("user_shard").OrderBy("id DESC").Limit(1, 2).All()
+        gtest.Assert(err, nil)
+        gtest.Assert(len(all), 2)
+        gtest.Assert(all[0]["id"].Int(), 3)
+        gtest.Assert(all[1]["id"].Int(), 2)
+
+        count, err = db.Table("user_shard").Shard("user_shard_0").Count()
+        gtest.Assert(err, nil)
+        gtest.Assert(count, 1)
+
+        _, err = db.Table("user_shard").Data("nickname", "T").Update()
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestModel_HashShardRule(t *testing.T) {
+    gtest.Case(t, func() {
+        rule := &gdb.HashShardRule{Count : 4}
+        for _, v := range []interface{}{3, int64(3), uint8(3), "3"} {
+            table, err := rule.Shard("user", v)
+            gtest.Assert(err, nil)
+            gtest.Assert(table, "user_3")
+        }
+        table1, err := rule.Shard("user", -10)
+        gtest.Assert(err, nil)
+        table2, err := rule.Shard("user", "-10")
+        gtest.Assert(err, nil)
+        gtest.Assert(table1, table2)
+
+        _, err = (&gdb.HashShardRule{}).Shard("user", 3)
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestModel_JsonField(t *testing.T) {
+    gtest.Case(t, func() {
+        _, err := db.Exec("DROP TABLE IF EXISTS `user_json`")
+        gtest.Assert(err, nil)
+        _, err = db.Exec(`
+        CREATE TABLE user_json (
+            id      int(10) unsigned NOT NULL AUTO_INCREMENT,
+            profile json DEFAULT NULL,
+            tags    json DEFAULT NULL,
+            extra   json DEFAULT NULL,
+            PRIMARY KEY (id)
+        ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+        `)
+        gtest.Assert(err, nil)
+        defer db.Exec("DROP TABLE IF EXISTS `user_json`")
+
+        type Profile struct {
+            Age  int
+            City string
+        }
+        type UserJson struct {
+            Id      int
+            Profile *Profile       `gdb:"json"`
+            Tags    []string       `gconv:"tags" gdb:"json"`
+            Extra   map[string]int `gdb:"json"`
+        }
+        _, err = db.Table("user_json").Data(UserJson{
+            Id      : 1,
+            Profile : &Profile{Age : 18, City : "Chengdu"},
+            Tags    : []string{"a", "b"},
+        }).Insert()
+        gtest.Assert(err, nil)
+        // 未使用标签时，根据JSON字段类型自动编码
+        _, err = db.Table("user_json").Data(g.Map{
+            "id"    : 2,
+            "tags"  : g.Slice{"c"},
+            "extra" : g.Map{"k" : 1},
+        }).Insert()
+        gtest.Assert(err, nil)
+
+        var users []UserJson
+        gtest.Assert(db.Table("user_json").OrderBy("id").Structs(&users), nil)
+        gtest.Assert(len(users), 2)
+        gtest.Assert(users[0].Profile.City, "Chengdu")
+        gtest.Assert(users[0].Tags, g.Slice{"a", "b"})
+        gtest.Assert(users[0].Extra, nil)
+        gtest.Assert(users[1].Profile, nil)
+        gtest.Assert(users[1].Tags, g.Slice{"c"})
+        gtest.Assert(users[1].Extra["k"], 1)
+
+        users[1].Profile = &Profile{Age : 20}
+        _, err = db.Table("user_json").Data(users[1]).Where("id", 2).Update()
+        gtest.Assert(err, nil)
+        user := new(UserJson)
+        gtest.Assert(db.Table("user_json").Where("id", 2).Struct(user), nil)
+        gtest.Assert(user.Profile.Age, 20)
+        // nil的JSON字段更新为NULL
+        user.Profile = nil
+        _, err = db.Table("user_json").Data(user).Where("id", 2).Update()
+        gtest.Assert(err, nil)
+        n, err := db.Table("user_json").Where("id=2 AND profile IS NULL").Count()
+        gtest.Assert(err, nil)
+        gtest.Assert(n,   1)
+    })
+}
+
 func TestModel_Delete(t *testing.T) {
     result, err := db.Table("user").Delete()
     if err != nil {
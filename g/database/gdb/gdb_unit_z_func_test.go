@@ -0,0 +1,82 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "github.com/gogf/gf/g/test/gtest"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+func Test_FormatNamedParams(t *testing.T) {
+    params := []interface{}{Map{"id" : 1, "ids" : []int{1, 2}}}
+    gtest.Case(t, func() {
+        query, args, err := formatNamedParams("SELECT * FROM user WHERE id=:id AND uid IN(:ids)", params)
+        gtest.Assert(err,   nil)
+        gtest.Assert(query, "SELECT * FROM user WHERE id=? AND uid IN(?,?)")
+        gtest.Assert(args,  []interface{}{1, 1, 2})
+        // 缺少参数及空slice时返回错误
+        _, _, err = formatNamedParams("SELECT * FROM user WHERE id=:none", params)
+        gtest.AssertNE(err, nil)
+        _, _, err = formatNamedParams("SELECT * FROM user WHERE id IN(:ids)", []interface{}{Map{"ids" : []int{}}})
+        gtest.AssertNE(err, nil)
+    })
+    // 字符串常量(包括转义及连续的引号)、引用标识符、注释及类型转换中的冒号不作为命名参数
+    gtest.Case(t, func() {
+        queries := []string {
+            `SELECT * FROM user WHERE name='it\'s :x' AND id=:id`,
+            `SELECT * FROM user WHERE name='it''s :x' AND id=:id`,
+            `SELECT * FROM user WHERE name="say \":x\"" AND id=:id`,
+            "SELECT `a:x` FROM user WHERE id=:id",
+            "SELECT * FROM user -- filter by :x\nWHERE id=:id",
+            "SELECT * FROM user /* filter by :x */ WHERE id=:id",
+            "SELECT * FROM user WHERE id=:id::int",
+        }
+        for _, query := range queries {
+            newQuery, args, err := formatNamedParams(query, params)
+            gtest.Assert(err,  nil)
+            gtest.Assert(args, []interface{}{1})
+            gtest.Assert(strings.Contains(newQuery, ":x"),   strings.Contains(query, ":x"))
+            gtest.Assert(strings.Contains(newQuery, "id=?"), true)
+        }
+        newQuery, _, _ := formatNamedParams(queries[0], params)
+        gtest.Assert(newQuery, `SELECT * FROM user WHERE name='it\'s :x' AND id=?`)
+        newQuery, _, _  = formatNamedParams(queries[1], params)
+        gtest.Assert(newQuery, `SELECT * FROM user WHERE name='it''s :x' AND id=?`)
+        newQuery, _, _  = formatNamedParams(queries[4], params)
+        gtest.Assert(newQuery, "SELECT * FROM user -- filter by :x\nWHERE id=?")
+        newQuery, _, _  = formatNamedParams(queries[5], params)
+        gtest.Assert(newQuery, "SELECT * FROM user /* filter by :x */ WHERE id=?")
+        newQuery, _, _  = formatNamedParams(queries[6], params)
+        gtest.Assert(newQuery, "SELECT * FROM user WHERE id=?::int")
+    })
+    // 只在字符串常量或者注释中出现的命名参数不作为命名参数SQL处理
+    gtest.Case(t, func() {
+        gtest.Assert(isNamedParams(`SELECT 'it\'s :x'`,    params), false)
+        gtest.Assert(isNamedParams("SELECT 1 -- :x",       params), false)
+        gtest.Assert(isNamedParams("SELECT 1 /* :x */",    params), false)
+        gtest.Assert(isNamedParams("SELECT 1 WHERE id=:id", params), true)
+    })
+}
+
+func Test_FormatPlaceholder(t *testing.T) {
+    format := func(index int) string {
+        return "$" + strconv.Itoa(index)
+    }
+    gtest.Case(t, func() {
+        gtest.Assert(formatPlaceholder("SELECT * FROM user WHERE id=? AND name=?", format), "SELECT * FROM user WHERE id=$1 AND name=$2")
+        // 连续的引号、引用标识符及注释中的'?'不做转换
+        gtest.Assert(formatPlaceholder("SELECT 'it''s ?' WHERE id=?", format),       "SELECT 'it''s ?' WHERE id=$1")
+        gtest.Assert(formatPlaceholder(`SELECT "a?b", [c?d] WHERE id=?`, format),    `SELECT "a?b", [c?d] WHERE id=$1`)
+        gtest.Assert(formatPlaceholder("SELECT 1 -- why?\nWHERE id=?", format),      "SELECT 1 -- why?\nWHERE id=$1")
+        gtest.Assert(formatPlaceholder("SELECT 1 /* why? */ WHERE id=?", format),    "SELECT 1 /* why? */ WHERE id=$1")
+        // 普通字符串常量中反斜杠不是转义字符，E'...'字符串中反斜杠为转义字符
+        gtest.Assert(formatPlaceholder(`SELECT 'C:\' WHERE id=?`, format),          `SELECT 'C:\' WHERE id=$1`)
+        gtest.Assert(formatPlaceholder(`SELECT E'it\'s ?' WHERE id=?`, format),     `SELECT E'it\'s ?' WHERE id=$1`)
+    })
+}
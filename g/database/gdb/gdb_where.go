@@ -0,0 +1,255 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "bytes"
+    "reflect"
+    "sort"
+    "strings"
+    "github.com/gogf/gf/g/internal/empty"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 查询条件构造对象，用于根据map/struct动态构造查询条件，支持OR分组及嵌套括号，例如：
+// b := db.Table("user").Builder().OmitEmpty()
+// b.Where(g.Map{"status" : 1, "name like" : name}).Or(b.New().Where("vip", 1).Where("score>", 90))
+// db.Table("user").Where(b).All()
+// 生成的条件为：(name like ? AND `status`=? OR (`vip`=? AND score> ?))
+//
+// map/struct条件的处理规则：
+// 1、键名为简单字段名称时，值为slice时生成IN条件，值为nil(包括nil指针)时生成IS NULL条件(需开启IncludeNull，否则忽略)，其他生成=条件；
+// 2、键名包含操作符时(例如："age>"、"name like")原样使用，并在后面添加占位符；
+// 3、开启OmitEmpty时，零值(0、""、false、空slice/map及nil)的键值对将被忽略；
+// 4、条件按照键名排序，以保证生成的SQL语句稳定(便于查询缓存)。
+type WhereBuilder struct {
+    db          DB
+    omitEmpty   bool              // 是否忽略零值
+    includeNull bool              // nil值是否生成IS NULL条件
+    items       []whereBuilderItem
+}
+
+// 查询条件项
+type whereBuilderItem struct {
+    operator string        // 与前一个条件的连接操作符(AND/OR)
+    where    interface{}   // 查询条件(string/map/struct/*WhereBuilder)
+    args     []interface{} // 查询条件参数
+}
+
+// 创建查询条件构造对象
+func (md *Model) Builder() *WhereBuilder {
+    return &WhereBuilder {
+        db : md.db,
+    }
+}
+
+// 创建一个新的查询条件构造对象(继承OmitEmpty/IncludeNull设置)，常用于构造嵌套的条件分组
+func (b *WhereBuilder) New() *WhereBuilder {
+    return &WhereBuilder {
+        db          : b.db,
+        omitEmpty   : b.omitEmpty,
+        includeNull : b.includeNull,
+    }
+}
+
+// 设置map/struct条件忽略零值
+func (b *WhereBuilder) OmitEmpty() *WhereBuilder {
+    b.omitEmpty = true
+    return b
+}
+
+// 设置map/struct条件中nil值生成IS NULL条件(默认忽略nil值)
+func (b *WhereBuilder) IncludeNull() *WhereBuilder {
+    b.includeNull = true
+    return b
+}
+
+// 添加AND条件，where支持string/map/struct/*WhereBuilder类型，*WhereBuilder条件将使用括号包裹
+func (b *WhereBuilder) Where(where interface{}, args...interface{}) *WhereBuilder {
+    b.items = append(b.items, whereBuilderItem{"AND", where, args})
+    return b
+}
+
+// 添加AND条件，同Where
+func (b *WhereBuilder) And(where interface{}, args...interface{}) *WhereBuilder {
+    return b.Where(where, args...)
+}
+
+// 添加OR条件，where支持string/map/struct/*WhereBuilder类型，*WhereBuilder条件将使用括号包裹
+func (b *WhereBuilder) Or(where interface{}, args...interface{}) *WhereBuilder {
+    b.items = append(b.items, whereBuilderItem{"OR", where, args})
+    return b
+}
+
+// 生成查询条件语句及参数，没有有效条件时返回空字符串，条件参数错误(例如命名参数不存在)时返回错误
+func (b *WhereBuilder) Build() (where string, args []interface{}, err error) {
+    buffer := bytes.NewBuffer(nil)
+    for _, item := range b.items {
+        itemWhere, itemArgs, err := b.buildItem(item)
+        if err != nil {
+            return "", nil, err
+        }
+        if itemWhere == "" {
+            continue
+        }
+        if buffer.Len() > 0 {
+            buffer.WriteString(" " + item.operator + " ")
+        }
+        buffer.WriteString(itemWhere)
+        args = append(args, itemArgs...)
+    }
+    return buffer.String(), args, nil
+}
+
+// 生成查询条件语句及参数，条件中包含OR连接时使用括号包裹，以便与其他条件安全地组合
+func (b *WhereBuilder) buildGroup() (where string, args []interface{}, err error) {
+    if where, args, err = b.Build(); err != nil {
+        return
+    }
+    if where != "" && b.hasOr() {
+        where = "(" + where + ")"
+    }
+    return
+}
+
+// 判断条件之间是否存在OR连接(忽略第一个有效条件的连接操作符)
+func (b *WhereBuilder) hasOr() bool {
+    for i, item := range b.items {
+        if i > 0 && item.operator == "OR" {
+            return true
+        }
+    }
+    return false
+}
+
+// 生成单个条件项的语句及参数
+func (b *WhereBuilder) buildItem(item whereBuilderItem) (string, []interface{}, error) {
+    if v, ok := item.where.(*WhereBuilder); ok {
+        where, args, err := v.Build()
+        if where != "" && len(v.items) > 1 {
+            where = "(" + where + ")"
+        }
+        return where, args, err
+    }
+    rv := reflect.ValueOf(item.where)
+    for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+        rv = rv.Elem()
+    }
+    switch rv.Kind() {
+        case reflect.Map, reflect.Struct:
+            where, args := b.buildMap(gconv.Map(item.where))
+            return where, args, nil
+    }
+    where := gconv.String(item.where)
+    if where == "" {
+        return "", nil, nil
+    }
+    // 支持Where("uid", 1)这种格式，同样按照map条件的规则处理零值及nil值
+    if len(item.args) == 1 && strings.Index(where, "?") < 0 && !isNamedParams(where, item.args) {
+        where, args := b.buildMap(map[string]interface{}{where : item.args[0]})
+        return where, args, nil
+    }
+    newWhere, newArgs, err := formatCondition(b.db, where, item.args)
+    if err != nil {
+        return "", nil, err
+    }
+    if gregex.IsMatchString(`(?i)\sOR\s`, newWhere) {
+        newWhere = "(" + newWhere + ")"
+    }
+    return newWhere, newArgs, nil
+}
+
+// 按照map/struct条件的处理规则生成条件语句及参数
+func (b *WhereBuilder) buildMap(m map[string]interface{}) (string, []interface{}) {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    charL, charR := b.db.getChars()
+    buffer       := bytes.NewBuffer(nil)
+    args         := make([]interface{}, 0, len(m))
+    for _, k := range keys {
+        value  := m[k]
+        isNull := value == nil
+        // 指针类型取其指向的值
+        rv := reflect.ValueOf(value)
+        for rv.Kind() == reflect.Ptr {
+            if rv.IsNil() {
+                isNull = true
+                break
+            }
+            rv    = rv.Elem()
+            value = rv.Interface()
+        }
+        if isNull && !b.includeNull {
+            continue
+        }
+        // nil slice表示未设置的条件(例如struct中未赋值的slice属性)，忽略
+        if rv.Kind() == reflect.Slice && rv.IsNil() {
+            continue
+        }
+        if b.omitEmpty && (isNull || empty.IsEmpty(value)) {
+            continue
+        }
+        key      := strings.TrimSpace(k)
+        isPlain  := gregex.IsMatchString(`^[\w\.]+$`, key)
+        column   := quoteWord(charL, charR, key)
+        if buffer.Len() > 0 {
+            buffer.WriteString(" AND ")
+        }
+        switch {
+            case isNull:
+                if isPlain {
+                    buffer.WriteString(column + " IS NULL")
+                } else {
+                    buffer.WriteString(column + " NULL")
+                }
+            case isPlain && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && !isBytesValue(rv):
+                if rv.Len() == 0 {
+                    // 空的IN条件不匹配任何记录
+                    buffer.WriteString("0=1")
+                    continue
+                }
+                buffer.WriteString(column + " IN(?" + strings.Repeat(",?", rv.Len() - 1) + ")")
+                for i := 0; i < rv.Len(); i++ {
+                    args = append(args, rv.Index(i).Interface())
+                }
+            case isPlain:
+                buffer.WriteString(column + "=?")
+                args = append(args, value)
+            default:
+                // 键名包含操作符，值为slice时展开为多个占位符(例如："id in"、"age between")
+                if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && !isBytesValue(rv) {
+                    if rv.Len() == 0 {
+                        buffer.WriteString("0=1")
+                        continue
+                    }
+                    placeholders := make([]string, rv.Len())
+                    for i := 0; i < rv.Len(); i++ {
+                        placeholders[i] = "?"
+                        args            = append(args, rv.Index(i).Interface())
+                    }
+                    if strings.HasSuffix(strings.ToUpper(key), " BETWEEN") {
+                        buffer.WriteString(key + " " + strings.Join(placeholders, " AND "))
+                    } else {
+                        buffer.WriteString(key + "(" + strings.Join(placeholders, ",") + ")")
+                    }
+                } else {
+                    buffer.WriteString(key + " ?")
+                    args = append(args, value)
+                }
+        }
+    }
+    return buffer.String(), args
+}
+
+// 判断是否为[]byte类型的值(作为单个参数处理)
+func isBytesValue(rv reflect.Value) bool {
+    return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8
+}
@@ -0,0 +1,275 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// 关联数据预加载配置，对应struct中带有orm:"with:关联字段=当前字段"标签的属性，例如：
+// type User struct {
+//     Id         int
+//     Name       string
+//     UserDetail *UserDetail   `orm:"with:uid=id"`
+//     UserScores []*UserScore  `orm:"with:uid=id"`
+// }
+// 关联数据表名称优先使用关联struct的TableName()方法返回值，否则为struct名称的蛇形格式(UserDetail => user_detail)。
+type withConfig struct {
+    typ     reflect.Type  // 关联struct类型
+    fields  string        // 查询字段，为空时查询所有字段
+    wheres  []withWhere   // 附加的查询条件
+    orderBy string        // 排序语句
+}
+
+// 关联数据的附加查询条件
+type withWhere struct {
+    where interface{}
+    args  []interface{}
+}
+
+// 关联数据表名称接口
+type apiTableName interface {
+    TableName() string
+}
+
+// 链式操作，预加载关联数据，object为关联struct对象(或者指针)，fields为关联数据的查询字段(可选)。
+// 关联数据在Struct/Structs方法中按照关联关系批量查询(每个关联关系只执行一条查询)并赋值到对应的属性上。
+func (md *Model) With(object interface{}, fields...string) *Model {
+    model  := md.Clone()
+    config := model.cloneWithConfig(object)
+    if len(fields) > 0 {
+        config.fields = fields[0]
+    }
+    return model
+}
+
+// 链式操作，设置预加载关联数据的查询条件，多次调用时使用AND连接，未调用With时自动预加载该关联数据
+func (md *Model) WithCondition(object interface{}, where interface{}, args...interface{}) *Model {
+    model  := md.Clone()
+    config := model.cloneWithConfig(object)
+    config.wheres = append(config.wheres, withWhere{where : where, args : args})
+    return model
+}
+
+// 链式操作，设置预加载关联数据的排序语句，排序对每条记录的关联数据列表生效
+func (md *Model) WithOrder(object interface{}, orderBy string) *Model {
+    model  := md.Clone()
+    config := model.cloneWithConfig(object)
+    config.orderBy = orderBy
+    return model
+}
+
+// 复制当前的预加载配置(避免修改原有链式对象)，并返回指定关联类型的配置(不存在时新增)
+func (md *Model) cloneWithConfig(object interface{}) *withConfig {
+    typ    := getWithType(reflect.TypeOf(object))
+    withs  := make([]*withConfig, len(md.withs))
+    result := (*withConfig)(nil)
+    for i, config := range md.withs {
+        c       := *config
+        c.wheres = append([]withWhere{}, config.wheres...)
+        withs[i] = &c
+        if c.typ == typ {
+            result = withs[i]
+        }
+    }
+    if result == nil {
+        result = &withConfig{typ : typ}
+        withs  = append(withs, result)
+    }
+    md.withs = withs
+    return result
+}
+
+// 获得关联类型的struct类型(去掉指针及slice)
+func getWithType(typ reflect.Type) reflect.Type {
+    for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+        typ = typ.Elem()
+    }
+    return typ
+}
+
+// 获得指定关联类型的预加载配置
+func (md *Model) getWithConfig(typ reflect.Type) *withConfig {
+    typ = getWithType(typ)
+    for _, config := range md.withs {
+        if config.typ == typ {
+            return config
+        }
+    }
+    return nil
+}
+
+// 获得关联struct对应的数据表名称
+func getWithTableName(typ reflect.Type) string {
+    if v, ok := reflect.New(typ).Interface().(apiTableName); ok {
+        return v.TableName()
+    }
+    name   := typ.Name()
+    buffer := make([]byte, 0, len(name) + 4)
+    for i := 0; i < len(name); i++ {
+        c := name[i]
+        if c >= 'A' && c <= 'Z' {
+            if i > 0 && !(name[i - 1] >= 'A' && name[i - 1] <= 'Z') {
+                buffer = append(buffer, '_')
+            }
+            c += 'a' - 'A'
+        }
+        buffer = append(buffer, c)
+    }
+    return string(buffer)
+}
+
+// 解析关联标签(with:关联字段=当前字段)，省略当前字段时与关联字段同名
+func parseWithTag(tag string) (relatedKey, key string, ok bool) {
+    for _, item := range strings.Split(tag, ",") {
+        item = strings.TrimSpace(item)
+        if !strings.HasPrefix(item, "with:") {
+            continue
+        }
+        array := strings.Split(strings.TrimPrefix(item, "with:"), "=")
+        relatedKey = strings.TrimSpace(array[0])
+        key        = relatedKey
+        if len(array) > 1 {
+            key = strings.TrimSpace(array[1])
+        }
+        return relatedKey, key, relatedKey != ""
+    }
+    return "", "", false
+}
+
+// 按照预加载配置加载关联数据，items为struct对象(可寻址的reflect.Value)列表，result为items对应的查询结果
+func (md *Model) loadWith(items []reflect.Value, result Result) error {
+    if len(md.withs) == 0 || len(items) == 0 {
+        return nil
+    }
+    itemType := items[0].Type()
+    for i := 0; i < itemType.NumField(); i++ {
+        field := itemType.Field(i)
+        relatedKey, key, ok := parseWithTag(field.Tag.Get("orm"))
+        if !ok {
+            continue
+        }
+        config := md.getWithConfig(field.Type)
+        if config == nil {
+            continue
+        }
+        if err := md.loadWithField(items, result, i, relatedKey, key, config); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 批量查询指定属性的关联数据，并按照关联字段的值赋值到每个struct对象上
+func (md *Model) loadWithField(items []reflect.Value, result Result, index int, relatedKey, key string, config *withConfig) error {
+    // 收集当前记录的关联字段值(去重)
+    values := make([]interface{}, 0, len(result))
+    exists := make(map[string]struct{}, len(result))
+    for _, record := range result {
+        value, ok := record[key]
+        if !ok {
+            return errors.New(fmt.Sprintf(`field "%s" is required in the query result for preloading "%s"`, key, config.typ.Name()))
+        }
+        if value.IsNil() {
+            continue
+        }
+        if _, ok := exists[value.String()]; !ok {
+            exists[value.String()] = struct{}{}
+            values = append(values, value.Val())
+        }
+    }
+    if len(values) == 0 {
+        return nil
+    }
+    // 构造关联数据的查询，关联数据的预加载配置(除当前关联外)继续传递，以支持多层级的关联预加载
+    charL, charR := md.db.getChars()
+    table        := getWithTableName(config.typ)
+    model        := (*Model)(nil)
+    if md.tx != nil {
+        model = md.tx.Table(table)
+    } else {
+        model = md.db.Table(table)
+    }
+    model.linkType = md.linkType
+    if config.fields != "" {
+        fields := config.fields
+        if !withFieldExists(fields, relatedKey) {
+            fields += "," + relatedKey
+        }
+        model = model.Fields(fields)
+    }
+    model = model.Where(quoteWord(charL, charR, relatedKey) + " IN(?)", values)
+    for _, w := range config.wheres {
+        model = model.And(w.where, w.args...)
+    }
+    if config.orderBy != "" {
+        model = model.OrderBy(config.orderBy)
+    }
+    for _, c := range md.withs {
+        if c != config {
+            model.withs = append(model.withs, c)
+        }
+    }
+    relatedResult, err := model.All()
+    if err != nil {
+        return err
+    }
+    // 将关联数据转换为struct对象，并按照关联字段值进行分组
+    relatedItems := make([]reflect.Value, len(relatedResult))
+    groups       := make(map[string][]reflect.Value)
+    for i, record := range relatedResult {
+        relatedItems[i] = reflect.New(config.typ).Elem()
+        if err := record.ToStruct(relatedItems[i].Addr().Interface()); err != nil {
+            return err
+        }
+        k        := record[relatedKey].String()
+        groups[k] = append(groups[k], relatedItems[i])
+    }
+    if err := model.loadWith(relatedItems, relatedResult); err != nil {
+        return err
+    }
+    // 关联数据赋值
+    for i, item := range items {
+        group := groups[result[i][key].String()]
+        if len(group) == 0 {
+            continue
+        }
+        fieldValue := item.Field(index)
+        fieldType  := fieldValue.Type()
+        switch fieldType.Kind() {
+            case reflect.Slice:
+                slice := reflect.MakeSlice(fieldType, len(group), len(group))
+                for j, v := range group {
+                    if fieldType.Elem().Kind() == reflect.Ptr {
+                        slice.Index(j).Set(v.Addr())
+                    } else {
+                        slice.Index(j).Set(v)
+                    }
+                }
+                fieldValue.Set(slice)
+            case reflect.Ptr:
+                fieldValue.Set(group[0].Addr())
+            case reflect.Struct:
+                fieldValue.Set(group[0])
+        }
+    }
+    return nil
+}
+
+// 判断查询字段中是否包含指定字段(忽略标识符引用符号及大小写)
+func withFieldExists(fields string, field string) bool {
+    for _, v := range strings.Split(fields, ",") {
+        v = strings.Trim(strings.TrimSpace(v), "`\"[]")
+        if v == "*" || strings.EqualFold(v, field) {
+            return true
+        }
+    }
+    return false
+}
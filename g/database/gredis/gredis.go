@@ -11,125 +11,275 @@
 package gredis
 
 import (
+    "crypto/sha256"
+    "crypto/tls"
+    "strings"
     "time"
     "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
     "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gtype"
     "fmt"
 )
 
 const (
     gDEFAULT_POOL_MAX_IDLE      = 1
-    gDEFAULT_POOL_MAX_ACTIVE    = 10
     gDEFAULT_POOL_IDLE_TIMEOUT  = 180 * time.Second
     gDEFAULT_POOL_MAX_LIFE_TIME = 60  * time.Second
 )
 
 // Redis客户端
 type Redis struct {
-    pool *redis.Pool
+    pool         *redis.Pool
+    cluster      *cluster     // 集群模式下的集群客户端
+    waitCount    *gtype.Int64 // 获取连接时等待的总次数
+    waitDuration *gtype.Int64 // 获取连接时等待的总时长(纳秒)
 }
 
 // Redis服务端但节点连接配置信息
 type Config struct {
-    Host string // IP/域名
-    Port int    // 端口
-    Db   int    // db
-    Pass string // 密码
+    Host            string        // IP/域名
+    Port            int           // 端口
+    Db              int           // db
+    User            string        // (可选)Redis 6 ACL用户名，为空时使用default用户(仅使用Pass认证)
+    Pass            string        // 密码
+    MaxIdle         int           // (可选)连接池最大空闲连接数，默认为1
+    MaxActive       int           // (可选)连接池最大连接数，默认为0表示不限制
+    IdleTimeout     time.Duration // (可选)空闲连接的超时时间，超时后关闭，默认为180秒
+    MaxConnLifetime time.Duration // (可选)连接的最长存活时间，默认为60秒
+    Wait            bool          // (可选)连接数达到MaxActive时是否等待空闲连接，默认为false(直接返回错误)
+    TestOnBorrow    time.Duration // (可选)从连接池获取空闲时长超过该值的连接时执行PING检测，默认为0表示每次获取都执行检测
+    Cluster         []string      // (可选)集群模式的种子节点地址列表(host:port)，设置后Host/Port配置无效
+    Sentinel        []string      // (可选)Sentinel节点地址列表(host:port)，设置后通过Sentinel获取主节点地址，Host/Port配置无效
+    MasterName      string        // (可选)Sentinel模式下监控的主节点名称
+    TLS             bool          // (可选)是否使用TLS连接(rediss://)
+    TLSSkipVerify   bool          // (可选)TLS连接时是否跳过服务端证书校验
+    TLSCaFile       string        // (可选)TLS连接时用于校验服务端证书的CA证书文件路径，为空时使用系统CA
+    TLSCertFile     string        // (可选)TLS双向认证时的客户端证书文件路径
+    TLSKeyFile      string        // (可选)TLS双向认证时的客户端私钥文件路径
+    TLSServerName   string        // (可选)TLS连接时校验的服务端名称，为空时使用连接地址中的主机名
+    TLSConfig       *tls.Config   // (可选)自定义的TLS配置，设置后以上TLS证书相关配置无效
 }
 
 // Redis链接池统计信息
 type PoolStats struct {
     redis.PoolStats
+    WaitCount    int64         // 获取连接时等待的总次数(Wait为true并且连接数达到MaxActive时)
+    WaitDuration time.Duration // 获取连接时等待的总时长
 }
 
 // 连接池map
 var pools = gmap.NewStringInterfaceMap()
 
-// 创建redis操作对象.
+// 创建redis操作对象，完全相同的配置共享同一个连接池.
 func New(config Config) *Redis {
-    r       := &Redis{}
-    poolKey := fmt.Sprintf("%s:%d,%d", config.Host, config.Port, config.Db)
-    if v := pools.Get(poolKey); v == nil {
-        pool := &redis.Pool {
-            MaxIdle         : gDEFAULT_POOL_MAX_IDLE,
-            MaxActive       : gDEFAULT_POOL_MAX_ACTIVE,
-            IdleTimeout     : gDEFAULT_POOL_IDLE_TIMEOUT,
-            MaxConnLifetime : gDEFAULT_POOL_MAX_LIFE_TIME,
-            Dial            : func() (redis.Conn, error) {
-                c, err := redis.Dial("tcp", fmt.Sprintf("%s:%d", config.Host, config.Port))
-                if err != nil {
-                    return nil, err
-                }
-                if len(config.Pass) > 0 {
-                    if _, err := c.Do("AUTH", config.Pass); err != nil {
-                        return nil, err
-                    }
-                }
-                if _, err := c.Do("SELECT", config.Db); err != nil {
-                    return nil, err
-                }
-                return c, nil
-            },
-            // 用来测试连接是否可用
-            TestOnBorrow: func(c redis.Conn, t time.Time) error {
-			    _, err := c.Do("PING")
-			    return err
-		    },
-        }
-        pools.Set(poolKey, pool)
-        r.pool = pool
-    } else {
-        r.pool = v.(*redis.Pool)
+    v := pools.GetOrSetFuncLock(getPoolKey(config), func() interface{} {
+        return newRedis(config)
+    })
+    return v.(*Redis)
+}
+
+// 根据配置创建连接池及redis操作对象
+func newRedis(config Config) *Redis {
+    if config.MaxIdle == 0 {
+        config.MaxIdle = gDEFAULT_POOL_MAX_IDLE
+    }
+    if config.IdleTimeout == 0 {
+        config.IdleTimeout = gDEFAULT_POOL_IDLE_TIMEOUT
+    }
+    if config.MaxConnLifetime == 0 {
+        config.MaxConnLifetime = gDEFAULT_POOL_MAX_LIFE_TIME
+    }
+    r := &Redis {
+        waitCount    : gtype.NewInt64(),
+        waitDuration : gtype.NewInt64(),
+    }
+    switch {
+        case len(config.Cluster) > 0:
+            // 集群模式下默认连接池为第一个种子节点的连接池，用于订阅等不区分节点的操作
+            r.cluster = newCluster(config)
+            r.pool    = r.cluster.getPool(config.Cluster[0])
+        case len(config.Sentinel) > 0:
+            s     := newSentinel(config)
+            r.pool = newPool(config, s.dial, checkMasterRole)
+        default:
+            address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+            r.pool   = newPool(config, func() (redis.Conn, error) {
+                return dial(config, address)
+            }, nil)
     }
     return r
 }
 
+// 生成配置对应的连接池键名，任意配置不同(例如密码、连接池参数)时使用不同的连接池，
+// 密码及TLS配置只以哈希值的形式出现在键名中，避免键名泄露敏感信息(自定义的TLSConfig按照对象区分)。
+func getPoolKey(config Config) string {
+    secret := sha256.Sum256([]byte(fmt.Sprintf("%s|%t|%t|%s|%s|%s|%s|%p",
+        config.Pass, config.TLS, config.TLSSkipVerify, config.TLSCaFile, config.TLSCertFile,
+        config.TLSKeyFile, config.TLSServerName, config.TLSConfig,
+    )))
+    return fmt.Sprintf("%s:%d/%d?user=%s&max_idle=%d&max_active=%d&idle_timeout=%s&max_conn_lifetime=%s&wait=%t&test_on_borrow=%s&cluster=%s&sentinel=%s&master_name=%s&secret=%x",
+        config.Host, config.Port, config.Db, config.User, config.MaxIdle, config.MaxActive,
+        config.IdleTimeout, config.MaxConnLifetime, config.Wait, config.TestOnBorrow,
+        strings.Join(config.Cluster, ","), strings.Join(config.Sentinel, ","), config.MasterName, secret,
+    )
+}
+
+// 创建连接池，dial为创建连接的方法，check为获取连接时的可用性检测方法(为nil时使用PING检测)
+func newPool(config Config, dial func() (redis.Conn, error), check func(c redis.Conn) error) *redis.Pool {
+    return &redis.Pool {
+        MaxIdle         : config.MaxIdle,
+        MaxActive       : config.MaxActive,
+        IdleTimeout     : config.IdleTimeout,
+        MaxConnLifetime : config.MaxConnLifetime,
+        Wait            : config.Wait,
+        Dial            : dial,
+        // 用来测试连接是否可用，t为连接放回连接池的时间，
+        // 空闲时长未超过TestOnBorrow配置的连接不做检测，避免每次获取连接都增加一次网络往返
+        TestOnBorrow : func(c redis.Conn, t time.Time) error {
+            if config.TestOnBorrow > 0 && time.Since(t) < config.TestOnBorrow {
+                return nil
+            }
+            if check != nil {
+                return check(c)
+            }
+            _, err := c.Do("PING")
+            return err
+        },
+    }
+}
+
+// 连接指定地址(host:port)的redis服务端，并执行认证及db选择
+func dial(config Config, address string) (redis.Conn, error) {
+    options, err := getDialOptions(config)
+    if err != nil {
+        return nil, err
+    }
+    c, err := redis.Dial("tcp", address, options...)
+    if err != nil {
+        return nil, err
+    }
+    if len(config.Pass) > 0 {
+        // 设置了ACL用户名时使用Redis 6的AUTH username password认证方式
+        args := []interface{}{config.Pass}
+        if len(config.User) > 0 {
+            args = []interface{}{config.User, config.Pass}
+        }
+        if _, err := c.Do("AUTH", args...); err != nil {
+            c.Close()
+            return nil, err
+        }
+    }
+    // 集群模式只支持db 0，不需要执行SELECT
+    if len(config.Cluster) == 0 {
+        if _, err := c.Do("SELECT", config.Db); err != nil {
+            c.Close()
+            return nil, err
+        }
+    }
+    return c, nil
+}
+
+// 获得所有的连接池(集群模式下为所有节点的连接池)
+func (r *Redis) getPools() []*redis.Pool {
+    if r.cluster != nil {
+        return r.cluster.getPools()
+    }
+    return []*redis.Pool{r.pool}
+}
+
 // 关闭redis管理对象，将会关闭底层的
 func (r *Redis) Close() error {
-    return r.pool.Close()
+    err := error(nil)
+    for _, pool := range r.getPools() {
+        if e := pool.Close(); e != nil {
+            err = e
+        }
+    }
+    return err
 }
 
 // 获得一个原生的redis连接对象，用于自定义连接操作，
 // 但是需要注意的是如果不再使用该连接对象时，需要手动Close连接，否则会造成连接数超限。
 func (r *Redis) GetConn() redis.Conn {
+    // 集群模式下返回按照键名路由到对应节点的连接对象
+    if r.cluster != nil {
+        return newClusterConn(r.cluster)
+    }
+    // 开启了等待并且使用中的连接数已达上限(没有可用的空闲连接)时，记录等待的次数及时长，
+    // ActiveCount包含空闲连接，因此需要减去IdleCount，两者从同一份统计信息中获取
+    if r.pool.Wait && r.pool.MaxActive > 0 {
+        if stats := r.pool.Stats(); stats.ActiveCount - stats.IdleCount < r.pool.MaxActive {
+            return r.pool.Get()
+        }
+        start := time.Now()
+        conn  := r.pool.Get()
+        r.waitCount.Add(1)
+        r.waitDuration.Add(int64(time.Since(start)))
+        return conn
+    }
     return r.pool.Get()
 }
 
 // 设置属性 - MaxIdle
 func (r *Redis) SetMaxIdle(value int) {
-    r.pool.MaxIdle = value
+    for _, pool := range r.getPools() {
+        pool.MaxIdle = value
+    }
 }
 
 // 设置属性 - MaxActive
 func (r *Redis) SetMaxActive(value int) {
-    r.pool.MaxActive = value
+    for _, pool := range r.getPools() {
+        pool.MaxActive = value
+    }
 }
 
 // 设置属性 - IdleTimeout
 func (r *Redis) SetIdleTimeout(value time.Duration) {
-    r.pool.IdleTimeout = value
+    for _, pool := range r.getPools() {
+        pool.IdleTimeout = value
+    }
 }
 
 // 设置属性 - MaxConnLifetime
 func (r *Redis) SetMaxConnLifetime(value time.Duration) {
-    r.pool.MaxConnLifetime = value
+    for _, pool := range r.getPools() {
+        pool.MaxConnLifetime = value
+    }
+}
+
+// 设置属性 - Wait
+func (r *Redis) SetWait(value bool) {
+    for _, pool := range r.getPools() {
+        pool.Wait = value
+    }
 }
 
 // 获取当前连接池统计信息
 func (r *Redis) Stats() *PoolStats {
-    return &PoolStats{r.pool.Stats()}
+    stats := &PoolStats {
+        WaitCount    : r.waitCount.Val(),
+        WaitDuration : time.Duration(r.waitDuration.Val()),
+    }
+    // 集群模式下为所有节点连接池的统计总和
+    for _, pool := range r.getPools() {
+        s := pool.Stats()
+        stats.ActiveCount += s.ActiveCount
+        stats.IdleCount   += s.IdleCount
+    }
+    return stats
 }
 
 // 执行同步命令 - Do
 func (r *Redis) Do(command string, args ...interface{}) (interface{}, error) {
-    conn := r.pool.Get()
+    conn := r.GetConn()
     defer conn.Close()
     return conn.Do(command, args...)
 }
 
 // 执行异步命令 - Send
 func (r *Redis) Send(command string, args ...interface{}) error {
-    conn := r.pool.Get()
+    conn := r.GetConn()
     defer conn.Close()
     return conn.Send(command, args...)
 }
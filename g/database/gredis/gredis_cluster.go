@@ -0,0 +1,284 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "errors"
+    "fmt"
+    "net"
+    "strconv"
+    "strings"
+    "sync"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/util/gconv"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+const (
+    gCLUSTER_SLOT_COUNT    = 16384 // 集群槽位数量
+    gCLUSTER_MAX_REDIRECTS = 5     // 单条命令最大的MOVED/ASK重定向次数
+)
+
+// 集群客户端，维护槽位与主节点的映射关系，每个节点使用独立的连接池
+type cluster struct {
+    config     Config
+    pools      *gmap.StringInterfaceMap // 节点地址 => *redis.Pool
+    mu         sync.RWMutex
+    slots      []string                 // 槽位对应的主节点地址
+    refreshing *gtype.Bool              // 是否正在刷新槽位映射
+}
+
+// 集群命令
+type clusterCommand struct {
+    command string
+    args    []interface{}
+}
+
+// 集群命令执行结果
+type clusterReply struct {
+    reply interface{}
+    err   error
+}
+
+// 创建集群客户端，并从种子节点加载槽位映射(加载失败时首先使用种子节点，后续遇到重定向时再次加载)
+func newCluster(config Config) *cluster {
+    c := &cluster {
+        config     : config,
+        pools      : gmap.NewStringInterfaceMap(),
+        slots      : make([]string, gCLUSTER_SLOT_COUNT),
+        refreshing : gtype.NewBool(),
+    }
+    c.refresh()
+    return c
+}
+
+// 获得指定节点的连接池，不存在时创建
+func (c *cluster) getPool(address string) *redis.Pool {
+    return c.pools.GetOrSetFuncLock(address, func() interface{} {
+        return newPool(c.config, func() (redis.Conn, error) {
+            return dial(c.config, address)
+        }, nil)
+    }).(*redis.Pool)
+}
+
+// 获得所有节点的连接池
+func (c *cluster) getPools() []*redis.Pool {
+    values := c.pools.Values()
+    pools  := make([]*redis.Pool, len(values))
+    for i, v := range values {
+        pools[i] = v.(*redis.Pool)
+    }
+    return pools
+}
+
+// 依次从种子节点及已知节点加载槽位映射，任一节点加载成功即返回
+func (c *cluster) refresh() error {
+    err := error(nil)
+    for _, address := range append(append([]string{}, c.config.Cluster...), c.pools.Keys()...) {
+        slots, e := c.loadSlots(address)
+        if e != nil {
+            err = e
+            continue
+        }
+        c.mu.Lock()
+        c.slots = slots
+        c.mu.Unlock()
+        return nil
+    }
+    return err
+}
+
+// 异步刷新槽位映射，同一时间只执行一次刷新
+func (c *cluster) refreshAsync() {
+    if c.refreshing.Set(true) {
+        return
+    }
+    go func() {
+        defer c.refreshing.Set(false)
+        c.refresh()
+    }()
+}
+
+// 通过CLUSTER SLOTS命令从指定节点加载槽位映射
+func (c *cluster) loadSlots(address string) ([]string, error) {
+    conn := c.getPool(address).Get()
+    defer conn.Close()
+    values, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+    if err != nil {
+        return nil, err
+    }
+    slots := make([]string, gCLUSTER_SLOT_COUNT)
+    for _, value := range values {
+        item, _ := redis.Values(value, nil)
+        if len(item) < 3 {
+            return nil, errors.New(fmt.Sprintf(`invalid CLUSTER SLOTS reply from "%s"`, address))
+        }
+        node, _ := redis.Values(item[2], nil)
+        if len(node) < 2 {
+            return nil, errors.New(fmt.Sprintf(`invalid CLUSTER SLOTS reply from "%s"`, address))
+        }
+        start, _ := redis.Int(item[0], nil)
+        end, _   := redis.Int(item[1], nil)
+        host, _  := redis.String(node[0], nil)
+        port, _  := redis.Int(node[1], nil)
+        // 节点未配置cluster-announce-ip时可能返回空的IP，这时使用当前节点的IP
+        if host == "" {
+            host, _, _ = net.SplitHostPort(address)
+        }
+        nodeAddress := net.JoinHostPort(host, strconv.Itoa(port))
+        for i := start; i <= end && i < gCLUSTER_SLOT_COUNT; i++ {
+            slots[i] = nodeAddress
+        }
+    }
+    return slots, nil
+}
+
+// 获得槽位对应的节点地址，槽位未知(或者命令不包含键名)时使用第一个种子节点
+func (c *cluster) getAddress(slot int) string {
+    if slot >= 0 {
+        c.mu.RLock()
+        address := c.slots[slot]
+        c.mu.RUnlock()
+        if address != "" {
+            return address
+        }
+    }
+    return c.config.Cluster[0]
+}
+
+// 更新单个槽位对应的节点地址(收到MOVED重定向时)
+func (c *cluster) setAddress(slot int, address string) {
+    if slot < 0 || slot >= gCLUSTER_SLOT_COUNT {
+        return
+    }
+    c.mu.Lock()
+    c.slots[slot] = address
+    c.mu.Unlock()
+}
+
+// 在键名对应的节点上执行命令，并自动处理MOVED/ASK重定向
+func (c *cluster) do(command string, args []interface{}) (interface{}, error) {
+    address := c.getAddress(commandSlot(command, args))
+    asking  := false
+    for i := 0; ; i++ {
+        conn := c.getPool(address).Get()
+        if asking {
+            conn.Send("ASKING")
+        }
+        reply, err := conn.Do(command, args...)
+        conn.Close()
+        if err == nil || i >= gCLUSTER_MAX_REDIRECTS {
+            return reply, err
+        }
+        if address, asking = c.handleError(err); address == "" {
+            return reply, err
+        }
+    }
+}
+
+// 批量执行命令，命令按照节点分组后每个节点只需要一次网络往返，结果按照命令顺序返回
+func (c *cluster) doBatch(commands []clusterCommand) []clusterReply {
+    replies := make([]clusterReply, len(commands))
+    groups  := make(map[string][]int)
+    for i, cmd := range commands {
+        address        := c.getAddress(commandSlot(cmd.command, cmd.args))
+        groups[address] = append(groups[address], i)
+    }
+    for address, indexes := range groups {
+        conn := c.getPool(address).Get()
+        for _, i := range indexes {
+            conn.Send(commands[i].command, commands[i].args...)
+        }
+        err := conn.Flush()
+        for _, i := range indexes {
+            if err != nil {
+                replies[i].err = err
+                continue
+            }
+            replies[i].reply, replies[i].err = conn.Receive()
+        }
+        conn.Close()
+    }
+    // 重定向或者失败的命令单独重试
+    for i, reply := range replies {
+        if reply.err == nil {
+            continue
+        }
+        if address, _ := c.handleError(reply.err); address != "" {
+            replies[i].reply, replies[i].err = c.do(commands[i].command, commands[i].args)
+        }
+    }
+    return replies
+}
+
+// 处理命令执行错误，如果是MOVED/ASK重定向错误，返回重定向的节点地址，
+// 遇到MOVED重定向或者网络错误时异步刷新槽位映射
+func (c *cluster) handleError(err error) (address string, asking bool) {
+    e, ok := err.(redis.Error)
+    if !ok {
+        c.refreshAsync()
+        return "", false
+    }
+    // 格式：MOVED 3999 127.0.0.1:6381 或者 ASK 3999 127.0.0.1:6381
+    array := strings.Fields(string(e))
+    if len(array) != 3 {
+        return "", false
+    }
+    switch array[0] {
+        case "MOVED":
+            slot, _ := strconv.Atoi(array[1])
+            c.setAddress(slot, array[2])
+            c.refreshAsync()
+            return array[2], false
+        case "ASK":
+            return array[2], true
+    }
+    return "", false
+}
+
+// 获得命令对应的槽位，命令不包含键名时返回-1
+func commandSlot(command string, args []interface{}) int {
+    switch strings.ToUpper(command) {
+        case "EVAL", "EVALSHA":
+            // EVAL script numkeys key [key ...] arg [arg ...]
+            if len(args) > 2 && gconv.Int(args[1]) > 0 {
+                return hashSlot(gconv.String(args[2]))
+            }
+            return -1
+    }
+    if len(args) == 0 {
+        return -1
+    }
+    return hashSlot(gconv.String(args[0]))
+}
+
+// 计算键名对应的槽位，键名包含{hash tag}时只使用hash tag计算，以便多个键分配到同一个槽位
+func hashSlot(key string) int {
+    if start := strings.IndexByte(key, '{'); start >= 0 {
+        if end := strings.IndexByte(key[start + 1:], '}'); end > 0 {
+            key = key[start + 1 : start + 1 + end]
+        }
+    }
+    return int(crc16(key) % gCLUSTER_SLOT_COUNT)
+}
+
+// CRC16(XMODEM)校验值计算，与redis集群的槽位算法一致
+func crc16(s string) uint16 {
+    crc := uint16(0)
+    for i := 0; i < len(s); i++ {
+        crc ^= uint16(s[i]) << 8
+        for j := 0; j < 8; j++ {
+            if crc & 0x8000 != 0 {
+                crc = crc << 1 ^ 0x1021
+            } else {
+                crc <<= 1
+            }
+        }
+    }
+    return crc
+}
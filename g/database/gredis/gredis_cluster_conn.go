@@ -0,0 +1,201 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "errors"
+    "strings"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+// 集群模式下的连接对象，实现了redis.Conn接口，命令按照键名路由到对应的节点执行。
+// 通过Send写入的命令在Flush时按照节点分组批量执行；
+// WATCH/MULTI开启的事务固定使用第一个键名所在节点的连接，因此事务中的键名需要位于同一个槽位(可使用{hash tag})。
+type clusterConn struct {
+    cluster *cluster
+    conn    redis.Conn       // 事务中固定使用的节点连接
+    multi   bool             // 是否已开启事务(MULTI)
+    skip    int              // 固定连接上需要忽略的结果数量(延迟发送的MULTI命令)
+    pending []clusterCommand // 未固定节点时通过Send缓存的命令
+    replies []clusterReply   // 已执行但尚未通过Receive读取的结果
+}
+
+// 创建集群连接对象
+func newClusterConn(c *cluster) *clusterConn {
+    return &clusterConn {
+        cluster : c,
+    }
+}
+
+// 固定使用槽位所在节点的连接，固定之前缓存的命令会先执行
+func (c *clusterConn) pin(slot int) error {
+    if c.conn != nil {
+        return nil
+    }
+    if err := c.Flush(); err != nil {
+        return err
+    }
+    c.conn = c.cluster.getPool(c.cluster.getAddress(slot)).Get()
+    return c.conn.Err()
+}
+
+// 释放固定的节点连接
+func (c *clusterConn) unpin() {
+    if c.conn != nil {
+        c.conn.Close()
+        c.conn = nil
+    }
+    c.multi = false
+    c.skip  = 0
+}
+
+// 判断命令是否需要固定节点连接，如果是则固定连接(事务中第一个包含键名的命令需要先发送MULTI)
+func (c *clusterConn) prepare(command string, args []interface{}) (handled bool, reply interface{}, err error) {
+    switch command {
+        case "WATCH":
+            err = c.pin(commandSlot(command, args))
+        case "MULTI":
+            if c.conn == nil {
+                c.multi = true
+                return true, "OK", nil
+            }
+            c.multi = true
+        case "EXEC", "DISCARD":
+            if c.conn == nil {
+                if !c.multi {
+                    return true, nil, redis.Error("ERR " + command + " without MULTI")
+                }
+                c.multi = false
+                if command == "EXEC" {
+                    return true, []interface{}{}, nil
+                }
+                return true, "OK", nil
+            }
+        default:
+            if c.multi && c.conn == nil {
+                if err = c.pin(commandSlot(command, args)); err == nil {
+                    if err = c.conn.Send("MULTI"); err == nil {
+                        c.skip++
+                    }
+                }
+            }
+    }
+    return err != nil, nil, err
+}
+
+// 执行同步命令，会读取并丢弃之前通过Send写入的命令结果
+func (c *clusterConn) Do(command string, args...interface{}) (interface{}, error) {
+    command = strings.ToUpper(command)
+    if command == "" {
+        if c.conn != nil {
+            c.replies, c.skip = nil, 0
+            return c.conn.Do("")
+        }
+        if err := c.Flush(); err != nil {
+            return nil, err
+        }
+        last := clusterReply{}
+        if len(c.replies) > 0 {
+            last = c.replies[len(c.replies) - 1]
+        }
+        c.replies = nil
+        return last.reply, last.err
+    }
+    if handled, reply, err := c.prepare(command, args); handled {
+        return reply, err
+    }
+    c.replies = nil
+    if c.conn != nil {
+        c.skip = 0
+        reply, err := c.conn.Do(command, args...)
+        switch command {
+            case "EXEC", "DISCARD":
+                c.unpin()
+            case "UNWATCH":
+                if !c.multi {
+                    c.unpin()
+                }
+        }
+        return reply, err
+    }
+    if err := c.Flush(); err != nil {
+        return nil, err
+    }
+    c.replies = nil
+    return c.cluster.do(command, args)
+}
+
+// 写入命令，未固定节点时缓存到Flush时批量执行
+func (c *clusterConn) Send(command string, args...interface{}) error {
+    command = strings.ToUpper(command)
+    if handled, reply, err := c.prepare(command, args); handled {
+        if err != nil {
+            return err
+        }
+        c.replies = append(c.replies, clusterReply{reply : reply})
+        return nil
+    }
+    if c.conn != nil {
+        return c.conn.Send(command, args...)
+    }
+    c.pending = append(c.pending, clusterCommand{command : command, args : args})
+    return nil
+}
+
+// 执行缓存的命令(未固定节点时)或者将固定连接的缓冲区发送到服务端
+func (c *clusterConn) Flush() error {
+    if c.conn != nil {
+        return c.conn.Flush()
+    }
+    if len(c.pending) == 0 {
+        return nil
+    }
+    c.replies = append(c.replies, c.cluster.doBatch(c.pending)...)
+    c.pending = nil
+    return nil
+}
+
+// 按照命令顺序读取一条执行结果
+func (c *clusterConn) Receive() (interface{}, error) {
+    if len(c.replies) == 0 {
+        if err := c.Flush(); err != nil {
+            return nil, err
+        }
+    }
+    if len(c.replies) > 0 {
+        r        := c.replies[0]
+        c.replies = c.replies[1:]
+        return r.reply, r.err
+    }
+    if c.conn == nil {
+        return nil, errors.New("no pending reply to receive")
+    }
+    for ; c.skip > 0; c.skip-- {
+        if _, err := c.conn.Receive(); err != nil {
+            if _, ok := err.(redis.Error); !ok {
+                return nil, err
+            }
+        }
+    }
+    return c.conn.Receive()
+}
+
+// 获取连接错误
+func (c *clusterConn) Err() error {
+    if c.conn != nil {
+        return c.conn.Err()
+    }
+    return nil
+}
+
+// 关闭连接，释放固定的节点连接，未执行的命令将被丢弃
+func (c *clusterConn) Close() error {
+    c.pending = nil
+    c.replies = nil
+    c.unpin()
+    return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+const (
+    gDEFAULT_REDIS_PORT = 6379
+)
+
+// 解析URL格式的连接配置，格式：
+// redis://[[user]:password@]host[:port][/db][?tls_skip_verify=true]
+// rediss://[[user]:password@]host[:port][/db] (TLS连接)
+// 连接池参数通过查询参数设置，例如：
+// redis://127.0.0.1:6379/1?max_idle=10&max_active=100&idle_timeout=60s&max_conn_lifetime=10m&wait=true&test_on_borrow=1s
+func ParseURL(rawurl string) (Config, error) {
+    config := Config{}
+    u, err := url.Parse(rawurl)
+    if err != nil {
+        return config, err
+    }
+    switch u.Scheme {
+        case "redis":
+        case "rediss":
+            config.TLS = true
+        default:
+            return config, errors.New(fmt.Sprintf(`invalid redis URL scheme: "%s"`, u.Scheme))
+    }
+    config.Host = u.Hostname()
+    config.Port = gDEFAULT_REDIS_PORT
+    if config.Host == "" {
+        config.Host = "127.0.0.1"
+    }
+    if port := u.Port(); port != "" {
+        if config.Port, err = strconv.Atoi(port); err != nil {
+            return config, errors.New(fmt.Sprintf(`invalid redis URL port: "%s"`, port))
+        }
+    }
+    if u.User != nil {
+        // 只有一个值并且没有冒号(redis://password@host)时，兼容作为密码处理
+        if password, ok := u.User.Password(); ok {
+            config.User = u.User.Username()
+            config.Pass = password
+        } else {
+            config.Pass = u.User.Username()
+        }
+    }
+    if path := strings.Trim(u.Path, "/"); path != "" {
+        if config.Db, err = strconv.Atoi(path); err != nil {
+            return config, errors.New(fmt.Sprintf(`invalid redis URL database: "%s"`, path))
+        }
+    }
+    query := u.Query()
+    config.TLSSkipVerify = query.Get("tls_skip_verify") == "true"
+    config.TLSCaFile     = query.Get("tls_ca_file")
+    config.TLSCertFile   = query.Get("tls_cert_file")
+    config.TLSKeyFile    = query.Get("tls_key_file")
+    config.TLSServerName = query.Get("tls_server_name")
+    config.Wait          = query.Get("wait") == "true"
+    numbers := map[string]*int {
+        "max_idle"   : &config.MaxIdle,
+        "max_active" : &config.MaxActive,
+    }
+    for name, p := range numbers {
+        if v := query.Get(name); v != "" {
+            n, err := strconv.Atoi(v)
+            if err != nil || n < 0 {
+                return config, errors.New(fmt.Sprintf(`invalid redis URL parameter %s: "%s"`, name, v))
+            }
+            *p = n
+        }
+    }
+    durations := map[string]*time.Duration {
+        "idle_timeout"      : &config.IdleTimeout,
+        "max_conn_lifetime" : &config.MaxConnLifetime,
+        "test_on_borrow"    : &config.TestOnBorrow,
+    }
+    for name, p := range durations {
+        if v := query.Get(name); v != "" {
+            d, err := time.ParseDuration(v)
+            if err != nil || d < 0 {
+                return config, errors.New(fmt.Sprintf(`invalid redis URL parameter %s: "%s"`, name, v))
+            }
+            *p = d
+        }
+    }
+    return config, nil
+}
+
+// 根据配置生成连接选项(TLS)
+func getDialOptions(config Config) ([]redis.DialOption, error) {
+    if !config.TLS {
+        return nil, nil
+    }
+    tlsConfig, err := getTLSConfig(config)
+    if err != nil {
+        return nil, err
+    }
+    return []redis.DialOption {
+        redis.DialUseTLS(true),
+        redis.DialTLSConfig(tlsConfig),
+    }, nil
+}
+
+// 根据配置生成TLS配置，加载CA证书及客户端证书
+func getTLSConfig(config Config) (*tls.Config, error) {
+    if config.TLSConfig != nil {
+        return config.TLSConfig, nil
+    }
+    tlsConfig := &tls.Config {
+        InsecureSkipVerify : config.TLSSkipVerify,
+        ServerName         : config.TLSServerName,
+    }
+    if config.TLSCaFile != "" {
+        content, err := ioutil.ReadFile(config.TLSCaFile)
+        if err != nil {
+            return nil, err
+        }
+        pool := x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(content) {
+            return nil, errors.New(fmt.Sprintf(`no valid certificate found in CA file "%s"`, config.TLSCaFile))
+        }
+        tlsConfig.RootCAs = pool
+    }
+    if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+        cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+        if err != nil {
+            return nil, err
+        }
+        tlsConfig.Certificates = []tls.Certificate{cert}
+    }
+    return tlsConfig, nil
+}
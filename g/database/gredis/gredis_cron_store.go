@@ -0,0 +1,63 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "encoding/json"
+    "github.com/gogf/gf/g/os/gcron"
+)
+
+const (
+    gDEFAULT_CRON_STORE_KEY = "gcron:jobs" // Redis存储默认的哈希表键名
+)
+
+// 基于Redis的定时任务持久化存储(gcron.Store)，所有记录以JSON格式保存在同一个哈希表中，键名为定时任务名称
+type cronStore struct {
+    redis *Redis
+    key   string
+}
+
+// 创建基于Redis的定时任务持久化存储，通过gcron.SetStore设置，key为保存记录的哈希表键名，默认为gcron:jobs
+func NewCronStore(redis *Redis, key...string) gcron.Store {
+    s := &cronStore {
+        redis : redis,
+        key   : gDEFAULT_CRON_STORE_KEY,
+    }
+    if len(key) > 0 && key[0] != "" {
+        s.key = key[0]
+    }
+    return s
+}
+
+func (s *cronStore) Get(name string) (*gcron.StoreRecord, error) {
+    v, err := s.redis.DoVar("HGET", s.key, name)
+    if err != nil {
+        return nil, err
+    }
+    if v.IsNil() {
+        return nil, nil
+    }
+    record := new(gcron.StoreRecord)
+    if err := json.Unmarshal(v.Bytes(), record); err != nil {
+        return nil, err
+    }
+    return record, nil
+}
+
+func (s *cronStore) Set(record *gcron.StoreRecord) error {
+    content, err := json.Marshal(record)
+    if err != nil {
+        return err
+    }
+    _, err = s.redis.Do("HSET", s.key, record.Name, content)
+    return err
+}
+
+func (s *cronStore) Remove(name string) error {
+    _, err := s.redis.Do("HDEL", s.key, name)
+    return err
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+// 管道对象，将多条命令缓存后一次性发送到服务端并批量读取结果，减少网络往返次数。
+// 管道对象独占一个连接池中的连接，使用完毕后需要调用Close将连接放回连接池。
+type Pipeline struct {
+    conn  redis.Conn // 管道使用的连接
+    count int        // 已发送但尚未读取结果的命令数量
+}
+
+// 创建管道对象
+func (r *Redis) Pipeline() *Pipeline {
+    return &Pipeline {
+        conn : r.GetConn(),
+    }
+}
+
+// 将命令写入管道缓冲区，命令在调用Exec时才会发送到服务端
+func (p *Pipeline) Send(command string, args...interface{}) error {
+    if err := p.conn.Send(command, args...); err != nil {
+        return err
+    }
+    p.count++
+    return nil
+}
+
+// 发送管道中缓存的所有命令，并按照命令顺序返回执行结果。
+// 某条命令执行失败时对应的结果为nil，返回的错误为第一条执行失败命令的错误信息，不影响其他命令的结果；
+// 执行完成后管道可以继续使用。
+func (p *Pipeline) Exec() ([]interface{}, error) {
+    count  := p.count
+    p.count = 0
+    if err := p.conn.Flush(); err != nil {
+        return nil, err
+    }
+    firstErr := error(nil)
+    replies  := make([]interface{}, count)
+    for i := 0; i < count; i++ {
+        reply, err := p.conn.Receive()
+        if err != nil {
+            if _, ok := err.(redis.Error); !ok {
+                // 网络等连接错误，后续结果已无法读取
+                return nil, err
+            }
+            if firstErr == nil {
+                firstErr = err
+            }
+        }
+        replies[i] = reply
+    }
+    return replies, firstErr
+}
+
+// 关闭管道，将连接放回连接池，未执行的命令将被丢弃
+func (p *Pipeline) Close() error {
+    return p.conn.Close()
+}
@@ -0,0 +1,270 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "encoding/json"
+    "sync"
+    "time"
+    "github.com/gogf/gf/g/container/gset"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+const (
+    gPUBSUB_MESSAGE_BUFFER_SIZE = 100                       // 消息通道缓冲大小
+    gPUBSUB_PING_INTERVAL       = 30 * time.Second          // 订阅连接的心跳间隔
+    gPUBSUB_READ_TIMEOUT        = 2 * gPUBSUB_PING_INTERVAL // 订阅连接的读取超时时间，超时未收到任何数据(包括心跳回复)时重连
+    gPUBSUB_RECONNECT_MIN_DELAY = 100 * time.Millisecond    // 断线重连的最小等待时间
+    gPUBSUB_RECONNECT_MAX_DELAY = 5 * time.Second           // 断线重连的最大等待时间
+)
+
+// 订阅消息
+type Message struct {
+    Channel string // 消息所属频道
+    Pattern string // 匹配的订阅模式(仅PSubscribe订阅的消息)
+    Data    []byte // 消息内容
+}
+
+// 订阅对象，使用独立的连接(不占用连接池)接收消息，连接断开时自动重连并重新订阅所有的频道及模式
+type Subscriber struct {
+    redis    *Redis
+    mu       sync.Mutex         // 连接写操作及重连互斥锁
+    conn     *redis.PubSubConn  // 当前订阅连接
+    channels *gset.StringSet    // 已订阅的频道
+    patterns *gset.StringSet    // 已订阅的模式
+    messages chan *Message      // 消息通道
+    closed   *gtype.Bool        // 是否已关闭
+    done     chan struct{}      // 关闭通知
+}
+
+// 发布消息到指定频道，返回接收到消息的订阅者数量
+func (r *Redis) Publish(channel string, message interface{}) (int, error) {
+    return redis.Int(r.Do("PUBLISH", channel, message))
+}
+
+// 将消息按照JSON编码后发布到指定频道，返回接收到消息的订阅者数量
+func (r *Redis) PublishJson(channel string, message interface{}) (int, error) {
+    data, err := json.Marshal(message)
+    if err != nil {
+        return 0, err
+    }
+    return r.Publish(channel, data)
+}
+
+// 订阅指定的频道，通过返回对象的Channel方法获取消息通道，使用完毕后需要调用Close关闭订阅
+func (r *Redis) Subscribe(channels...string) (*Subscriber, error) {
+    s := newSubscriber(r)
+    s.channels.Add(channels...)
+    if err := s.start(); err != nil {
+        return nil, err
+    }
+    return s, nil
+}
+
+// 按照模式订阅频道(例如：news.*)，通过返回对象的Channel方法获取消息通道，使用完毕后需要调用Close关闭订阅
+func (r *Redis) PSubscribe(patterns...string) (*Subscriber, error) {
+    s := newSubscriber(r)
+    s.patterns.Add(patterns...)
+    if err := s.start(); err != nil {
+        return nil, err
+    }
+    return s, nil
+}
+
+// 创建订阅对象
+func newSubscriber(r *Redis) *Subscriber {
+    return &Subscriber {
+        redis    : r,
+        channels : gset.NewStringSet(),
+        patterns : gset.NewStringSet(),
+        messages : make(chan *Message, gPUBSUB_MESSAGE_BUFFER_SIZE),
+        closed   : gtype.NewBool(),
+        done     : make(chan struct{}),
+    }
+}
+
+// 建立订阅连接并启动消息接收及心跳检测
+func (s *Subscriber) start() error {
+    if err := s.connect(); err != nil {
+        return err
+    }
+    go s.receiveLoop()
+    go s.pingLoop()
+    return nil
+}
+
+// 建立新的订阅连接，并订阅所有已记录的频道及模式
+func (s *Subscriber) connect() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed.Val() {
+        return nil
+    }
+    c, err := s.redis.pool.Dial()
+    if err != nil {
+        return err
+    }
+    conn := &redis.PubSubConn{Conn : c}
+    if channels := s.channels.Slice(); len(channels) > 0 {
+        if err := conn.Subscribe(redis.Args{}.AddFlat(channels)...); err != nil {
+            conn.Close()
+            return err
+        }
+    }
+    if patterns := s.patterns.Slice(); len(patterns) > 0 {
+        if err := conn.PSubscribe(redis.Args{}.AddFlat(patterns)...); err != nil {
+            conn.Close()
+            return err
+        }
+    }
+    s.conn = conn
+    return nil
+}
+
+// 获取当前订阅连接
+func (s *Subscriber) getConn() *redis.PubSubConn {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.conn
+}
+
+// 关闭指定的订阅连接(如果仍然是当前连接)
+func (s *Subscriber) closeConn(conn *redis.PubSubConn) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.conn == conn {
+        s.conn = nil
+    }
+    conn.Close()
+}
+
+// 循环读取订阅消息，连接出错时按照递增的时间间隔自动重连，订阅关闭后关闭消息通道
+func (s *Subscriber) receiveLoop() {
+    defer close(s.messages)
+    delay := gPUBSUB_RECONNECT_MIN_DELAY
+    for !s.closed.Val() {
+        conn := s.getConn()
+        if conn == nil {
+            select {
+                case <-s.done:
+                    return
+                case <-time.After(delay):
+            }
+            if err := s.connect(); err != nil {
+                if delay *= 2; delay > gPUBSUB_RECONNECT_MAX_DELAY {
+                    delay = gPUBSUB_RECONNECT_MAX_DELAY
+                }
+                continue
+            }
+            delay = gPUBSUB_RECONNECT_MIN_DELAY
+            continue
+        }
+        switch v := conn.ReceiveWithTimeout(gPUBSUB_READ_TIMEOUT).(type) {
+            case redis.Message:
+                message := &Message {
+                    Channel : v.Channel,
+                    Pattern : v.Pattern,
+                    Data    : v.Data,
+                }
+                select {
+                    case s.messages <- message:
+                    case <-s.done:
+                        return
+                }
+            case error:
+                s.closeConn(conn)
+        }
+    }
+}
+
+// 定时发送心跳，以便及时发现已断开的订阅连接
+func (s *Subscriber) pingLoop() {
+    ticker := time.NewTicker(gPUBSUB_PING_INTERVAL)
+    defer ticker.Stop()
+    for {
+        select {
+            case <-s.done:
+                return
+            case <-ticker.C:
+                s.mu.Lock()
+                if s.conn != nil {
+                    s.conn.Ping("")
+                }
+                s.mu.Unlock()
+        }
+    }
+}
+
+// 获取消息通道，订阅关闭后该通道将被关闭
+func (s *Subscriber) Channel() <-chan *Message {
+    return s.messages
+}
+
+// 增加订阅的频道
+func (s *Subscriber) Subscribe(channels...string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.channels.Add(channels...)
+    if s.conn != nil {
+        return s.conn.Subscribe(redis.Args{}.AddFlat(channels)...)
+    }
+    return nil
+}
+
+// 增加订阅的模式
+func (s *Subscriber) PSubscribe(patterns...string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.patterns.Add(patterns...)
+    if s.conn != nil {
+        return s.conn.PSubscribe(redis.Args{}.AddFlat(patterns)...)
+    }
+    return nil
+}
+
+// 取消订阅指定的频道
+func (s *Subscriber) Unsubscribe(channels...string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for _, channel := range channels {
+        s.channels.Remove(channel)
+    }
+    if s.conn != nil {
+        return s.conn.Unsubscribe(redis.Args{}.AddFlat(channels)...)
+    }
+    return nil
+}
+
+// 取消订阅指定的模式
+func (s *Subscriber) PUnsubscribe(patterns...string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for _, pattern := range patterns {
+        s.patterns.Remove(pattern)
+    }
+    if s.conn != nil {
+        return s.conn.PUnsubscribe(redis.Args{}.AddFlat(patterns)...)
+    }
+    return nil
+}
+
+// 关闭订阅，关闭订阅连接及消息通道
+func (s *Subscriber) Close() error {
+    if s.closed.Set(true) {
+        return nil
+    }
+    close(s.done)
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.conn != nil {
+        err := s.conn.Close()
+        s.conn = nil
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "errors"
+    "fmt"
+    "net"
+    "time"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+const (
+    gSENTINEL_TIMEOUT = 3 * time.Second // 连接Sentinel节点的超时时间
+)
+
+// Sentinel客户端，用于查询当前的主节点地址
+type sentinel struct {
+    config Config
+}
+
+// 创建Sentinel客户端
+func newSentinel(config Config) *sentinel {
+    return &sentinel {
+        config : config,
+    }
+}
+
+// 依次向Sentinel节点查询主节点地址，任一节点查询成功即返回
+func (s *sentinel) getMasterAddress() (string, error) {
+    options, err := getDialOptions(s.config)
+    if err != nil {
+        return "", err
+    }
+    options = append(options,
+        redis.DialConnectTimeout(gSENTINEL_TIMEOUT),
+        redis.DialReadTimeout(gSENTINEL_TIMEOUT),
+        redis.DialWriteTimeout(gSENTINEL_TIMEOUT),
+    )
+    for _, address := range s.config.Sentinel {
+        conn, e := redis.Dial("tcp", address, options...)
+        if e != nil {
+            err = e
+            continue
+        }
+        values, e := redis.Strings(conn.Do("SENTINEL", "get-master-addr-by-name", s.config.MasterName))
+        conn.Close()
+        if e != nil {
+            if e == redis.ErrNil {
+                e = errors.New(fmt.Sprintf(`master "%s" not found on sentinel "%s"`, s.config.MasterName, address))
+            }
+            err = e
+            continue
+        }
+        if len(values) == 2 {
+            return net.JoinHostPort(values[0], values[1]), nil
+        }
+    }
+    if err == nil {
+        err = errors.New(fmt.Sprintf(`no sentinel available for master "%s"`, s.config.MasterName))
+    }
+    return "", err
+}
+
+// 连接当前的主节点，发生故障转移后新建的连接将自动连接到新的主节点
+func (s *sentinel) dial() (redis.Conn, error) {
+    address, err := s.getMasterAddress()
+    if err != nil {
+        return nil, err
+    }
+    c, err := dial(s.config, address)
+    if err != nil {
+        return nil, err
+    }
+    // Sentinel返回的主节点可能尚未完成切换，需要确认节点角色
+    if err := checkMasterRole(c); err != nil {
+        c.Close()
+        return nil, err
+    }
+    return c, nil
+}
+
+// 检测连接的节点是否为主节点，用于从连接池获取连接时丢弃故障转移后已降级为从节点的连接
+func checkMasterRole(c redis.Conn) error {
+    values, err := redis.Values(c.Do("ROLE"))
+    if err != nil {
+        return err
+    }
+    if len(values) == 0 {
+        return errors.New("invalid ROLE reply")
+    }
+    if role, _ := redis.String(values[0], nil); role != "master" {
+        return errors.New(fmt.Sprintf(`redis node role is "%s", master expected`, role))
+    }
+    return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "time"
+    "github.com/gogf/gf/g/container/gvar"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/util/gconv"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+// 键名不存在时GetStruct/HGetAllStruct返回的错误
+var ErrNil = redis.ErrNil
+
+// 执行同步命令，并将结果转换为通用变量对象返回，便于进行类型转换
+func (r *Redis) DoVar(command string, args...interface{}) (*gvar.Var, error) {
+    reply, err := r.Do(command, args...)
+    if err != nil {
+        return nil, err
+    }
+    return gvar.New(reply, true), nil
+}
+
+// 将对象按照JSON编码后写入指定的键名，expire为过期时间(可选，精确到毫秒)
+func (r *Redis) SetStruct(key string, value interface{}, expire...time.Duration) error {
+    data, err := gjson.Encode(value)
+    if err != nil {
+        return err
+    }
+    if len(expire) > 0 && expire[0] > 0 {
+        _, err = r.Do("SET", key, data, "PX", int64(expire[0] / time.Millisecond))
+    } else {
+        _, err = r.Do("SET", key, data)
+    }
+    return err
+}
+
+// 读取指定键名的JSON数据并解码到pointer指向的对象中(struct/map/slice等)，键名不存在时返回ErrNil
+func (r *Redis) GetStruct(key string, pointer interface{}) error {
+    data, err := redis.Bytes(r.Do("GET", key))
+    if err != nil {
+        return err
+    }
+    return gjson.DecodeTo(data, pointer)
+}
+
+// 将struct(或者map)对象的属性写入指定的哈希表，每个属性对应哈希表的一个字段，
+// 字段名称优先使用gconv/json标签，属性值为struct/map/slice等复杂类型时按照JSON编码后写入
+func (r *Redis) HSetStruct(key string, value interface{}) error {
+    m := gconv.Map(value)
+    if len(m) == 0 {
+        return errors.New(fmt.Sprintf(`no field to set for hash "%s"`, key))
+    }
+    args := make([]interface{}, 0, 2*len(m) + 1)
+    args  = append(args, key)
+    for k, v := range m {
+        if v != nil {
+            switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+                case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+                    if _, ok := v.([]byte); !ok {
+                        data, err := gjson.Encode(v)
+                        if err != nil {
+                            return err
+                        }
+                        v = data
+                    }
+            }
+        }
+        args = append(args, k, v)
+    }
+    _, err := r.Do("HMSET", args...)
+    return err
+}
+
+// 读取指定哈希表的所有字段并映射到pointer指向的struct对象中，哈希表不存在时返回ErrNil
+func (r *Redis) HGetAllStruct(key string, pointer interface{}) error {
+    reply, err := r.Do("HGETALL", key)
+    if err != nil {
+        return err
+    }
+    if values, ok := reply.([]interface{}); ok && len(values) == 0 {
+        return ErrNil
+    }
+    return ScanStruct(reply, pointer)
+}
+
+// 将HGETALL等命令返回的字段/值交替排列的结果映射到pointer指向的struct对象中，
+// 常用于Pipeline/事务中HGETALL命令的结果处理，JSON编码的字段值(对象或者数组)将自动解码
+func ScanStruct(reply interface{}, pointer interface{}) error {
+    values, err := redis.Values(reply, nil)
+    if err != nil {
+        return err
+    }
+    if len(values) % 2 != 0 {
+        return errors.New("expected even number of values for hash result")
+    }
+    m := make(map[string]interface{}, len(values) / 2)
+    for i := 0; i < len(values); i += 2 {
+        k, err := redis.String(values[i], nil)
+        if err != nil {
+            return err
+        }
+        v, err := redis.Bytes(values[i + 1], nil)
+        if err != nil {
+            return err
+        }
+        if len(v) > 0 && (v[0] == '{' || v[0] == '[') {
+            if decoded, err := gjson.Decode(v); err == nil {
+                m[k] = decoded
+                continue
+            }
+        }
+        m[k] = string(v)
+    }
+    return gconv.Struct(m, pointer)
+}
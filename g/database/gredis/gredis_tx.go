@@ -0,0 +1,127 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "errors"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+// 事务由于WATCH的键被其他客户端修改而未执行
+var ErrTxAborted = errors.New("transaction aborted: watched keys were modified")
+
+// 事务对象，基于MULTI/EXEC实现，支持使用WATCH实现乐观锁，例如：
+// tx, _ := r.Watch("balance")
+// defer tx.Close()
+// v, _  := redis.Int(tx.Do("GET", "balance"))
+// tx.Multi()
+// tx.Send("SET", "balance", v + 1)
+// _, err := tx.Exec() // WATCH的键被修改时返回ErrTxAborted，可以重试
+// 事务对象独占一个连接池中的连接，使用完毕后需要调用Close将连接放回连接池。
+type Tx struct {
+    conn  redis.Conn // 事务使用的连接
+    multi bool       // 是否已开启事务(MULTI)
+}
+
+// 开启事务，之后通过Send写入的命令在Exec时原子执行
+func (r *Redis) Multi() (*Tx, error) {
+    tx := &Tx {
+        conn : r.GetConn(),
+    }
+    if err := tx.Multi(); err != nil {
+        tx.conn.Close()
+        return nil, err
+    }
+    return tx, nil
+}
+
+// 监视指定的键并返回事务对象，在Exec之前这些键被其他客户端修改时事务不会执行(乐观锁)，
+// 监视之后可以通过Do读取数据，再调用Multi开启事务。
+func (r *Redis) Watch(keys...string) (*Tx, error) {
+    tx := &Tx {
+        conn : r.GetConn(),
+    }
+    if err := tx.Watch(keys...); err != nil {
+        tx.conn.Close()
+        return nil, err
+    }
+    return tx, nil
+}
+
+// 监视指定的键，必须在Multi之前调用
+func (tx *Tx) Watch(keys...string) error {
+    if tx.multi {
+        return errors.New("WATCH inside MULTI is not allowed")
+    }
+    _, err := tx.conn.Do("WATCH", redis.Args{}.AddFlat(keys)...)
+    return err
+}
+
+// 取消所有键的监视
+func (tx *Tx) Unwatch() error {
+    _, err := tx.conn.Do("UNWATCH")
+    return err
+}
+
+// 在事务连接上执行同步命令，常用于在Multi之前读取被监视键的数据
+func (tx *Tx) Do(command string, args...interface{}) (interface{}, error) {
+    if tx.multi {
+        return nil, errors.New("Do inside MULTI is not allowed, use Send instead")
+    }
+    return tx.conn.Do(command, args...)
+}
+
+// 开启事务
+func (tx *Tx) Multi() error {
+    if tx.multi {
+        return nil
+    }
+    if err := tx.conn.Send("MULTI"); err != nil {
+        return err
+    }
+    tx.multi = true
+    return nil
+}
+
+// 将命令写入事务队列，命令在Exec时才会发送到服务端并原子执行
+func (tx *Tx) Send(command string, args...interface{}) error {
+    if !tx.multi {
+        return errors.New("Multi should be called before Send")
+    }
+    return tx.conn.Send(command, args...)
+}
+
+// 执行事务，按照命令顺序返回执行结果，WATCH的键被修改导致事务未执行时返回ErrTxAborted
+func (tx *Tx) Exec() ([]interface{}, error) {
+    if !tx.multi {
+        return nil, errors.New("Multi should be called before Exec")
+    }
+    tx.multi = false
+    reply, err := tx.conn.Do("EXEC")
+    if err != nil {
+        return nil, err
+    }
+    if reply == nil {
+        return nil, ErrTxAborted
+    }
+    return redis.Values(reply, nil)
+}
+
+// 放弃事务，清空事务队列并取消所有键的监视
+func (tx *Tx) Discard() error {
+    if !tx.multi {
+        return tx.Unwatch()
+    }
+    tx.multi = false
+    _, err := tx.conn.Do("DISCARD")
+    return err
+}
+
+// 关闭事务对象，将连接放回连接池(连接池会自动放弃未执行的事务并取消监视)
+func (tx *Tx) Close() error {
+    return tx.conn.Close()
+}
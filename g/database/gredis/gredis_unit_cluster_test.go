@@ -0,0 +1,36 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func TestCluster_HashSlot(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(crc16("123456789"), 0x31C3)
+        gtest.Assert(hashSlot("foo"),    12182)
+        gtest.Assert(hashSlot("bar"),    5061)
+        gtest.Assert(hashSlot(""),       0)
+        // hash tag
+        gtest.Assert(hashSlot("{user1000}.following"), hashSlot("user1000"))
+        gtest.Assert(hashSlot("{user1000}.followers"), hashSlot("user1000"))
+        gtest.Assert(hashSlot("foo{}{bar}"),           int(crc16("foo{}{bar}") % 16384))
+        gtest.Assert(hashSlot("foo{{bar}}zap"),        hashSlot("{bar"))
+    })
+}
+
+func TestCluster_CommandSlot(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(commandSlot("PING", nil), -1)
+        gtest.Assert(commandSlot("get", []interface{}{"foo"}), 12182)
+        gtest.Assert(commandSlot("SET", []interface{}{[]byte("foo"), 1}), 12182)
+        gtest.Assert(commandSlot("EVAL", []interface{}{"return 1", 1, "foo"}), 12182)
+        gtest.Assert(commandSlot("EVAL", []interface{}{"return 1", 0}), -1)
+    })
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gredis
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func TestScanStruct(t *testing.T) {
+    type Profile struct {
+        City string
+        Tags []string
+    }
+    type User struct {
+        Id      int
+        Name    string   `json:"nickname"`
+        Profile Profile
+    }
+    gtest.Case(t, func() {
+        user  := new(User)
+        reply := []interface{}{
+            []byte("id"),       []byte("100"),
+            []byte("nickname"), []byte("john"),
+            []byte("profile"),  []byte(`{"City":"Chengdu","Tags":["a","b"]}`),
+        }
+        gtest.Assert(ScanStruct(reply, user), nil)
+        gtest.Assert(user.Id,           100)
+        gtest.Assert(user.Name,         "john")
+        gtest.Assert(user.Profile.City, "Chengdu")
+        gtest.Assert(user.Profile.Tags, []string{"a", "b"})
+    })
+    gtest.Case(t, func() {
+        user := new(User)
+        gtest.AssertNE(ScanStruct([]interface{}{[]byte("id")}, user), nil)
+    })
+}
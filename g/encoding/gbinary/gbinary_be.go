@@ -0,0 +1,224 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "math"
+)
+
+// 大端字节序(网络字节序)的二进制打包，规则同Encode
+func BeEncode(vs ...interface{}) []byte {
+    buf := new(bytes.Buffer)
+    for i := 0; i < len(vs); i++ {
+        switch value := vs[i].(type) {
+            case int:     buf.Write(BeEncodeInt(value))
+            case int8:    buf.Write(BeEncodeInt8(value))
+            case int16:   buf.Write(BeEncodeInt16(value))
+            case int32:   buf.Write(BeEncodeInt32(value))
+            case int64:   buf.Write(BeEncodeInt64(value))
+            case uint:    buf.Write(BeEncodeUint(value))
+            case uint8:   buf.Write(BeEncodeUint8(value))
+            case uint16:  buf.Write(BeEncodeUint16(value))
+            case uint32:  buf.Write(BeEncodeUint32(value))
+            case uint64:  buf.Write(BeEncodeUint64(value))
+            case bool:    buf.Write(EncodeBool(value))
+            case string:  buf.Write(EncodeString(value))
+            case []byte:  buf.Write(value)
+            case float32: buf.Write(BeEncodeFloat32(value))
+            case float64: buf.Write(BeEncodeFloat64(value))
+            default:
+                if err := binary.Write(buf, binary.BigEndian, value); err != nil {
+                    buf.Write(EncodeString(fmt.Sprintf("%v", value)))
+                }
+        }
+    }
+    return buf.Bytes()
+}
+
+// 大端字节序打包，并指定固定的[]byte长度返回，规则同EncodeByLength
+func BeEncodeByLength(length int, vs ...interface{}) []byte {
+    b := BeEncode(vs...)
+    if len(b) < length {
+        b = append(b, make([]byte, length - len(b))...)
+    } else if len(b) > length {
+        b = b[0 : length]
+    }
+    return b
+}
+
+// 大端字节序解包，规则同Decode
+func BeDecode(b []byte, vs ...interface{}) error {
+    buf := bytes.NewBuffer(b)
+    for i := 0; i < len(vs); i++ {
+        err := binary.Read(buf, binary.BigEndian, vs[i])
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 自动识别int类型长度，转换为大端字节序的[]byte
+func BeEncodeInt(i int) []byte {
+    if i <= math.MaxInt8 {
+        return BeEncodeInt8(int8(i))
+    } else if i <= math.MaxInt16 {
+        return BeEncodeInt16(int16(i))
+    } else if i <= math.MaxInt32 {
+        return BeEncodeInt32(int32(i))
+    } else {
+        return BeEncodeInt64(int64(i))
+    }
+}
+
+// 自动识别uint类型长度，转换为大端字节序的[]byte
+func BeEncodeUint(i uint) []byte {
+    if i <= math.MaxUint8 {
+        return BeEncodeUint8(uint8(i))
+    } else if i <= math.MaxUint16 {
+        return BeEncodeUint16(uint16(i))
+    } else if i <= math.MaxUint32 {
+        return BeEncodeUint32(uint32(i))
+    } else {
+        return BeEncodeUint64(uint64(i))
+    }
+}
+
+func BeEncodeInt8(i int8) []byte {
+    return []byte{byte(i)}
+}
+
+func BeEncodeUint8(i uint8) []byte {
+    return []byte{byte(i)}
+}
+
+func BeEncodeInt16(i int16) []byte {
+    bytes := make([]byte, 2)
+    binary.BigEndian.PutUint16(bytes, uint16(i))
+    return bytes
+}
+
+func BeEncodeUint16(i uint16) []byte {
+    bytes := make([]byte, 2)
+    binary.BigEndian.PutUint16(bytes, i)
+    return bytes
+}
+
+func BeEncodeInt32(i int32) []byte {
+    bytes := make([]byte, 4)
+    binary.BigEndian.PutUint32(bytes, uint32(i))
+    return bytes
+}
+
+func BeEncodeUint32(i uint32) []byte {
+    bytes := make([]byte, 4)
+    binary.BigEndian.PutUint32(bytes, i)
+    return bytes
+}
+
+func BeEncodeInt64(i int64) []byte {
+    bytes := make([]byte, 8)
+    binary.BigEndian.PutUint64(bytes, uint64(i))
+    return bytes
+}
+
+func BeEncodeUint64(i uint64) []byte {
+    bytes := make([]byte, 8)
+    binary.BigEndian.PutUint64(bytes, i)
+    return bytes
+}
+
+func BeEncodeFloat32(f float32) []byte {
+    bytes := make([]byte, 4)
+    binary.BigEndian.PutUint32(bytes, math.Float32bits(f))
+    return bytes
+}
+
+func BeEncodeFloat64(f float64) []byte {
+    bytes := make([]byte, 8)
+    binary.BigEndian.PutUint64(bytes, math.Float64bits(f))
+    return bytes
+}
+
+// 当b位数不够时，进行高位补0(大端字节序的高位在前)
+func beFillUpSize(b []byte, l int) []byte {
+    if len(b) >= l {
+        return b
+    }
+    c := make([]byte, l)
+    copy(c[l - len(b):], b)
+    return c
+}
+
+// 将大端字节序的二进制解析为int类型，根据[]byte的长度进行自动转换
+func BeDecodeToInt(b []byte) int {
+    if len(b) < 2 {
+        return int(BeDecodeToUint8(b))
+    } else if len(b) < 3 {
+        return int(BeDecodeToUint16(b))
+    } else if len(b) < 5 {
+        return int(BeDecodeToUint32(b))
+    } else {
+        return int(BeDecodeToUint64(b))
+    }
+}
+
+// 将大端字节序的二进制解析为uint类型，根据[]byte的长度进行自动转换
+func BeDecodeToUint(b []byte) uint {
+    if len(b) < 2 {
+        return uint(BeDecodeToUint8(b))
+    } else if len(b) < 3 {
+        return uint(BeDecodeToUint16(b))
+    } else if len(b) < 5 {
+        return uint(BeDecodeToUint32(b))
+    } else {
+        return uint(BeDecodeToUint64(b))
+    }
+}
+
+func BeDecodeToInt8(b []byte) int8 {
+    return int8(b[0])
+}
+
+func BeDecodeToUint8(b []byte) uint8 {
+    return uint8(b[0])
+}
+
+func BeDecodeToInt16(b []byte) int16 {
+    return int16(binary.BigEndian.Uint16(beFillUpSize(b, 2)))
+}
+
+func BeDecodeToUint16(b []byte) uint16 {
+    return binary.BigEndian.Uint16(beFillUpSize(b, 2))
+}
+
+func BeDecodeToInt32(b []byte) int32 {
+    return int32(binary.BigEndian.Uint32(beFillUpSize(b, 4)))
+}
+
+func BeDecodeToUint32(b []byte) uint32 {
+    return binary.BigEndian.Uint32(beFillUpSize(b, 4))
+}
+
+func BeDecodeToInt64(b []byte) int64 {
+    return int64(binary.BigEndian.Uint64(beFillUpSize(b, 8)))
+}
+
+func BeDecodeToUint64(b []byte) uint64 {
+    return binary.BigEndian.Uint64(beFillUpSize(b, 8))
+}
+
+func BeDecodeToFloat32(b []byte) float32 {
+    return math.Float32frombits(binary.BigEndian.Uint32(beFillUpSize(b, 4)))
+}
+
+func BeDecodeToFloat64(b []byte) float64 {
+    return math.Float64frombits(binary.BigEndian.Uint64(beFillUpSize(b, 8)))
+}
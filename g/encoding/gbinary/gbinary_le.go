@@ -0,0 +1,118 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+// 小端字节序的二进制打包/解包，与不带前缀的默认方法(默认即为小端字节序)完全一致，
+// 用于在协议实现中显式声明字节序，与Be*系列方法对应。
+
+func LeEncode(vs ...interface{}) []byte {
+    return Encode(vs...)
+}
+
+func LeEncodeByLength(length int, vs ...interface{}) []byte {
+    return EncodeByLength(length, vs...)
+}
+
+func LeDecode(b []byte, vs ...interface{}) error {
+    return Decode(b, vs...)
+}
+
+func LeEncodeInt(i int) []byte {
+    return EncodeInt(i)
+}
+
+func LeEncodeUint(i uint) []byte {
+    return EncodeUint(i)
+}
+
+func LeEncodeInt8(i int8) []byte {
+    return EncodeInt8(i)
+}
+
+func LeEncodeUint8(i uint8) []byte {
+    return EncodeUint8(i)
+}
+
+func LeEncodeInt16(i int16) []byte {
+    return EncodeInt16(i)
+}
+
+func LeEncodeUint16(i uint16) []byte {
+    return EncodeUint16(i)
+}
+
+func LeEncodeInt32(i int32) []byte {
+    return EncodeInt32(i)
+}
+
+func LeEncodeUint32(i uint32) []byte {
+    return EncodeUint32(i)
+}
+
+func LeEncodeInt64(i int64) []byte {
+    return EncodeInt64(i)
+}
+
+func LeEncodeUint64(i uint64) []byte {
+    return EncodeUint64(i)
+}
+
+func LeEncodeFloat32(f float32) []byte {
+    return EncodeFloat32(f)
+}
+
+func LeEncodeFloat64(f float64) []byte {
+    return EncodeFloat64(f)
+}
+
+func LeDecodeToInt(b []byte) int {
+    return DecodeToInt(b)
+}
+
+func LeDecodeToUint(b []byte) uint {
+    return DecodeToUint(b)
+}
+
+func LeDecodeToInt8(b []byte) int8 {
+    return DecodeToInt8(b)
+}
+
+func LeDecodeToUint8(b []byte) uint8 {
+    return DecodeToUint8(b)
+}
+
+func LeDecodeToInt16(b []byte) int16 {
+    return DecodeToInt16(b)
+}
+
+func LeDecodeToUint16(b []byte) uint16 {
+    return DecodeToUint16(b)
+}
+
+func LeDecodeToInt32(b []byte) int32 {
+    return DecodeToInt32(b)
+}
+
+func LeDecodeToUint32(b []byte) uint32 {
+    return DecodeToUint32(b)
+}
+
+func LeDecodeToInt64(b []byte) int64 {
+    return DecodeToInt64(b)
+}
+
+func LeDecodeToUint64(b []byte) uint64 {
+    return DecodeToUint64(b)
+}
+
+func LeDecodeToFloat32(b []byte) float32 {
+    return DecodeToFloat32(b)
+}
+
+func LeDecodeToFloat64(b []byte) float64 {
+    return DecodeToFloat64(b)
+}
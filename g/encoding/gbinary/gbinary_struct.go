@@ -0,0 +1,466 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+import (
+    "bytes"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "math"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// struct二进制布局的标签名称
+const gTAG_NAME = "gbinary"
+
+// struct属性的二进制布局
+type fieldLayout struct {
+    index  int              // 属性索引
+    name   string           // 属性名称
+    order  int              // 编码顺序
+    size   int              // 固定长度(字节)，整型为编码宽度，string/[]byte为固定的字节长度
+    length string           // 记录长度的属性名称，string/[]byte为字节数，其他slice为元素数量
+    endian binary.ByteOrder // 字节序，为nil时继承外层struct的字节序
+    pad    int              // 属性之后填充的字节数
+    skip   bool             // 属性名称为 _ 的占位属性，编码时写入0，解码时跳过
+}
+
+// 根据struct标签将struct对象编码为二进制，pointer可以为struct对象或者struct指针，默认使用小端字节序。
+// 标签格式为 gbinary:"order=1,size=4,endian=be,pad=2,len=Length"，多个选项使用逗号分隔：
+// order  : 编码顺序，按照order稳定排序(未指定时为0，相同order按照属性定义顺序)；
+// size   : 固定长度(字节)，整型属性为编码宽度(1-8，解码时有符号整型进行符号扩展)，string/[]byte不足时末尾补0，超过时截断；
+// endian : 字节序，be/big为大端，le/little为小端，嵌套的struct继承外层的字节序；
+// pad    : 在该属性之后填充的0字节数；
+// len    : 记录长度的整型属性名称(必须在该属性之前编码)，编码时自动写入实际长度，string/[]byte为字节数，其他slice为元素数量；
+// -      : 忽略该属性。
+// 名称为 _ 的属性(例如: _ [2]byte)作为占位填充，编码时写入0，解码时跳过。
+// 未指定size/len的string/[]byte/slice属性在解码时读取剩余的所有数据，因此只能作为最后一个属性。
+// int/uint未指定size时按照8字节编码。
+func EncodeStruct(pointer interface{}) ([]byte, error) {
+    rv := reflect.ValueOf(pointer)
+    for rv.Kind() == reflect.Ptr {
+        if rv.IsNil() {
+            return nil, errors.New("struct pointer should not be nil")
+        }
+        rv = rv.Elem()
+    }
+    if rv.Kind() != reflect.Struct {
+        return nil, errors.New(fmt.Sprintf("invalid struct type: %s", rv.Type()))
+    }
+    buf := new(bytes.Buffer)
+    if err := encodeStruct(buf, rv, binary.LittleEndian); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// 根据struct标签将二进制解码到struct对象，pointer必须为struct指针，标签规则参考EncodeStruct
+func DecodeStruct(b []byte, pointer interface{}) error {
+    rv := reflect.ValueOf(pointer)
+    if rv.Kind() != reflect.Ptr || rv.IsNil() {
+        return errors.New("object should be a non-nil struct pointer")
+    }
+    rv = rv.Elem()
+    if rv.Kind() != reflect.Struct {
+        return errors.New(fmt.Sprintf("invalid struct type: %s", rv.Type()))
+    }
+    _, err := decodeStruct(b, rv, binary.LittleEndian)
+    return err
+}
+
+// 解析struct的二进制布局
+func getStructLayout(t reflect.Type) ([]*fieldLayout, error) {
+    layouts := make([]*fieldLayout, 0, t.NumField())
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        tag   := field.Tag.Get(gTAG_NAME)
+        if tag == "-" || (field.PkgPath != "" && field.Name != "_") {
+            continue
+        }
+        layout := &fieldLayout {
+            index : i,
+            name  : field.Name,
+            skip  : field.Name == "_",
+        }
+        for _, item := range strings.Split(tag, ",") {
+            item = strings.TrimSpace(item)
+            if item == "" {
+                continue
+            }
+            key, value := item, ""
+            if pos := strings.Index(item, "="); pos != -1 {
+                key, value = strings.TrimSpace(item[ : pos]), strings.TrimSpace(item[pos + 1 : ])
+            }
+            var err error
+            switch key {
+                case "order":
+                    layout.order, err = strconv.Atoi(value)
+                case "size":
+                    layout.size, err = strconv.Atoi(value)
+                case "pad":
+                    layout.pad, err = strconv.Atoi(value)
+                case "len":
+                    layout.length = value
+                case "endian":
+                    switch strings.ToLower(value) {
+                        case "be", "big":    layout.endian = binary.BigEndian
+                        case "le", "little": layout.endian = binary.LittleEndian
+                        default:
+                            err = errors.New("unknown endian")
+                    }
+                default:
+                    err = errors.New("unknown option")
+            }
+            if err != nil {
+                return nil, errors.New(fmt.Sprintf(`invalid tag option "%s" of field %s.%s: %s`, item, t.Name(), field.Name, err.Error()))
+            }
+        }
+        layouts = append(layouts, layout)
+    }
+    sort.SliceStable(layouts, func(i, j int) bool {
+        return layouts[i].order < layouts[j].order
+    })
+    // 校验长度属性
+    for i, layout := range layouts {
+        if layout.length == "" {
+            continue
+        }
+        found := false
+        for _, v := range layouts[ : i] {
+            if v.name == layout.length {
+                found = true
+                break
+            }
+        }
+        if !found {
+            return nil, errors.New(fmt.Sprintf("length field %s of %s.%s should be encoded before it", layout.length, t.Name(), layout.name))
+        }
+    }
+    return layouts, nil
+}
+
+// 编码struct对象
+func encodeStruct(buf *bytes.Buffer, rv reflect.Value, endian binary.ByteOrder) error {
+    layouts, err := getStructLayout(rv.Type())
+    if err != nil {
+        return err
+    }
+    for _, layout := range layouts {
+        order := endian
+        if layout.endian != nil {
+            order = layout.endian
+        }
+        value := rv.Field(layout.index)
+        if layout.skip {
+            size := getPlaceholderSize(value.Type())
+            if size < 0 {
+                return errors.New(fmt.Sprintf("placeholder field of %s should have fixed size", rv.Type().Name()))
+            }
+            buf.Write(make([]byte, size))
+        } else {
+            // 记录其他属性长度的属性，写入对应属性的实际长度
+            for _, v := range layouts {
+                if v.length == layout.name {
+                    value = reflect.New(value.Type()).Elem()
+                    if err := setIntValue(value, int64(rv.Field(v.index).Len())); err != nil {
+                        return errors.New(fmt.Sprintf("field %s: %s", layout.name, err.Error()))
+                    }
+                    break
+                }
+            }
+            if err := encodeValue(buf, value, layout, order); err != nil {
+                return errors.New(fmt.Sprintf("field %s: %s", layout.name, err.Error()))
+            }
+        }
+        buf.Write(make([]byte, layout.pad))
+    }
+    return nil
+}
+
+// 编码单个属性值
+func encodeValue(buf *bytes.Buffer, value reflect.Value, layout *fieldLayout, order binary.ByteOrder) error {
+    switch value.Kind() {
+        case reflect.Bool:
+            b := make([]byte, getIntSize(layout, 1))
+            if value.Bool() {
+                if order == binary.BigEndian {
+                    b[len(b) - 1] = 1
+                } else {
+                    b[0] = 1
+                }
+            }
+            buf.Write(b)
+
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+            buf.Write(encodeInteger(uint64(value.Int()), getIntSize(layout, int(value.Type().Size())), order))
+
+        case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            buf.Write(encodeInteger(value.Uint(), getIntSize(layout, int(value.Type().Size())), order))
+
+        case reflect.Float32:
+            b := make([]byte, 4)
+            order.PutUint32(b, math.Float32bits(float32(value.Float())))
+            buf.Write(b)
+
+        case reflect.Float64:
+            b := make([]byte, 8)
+            order.PutUint64(b, math.Float64bits(value.Float()))
+            buf.Write(b)
+
+        case reflect.String:
+            buf.Write(fixBytesSize([]byte(value.String()), layout.size))
+
+        case reflect.Slice:
+            if value.Type().Elem().Kind() == reflect.Uint8 {
+                buf.Write(fixBytesSize(value.Bytes(), layout.size))
+                return nil
+            }
+            for i := 0; i < value.Len(); i++ {
+                if err := encodeValue(buf, value.Index(i), &fieldLayout{}, order); err != nil {
+                    return err
+                }
+            }
+
+        case reflect.Array:
+            for i := 0; i < value.Len(); i++ {
+                if err := encodeValue(buf, value.Index(i), &fieldLayout{}, order); err != nil {
+                    return err
+                }
+            }
+
+        case reflect.Struct:
+            return encodeStruct(buf, value, order)
+
+        case reflect.Ptr:
+            if value.IsNil() {
+                value = reflect.New(value.Type().Elem())
+            }
+            return encodeValue(buf, value.Elem(), layout, order)
+
+        default:
+            return errors.New(fmt.Sprintf("unsupported type: %s", value.Type()))
+    }
+    return nil
+}
+
+// 解码struct对象，返回读取的字节数
+func decodeStruct(b []byte, rv reflect.Value, endian binary.ByteOrder) (int, error) {
+    layouts, err := getStructLayout(rv.Type())
+    if err != nil {
+        return 0, err
+    }
+    offset := 0
+    for _, layout := range layouts {
+        order := endian
+        if layout.endian != nil {
+            order = layout.endian
+        }
+        value := rv.Field(layout.index)
+        if layout.skip {
+            size := getPlaceholderSize(value.Type())
+            if size < 0 || offset + size > len(b) {
+                return 0, errors.New(fmt.Sprintf("field %s: insufficient data", layout.name))
+            }
+            offset += size
+        } else {
+            length := -1
+            if layout.length != "" {
+                length = int(getIntValue(rv.FieldByName(layout.length)))
+            }
+            n, err := decodeValue(b[offset : ], value, layout, order, length)
+            if err != nil {
+                return 0, errors.New(fmt.Sprintf("field %s: %s", layout.name, err.Error()))
+            }
+            offset += n
+        }
+        if offset + layout.pad > len(b) {
+            return 0, errors.New(fmt.Sprintf("field %s: insufficient data for padding", layout.name))
+        }
+        offset += layout.pad
+    }
+    return offset, nil
+}
+
+// 解码单个属性值，length为记录的长度(没有时为-1)，返回读取的字节数
+func decodeValue(b []byte, value reflect.Value, layout *fieldLayout, order binary.ByteOrder, length int) (int, error) {
+    insufficient := errors.New("insufficient data")
+    switch value.Kind() {
+        case reflect.Bool:
+            size := getIntSize(layout, 1)
+            if size > len(b) {
+                return 0, insufficient
+            }
+            value.SetBool(DecodeToBool(b[ : size]))
+            return size, nil
+
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+            size := getIntSize(layout, int(value.Type().Size()))
+            if size > len(b) {
+                return 0, insufficient
+            }
+            i := decodeInteger(b[ : size], order)
+            // 有符号整型的符号扩展
+            if shift := uint(64 - size * 8); shift > 0 {
+                value.SetInt(int64(i << shift) >> shift)
+            } else {
+                value.SetInt(int64(i))
+            }
+            return size, nil
+
+        case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            size := getIntSize(layout, int(value.Type().Size()))
+            if size > len(b) {
+                return 0, insufficient
+            }
+            value.SetUint(decodeInteger(b[ : size], order))
+            return size, nil
+
+        case reflect.Float32:
+            if len(b) < 4 {
+                return 0, insufficient
+            }
+            value.SetFloat(float64(math.Float32frombits(order.Uint32(b))))
+            return 4, nil
+
+        case reflect.Float64:
+            if len(b) < 8 {
+                return 0, insufficient
+            }
+            value.SetFloat(math.Float64frombits(order.Uint64(b)))
+            return 8, nil
+
+        case reflect.String, reflect.Slice:
+            isBytes := value.Kind() == reflect.String || value.Type().Elem().Kind() == reflect.Uint8
+            if isBytes {
+                size := len(b)
+                if length >= 0 {
+                    size = length
+                } else if layout.size > 0 {
+                    size = layout.size
+                }
+                if size > len(b) {
+                    return 0, insufficient
+                }
+                data := make([]byte, size)
+                copy(data, b[ : size])
+                if value.Kind() == reflect.String {
+                    // 固定长度的字符串去掉末尾填充的0
+                    if length < 0 && layout.size > 0 {
+                        data = bytes.TrimRight(data, "\x00")
+                    }
+                    value.SetString(string(data))
+                } else {
+                    value.SetBytes(data)
+                }
+                return size, nil
+            }
+            offset := 0
+            slice  := reflect.MakeSlice(value.Type(), 0, 0)
+            for i := 0; (length < 0 && offset < len(b)) || i < length; i++ {
+                item := reflect.New(value.Type().Elem()).Elem()
+                n, err := decodeValue(b[offset : ], item, &fieldLayout{}, order, -1)
+                if err != nil {
+                    return 0, err
+                }
+                slice   = reflect.Append(slice, item)
+                offset += n
+            }
+            value.Set(slice)
+            return offset, nil
+
+        case reflect.Array:
+            offset := 0
+            for i := 0; i < value.Len(); i++ {
+                n, err := decodeValue(b[offset : ], value.Index(i), &fieldLayout{}, order, -1)
+                if err != nil {
+                    return 0, err
+                }
+                offset += n
+            }
+            return offset, nil
+
+        case reflect.Struct:
+            return decodeStruct(b, value, order)
+
+        case reflect.Ptr:
+            if value.IsNil() {
+                value.Set(reflect.New(value.Type().Elem()))
+            }
+            return decodeValue(b, value.Elem(), layout, order, length)
+    }
+    return 0, errors.New(fmt.Sprintf("unsupported type: %s", value.Type()))
+}
+
+// 获得占位属性的字节长度，占位属性为非公开属性，因此使用新建的值计算
+func getPlaceholderSize(t reflect.Type) int {
+    return binary.Size(reflect.New(t).Elem().Interface())
+}
+
+// 获得整型的编码宽度
+func getIntSize(layout *fieldLayout, size int) int {
+    if layout.size > 0 && layout.size <= 8 {
+        return layout.size
+    }
+    return size
+}
+
+// 按照指定宽度编码整型
+func encodeInteger(i uint64, size int, order binary.ByteOrder) []byte {
+    b := make([]byte, 8)
+    order.PutUint64(b, i)
+    if order == binary.BigEndian {
+        return b[8 - size : ]
+    }
+    return b[ : size]
+}
+
+// 解码指定宽度的整型
+func decodeInteger(b []byte, order binary.ByteOrder) uint64 {
+    if order == binary.BigEndian {
+        return binary.BigEndian.Uint64(beFillUpSize(b, 8))
+    }
+    return binary.LittleEndian.Uint64(fillUpSize(b, 8))
+}
+
+// 将[]byte调整为固定长度，size <= 0 时不做调整
+func fixBytesSize(b []byte, size int) []byte {
+    if size <= 0 || len(b) == size {
+        return b
+    }
+    if len(b) > size {
+        return b[ : size]
+    }
+    return append(append([]byte{}, b...), make([]byte, size - len(b))...)
+}
+
+// 设置长度属性的值
+func setIntValue(value reflect.Value, i int64) error {
+    switch value.Kind() {
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+            value.SetInt(i)
+        case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            value.SetUint(uint64(i))
+        default:
+            return errors.New("length field should be an integer")
+    }
+    return nil
+}
+
+// 获得长度属性的值
+func getIntValue(value reflect.Value) int64 {
+    switch value.Kind() {
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+            return value.Int()
+        case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            return int64(value.Uint())
+    }
+    return -1
+}
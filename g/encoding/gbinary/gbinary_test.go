@@ -0,0 +1,153 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gbinary_test
+
+import (
+    "bytes"
+    "math"
+    "testing"
+    "github.com/gogf/gf/g/encoding/gbinary"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_BeLe(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(gbinary.BeEncodeUint16(0x0102), []byte{0x01, 0x02})
+        gtest.Assert(gbinary.LeEncodeUint16(0x0102), []byte{0x02, 0x01})
+        gtest.Assert(gbinary.BeEncodeInt32(-2), []byte{0xff, 0xff, 0xff, 0xfe})
+        gtest.Assert(gbinary.BeDecodeToInt32([]byte{0xff, 0xff, 0xff, 0xfe}), -2)
+        gtest.Assert(gbinary.BeDecodeToUint32([]byte{0x01, 0x02}), 0x0102)
+        gtest.Assert(gbinary.LeDecodeToUint32([]byte{0x02, 0x01}), 0x0102)
+        gtest.Assert(gbinary.BeDecodeToUint64(gbinary.BeEncodeUint64(math.MaxUint64 - 1)), uint64(math.MaxUint64 - 1))
+        gtest.Assert(gbinary.BeDecodeToFloat64(gbinary.BeEncodeFloat64(3.14)), 3.14)
+        gtest.Assert(gbinary.BeDecodeToFloat32(gbinary.BeEncodeFloat32(1.5)), float32(1.5))
+        gtest.Assert(gbinary.BeDecodeToInt(gbinary.BeEncodeInt(300)), 300)
+
+        b := gbinary.BeEncode(uint16(1), int32(-1), "ab")
+        gtest.Assert(b, []byte{0x00, 0x01, 0xff, 0xff, 0xff, 0xff, 'a', 'b'})
+        var (
+            u16 uint16
+            i32 int32
+        )
+        gtest.Assert(gbinary.BeDecode(b, &u16, &i32), nil)
+        gtest.Assert(u16, 1)
+        gtest.Assert(i32, -1)
+        gtest.Assert(gbinary.LeEncode(uint16(1)), []byte{0x01, 0x00})
+        gtest.Assert(len(gbinary.BeEncodeByLength(4, uint16(1))), 4)
+    })
+}
+
+func Test_Varint(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(gbinary.EncodeUvarint(1), []byte{0x01})
+        gtest.Assert(gbinary.EncodeUvarint(300), []byte{0xac, 0x02})
+        gtest.Assert(gbinary.EncodeVarint(-1), []byte{0x01})
+        gtest.Assert(gbinary.EncodeVarint(1), []byte{0x02})
+
+        v, n := gbinary.DecodeUvarint([]byte{0xac, 0x02, 0xff})
+        gtest.Assert(v, 300)
+        gtest.Assert(n, 2)
+        i, n := gbinary.DecodeVarint(gbinary.EncodeVarint(-123456789))
+        gtest.Assert(i, -123456789)
+        gtest.Assert(n > 0, true)
+        _, n = gbinary.DecodeUvarint([]byte{0xac})
+        gtest.Assert(n, 0)
+
+        b := gbinary.AppendUvarint(nil, 300)
+        b  = gbinary.AppendVarint(b, -2)
+        reader := bytes.NewReader(b)
+        u, err := gbinary.ReadUvarint(reader)
+        gtest.Assert(err, nil)
+        gtest.Assert(u, 300)
+        s, err := gbinary.ReadVarint(reader)
+        gtest.Assert(err, nil)
+        gtest.Assert(s, -2)
+    })
+    gtest.Case(t, func() {
+        gtest.Assert(gbinary.ZigZagEncode32(0), 0)
+        gtest.Assert(gbinary.ZigZagEncode32(-1), 1)
+        gtest.Assert(gbinary.ZigZagEncode32(1), 2)
+        gtest.Assert(gbinary.ZigZagEncode32(-2), 3)
+        gtest.Assert(gbinary.ZigZagEncode32(math.MinInt32), uint32(math.MaxUint32))
+        gtest.Assert(gbinary.ZigZagDecode32(math.MaxUint32), int32(math.MinInt32))
+        gtest.Assert(gbinary.ZigZagDecode32(3), -2)
+        gtest.Assert(gbinary.ZigZagEncode64(-3), 5)
+        gtest.Assert(gbinary.ZigZagDecode64(gbinary.ZigZagEncode64(math.MaxInt64)), int64(math.MaxInt64))
+        gtest.Assert(gbinary.ZigZagDecode64(gbinary.ZigZagEncode64(math.MinInt64)), int64(math.MinInt64))
+    })
+}
+
+func Test_Struct(t *testing.T) {
+    type Header struct {
+        Magic   [2]byte
+        Version uint8
+        _       [1]byte
+        Length  uint32 `gbinary:"endian=be"`
+    }
+    type Frame struct {
+        Header  Header
+        Flags   int32  `gbinary:"size=3,pad=1"`
+        Name    string `gbinary:"size=8"`
+        Count   uint16
+        Items   []int16 `gbinary:"len=Count"`
+        Payload []byte
+    }
+    gtest.Case(t, func() {
+        frame := &Frame {
+            Header  : Header{Magic : [2]byte{'G', 'F'}, Version : 1, Length : 0x0a0b},
+            Flags   : -2,
+            Name    : "gf",
+            Items   : []int16{1, -1},
+            Payload : []byte("data"),
+        }
+        b, err := gbinary.EncodeStruct(frame)
+        gtest.Assert(err, nil)
+        gtest.Assert(b, []byte {
+            'G', 'F', 0x01, 0x00, 0x00, 0x00, 0x0a, 0x0b,
+            0xfe, 0xff, 0xff, 0x00,
+            'g', 'f', 0, 0, 0, 0, 0, 0,
+            0x02, 0x00,
+            0x01, 0x00, 0xff, 0xff,
+            'd', 'a', 't', 'a',
+        })
+
+        result := new(Frame)
+        gtest.Assert(gbinary.DecodeStruct(b, result), nil)
+        gtest.Assert(result.Header.Magic, [2]byte{'G', 'F'})
+        gtest.Assert(result.Header.Length, 0x0a0b)
+        gtest.Assert(result.Flags, -2)
+        gtest.Assert(result.Name, "gf")
+        gtest.Assert(result.Count, 2)
+        gtest.Assert(result.Items, []int16{1, -1})
+        gtest.Assert(result.Payload, []byte("data"))
+
+        gtest.AssertNE(gbinary.DecodeStruct(b[ : 10], new(Frame)), nil)
+    })
+    gtest.Case(t, func() {
+        type Packet struct {
+            Body string `gbinary:"order=2"`
+            Type uint16 `gbinary:"order=1,endian=be"`
+            Skip int    `gbinary:"-"`
+        }
+        b, err := gbinary.EncodeStruct(Packet{Body : "ok", Type : 1, Skip : 1})
+        gtest.Assert(err, nil)
+        gtest.Assert(b, []byte{0x00, 0x01, 'o', 'k'})
+        p := new(Packet)
+        gtest.Assert(gbinary.DecodeStruct(b, p), nil)
+        gtest.Assert(p.Type, 1)
+        gtest.Assert(p.Body, "ok")
+
+        type Invalid struct {
+            Data []byte `gbinary:"len=Size"`
+            Size int
+        }
+        _, err = gbinary.EncodeStruct(Invalid{})
+        gtest.AssertNE(err, nil)
+    })
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gbinary
+
+import (
+    "encoding/binary"
+    "io"
+)
+
+// 无符号变长整型编码(Uvarint，与protobuf的varint编码一致)，每个字节使用低7位存储数据，最高位表示是否还有后续字节
+func EncodeUvarint(i uint64) []byte {
+    bytes := make([]byte, binary.MaxVarintLen64)
+    return bytes[ : binary.PutUvarint(bytes, i)]
+}
+
+// 有符号变长整型编码(Varint)，先进行zigzag编码再进行Uvarint编码，绝对值较小的负数同样只占用较少的字节
+func EncodeVarint(i int64) []byte {
+    bytes := make([]byte, binary.MaxVarintLen64)
+    return bytes[ : binary.PutVarint(bytes, i)]
+}
+
+// 将Uvarint编码追加到b末尾，并返回新的[]byte
+func AppendUvarint(b []byte, i uint64) []byte {
+    return append(b, EncodeUvarint(i)...)
+}
+
+// 将Varint编码追加到b末尾，并返回新的[]byte
+func AppendVarint(b []byte, i int64) []byte {
+    return append(b, EncodeVarint(i)...)
+}
+
+// 从b的起始位置解析Uvarint编码，第二个返回值为读取的字节数：
+// n == 0 表示b的长度不够(数据不完整)，n < 0 表示数值溢出(读取了-n个字节)。
+func DecodeUvarint(b []byte) (uint64, int) {
+    return binary.Uvarint(b)
+}
+
+// 从b的起始位置解析Varint编码，返回值的含义同DecodeUvarint
+func DecodeVarint(b []byte) (int64, int) {
+    return binary.Varint(b)
+}
+
+// 从数据流中读取一个Uvarint编码的整数
+func ReadUvarint(reader io.ByteReader) (uint64, error) {
+    return binary.ReadUvarint(reader)
+}
+
+// 从数据流中读取一个Varint编码的整数
+func ReadVarint(reader io.ByteReader) (int64, error) {
+    return binary.ReadVarint(reader)
+}
+
+// zigzag编码，将有符号整数映射为无符号整数: 0 => 0, -1 => 1, 1 => 2, -2 => 3 ...
+func ZigZagEncode32(i int32) uint32 {
+    return uint32((i << 1) ^ (i >> 31))
+}
+
+// zigzag解码
+func ZigZagDecode32(i uint32) int32 {
+    return int32(i >> 1) ^ -int32(i & 1)
+}
+
+// zigzag编码(64位)
+func ZigZagEncode64(i int64) uint64 {
+    return uint64((i << 1) ^ (i >> 63))
+}
+
+// zigzag解码(64位)
+func ZigZagDecode64(i uint64) int64 {
+    return int64(i >> 1) ^ -int64(i & 1)
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcharset
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// 多字节字符集的检测统计
+type detectStat struct {
+	chars   int // 非ASCII字符数量
+	typical int // 常用字符数量(位于常用汉字/假名/标点的编码区间)
+	invalid int // 非法编码数量
+}
+
+// 根据统计结果计算可信度，非法编码超过5%时认为不是该字符集
+func (s detectStat) confidence() float64 {
+	if s.chars == 0 || s.invalid * 20 > s.chars {
+		return 0
+	}
+	c := float64(s.typical) / float64(s.chars) * (1 - 2 * float64(s.invalid) / float64(s.chars))
+	// 多字节字符集的检测存在误判的可能，最高可信度为0.95
+	if c > 0.95 {
+		c = 0.95
+	}
+	return c
+}
+
+// 检测数据的字符集，返回字符集名称(可直接用于Convert/ToUTF8)及可信度(0-1)。
+// 支持检测的字符集包括: UTF-8(纯ASCII内容同样返回UTF-8)、带BOM的UTF-16、GBK、Big5、Shift_JIS，
+// 均不匹配时返回ISO-8859-1(包含0x80-0x9F区间的字节时返回windows-1252)。
+// 检测基于编码规则及常用字符的编码区间，数据越长结果越准确，可信度较低时建议由调用方确认。
+func Detect(data []byte) (charset string, confidence float64) {
+	switch {
+		case bytes.HasPrefix(data, []byte{0xef, 0xbb, 0xbf}):
+			return "UTF-8", 1
+		case bytes.HasPrefix(data, []byte{0xfe, 0xff}), bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+			return "UTF-16", 1
+	}
+	if utf8.Valid(data) {
+		// 纯ASCII或者包含合法的多字节UTF-8序列，其他字符集的内容极少能构成合法的UTF-8序列
+		multiple := 0
+		for _, b := range data {
+			if b >= 0xc0 {
+				multiple++
+			}
+		}
+		if multiple == 1 {
+			return "UTF-8", 0.9
+		}
+		return "UTF-8", 1
+	}
+	charset, confidence = detectLatin(data)
+	for _, item := range []struct{
+		name string
+		stat detectStat
+	}{
+		{"GBK",       detectGBK(data)},
+		{"Big5",      detectBig5(data)},
+		{"Shift_JIS", detectShiftJIS(data)},
+	} {
+		if c := item.stat.confidence(); c > confidence {
+			charset, confidence = item.name, c
+		}
+	}
+	return
+}
+
+// 自动检测字符集并转换为UTF8，字符集检测规则参考Detect
+func ToUTF8Auto(src string) (dst string, err error) {
+	charset, _ := Detect([]byte(src))
+	if charset == "UTF-8" {
+		// 去掉BOM
+		return string(bytes.TrimPrefix([]byte(src), []byte{0xef, 0xbb, 0xbf})), nil
+	}
+	return ToUTF8(charset, src)
+}
+
+// 单字节字符集的检测，非ASCII字节为前后均是字母的单个字节(例如: Résumé)时可信度较高
+func detectLatin(data []byte) (charset string, confidence float64) {
+	charset = "ISO-8859-1"
+	total, letters := 0, 0
+	for i, b := range data {
+		if b < 0x80 {
+			continue
+		}
+		total++
+		if b >= 0x80 && b <= 0x9f {
+			charset = "windows-1252"
+		}
+		if b >= 0xc0 && b != 0xd7 && b != 0xf7 &&
+			(i == 0 || isAsciiLetter(data[i - 1])) && (i == len(data) - 1 || isAsciiLetter(data[i + 1])) {
+			letters++
+		}
+	}
+	if total == 0 {
+		return charset, 0.3
+	}
+	return charset, 0.3 + 0.5 * float64(letters) / float64(total)
+}
+
+func isAsciiLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// GBK编码检测: 首字节0x81-0xFE，尾字节0x40-0xFE(不包括0x7F)，
+// 常用字符为GB2312的汉字区(0xB0-0xF7)及全角标点区(0xA1-0xA3)
+func detectGBK(data []byte) (stat detectStat) {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b < 0x80 {
+			continue
+		}
+		stat.chars++
+		if b == 0x80 || b == 0xff || i + 1 >= len(data) {
+			stat.invalid++
+			continue
+		}
+		i++
+		t := data[i]
+		if t < 0x40 || t == 0x7f || t == 0xff {
+			stat.invalid++
+			continue
+		}
+		if t >= 0xa1 && ((b >= 0xb0 && b <= 0xf7) || (b >= 0xa1 && b <= 0xa3)) {
+			stat.typical++
+		}
+	}
+	return
+}
+
+// Big5编码检测: 首字节0xA1-0xF9，尾字节0x40-0x7E或0xA1-0xFE，
+// 常用字符为常用字区(0xA440-0xC67E)及符号区(0xA1-0xA3)
+func detectBig5(data []byte) (stat detectStat) {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b < 0x80 {
+			continue
+		}
+		stat.chars++
+		if b < 0xa1 || b > 0xf9 || i + 1 >= len(data) {
+			stat.invalid++
+			continue
+		}
+		i++
+		t := data[i]
+		if !((t >= 0x40 && t <= 0x7e) || (t >= 0xa1 && t <= 0xfe)) {
+			stat.invalid++
+			continue
+		}
+		if (b >= 0xa1 && b <= 0xa3) || (b >= 0xa4 && b < 0xc6) || (b == 0xc6 && t <= 0x7e) {
+			stat.typical++
+		}
+	}
+	return
+}
+
+// Shift_JIS编码检测: 0xA1-0xDF为单字节的半角片假名，双字节首字节0x81-0x9F或0xE0-0xFC，尾字节0x40-0x7E或0x80-0xFC，
+// 常用字符为标点(0x81)、平假名(0x82)、片假名(0x83)及JIS第一/二水准汉字(0x88-0x9F, 0xE0-0xEA)
+func detectShiftJIS(data []byte) (stat detectStat) {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b < 0x80 {
+			continue
+		}
+		stat.chars++
+		if b >= 0xa1 && b <= 0xdf {
+			// 半角片假名在现代文本中较少使用，不计入常用字符
+			continue
+		}
+		if b == 0x80 || b == 0xa0 || b > 0xfc || i + 1 >= len(data) {
+			stat.invalid++
+			continue
+		}
+		i++
+		t := data[i]
+		if t < 0x40 || t == 0x7f || t > 0xfc {
+			stat.invalid++
+			continue
+		}
+		if (b >= 0x81 && b <= 0x83) || (b >= 0x88 && b <= 0x9f) || (b >= 0xe0 && b <= 0xea) {
+			stat.typical++
+		}
+	}
+	return
+}
@@ -95,4 +95,38 @@ func TestConvert(t *testing.T) {
 	if str != dst {
 		t.Errorf("unexpected value:%#v (expected %#v)", str, dst)
 	}
-}
\ No newline at end of file
+}
+
+func TestDetect(t *testing.T) {
+	samples := []struct {
+		text, charset string
+	}{
+		{"中华人民共和国是工人阶级领导的、以工农联盟为基础的人民民主专政的社会主义国家。", "GBK"},
+		{"中華民國憲法是中華民國的根本大法，規定了國家的基本制度和人民的權利義務。", "Big5"},
+		{"これは日本語の文章です。ひらがなとカタカナと漢字が含まれています。", "Shift_JIS"},
+		{"Résumé of the café owner, naïve façade", "ISO-8859-1"},
+	}
+	for _, sample := range samples {
+		src, err := UTF8To(sample.charset, sample.text)
+		if err != nil {
+			t.Errorf("convert error: %v", err)
+			continue
+		}
+		charset, confidence := Detect([]byte(src))
+		if charset != sample.charset {
+			t.Errorf("unexpected charset: %s(%v) (expected %s)", charset, confidence, sample.charset)
+		}
+		if dst, err := ToUTF8Auto(src); err != nil || dst != sample.text {
+			t.Errorf("unexpected value: %#v (expected %#v)", dst, sample.text)
+		}
+	}
+	if charset, confidence := Detect([]byte("你好, gf")); charset != "UTF-8" || confidence != 1 {
+		t.Errorf("unexpected charset: %s(%v)", charset, confidence)
+	}
+	if charset, _ := Detect([]byte("\xfe\xff\x4f\x60")); charset != "UTF-16" {
+		t.Errorf("unexpected charset: %s", charset)
+	}
+	if dst, _ := ToUTF8Auto("\xef\xbb\xbfhello"); dst != "hello" {
+		t.Errorf("unexpected value: %#v", dst)
+	}
+}
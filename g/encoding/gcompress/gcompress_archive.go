@@ -0,0 +1,350 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 在ArchiveOption.Callback中返回该错误时跳过当前条目(目录条目跳过整个目录)，不中断归档/解压
+var ErrSkipEntry = errors.New("skip this entry")
+
+// 归档(zip/tar)创建及解压的选项
+type ArchiveOption struct {
+    // 创建归档时的路径前缀，例如: "backup/2019"(解压时不使用)
+    Prefix   string
+    // 需要排除的文件glob规则(path.Match格式)，匹配路径(创建时不包含Prefix)的任意后缀部分及任意一级的名称，
+    // 例如: "*.log", ".git", "runtime/*"，匹配的目录将排除整个目录
+    Excludes []string
+    // 每个条目的回调函数，name为归档内的路径(使用/分隔)，返回ErrSkipEntry时跳过该条目，返回其他错误时中断操作
+    Callback func(name string, info os.FileInfo) error
+}
+
+// 待归档的文件条目
+type archiveEntry struct {
+    path string      // 文件的绝对路径
+    name string      // 归档内的路径
+    info os.FileInfo // 文件信息(不跟随符号链接)
+    link string      // 符号链接的目标
+}
+
+// 获得归档选项
+func getArchiveOption(option []ArchiveOption) ArchiveOption {
+    if len(option) > 0 {
+        return option[0]
+    }
+    return ArchiveOption{}
+}
+
+// 遍历需要归档的文件，paths支持使用逗号分隔多个文件/目录路径，
+// 目录归档时包含目录名称本身，例如: /var/www 的条目为 www/index.html
+func (o ArchiveOption) walk(paths string, handler func(entry *archiveEntry) error) error {
+    for _, p := range strings.Split(paths, ",") {
+        p = strings.TrimSpace(p)
+        if p == "" {
+            continue
+        }
+        root, err := filepath.Abs(p)
+        if err != nil {
+            return err
+        }
+        base := filepath.Dir(root)
+        err   = filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+            if err != nil {
+                return err
+            }
+            relative, err := filepath.Rel(base, file)
+            if err != nil {
+                return err
+            }
+            entry := &archiveEntry {
+                path : file,
+                name : path.Join(o.Prefix, filepath.ToSlash(relative)),
+                info : info,
+            }
+            if o.isExcluded(filepath.ToSlash(relative)) {
+                if info.IsDir() {
+                    return filepath.SkipDir
+                }
+                return nil
+            }
+            if err := o.callback(entry.name, info); err != nil {
+                if err == ErrSkipEntry {
+                    if info.IsDir() {
+                        return filepath.SkipDir
+                    }
+                    return nil
+                }
+                return err
+            }
+            if info.Mode() & os.ModeSymlink != 0 {
+                if entry.link, err = os.Readlink(file); err != nil {
+                    return err
+                }
+            }
+            return handler(entry)
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 创建归档文件dest，并由handler写入归档内容
+func archiveFile(dest string, handler func(writer io.Writer, dest string) error) error {
+    file, err := gfile.Create(dest)
+    if err != nil {
+        return err
+    }
+    if err := handler(file, dest); err != nil {
+        file.Close()
+        return err
+    }
+    return file.Close()
+}
+
+// 判断路径是否需要排除，排除规则匹配路径的任意后缀部分(例如: www/runtime/log 匹配 runtime/*)及任意一级的名称
+func (o ArchiveOption) isExcluded(name string) bool {
+    parts := strings.Split(strings.Trim(name, "/"), "/")
+    for _, pattern := range o.Excludes {
+        for i := range parts {
+            if matched, _ := path.Match(pattern, strings.Join(parts[i : ], "/")); matched {
+                return true
+            }
+            if matched, _ := path.Match(pattern, parts[i]); matched {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// 执行条目回调
+func (o ArchiveOption) callback(name string, info os.FileInfo) error {
+    if o.Callback != nil {
+        return o.Callback(name, info)
+    }
+    return nil
+}
+
+// 归档解压器，记录跳过的目录及需要恢复修改时间的目录
+type archiveExtractor struct {
+    option  ArchiveOption
+    dest    string
+    skipped []string             // 跳过的目录(归档内的路径，以/结尾)
+    dirs    map[string]time.Time // 解压的目录及其修改时间
+}
+
+func newArchiveExtractor(dest string, option ArchiveOption) (*archiveExtractor, error) {
+    dest, err := filepath.Abs(dest)
+    if err != nil {
+        return nil, err
+    }
+    if err := os.MkdirAll(dest, 0755); err != nil {
+        return nil, err
+    }
+    return &archiveExtractor {
+        option : option,
+        dest   : dest,
+        dirs   : make(map[string]time.Time),
+    }, nil
+}
+
+// 解压条目前的检查，返回条目在目标目录中的路径，返回空字符串表示跳过该条目
+func (e *archiveExtractor) target(name string, info os.FileInfo) (string, error) {
+    for _, dir := range e.skipped {
+        if strings.HasPrefix(name, dir) {
+            return "", nil
+        }
+    }
+    skip := func() (string, error) {
+        if info.IsDir() {
+            e.skipped = append(e.skipped, strings.TrimSuffix(name, "/") + "/")
+        }
+        return "", nil
+    }
+    if e.option.isExcluded(name) {
+        return skip()
+    }
+    // 防止条目路径跳出目标目录(zip slip)，包括通过已解压的符号链接跳出
+    target := filepath.Join(e.dest, filepath.FromSlash(name))
+    if !e.isInside(target) {
+        return "", errors.New(fmt.Sprintf("invalid entry path: %s", name))
+    }
+    if err := e.checkParents(name, target); err != nil {
+        return "", err
+    }
+    if err := e.option.callback(name, info); err != nil {
+        if err == ErrSkipEntry {
+            return skip()
+        }
+        return "", err
+    }
+    return target, nil
+}
+
+// 判断路径是否位于目标目录中(包括目标目录本身)
+func (e *archiveExtractor) isInside(path string) bool {
+    return path == e.dest || strings.HasPrefix(path, e.dest + string(filepath.Separator))
+}
+
+// 检查目标路径在目标目录中已存在的各级父目录，任意一级为符号链接时返回错误，
+// 防止先解压指向其他位置的符号链接条目，再通过该符号链接写入目标目录之外的文件
+func (e *archiveExtractor) checkParents(name string, target string) error {
+    relative, err := filepath.Rel(e.dest, filepath.Dir(target))
+    if err != nil || relative == "." {
+        return err
+    }
+    current := e.dest
+    for _, part := range strings.Split(relative, string(filepath.Separator)) {
+        current = filepath.Join(current, part)
+        info, err := os.Lstat(current)
+        if err != nil {
+            if os.IsNotExist(err) {
+                return nil
+            }
+            return err
+        }
+        if info.Mode() & os.ModeSymlink != 0 {
+            return errors.New(fmt.Sprintf("invalid entry path: %s, parent directory is a symbolic link", name))
+        }
+    }
+    return nil
+}
+
+// 检查符号链接的目标，只允许指向目标目录中的相对路径。
+// 链接目标中的".."只允许出现在开头，否则例如 l/../.. 在l为符号链接时由系统解析到目标目录之外，
+// 开头的".."从符号链接所在的目录(已由checkParents检查不包含符号链接)开始计算，与按路径文本计算的结果一致。
+func (e *archiveExtractor) checkLink(name string, target string, link string) error {
+    if link == "" || filepath.IsAbs(link) || strings.HasPrefix(link, "/") {
+        return errors.New(fmt.Sprintf("invalid symbolic link: %s -> %s", name, link))
+    }
+    leading := true
+    for _, part := range strings.Split(filepath.ToSlash(link), "/") {
+        switch part {
+            case "", ".":
+            case "..":
+                if !leading {
+                    return errors.New(fmt.Sprintf("invalid symbolic link: %s -> %s, \"..\" is only allowed at the beginning", name, link))
+                }
+            default:
+                leading = false
+        }
+    }
+    if !e.isInside(filepath.Join(filepath.Dir(target), filepath.FromSlash(link))) {
+        return errors.New(fmt.Sprintf("invalid symbolic link: %s -> %s", name, link))
+    }
+    return nil
+}
+
+// 删除已存在的符号链接，避免写入文件时跟随符号链接
+func removeSymlink(path string) error {
+    if info, err := os.Lstat(path); err == nil && info.Mode() & os.ModeSymlink != 0 {
+        return os.Remove(path)
+    }
+    return nil
+}
+
+// 解压单个条目，reader为文件内容(目录及符号链接时不使用)
+func (e *archiveExtractor) extract(name string, info os.FileInfo, link string, reader io.Reader) error {
+    target, err := e.target(name, info)
+    if err != nil || target == "" {
+        return err
+    }
+    mode := info.Mode()
+    switch {
+        case mode.IsDir():
+            if err := os.MkdirAll(target, mode.Perm() | 0700); err != nil {
+                return err
+            }
+            if err := os.Chmod(target, mode.Perm() | 0700); err != nil {
+                return err
+            }
+            // 目录中写入文件会改变目录的修改时间，因此在最后统一设置
+            e.dirs[target] = info.ModTime()
+            return nil
+
+        case mode & os.ModeSymlink != 0:
+            if err := e.checkLink(name, target, link); err != nil {
+                return err
+            }
+            if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+                return err
+            }
+            os.Remove(target)
+            return os.Symlink(link, target)
+
+        case mode.IsRegular():
+            if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+                return err
+            }
+            if err := removeSymlink(target); err != nil {
+                return err
+            }
+            file, err := os.OpenFile(target, os.O_CREATE | os.O_TRUNC | os.O_WRONLY, mode.Perm())
+            if err != nil {
+                return err
+            }
+            if _, err := io.Copy(file, reader); err != nil {
+                file.Close()
+                return err
+            }
+            if err := file.Close(); err != nil {
+                return err
+            }
+            // 文件已存在时OpenFile不会修改权限，并且创建时受umask影响
+            if err := os.Chmod(target, mode.Perm()); err != nil {
+                return err
+            }
+            return os.Chtimes(target, info.ModTime(), info.ModTime())
+    }
+    // 其他类型(设备文件、管道等)忽略
+    return nil
+}
+
+// 解压硬链接条目(tar)，linkname为链接目标在归档内的路径，目标必须为已解压到目标目录中的普通文件
+func (e *archiveExtractor) extractHardlink(name string, info os.FileInfo, linkname string) error {
+    target, err := e.target(name, info)
+    if err != nil || target == "" {
+        return err
+    }
+    // 链接目标被排除时同时跳过该链接
+    if e.option.isExcluded(linkname) {
+        return nil
+    }
+    source := filepath.Join(e.dest, filepath.FromSlash(linkname))
+    if !e.isInside(source) || source == target {
+        return errors.New(fmt.Sprintf("invalid hard link: %s -> %s", name, linkname))
+    }
+    if err := e.checkParents(linkname, source); err != nil {
+        return err
+    }
+    if sourceInfo, err := os.Lstat(source); err != nil || !sourceInfo.Mode().IsRegular() {
+        return errors.New(fmt.Sprintf("invalid hard link: %s -> %s, target is not an extracted regular file", name, linkname))
+    }
+    if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+        return err
+    }
+    os.Remove(target)
+    return os.Link(source, target)
+}
+
+// 解压完成后恢复目录的修改时间
+func (e *archiveExtractor) finish() {
+    for dir, t := range e.dirs {
+        os.Chtimes(dir, t, t)
+    }
+}
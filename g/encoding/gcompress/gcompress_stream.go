@@ -0,0 +1,163 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+    "compress/gzip"
+    "compress/zlib"
+    "io"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 文件压缩/解压进度回调函数，current为已读取的源文件字节数，total为源文件大小
+type ProgressFunc func(current, total int64)
+
+// 封装writer，写入的数据经过gzip压缩后写入writer，level为可选的压缩级别(默认gzip.DefaultCompression)，
+// 注意使用完毕后必须调用Close方法，以便写入剩余的压缩数据(不会关闭底层的writer)。
+func GzipWriter(writer io.Writer, level...int) (io.WriteCloser, error) {
+    if len(level) > 0 {
+        return gzip.NewWriterLevel(writer, level[0])
+    }
+    return gzip.NewWriter(writer), nil
+}
+
+// 封装reader，从reader读取gzip压缩数据并返回解压后的数据
+func GzipReader(reader io.Reader) (io.ReadCloser, error) {
+    return gzip.NewReader(reader)
+}
+
+// 封装writer，写入的数据经过zlib压缩后写入writer，使用规则同GzipWriter
+func ZlibWriter(writer io.Writer, level...int) (io.WriteCloser, error) {
+    if len(level) > 0 {
+        return zlib.NewWriterLevel(writer, level[0])
+    }
+    return zlib.NewWriter(writer), nil
+}
+
+// 封装reader，从reader读取zlib压缩数据并返回解压后的数据
+func ZlibReader(reader io.Reader) (io.ReadCloser, error) {
+    return zlib.NewReader(reader)
+}
+
+// 从src读取数据并进行gzip压缩后写入dst，返回读取的原始数据字节数
+func GzipStream(dst io.Writer, src io.Reader, level...int) (int64, error) {
+    writer, err := GzipWriter(dst, level...)
+    if err != nil {
+        return 0, err
+    }
+    return copyAndClose(writer, src)
+}
+
+// 从src读取gzip压缩数据并将解压后的数据写入dst，返回写入的字节数
+func UnGzipStream(dst io.Writer, src io.Reader) (int64, error) {
+    reader, err := GzipReader(src)
+    if err != nil {
+        return 0, err
+    }
+    defer reader.Close()
+    return io.Copy(dst, reader)
+}
+
+// 从src读取数据并进行zlib压缩后写入dst，返回读取的原始数据字节数
+func ZlibStream(dst io.Writer, src io.Reader, level...int) (int64, error) {
+    writer, err := ZlibWriter(dst, level...)
+    if err != nil {
+        return 0, err
+    }
+    return copyAndClose(writer, src)
+}
+
+// 从src读取zlib压缩数据并将解压后的数据写入dst，返回写入的字节数
+func UnZlibStream(dst io.Writer, src io.Reader) (int64, error) {
+    reader, err := ZlibReader(src)
+    if err != nil {
+        return 0, err
+    }
+    defer reader.Close()
+    return io.Copy(dst, reader)
+}
+
+// 将文件src进行gzip压缩后保存为dst(目录不存在时自动创建)，progress为可选的进度回调函数
+func GzipFile(src, dst string, progress...ProgressFunc) error {
+    return streamFile(src, dst, progress, func(w io.Writer, r io.Reader) (int64, error) {
+        return GzipStream(w, r)
+    })
+}
+
+// 将gzip压缩文件src解压后保存为dst，进度按照读取的压缩文件字节数计算
+func UnGzipFile(src, dst string, progress...ProgressFunc) error {
+    return streamFile(src, dst, progress, UnGzipStream)
+}
+
+// 将文件src进行zlib压缩后保存为dst(目录不存在时自动创建)，progress为可选的进度回调函数
+func ZlibFile(src, dst string, progress...ProgressFunc) error {
+    return streamFile(src, dst, progress, func(w io.Writer, r io.Reader) (int64, error) {
+        return ZlibStream(w, r)
+    })
+}
+
+// 将zlib压缩文件src解压后保存为dst，进度按照读取的压缩文件字节数计算
+func UnZlibFile(src, dst string, progress...ProgressFunc) error {
+    return streamFile(src, dst, progress, UnZlibStream)
+}
+
+// 读取src文件，经过handler处理后写入dst文件
+func streamFile(src, dst string, progress []ProgressFunc, handler func(w io.Writer, r io.Reader) (int64, error)) error {
+    in, err := gfile.Open(src)
+    if err != nil {
+        return err
+    }
+    defer in.Close()
+    info, err := in.Stat()
+    if err != nil {
+        return err
+    }
+    out, err := gfile.Create(dst)
+    if err != nil {
+        return err
+    }
+    var reader io.Reader = in
+    if len(progress) > 0 && progress[0] != nil {
+        reader = &progressReader {
+            reader   : in,
+            total    : info.Size(),
+            callback : progress[0],
+        }
+    }
+    if _, err := handler(out, reader); err != nil {
+        out.Close()
+        return err
+    }
+    return out.Close()
+}
+
+// 复制数据后关闭writer，返回复制的字节数
+func copyAndClose(writer io.WriteCloser, reader io.Reader) (int64, error) {
+    n, err := io.Copy(writer, reader)
+    if err != nil {
+        writer.Close()
+        return n, err
+    }
+    return n, writer.Close()
+}
+
+// 带有进度回调的reader
+type progressReader struct {
+    reader   io.Reader
+    current  int64
+    total    int64
+    callback ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+    n, err := r.reader.Read(p)
+    if n > 0 {
+        r.current += int64(n)
+        r.callback(r.current, r.total)
+    }
+    return n, err
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+    "archive/tar"
+    "compress/gzip"
+    "io"
+    "os"
+    "path/filepath"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 将文件/目录归档为tar文件dest，规则同ZipPath
+func Tar(paths, dest string, option...ArchiveOption) error {
+    return archiveFile(dest, func(writer io.Writer, dest string) error {
+        return tarPath(paths, writer, dest, getArchiveOption(option))
+    })
+}
+
+// 将文件/目录归档为tar.gz文件dest，规则同ZipPath
+func TarGz(paths, dest string, option...ArchiveOption) error {
+    return archiveFile(dest, func(writer io.Writer, dest string) error {
+        return tarGzPath(paths, writer, dest, getArchiveOption(option))
+    })
+}
+
+// 将文件/目录归档为tar格式并写入writer，规则同ZipPath
+func TarWriter(paths string, writer io.Writer, option...ArchiveOption) error {
+    return tarPath(paths, writer, "", getArchiveOption(option))
+}
+
+// 将文件/目录归档为tar.gz格式并写入writer，规则同ZipPath
+func TarGzWriter(paths string, writer io.Writer, option...ArchiveOption) error {
+    return tarGzPath(paths, writer, "", getArchiveOption(option))
+}
+
+// 将tar文件解压到目录dest，规则同UnzipFile
+func Untar(archive, dest string, option...ArchiveOption) error {
+    file, err := gfile.Open(archive)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    return UntarReader(file, dest, option...)
+}
+
+// 将tar.gz文件解压到目录dest，规则同UnzipFile
+func UntarGz(archive, dest string, option...ArchiveOption) error {
+    file, err := gfile.Open(archive)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    return UntarGzReader(file, dest, option...)
+}
+
+// 从reader读取tar内容并解压到目录dest，规则同UnzipFile，
+// 硬链接条目链接到已解压的目标文件(目标不存在或者不在目标目录中时返回错误)
+func UntarReader(reader io.Reader, dest string, option...ArchiveOption) error {
+    extractor, err := newArchiveExtractor(dest, getArchiveOption(option))
+    if err != nil {
+        return err
+    }
+    defer extractor.finish()
+    tarReader := tar.NewReader(reader)
+    for {
+        header, err := tarReader.Next()
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        if header.Typeflag == tar.TypeLink {
+            err = extractor.extractHardlink(header.Name, header.FileInfo(), header.Linkname)
+        } else {
+            err = extractor.extract(header.Name, header.FileInfo(), header.Linkname, tarReader)
+        }
+        if err != nil {
+            return err
+        }
+    }
+}
+
+// 从reader读取tar.gz内容并解压到目录dest，规则同UnzipFile
+func UntarGzReader(reader io.Reader, dest string, option...ArchiveOption) error {
+    gzipReader, err := gzip.NewReader(reader)
+    if err != nil {
+        return err
+    }
+    defer gzipReader.Close()
+    return UntarReader(gzipReader, dest, option...)
+}
+
+// 归档为tar.gz格式
+func tarGzPath(paths string, writer io.Writer, dest string, option ArchiveOption) error {
+    gzipWriter := gzip.NewWriter(writer)
+    if err := tarPath(paths, gzipWriter, dest, option); err != nil {
+        gzipWriter.Close()
+        return err
+    }
+    return gzipWriter.Close()
+}
+
+// 归档为tar格式，dest为归档文件的路径(归档文件位于归档目录中时需要忽略)
+func tarPath(paths string, writer io.Writer, dest string, option ArchiveOption) error {
+    if dest != "" {
+        dest, _ = filepath.Abs(dest)
+    }
+    tarWriter := tar.NewWriter(writer)
+    err       := option.walk(paths, func(entry *archiveEntry) error {
+        if entry.path == dest {
+            return nil
+        }
+        header, err := tar.FileInfoHeader(entry.info, entry.link)
+        if err != nil {
+            return err
+        }
+        header.Name = entry.name
+        if entry.info.IsDir() {
+            header.Name += "/"
+        }
+        if err := tarWriter.WriteHeader(header); err != nil {
+            return err
+        }
+        if !entry.info.Mode().IsRegular() {
+            return nil
+        }
+        file, err := os.Open(entry.path)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        _, err = io.Copy(tarWriter, file)
+        return err
+    })
+    if err != nil {
+        tarWriter.Close()
+        return err
+    }
+    return tarWriter.Close()
+}
@@ -0,0 +1,311 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gcompress_test
+
+import (
+    "archive/tar"
+    "archive/zip"
+    "bytes"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/encoding/gcompress"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+// 创建测试使用的临时目录
+func newTestDir(name string) string {
+    path := fmt.Sprintf("%s/gcompress_%s_%d", gfile.TempDir(), name, time.Now().UnixNano())
+    gfile.Mkdir(path)
+    return path
+}
+
+func Test_Stream(t *testing.T) {
+    data := []byte(strings.Repeat("gf compress stream data. ", 1000))
+    gtest.Case(t, func() {
+        buffer := new(bytes.Buffer)
+        n, err := gcompress.GzipStream(buffer, bytes.NewReader(data))
+        gtest.Assert(err, nil)
+        gtest.Assert(n, len(data))
+        gtest.Assert(buffer.Len() < len(data), true)
+        gtest.Assert(gcompress.UnGzip(buffer.Bytes()), data)
+
+        result := new(bytes.Buffer)
+        _, err  = gcompress.UnGzipStream(result, buffer)
+        gtest.Assert(err, nil)
+        gtest.Assert(result.Bytes(), data)
+    })
+    gtest.Case(t, func() {
+        buffer := new(bytes.Buffer)
+        writer, err := gcompress.ZlibWriter(buffer, 9)
+        gtest.Assert(err, nil)
+        writer.Write(data[ : 100])
+        writer.Write(data[100 : ])
+        gtest.Assert(writer.Close(), nil)
+        gtest.Assert(gcompress.UnZlib(buffer.Bytes()), data)
+
+        result := new(bytes.Buffer)
+        _, err  = gcompress.UnZlibStream(result, bytes.NewReader(gcompress.Zlib(data)))
+        gtest.Assert(err, nil)
+        gtest.Assert(result.Bytes(), data)
+
+        _, err = gcompress.UnGzipStream(result, bytes.NewReader(data))
+        gtest.AssertNE(err, nil)
+    })
+    gtest.Case(t, func() {
+        dir := newTestDir("stream")
+        defer gfile.Remove(dir)
+        src := dir + "/data.txt"
+        gtest.Assert(gfile.PutBinContents(src, data), nil)
+
+        var current, total int64
+        progress := func(c, t int64) {
+            current, total = c, t
+        }
+        gtest.Assert(gcompress.GzipFile(src, dir + "/gz/data.txt.gz", progress), nil)
+        gtest.Assert(current, len(data))
+        gtest.Assert(total, len(data))
+        gtest.Assert(gcompress.UnGzipFile(dir + "/gz/data.txt.gz", dir + "/data.gz.txt"), nil)
+        gtest.Assert(gfile.GetBinContents(dir + "/data.gz.txt"), data)
+
+        gtest.Assert(gcompress.ZlibFile(src, dir + "/data.txt.zlib"), nil)
+        gtest.Assert(gcompress.UnZlibFile(dir + "/data.txt.zlib", dir + "/data.zlib.txt", progress), nil)
+        gtest.Assert(current, total)
+        gtest.Assert(gfile.GetBinContents(dir + "/data.zlib.txt"), data)
+
+        gtest.AssertNE(gcompress.GzipFile(dir + "/none.txt", dir + "/none.gz"), nil)
+    })
+}
+
+// 创建用于归档测试的目录结构
+func newArchiveTestDir(name string) string {
+    dir := newTestDir(name)
+    gfile.PutContents(dir + "/www/index.html", "index")
+    gfile.PutContents(dir + "/www/static/app.js", "app")
+    gfile.PutContents(dir + "/www/runtime/error.log", "error")
+    gfile.PutContents(dir + "/www/.git/config", "git")
+    gfile.PutContents(dir + "/www/run.sh", "#!/bin/sh")
+    os.Chmod(dir + "/www/run.sh", 0750)
+    os.Symlink("index.html", dir + "/www/link.html")
+    mtime := time.Date(2019, 1, 2, 3, 4, 5, 0, time.Local)
+    os.Chtimes(dir + "/www/index.html", mtime, mtime)
+    os.Chtimes(dir + "/www/static", mtime, mtime)
+    return dir
+}
+
+// 校验解压后的目录结构
+func checkArchiveTestDir(dir string) {
+    gtest.Assert(gfile.GetContents(dir + "/www/index.html"), "index")
+    gtest.Assert(gfile.GetContents(dir + "/www/static/app.js"), "app")
+    gtest.Assert(gfile.Exists(dir + "/www/runtime/error.log"), false)
+    gtest.Assert(gfile.Exists(dir + "/www/.git"), false)
+    info, _ := os.Stat(dir + "/www/run.sh")
+    gtest.Assert(info.Mode().Perm(), os.FileMode(0750))
+    info, _ = os.Stat(dir + "/www/index.html")
+    gtest.Assert(info.ModTime().Unix(), time.Date(2019, 1, 2, 3, 4, 5, 0, time.Local).Unix())
+    info, _ = os.Stat(dir + "/www/static")
+    gtest.Assert(info.ModTime().Unix(), time.Date(2019, 1, 2, 3, 4, 5, 0, time.Local).Unix())
+    link, _ := os.Readlink(dir + "/www/link.html")
+    gtest.Assert(link, "index.html")
+}
+
+func Test_Zip(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := newArchiveTestDir("zip")
+        defer gfile.Remove(dir)
+        option := gcompress.ArchiveOption {
+            Excludes : []string{"*.log", ".git"},
+        }
+        gtest.Assert(gcompress.ZipPath(dir + "/www", dir + "/www.zip", option), nil)
+
+        names := make([]string, 0)
+        gtest.Assert(gcompress.UnzipFile(dir + "/www.zip", dir + "/unzip", gcompress.ArchiveOption {
+            Callback : func(name string, info os.FileInfo) error {
+                names = append(names, name)
+                return nil
+            },
+        }), nil)
+        gtest.AssertIN("www/static/app.js", names)
+        gtest.AssertIN("www/runtime/", names)
+        checkArchiveTestDir(dir + "/unzip")
+
+        // 路径前缀及跳过条目
+        buffer := new(bytes.Buffer)
+        gtest.Assert(gcompress.ZipPathWriter(dir + "/www/index.html," + dir + "/www/static", buffer, gcompress.ArchiveOption {
+            Prefix   : "backup",
+            Callback : func(name string, info os.FileInfo) error {
+                if name == "backup/static" {
+                    return gcompress.ErrSkipEntry
+                }
+                return nil
+            },
+        }), nil)
+        gtest.Assert(gcompress.UnzipContent(buffer.Bytes(), dir + "/prefix"), nil)
+        gtest.Assert(gfile.GetContents(dir + "/prefix/backup/index.html"), "index")
+        gtest.Assert(gfile.Exists(dir + "/prefix/backup/static"), false)
+
+        // 回调返回错误时中断
+        err := gcompress.UnzipFile(dir + "/www.zip", dir + "/abort", gcompress.ArchiveOption {
+            Callback : func(name string, info os.FileInfo) error {
+                return errors.New("abort")
+            },
+        })
+        gtest.Assert(err.Error(), "abort")
+    })
+}
+
+// 创建包含指定条目的ZIP内容，条目的值为符号链接目标时名称以"@"开头，例如: {"a", "@/tmp"}
+func newZipContent(entries [][2]string) []byte {
+    buffer := new(bytes.Buffer)
+    writer := zip.NewWriter(buffer)
+    for _, entry := range entries {
+        header := &zip.FileHeader{Name : entry[0], Method : zip.Deflate}
+        header.SetMode(0644)
+        content := entry[1]
+        if strings.HasPrefix(content, "@") {
+            header.SetMode(os.ModeSymlink | 0777)
+            content = content[1 : ]
+        }
+        w, _ := writer.CreateHeader(header)
+        w.Write([]byte(content))
+    }
+    writer.Close()
+    return buffer.Bytes()
+}
+
+func Test_Zip_Symlink(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := newTestDir("zip_symlink")
+        defer gfile.Remove(dir)
+        outside := dir + "/outside"
+        gfile.Mkdir(outside)
+        // 符号链接指向目标目录之外(绝对路径及相对路径)
+        gtest.AssertNE(gcompress.UnzipContent(newZipContent([][2]string{{"a", "@" + outside}}), dir + "/abs"), nil)
+        gtest.Assert(gfile.Exists(dir + "/abs/a"), false)
+        gtest.AssertNE(gcompress.UnzipContent(newZipContent([][2]string{{"a/b", "@../../outside"}}), dir + "/rel"), nil)
+        gtest.Assert(gfile.Exists(dir + "/rel/a/b"), false)
+        // 通过已解压的符号链接写入文件
+        gfile.Mkdir(dir + "/through/sub")
+        os.Symlink(outside, dir + "/through/a")
+        gtest.AssertNE(gcompress.UnzipContent(newZipContent([][2]string{{"a/evil", "evil"}}), dir + "/through"), nil)
+        gtest.AssertNE(gcompress.UnzipContent(newZipContent([][2]string{{"b", "@sub"}, {"b/evil", "evil"}}), dir + "/through"), nil)
+        gtest.Assert(gfile.Exists(outside + "/evil"), false)
+        gtest.Assert(gfile.Exists(dir + "/through/sub/evil"), false)
+        // 覆盖已存在的符号链接文件时不跟随符号链接
+        gfile.PutContents(dir + "/over/target", "target")
+        os.Symlink("target", dir + "/over/file")
+        gtest.Assert(gcompress.UnzipContent(newZipContent([][2]string{{"file", "new"}}), dir + "/over"), nil)
+        gtest.Assert(gfile.GetContents(dir + "/over/target"), "target")
+        gtest.Assert(gfile.GetContents(dir + "/over/file"), "new")
+        // 目标目录中的相对符号链接正常解压
+        gtest.Assert(gcompress.UnzipContent(newZipContent([][2]string{{"x/y", "y"}, {"x/z", "@y"}, {"w", "@x/y"}}), dir + "/ok"), nil)
+        gtest.Assert(gfile.GetContents(dir + "/ok/x/z"), "y")
+        gtest.Assert(gfile.GetContents(dir + "/ok/w"), "y")
+    })
+}
+
+func Test_Tar(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := newArchiveTestDir("tar")
+        defer gfile.Remove(dir)
+        option := gcompress.ArchiveOption {
+            Excludes : []string{"runtime/*.log", ".git"},
+        }
+        gtest.Assert(gcompress.Tar(dir + "/www", dir + "/www.tar", option), nil)
+        gtest.Assert(gcompress.Untar(dir + "/www.tar", dir + "/untar"), nil)
+        checkArchiveTestDir(dir + "/untar")
+
+        // 归档文件位于归档目录中时忽略自身
+        gtest.Assert(gcompress.TarGz(dir + "/www", dir + "/www/www.tar.gz", option), nil)
+        gtest.Assert(gcompress.UntarGz(dir + "/www/www.tar.gz", dir + "/untargz"), nil)
+        checkArchiveTestDir(dir + "/untargz")
+        gtest.Assert(gfile.Exists(dir + "/untargz/www/www.tar.gz"), false)
+
+        // 解压时排除
+        buffer := new(bytes.Buffer)
+        gtest.Assert(gcompress.TarGzWriter(dir + "/www", buffer), nil)
+        gtest.Assert(gcompress.UntarGzReader(buffer, dir + "/exclude", gcompress.ArchiveOption {
+            Excludes : []string{"static", "*.log", ".git", "*.gz"},
+        }), nil)
+        gtest.Assert(gfile.Exists(dir + "/exclude/www/static"), false)
+        gtest.Assert(gfile.Exists(dir + "/exclude/www/.git/config"), false)
+        gtest.Assert(gfile.GetContents(dir + "/exclude/www/index.html"), "index")
+    })
+    gtest.Case(t, func() {
+        // 条目路径跳出目标目录时报错
+        dir := newTestDir("tar_slip")
+        defer gfile.Remove(dir)
+        gfile.PutContents(dir + "/a/evil.txt", "evil")
+        buffer := new(bytes.Buffer)
+        gtest.Assert(gcompress.TarWriter(dir + "/a/evil.txt", buffer, gcompress.ArchiveOption{Prefix : "../.."}), nil)
+        gtest.AssertNE(gcompress.UntarReader(buffer, dir + "/b"), nil)
+        gtest.Assert(gfile.Exists(dir + "/../evil.txt"), false)
+    })
+}
+
+// 创建包含指定条目的tar内容，条目的值以"@"开头时为符号链接，以"="开头时为硬链接，名称以"/"结尾时为目录
+func newTarContent(entries [][2]string) *bytes.Buffer {
+    buffer := new(bytes.Buffer)
+    writer := tar.NewWriter(buffer)
+    for _, entry := range entries {
+        header  := &tar.Header{Name : entry[0], Mode : 0644, Typeflag : tar.TypeReg}
+        content := entry[1]
+        switch {
+            case strings.HasSuffix(entry[0], "/"):
+                header.Typeflag, header.Mode = tar.TypeDir, 0755
+            case strings.HasPrefix(content, "@"):
+                header.Typeflag, header.Linkname, content = tar.TypeSymlink, content[1 : ], ""
+            case strings.HasPrefix(content, "="):
+                header.Typeflag, header.Linkname, content = tar.TypeLink, content[1 : ], ""
+        }
+        header.Size = int64(len(content))
+        writer.WriteHeader(header)
+        writer.Write([]byte(content))
+    }
+    writer.Close()
+    return buffer
+}
+
+func Test_Tar_Link(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := newTestDir("tar_link")
+        defer gfile.Remove(dir)
+        outside := dir + "/outside"
+        gfile.PutContents(outside + "/f", "outside")
+        // 符号链接指向目标目录之外，或者通过符号链接写入文件
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"a", "@" + outside}, {"a/evil", "evil"}}), dir + "/abs"), nil)
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"a", "@../outside"}, {"a/evil", "evil"}}), dir + "/rel"), nil)
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"a", "@."}, {"a/evil", "evil"}}), dir + "/self"), nil)
+        gtest.Assert(gfile.Exists(outside + "/evil"), false)
+        gtest.Assert(gfile.Exists(dir + "/self/evil"), false)
+        // 通过符号链接链跳出目标目录: sub/m -> l/../.. 中的l指向sub，系统解析结果为目标目录的上级目录
+        gfile.PutContents(dir + "/secret.txt", "secret")
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"sub/", ""}, {"sub/l", "@."}, {"sub/m", "@l/../.."}}), dir + "/chain"), nil)
+        gtest.Assert(gfile.Exists(dir + "/chain/sub/m"), false)
+        gtest.Assert(gfile.Exists(dir + "/chain/sub/m/secret.txt"), false)
+        // 开头的".."指向目标目录中的路径时正常解压
+        gtest.Assert(gcompress.UntarReader(newTarContent([][2]string{{"x/y", "y"}, {"z/l", "@../x/y"}}), dir + "/up"), nil)
+        gtest.Assert(gfile.GetContents(dir + "/up/z/l"), "y")
+        // 硬链接指向已解压的文件
+        gtest.Assert(gcompress.UntarReader(newTarContent([][2]string{{"x/a", "content"}, {"b", "=x/a"}}), dir + "/hard"), nil)
+        gtest.Assert(gfile.GetContents(dir + "/hard/b"), "content")
+        info1, _ := os.Stat(dir + "/hard/x/a")
+        info2, _ := os.Stat(dir + "/hard/b")
+        gtest.Assert(os.SameFile(info1, info2), true)
+        // 硬链接指向目标目录之外、不存在的文件或者符号链接
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"b", "=../outside/f"}}), dir + "/hard1"), nil)
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"b", "=none"}}), dir + "/hard2"), nil)
+        gtest.AssertNE(gcompress.UntarReader(newTarContent([][2]string{{"a", "@x"}, {"x", "x"}, {"b", "=a"}}), dir + "/hard3"), nil)
+        gtest.Assert(gfile.GetContents(outside + "/f"), "outside")
+    })
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcompress
+
+import (
+    "archive/zip"
+    "bytes"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+)
+
+// 将文件/目录压缩为ZIP文件dest，paths支持使用逗号分隔多个文件/目录路径，目录归档时包含目录名称本身，
+// 归档条目保留文件的权限及修改时间，符号链接按照链接保存(不跟随)，option为可选的归档选项。
+func ZipPath(paths, dest string, option...ArchiveOption) error {
+    return archiveFile(dest, func(writer io.Writer, dest string) error {
+        return zipPath(paths, writer, dest, getArchiveOption(option))
+    })
+}
+
+// 将文件/目录压缩为ZIP格式并写入writer，规则同ZipPath
+func ZipPathWriter(paths string, writer io.Writer, option...ArchiveOption) error {
+    return zipPath(paths, writer, "", getArchiveOption(option))
+}
+
+// 压缩文件/目录，dest为归档文件的路径(归档文件位于压缩目录中时需要忽略)
+func zipPath(paths string, writer io.Writer, dest string, option ArchiveOption) error {
+    if dest != "" {
+        dest, _ = filepath.Abs(dest)
+    }
+    zipWriter := zip.NewWriter(writer)
+    err       := option.walk(paths, func(entry *archiveEntry) error {
+        if entry.path == dest {
+            return nil
+        }
+        header, err := zip.FileInfoHeader(entry.info)
+        if err != nil {
+            return err
+        }
+        header.Name = entry.name
+        switch {
+            case entry.info.IsDir():
+                header.Name  += "/"
+                header.Method = zip.Store
+            case entry.link != "":
+                header.Method = zip.Store
+            default:
+                header.Method = zip.Deflate
+        }
+        w, err := zipWriter.CreateHeader(header)
+        if err != nil {
+            return err
+        }
+        switch {
+            case entry.info.IsDir():
+                return nil
+            case entry.link != "":
+                // 符号链接的内容为链接目标
+                _, err = w.Write([]byte(entry.link))
+                return err
+        }
+        file, err := os.Open(entry.path)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        _, err = io.Copy(w, file)
+        return err
+    })
+    if err != nil {
+        zipWriter.Close()
+        return err
+    }
+    return zipWriter.Close()
+}
+
+// 将ZIP文件解压到目录dest(不存在时自动创建)，还原文件的权限及修改时间，option为可选的归档选项
+func UnzipFile(archive, dest string, option...ArchiveOption) error {
+    reader, err := zip.OpenReader(archive)
+    if err != nil {
+        return err
+    }
+    defer reader.Close()
+    return unzip(&reader.Reader, dest, getArchiveOption(option))
+}
+
+// 将ZIP内容解压到目录dest，规则同UnzipFile
+func UnzipContent(data []byte, dest string, option...ArchiveOption) error {
+    reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+    if err != nil {
+        return err
+    }
+    return unzip(reader, dest, getArchiveOption(option))
+}
+
+// 解压ZIP内容
+func unzip(reader *zip.Reader, dest string, option ArchiveOption) error {
+    extractor, err := newArchiveExtractor(dest, option)
+    if err != nil {
+        return err
+    }
+    defer extractor.finish()
+    for _, file := range reader.File {
+        if err := unzipEntry(extractor, file); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 解压单个ZIP条目
+func unzipEntry(extractor *archiveExtractor, file *zip.File) error {
+    info := file.FileInfo()
+    if info.IsDir() {
+        return extractor.extract(file.Name, info, "", nil)
+    }
+    r, err := file.Open()
+    if err != nil {
+        return err
+    }
+    defer r.Close()
+    link := ""
+    if info.Mode() & os.ModeSymlink != 0 {
+        b, err := ioutil.ReadAll(r)
+        if err != nil {
+            return err
+        }
+        link = string(b)
+    }
+    return extractor.extract(file.Name, info, link, r)
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gencode provides unified API for base64/base64url/base32/hex encoding/decoding.
+//
+// 统一的编码/解码接口，所有编码均提供[]byte及string的编码/解码方法，并支持填充(=)控制，例如:
+//     gencode.Base64URL.WithPadding(false).EncodeToString(data)
+//     gencode.Hex.DecodeString("676f")
+package gencode
+
+import (
+    "encoding/base32"
+    "encoding/base64"
+    "encoding/hex"
+    "strings"
+)
+
+// 编码器需要实现的接口，base64.Encoding/base32.Encoding均已实现
+type codec interface {
+    EncodeToString(src []byte) string
+    DecodeString(s string) ([]byte, error)
+}
+
+// 编码对象，不同填充设置的编码对象通过WithPadding获得，编码对象是并发安全的
+type Encoding struct {
+    padded  codec // 带填充的编码器
+    raw     codec // 不带填充的编码器
+    padding bool  // 编码时是否填充
+}
+
+var (
+    // 标准base64编码(RFC 4648)，带填充
+    Base64    = &Encoding{base64.StdEncoding, base64.RawStdEncoding, true}
+    // URL安全的base64编码(使用-及_替代+及/)，带填充，如需用于URL及token一般使用WithPadding(false)
+    Base64URL = &Encoding{base64.URLEncoding, base64.RawURLEncoding, true}
+    // 标准base32编码，带填充
+    Base32    = &Encoding{base32.StdEncoding, base32.StdEncoding.WithPadding(base32.NoPadding), true}
+    // 使用扩展十六进制字母表的base32编码(保持排序性)，带填充
+    Base32Hex = &Encoding{base32.HexEncoding, base32.HexEncoding.WithPadding(base32.NoPadding), true}
+    // 十六进制编码(小写)，没有填充的概念，WithPadding对其无影响，解码时不区分大小写
+    Hex       = &Encoding{hexCodec{}, hexCodec{}, false}
+)
+
+// 返回指定填充设置的编码对象(不修改当前对象)
+func (e *Encoding) WithPadding(padding bool) *Encoding {
+    return &Encoding {
+        padded  : e.padded,
+        raw     : e.raw,
+        padding : padding,
+    }
+}
+
+// 编码[]byte，返回编码后的[]byte
+func (e *Encoding) Encode(src []byte) []byte {
+    return []byte(e.EncodeToString(src))
+}
+
+// 编码[]byte，返回编码后的字符串
+func (e *Encoding) EncodeToString(src []byte) string {
+    if e.padding {
+        return e.padded.EncodeToString(src)
+    }
+    return e.raw.EncodeToString(src)
+}
+
+// 编码字符串，返回编码后的字符串
+func (e *Encoding) EncodeString(src string) string {
+    return e.EncodeToString([]byte(src))
+}
+
+// 解码[]byte，返回解码后的[]byte，解码时不区分数据是否带有填充
+func (e *Encoding) Decode(src []byte) ([]byte, error) {
+    return e.DecodeToBytes(string(src))
+}
+
+// 解码字符串，返回解码后的[]byte，解码时不区分数据是否带有填充
+func (e *Encoding) DecodeToBytes(src string) ([]byte, error) {
+    return e.raw.DecodeString(strings.TrimRight(src, "="))
+}
+
+// 解码字符串，返回解码后的字符串，解码时不区分数据是否带有填充
+func (e *Encoding) DecodeString(src string) (string, error) {
+    b, err := e.DecodeToBytes(src)
+    return string(b), err
+}
+
+// 十六进制编码器
+type hexCodec struct {}
+
+func (hexCodec) EncodeToString(src []byte) string {
+    return hex.EncodeToString(src)
+}
+
+func (hexCodec) DecodeString(s string) ([]byte, error) {
+    return hex.DecodeString(s)
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gencode_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/encoding/gencode"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Encoding(t *testing.T) {
+    gtest.Case(t, func() {
+        data := []byte{0xfb, 0xff, 0x01}
+        gtest.Assert(gencode.Base64.EncodeToString(data),    "+/8B")
+        gtest.Assert(gencode.Base64URL.EncodeToString(data), "-_8B")
+        gtest.Assert(gencode.Base64.EncodeString("gf"),      "Z2Y=")
+        gtest.Assert(gencode.Base64.WithPadding(false).EncodeString("gf"), "Z2Y")
+        gtest.Assert(gencode.Base64.EncodeString("gf"),      "Z2Y=")
+        gtest.Assert(gencode.Base32.EncodeString("gf"),      "M5TA====")
+        gtest.Assert(gencode.Base32.WithPadding(false).EncodeString("gf"), "M5TA")
+        gtest.Assert(gencode.Base32Hex.EncodeString("gf"),   "CTJ0====")
+        gtest.Assert(gencode.Hex.EncodeString("gf"),         "6766")
+        gtest.Assert(gencode.Hex.WithPadding(true).EncodeString("gf"), "6766")
+        gtest.Assert(gencode.Hex.Encode([]byte("gf")),       []byte("6766"))
+    })
+}
+
+func Test_Decoding(t *testing.T) {
+    gtest.Case(t, func() {
+        for _, e := range []*gencode.Encoding{gencode.Base64, gencode.Base64URL, gencode.Base32, gencode.Base32Hex, gencode.Hex} {
+            for _, padding := range []bool{true, false} {
+                encoded := e.WithPadding(padding).EncodeString("GoFrame")
+                // 解码时不区分是否带有填充
+                s, err := e.DecodeString(encoded)
+                gtest.Assert(err, nil)
+                gtest.Assert(s, "GoFrame")
+                b, err := e.WithPadding(!padding).Decode([]byte(encoded))
+                gtest.Assert(err, nil)
+                gtest.Assert(string(b), "GoFrame")
+            }
+        }
+        b, err := gencode.Base64URL.DecodeToBytes("-_8B")
+        gtest.Assert(err, nil)
+        gtest.Assert(b, []byte{0xfb, 0xff, 0x01})
+        s, err := gencode.Hex.DecodeString("6F6b")
+        gtest.Assert(err, nil)
+        gtest.Assert(s, "ok")
+        _, err  = gencode.Base64.DecodeString("!!")
+        gtest.AssertNE(err, nil)
+        _, err  = gencode.Hex.DecodeString("zz")
+        gtest.AssertNE(err, nil)
+    })
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghash
+
+import (
+    "hash"
+    "hash/crc64"
+)
+
+var (
+    // CRC64校验使用的多项式表
+    crc64EcmaTable = crc64.MakeTable(crc64.ECMA)
+    crc64IsoTable  = crc64.MakeTable(crc64.ISO)
+)
+
+// CRC64校验值(ECMA多项式，与xz/ECMA-182一致)
+func CRC64(data []byte) uint64 {
+    return crc64.Checksum(data, crc64EcmaTable)
+}
+
+// CRC64校验值(ISO多项式)
+func CRC64ISO(data []byte) uint64 {
+    return crc64.Checksum(data, crc64IsoTable)
+}
+
+// 创建流式计算的CRC64校验对象(实现hash.Hash64/io.Writer接口)，iso为true时使用ISO多项式，默认使用ECMA多项式
+func NewCRC64(iso...bool) hash.Hash64 {
+    if len(iso) > 0 && iso[0] {
+        return crc64.New(crc64IsoTable)
+    }
+    return crc64.New(crc64EcmaTable)
+}
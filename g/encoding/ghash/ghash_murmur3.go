@@ -0,0 +1,254 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghash
+
+import (
+    "encoding/binary"
+    "hash"
+    "math/bits"
+)
+
+const (
+    murmur3C1_32  = 0xcc9e2d51
+    murmur3C2_32  = 0x1b873593
+    murmur3C1_128 = 0x87c37b91114253d5
+    murmur3C2_128 = 0x4cf5ad432745937f
+)
+
+// MurmurHash3 32位哈希(x86_32)，seed为可选的种子(默认为0)
+func Murmur3Hash32(data []byte, seed...uint32) uint32 {
+    h := NewMurmur3Hash32(seed...)
+    h.Write(data)
+    return h.Sum32()
+}
+
+// MurmurHash3 128位哈希(x64_128)，返回高低两个64位的值，seed为可选的种子(默认为0)
+func Murmur3Hash128(data []byte, seed...uint64) (uint64, uint64) {
+    h := NewMurmur3Hash128(seed...)
+    h.Write(data)
+    return h.(*murmur3Hash128).Sum128()
+}
+
+// 创建流式计算的MurmurHash3 32位哈希对象(实现hash.Hash32/io.Writer接口)
+func NewMurmur3Hash32(seed...uint32) hash.Hash32 {
+    h := &murmur3Hash32{}
+    if len(seed) > 0 {
+        h.seed = seed[0]
+    }
+    h.Reset()
+    return h
+}
+
+// 创建流式计算的MurmurHash3 128位哈希对象(实现hash.Hash/io.Writer接口)，Sum结果为大端字节序的h1+h2
+func NewMurmur3Hash128(seed...uint64) hash.Hash {
+    h := &murmur3Hash128{}
+    if len(seed) > 0 {
+        h.seed = seed[0]
+    }
+    h.Reset()
+    return h
+}
+
+// MurmurHash3 32位哈希的计算状态
+type murmur3Hash32 struct {
+    seed   uint32
+    h      uint32
+    length uint64
+    tail   []byte // 未满一个数据块(4字节)的剩余数据
+}
+
+func (m *murmur3Hash32) Write(p []byte) (int, error) {
+    n := len(p)
+    m.length += uint64(n)
+    if len(m.tail) > 0 {
+        need := 4 - len(m.tail)
+        if len(p) < need {
+            m.tail = append(m.tail, p...)
+            return n, nil
+        }
+        m.tail = append(m.tail, p[ : need]...)
+        m.block(binary.LittleEndian.Uint32(m.tail))
+        m.tail = m.tail[ : 0]
+        p      = p[need : ]
+    }
+    for ; len(p) >= 4; p = p[4 : ] {
+        m.block(binary.LittleEndian.Uint32(p))
+    }
+    m.tail = append(m.tail, p...)
+    return n, nil
+}
+
+func (m *murmur3Hash32) block(k uint32) {
+    k *= murmur3C1_32
+    k  = bits.RotateLeft32(k, 15)
+    k *= murmur3C2_32
+    m.h ^= k
+    m.h  = bits.RotateLeft32(m.h, 13)
+    m.h  = m.h * 5 + 0xe6546b64
+}
+
+func (m *murmur3Hash32) Sum32() uint32 {
+    h := m.h
+    k := uint32(0)
+    switch len(m.tail) {
+        case 3:
+            k ^= uint32(m.tail[2]) << 16
+            fallthrough
+        case 2:
+            k ^= uint32(m.tail[1]) << 8
+            fallthrough
+        case 1:
+            k ^= uint32(m.tail[0])
+            k *= murmur3C1_32
+            k  = bits.RotateLeft32(k, 15)
+            k *= murmur3C2_32
+            h ^= k
+    }
+    h ^= uint32(m.length)
+    h ^= h >> 16
+    h *= 0x85ebca6b
+    h ^= h >> 13
+    h *= 0xc2b2ae35
+    h ^= h >> 16
+    return h
+}
+
+func (m *murmur3Hash32) Sum(b []byte) []byte {
+    h := m.Sum32()
+    return append(b, byte(h >> 24), byte(h >> 16), byte(h >> 8), byte(h))
+}
+
+func (m *murmur3Hash32) Reset() {
+    m.h      = m.seed
+    m.length = 0
+    m.tail   = make([]byte, 0, 4)
+}
+
+func (m *murmur3Hash32) Size() int {
+    return 4
+}
+
+func (m *murmur3Hash32) BlockSize() int {
+    return 4
+}
+
+// MurmurHash3 128位哈希的计算状态
+type murmur3Hash128 struct {
+    seed   uint64
+    h1     uint64
+    h2     uint64
+    length uint64
+    tail   []byte // 未满一个数据块(16字节)的剩余数据
+}
+
+func (m *murmur3Hash128) Write(p []byte) (int, error) {
+    n := len(p)
+    m.length += uint64(n)
+    if len(m.tail) > 0 {
+        need := 16 - len(m.tail)
+        if len(p) < need {
+            m.tail = append(m.tail, p...)
+            return n, nil
+        }
+        m.tail = append(m.tail, p[ : need]...)
+        m.block(m.tail)
+        m.tail = m.tail[ : 0]
+        p      = p[need : ]
+    }
+    for ; len(p) >= 16; p = p[16 : ] {
+        m.block(p)
+    }
+    m.tail = append(m.tail, p...)
+    return n, nil
+}
+
+func (m *murmur3Hash128) block(p []byte) {
+    k1 := binary.LittleEndian.Uint64(p)
+    k2 := binary.LittleEndian.Uint64(p[8 : ])
+
+    k1 *= murmur3C1_128
+    k1  = bits.RotateLeft64(k1, 31)
+    k1 *= murmur3C2_128
+    m.h1 ^= k1
+    m.h1  = bits.RotateLeft64(m.h1, 27)
+    m.h1 += m.h2
+    m.h1  = m.h1 * 5 + 0x52dce729
+
+    k2 *= murmur3C2_128
+    k2  = bits.RotateLeft64(k2, 33)
+    k2 *= murmur3C1_128
+    m.h2 ^= k2
+    m.h2  = bits.RotateLeft64(m.h2, 31)
+    m.h2 += m.h1
+    m.h2  = m.h2 * 5 + 0x38495ab5
+}
+
+// 返回128位哈希值的高低两个64位的值
+func (m *murmur3Hash128) Sum128() (uint64, uint64) {
+    h1, h2 := m.h1, m.h2
+    k1, k2 := uint64(0), uint64(0)
+    tail   := m.tail
+    for i := 8; i < len(tail); i++ {
+        k2 ^= uint64(tail[i]) << (uint(i - 8) * 8)
+    }
+    if len(tail) > 8 {
+        k2 *= murmur3C2_128
+        k2  = bits.RotateLeft64(k2, 33)
+        k2 *= murmur3C1_128
+        h2 ^= k2
+    }
+    for i := 0; i < len(tail) && i < 8; i++ {
+        k1 ^= uint64(tail[i]) << (uint(i) * 8)
+    }
+    if len(tail) > 0 {
+        k1 *= murmur3C1_128
+        k1  = bits.RotateLeft64(k1, 31)
+        k1 *= murmur3C2_128
+        h1 ^= k1
+    }
+    h1 ^= m.length
+    h2 ^= m.length
+    h1 += h2
+    h2 += h1
+    h1  = fmix64(h1)
+    h2  = fmix64(h2)
+    h1 += h2
+    h2 += h1
+    return h1, h2
+}
+
+func (m *murmur3Hash128) Sum(b []byte) []byte {
+    h1, h2 := m.Sum128()
+    b = append(b, make([]byte, 16)...)
+    binary.BigEndian.PutUint64(b[len(b) - 16 : ], h1)
+    binary.BigEndian.PutUint64(b[len(b) - 8 : ], h2)
+    return b
+}
+
+func (m *murmur3Hash128) Reset() {
+    m.h1     = m.seed
+    m.h2     = m.seed
+    m.length = 0
+    m.tail   = make([]byte, 0, 16)
+}
+
+func (m *murmur3Hash128) Size() int {
+    return 16
+}
+
+func (m *murmur3Hash128) BlockSize() int {
+    return 16
+}
+
+func fmix64(k uint64) uint64 {
+    k ^= k >> 33
+    k *= 0xff51afd7ed558ccd
+    k ^= k >> 33
+    k *= 0xc4ceb9fe1a85ec53
+    k ^= k >> 33
+    return k
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package ghash_test
+
+import (
+    "io"
+    "strings"
+    "testing"
+    "github.com/gogf/gf/g/encoding/ghash"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+// 分多次写入数据，校验流式计算的结果
+func writeInPieces(w io.Writer, data string) {
+    for i := 0; i < len(data); i += 7 {
+        end := i + 7
+        if end > len(data) {
+            end = len(data)
+        }
+        w.Write([]byte(data[i : end]))
+    }
+}
+
+func Test_Murmur3(t *testing.T) {
+    fox := "The quick brown fox jumps over the lazy dog"
+    gtest.Case(t, func() {
+        gtest.Assert(ghash.Murmur3Hash32([]byte("")), 0)
+        gtest.Assert(ghash.Murmur3Hash32([]byte(""), 1), 0x514e28b7)
+        gtest.Assert(ghash.Murmur3Hash32([]byte("hello")), 0x248bfa47)
+        gtest.Assert(ghash.Murmur3Hash32([]byte(fox)), 0x2e4ff723)
+
+        h := ghash.NewMurmur3Hash32()
+        writeInPieces(h, fox)
+        gtest.Assert(h.Sum32(), 0x2e4ff723)
+        gtest.Assert(h.Sum(nil), []byte{0x2e, 0x4f, 0xf7, 0x23})
+        h.Reset()
+        gtest.Assert(h.Sum32(), 0)
+    })
+    gtest.Case(t, func() {
+        h1, h2 := ghash.Murmur3Hash128([]byte(""))
+        gtest.Assert(h1, 0)
+        gtest.Assert(h2, 0)
+        h1, h2  = ghash.Murmur3Hash128([]byte("hello"))
+        gtest.Assert(h1, uint64(0xcbd8a7b341bd9b02))
+        gtest.Assert(h2, uint64(0x5b1e906a48ae1d19))
+        h1, h2  = ghash.Murmur3Hash128([]byte(fox))
+        gtest.Assert(h1, uint64(0xe34bbc7bbc071b6c))
+        gtest.Assert(h2, uint64(0x7a433ca9c49a9347))
+
+        h := ghash.NewMurmur3Hash128()
+        writeInPieces(h, fox)
+        gtest.Assert(h.Sum(nil), []byte{0xe3, 0x4b, 0xbc, 0x7b, 0xbc, 0x07, 0x1b, 0x6c, 0x7a, 0x43, 0x3c, 0xa9, 0xc4, 0x9a, 0x93, 0x47})
+    })
+}
+
+func Test_XXHash64(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(ghash.XXHash64([]byte("")), uint64(0xef46db3751d8e999))
+        gtest.Assert(ghash.XXHash64([]byte("abc")), uint64(0x44bc2cf5ad770999))
+        gtest.Assert(ghash.XXHash64([]byte("Nobody inspects the spammish repetition")), uint64(0xfbcea83c8a378bf1))
+
+        data := strings.Repeat("gf xxhash streaming ", 10)
+        h    := ghash.NewXXHash64(1)
+        writeInPieces(h, data)
+        gtest.Assert(h.Sum64(), ghash.XXHash64([]byte(data), 1))
+        gtest.AssertNE(h.Sum64(), ghash.XXHash64([]byte(data)))
+    })
+}
+
+func Test_CRC64(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(ghash.CRC64([]byte("123456789")), uint64(0x995dc9bbdf1939fa))
+        gtest.Assert(ghash.CRC64ISO([]byte("123456789")), uint64(0xb90956c775a41001))
+
+        h := ghash.NewCRC64()
+        writeInPieces(h, "123456789")
+        gtest.Assert(h.Sum64(), uint64(0x995dc9bbdf1939fa))
+        h = ghash.NewCRC64(true)
+        writeInPieces(h, "123456789")
+        gtest.Assert(h.Sum64(), uint64(0xb90956c775a41001))
+    })
+}
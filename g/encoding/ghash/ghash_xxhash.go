@@ -0,0 +1,147 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghash
+
+import (
+    "encoding/binary"
+    "hash"
+    "math/bits"
+)
+
+const (
+    xxPrime1 uint64 = 11400714785074694791
+    xxPrime2 uint64 = 14029467366897019727
+    xxPrime3 uint64 = 1609587929392839161
+    xxPrime4 uint64 = 9650029242287828579
+    xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxHash 64位哈希，seed为可选的种子(默认为0)
+func XXHash64(data []byte, seed...uint64) uint64 {
+    h := NewXXHash64(seed...)
+    h.Write(data)
+    return h.Sum64()
+}
+
+// 创建流式计算的xxHash 64位哈希对象(实现hash.Hash64/io.Writer接口)
+func NewXXHash64(seed...uint64) hash.Hash64 {
+    h := &xxHash64{}
+    if len(seed) > 0 {
+        h.seed = seed[0]
+    }
+    h.Reset()
+    return h
+}
+
+// xxHash 64位哈希的计算状态
+type xxHash64 struct {
+    seed   uint64
+    v1     uint64
+    v2     uint64
+    v3     uint64
+    v4     uint64
+    length uint64
+    tail   []byte // 未满一个数据块(32字节)的剩余数据
+}
+
+func (x *xxHash64) Write(p []byte) (int, error) {
+    n := len(p)
+    x.length += uint64(n)
+    if len(x.tail) > 0 {
+        need := 32 - len(x.tail)
+        if len(p) < need {
+            x.tail = append(x.tail, p...)
+            return n, nil
+        }
+        x.tail = append(x.tail, p[ : need]...)
+        x.block(x.tail)
+        x.tail = x.tail[ : 0]
+        p      = p[need : ]
+    }
+    for ; len(p) >= 32; p = p[32 : ] {
+        x.block(p)
+    }
+    x.tail = append(x.tail, p...)
+    return n, nil
+}
+
+func (x *xxHash64) block(p []byte) {
+    x.v1 = xxRound(x.v1, binary.LittleEndian.Uint64(p))
+    x.v2 = xxRound(x.v2, binary.LittleEndian.Uint64(p[8 : ]))
+    x.v3 = xxRound(x.v3, binary.LittleEndian.Uint64(p[16 : ]))
+    x.v4 = xxRound(x.v4, binary.LittleEndian.Uint64(p[24 : ]))
+}
+
+func (x *xxHash64) Sum64() uint64 {
+    var h uint64
+    if x.length >= 32 {
+        h = bits.RotateLeft64(x.v1, 1) + bits.RotateLeft64(x.v2, 7) + bits.RotateLeft64(x.v3, 12) + bits.RotateLeft64(x.v4, 18)
+        h = xxMergeRound(h, x.v1)
+        h = xxMergeRound(h, x.v2)
+        h = xxMergeRound(h, x.v3)
+        h = xxMergeRound(h, x.v4)
+    } else {
+        h = x.seed + xxPrime5
+    }
+    h += x.length
+    p := x.tail
+    for ; len(p) >= 8; p = p[8 : ] {
+        h ^= xxRound(0, binary.LittleEndian.Uint64(p))
+        h  = bits.RotateLeft64(h, 27) * xxPrime1 + xxPrime4
+    }
+    if len(p) >= 4 {
+        h ^= uint64(binary.LittleEndian.Uint32(p)) * xxPrime1
+        h  = bits.RotateLeft64(h, 23) * xxPrime2 + xxPrime3
+        p  = p[4 : ]
+    }
+    for _, b := range p {
+        h ^= uint64(b) * xxPrime5
+        h  = bits.RotateLeft64(h, 11) * xxPrime1
+    }
+    h ^= h >> 33
+    h *= xxPrime2
+    h ^= h >> 29
+    h *= xxPrime3
+    h ^= h >> 32
+    return h
+}
+
+func (x *xxHash64) Sum(b []byte) []byte {
+    b = append(b, make([]byte, 8)...)
+    binary.BigEndian.PutUint64(b[len(b) - 8 : ], x.Sum64())
+    return b
+}
+
+func (x *xxHash64) Reset() {
+    x.v1     = x.seed + xxPrime1 + xxPrime2
+    x.v2     = x.seed + xxPrime2
+    x.v3     = x.seed
+    x.v4     = x.seed - xxPrime1
+    x.length = 0
+    x.tail   = make([]byte, 0, 32)
+}
+
+func (x *xxHash64) Size() int {
+    return 8
+}
+
+func (x *xxHash64) BlockSize() int {
+    return 32
+}
+
+func xxRound(acc, input uint64) uint64 {
+    acc += input * xxPrime2
+    acc  = bits.RotateLeft64(acc, 31)
+    acc *= xxPrime1
+    return acc
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+    acc ^= xxRound(0, val)
+    acc  = acc * xxPrime1 + xxPrime4
+    return acc
+}
@@ -0,0 +1,460 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghtml
+
+import (
+    "bytes"
+    "html"
+    "strings"
+    "sync"
+)
+
+// HTML白名单过滤策略，只保留允许的标签、属性及URL协议，其他内容将被过滤。
+// 不允许的标签去掉标签本身并保留其文本内容，script/style等标签的内容将被整体删除，
+// 注释、DOCTYPE等内容将被删除，文本内容统一进行HTML转义，未闭合的标签在末尾自动闭合。
+type Policy struct {
+    mu            sync.RWMutex
+    none          bool                       // 是否不进行任何过滤(NonePolicy)
+    tags          map[string]map[string]bool // 允许的标签 => 该标签允许的属性
+    globalAttrs   map[string]bool            // 所有允许的标签均可使用的属性
+    schemes       map[string]bool            // URL属性允许的协议
+    allowRelative bool                       // URL属性是否允许相对地址
+    noFollow      bool                       // 是否为a标签增加rel="nofollow"
+}
+
+var (
+    // 内容需要整体删除的标签
+    removeContentTags = map[string]bool {
+        "script"   : true,
+        "style"    : true,
+        "iframe"   : true,
+        "object"   : true,
+        "embed"    : true,
+        "noscript" : true,
+        "textarea" : true,
+        "title"    : true,
+        "head"     : true,
+    }
+    // 内容为原始文本(不解析标签)的标签
+    rawTextTags = map[string]bool {
+        "script"   : true,
+        "style"    : true,
+        "textarea" : true,
+        "title"    : true,
+    }
+    // 没有结束标签的空元素
+    voidTags = map[string]bool {
+        "area"  : true,
+        "br"    : true,
+        "col"   : true,
+        "hr"    : true,
+        "img"   : true,
+        "input" : true,
+        "wbr"   : true,
+    }
+    // 值为URL的属性
+    urlAttrs = map[string]bool {
+        "href"       : true,
+        "src"        : true,
+        "cite"       : true,
+        "action"     : true,
+        "longdesc"   : true,
+        "background" : true,
+        "poster"     : true,
+    }
+)
+
+// 创建空的过滤策略(不允许任何标签)
+func NewPolicy() *Policy {
+    return &Policy {
+        tags        : make(map[string]map[string]bool),
+        globalAttrs : make(map[string]bool),
+        schemes     : make(map[string]bool),
+    }
+}
+
+// 严格策略：去掉所有的HTML标签，只保留转义后的文本内容
+func StrictPolicy() *Policy {
+    return NewPolicy()
+}
+
+// 用户生成内容(UGC)策略：允许常用的富文本格式标签、链接及图片，适用于评论、文章等内容的展示，
+// 链接只允许http/https/mailto协议及相对地址，并自动增加rel="nofollow"。
+func UGCPolicy() *Policy {
+    p := NewPolicy()
+    p.AllowTags(
+        "p", "br", "hr", "div", "span", "blockquote", "pre", "code",
+        "b", "strong", "i", "em", "u", "s", "strike", "del", "ins", "sub", "sup", "small", "mark",
+        "h1", "h2", "h3", "h4", "h5", "h6",
+        "ul", "ol", "li", "dl", "dt", "dd",
+        "table", "thead", "tbody", "tfoot", "tr", "th", "td", "caption",
+        "a", "img", "figure", "figcaption",
+    )
+    p.AllowAttrs([]string{"title", "class", "dir", "lang"})
+    p.AllowAttrs([]string{"href", "target"}, "a")
+    p.AllowAttrs([]string{"src", "alt", "width", "height"}, "img")
+    p.AllowAttrs([]string{"cite"}, "blockquote", "del", "ins")
+    p.AllowAttrs([]string{"colspan", "rowspan", "align"}, "th", "td")
+    p.AllowAttrs([]string{"start", "type"}, "ol")
+    p.AllowSchemes("http", "https", "mailto")
+    p.AllowRelativeURLs(true)
+    p.RequireNoFollow(true)
+    return p
+}
+
+// 不过滤策略：原样返回内容，用于可信的内容，便于通过配置切换策略
+func NonePolicy() *Policy {
+    p := NewPolicy()
+    p.none = true
+    return p
+}
+
+// 使用UGCPolicy策略过滤HTML内容
+func Sanitize(s string) string {
+    return ugcPolicy.Sanitize(s)
+}
+
+// 默认的UGC策略
+var ugcPolicy = UGCPolicy()
+
+// 允许指定的标签(不区分大小写)
+func (p *Policy) AllowTags(tags...string) *Policy {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    for _, tag := range tags {
+        tag = strings.ToLower(tag)
+        if _, ok := p.tags[tag]; !ok {
+            p.tags[tag] = make(map[string]bool)
+        }
+    }
+    return p
+}
+
+// 允许指定的属性，tags为空时表示所有允许的标签均可使用该属性，否则只允许在指定的标签上使用(同时允许这些标签)
+func (p *Policy) AllowAttrs(attrs []string, tags...string) *Policy {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    for _, attr := range attrs {
+        attr = strings.ToLower(attr)
+        if len(tags) == 0 {
+            p.globalAttrs[attr] = true
+            continue
+        }
+        for _, tag := range tags {
+            tag = strings.ToLower(tag)
+            if _, ok := p.tags[tag]; !ok {
+                p.tags[tag] = make(map[string]bool)
+            }
+            p.tags[tag][attr] = true
+        }
+    }
+    return p
+}
+
+// 允许URL属性(href, src等)使用的协议，例如: http, https, mailto
+func (p *Policy) AllowSchemes(schemes...string) *Policy {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    for _, scheme := range schemes {
+        p.schemes[strings.ToLower(scheme)] = true
+    }
+    return p
+}
+
+// 设置URL属性是否允许相对地址(不带协议的地址)
+func (p *Policy) AllowRelativeURLs(allow bool) *Policy {
+    p.mu.Lock()
+    p.allowRelative = allow
+    p.mu.Unlock()
+    return p
+}
+
+// 设置是否为a标签增加rel="nofollow"
+func (p *Policy) RequireNoFollow(require bool) *Policy {
+    p.mu.Lock()
+    p.noFollow = require
+    p.mu.Unlock()
+    return p
+}
+
+// 按照策略过滤HTML内容
+func (p *Policy) Sanitize(s string) string {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    if p.none {
+        return s
+    }
+    var (
+        buffer = bytes.NewBuffer(nil)
+        stack  = make([]string, 0) // 已输出的未闭合标签
+        skip   = 0                 // 需要删除内容的标签层级
+    )
+    tokenizer := &htmlTokenizer{s : s}
+    for {
+        token := tokenizer.next()
+        if token == nil {
+            break
+        }
+        switch token.kind {
+            case htmlTokenText:
+                if skip == 0 {
+                    buffer.WriteString(html.EscapeString(html.UnescapeString(token.data)))
+                }
+
+            case htmlTokenStartTag:
+                if removeContentTags[token.data] {
+                    if !token.selfClosing && !voidTags[token.data] {
+                        skip++
+                    }
+                    continue
+                }
+                if skip > 0 {
+                    continue
+                }
+                attrs, ok := p.tags[token.data]
+                if !ok {
+                    continue
+                }
+                buffer.WriteString("<" + token.data)
+                for _, attr := range token.attrs {
+                    if value, ok := p.filterAttr(token.data, attr, attrs); ok {
+                        buffer.WriteString(" " + attr.name + `="` + html.EscapeString(value) + `"`)
+                    }
+                }
+                if token.data == "a" && p.noFollow {
+                    buffer.WriteString(` rel="nofollow"`)
+                }
+                if voidTags[token.data] {
+                    buffer.WriteString(" />")
+                } else {
+                    buffer.WriteString(">")
+                    stack = append(stack, token.data)
+                }
+
+            case htmlTokenEndTag:
+                if removeContentTags[token.data] {
+                    if skip > 0 {
+                        skip--
+                    }
+                    continue
+                }
+                if skip > 0 {
+                    continue
+                }
+                // 只输出与已输出的标签匹配的结束标签，并闭合中间未闭合的标签
+                for i := len(stack) - 1; i >= 0; i-- {
+                    if stack[i] == token.data {
+                        for k := len(stack) - 1; k >= i; k-- {
+                            buffer.WriteString("</" + stack[k] + ">")
+                        }
+                        stack = stack[ : i]
+                        break
+                    }
+                }
+        }
+    }
+    for i := len(stack) - 1; i >= 0; i-- {
+        buffer.WriteString("</" + stack[i] + ">")
+    }
+    return buffer.String()
+}
+
+// 过滤属性，返回过滤后的属性值及是否允许该属性
+func (p *Policy) filterAttr(tag string, attr htmlAttr, attrs map[string]bool) (string, bool) {
+    // 事件属性(on*)及style属性可能执行脚本，需要显式允许
+    if !attrs[attr.name] && !p.globalAttrs[attr.name] {
+        return "", false
+    }
+    if tag == "a" && attr.name == "rel" && p.noFollow {
+        return "", false
+    }
+    value := html.UnescapeString(attr.value)
+    if urlAttrs[attr.name] && !p.checkURL(value) {
+        return "", false
+    }
+    return value, true
+}
+
+// 校验URL的协议是否允许
+func (p *Policy) checkURL(url string) bool {
+    // 去掉空白及控制字符，防止使用 "java\tscript:" 等形式绕过协议检查
+    url = strings.Map(func(r rune) rune {
+        if r <= ' ' || r == 0x7f {
+            return -1
+        }
+        return r
+    }, url)
+    if url == "" {
+        return false
+    }
+    pos := strings.IndexAny(url, ":/?#")
+    if pos == -1 || url[pos] != ':' {
+        return p.allowRelative
+    }
+    return p.schemes[strings.ToLower(url[ : pos])]
+}
+
+// HTML词法单元类型
+const (
+    htmlTokenText = iota
+    htmlTokenStartTag
+    htmlTokenEndTag
+)
+
+// HTML属性
+type htmlAttr struct {
+    name  string
+    value string
+}
+
+// HTML词法单元
+type htmlToken struct {
+    kind        int
+    data        string // 文本内容或者标签名称(小写)
+    attrs       []htmlAttr
+    selfClosing bool
+}
+
+// 简单的HTML词法解析器，注释、DOCTYPE、处理指令等内容直接忽略
+type htmlTokenizer struct {
+    s       string
+    pos     int
+    rawText string // 当前所在的原始文本标签
+}
+
+// 获取下一个词法单元，结束时返回nil
+func (t *htmlTokenizer) next() *htmlToken {
+    for t.pos < len(t.s) {
+        if t.rawText != "" {
+            // 原始文本标签的内容直到对应的结束标签
+            end := strings.Index(strings.ToLower(t.s[t.pos : ]), "</" + t.rawText)
+            if end == -1 {
+                end = len(t.s) - t.pos
+            }
+            text     := t.s[t.pos : t.pos + end]
+            t.pos    += end
+            t.rawText = ""
+            if text != "" {
+                return &htmlToken{kind : htmlTokenText, data : text}
+            }
+            continue
+        }
+        if t.s[t.pos] != '<' {
+            end := strings.IndexByte(t.s[t.pos : ], '<')
+            if end == -1 {
+                end = len(t.s) - t.pos
+            }
+            text  := t.s[t.pos : t.pos + end]
+            t.pos += end
+            return &htmlToken{kind : htmlTokenText, data : text}
+        }
+        rest := t.s[t.pos : ]
+        switch {
+            case strings.HasPrefix(rest, "<!--"):
+                end := strings.Index(rest[4 : ], "-->")
+                if end == -1 {
+                    t.pos = len(t.s)
+                } else {
+                    t.pos += end + 7
+                }
+
+            case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+                end := strings.IndexByte(rest, '>')
+                if end == -1 {
+                    t.pos = len(t.s)
+                } else {
+                    t.pos += end + 1
+                }
+
+            case len(rest) > 2 && rest[1] == '/' && isLetter(rest[2]):
+                token := t.parseTag(t.pos + 2)
+                token.kind = htmlTokenEndTag
+                return token
+
+            case len(rest) > 1 && isLetter(rest[1]):
+                token := t.parseTag(t.pos + 1)
+                if rawTextTags[token.data] && !token.selfClosing {
+                    t.rawText = token.data
+                }
+                return token
+
+            default:
+                t.pos++
+                return &htmlToken{kind : htmlTokenText, data : "<"}
+        }
+    }
+    return nil
+}
+
+// 解析标签名称及属性，start为标签名称的起始位置
+func (t *htmlTokenizer) parseTag(start int) *htmlToken {
+    token := &htmlToken{kind : htmlTokenStartTag}
+    i     := start
+    for i < len(t.s) && isTagNameChar(t.s[i]) {
+        i++
+    }
+    token.data = strings.ToLower(t.s[start : i])
+    for i < len(t.s) {
+        c := t.s[i]
+        switch {
+            case c == '>':
+                t.pos = i + 1
+                return token
+            case c == '/':
+                token.selfClosing = true
+                i++
+                continue
+            case c <= ' ':
+                i++
+                continue
+        }
+        token.selfClosing = false
+        // 属性名称
+        nameStart := i
+        for i < len(t.s) && t.s[i] > ' ' && t.s[i] != '=' && t.s[i] != '>' && t.s[i] != '/' {
+            i++
+        }
+        attr := htmlAttr{name : strings.ToLower(t.s[nameStart : i])}
+        for i < len(t.s) && t.s[i] <= ' ' {
+            i++
+        }
+        if i < len(t.s) && t.s[i] == '=' {
+            i++
+            for i < len(t.s) && t.s[i] <= ' ' {
+                i++
+            }
+            if i < len(t.s) && (t.s[i] == '"' || t.s[i] == '\'') {
+                quote := t.s[i]
+                end   := strings.IndexByte(t.s[i + 1 : ], quote)
+                if end == -1 {
+                    end = len(t.s) - i - 1
+                }
+                attr.value = t.s[i + 1 : i + 1 + end]
+                i += end + 2
+            } else {
+                valueStart := i
+                for i < len(t.s) && t.s[i] > ' ' && t.s[i] != '>' {
+                    i++
+                }
+                attr.value = t.s[valueStart : i]
+            }
+        }
+        if attr.name != "" {
+            token.attrs = append(token.attrs, attr)
+        }
+    }
+    t.pos = len(t.s)
+    return token
+}
+
+func isTagNameChar(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == ':'
+}
+
+func isLetter(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
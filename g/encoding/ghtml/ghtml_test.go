@@ -0,0 +1,39 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package ghtml_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/encoding/ghtml"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Sanitize(t *testing.T) {
+    gtest.Case(t, func() {
+        gtest.Assert(ghtml.Sanitize(`<p class="x" onclick="alert(1)">Hello <b>gf</b></p>`), `<p class="x">Hello <b>gf</b></p>`)
+        gtest.Assert(ghtml.Sanitize(`<script>alert("<b>x</b>")</script>text`), `text`)
+        gtest.Assert(ghtml.Sanitize(`<a href="javascript:alert(1)">x</a>`), `<a rel="nofollow">x</a>`)
+        gtest.Assert(ghtml.Sanitize(`<a href="java&#x09;script:alert(1)">x</a>`), `<a rel="nofollow">x</a>`)
+        gtest.Assert(ghtml.Sanitize(`<a href="https://goframe.org?a=1&amp;b=2" rel="opener">x</a>`), `<a href="https://goframe.org?a=1&amp;b=2" rel="nofollow">x</a>`)
+        gtest.Assert(ghtml.Sanitize(`<img src=/logo.png alt='logo' onerror=alert(1)>`), `<img src="/logo.png" alt="logo" />`)
+        gtest.Assert(ghtml.Sanitize(`<div><span>unclosed <em>tags</div> 1 < 2 & 3`), `<div><span>unclosed <em>tags</em></span></div> 1 &lt; 2 &amp; 3`)
+        gtest.Assert(ghtml.Sanitize(`<!-- comment --><foo>bar</foo></p>`), `bar`)
+        gtest.Assert(ghtml.Sanitize(`<iframe src="x"><b>hidden</b></iframe><b>shown</b>`), `<b>shown</b>`)
+    })
+    gtest.Case(t, func() {
+        gtest.Assert(ghtml.StrictPolicy().Sanitize(`<p>Hello <b>"gf"</b></p>`), `Hello &#34;gf&#34;`)
+        gtest.Assert(ghtml.NonePolicy().Sanitize(`<script>x</script>`), `<script>x</script>`)
+
+        p := ghtml.NewPolicy().AllowTags("p").AllowAttrs([]string{"href"}, "a").AllowSchemes("https")
+        gtest.Assert(p.Sanitize(`<P Title="t"><a href="http://x">a</a><a href="https://x">b</a><a href="/x">c</a></P>`),
+            `<p><a>a</a><a href="https://x">b</a><a>c</a></p>`)
+        p.AllowRelativeURLs(true).AllowAttrs([]string{"title"})
+        gtest.Assert(p.Sanitize(`<p title="t"><a href="/x">c</a></p>`), `<p title="t"><a href="/x">c</a></p>`)
+    })
+}
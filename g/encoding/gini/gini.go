@@ -0,0 +1,260 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gini provides accessing and converting for INI content.
+//
+// INI格式说明:
+// 1. 使用 key = value 或者 key: value 定义键值，; 及 # 开头的行为注释；
+// 2. [section] 定义分组，分组名称中的层级分隔符(默认为".")表示嵌套，例如: [redis.cache] 解析为 {"redis": {"cache": {...}}}，
+//    第一个分组之前的键值位于根级别；
+// 3. 键名以 [] 结尾时表示数组，例如: hosts[] = a 及 hosts[] = b 解析为 {"hosts": ["a", "b"]}；
+// 4. 键值两端的空白会被去掉，使用单引号或者双引号包含时去掉引号(双引号内支持 \" \\ \n \t 转义)；
+// 5. 所有的值均解析为字符串。
+package gini
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 默认的层级分隔符
+const gDEFAULT_SEPARATOR = "."
+
+// 将变量编码为INI内容，v应当为map或者struct(按照gconv.Map的规则转换)。
+// 根级别的非map值作为全局键值，map值作为分组，嵌套的map使用分隔符(默认为".")连接为分组名称，
+// 数组使用 key[] = value 的形式编码(数组元素不能为map或者数组)。
+func Encode(v interface{}, separator...string) ([]byte, error) {
+    sep    := getSeparator(separator)
+    if sep == "" {
+        sep = gDEFAULT_SEPARATOR
+    }
+    buffer := bytes.NewBuffer(nil)
+    if err := encodeSection(buffer, "", gconv.Map(v), sep); err != nil {
+        return nil, err
+    }
+    return buffer.Bytes(), nil
+}
+
+// 编码一个分组，先写入分组的键值，再写入子分组
+func encodeSection(buffer *bytes.Buffer, name string, m map[string]interface{}, sep string) error {
+    keys := sortedKeys(m)
+    subs := make([]string, 0)
+    if name != "" {
+        buffer.WriteString("[" + name + "]\n")
+    }
+    for _, k := range keys {
+        value := m[k]
+        if value == nil {
+            buffer.WriteString(k + " = \n")
+            continue
+        }
+        rv := reflect.ValueOf(value)
+        for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+            rv = rv.Elem()
+        }
+        switch rv.Kind() {
+            case reflect.Map, reflect.Struct:
+                subs = append(subs, k)
+            case reflect.Slice, reflect.Array:
+                if _, ok := value.([]byte); ok {
+                    buffer.WriteString(k + " = " + encodeValue(string(value.([]byte))) + "\n")
+                    continue
+                }
+                for i := 0; i < rv.Len(); i++ {
+                    item := rv.Index(i)
+                    for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+                        if item.IsNil() {
+                            break
+                        }
+                        item = item.Elem()
+                    }
+                    switch item.Kind() {
+                        case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+                            return errors.New(fmt.Sprintf(`ini: unsupported nested array value for key "%s"`, joinName(name, k, sep)))
+                    }
+                    buffer.WriteString(k + "[] = " + encodeValue(gconv.String(rv.Index(i).Interface())) + "\n")
+                }
+            default:
+                buffer.WriteString(k + " = " + encodeValue(gconv.String(value)) + "\n")
+        }
+    }
+    for _, k := range subs {
+        if buffer.Len() > 0 {
+            buffer.WriteString("\n")
+        }
+        if err := encodeSection(buffer, joinName(name, k, sep), gconv.Map(m[k]), sep); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 编码键值，包含首尾空白、引号、注释符号或者换行符的值使用双引号包含
+func encodeValue(value string) string {
+    if value == "" {
+        return value
+    }
+    if strings.TrimSpace(value) != value || strings.ContainsAny(value, "\"';#\r\n\\") {
+        return strconv.Quote(value)
+    }
+    return value
+}
+
+// 解析INI内容，返回解析后的map，separator为分组名称的层级分隔符(默认为".")，传递空字符串时不进行嵌套
+func Decode(data []byte, separator...string) (map[string]interface{}, error) {
+    var (
+        sep     = getSeparator(separator)
+        result  = make(map[string]interface{})
+        section = result
+        scanner = bufio.NewScanner(bytes.NewReader(data))
+        line    = 0
+    )
+    scanner.Buffer(make([]byte, 64 * 1024), len(data) + 1)
+    for scanner.Scan() {
+        line++
+        text := strings.TrimSpace(scanner.Text())
+        if line == 1 {
+            // 去掉UTF-8 BOM
+            text = strings.TrimPrefix(text, "\uFEFF")
+        }
+        if text == "" || text[0] == ';' || text[0] == '#' {
+            continue
+        }
+        if text[0] == '[' {
+            if text[len(text) - 1] != ']' {
+                return nil, errors.New(fmt.Sprintf("ini: invalid section at line %d: %s", line, text))
+            }
+            name := strings.TrimSpace(text[1 : len(text) - 1])
+            if name == "" {
+                return nil, errors.New(fmt.Sprintf("ini: empty section name at line %d", line))
+            }
+            m, err := getSection(result, name, sep)
+            if err != nil {
+                return nil, errors.New(fmt.Sprintf("ini: line %d: %s", line, err.Error()))
+            }
+            section = m
+            continue
+        }
+        index := strings.IndexAny(text, "=:")
+        if index <= 0 {
+            return nil, errors.New(fmt.Sprintf("ini: invalid key-value at line %d: %s", line, text))
+        }
+        key   := strings.TrimSpace(text[ : index])
+        value, err := decodeValue(strings.TrimSpace(text[index + 1 : ]))
+        if err != nil {
+            return nil, errors.New(fmt.Sprintf("ini: invalid value at line %d: %s", line, err.Error()))
+        }
+        if strings.HasSuffix(key, "[]") {
+            key = strings.TrimSpace(key[ : len(key) - 2])
+            if old, ok := section[key]; ok {
+                if array, ok := old.([]interface{}); ok {
+                    section[key] = append(array, value)
+                    continue
+                }
+            }
+            section[key] = []interface{}{value}
+            continue
+        }
+        section[key] = value
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// 解析键值，去掉引号及行尾注释(未使用引号时，空白后的 ; 及 # 视为注释)
+func decodeValue(value string) (string, error) {
+    if value == "" {
+        return value, nil
+    }
+    switch value[0] {
+        case '"':
+            // 查找结束的双引号，其后的内容只能为注释
+            for i := 1; i < len(value); i++ {
+                switch value[i] {
+                    case '\\':
+                        i++
+                    case '"':
+                        return strconv.Unquote(value[ : i + 1])
+                }
+            }
+            return "", errors.New("unterminated quoted string")
+        case '\'':
+            if index := strings.IndexByte(value[1 : ], '\''); index >= 0 {
+                return value[1 : index + 1], nil
+            }
+            return "", errors.New("unterminated quoted string")
+    }
+    for i := 1; i < len(value); i++ {
+        if (value[i] == ';' || value[i] == '#') && (value[i - 1] == ' ' || value[i - 1] == '\t') {
+            return strings.TrimSpace(value[ : i]), nil
+        }
+    }
+    return value, nil
+}
+
+// 获取(不存在时创建)分组对应的map
+func getSection(m map[string]interface{}, name string, sep string) (map[string]interface{}, error) {
+    parts := []string{name}
+    if sep != "" {
+        parts = strings.Split(name, sep)
+    }
+    for _, part := range parts {
+        part = strings.TrimSpace(part)
+        switch v := m[part].(type) {
+            case nil:
+                sub    := make(map[string]interface{})
+                m[part] = sub
+                m       = sub
+            case map[string]interface{}:
+                m = v
+            default:
+                return nil, errors.New(fmt.Sprintf(`section "%s" conflicts with key "%s"`, name, part))
+        }
+    }
+    return m, nil
+}
+
+// 将INI内容转换为JSON内容
+func ToJson(data []byte, separator...string) ([]byte, error) {
+    m, err := Decode(data, separator...)
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(m)
+}
+
+func getSeparator(separator []string) string {
+    if len(separator) > 0 {
+        return separator[0]
+    }
+    return gDEFAULT_SEPARATOR
+}
+
+func joinName(parent, name, sep string) string {
+    if parent == "" {
+        return name
+    }
+    return parent + sep + name
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gini_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/encoding/gini"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+var iniContent = `
+; global
+name = gf
+desc = "Go Frame; \"gf\""   # comment
+
+[redis]
+addr  = 127.0.0.1:6379
+db    = 1 ; comment
+hosts[] = a
+hosts[] = b
+
+[redis.cache]
+ttl: '60'
+
+[empty]
+`
+
+func Test_Decode(t *testing.T) {
+    gtest.Case(t, func() {
+        m, err := gini.Decode([]byte(iniContent))
+        gtest.Assert(err, nil)
+        gtest.Assert(m["name"], "gf")
+        gtest.Assert(m["desc"], `Go Frame; "gf"`)
+        redis := m["redis"].(map[string]interface{})
+        gtest.Assert(redis["addr"],  "127.0.0.1:6379")
+        gtest.Assert(redis["db"],    "1")
+        gtest.Assert(redis["hosts"], []interface{}{"a", "b"})
+        gtest.Assert(redis["cache"], map[string]interface{}{"ttl" : "60"})
+        gtest.Assert(m["empty"],     map[string]interface{}{})
+        // 不进行嵌套
+        m, err  = gini.Decode([]byte(iniContent), "")
+        gtest.Assert(err, nil)
+        gtest.Assert(m["redis.cache"], map[string]interface{}{"ttl" : "60"})
+        // 非法内容
+        _, err  = gini.Decode([]byte("[redis"))
+        gtest.AssertNE(err, nil)
+        _, err  = gini.Decode([]byte("name"))
+        gtest.AssertNE(err, nil)
+        _, err  = gini.Decode([]byte("a = 1\n[a]"))
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_Encode(t *testing.T) {
+    gtest.Case(t, func() {
+        b, err := gini.Encode(map[string]interface{} {
+            "name"  : "gf",
+            "desc"  : " gf; ",
+            "redis" : map[string]interface{} {
+                "addr"  : "127.0.0.1:6379",
+                "hosts" : []string{"a", "b"},
+                "cache" : map[string]interface{}{"ttl" : 60},
+            },
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(b), "desc = \" gf; \"\nname = gf\n\n[redis]\naddr = 127.0.0.1:6379\nhosts[] = a\nhosts[] = b\n\n[redis.cache]\nttl = 60\n")
+        m, err := gini.Decode(b)
+        gtest.Assert(err, nil)
+        gtest.Assert(m["desc"], " gf; ")
+        gtest.Assert(m["redis"].(map[string]interface{})["hosts"], []interface{}{"a", "b"})
+        // 数组元素不支持map
+        _, err  = gini.Encode(map[string]interface{}{"a" : []interface{}{map[string]interface{}{"b" : 1}}})
+        gtest.AssertNE(err, nil)
+    })
+}
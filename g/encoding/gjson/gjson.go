@@ -19,10 +19,14 @@ import (
     "github.com/gogf/gf/g/encoding/gxml"
     "github.com/gogf/gf/g/encoding/gyaml"
     "github.com/gogf/gf/g/encoding/gtoml"
+    "github.com/gogf/gf/g/encoding/gini"
+    "github.com/gogf/gf/g/encoding/gproperties"
+    "github.com/gogf/gf/g/encoding/gmsgpack"
     "github.com/gogf/gf/g/text/gstr"
     "time"
     "github.com/gogf/gf/g/internal/rwmutex"
     "fmt"
+    "math"
 )
 
 const (
@@ -35,6 +39,7 @@ type Json struct {
     p  *interface{} // 注意这是一个指针
     c  byte         // 层级分隔符，默认为"."
     vc bool         // 层级检索是否执行分隔符冲突检测(默认为false，检测会比较影响检索效率)
+    o  *orderedKeys // 有序模式下记录的对象键名顺序，非有序模式时为nil
 }
 
 // 将变量转换为Json对象进行处理，该变量至少应当是一个map或者slice，否者转换没有意义
@@ -117,41 +122,39 @@ func Load (path string) (*Json, error) {
     return LoadContent(data, gfile.Ext(path))
 }
 
-// 支持的配置文件格式：xml, json, yaml/yml, toml,
-// 默认为自动识别，当无法检测成功时使用json解析。
+// 支持的配置文件格式：xml, json, yaml/yml, toml, ini, properties, msgpack，dataType同时支持文件扩展名格式(例如: .yaml, .toml)，
+// 未指定或者为无法识别的格式时自动识别(ini/properties/msgpack格式需要明确指定)，当无法检测成功时使用json解析。
+// ini分组名称及properties键名中的"."表示层级，所有的值均解析为字符串。
 func LoadContent(data []byte, dataType...string) (*Json, error) {
     var err    error
     var result interface{}
-    t := "json"
+    t := ""
     if len(dataType) > 0 {
-        t = dataType[0]
-    } else {
-        if gregex.IsMatch(`<.+>.*</.+>`, data) {
-            t = "xml"
-        } else if gregex.IsMatch(`\w+\s*:\s*\w+`, data) {
-            t = "yml"
-        } else if gregex.IsMatch(`\w+\s*=\s*\w+`, data) {
-            t = "toml"
-        }
+        t = formatDataType(dataType[0])
+    }
+    switch t {
+        case "json", "xml", "yaml", "toml", "ini", "properties", "msgpack":
+        default:
+            // 未指定或者无法识别的数据格式(例如未知的文件扩展名)按照内容自动识别，无法识别时使用json解析
+            t = checkDataType(data)
     }
     switch t {
-        case  "xml", ".xml":
+        case "json":
+        case "xml":
             data, err = gxml.ToJson(data)
-            if err != nil {
-                return nil, err
-            }
-
-        case   "yml", "yaml", ".yml", ".yaml":
+        case "yaml":
             data, err = gyaml.ToJson(data)
-            if err != nil {
-                return nil, err
-            }
-
-        case  "toml", ".toml":
+        case "toml":
             data, err = gtoml.ToJson(data)
-            if err != nil {
-                return nil, err
-            }
+        case "ini":
+            data, err = gini.ToJson(data)
+        case "properties":
+            data, err = gproperties.ToJson(data)
+        case "msgpack":
+            data, err = gmsgpack.ToJson(data)
+    }
+    if err != nil {
+        return nil, err
     }
     if result == nil {
         if err := json.Unmarshal(data, &result); err != nil {
@@ -161,6 +164,39 @@ func LoadContent(data []byte, dataType...string) (*Json, error) {
     return New(result), nil
 }
 
+// 格式化数据格式名称，去掉扩展名前面的"."并转换为小写，yml统一为yaml
+func formatDataType(dataType string) string {
+    t := strings.ToLower(strings.TrimLeft(strings.TrimSpace(dataType), "."))
+    switch t {
+        case "yml":
+            t = "yaml"
+        case "mpk", "msgp":
+            t = "msgpack"
+        case "prop", "props":
+            t = "properties"
+    }
+    return t
+}
+
+// 自动识别数据内容格式，识别时忽略注释行，无法识别时返回json
+func checkDataType(data []byte) string {
+    if json.Valid(data) {
+        return "json"
+    }
+    if gregex.IsMatch(`^\s*<.+>[\S\s]*</.+>\s*$`, data) {
+        return "xml"
+    }
+    // 去掉#注释行，避免注释内容影响识别
+    content, _ := gregex.Replace(`(?m)^\s*#.*$`, nil, data)
+    if gregex.IsMatch(`(?m)^\s*\[[\w\.\-"' ]+\]\s*$`, content) || gregex.IsMatch(`(?m)^\s*[\w\-\."']+\s*=`, content) {
+        return "toml"
+    }
+    if gregex.IsMatch(`(?m)^\s*[\w\-\."']+\s*:(\s|$)`, content) || gregex.IsMatch(`(?m)^\s*-\s+\S`, content) {
+        return "yaml"
+    }
+    return "json"
+}
+
 // 设置自定义的层级分隔符号
 func (j *Json) SetSplitChar(char byte) {
     j.mu.Lock()
@@ -208,7 +244,11 @@ func (j *Json) GetMap(pattern string) map[string]interface{} {
 func (j *Json) GetJson(pattern string) *Json {
     result := j.Get(pattern)
     if result != nil {
-        return New(result)
+        r := New(result)
+        if j.o != nil {
+            r.o = j.o.sub(pattern, j.c)
+        }
+        return r
     }
     return nil
 }
@@ -308,18 +348,31 @@ func (j *Json) GetFloats(pattern string) []float64 {
     return gconv.Floats(j.Get(pattern))
 }
 
-// 将指定变量转换为struct对象(对象属性赋值)
+// 将指定变量转换为struct对象(对象属性赋值)，规则参考ToStruct
 func (j *Json) GetToStruct(pattern string, objPointer interface{}) error {
-    return gconv.Struct(j.Get(pattern), objPointer)
+    _, err := mapToStruct(j.Get(pattern), objPointer)
+    return err
+}
+
+// 同GetToStruct，数据中存在未映射到struct属性的键名时返回*UnmappedError(属性仍然完成赋值)
+func (j *Json) GetToStructStrict(pattern string, objPointer interface{}) error {
+    return checkUnmapped(mapToStruct(j.Get(pattern), objPointer))
 }
 
 // 动态设置层级变量
 func (j *Json) Set(pattern string, value interface{}) error {
+    if j.o != nil {
+        value = j.setOrderedValue(pattern, value)
+        defer j.syncOrderedKeys()
+    }
     return j.setValue(pattern, value, false)
 }
 
 // 动态删除层级变量
 func (j *Json) Remove(pattern string) error {
+    if j.o != nil {
+        defer j.syncOrderedKeys()
+    }
     return j.setValue(pattern, nil, true)
 }
 
@@ -524,6 +577,7 @@ func (j *Json) setPointerWithValue(pointer *interface{}, key string, value inter
 }
 
 // 根据约定字符串方式访问json解析数据，参数形如： "items.name.first", "list.0"; 当pattern为空时，表示获取所有数据;
+// pattern同时支持通配符(users.*.name)、数组过滤(orders.#(status=paid).id)及数组切片(items.0:10)，此时返回所有匹配结果组成的[]interface{}(没有匹配时为nil);
 // 返回的结果类型的interface{}，因此需要自己做类型转换;
 // 如果找不到对应节点的数据，返回nil;
 func (j *Json) Get(pattern...string) interface{} {
@@ -543,6 +597,10 @@ func (j *Json) Get(pattern...string) interface{} {
     if result != nil {
         return *result
     }
+    // 查询表达式(通配符、数组过滤及切片)，参考getByQuery
+    if r, ok := j.getByQuery(queryPattern); ok && r != nil {
+        return r
+    }
     return nil
 }
 
@@ -693,12 +751,18 @@ func (j *Json) ToXmlIndent(rootTag...string) ([]byte, error) {
 func (j *Json) ToJson() ([]byte, error) {
     j.mu.RLock()
     defer j.mu.RUnlock()
+    if j.o != nil {
+        return j.toJsonOrdered(false)
+    }
     return Encode(*(j.p))
 }
 
 func (j *Json) ToJsonIndent() ([]byte, error) {
     j.mu.RLock()
     defer j.mu.RUnlock()
+    if j.o != nil {
+        return j.toJsonOrdered(true)
+    }
     return json.MarshalIndent(*(j.p), "", "\t")
 }
 
@@ -711,14 +775,90 @@ func (j *Json) ToYaml() ([]byte, error) {
 func (j *Json) ToToml() ([]byte, error) {
     j.mu.RLock()
     defer j.mu.RUnlock()
-    return gtoml.Encode(*(j.p))
+    return gtoml.Encode(convertIntegralFloats(*(j.p)))
+}
+
+// 导出为INI内容，根级别的map值作为分组，嵌套的map使用"."连接为分组名称
+func (j *Json) ToIni() ([]byte, error) {
+    j.mu.RLock()
+    defer j.mu.RUnlock()
+    return gini.Encode(convertIntegralFloats(*(j.p)))
+}
+
+// 导出为properties内容，嵌套的map及数组使用"."展开为扁平的键名
+func (j *Json) ToProperties() ([]byte, error) {
+    j.mu.RLock()
+    defer j.mu.RUnlock()
+    return gproperties.Encode(convertIntegralFloats(*(j.p)))
+}
+
+func (j *Json) ToMsgPack() ([]byte, error) {
+    j.mu.RLock()
+    defer j.mu.RUnlock()
+    return gmsgpack.Encode(convertIntegralFloats(*(j.p)))
+}
+
+// 将整数值的float64转换为int64(json解析后的数值均为float64)，以便TOML/MessagePack导出时保持整数格式
+func convertIntegralFloats(value interface{}) interface{} {
+    switch v := value.(type) {
+        case float64:
+            if v == math.Trunc(v) && math.Abs(v) < 1 << 53 {
+                return int64(v)
+            }
+        case map[string]interface{}:
+            m := make(map[string]interface{}, len(v))
+            for k, item := range v {
+                m[k] = convertIntegralFloats(item)
+            }
+            return m
+        case []interface{}:
+            a := make([]interface{}, len(v))
+            for i, item := range v {
+                a[i] = convertIntegralFloats(item)
+            }
+            return a
+    }
+    return value
+}
+
+// 按照指定的数据格式(json, xml, yaml/yml, toml, ini, properties, msgpack，同时支持文件扩展名格式，例如: .yaml)导出内容
+func (j *Json) ToFormat(dataType string) ([]byte, error) {
+    switch formatDataType(dataType) {
+        case "json":       return j.ToJsonIndent()
+        case "xml":        return j.ToXmlIndent()
+        case "yaml":       return j.ToYaml()
+        case "toml":       return j.ToToml()
+        case "ini":        return j.ToIni()
+        case "properties": return j.ToProperties()
+        case "msgpack":    return j.ToMsgPack()
+    }
+    return nil, errors.New(fmt.Sprintf(`unsupported data type "%s"`, dataType))
+}
+
+// 将内容保存到指定文件，导出格式根据文件扩展名确定(json, xml, yaml/yml, toml, ini, properties)
+func (j *Json) Save(path string) error {
+    content, err := j.ToFormat(gfile.Ext(path))
+    if err != nil {
+        return err
+    }
+    return gfile.PutBinContents(path, content)
 }
 
-// 转换为指定的struct对象
+// 转换为指定的struct对象，键名优先匹配gconv/json标签，其次匹配属性名称(忽略大小写及 _ - 空格)。
+// 支持嵌套struct、匿名嵌入struct、struct数组、time.Time/gtime.Time(layout/format标签指定解析格式)，
+// 以及ValueUnmarshaler接口、RegisterConverter注册的自定义类型转换。
 func (j *Json) ToStruct(o interface{}) error {
     j.mu.RLock()
     defer j.mu.RUnlock()
-    return gconv.Struct(*(j.p), o)
+    _, err := mapToStruct(*(j.p), o)
+    return err
+}
+
+// 同ToStruct，数据中存在未映射到struct属性的键名时返回*UnmappedError(属性仍然完成赋值)
+func (j *Json) ToStructStrict(o interface{}) error {
+    j.mu.RLock()
+    defer j.mu.RUnlock()
+    return checkUnmapped(mapToStruct(*(j.p), o))
 }
 
 // 打印Json对象
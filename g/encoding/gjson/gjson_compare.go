@@ -0,0 +1,176 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "bytes"
+    "reflect"
+    "sort"
+    "strconv"
+)
+
+// 差异类型
+const (
+    DIFF_ADDED   = "added"
+    DIFF_REMOVED = "removed"
+    DIFF_CHANGED = "changed"
+)
+
+// 单个差异项
+type Difference struct {
+    Type    string      `json:"type"`              // 差异类型：added, removed, changed
+    Path    string      `json:"path"`              // 层级路径(使用"."分隔，例如: users.0.name)，根节点为空字符串
+    Pointer string      `json:"pointer"`           // 路径的JSON Pointer格式(例如: /users/0/name)，键名中包含"."时应当使用该路径
+    Old     interface{} `json:"old,omitempty"`     // 原有的值(added类型时为nil)
+    New     interface{} `json:"new,omitempty"`     // 新的值(removed类型时为nil)
+}
+
+// 两个文档的对比结果，差异项按照路径排序
+type Comparison struct {
+    Added   []Difference `json:"added"`
+    Removed []Difference `json:"removed"`
+    Changed []Difference `json:"changed"`
+    source  interface{}
+    target  interface{}
+}
+
+// 对比文档a与b的结构差异，a为原有文档，b为新文档，参数为nil时视为空文档(null)。
+// 对象按照键名递归对比，数组按照索引逐个元素对比(多出/缺少的元素为added/removed)，
+// 类型不同或者值不同时为changed，数值统一按照float64对比(例如: 1与1.0相等)。
+func Compare(a, b *Json) *Comparison {
+    c := &Comparison {
+        Added   : make([]Difference, 0),
+        Removed : make([]Difference, 0),
+        Changed : make([]Difference, 0),
+    }
+    if a != nil {
+        decodeVar(a.Get(), &c.source)
+    }
+    if b != nil {
+        decodeVar(b.Get(), &c.target)
+    }
+    c.compare("", "", c.source, c.target)
+    return c
+}
+
+// 判断两个文档是否一致
+func (c *Comparison) Equal() bool {
+    return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// 获取所有的差异项，按照路径排序
+func (c *Comparison) Differences() []Difference {
+    diffs := make([]Difference, 0, len(c.Added) + len(c.Removed) + len(c.Changed))
+    diffs  = append(diffs, c.Added...)
+    diffs  = append(diffs, c.Removed...)
+    diffs  = append(diffs, c.Changed...)
+    sort.SliceStable(diffs, func(i, j int) bool {
+        return diffs[i].Pointer < diffs[j].Pointer
+    })
+    return diffs
+}
+
+// 生成将文档a转换为文档b的JSON Patch(RFC 6902)操作列表，可以使用ApplyPatch应用，参考Json.Diff
+func (c *Comparison) Patch() []PatchOperation {
+    operations := make([]PatchOperation, 0)
+    diffValue("", c.source, c.target, &operations)
+    return operations
+}
+
+// 生成JSON Patch(RFC 6902)内容
+func (c *Comparison) PatchJson() ([]byte, error) {
+    return Encode(c.Patch())
+}
+
+// 生成便于阅读的差异内容，每行一个差异项，例如:
+// + users.2: {"name":"john"}
+// - debug: true
+// ~ redis.db: 1 => 2
+func (c *Comparison) String() string {
+    buffer := bytes.NewBuffer(nil)
+    for _, d := range c.Differences() {
+        path := d.Path
+        if path == "" {
+            path = "(root)"
+        }
+        switch d.Type {
+            case DIFF_ADDED:
+                buffer.WriteString("+ " + path + ": " + formatSchemaValue(d.New) + "\n")
+            case DIFF_REMOVED:
+                buffer.WriteString("- " + path + ": " + formatSchemaValue(d.Old) + "\n")
+            default:
+                buffer.WriteString("~ " + path + ": " + formatSchemaValue(d.Old) + " => " + formatSchemaValue(d.New) + "\n")
+        }
+    }
+    return buffer.String()
+}
+
+// 递归对比
+func (c *Comparison) compare(path, pointer string, source, target interface{}) {
+    if reflect.DeepEqual(source, target) {
+        return
+    }
+    switch s := source.(type) {
+        case map[string]interface{}:
+            if t, ok := target.(map[string]interface{}); ok {
+                keys := make([]string, 0, len(s) + len(t))
+                for k := range s {
+                    keys = append(keys, k)
+                }
+                for k := range t {
+                    if _, ok := s[k]; !ok {
+                        keys = append(keys, k)
+                    }
+                }
+                sort.Strings(keys)
+                for _, k := range keys {
+                    sv, o1 := s[k]
+                    tv, o2 := t[k]
+                    c.compareItem(joinSchemaPath(path, k), pointer + "/" + escapePointerToken(k), sv, tv, o1, o2)
+                }
+                return
+            }
+        case []interface{}:
+            if t, ok := target.([]interface{}); ok {
+                length := len(s)
+                if len(t) > length {
+                    length = len(t)
+                }
+                for i := 0; i < length; i++ {
+                    var sv, tv interface{}
+                    if i < len(s) {
+                        sv = s[i]
+                    }
+                    if i < len(t) {
+                        tv = t[i]
+                    }
+                    index := strconv.Itoa(i)
+                    c.compareItem(joinSchemaPath(path, index), pointer + "/" + index, sv, tv, i < len(s), i < len(t))
+                }
+                return
+            }
+    }
+    c.Changed = append(c.Changed, Difference {
+        Type    : DIFF_CHANGED,
+        Path    : path,
+        Pointer : pointer,
+        Old     : source,
+        New     : target,
+    })
+}
+
+// 对比子项，exist1/exist2分别表示子项在原有文档/新文档中是否存在
+func (c *Comparison) compareItem(path, pointer string, source, target interface{}, exist1, exist2 bool) {
+    switch {
+        case exist1 && !exist2:
+            c.Removed = append(c.Removed, Difference{Type : DIFF_REMOVED, Path : path, Pointer : pointer, Old : source})
+        case !exist1 && exist2:
+            c.Added   = append(c.Added,   Difference{Type : DIFF_ADDED,   Path : path, Pointer : pointer, New : target})
+        default:
+            c.compare(path, pointer, source, target)
+    }
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "github.com/gogf/gf/g/encoding/gini"
+    "github.com/gogf/gf/g/encoding/gtoml"
+    "github.com/gogf/gf/g/encoding/gxml"
+    "github.com/gogf/gf/g/encoding/gyaml"
+    "github.com/gogf/gf/g/text/gregex"
+)
+
+// 检测数据内容的格式，返回json, xml, yaml, toml或者ini，无法识别时返回错误。
+// 检测时先根据内容特征(首字符、键值分隔符等)确定候选格式，再按照候选顺序尝试解析，返回第一个解析成功的格式，
+// 其中yaml格式要求解析结果为对象或者数组(任意文本均可以解析为yaml字符串)。
+// 与LoadContent的自动识别相比更加准确，但是由于需要尝试解析，性能较低。
+func DetectDataType(data []byte) (string, error) {
+    content := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+    if len(content) == 0 {
+        return "", errors.New("empty content")
+    }
+    for _, t := range detectCandidates(content) {
+        if tryDecode(t, content) {
+            return t, nil
+        }
+    }
+    return "", errors.New("unable to detect data type")
+}
+
+// 自动检测数据内容的格式(参考DetectDataType)并解析为Json对象
+func LoadContentAuto(data []byte) (*Json, error) {
+    t, err := DetectDataType(data)
+    if err != nil {
+        return nil, err
+    }
+    return LoadContent(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), t)
+}
+
+// 根据内容特征获得候选格式列表，最可能的格式在前
+func detectCandidates(content []byte) []string {
+    switch content[0] {
+        case '{':
+            return []string{"json", "yaml"}
+        case '<':
+            return []string{"xml"}
+        case '[':
+            // JSON数组或者TOML/INI分组
+            return []string{"json", "toml", "ini", "yaml"}
+    }
+    // 去掉注释行，避免注释内容影响识别
+    stripped, _ := gregex.Replace(`(?m)^\s*[#;].*$`, nil, content)
+    if gregex.IsMatch(`(?m)^\s*[\w\-\."']+\s*=`, stripped) {
+        return []string{"toml", "ini", "yaml"}
+    }
+    return []string{"yaml", "json", "toml", "ini"}
+}
+
+// 尝试按照指定格式解析内容，判断是否解析成功
+func tryDecode(dataType string, content []byte) bool {
+    switch dataType {
+        case "json":
+            return json.Valid(content)
+        case "xml":
+            _, err := gxml.Decode(content)
+            return err == nil
+        case "toml":
+            _, err := gtoml.Decode(content)
+            return err == nil
+        case "ini":
+            m, err := gini.Decode(content)
+            return err == nil && len(m) > 0
+        case "yaml":
+            v, err := gyaml.Decode(content)
+            if err != nil {
+                return false
+            }
+            switch v.(type) {
+                case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+                    return true
+            }
+    }
+    return false
+}
@@ -0,0 +1,345 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "io/ioutil"
+    "strconv"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/internal/mutex"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 延迟解析的JSON对象，适用于只读取大JSON内容中少量字段的场景。
+// 加载时只校验内容格式，不进行解析；检索时只对检索路径上的对象/数组建立子节点的索引(记录原始内容的位置)，
+// 只有检索到的节点才会被解码，解码结果会被缓存，未访问的子树不会产生任何内存分配。
+// 延迟解析的对象是只读的，需要修改时使用Json方法获得完整解析的Json对象。
+// 注意原始内容不会被复制，在使用期间不能修改。
+type LazyJson struct {
+    mu   *mutex.Mutex
+    root *lazyNode
+    c    byte // 层级分隔符，默认为"."
+}
+
+// 延迟解析的节点
+type lazyNode struct {
+    raw     []byte               // 节点的原始内容
+    indexed bool                 // 是否已建立子节点索引
+    keys    map[string]*lazyNode // 对象的子节点
+    items   []*lazyNode          // 数组的子节点
+    decoded bool                 // 是否已解码
+    value   interface{}          // 解码后的值
+}
+
+// 延迟解析JSON内容，unsafe参数用于创建非并发安全的对象(检索时会修改内部索引，因此默认并发安全)
+func LoadContentLazy(data []byte, unsafe...bool) (*LazyJson, error) {
+    data = bytes.TrimSpace(data)
+    if !json.Valid(data) {
+        return nil, errors.New("invalid json content")
+    }
+    return &LazyJson {
+        mu   : mutex.New(unsafe...),
+        root : &lazyNode{raw : data},
+        c    : byte(gDEFAULT_SPLIT_CHAR),
+    }, nil
+}
+
+// 读取JSON文件并延迟解析，参考LoadContentLazy
+func LoadLazy(path string, unsafe...bool) (*LazyJson, error) {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return LoadContentLazy(data, unsafe...)
+}
+
+// 设置自定义的层级分隔符号
+func (l *LazyJson) SetSplitChar(char byte) {
+    l.mu.Lock()
+    l.c = char
+    l.mu.Unlock()
+}
+
+// 根据pattern检索并解码节点的值，不存在时返回nil，pattern为空时返回完整的内容
+func (l *LazyJson) Get(pattern...string) interface{} {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    node := l.root
+    if len(pattern) > 0 {
+        node = l.find(pattern[0])
+    }
+    if node == nil {
+        return nil
+    }
+    return node.decode()
+}
+
+// 获取节点的原始JSON内容(不进行解码)，不存在时返回nil
+func (l *LazyJson) GetRaw(pattern string) []byte {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    if node := l.find(pattern); node != nil {
+        return node.raw
+    }
+    return nil
+}
+
+// 判断节点是否存在(不进行解码)
+func (l *LazyJson) Contains(pattern string) bool {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.find(pattern) != nil
+}
+
+// 获取对象的键名数量或者数组的长度(只建立索引，不进行解码)，其他类型或者不存在时返回-1
+func (l *LazyJson) Len(pattern string) int {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    node := l.find(pattern)
+    if node == nil {
+        return -1
+    }
+    switch node.raw[0] {
+        case '{':
+            node.index()
+            return len(node.keys)
+        case '[':
+            node.index()
+            return len(node.items)
+    }
+    return -1
+}
+
+// 获取节点并转换为完整解析的Json对象，不存在时返回nil
+func (l *LazyJson) GetJson(pattern string) *Json {
+    if v := l.Get(pattern); v != nil {
+        return New(copyValue(v))
+    }
+    return nil
+}
+
+// 完整解析所有内容并返回Json对象，返回的Json对象可以修改，不影响当前对象
+func (l *LazyJson) Json() *Json {
+    return New(copyValue(l.Get()))
+}
+
+// 将节点的值解析到指定的变量，注意参数应当是变量的指针
+func (l *LazyJson) GetToVar(pattern string, v interface{}) error {
+    if raw := l.GetRaw(pattern); raw != nil {
+        return json.Unmarshal(raw, v)
+    }
+    return nil
+}
+
+// 将节点的值转换为struct对象，规则同Json.GetToStruct
+func (l *LazyJson) GetToStruct(pattern string, objPointer interface{}) error {
+    _, err := mapToStruct(l.Get(pattern), objPointer)
+    return err
+}
+
+func (l *LazyJson) GetMap(pattern string) map[string]interface{} {
+    if r, ok := l.Get(pattern).(map[string]interface{}); ok {
+        return r
+    }
+    return nil
+}
+
+func (l *LazyJson) GetArray(pattern string) []interface{} {
+    if r, ok := l.Get(pattern).([]interface{}); ok {
+        return r
+    }
+    return nil
+}
+
+func (l *LazyJson) GetString(pattern string) string {
+    return gconv.String(l.Get(pattern))
+}
+
+func (l *LazyJson) GetStrings(pattern string) []string {
+    return gconv.Strings(l.Get(pattern))
+}
+
+func (l *LazyJson) GetBool(pattern string) bool {
+    return gconv.Bool(l.Get(pattern))
+}
+
+func (l *LazyJson) GetInt(pattern string) int {
+    return gconv.Int(l.Get(pattern))
+}
+
+func (l *LazyJson) GetInt64(pattern string) int64 {
+    return gconv.Int64(l.Get(pattern))
+}
+
+func (l *LazyJson) GetUint64(pattern string) uint64 {
+    return gconv.Uint64(l.Get(pattern))
+}
+
+func (l *LazyJson) GetFloat64(pattern string) float64 {
+    return gconv.Float64(l.Get(pattern))
+}
+
+func (l *LazyJson) GetTime(pattern string, format...string) time.Time {
+    return gconv.Time(l.Get(pattern), format...)
+}
+
+func (l *LazyJson) GetTimeDuration(pattern string) time.Duration {
+    return gconv.TimeDuration(l.Get(pattern))
+}
+
+// 按照层级检索节点，检索路径上的对象/数组会建立子节点索引
+func (l *LazyJson) find(pattern string) *lazyNode {
+    node := l.root
+    if pattern == "" {
+        return node
+    }
+    for _, key := range strings.Split(pattern, string(l.c)) {
+        if node = node.child(key); node == nil {
+            return nil
+        }
+    }
+    return node
+}
+
+// 获取子节点，数组使用数字索引
+func (n *lazyNode) child(key string) *lazyNode {
+    switch n.raw[0] {
+        case '{':
+            n.index()
+            return n.keys[key]
+        case '[':
+            n.index()
+            if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(n.items) {
+                return n.items[i]
+            }
+    }
+    return nil
+}
+
+// 解码节点的值，结果会被缓存
+func (n *lazyNode) decode() interface{} {
+    if !n.decoded {
+        // 内容已经校验过格式，不会解析失败
+        json.Unmarshal(n.raw, &n.value)
+        n.decoded = true
+    }
+    return n.value
+}
+
+// 建立子节点索引，只扫描当前层级，子节点的内容不做处理
+func (n *lazyNode) index() {
+    if n.indexed {
+        return
+    }
+    n.indexed = true
+    data     := n.raw
+    object   := data[0] == '{'
+    if object {
+        n.keys = make(map[string]*lazyNode)
+    } else {
+        n.items = make([]*lazyNode, 0)
+    }
+    i := skipJsonSpace(data, 1)
+    if data[i] == '}' || data[i] == ']' {
+        return
+    }
+    for i < len(data) {
+        key := ""
+        if object {
+            end := scanJsonString(data, i)
+            key  = unquoteJsonKey(data[i : end])
+            // 跳过冒号
+            i    = skipJsonSpace(data, skipJsonSpace(data, end) + 1)
+        }
+        end  := scanJsonValue(data, i)
+        child := &lazyNode{raw : data[i : end]}
+        if object {
+            n.keys[key] = child
+        } else {
+            n.items = append(n.items, child)
+        }
+        i = skipJsonSpace(data, end)
+        if data[i] != ',' {
+            break
+        }
+        i = skipJsonSpace(data, i + 1)
+    }
+}
+
+// 跳过空白字符，返回下一个非空白字符的位置
+func skipJsonSpace(data []byte, i int) int {
+    for i < len(data) {
+        switch data[i] {
+            case ' ', '\t', '\r', '\n':
+                i++
+            default:
+                return i
+        }
+    }
+    return i
+}
+
+// 扫描字符串，i为开始的双引号位置，返回结束双引号之后的位置
+func scanJsonString(data []byte, i int) int {
+    for i++; i < len(data); i++ {
+        switch data[i] {
+            case '\\':
+                i++
+            case '"':
+                return i + 1
+        }
+    }
+    return i
+}
+
+// 扫描任意值，返回值结束之后的位置
+func scanJsonValue(data []byte, i int) int {
+    switch data[i] {
+        case '"':
+            return scanJsonString(data, i)
+        case '{', '[':
+            depth := 0
+            for i < len(data) {
+                switch data[i] {
+                    case '"':
+                        i = scanJsonString(data, i)
+                        continue
+                    case '{', '[':
+                        depth++
+                    case '}', ']':
+                        depth--
+                        if depth == 0 {
+                            return i + 1
+                        }
+                }
+                i++
+            }
+            return i
+    }
+    for i < len(data) {
+        switch data[i] {
+            case ',', '}', ']', ' ', '\t', '\r', '\n':
+                return i
+        }
+        i++
+    }
+    return i
+}
+
+// 解析对象的键名，不包含转义字符时直接转换
+func unquoteJsonKey(quoted []byte) string {
+    if bytes.IndexByte(quoted, '\\') < 0 {
+        return string(quoted[1 : len(quoted) - 1])
+    }
+    key := ""
+    json.Unmarshal(quoted, &key)
+    return key
+}
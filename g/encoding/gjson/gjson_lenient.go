@@ -0,0 +1,115 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "bytes"
+    "io/ioutil"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 同Load，但是JSON内容使用宽松模式解析，允许 // 及 /* */ 注释，以及对象/数组末尾多余的逗号，
+// 常用于人工编写的JSON配置文件。其他格式(xml, yaml, toml)的解析与Load一致。
+func LoadLenient(path string) (*Json, error) {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return LoadContentLenient(data, gfile.Ext(path))
+}
+
+// 同LoadContent，但是JSON内容使用宽松模式解析，允许 // 及 /* */ 注释，以及对象/数组末尾多余的逗号。
+func LoadContentLenient(data []byte, dataType...string) (*Json, error) {
+    t := ""
+    if len(dataType) > 0 {
+        t = formatDataType(dataType[0])
+    }
+    if t == "" {
+        // 去掉注释后以{或者[开头的内容按照JSON处理
+        if c := bytes.TrimSpace(StripComments(data)); len(c) > 0 && (c[0] == '{' || c[0] == '[') {
+            t = "json"
+        }
+    }
+    if t == "json" {
+        return LoadContent(StripComments(data), "json")
+    }
+    return LoadContent(data, dataType...)
+}
+
+// 将宽松格式的JSON内容转换为标准JSON内容：去掉 // 及 /* */ 注释，以及对象/数组末尾多余的逗号。
+// 字符串中的内容保持不变，注释中的换行符将被保留，以便解析错误时行号保持一致。
+func StripComments(data []byte) []byte {
+    var (
+        buffer   = bytes.NewBuffer(make([]byte, 0, len(data)))
+        inString = false
+        // 当前输出内容中最后一个逗号的位置，逗号之后只有空白字符时有效，否则为-1
+        comma    = -1
+    )
+    for i := 0; i < len(data); i++ {
+        c := data[i]
+        if inString {
+            buffer.WriteByte(c)
+            switch c {
+                case '\\':
+                    if i + 1 < len(data) {
+                        i++
+                        buffer.WriteByte(data[i])
+                    }
+                case '"':
+                    inString = false
+            }
+            continue
+        }
+        switch {
+            case c == '"':
+                inString = true
+                comma    = -1
+                buffer.WriteByte(c)
+
+            case c == '/' && i + 1 < len(data) && data[i + 1] == '/':
+                // 单行注释，保留换行符
+                for i < len(data) && data[i] != '\n' {
+                    i++
+                }
+                if i < len(data) {
+                    buffer.WriteByte('\n')
+                }
+
+            case c == '/' && i + 1 < len(data) && data[i + 1] == '*':
+                // 多行注释，保留其中的换行符
+                i += 2
+                for i < len(data) && !(data[i] == '*' && i + 1 < len(data) && data[i + 1] == '/') {
+                    if data[i] == '\n' {
+                        buffer.WriteByte('\n')
+                    }
+                    i++
+                }
+                i++
+
+            case c == ',':
+                comma = buffer.Len()
+                buffer.WriteByte(c)
+
+            case c == '}' || c == ']':
+                // 去掉末尾多余的逗号
+                if comma >= 0 {
+                    b := buffer.Bytes()
+                    b[comma] = ' '
+                }
+                comma = -1
+                buffer.WriteByte(c)
+
+            case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+                buffer.WriteByte(c)
+
+            default:
+                comma = -1
+                buffer.WriteByte(c)
+        }
+    }
+    return buffer.Bytes()
+}
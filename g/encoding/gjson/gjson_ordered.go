@@ -0,0 +1,336 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "github.com/gogf/gf/g/internal/rwmutex"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 有序模式下记录键名顺序时使用的层级分隔符(避免与键名中的字符冲突)
+const gORDERED_PATH_SEP = "\x1f"
+
+// 有序模式下各层级对象的键名顺序(层级路径 => 键名列表)
+type orderedKeys struct {
+    mu   sync.RWMutex
+    keys map[string][]string
+}
+
+func newOrderedKeys() *orderedKeys {
+    return &orderedKeys {
+        keys : make(map[string][]string),
+    }
+}
+
+// 创建有序模式的Json对象，对象键名的顺序在Get/Set/Remove及导出JSON(ToJson/ToJsonIndent/Dump)时保持不变。
+// value为JSON字符串/[]byte时按照内容中的顺序，为struct时按照属性定义的顺序，为map时按照键名排序。
+// 注意：YAML/TOML/XML等格式在解析及导出时由对应的编码库处理，不保证键名顺序；Apply等批量修改操作新增的键名将按照键名排序追加到末尾。
+func NewOrdered(value interface{}, unsafe...bool) (*Json, error) {
+    var (
+        data []byte
+        err  error
+    )
+    switch v := value.(type) {
+        case string:
+            data = []byte(v)
+        case []byte:
+            data = v
+        default:
+            if data, err = Encode(value); err != nil {
+                return nil, err
+            }
+    }
+    return loadContentOrdered(data, unsafe...)
+}
+
+// 同Load，JSON内容使用有序模式解析，参考NewOrdered
+func LoadOrdered(path string) (*Json, error) {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return LoadContentOrdered(data, gfile.Ext(path))
+}
+
+// 同LoadContent，JSON内容使用有序模式解析，参考NewOrdered。
+// 其他格式的内容先转换为JSON后再进行解析，键名顺序由对应的编码库决定。
+func LoadContentOrdered(data []byte, dataType...string) (*Json, error) {
+    t := ""
+    if len(dataType) > 0 {
+        t = formatDataType(dataType[0])
+    }
+    if t == "" {
+        t = checkDataType(data)
+    }
+    if t != "json" {
+        j, err := LoadContent(data, t)
+        if err != nil {
+            return nil, err
+        }
+        if data, err = j.ToJson(); err != nil {
+            return nil, err
+        }
+    }
+    return loadContentOrdered(data)
+}
+
+// 按照有序模式解析JSON内容
+func loadContentOrdered(data []byte, unsafe...bool) (*Json, error) {
+    keys       := newOrderedKeys()
+    value, err := decodeOrdered(data, keys)
+    if err != nil {
+        return nil, err
+    }
+    return &Json {
+        mu : rwmutex.New(unsafe...),
+        p  : &value,
+        c  : byte(gDEFAULT_SPLIT_CHAR),
+        o  : keys,
+    }, nil
+}
+
+// 判断是否为有序模式
+func (j *Json) IsOrdered() bool {
+    return j.o != nil
+}
+
+// 解析JSON内容，并记录所有对象的键名顺序
+func decodeOrdered(data []byte, keys *orderedKeys) (interface{}, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    value, err := decodeOrderedValue(decoder, "", keys)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := decoder.Token(); err != io.EOF {
+        return nil, errors.New("invalid character after top-level value")
+    }
+    return value, nil
+}
+
+// 解析单个值
+func decodeOrderedValue(decoder *json.Decoder, path string, keys *orderedKeys) (interface{}, error) {
+    token, err := decoder.Token()
+    if err != nil {
+        return nil, err
+    }
+    delim, ok := token.(json.Delim)
+    if !ok {
+        return token, nil
+    }
+    switch delim {
+        case '{':
+            m     := make(map[string]interface{})
+            names := make([]string, 0)
+            for decoder.More() {
+                token, err := decoder.Token()
+                if err != nil {
+                    return nil, err
+                }
+                key, ok := token.(string)
+                if !ok {
+                    return nil, errors.New(fmt.Sprintf("invalid object key: %v", token))
+                }
+                value, err := decodeOrderedValue(decoder, joinOrderedPath(path, key), keys)
+                if err != nil {
+                    return nil, err
+                }
+                if _, ok := m[key]; !ok {
+                    names = append(names, key)
+                }
+                m[key] = value
+            }
+            if _, err := decoder.Token(); err != nil {
+                return nil, err
+            }
+            keys.set(path, names)
+            return m, nil
+
+        case '[':
+            array := make([]interface{}, 0)
+            for i := 0; decoder.More(); i++ {
+                value, err := decodeOrderedValue(decoder, joinOrderedPath(path, strconv.Itoa(i)), keys)
+                if err != nil {
+                    return nil, err
+                }
+                array = append(array, value)
+            }
+            if _, err := decoder.Token(); err != nil {
+                return nil, err
+            }
+            return array, nil
+    }
+    return nil, errors.New(fmt.Sprintf("unexpected delimiter: %v", delim))
+}
+
+// 按照记录的键名顺序编码为JSON内容
+func encodeOrdered(buffer *bytes.Buffer, value interface{}, path string, keys *orderedKeys) error {
+    switch v := value.(type) {
+        case map[string]interface{}:
+            buffer.WriteByte('{')
+            for i, k := range keys.sorted(path, v) {
+                if i > 0 {
+                    buffer.WriteByte(',')
+                }
+                b, _ := json.Marshal(k)
+                buffer.Write(b)
+                buffer.WriteByte(':')
+                if err := encodeOrdered(buffer, v[k], joinOrderedPath(path, k), keys); err != nil {
+                    return err
+                }
+            }
+            buffer.WriteByte('}')
+        case []interface{}:
+            buffer.WriteByte('[')
+            for i, item := range v {
+                if i > 0 {
+                    buffer.WriteByte(',')
+                }
+                if err := encodeOrdered(buffer, item, joinOrderedPath(path, strconv.Itoa(i)), keys); err != nil {
+                    return err
+                }
+            }
+            buffer.WriteByte(']')
+        default:
+            b, err := json.Marshal(v)
+            if err != nil {
+                return err
+            }
+            buffer.Write(b)
+    }
+    return nil
+}
+
+// 有序模式下导出JSON内容
+func (j *Json) toJsonOrdered(indent bool) ([]byte, error) {
+    buffer := bytes.NewBuffer(nil)
+    if err := encodeOrdered(buffer, *(j.p), "", j.o); err != nil {
+        return nil, err
+    }
+    if !indent {
+        return buffer.Bytes(), nil
+    }
+    result := bytes.NewBuffer(nil)
+    if err := json.Indent(result, buffer.Bytes(), "", "\t"); err != nil {
+        return nil, err
+    }
+    return result.Bytes(), nil
+}
+
+// 有序模式下设置值之前，解析值的键名顺序并记录到pattern对应的层级路径下
+func (j *Json) setOrderedValue(pattern string, value interface{}) interface{} {
+    switch value.(type) {
+        case map[string]interface{}, []interface{}, nil:
+            return value
+    }
+    b, err := Encode(value)
+    if err != nil {
+        return value
+    }
+    keys := newOrderedKeys()
+    v, err := decodeOrdered(b, keys)
+    if err != nil {
+        return value
+    }
+    prefix := strings.Replace(pattern, string(j.c), gORDERED_PATH_SEP, -1)
+    for path, names := range keys.keys {
+        if path == "" {
+            j.o.set(prefix, names)
+        } else {
+            j.o.set(joinOrderedPath(prefix, path), names)
+        }
+    }
+    return v
+}
+
+// 同步记录的键名顺序：删除已不存在的键名，新增的键名按照排序追加到末尾
+func (j *Json) syncOrderedKeys() {
+    j.mu.RLock()
+    defer j.mu.RUnlock()
+    j.o.sync(*(j.p), "")
+}
+
+// 获得子层级的有序键名记录，用于GetJson
+func (o *orderedKeys) sub(pattern string, splitChar byte) *orderedKeys {
+    prefix := strings.Replace(pattern, string(splitChar), gORDERED_PATH_SEP, -1)
+    keys   := newOrderedKeys()
+    o.mu.RLock()
+    defer o.mu.RUnlock()
+    for path, names := range o.keys {
+        switch {
+            case path == prefix:
+                keys.keys[""] = names
+            case strings.HasPrefix(path, prefix + gORDERED_PATH_SEP):
+                keys.keys[path[len(prefix) + 1:]] = names
+        }
+    }
+    return keys
+}
+
+func (o *orderedKeys) set(path string, names []string) {
+    o.mu.Lock()
+    o.keys[path] = names
+    o.mu.Unlock()
+}
+
+// 按照记录的顺序返回对象的键名，没有记录的键名按照排序追加到末尾
+func (o *orderedKeys) sorted(path string, m map[string]interface{}) []string {
+    o.mu.RLock()
+    names := o.keys[path]
+    o.mu.RUnlock()
+    result := make([]string, 0, len(m))
+    exists := make(map[string]bool, len(names))
+    for _, k := range names {
+        if _, ok := m[k]; ok && !exists[k] {
+            result    = append(result, k)
+            exists[k] = true
+        }
+    }
+    if len(result) == len(m) {
+        return result
+    }
+    others := make([]string, 0, len(m) - len(result))
+    for k := range m {
+        if !exists[k] {
+            others = append(others, k)
+        }
+    }
+    sort.Strings(others)
+    return append(result, others...)
+}
+
+// 递归同步各层级对象的键名顺序
+func (o *orderedKeys) sync(value interface{}, path string) {
+    switch v := value.(type) {
+        case map[string]interface{}:
+            o.set(path, o.sorted(path, v))
+            for k, item := range v {
+                o.sync(item, joinOrderedPath(path, k))
+            }
+        case []interface{}:
+            for i, item := range v {
+                o.sync(item, joinOrderedPath(path, strconv.Itoa(i)))
+            }
+    }
+}
+
+// 拼接有序键名记录的层级路径
+func joinOrderedPath(path, key string) string {
+    if path == "" {
+        return key
+    }
+    return path + gORDERED_PATH_SEP + key
+}
@@ -0,0 +1,422 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// JSON Patch(RFC 6902)操作项
+type PatchOperation struct {
+    Op    string      `json:"op"`              // 操作类型：add, remove, replace, move, copy, test
+    Path  string      `json:"path"`            // 操作路径(JSON Pointer格式，例如: /users/0/name)
+    From  string      `json:"from,omitempty"`  // 来源路径(move/copy操作)
+    Value interface{} `json:"value,omitempty"` // 操作值(add/replace/test操作)
+}
+
+// 应用补丁，patch为数组时按照JSON Patch(RFC 6902)处理，为对象时按照Merge Patch(RFC 7386)处理。
+// patch支持JSON字符串/[]byte、[]PatchOperation、*Json以及map/slice等变量。
+func (j *Json) Apply(patch interface{}) error {
+    value, err := parseValue(patch)
+    if err != nil {
+        return err
+    }
+    if _, ok := value.([]interface{}); ok {
+        return j.ApplyPatch(value)
+    }
+    return j.ApplyMergePatch(value)
+}
+
+// 应用JSON Patch(RFC 6902)，所有操作均执行成功时才会修改当前对象，任一操作失败时返回错误并保持原内容不变。
+func (j *Json) ApplyPatch(patch interface{}) error {
+    if j.o != nil {
+        defer j.syncOrderedKeys()
+    }
+    value, err := parseValue(patch)
+    if err != nil {
+        return err
+    }
+    operations := make([]PatchOperation, 0)
+    if err := decodeVar(value, &operations); err != nil {
+        return errors.New("invalid json patch: " + err.Error())
+    }
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    doc := copyValue(*j.p)
+    for i, op := range operations {
+        if doc, err = applyPatchOperation(doc, op); err != nil {
+            return errors.New(fmt.Sprintf(`json patch operation %d "%s %s" failed: %s`, i, op.Op, op.Path, err.Error()))
+        }
+    }
+    *j.p = doc
+    return nil
+}
+
+// 应用Merge Patch(RFC 7386)，补丁中值为null的键名将被删除，对象递归合并，其他值直接替换。
+func (j *Json) ApplyMergePatch(patch interface{}) error {
+    if j.o != nil {
+        defer j.syncOrderedKeys()
+    }
+    value, err := parseValue(patch)
+    if err != nil {
+        return err
+    }
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    *j.p = mergePatch(*j.p, value)
+    return nil
+}
+
+// 对比当前对象与other的差异，生成将当前对象转换为other的JSON Patch操作列表(仅包含add/remove/replace操作)。
+func (j *Json) Diff(other *Json) []PatchOperation {
+    var source, target interface{}
+    decodeVar(j.Get(), &source)
+    if other != nil {
+        decodeVar(other.Get(), &target)
+    }
+    operations := make([]PatchOperation, 0)
+    diffValue("", source, target, &operations)
+    return operations
+}
+
+// 生成差异操作
+func diffValue(path string, source, target interface{}, operations *[]PatchOperation) {
+    if reflect.DeepEqual(source, target) {
+        return
+    }
+    switch s := source.(type) {
+        case map[string]interface{}:
+            if t, ok := target.(map[string]interface{}); ok {
+                keys := make([]string, 0, len(s) + len(t))
+                for k := range s {
+                    keys = append(keys, k)
+                }
+                for k := range t {
+                    if _, ok := s[k]; !ok {
+                        keys = append(keys, k)
+                    }
+                }
+                // 排序以保证生成结果稳定
+                sort.Strings(keys)
+                for _, k := range keys {
+                    p      := path + "/" + escapePointerToken(k)
+                    sv, o1 := s[k]
+                    tv, o2 := t[k]
+                    switch {
+                        case o1 && !o2:
+                            *operations = append(*operations, PatchOperation{Op : "remove", Path : p})
+                        case !o1 && o2:
+                            *operations = append(*operations, PatchOperation{Op : "add", Path : p, Value : tv})
+                        default:
+                            diffValue(p, sv, tv, operations)
+                    }
+                }
+                return
+            }
+        case []interface{}:
+            // 数组长度一致时逐个元素对比，否则整体替换
+            if t, ok := target.([]interface{}); ok && len(s) == len(t) {
+                for i := range s {
+                    diffValue(path + "/" + strconv.Itoa(i), s[i], t[i], operations)
+                }
+                return
+            }
+    }
+    *operations = append(*operations, PatchOperation{Op : "replace", Path : path, Value : target})
+}
+
+// 执行单个JSON Patch操作，返回修改后的文档
+func applyPatchOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+    tokens, err := parsePointer(op.Path)
+    if err != nil {
+        return nil, err
+    }
+    switch op.Op {
+        case "add":
+            return patchAdd(doc, tokens, copyValue(op.Value), false)
+
+        case "replace":
+            return patchAdd(doc, tokens, copyValue(op.Value), true)
+
+        case "remove":
+            doc, _, err = patchRemove(doc, tokens)
+            return doc, err
+
+        case "move", "copy":
+            from, err := parsePointer(op.From)
+            if err != nil {
+                return nil, err
+            }
+            value, err := patchGet(doc, from)
+            if err != nil {
+                return nil, err
+            }
+            if op.Op == "move" {
+                if op.Path != op.From && strings.HasPrefix(op.Path, op.From + "/") {
+                    return nil, errors.New("cannot move a value into one of its children")
+                }
+                if doc, _, err = patchRemove(doc, from); err != nil {
+                    return nil, err
+                }
+            } else {
+                value = copyValue(value)
+            }
+            return patchAdd(doc, tokens, value, false)
+
+        case "test":
+            value, err := patchGet(doc, tokens)
+            if err != nil {
+                return nil, err
+            }
+            var expect, actual interface{}
+            decodeVar(op.Value, &expect)
+            decodeVar(value,    &actual)
+            if !reflect.DeepEqual(expect, actual) {
+                return nil, errors.New("test failed")
+            }
+            return doc, nil
+    }
+    return nil, errors.New(fmt.Sprintf(`unsupported operation "%s"`, op.Op))
+}
+
+// 在指定路径添加(replace为true时替换)值，返回修改后的节点
+func patchAdd(node interface{}, tokens []string, value interface{}, replace bool) (interface{}, error) {
+    if len(tokens) == 0 {
+        return value, nil
+    }
+    key := tokens[0]
+    switch n := node.(type) {
+        case map[string]interface{}:
+            child, ok := n[key]
+            if len(tokens) == 1 {
+                if replace && !ok {
+                    return nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+                }
+                n[key] = value
+                return n, nil
+            }
+            if !ok {
+                return nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+            }
+            child, err := patchAdd(child, tokens[1:], value, replace)
+            if err != nil {
+                return nil, err
+            }
+            n[key] = child
+            return n, nil
+
+        case []interface{}:
+            if len(tokens) == 1 && key == "-" && !replace {
+                return append(n, value), nil
+            }
+            index, err := parseArrayIndex(key)
+            if err != nil {
+                return nil, err
+            }
+            if len(tokens) == 1 {
+                if replace {
+                    if index >= len(n) {
+                        return nil, errors.New(fmt.Sprintf(`array index "%s" out of range`, key))
+                    }
+                    n[index] = value
+                    return n, nil
+                }
+                if index > len(n) {
+                    return nil, errors.New(fmt.Sprintf(`array index "%s" out of range`, key))
+                }
+                n = append(n, nil)
+                copy(n[index + 1:], n[index:])
+                n[index] = value
+                return n, nil
+            }
+            if index >= len(n) {
+                return nil, errors.New(fmt.Sprintf(`array index "%s" out of range`, key))
+            }
+            child, err := patchAdd(n[index], tokens[1:], value, replace)
+            if err != nil {
+                return nil, err
+            }
+            n[index] = child
+            return n, nil
+    }
+    return nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+}
+
+// 删除指定路径的值，返回修改后的节点及被删除的值
+func patchRemove(node interface{}, tokens []string) (interface{}, interface{}, error) {
+    if len(tokens) == 0 {
+        return nil, node, nil
+    }
+    key := tokens[0]
+    switch n := node.(type) {
+        case map[string]interface{}:
+            child, ok := n[key]
+            if !ok {
+                return nil, nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+            }
+            if len(tokens) == 1 {
+                delete(n, key)
+                return n, child, nil
+            }
+            child, removed, err := patchRemove(child, tokens[1:])
+            if err != nil {
+                return nil, nil, err
+            }
+            n[key] = child
+            return n, removed, nil
+
+        case []interface{}:
+            index, err := parseArrayIndex(key)
+            if err != nil {
+                return nil, nil, err
+            }
+            if index >= len(n) {
+                return nil, nil, errors.New(fmt.Sprintf(`array index "%s" out of range`, key))
+            }
+            if len(tokens) == 1 {
+                removed := n[index]
+                return append(n[:index], n[index + 1:]...), removed, nil
+            }
+            child, removed, err := patchRemove(n[index], tokens[1:])
+            if err != nil {
+                return nil, nil, err
+            }
+            n[index] = child
+            return n, removed, nil
+    }
+    return nil, nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+}
+
+// 获取指定路径的值
+func patchGet(node interface{}, tokens []string) (interface{}, error) {
+    for _, key := range tokens {
+        switch n := node.(type) {
+            case map[string]interface{}:
+                v, ok := n[key]
+                if !ok {
+                    return nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+                }
+                node = v
+            case []interface{}:
+                index, err := parseArrayIndex(key)
+                if err != nil {
+                    return nil, err
+                }
+                if index >= len(n) {
+                    return nil, errors.New(fmt.Sprintf(`array index "%s" out of range`, key))
+                }
+                node = n[index]
+            default:
+                return nil, errors.New(fmt.Sprintf(`path "%s" not found`, key))
+        }
+    }
+    return node, nil
+}
+
+// Merge Patch(RFC 7386)合并算法
+func mergePatch(target, patch interface{}) interface{} {
+    p, ok := patch.(map[string]interface{})
+    if !ok {
+        return copyValue(patch)
+    }
+    t, ok := target.(map[string]interface{})
+    if !ok {
+        t = make(map[string]interface{})
+    }
+    for k, v := range p {
+        if v == nil {
+            delete(t, k)
+        } else {
+            t[k] = mergePatch(t[k], v)
+        }
+    }
+    return t
+}
+
+// 解析JSON Pointer(RFC 6901)为层级键名列表
+func parsePointer(pointer string) ([]string, error) {
+    if pointer == "" {
+        return []string{}, nil
+    }
+    if pointer[0] != '/' {
+        return nil, errors.New(fmt.Sprintf(`invalid json pointer "%s"`, pointer))
+    }
+    tokens := strings.Split(pointer[1:], "/")
+    for i, v := range tokens {
+        tokens[i] = strings.Replace(strings.Replace(v, "~1", "/", -1), "~0", "~", -1)
+    }
+    return tokens, nil
+}
+
+// 解析JSON Pointer中的数组下标，按照RFC 6901只允许"0"或者不以0开头的十进制数字(例如"01"、"+1"、"-1"均为无效下标)
+func parseArrayIndex(key string) (int, error) {
+    valid := key != "" && (key == "0" || key[0] != '0')
+    for i := 0; valid && i < len(key); i++ {
+        valid = key[i] >= '0' && key[i] <= '9'
+    }
+    if valid {
+        if index, err := strconv.Atoi(key); err == nil {
+            return index, nil
+        }
+    }
+    return 0, errors.New(fmt.Sprintf(`invalid json pointer: invalid array index "%s"`, key))
+}
+
+// 转义JSON Pointer中的键名
+func escapePointerToken(token string) string {
+    return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// 将JSON字符串/[]byte/*Json/struct等参数转换为map/slice变量
+func parseValue(patch interface{}) (interface{}, error) {
+    switch v := patch.(type) {
+        case string:
+            return Decode([]byte(v))
+        case []byte:
+            return Decode(v)
+        case *Json:
+            patch = v.Get()
+    }
+    var value interface{}
+    if err := decodeVar(patch, &value); err != nil {
+        return nil, err
+    }
+    return value, nil
+}
+
+// 通过JSON编码/解码将变量转换为指定类型的变量
+func decodeVar(value interface{}, pointer interface{}) error {
+    b, err := Encode(value)
+    if err != nil {
+        return err
+    }
+    return DecodeTo(b, pointer)
+}
+
+// 深度复制map/slice变量
+func copyValue(value interface{}) interface{} {
+    switch v := value.(type) {
+        case map[string]interface{}:
+            m := make(map[string]interface{}, len(v))
+            for k, item := range v {
+                m[k] = copyValue(item)
+            }
+            return m
+        case []interface{}:
+            a := make([]interface{}, len(v))
+            for i, item := range v {
+                a[i] = copyValue(item)
+            }
+            return a
+    }
+    return value
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "encoding/json"
+    "errors"
+    "reflect"
+    "sync"
+)
+
+// protobuf消息与JSON内容之间的转换器。
+// 框架本身不依赖protobuf库，默认转换器基于encoding/json，使用生成代码中的json标签作为键名，
+// 适用于普通的消息类型；需要完整支持protobuf的JSON映射规则(oneof、Any、Timestamp等内置类型、
+// 枚举名称等)时，可以通过SetProtoCodec设置基于protojson/jsonpb实现的转换器，例如：
+// gjson.SetProtoCodec(gjson.ProtoCodecFunc{
+//     MarshalFunc   : func(m interface{}) ([]byte, error) { return protojson.Marshal(m.(proto.Message)) },
+//     UnmarshalFunc : func(b []byte, m interface{}) error { return protojson.Unmarshal(b, m.(proto.Message)) },
+// })
+type ProtoCodec interface {
+    // 将消息对象转换为JSON内容
+    Marshal(message interface{}) ([]byte, error)
+    // 将JSON内容解析到消息对象，message为消息对象指针
+    Unmarshal(data []byte, message interface{}) error
+}
+
+// 使用函数实现的ProtoCodec
+type ProtoCodecFunc struct {
+    MarshalFunc   func(message interface{}) ([]byte, error)
+    UnmarshalFunc func(data []byte, message interface{}) error
+}
+
+func (c ProtoCodecFunc) Marshal(message interface{}) ([]byte, error) {
+    return c.MarshalFunc(message)
+}
+
+func (c ProtoCodecFunc) Unmarshal(data []byte, message interface{}) error {
+    return c.UnmarshalFunc(data, message)
+}
+
+// 默认的转换器，基于encoding/json实现
+type defaultProtoCodec struct{}
+
+func (c defaultProtoCodec) Marshal(message interface{}) ([]byte, error) {
+    return json.Marshal(message)
+}
+
+func (c defaultProtoCodec) Unmarshal(data []byte, message interface{}) error {
+    return json.Unmarshal(data, message)
+}
+
+var (
+    protoCodecMu sync.RWMutex
+    protoCodec   ProtoCodec = defaultProtoCodec{}
+)
+
+// 设置全局的protobuf消息转换器，参数为nil时恢复为默认转换器
+func SetProtoCodec(codec ProtoCodec) {
+    protoCodecMu.Lock()
+    defer protoCodecMu.Unlock()
+    if codec == nil {
+        codec = defaultProtoCodec{}
+    }
+    protoCodec = codec
+}
+
+// 获得当前的protobuf消息转换器
+func getProtoCodec() ProtoCodec {
+    protoCodecMu.RLock()
+    defer protoCodecMu.RUnlock()
+    return protoCodec
+}
+
+// 将protobuf消息对象转换为Json对象
+func FromProto(message interface{}) (*Json, error) {
+    if message == nil {
+        return nil, errors.New("proto message should not be nil")
+    }
+    data, err := getProtoCodec().Marshal(message)
+    if err != nil {
+        return nil, err
+    }
+    return LoadContent(data, "json")
+}
+
+// 将当前内容转换为protobuf消息对象，注意参数应当是消息对象的指针
+func (j *Json) ToProto(message interface{}) error {
+    if rv := reflect.ValueOf(message); rv.Kind() != reflect.Ptr || rv.IsNil() {
+        return errors.New("proto message should be a non-nil pointer")
+    }
+    data, err := j.ToJson()
+    if err != nil {
+        return err
+    }
+    return getProtoCodec().Unmarshal(data, message)
+}
@@ -0,0 +1,250 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "sort"
+    "strconv"
+    "strings"
+    "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/text/gstr"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 查询表达式的层级类型
+const (
+    querySegmentKey      = iota // 普通键名/数组索引
+    querySegmentWildcard        // 通配符: *
+    querySegmentFilter          // 数组过滤: #(status=paid)
+    querySegmentSlice           // 数组切片: 0:10
+)
+
+// 查询表达式的层级项
+type querySegment struct {
+    kind     int
+    key      string // 键名(querySegmentKey)，或者过滤条件的键名(querySegmentFilter)
+    operator string // 过滤条件的比较操作符，为空时表示判断键名是否存在
+    value    string // 过滤条件的比较值
+    start    int    // 切片起始位置
+    end      int    // 切片结束位置(不包含)
+    hasStart bool
+    hasEnd   bool
+}
+
+// 使用查询表达式检索数据，支持以下扩展语法：
+// 1、通配符 users.*.name        : 匹配map的所有键值或者数组的所有元素；
+// 2、数组过滤 orders.#(status=paid).id : 匹配数组中满足条件的元素，操作符支持 =, ==, !=, >, >=, <, <=，
+//    值可以使用引号包含，省略操作符时(例如: #(email))表示元素存在该键名且值不为null；
+// 3、数组切片 items.0:10        : 匹配数组中指定范围的元素，起止位置均可省略，支持负数(从末尾计算)。
+// 表达式中包含以上语法时返回所有匹配结果组成的[]interface{}(没有匹配时为nil)，第二个返回值表示是否为查询表达式。
+func (j *Json) getByQuery(pattern string) ([]interface{}, bool) {
+    segments, ok := parseQueryPattern(pattern, j.c)
+    if !ok {
+        return nil, false
+    }
+    values := []interface{}{*j.p}
+    for _, segment := range segments {
+        next := make([]interface{}, 0)
+        for _, value := range values {
+            next = append(next, segment.apply(value)...)
+        }
+        values = next
+    }
+    if len(values) == 0 {
+        return nil, true
+    }
+    return values, true
+}
+
+// 解析查询表达式，表达式中不包含扩展语法时第二个返回值为false
+func parseQueryPattern(pattern string, splitChar byte) ([]*querySegment, bool) {
+    if !strings.ContainsAny(pattern, "*#:") {
+        return nil, false
+    }
+    var (
+        segments = make([]*querySegment, 0)
+        isQuery  = false
+        depth    = 0
+        start    = 0
+    )
+    for i := 0; i <= len(pattern); i++ {
+        if i < len(pattern) {
+            switch pattern[i] {
+                case '(':
+                    depth++
+                    continue
+                case ')':
+                    depth--
+                    continue
+            }
+            if pattern[i] != splitChar || depth > 0 {
+                continue
+            }
+        }
+        segment := parseQuerySegment(pattern[start : i])
+        if segment.kind != querySegmentKey {
+            isQuery = true
+        }
+        segments = append(segments, segment)
+        start    = i + 1
+    }
+    return segments, isQuery
+}
+
+// 解析单个层级项
+func parseQuerySegment(s string) *querySegment {
+    switch {
+        case s == "*":
+            return &querySegment{kind : querySegmentWildcard}
+
+        case strings.HasPrefix(s, "#(") && strings.HasSuffix(s, ")"):
+            segment := &querySegment{kind : querySegmentFilter}
+            cond    := strings.TrimSpace(s[2 : len(s) - 1])
+            if match, _ := gregex.MatchString(`^(.+?)\s*(==|!=|>=|<=|=|>|<)\s*(.*)$`, cond); len(match) == 4 {
+                segment.key      = strings.TrimSpace(match[1])
+                segment.operator = match[2]
+                segment.value    = strings.Trim(strings.TrimSpace(match[3]), `"'`)
+            } else {
+                segment.key = cond
+            }
+            return segment
+
+        case gregex.IsMatchString(`^-?\d*:-?\d*$`, s):
+            segment := &querySegment{kind : querySegmentSlice}
+            array   := strings.Split(s, ":")
+            if array[0] != "" {
+                segment.start, _  = strconv.Atoi(array[0])
+                segment.hasStart  = true
+            }
+            if array[1] != "" {
+                segment.end, _  = strconv.Atoi(array[1])
+                segment.hasEnd  = true
+            }
+            return segment
+    }
+    return &querySegment{kind : querySegmentKey, key : s}
+}
+
+// 对指定值执行层级检索，返回所有匹配的结果
+func (s *querySegment) apply(value interface{}) []interface{} {
+    switch s.kind {
+        case querySegmentKey:
+            if v, ok := getChildValue(value, s.key); ok {
+                return []interface{}{v}
+            }
+
+        case querySegmentWildcard:
+            switch v := value.(type) {
+                case map[string]interface{}:
+                    // 按照键名排序，保证结果顺序稳定
+                    keys := make([]string, 0, len(v))
+                    for k := range v {
+                        keys = append(keys, k)
+                    }
+                    sort.Strings(keys)
+                    result := make([]interface{}, len(keys))
+                    for i, k := range keys {
+                        result[i] = v[k]
+                    }
+                    return result
+                case []interface{}:
+                    return v
+            }
+
+        case querySegmentFilter:
+            if array, ok := value.([]interface{}); ok {
+                result := make([]interface{}, 0)
+                for _, item := range array {
+                    if s.filter(item) {
+                        result = append(result, item)
+                    }
+                }
+                return result
+            }
+
+        case querySegmentSlice:
+            if array, ok := value.([]interface{}); ok {
+                start, end := 0, len(array)
+                if s.hasStart {
+                    start = formatSliceIndex(s.start, len(array))
+                }
+                if s.hasEnd {
+                    end = formatSliceIndex(s.end, len(array))
+                }
+                if start < end {
+                    return array[start : end]
+                }
+                return []interface{}{}
+            }
+    }
+    return nil
+}
+
+// 判断数组元素是否满足过滤条件
+func (s *querySegment) filter(item interface{}) bool {
+    var (
+        value = item
+        ok    = true
+    )
+    // 过滤条件的键名支持层级格式，例如: #(user.name=john)
+    for _, key := range strings.Split(s.key, string(gDEFAULT_SPLIT_CHAR)) {
+        if value, ok = getChildValue(value, key); !ok {
+            return false
+        }
+    }
+    if s.operator == "" {
+        return value != nil
+    }
+    // 两边均为数值时按照数值进行比较，否则按照字符串进行比较
+    result := 0
+    if _, isString := value.(string); !isString && gstr.IsNumeric(s.value) && gstr.IsNumeric(gconv.String(value)) {
+        a, b := gconv.Float64(value), gconv.Float64(s.value)
+        switch {
+            case a < b: result = -1
+            case a > b: result = 1
+        }
+    } else {
+        result = strings.Compare(gconv.String(value), s.value)
+    }
+    switch s.operator {
+        case "=", "==": return result == 0
+        case "!=":      return result != 0
+        case ">":       return result > 0
+        case ">=":      return result >= 0
+        case "<":       return result < 0
+        case "<=":      return result <= 0
+    }
+    return false
+}
+
+// 获取map的键值或者数组的元素
+func getChildValue(value interface{}, key string) (interface{}, bool) {
+    switch v := value.(type) {
+        case map[string]interface{}:
+            r, ok := v[key]
+            return r, ok
+        case []interface{}:
+            if n, err := strconv.Atoi(key); err == nil && n >= 0 && n < len(v) {
+                return v[n], true
+            }
+    }
+    return nil, false
+}
+
+// 计算切片位置，负数表示从末尾计算，结果限定在[0, length]范围内
+func formatSliceIndex(index, length int) int {
+    if index < 0 {
+        index += length
+    }
+    if index < 0 {
+        return 0
+    }
+    if index > length {
+        return length
+    }
+    return index
+}
@@ -0,0 +1,319 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "fmt"
+    "math"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+    "github.com/gogf/gf/g/text/gregex"
+)
+
+// JSON Schema校验错误项
+type SchemaError struct {
+    Path    string // 校验失败的数据层级路径(与Get的pattern格式一致，根节点为空)
+    Keyword string // 校验失败的Schema关键字，例如: type, required, minimum
+    Message string // 错误信息
+}
+
+// JSON Schema校验错误列表
+type SchemaErrors []*SchemaError
+
+func (e *SchemaError) Error() string {
+    if e.Path == "" {
+        return e.Message
+    }
+    return e.Path + ": " + e.Message
+}
+
+func (e SchemaErrors) Error() string {
+    messages := make([]string, len(e))
+    for i, v := range e {
+        messages[i] = v.Error()
+    }
+    return strings.Join(messages, "; ")
+}
+
+// 使用JSON Schema校验当前数据，校验通过时返回nil，否则返回SchemaErrors(包含所有校验失败的错误项)。
+// schema支持JSON字符串/[]byte、*Json以及map等变量，支持的关键字(JSON Schema的常用子集)：
+// 通用  : type, enum, const, allOf, anyOf, oneOf, not；
+// 数值  : minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf；
+// 字符串: minLength, maxLength, pattern；
+// 数组  : items, minItems, maxItems, uniqueItems；
+// 对象  : properties, required, additionalProperties, minProperties, maxProperties。
+func (j *Json) ValidateSchema(schema interface{}) error {
+    s, err := parseValue(schema)
+    if err != nil {
+        return err
+    }
+    var data interface{}
+    if err := decodeVar(j.Get(), &data); err != nil {
+        return err
+    }
+    errs := validateSchema("", data, s)
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+
+// 校验单个节点，返回所有的错误项
+func validateSchema(path string, value interface{}, schema interface{}) (errs SchemaErrors) {
+    s, ok := schema.(map[string]interface{})
+    if !ok {
+        // 布尔类型的schema：true表示任意值有效，false表示任意值无效
+        if b, ok := schema.(bool); ok && !b {
+            errs = append(errs, newSchemaError(path, "false", "value is not allowed"))
+        }
+        return
+    }
+    addError := func(keyword string, format string, args...interface{}) {
+        errs = append(errs, newSchemaError(path, keyword, fmt.Sprintf(format, args...)))
+    }
+    // type
+    if t, ok := s["type"]; ok {
+        types := make([]string, 0)
+        switch v := t.(type) {
+            case string:
+                types = append(types, v)
+            case []interface{}:
+                for _, item := range v {
+                    types = append(types, fmt.Sprint(item))
+                }
+        }
+        matched := false
+        for _, v := range types {
+            if checkSchemaType(value, v) {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            addError("type", "expected type %s, got %s", strings.Join(types, "/"), getSchemaType(value))
+            // 类型不匹配时其他关键字的校验没有意义
+            return
+        }
+    }
+    // enum/const
+    if enum, ok := s["enum"].([]interface{}); ok {
+        found := false
+        for _, v := range enum {
+            if reflect.DeepEqual(v, value) {
+                found = true
+                break
+            }
+        }
+        if !found {
+            addError("enum", "value should be one of %s", formatSchemaValue(enum))
+        }
+    }
+    if c, ok := s["const"]; ok && !reflect.DeepEqual(c, value) {
+        addError("const", "value should be %s", formatSchemaValue(c))
+    }
+    // 组合关键字
+    if list, ok := s["allOf"].([]interface{}); ok {
+        for _, item := range list {
+            errs = append(errs, validateSchema(path, value, item)...)
+        }
+    }
+    if list, ok := s["anyOf"].([]interface{}); ok {
+        matched := false
+        for _, item := range list {
+            if len(validateSchema(path, value, item)) == 0 {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            addError("anyOf", "value does not match any of the schemas")
+        }
+    }
+    if list, ok := s["oneOf"].([]interface{}); ok {
+        count := 0
+        for _, item := range list {
+            if len(validateSchema(path, value, item)) == 0 {
+                count++
+            }
+        }
+        if count != 1 {
+            addError("oneOf", "value should match exactly one schema, matched %d", count)
+        }
+    }
+    if not, ok := s["not"]; ok && len(validateSchema(path, value, not)) == 0 {
+        addError("not", "value should not match the schema")
+    }
+    switch v := value.(type) {
+        case float64:
+            errs = append(errs, validateSchemaNumber(path, v, s)...)
+        case string:
+            length := utf8.RuneCountInString(v)
+            if n, ok := getSchemaNumber(s, "minLength"); ok && float64(length) < n {
+                addError("minLength", "length should be at least %v", n)
+            }
+            if n, ok := getSchemaNumber(s, "maxLength"); ok && float64(length) > n {
+                addError("maxLength", "length should be at most %v", n)
+            }
+            if p, ok := s["pattern"].(string); ok && !gregex.IsMatchString(p, v) {
+                addError("pattern", "value should match pattern %s", p)
+            }
+        case []interface{}:
+            if n, ok := getSchemaNumber(s, "minItems"); ok && float64(len(v)) < n {
+                addError("minItems", "array should have at least %v items", n)
+            }
+            if n, ok := getSchemaNumber(s, "maxItems"); ok && float64(len(v)) > n {
+                addError("maxItems", "array should have at most %v items", n)
+            }
+            if unique, _ := s["uniqueItems"].(bool); unique {
+                for i := 0; i < len(v); i++ {
+                    for k := i + 1; k < len(v); k++ {
+                        if reflect.DeepEqual(v[i], v[k]) {
+                            addError("uniqueItems", "items %d and %d are duplicated", i, k)
+                        }
+                    }
+                }
+            }
+            if items, ok := s["items"]; ok {
+                for i, item := range v {
+                    errs = append(errs, validateSchema(joinSchemaPath(path, strconv.Itoa(i)), item, items)...)
+                }
+            }
+        case map[string]interface{}:
+            if n, ok := getSchemaNumber(s, "minProperties"); ok && float64(len(v)) < n {
+                addError("minProperties", "object should have at least %v properties", n)
+            }
+            if n, ok := getSchemaNumber(s, "maxProperties"); ok && float64(len(v)) > n {
+                addError("maxProperties", "object should have at most %v properties", n)
+            }
+            if required, ok := s["required"].([]interface{}); ok {
+                for _, item := range required {
+                    name := fmt.Sprint(item)
+                    if _, ok := v[name]; !ok {
+                        errs = append(errs, newSchemaError(joinSchemaPath(path, name), "required", "field is required"))
+                    }
+                }
+            }
+            properties, _ := s["properties"].(map[string]interface{})
+            // 按照键名排序，保证错误顺序稳定
+            keys := make([]string, 0, len(v))
+            for k := range v {
+                keys = append(keys, k)
+            }
+            sort.Strings(keys)
+            for _, k := range keys {
+                if p, ok := properties[k]; ok {
+                    errs = append(errs, validateSchema(joinSchemaPath(path, k), v[k], p)...)
+                } else if additional, ok := s["additionalProperties"]; ok {
+                    if b, ok := additional.(bool); ok {
+                        if !b {
+                            errs = append(errs, newSchemaError(joinSchemaPath(path, k), "additionalProperties", "field is not allowed"))
+                        }
+                    } else {
+                        errs = append(errs, validateSchema(joinSchemaPath(path, k), v[k], additional)...)
+                    }
+                }
+            }
+    }
+    return
+}
+
+// 校验数值相关的关键字
+func validateSchemaNumber(path string, value float64, s map[string]interface{}) (errs SchemaErrors) {
+    addError := func(keyword string, format string, args...interface{}) {
+        errs = append(errs, newSchemaError(path, keyword, fmt.Sprintf(format, args...)))
+    }
+    if n, ok := getSchemaNumber(s, "minimum"); ok {
+        // 兼容draft-04中布尔类型的exclusiveMinimum
+        if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive {
+            if value <= n {
+                addError("minimum", "value should be greater than %v", n)
+            }
+        } else if value < n {
+            addError("minimum", "value should be greater than or equal to %v", n)
+        }
+    }
+    if n, ok := getSchemaNumber(s, "maximum"); ok {
+        if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive {
+            if value >= n {
+                addError("maximum", "value should be less than %v", n)
+            }
+        } else if value > n {
+            addError("maximum", "value should be less than or equal to %v", n)
+        }
+    }
+    if n, ok := getSchemaNumber(s, "exclusiveMinimum"); ok && value <= n {
+        addError("exclusiveMinimum", "value should be greater than %v", n)
+    }
+    if n, ok := getSchemaNumber(s, "exclusiveMaximum"); ok && value >= n {
+        addError("exclusiveMaximum", "value should be less than %v", n)
+    }
+    if n, ok := getSchemaNumber(s, "multipleOf"); ok && n > 0 {
+        if r := value / n; math.Abs(r - math.Round(r)) > 1e-9 {
+            addError("multipleOf", "value should be a multiple of %v", n)
+        }
+    }
+    return
+}
+
+// 判断值是否为指定的JSON Schema类型
+func checkSchemaType(value interface{}, t string) bool {
+    switch t {
+        case "integer":
+            v, ok := value.(float64)
+            return ok && v == math.Trunc(v)
+        case "number":
+            _, ok := value.(float64)
+            return ok
+    }
+    return getSchemaType(value) == t
+}
+
+// 获得值对应的JSON Schema类型名称
+func getSchemaType(value interface{}) string {
+    switch value.(type) {
+        case nil:                    return "null"
+        case bool:                   return "boolean"
+        case float64:                return "number"
+        case string:                 return "string"
+        case []interface{}:          return "array"
+        case map[string]interface{}: return "object"
+    }
+    return reflect.TypeOf(value).String()
+}
+
+// 获得schema中的数值类型关键字
+func getSchemaNumber(s map[string]interface{}, keyword string) (float64, bool) {
+    n, ok := s[keyword].(float64)
+    return n, ok
+}
+
+// 拼接数据层级路径
+func joinSchemaPath(path, key string) string {
+    if path == "" {
+        return key
+    }
+    return path + string(gDEFAULT_SPLIT_CHAR) + key
+}
+
+// 格式化schema中的值，用于错误信息
+func formatSchemaValue(value interface{}) string {
+    if b, err := Encode(value); err == nil {
+        return string(b)
+    }
+    return fmt.Sprint(value)
+}
+
+func newSchemaError(path, keyword, message string) *SchemaError {
+    return &SchemaError {
+        Path    : path,
+        Keyword : keyword,
+        Message : message,
+    }
+}
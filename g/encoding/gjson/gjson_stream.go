@@ -0,0 +1,149 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// 流式解析时回调函数返回false表示停止解析
+var errStreamStop = errors.New("stream stopped")
+
+// 流式解析JSON内容，适用于无法一次性加载到内存中的超大JSON文件或者网络数据流。
+// 解析过程中只有与pattern匹配的节点会被完整解码并传递给callback，其余节点在读取时直接丢弃。
+// pattern使用与Get相同的层级格式，其中"*"匹配任意的键名或者数组索引，例如：
+// "items.*"      : 逐个获取items数组中的元素；
+// "users.*.name" : 逐个获取users中每个元素的name属性；
+// callback的参数path为匹配节点的实际层级路径(例如: items.10)，返回false时停止解析。
+func Stream(reader io.Reader, pattern string, callback func(path string, value interface{}) bool) error {
+    s := &streamParser {
+        decoder  : json.NewDecoder(reader),
+        callback : callback,
+    }
+    if pattern != "" {
+        s.pattern = strings.Split(pattern, string(gDEFAULT_SPLIT_CHAR))
+    }
+    err := s.walk(make([]string, 0))
+    if err == errStreamStop {
+        return nil
+    }
+    return err
+}
+
+// 流式解析JSON文件，参考Stream
+func StreamFile(path string, pattern string, callback func(path string, value interface{}) bool) error {
+    file, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    return Stream(file, pattern, callback)
+}
+
+// 流式解析器
+type streamParser struct {
+    decoder  *json.Decoder
+    pattern  []string
+    callback func(path string, value interface{}) bool
+}
+
+// 解析当前位置的节点，path为当前节点的层级路径
+func (s *streamParser) walk(path []string) error {
+    switch s.match(path) {
+        // 完全匹配，解码整个节点
+        case 1:
+            var value interface{}
+            if err := s.decoder.Decode(&value); err != nil {
+                return err
+            }
+            if !s.callback(strings.Join(path, string(gDEFAULT_SPLIT_CHAR)), value) {
+                return errStreamStop
+            }
+            return nil
+        // 不匹配，丢弃整个节点
+        case -1:
+            return s.skip()
+    }
+    // 部分匹配，继续解析子节点
+    token, err := s.decoder.Token()
+    if err != nil {
+        return err
+    }
+    delim, ok := token.(json.Delim)
+    if !ok {
+        return nil
+    }
+    switch delim {
+        case '{':
+            for s.decoder.More() {
+                token, err := s.decoder.Token()
+                if err != nil {
+                    return err
+                }
+                key, ok := token.(string)
+                if !ok {
+                    return errors.New(fmt.Sprintf("invalid object key: %v", token))
+                }
+                if err := s.walk(append(path, key)); err != nil {
+                    return err
+                }
+            }
+        case '[':
+            for i := 0; s.decoder.More(); i++ {
+                if err := s.walk(append(path, strconv.Itoa(i))); err != nil {
+                    return err
+                }
+            }
+    }
+    // 读取结束符号
+    _, err = s.decoder.Token()
+    return err
+}
+
+// 判断层级路径与pattern的匹配情况：1表示完全匹配，0表示部分匹配(子节点可能匹配)，-1表示不匹配
+func (s *streamParser) match(path []string) int {
+    if len(path) > len(s.pattern) {
+        return -1
+    }
+    for i, v := range path {
+        if s.pattern[i] != "*" && s.pattern[i] != v {
+            return -1
+        }
+    }
+    if len(path) == len(s.pattern) {
+        return 1
+    }
+    return 0
+}
+
+// 丢弃当前位置的节点(包括所有子节点)
+func (s *streamParser) skip() error {
+    depth := 0
+    for {
+        token, err := s.decoder.Token()
+        if err != nil {
+            return err
+        }
+        if delim, ok := token.(json.Delim); ok {
+            switch delim {
+                case '{', '[':
+                    depth++
+                default:
+                    depth--
+            }
+        }
+        if depth == 0 {
+            return nil
+        }
+    }
+}
@@ -0,0 +1,417 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gjson
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/text/gstr"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 自定义的属性值转换接口，属性类型的指针实现该接口时，ToStruct将使用该接口进行赋值，
+// value为对应的原始数据(map[string]interface{}, []interface{}, string, float64等)。
+type ValueUnmarshaler interface {
+    UnmarshalValue(value interface{}) error
+}
+
+// 自定义的类型转换函数，返回值的类型应当可以赋值给注册的类型
+type ConverterFunc func(value interface{}) (interface{}, error)
+
+// 未映射到struct属性的数据键名列表(ToStructStrict)
+type UnmappedError struct {
+    Fields []string // 未映射的数据层级路径(与Get的pattern格式一致)
+}
+
+func (e *UnmappedError) Error() string {
+    return "unmapped fields: " + strings.Join(e.Fields, ", ")
+}
+
+var (
+    // 自定义的类型转换函数(reflect.Type => ConverterFunc)
+    converters   = make(map[reflect.Type]ConverterFunc)
+    convertersMu sync.RWMutex
+    // 常用的时间类型
+    timeType     = reflect.TypeOf(time.Time{})
+    gtimeType    = reflect.TypeOf(gtime.Time{})
+    durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// 注册指定类型的转换函数，typ可以为该类型的任意值(例如: uuid.UUID{})或者reflect.Type，
+// ToStruct对该类型的属性赋值时将优先使用注册的转换函数，fn为nil时删除注册。
+func RegisterConverter(typ interface{}, fn ConverterFunc) {
+    t, ok := typ.(reflect.Type)
+    if !ok {
+        t = reflect.TypeOf(typ)
+    }
+    convertersMu.Lock()
+    defer convertersMu.Unlock()
+    if fn == nil {
+        delete(converters, t)
+    } else {
+        converters[t] = fn
+    }
+}
+
+// 获得指定类型的转换函数
+func getConverter(t reflect.Type) ConverterFunc {
+    convertersMu.RLock()
+    defer convertersMu.RUnlock()
+    return converters[t]
+}
+
+// struct属性赋值器
+type structMapper struct {
+    unmapped []string // 未映射的数据层级路径
+}
+
+// struct属性信息
+type structField struct {
+    value  reflect.Value
+    name   string // 属性名称
+    tags   []string // 标签指定的键名(gconv标签支持使用逗号指定多个键名)
+    layout string // 时间类型的解析格式，layout标签为Go时间格式(2006-01-02)，format标签为gtime格式(Y-m-d)
+    format string
+}
+
+// 将数据value赋值给对象指针pointer，数据中未能映射到属性的键名按照层级路径返回。
+// 支持嵌套的struct/struct指针、匿名嵌入的struct(属性展开到外层)、struct数组、map、
+// time.Time/gtime.Time(通过layout或format标签指定解析格式，数值类型按照时间戳解析)，
+// 以及ValueUnmarshaler接口及RegisterConverter注册的自定义类型转换。
+func mapToStruct(value interface{}, pointer interface{}) ([]string, error) {
+    rv := reflect.ValueOf(pointer)
+    if rv.Kind() != reflect.Ptr || rv.IsNil() {
+        return nil, errors.New("object pointer should be a non-nil pointer")
+    }
+    m := &structMapper{}
+    if err := m.bind("", value, rv.Elem(), nil); err != nil {
+        return nil, err
+    }
+    return m.unmapped, nil
+}
+
+// 对指定属性进行赋值，field为属性的标签信息(非struct属性时为nil)
+func (m *structMapper) bind(path string, value interface{}, rv reflect.Value, field *structField) error {
+    if value == nil {
+        return nil
+    }
+    value = normalizeStructValue(value)
+    if fn := getConverter(rv.Type()); fn != nil {
+        result, err := fn(value)
+        if err != nil {
+            return newBindError(path, err)
+        }
+        if result == nil {
+            return nil
+        }
+        v := reflect.ValueOf(result)
+        if !v.Type().AssignableTo(rv.Type()) {
+            return newBindError(path, errors.New(fmt.Sprintf("converter returned %s for %s", v.Type(), rv.Type())))
+        }
+        rv.Set(v)
+        return nil
+    }
+    if rv.Kind() == reflect.Ptr {
+        if rv.IsNil() {
+            rv.Set(reflect.New(rv.Type().Elem()))
+        }
+        return m.bind(path, value, rv.Elem(), field)
+    }
+    if rv.CanAddr() {
+        if u, ok := rv.Addr().Interface().(ValueUnmarshaler); ok {
+            return newBindError(path, u.UnmarshalValue(value))
+        }
+    }
+    switch rv.Type() {
+        case timeType, gtimeType:
+            t, err := parseStructTime(value, field)
+            if err != nil {
+                return newBindError(path, err)
+            }
+            if t == nil {
+                return nil
+            }
+            if rv.Type() == timeType {
+                rv.Set(reflect.ValueOf(t.Time))
+            } else {
+                rv.Set(reflect.ValueOf(*t))
+            }
+            return nil
+
+        case durationType:
+            if s, ok := value.(string); ok && !gstr.IsNumeric(s) {
+                d, err := time.ParseDuration(s)
+                if err != nil {
+                    return newBindError(path, err)
+                }
+                rv.SetInt(int64(d))
+            } else {
+                rv.SetInt(gconv.Int64(value))
+            }
+            return nil
+    }
+    switch rv.Kind() {
+        case reflect.Struct:
+            if data, ok := value.(map[string]interface{}); ok {
+                return m.bindStruct(path, data, rv)
+            }
+            return newBindError(path, gconv.Struct(value, rv.Addr().Interface()))
+
+        case reflect.Slice:
+            if s, ok := value.(string); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+                rv.SetBytes([]byte(s))
+                return nil
+            }
+            array, ok := value.([]interface{})
+            if !ok {
+                array = []interface{}{value}
+            }
+            slice := reflect.MakeSlice(rv.Type(), len(array), len(array))
+            for i, item := range array {
+                if err := m.bind(joinSchemaPath(path, strconv.Itoa(i)), item, slice.Index(i), field); err != nil {
+                    return err
+                }
+            }
+            rv.Set(slice)
+            return nil
+
+        case reflect.Array:
+            array, ok := value.([]interface{})
+            if !ok {
+                array = []interface{}{value}
+            }
+            for i := 0; i < len(array) && i < rv.Len(); i++ {
+                if err := m.bind(joinSchemaPath(path, strconv.Itoa(i)), array[i], rv.Index(i), field); err != nil {
+                    return err
+                }
+            }
+            return nil
+
+        case reflect.Map:
+            data, ok := value.(map[string]interface{})
+            if !ok {
+                return nil
+            }
+            if rv.IsNil() {
+                rv.Set(reflect.MakeMap(rv.Type()))
+            }
+            keyType  := rv.Type().Key()
+            elemType := rv.Type().Elem()
+            for k, v := range data {
+                key := reflect.New(keyType).Elem()
+                if err := m.bind(path, k, key, nil); err != nil {
+                    return err
+                }
+                elem := reflect.New(elemType).Elem()
+                if err := m.bind(joinSchemaPath(path, k), v, elem, field); err != nil {
+                    return err
+                }
+                rv.SetMapIndex(key, elem)
+            }
+            return nil
+
+        case reflect.Interface:
+            v := reflect.ValueOf(value)
+            if v.Type().AssignableTo(rv.Type()) {
+                rv.Set(v)
+            }
+            return nil
+
+        case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+            reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+            reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+            // 按照基础类型转换后再转换为属性的类型，以支持自定义的基础类型(例如: type Status int)
+            v := reflect.ValueOf(gconv.Convert(value, rv.Kind().String()))
+            rv.Set(v.Convert(rv.Type()))
+            return nil
+    }
+    return nil
+}
+
+// 对struct的属性进行赋值，数据中未能映射到属性的键名记录到unmapped中
+func (m *structMapper) bindStruct(path string, data map[string]interface{}, rv reflect.Value) error {
+    fields := m.getStructFields(rv)
+    // 按照键名排序，保证未映射键名的顺序稳定
+    keys := make([]string, 0, len(data))
+    for k := range data {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    for _, k := range keys {
+        field := matchStructField(fields, k)
+        if field == nil {
+            m.unmapped = append(m.unmapped, joinSchemaPath(path, k))
+            continue
+        }
+        if err := m.bind(joinSchemaPath(path, k), data[k], field.value, field); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 获得struct的所有可赋值属性，匿名嵌入且未指定键名标签的struct属性展开到外层，外层属性优先
+func (m *structMapper) getStructFields(rv reflect.Value) []*structField {
+    fields   := make([]*structField, 0)
+    embedded := make([]reflect.Value, 0)
+    rt       := rv.Type()
+    for i := 0; i < rv.NumField(); i++ {
+        t := rt.Field(i)
+        // 非公开属性无法赋值(嵌入的非公开struct类型中的公开属性仍然可以赋值)
+        if t.PkgPath != "" && !t.Anonymous {
+            continue
+        }
+        field := &structField {
+            value  : rv.Field(i),
+            name   : t.Name,
+            layout : t.Tag.Get("layout"),
+            format : t.Tag.Get("format"),
+        }
+        if tag := t.Tag.Get("gconv"); tag != "" {
+            for _, v := range strings.Split(tag, ",") {
+                field.tags = append(field.tags, strings.TrimSpace(v))
+            }
+        } else if tag := t.Tag.Get("json"); tag != "" {
+            field.tags = append(field.tags, strings.TrimSpace(strings.Split(tag, ",")[0]))
+        }
+        if len(field.tags) == 1 && field.tags[0] == "-" {
+            continue
+        }
+        if t.Anonymous && len(field.tags) == 0 {
+            ft := t.Type
+            if ft.Kind() == reflect.Ptr {
+                ft = ft.Elem()
+            }
+            if ft.Kind() == reflect.Struct && ft != timeType && ft != gtimeType {
+                embedded = append(embedded, field.value)
+                continue
+            }
+        }
+        if t.PkgPath != "" {
+            continue
+        }
+        fields = append(fields, field)
+    }
+    for _, v := range embedded {
+        if v.Kind() == reflect.Ptr {
+            if v.IsNil() {
+                if !v.CanSet() {
+                    continue
+                }
+                v.Set(reflect.New(v.Type().Elem()))
+            }
+            v = v.Elem()
+        }
+        fields = append(fields, m.getStructFields(v)...)
+    }
+    return fields
+}
+
+// 根据键名查找对应的属性，优先匹配标签，其次匹配属性名称(忽略大小写及 _ - 空格)
+func matchStructField(fields []*structField, key string) *structField {
+    for _, field := range fields {
+        for _, tag := range field.tags {
+            if tag == key {
+                return field
+            }
+        }
+    }
+    for _, field := range fields {
+        if len(field.tags) == 0 && field.name == key {
+            return field
+        }
+    }
+    name := normalizeFieldName(key)
+    for _, field := range fields {
+        if len(field.tags) == 0 && strings.EqualFold(normalizeFieldName(field.name), name) {
+            return field
+        }
+    }
+    return nil
+}
+
+// 去掉名称中的 _ - 空格，用于模糊匹配
+func normalizeFieldName(name string) string {
+    return gstr.ReplaceByMap(name, map[string]string {
+        "_" : "",
+        "-" : "",
+        " " : "",
+    })
+}
+
+// 解析时间类型的属性值，数值类型按照时间戳(秒)解析，空字符串返回nil
+func parseStructTime(value interface{}, field *structField) (*gtime.Time, error) {
+    switch v := value.(type) {
+        case float64:
+            return gtime.NewFromTimeStamp(int64(v)), nil
+        case string:
+            if v == "" {
+                return nil, nil
+            }
+            if field != nil && field.layout != "" {
+                return gtime.StrToTimeLayout(v, field.layout)
+            }
+            if field != nil && field.format != "" {
+                return gtime.StrToTimeFormat(v, field.format)
+            }
+            if gstr.IsNumeric(v) {
+                return gtime.NewFromTimeStamp(gconv.Int64(v)), nil
+            }
+            // 兼容RFC3339格式(encoding/json对time.Time的默认编码格式)
+            if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+                return gtime.NewFromTime(t), nil
+            }
+            return gtime.StrToTime(v)
+    }
+    return gconv.GTime(value), nil
+}
+
+// 将非JSON标准类型的map/slice数据转换为map[string]interface{}/[]interface{}，便于统一处理
+func normalizeStructValue(value interface{}) interface{} {
+    switch value.(type) {
+        case map[string]interface{}, []interface{}, []byte, string:
+            return value
+    }
+    rv := reflect.ValueOf(value)
+    switch rv.Kind() {
+        case reflect.Map:
+            return gconv.Map(value)
+        case reflect.Slice, reflect.Array:
+            array := make([]interface{}, rv.Len())
+            for i := 0; i < rv.Len(); i++ {
+                array[i] = rv.Index(i).Interface()
+            }
+            return array
+    }
+    return value
+}
+
+// 为赋值错误增加数据层级路径
+func newBindError(path string, err error) error {
+    if err == nil || path == "" {
+        return err
+    }
+    return errors.New(fmt.Sprintf("%s: %s", path, err.Error()))
+}
+
+// 存在未映射的键名时返回*UnmappedError
+func checkUnmapped(unmapped []string, err error) error {
+    if err != nil {
+        return err
+    }
+    if len(unmapped) > 0 {
+        return &UnmappedError{Fields : unmapped}
+    }
+    return nil
+}
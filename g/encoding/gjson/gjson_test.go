@@ -0,0 +1,554 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gjson_test
+
+import (
+    "errors"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_Stream(t *testing.T) {
+    data := `{"meta":{"total":3,"skip":[1,{"a":[]}]},"users":[{"name":"john","age":18},{"name":"smith"},{"name":"lily"}]}`
+    gtest.Case(t, func() {
+        paths  := make([]string, 0)
+        values := make([]interface{}, 0)
+        err    := gjson.Stream(strings.NewReader(data), "users.*.name", func(path string, value interface{}) bool {
+            paths  = append(paths, path)
+            values = append(values, value)
+            return true
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(paths,  []string{"users.0.name", "users.1.name", "users.2.name"})
+        gtest.Assert(values, []interface{}{"john", "smith", "lily"})
+    })
+    gtest.Case(t, func() {
+        count := 0
+        err   := gjson.Stream(strings.NewReader(data), "users.*", func(path string, value interface{}) bool {
+            count++
+            gtest.Assert(gjson.New(value).GetString("name"), "john")
+            return false
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(count, 1)
+    })
+    gtest.Case(t, func() {
+        total := 0
+        err   := gjson.Stream(strings.NewReader(data), "meta.total", func(path string, value interface{}) bool {
+            total = gjson.New(map[string]interface{}{"v" : value}).GetInt("v")
+            return true
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(total, 3)
+    })
+    gtest.Case(t, func() {
+        err := gjson.Stream(strings.NewReader(`{"users":[{"name":`), "users.*.name", func(path string, value interface{}) bool {
+            return true
+        })
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_Patch(t *testing.T) {
+    gtest.Case(t, func() {
+        j, err := gjson.LoadContent([]byte(`{"name":"gf","tags":["a","b"],"db":{"host":"127.0.0.1","port":3306}}`))
+        gtest.Assert(err, nil)
+        err = j.Apply(`[
+            {"op":"replace", "path":"/name",     "value":"goframe"},
+            {"op":"add",     "path":"/tags/1",   "value":"c"},
+            {"op":"add",     "path":"/tags/-",   "value":"d"},
+            {"op":"remove",  "path":"/db/port"},
+            {"op":"copy",    "from":"/db/host",  "path":"/host"},
+            {"op":"move",    "from":"/host",     "path":"/db/addr"},
+            {"op":"test",    "path":"/db/addr",  "value":"127.0.0.1"}
+        ]`)
+        gtest.Assert(err, nil)
+        gtest.Assert(j.GetString("name"), "goframe")
+        gtest.Assert(j.GetStrings("tags"), []string{"a", "c", "b", "d"})
+        gtest.Assert(j.Get("db.port"), nil)
+        gtest.Assert(j.Get("host"), nil)
+        gtest.Assert(j.GetString("db.addr"), "127.0.0.1")
+
+        // 任一操作失败时内容保持不变
+        err = j.ApplyPatch([]gjson.PatchOperation{
+            {Op : "replace", Path : "/name", Value : "x"},
+            {Op : "test",    Path : "/name", Value : "y"},
+        })
+        gtest.AssertNE(err, nil)
+        gtest.Assert(j.GetString("name"), "goframe")
+        gtest.AssertNE(j.ApplyPatch(`[{"op":"remove","path":"/none"}]`), nil)
+
+        // 数组下标不允许以0开头(除"0"本身)或者带有符号
+        for _, path := range []string{"/tags/01", "/tags/00", "/tags/+1", "/tags/-1"} {
+            err = j.ApplyPatch([]gjson.PatchOperation{{Op : "add", Path : path, Value : "x"}})
+            gtest.AssertNE(err, nil)
+            gtest.Assert(strings.Contains(err.Error(), "invalid json pointer"), true)
+        }
+        gtest.AssertNE(j.ApplyPatch(`[{"op":"remove","path":"/tags/01"}]`), nil)
+        gtest.AssertNE(j.ApplyPatch(`[{"op":"test","path":"/tags/01","value":"c"}]`), nil)
+        gtest.Assert(j.GetStrings("tags"), []string{"a", "c", "b", "d"})
+        gtest.Assert(j.ApplyPatch(`[{"op":"test","path":"/tags/0","value":"a"}]`), nil)
+    })
+    gtest.Case(t, func() {
+        j := gjson.New(map[string]interface{}{
+            "name" : "gf",
+            "db"   : map[string]interface{}{"host" : "127.0.0.1", "port" : 3306},
+        })
+        err := j.Apply(map[string]interface{}{
+            "name" : nil,
+            "db"   : map[string]interface{}{"port" : 3307, "user" : "root"},
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(j.Get("name"), nil)
+        gtest.Assert(j.GetString("db.host"), "127.0.0.1")
+        gtest.Assert(j.GetInt("db.port"), 3307)
+        gtest.Assert(j.GetString("db.user"), "root")
+    })
+    gtest.Case(t, func() {
+        j1, _ := gjson.LoadContent([]byte(`{"a":1,"b":{"c":[1,2],"d/e":"x"},"f":[1]}`))
+        j2, _ := gjson.LoadContent([]byte(`{"a":1,"b":{"c":[1,3],"g":true},"f":[1,2]}`))
+        operations := j1.Diff(j2)
+        gtest.Assert(len(operations), 4)
+        gtest.Assert(operations[0].Op,   "replace")
+        gtest.Assert(operations[0].Path, "/b/c/1")
+        gtest.Assert(operations[1].Op,   "remove")
+        gtest.Assert(operations[1].Path, "/b/d~1e")
+        gtest.Assert(j1.ApplyPatch(operations), nil)
+        gtest.Assert(len(j1.Diff(j2)), 0)
+    })
+}
+
+func Test_ValidateSchema(t *testing.T) {
+    schema := `{
+        "type"     : "object",
+        "required" : ["name", "age"],
+        "properties" : {
+            "name"   : {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+            "age"    : {"type": "integer", "minimum": 0, "maximum": 150},
+            "status" : {"enum": ["active", "disabled"]},
+            "tags"   : {"type": "array", "maxItems": 2, "uniqueItems": true, "items": {"type": "string"}},
+            "address": {
+                "type": "object",
+                "required": ["city"],
+                "properties": {"city": {"type": "string"}},
+                "additionalProperties": false
+            }
+        }
+    }`
+    gtest.Case(t, func() {
+        j := gjson.New(map[string]interface{}{
+            "name"    : "john",
+            "age"     : 18,
+            "status"  : "active",
+            "tags"    : []string{"a", "b"},
+            "address" : map[string]interface{}{"city" : "Chengdu"},
+        })
+        gtest.Assert(j.ValidateSchema(schema), nil)
+    })
+    gtest.Case(t, func() {
+        j, _ := gjson.LoadContent([]byte(`{
+            "name"    : "J",
+            "age"     : 18.5,
+            "status"  : "unknown",
+            "tags"    : ["a", "a", 1],
+            "address" : {"zip": "610000"}
+        }`))
+        err := j.ValidateSchema(schema)
+        gtest.AssertNE(err, nil)
+        errs := err.(gjson.SchemaErrors)
+        paths := make([]string, len(errs))
+        for i, e := range errs {
+            paths[i] = e.Path + "|" + e.Keyword
+        }
+        gtest.Assert(paths, []string{
+            "address.city|required",
+            "address.zip|additionalProperties",
+            "age|type",
+            "name|minLength",
+            "name|pattern",
+            "status|enum",
+            "tags|maxItems",
+            "tags|uniqueItems",
+            "tags.2|type",
+        })
+    })
+    gtest.Case(t, func() {
+        j := gjson.New([]interface{}{1, "a"})
+        gtest.Assert(j.ValidateSchema(`{"type":"array","items":{"anyOf":[{"type":"integer"},{"type":"string"}]}}`), nil)
+        gtest.AssertNE(j.ValidateSchema(`{"type":"object"}`), nil)
+        gtest.AssertNE(j.ValidateSchema(`{invalid`), nil)
+    })
+}
+
+func Test_Query(t *testing.T) {
+    j, err := gjson.LoadContent([]byte(`{
+        "users"  : [{"name":"john","age":18},{"name":"smith","age":30},{"name":"lily"}],
+        "orders" : [
+            {"id":1, "status":"paid",   "amount":100, "user":{"name":"john"}},
+            {"id":2, "status":"unpaid", "amount":200, "user":{"name":"smith"}},
+            {"id":3, "status":"paid",   "amount":300, "user":{"name":"john"}}
+        ],
+        "map"    : {"b":{"v":2}, "a":{"v":1}},
+        "items"  : [0,1,2,3,4,5],
+        "host:port" : "127.0.0.1:80"
+    }`))
+    gtest.Assert(err, nil)
+    gtest.Case(t, func() {
+        gtest.Assert(j.GetStrings("users.*.name"), []string{"john", "smith", "lily"})
+        gtest.Assert(j.GetInts("users.*.age"), []int{18, 30})
+        gtest.Assert(j.GetInts("map.*.v"), []int{1, 2})
+        gtest.Assert(j.GetInts("orders.#(status=paid).id"), []int{1, 3})
+        gtest.Assert(j.GetInts(`orders.#(status!="paid").id`), []int{2})
+        gtest.Assert(j.GetInts("orders.#(amount>=200).id"), []int{2, 3})
+        gtest.Assert(j.GetInts("orders.#(user.name=john).amount"), []int{100, 300})
+        gtest.Assert(j.GetStrings("users.#(age).name"), []string{"john", "smith"})
+        gtest.Assert(j.GetInts("items.0:2"), []int{0, 1})
+        gtest.Assert(j.GetInts("items.4:"), []int{4, 5})
+        gtest.Assert(j.GetInts("items.:-4"), []int{0, 1})
+        gtest.Assert(j.GetInts("users.1:.age"), []int{30})
+        gtest.Assert(len(j.GetArray("orders.#(status=none)")), 0)
+        // 没有匹配时返回nil
+        gtest.Assert(j.Get("orders.#(status=none)") == nil, true)
+        gtest.Assert(j.Get("users.*.email") == nil, true)
+        // 普通键名优先
+        gtest.Assert(j.GetString("host:port"), "127.0.0.1:80")
+        gtest.Assert(j.GetString("users.0.name"), "john")
+        gtest.Assert(j.Get("users.9.name"), nil)
+    })
+}
+
+// 模拟protoc-gen-go生成的消息类型
+type testProtoUser struct {
+    UserName             string   `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+    Age                  int32    `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+    Tags                 []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+    XXX_NoUnkeyedLiteral struct{} `json:"-"`
+    XXX_sizecache        int32    `json:"-"`
+}
+
+func Test_Proto(t *testing.T) {
+    gtest.Case(t, func() {
+        j, err := gjson.FromProto(&testProtoUser{UserName : "john", Age : 18, Tags : []string{"a"}})
+        gtest.Assert(err, nil)
+        gtest.Assert(j.GetString("user_name"), "john")
+        gtest.Assert(j.GetInt("age"), 18)
+        gtest.Assert(j.Set("age", 20), nil)
+
+        user := new(testProtoUser)
+        gtest.Assert(j.ToProto(user), nil)
+        gtest.Assert(user.UserName, "john")
+        gtest.Assert(user.Age, 20)
+        gtest.Assert(user.Tags, []string{"a"})
+        gtest.AssertNE(j.ToProto(*user), nil)
+    })
+    gtest.Case(t, func() {
+        names := make([]string, 0)
+        gjson.SetProtoCodec(gjson.ProtoCodecFunc {
+            MarshalFunc : func(message interface{}) ([]byte, error) {
+                names = append(names, "marshal")
+                return []byte(`{"userName":"smith"}`), nil
+            },
+            UnmarshalFunc : func(data []byte, message interface{}) error {
+                names = append(names, "unmarshal")
+                message.(*testProtoUser).UserName = gjson.New(data).GetString("userName")
+                return nil
+            },
+        })
+        defer gjson.SetProtoCodec(nil)
+        j, err := gjson.FromProto(&testProtoUser{})
+        gtest.Assert(err, nil)
+        gtest.Assert(j.GetString("userName"), "smith")
+        user := new(testProtoUser)
+        gtest.Assert(j.ToProto(user), nil)
+        gtest.Assert(user.UserName, "smith")
+        gtest.Assert(names, []string{"marshal", "unmarshal"})
+    })
+}
+
+func Test_Ordered(t *testing.T) {
+    gtest.Case(t, func() {
+        j, err := gjson.LoadContentOrdered([]byte(`{"z":1,"b":{"y":1,"x":2},"a":[{"n":1,"m":2}]}`))
+        gtest.Assert(err, nil)
+        gtest.Assert(j.IsOrdered(), true)
+        b, err := j.ToJson()
+        gtest.Assert(err, nil)
+        gtest.Assert(string(b), `{"z":1,"b":{"y":1,"x":2},"a":[{"n":1,"m":2}]}`)
+
+        gtest.Assert(j.Set("c", 3), nil)
+        gtest.Assert(j.Set("b.w", 0), nil)
+        gtest.Assert(j.Remove("z"), nil)
+        type Item struct {
+            Second int
+            First  int
+        }
+        gtest.Assert(j.Set("d", Item{Second : 2, First : 1}), nil)
+        b, _ = j.ToJson()
+        gtest.Assert(string(b), `{"b":{"y":1,"x":2,"w":0},"a":[{"n":1,"m":2}],"c":3,"d":{"Second":2,"First":1}}`)
+
+        b, _ = j.GetJson("b").ToJson()
+        gtest.Assert(string(b), `{"y":1,"x":2,"w":0}`)
+
+        b, _ = j.ToJsonIndent()
+        gtest.Assert(strings.Index(string(b), `"b"`) < strings.Index(string(b), `"a"`), true)
+    })
+    gtest.Case(t, func() {
+        j, err := gjson.NewOrdered(struct {
+            Name string
+            Age  int
+        }{"john", 18})
+        gtest.Assert(err, nil)
+        b, _ := j.ToJson()
+        gtest.Assert(string(b), `{"Name":"john","Age":18}`)
+        gtest.Assert(j.ApplyMergePatch(`{"Email":"a@b.c","Age":null}`), nil)
+        b, _ = j.ToJson()
+        gtest.Assert(string(b), `{"Name":"john","Email":"a@b.c"}`)
+
+        // 非有序模式按照键名排序
+        b, _ = gjson.New(`{"b":1,"a":2}`).ToJson()
+        gtest.Assert(string(b), `{"a":2,"b":1}`)
+    })
+}
+
+type testStatus struct {
+    Code int
+}
+
+func (s *testStatus) UnmarshalValue(value interface{}) error {
+    switch value {
+        case "active":   s.Code = 1
+        case "disabled": s.Code = 2
+        default:
+            return errors.New("invalid status")
+    }
+    return nil
+}
+
+type testLevel int
+
+func Test_ToStruct(t *testing.T) {
+    type Base struct {
+        Id      int
+        Created time.Time `layout:"2006-01-02 15:04"`
+    }
+    type Address struct {
+        City    string `json:"city_name"`
+        ZipCode string
+    }
+    type User struct {
+        Base
+        Name      string
+        Level     testLevel
+        Status    testStatus
+        Birthday  *gtime.Time `format:"Y/m/d"`
+        LoginTime gtime.Time
+        Timeout   time.Duration
+        Address   *Address
+        Addresses []Address
+        Tags      map[string]int
+        Ignored   string `json:"-"`
+    }
+    data := `{
+        "id"        : 1,
+        "created"   : "2019-06-01 12:30",
+        "name"      : "john",
+        "level"     : 3,
+        "status"    : "active",
+        "birthday"  : "1990/02/03",
+        "login_time": 1559361600,
+        "timeout"   : "1m30s",
+        "address"   : {"city_name":"chengdu", "zip_code":"610000", "street":"x"},
+        "addresses" : [{"city_name":"beijing"}, {"city_name":"shanghai"}],
+        "tags"      : {"a":1, "b":2},
+        "ignored"   : "y",
+        "extra"     : true
+    }`
+    gtest.Case(t, func() {
+        j, err := gjson.DecodeToJson([]byte(data))
+        gtest.Assert(err, nil)
+        user := new(User)
+        gtest.Assert(j.ToStruct(user), nil)
+        gtest.Assert(user.Id, 1)
+        gtest.Assert(user.Created.Format("2006-01-02 15:04"), "2019-06-01 12:30")
+        gtest.Assert(user.Name, "john")
+        gtest.Assert(user.Level, testLevel(3))
+        gtest.Assert(user.Status.Code, 1)
+        gtest.Assert(user.Birthday.Format("Y-m-d"), "1990-02-03")
+        gtest.Assert(user.LoginTime.Second(), 1559361600)
+        gtest.Assert(user.Timeout, 90*time.Second)
+        gtest.Assert(user.Address.City, "chengdu")
+        gtest.Assert(user.Address.ZipCode, "610000")
+        gtest.Assert(len(user.Addresses), 2)
+        gtest.Assert(user.Addresses[1].City, "shanghai")
+        gtest.Assert(user.Tags["b"], 2)
+        gtest.Assert(user.Ignored, "")
+
+        err = j.ToStructStrict(new(User))
+        gtest.AssertNE(err, nil)
+        unmapped, ok := err.(*gjson.UnmappedError)
+        gtest.Assert(ok, true)
+        gtest.Assert(unmapped.Fields, []string{"address.street", "extra", "ignored"})
+
+        address := new(Address)
+        gtest.Assert(j.GetToStructStrict("addresses.0", address), nil)
+        gtest.Assert(address.City, "beijing")
+    })
+    gtest.Case(t, func() {
+        type Item struct {
+            Status testStatus
+            Level  testLevel
+        }
+        // 自定义转换器及错误路径
+        gjson.RegisterConverter(testLevel(0), func(value interface{}) (interface{}, error) {
+            if value == "high" {
+                return testLevel(9), nil
+            }
+            return testLevel(0), nil
+        })
+        defer gjson.RegisterConverter(testLevel(0), nil)
+        item := new(Item)
+        gtest.Assert(gjson.New(`{"level":"high"}`).ToStruct(item), nil)
+        gtest.Assert(item.Level, testLevel(9))
+
+        err := gjson.New(`{"items":[{"status":"unknown"}]}`).GetToStruct("items", &[]Item{})
+        gtest.AssertNE(err, nil)
+        gtest.Assert(err.Error(), "0.status: invalid status")
+    })
+}
+
+func Test_Compare(t *testing.T) {
+    gtest.Case(t, func() {
+        a, _ := gjson.DecodeToJson([]byte(`{"name":"gf","debug":true,"redis":{"db":1,"addr":"127.0.0.1"},"hosts":["a","b"],"a.b":1}`))
+        b, _ := gjson.DecodeToJson([]byte(`{"name":"gf","redis":{"db":2.0,"addr":"127.0.0.1","pass":"x"},"hosts":["a","c","d"],"a.b":{"c":1}}`))
+        c    := gjson.Compare(a, b)
+        gtest.Assert(c.Equal(), false)
+        gtest.Assert(len(c.Added),   2)
+        gtest.Assert(c.Added[0].Path, "hosts.2")
+        gtest.Assert(c.Added[1].Path, "redis.pass")
+        gtest.Assert(c.Added[1].New,  "x")
+        gtest.Assert(len(c.Removed), 1)
+        gtest.Assert(c.Removed[0].Path, "debug")
+        gtest.Assert(c.Removed[0].Old,  true)
+        gtest.Assert(len(c.Changed), 3)
+        gtest.Assert(c.Changed[0].Pointer, "/a.b")
+        gtest.Assert(c.Changed[1].Path, "hosts.1")
+        gtest.Assert(c.Changed[2].Path, "redis.db")
+        gtest.Assert(c.String(), "~ a.b: 1 => {\"c\":1}\n- debug: true\n~ hosts.1: \"b\" => \"c\"\n+ hosts.2: \"d\"\n~ redis.db: 1 => 2\n+ redis.pass: \"x\"\n")
+        // 应用补丁后与b一致
+        gtest.Assert(a.ApplyPatch(c.Patch()), nil)
+        gtest.Assert(gjson.Compare(a, b).Equal(), true)
+        p, err := c.PatchJson()
+        gtest.Assert(err, nil)
+        gtest.Assert(strings.Contains(string(p), `"op":"remove","path":"/debug"`), true)
+        // 数值类型不同但值相等
+        gtest.Assert(gjson.Compare(gjson.New(map[string]interface{}{"n" : 1}), gjson.New(map[string]interface{}{"n" : 1.0})).Equal(), true)
+        // 根节点类型不同
+        a, _  = gjson.DecodeToJson([]byte(`{"a":1}`))
+        c     = gjson.Compare(a, nil)
+        gtest.Assert(c.Changed[0].Path, "")
+        gtest.Assert(c.String(), "~ (root): {\"a\":1} => null\n")
+    })
+}
+
+func Test_DetectDataType(t *testing.T) {
+    gtest.Case(t, func() {
+        for content, dataType := range map[string]string {
+            "\xef\xbb\xbf{\"a\":1}"    : "json",
+            "[1, 2]"                  : "json",
+            "<a><b>1</b></a>"         : "xml",
+            "a: 1\nb:\n  - x\n"       : "yaml",
+            "- 1\n- 2\n"              : "yaml",
+            "a = 1\n[b]\nc = \"d\"\n" : "toml",
+            "[b]\nc = d e\n"          : "ini",
+            "a = hello world\n"       : "ini",
+        } {
+            result, err := gjson.DetectDataType([]byte(content))
+            gtest.Assert(err, nil)
+            gtest.Assert(result, dataType)
+        }
+        _, err := gjson.DetectDataType([]byte("<a>"))
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_LoadContent_UnknownType(t *testing.T) {
+    gtest.Case(t, func() {
+        // 未知或者为空的数据格式按照内容自动识别，无法识别时使用json解析
+        for _, dataType := range []string{"", "txt", ".conf"} {
+            j, err := gjson.LoadContent([]byte(`{"a":{"b":1}}`), dataType)
+            gtest.Assert(err, nil)
+            gtest.Assert(j.GetInt("a.b"), 1)
+        }
+        j, err := gjson.LoadContent([]byte("a:\n  b: 2\n"), "conf")
+        gtest.Assert(err, nil)
+        gtest.Assert(j.GetInt("a.b"), 2)
+        _, err  = gjson.LoadContent([]byte(`{"a":`), "txt")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_Lazy(t *testing.T) {
+    data := `{"name":"gf","a\"b":1,"s":"{[\"]}","users":[{"name":"john","age":18},{"name":"smith","tags":[]}],"meta":{"total":2,"empty":{}},"n":null}`
+    gtest.Case(t, func() {
+        l, err := gjson.LoadContentLazy([]byte(data))
+        gtest.Assert(err, nil)
+        gtest.Assert(l.GetString("name"),         "gf")
+        gtest.Assert(l.GetInt(`a"b`),             1)
+        gtest.Assert(l.GetString("s"),            `{["]}`)
+        gtest.Assert(l.GetString("users.1.name"), "smith")
+        gtest.Assert(l.GetInt("users.0.age"),     18)
+        gtest.Assert(l.Get("users.2"),            nil)
+        gtest.Assert(l.Get("name.x"),             nil)
+        gtest.Assert(l.Get("n"),                  nil)
+        gtest.Assert(l.Contains("n"),             true)
+        gtest.Assert(l.Contains("x"),             false)
+        gtest.Assert(l.Len("users"),              2)
+        gtest.Assert(l.Len("users.1.tags"),       0)
+        gtest.Assert(l.Len("meta.empty"),         0)
+        gtest.Assert(l.Len("name"),               -1)
+        gtest.Assert(string(l.GetRaw("meta")),    `{"total":2,"empty":{}}`)
+        gtest.Assert(l.GetMap("meta")["total"],   2)
+        gtest.Assert(len(l.GetArray("users")),    2)
+        user := new(struct{ Name string; Age int })
+        gtest.Assert(l.GetToStruct("users.0", user), nil)
+        gtest.Assert(user.Name, "john")
+        gtest.Assert(user.Age,  18)
+        // 转换后的Json对象可以修改，不影响原有对象
+        j := l.GetJson("users.0")
+        gtest.Assert(j.Set("name", "lily"), nil)
+        gtest.Assert(l.GetString("users.0.name"), "john")
+        gtest.Assert(l.Json().GetString("meta.total"), "2")
+        l.SetSplitChar('/')
+        gtest.Assert(l.GetString("users/1/name"), "smith")
+        // 非法内容
+        _, err = gjson.LoadContentLazy([]byte(`{"a":`))
+        gtest.AssertNE(err, nil)
+    })
+    // 只读取少量字段时的内存分配远少于完整解析
+    gtest.Case(t, func() {
+        buffer := make([]string, 0)
+        for i := 0; i < 1000; i++ {
+            buffer = append(buffer, `{"id":` + strconv.Itoa(i) + `,"name":"user","tags":["a","b"]}`)
+        }
+        content := []byte(`{"items":[` + strings.Join(buffer, ",") + `],"total":1000}`)
+        lazy    := testing.AllocsPerRun(10, func() {
+            l, _ := gjson.LoadContentLazy(content)
+            l.GetInt("total")
+        })
+        full    := testing.AllocsPerRun(10, func() {
+            j, _ := gjson.DecodeToJson(content)
+            j.GetInt("total")
+        })
+        gtest.AssertLT(lazy * 100, full)
+    })
+}
@@ -0,0 +1,456 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gmsgpack provides accessing and converting for MessagePack content.
+package gmsgpack
+
+import (
+    "bytes"
+    "encoding/binary"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "reflect"
+    "sort"
+    "time"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 时间戳扩展类型(MessagePack规范定义)
+const extTypeTimestamp = -1
+
+// 将变量编码为MessagePack内容，struct将按照gconv.Map的规则转换为map进行编码
+func Encode(v interface{}) ([]byte, error) {
+    buffer := bytes.NewBuffer(nil)
+    if err := encodeValue(buffer, reflect.ValueOf(v)); err != nil {
+        return nil, err
+    }
+    return buffer.Bytes(), nil
+}
+
+// 解码MessagePack内容，map解码为map[string]interface{}，数组解码为[]interface{}，
+// 整数解码为int64/uint64，浮点数解码为float64，bin类型解码为[]byte，时间戳扩展类型解码为time.Time
+func Decode(v []byte) (interface{}, error) {
+    d := &decoder{data : v}
+    result, err := d.decode()
+    if err != nil {
+        return nil, err
+    }
+    if d.pos != len(v) {
+        return nil, errors.New(fmt.Sprintf("msgpack: %d bytes remaining after decoding", len(v) - d.pos))
+    }
+    return result, nil
+}
+
+// 解码MessagePack内容到指定变量，注意参数应当是一个变量的指针
+func DecodeTo(v []byte, result interface{}) error {
+    content, err := ToJson(v)
+    if err != nil {
+        return err
+    }
+    return json.Unmarshal(content, result)
+}
+
+// 将MessagePack内容转换为JSON内容，bin类型的值转换为字符串
+func ToJson(v []byte) ([]byte, error) {
+    if r, err := Decode(v); err != nil {
+        return nil, err
+    } else {
+        return json.Marshal(convertBinary(r))
+    }
+}
+
+// 将[]byte转换为string，以便JSON编码时保持原有内容
+func convertBinary(value interface{}) interface{} {
+    switch v := value.(type) {
+        case []byte:
+            return string(v)
+        case map[string]interface{}:
+            for k, item := range v {
+                v[k] = convertBinary(item)
+            }
+        case []interface{}:
+            for i, item := range v {
+                v[i] = convertBinary(item)
+            }
+    }
+    return value
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// 编码单个值
+func encodeValue(buffer *bytes.Buffer, rv reflect.Value) error {
+    if !rv.IsValid() {
+        buffer.WriteByte(0xc0)
+        return nil
+    }
+    switch rv.Kind() {
+        case reflect.Ptr, reflect.Interface:
+            if rv.IsNil() {
+                buffer.WriteByte(0xc0)
+                return nil
+            }
+            return encodeValue(buffer, rv.Elem())
+    }
+    if rv.Type() == timeType {
+        encodeTime(buffer, rv.Interface().(time.Time))
+        return nil
+    }
+    switch rv.Kind() {
+        case reflect.Bool:
+            if rv.Bool() {
+                buffer.WriteByte(0xc3)
+            } else {
+                buffer.WriteByte(0xc2)
+            }
+
+        case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+            encodeInt(buffer, rv.Int())
+
+        case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+            encodeUint(buffer, rv.Uint())
+
+        case reflect.Float32:
+            buffer.WriteByte(0xca)
+            binary.Write(buffer, binary.BigEndian, math.Float32bits(float32(rv.Float())))
+
+        case reflect.Float64:
+            buffer.WriteByte(0xcb)
+            binary.Write(buffer, binary.BigEndian, math.Float64bits(rv.Float()))
+
+        case reflect.String:
+            encodeString(buffer, rv.String())
+
+        case reflect.Slice, reflect.Array:
+            if rv.Kind() == reflect.Slice && rv.IsNil() {
+                buffer.WriteByte(0xc0)
+                return nil
+            }
+            if rv.Type().Elem().Kind() == reflect.Uint8 {
+                b := make([]byte, rv.Len())
+                reflect.Copy(reflect.ValueOf(b), rv)
+                encodeBinary(buffer, b)
+                return nil
+            }
+            encodeLength(buffer, rv.Len(), 0x90, 0x0f, 0xdc, 0xdd)
+            for i := 0; i < rv.Len(); i++ {
+                if err := encodeValue(buffer, rv.Index(i)); err != nil {
+                    return err
+                }
+            }
+
+        case reflect.Map:
+            if rv.IsNil() {
+                buffer.WriteByte(0xc0)
+                return nil
+            }
+            keys := rv.MapKeys()
+            // 按照键名排序，保证编码结果稳定
+            sort.Slice(keys, func(i, j int) bool {
+                return gconv.String(keys[i].Interface()) < gconv.String(keys[j].Interface())
+            })
+            encodeLength(buffer, len(keys), 0x80, 0x0f, 0xde, 0xdf)
+            for _, k := range keys {
+                if err := encodeValue(buffer, k); err != nil {
+                    return err
+                }
+                if err := encodeValue(buffer, rv.MapIndex(k)); err != nil {
+                    return err
+                }
+            }
+
+        case reflect.Struct:
+            return encodeValue(buffer, reflect.ValueOf(gconv.Map(rv.Interface())))
+
+        default:
+            return errors.New(fmt.Sprintf("msgpack: unsupported type %s", rv.Type().String()))
+    }
+    return nil
+}
+
+func encodeInt(buffer *bytes.Buffer, n int64) {
+    switch {
+        case n >= 0:
+            encodeUint(buffer, uint64(n))
+        case n >= -32:
+            buffer.WriteByte(byte(n))
+        case n >= math.MinInt8:
+            buffer.WriteByte(0xd0)
+            buffer.WriteByte(byte(n))
+        case n >= math.MinInt16:
+            buffer.WriteByte(0xd1)
+            binary.Write(buffer, binary.BigEndian, int16(n))
+        case n >= math.MinInt32:
+            buffer.WriteByte(0xd2)
+            binary.Write(buffer, binary.BigEndian, int32(n))
+        default:
+            buffer.WriteByte(0xd3)
+            binary.Write(buffer, binary.BigEndian, n)
+    }
+}
+
+func encodeUint(buffer *bytes.Buffer, n uint64) {
+    switch {
+        case n <= 0x7f:
+            buffer.WriteByte(byte(n))
+        case n <= math.MaxUint8:
+            buffer.WriteByte(0xcc)
+            buffer.WriteByte(byte(n))
+        case n <= math.MaxUint16:
+            buffer.WriteByte(0xcd)
+            binary.Write(buffer, binary.BigEndian, uint16(n))
+        case n <= math.MaxUint32:
+            buffer.WriteByte(0xce)
+            binary.Write(buffer, binary.BigEndian, uint32(n))
+        default:
+            buffer.WriteByte(0xcf)
+            binary.Write(buffer, binary.BigEndian, n)
+    }
+}
+
+func encodeString(buffer *bytes.Buffer, s string) {
+    switch n := len(s); {
+        case n <= 31:
+            buffer.WriteByte(0xa0 | byte(n))
+        case n <= math.MaxUint8:
+            buffer.WriteByte(0xd9)
+            buffer.WriteByte(byte(n))
+        case n <= math.MaxUint16:
+            buffer.WriteByte(0xda)
+            binary.Write(buffer, binary.BigEndian, uint16(n))
+        default:
+            buffer.WriteByte(0xdb)
+            binary.Write(buffer, binary.BigEndian, uint32(n))
+    }
+    buffer.WriteString(s)
+}
+
+func encodeBinary(buffer *bytes.Buffer, b []byte) {
+    switch n := len(b); {
+        case n <= math.MaxUint8:
+            buffer.WriteByte(0xc4)
+            buffer.WriteByte(byte(n))
+        case n <= math.MaxUint16:
+            buffer.WriteByte(0xc5)
+            binary.Write(buffer, binary.BigEndian, uint16(n))
+        default:
+            buffer.WriteByte(0xc6)
+            binary.Write(buffer, binary.BigEndian, uint32(n))
+    }
+    buffer.Write(b)
+}
+
+// 编码数组/map的长度，fix为fix格式的前缀，fixMax为fix格式的最大长度
+func encodeLength(buffer *bytes.Buffer, n int, fix byte, fixMax int, code16, code32 byte) {
+    switch {
+        case n <= fixMax:
+            buffer.WriteByte(fix | byte(n))
+        case n <= math.MaxUint16:
+            buffer.WriteByte(code16)
+            binary.Write(buffer, binary.BigEndian, uint16(n))
+        default:
+            buffer.WriteByte(code32)
+            binary.Write(buffer, binary.BigEndian, uint32(n))
+    }
+}
+
+// 按照时间戳扩展类型编码时间(timestamp 96格式)
+func encodeTime(buffer *bytes.Buffer, t time.Time) {
+    buffer.WriteByte(0xc7)
+    buffer.WriteByte(12)
+    buffer.WriteByte(0xff) // extTypeTimestamp
+    binary.Write(buffer, binary.BigEndian, uint32(t.Nanosecond()))
+    binary.Write(buffer, binary.BigEndian, t.Unix())
+}
+
+// MessagePack解码器
+type decoder struct {
+    data []byte
+    pos  int
+}
+
+// 读取指定长度的内容
+func (d *decoder) read(n int) ([]byte, error) {
+    if n < 0 || d.pos + n > len(d.data) {
+        return nil, errors.New("msgpack: unexpected end of data")
+    }
+    b := d.data[d.pos : d.pos + n]
+    d.pos += n
+    return b, nil
+}
+
+// 读取n字节的大端无符号整数
+func (d *decoder) readUint(n int) (uint64, error) {
+    b, err := d.read(n)
+    if err != nil {
+        return 0, err
+    }
+    v := uint64(0)
+    for _, c := range b {
+        v = v << 8 | uint64(c)
+    }
+    return v, nil
+}
+
+// 解码单个值
+func (d *decoder) decode() (interface{}, error) {
+    b, err := d.read(1)
+    if err != nil {
+        return nil, err
+    }
+    c := b[0]
+    switch {
+        case c <= 0x7f:
+            return int64(c), nil
+        case c >= 0xe0:
+            return int64(int8(c)), nil
+        case c & 0xf0 == 0x80:
+            return d.decodeMap(int(c & 0x0f))
+        case c & 0xf0 == 0x90:
+            return d.decodeArray(int(c & 0x0f))
+        case c & 0xe0 == 0xa0:
+            return d.decodeString(int(c & 0x1f))
+    }
+    switch c {
+        case 0xc0: return nil, nil
+        case 0xc2: return false, nil
+        case 0xc3: return true, nil
+        case 0xc4, 0xc5, 0xc6:
+            n, err := d.readUint(1 << (c - 0xc4))
+            if err != nil {
+                return nil, err
+            }
+            b, err := d.read(int(n))
+            if err != nil {
+                return nil, err
+            }
+            return append([]byte(nil), b...), nil
+        case 0xc7, 0xc8, 0xc9:
+            n, err := d.readUint(1 << (c - 0xc7))
+            if err != nil {
+                return nil, err
+            }
+            return d.decodeExt(int(n))
+        case 0xca:
+            n, err := d.readUint(4)
+            return float64(math.Float32frombits(uint32(n))), err
+        case 0xcb:
+            n, err := d.readUint(8)
+            return math.Float64frombits(n), err
+        case 0xcc, 0xcd, 0xce, 0xcf:
+            n, err := d.readUint(1 << (c - 0xcc))
+            if err != nil {
+                return nil, err
+            }
+            if n <= math.MaxInt64 {
+                return int64(n), nil
+            }
+            return n, nil
+        case 0xd0:
+            n, err := d.readUint(1)
+            return int64(int8(n)), err
+        case 0xd1:
+            n, err := d.readUint(2)
+            return int64(int16(n)), err
+        case 0xd2:
+            n, err := d.readUint(4)
+            return int64(int32(n)), err
+        case 0xd3:
+            n, err := d.readUint(8)
+            return int64(n), err
+        case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+            return d.decodeExt(1 << (c - 0xd4))
+        case 0xd9, 0xda, 0xdb:
+            n, err := d.readUint(1 << (c - 0xd9))
+            if err != nil {
+                return nil, err
+            }
+            return d.decodeString(int(n))
+        case 0xdc, 0xdd:
+            n, err := d.readUint(2 << (c - 0xdc))
+            if err != nil {
+                return nil, err
+            }
+            return d.decodeArray(int(n))
+        case 0xde, 0xdf:
+            n, err := d.readUint(2 << (c - 0xde))
+            if err != nil {
+                return nil, err
+            }
+            return d.decodeMap(int(n))
+    }
+    return nil, errors.New(fmt.Sprintf("msgpack: invalid code 0x%x at position %d", c, d.pos - 1))
+}
+
+func (d *decoder) decodeString(n int) (interface{}, error) {
+    b, err := d.read(n)
+    if err != nil {
+        return nil, err
+    }
+    return string(b), nil
+}
+
+func (d *decoder) decodeArray(n int) (interface{}, error) {
+    if n > len(d.data) - d.pos {
+        return nil, errors.New("msgpack: unexpected end of data")
+    }
+    array := make([]interface{}, n)
+    for i := 0; i < n; i++ {
+        v, err := d.decode()
+        if err != nil {
+            return nil, err
+        }
+        array[i] = v
+    }
+    return array, nil
+}
+
+func (d *decoder) decodeMap(n int) (interface{}, error) {
+    if n > len(d.data) - d.pos {
+        return nil, errors.New("msgpack: unexpected end of data")
+    }
+    m := make(map[string]interface{}, n)
+    for i := 0; i < n; i++ {
+        k, err := d.decode()
+        if err != nil {
+            return nil, err
+        }
+        v, err := d.decode()
+        if err != nil {
+            return nil, err
+        }
+        m[gconv.String(k)] = v
+    }
+    return m, nil
+}
+
+// 解码扩展类型，目前仅支持时间戳扩展类型，其他扩展类型返回原始内容
+func (d *decoder) decodeExt(n int) (interface{}, error) {
+    b, err := d.read(1)
+    if err != nil {
+        return nil, err
+    }
+    t := int8(b[0])
+    data, err := d.read(n)
+    if err != nil {
+        return nil, err
+    }
+    if t != extTypeTimestamp {
+        return append([]byte(nil), data...), nil
+    }
+    switch n {
+        case 4:
+            return time.Unix(int64(binary.BigEndian.Uint32(data)), 0), nil
+        case 8:
+            v := binary.BigEndian.Uint64(data)
+            return time.Unix(int64(v & 0x3ffffffff), int64(v >> 34)), nil
+        case 12:
+            return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data[:4]))), nil
+    }
+    return nil, errors.New(fmt.Sprintf("msgpack: invalid timestamp length %d", n))
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gmsgpack_test
+
+import (
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/encoding/gmsgpack"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func Test_EncodeDecode(t *testing.T) {
+    gtest.Case(t, func() {
+        // 与其他实现一致的编码结果: {"a":1,"b":[true,null,"x"]}
+        b, err := gmsgpack.Encode(map[string]interface{}{"a" : 1, "b" : []interface{}{true, nil, "x"}})
+        gtest.Assert(err, nil)
+        gtest.Assert(b, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x93, 0xc3, 0xc0, 0xa1, 'x'})
+
+        values := []interface{}{
+            int64(0), int64(-1), int64(-33), int64(200), int64(-200), int64(70000), int64(-70000),
+            int64(1) << 40, -(int64(1) << 40), uint64(1) << 63, 1.5, "", "hello",
+            string(make([]byte, 300)), []byte{1, 2, 3}, false,
+        }
+        for _, v := range values {
+            b, err := gmsgpack.Encode(v)
+            gtest.Assert(err, nil)
+            r, err := gmsgpack.Decode(b)
+            gtest.Assert(err, nil)
+            gtest.Assert(r, v)
+        }
+
+        now := time.Unix(1546300800, 123)
+        b, err = gmsgpack.Encode(now)
+        gtest.Assert(err, nil)
+        r, err := gmsgpack.Decode(b)
+        gtest.Assert(err, nil)
+        gtest.Assert(r.(time.Time).Equal(now), true)
+
+        _, err = gmsgpack.Decode([]byte{0x92, 0x01})
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_DecodeTo(t *testing.T) {
+    gtest.Case(t, func() {
+        type User struct {
+            Name string
+            Age  int
+            Tags []string
+        }
+        b, err := gmsgpack.Encode(User{Name : "john", Age : 18, Tags : []string{"a"}})
+        gtest.Assert(err, nil)
+        user := new(User)
+        gtest.Assert(gmsgpack.DecodeTo(b, user), nil)
+        gtest.Assert(user.Name, "john")
+        gtest.Assert(user.Age,  18)
+        gtest.Assert(user.Tags, []string{"a"})
+    })
+}
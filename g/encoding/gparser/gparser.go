@@ -38,7 +38,7 @@ func Load (path string) (*Parser, error) {
     }
 }
 
-// 支持的数据内容格式：json(默认), xml, yaml/yml, toml
+// 支持的数据内容格式：json(默认), xml, yaml/yml, toml, ini, properties, msgpack
 func LoadContent (data []byte, dataType...string) (*Parser, error) {
     if j, e := gjson.LoadContent(data, dataType...); e == nil {
         return &Parser{j}, nil
@@ -47,6 +47,72 @@ func LoadContent (data []byte, dataType...string) (*Parser, error) {
     }
 }
 
+// 同Load，但是JSON内容使用宽松模式解析，允许注释及末尾多余的逗号
+func LoadLenient (path string) (*Parser, error) {
+    if j, e := gjson.LoadLenient(path); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 同LoadContent，但是JSON内容使用宽松模式解析，允许注释及末尾多余的逗号
+func LoadContentLenient (data []byte, dataType...string) (*Parser, error) {
+    if j, e := gjson.LoadContentLenient(data, dataType...); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 自动检测数据内容的格式(json, xml, yaml, toml, ini)并解析，参考gjson.DetectDataType
+func LoadContentAuto (data []byte) (*Parser, error) {
+    if j, e := gjson.LoadContentAuto(data); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 同Load，JSON内容使用有序模式解析，对象键名的顺序在修改及导出时保持不变，参考gjson.NewOrdered
+func LoadOrdered (path string) (*Parser, error) {
+    if j, e := gjson.LoadOrdered(path); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 同LoadContent，JSON内容使用有序模式解析，参考gjson.NewOrdered
+func LoadContentOrdered (data []byte, dataType...string) (*Parser, error) {
+    if j, e := gjson.LoadContentOrdered(data, dataType...); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 将protobuf消息对象转换为Parser对象，参考gjson.FromProto
+func FromProto (message interface{}) (*Parser, error) {
+    if j, e := gjson.FromProto(message); e == nil {
+        return &Parser{j}, nil
+    } else {
+        return nil, e
+    }
+}
+
+// 对比两个文档的结构差异，参考gjson.Compare
+func Compare (a, b *Parser) *gjson.Comparison {
+    var ja, jb *gjson.Json
+    if a != nil {
+        ja = a.json
+    }
+    if b != nil {
+        jb = b.json
+    }
+    return gjson.Compare(ja, jb)
+}
+
 // 设置自定义的层级分隔符号
 func (p *Parser) SetSplitChar(char byte) {
     p.json.SetSplitChar(char)
@@ -163,6 +229,11 @@ func (p *Parser) GetToStruct(pattern string, objPointer interface{}) error {
     return p.json.GetToStruct(pattern, objPointer)
 }
 
+// 同GetToStruct，数据中存在未映射到struct属性的键名时返回*gjson.UnmappedError
+func (p *Parser) GetToStructStrict(pattern string, objPointer interface{}) error {
+    return p.json.GetToStructStrict(pattern, objPointer)
+}
+
 // 根据pattern查找并设置数据
 // 注意：写入的时候"."符号只能表示层级，不能使用带"."符号的键名
 func (p *Parser) Set(pattern string, value interface{}) error {
@@ -227,6 +298,33 @@ func (p *Parser) ToToml() ([]byte, error) {
     return p.json.ToToml()
 }
 
+func (p *Parser) ToIni() ([]byte, error) {
+    return p.json.ToIni()
+}
+
+func (p *Parser) ToProperties() ([]byte, error) {
+    return p.json.ToProperties()
+}
+
+func (p *Parser) ToMsgPack() ([]byte, error) {
+    return p.json.ToMsgPack()
+}
+
+// 按照指定的数据格式(json, xml, yaml/yml, toml, ini, properties, msgpack，同时支持文件扩展名格式，例如: .yaml)导出内容
+func (p *Parser) ToFormat(dataType string) ([]byte, error) {
+    return p.json.ToFormat(dataType)
+}
+
+// 将内容保存到指定文件，导出格式根据文件扩展名确定
+func (p *Parser) Save(path string) error {
+    return p.json.Save(path)
+}
+
+// 将内容转换为protobuf消息对象，注意参数应当是消息对象的指针
+func (p *Parser) ToProto(message interface{}) error {
+    return p.json.ToProto(message)
+}
+
 // 打印Json对象
 func (p *Parser) Dump() error {
     return p.json.Dump()
@@ -237,6 +335,11 @@ func (p *Parser) ToStruct(o interface{}) error {
     return p.json.ToStruct(o)
 }
 
+// 同ToStruct，数据中存在未映射到struct属性的键名时返回*gjson.UnmappedError
+func (p *Parser) ToStructStrict(o interface{}) error {
+    return p.json.ToStructStrict(o)
+}
+
 func VarToXml(value interface{}, rootTag...string) ([]byte, error) {
     return New(value).ToXml(rootTag...)
 }
@@ -261,6 +364,22 @@ func VarToToml(value interface{}) ([]byte, error) {
     return New(value).ToToml()
 }
 
+func VarToIni(value interface{}) ([]byte, error) {
+    return New(value).ToIni()
+}
+
+func VarToProperties(value interface{}) ([]byte, error) {
+    return New(value).ToProperties()
+}
+
+func VarToMsgPack(value interface{}) ([]byte, error) {
+    return New(value).ToMsgPack()
+}
+
+func VarToFormat(value interface{}, dataType string) ([]byte, error) {
+    return New(value).ToFormat(dataType)
+}
+
 // 将变量解析为对应的struct对象，注意传递的参数为struct对象指针
 func VarToStruct(value interface{}, obj interface{}) error {
     return New(value).ToStruct(obj)
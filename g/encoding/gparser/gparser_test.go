@@ -221,3 +221,151 @@ func Test_Set13(t *testing.T) {
 
 
 
+
+func Test_LoadContent_Format(t *testing.T) {
+    toml := []byte(`
+# 注释: 不影响格式识别
+title = "gf"
+[redis]
+    addr = "127.0.0.1:6379" # 行尾注释
+    db   = 1
+`)
+    yaml := []byte(`
+# 注释 = 不影响格式识别
+title: gf
+redis:
+  addr: 127.0.0.1:6379 # 行尾注释
+  db: 1
+`)
+    for _, data := range [][]byte{toml, yaml} {
+        p, err := gparser.LoadContent(data)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if p.GetString("redis.addr") != "127.0.0.1:6379" || p.GetInt("redis.db") != 1 {
+            t.Error("unexpected content:", p.Get())
+        }
+        // 导出后重新解析，内容应当保持一致
+        for _, dataType := range []string{"json", ".yml", "yaml", "toml"} {
+            c, err := p.ToFormat(dataType)
+            if err != nil {
+                t.Fatal(err)
+            }
+            p2, err := gparser.LoadContent(c, dataType)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if p2.GetString("title") != "gf" || p2.GetString("redis.addr") != "127.0.0.1:6379" || p2.GetInt("redis.db") != 1 {
+                t.Error("round trip failed:", dataType, string(c))
+            }
+        }
+    }
+    if c, err := gparser.VarToFormat(map[string]interface{}{"n" : 1}, "toml"); err != nil || string(c) != "n = 1\n" {
+        t.Error("unexpected toml:", string(c), err)
+    }
+    // 无法识别的数据格式按照内容自动识别
+    if p, err := gparser.LoadContent(toml, "csv"); err != nil || p.GetInt("redis.db") != 1 {
+        t.Error("unknown data type should fall back to content detection:", err)
+    }
+}
+
+func Test_LoadContentLenient(t *testing.T) {
+    data := []byte(`
+// 服务配置
+{
+    "name"  : "gf", /* 名称 */
+    "url"   : "http://goframe.org/*path*/",
+    "ports" : [80, 443,],
+    "quote" : "a\"//b",
+}
+`)
+    if _, err := gparser.LoadContent(data, "json"); err == nil {
+        t.Error("strict mode should fail")
+    }
+    p, err := gparser.LoadContentLenient(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p.GetString("name") != "gf" || p.GetString("url") != "http://goframe.org/*path*/" || p.GetString("quote") != `a"//b` {
+        t.Error("unexpected content:", p.Get())
+    }
+    if ports := p.GetInts("ports"); len(ports) != 2 || ports[1] != 443 {
+        t.Error("unexpected ports:", ports)
+    }
+    // 非JSON内容与LoadContent一致
+    p, err = gparser.LoadContentLenient([]byte("# comment\nname = \"gf\"\n"))
+    if err != nil || p.GetString("name") != "gf" {
+        t.Error("unexpected toml content:", err)
+    }
+}
+
+func Test_MsgPack(t *testing.T) {
+    p := gparser.New(map[string]interface{}{"name" : "gf", "ports" : []int{80, 443}})
+    b, err := p.ToMsgPack()
+    if err != nil {
+        t.Fatal(err)
+    }
+    p2, err := gparser.LoadContent(b, "msgpack")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p2.GetString("name") != "gf" || p2.GetInt("ports.1") != 443 {
+        t.Error("unexpected content:", p2.Get())
+    }
+}
+
+func Test_IniProperties(t *testing.T) {
+    p, err := gparser.LoadContent([]byte("title = gf\n[redis]\naddr = 127.0.0.1:6379\ndb = 1\n"), "ini")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p.GetString("title") != "gf" || p.GetInt("redis.db") != 1 {
+        t.Error("unexpected content:", p.Get())
+    }
+    // 导出后重新解析，内容应当保持一致
+    for _, dataType := range []string{"ini", ".properties", "props"} {
+        c, err := p.ToFormat(dataType)
+        if err != nil {
+            t.Fatal(err)
+        }
+        p2, err := gparser.LoadContent(c, dataType)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if p2.GetString("redis.addr") != "127.0.0.1:6379" || p2.GetInt("redis.db") != 1 {
+            t.Error("round trip failed:", dataType, string(c))
+        }
+    }
+    value := map[string]interface{}{"redis" : map[string]interface{}{"db" : 1}}
+    if c, err := gparser.VarToProperties(value); err != nil || string(c) != "redis.db=1\n" {
+        t.Error("unexpected properties:", string(c), err)
+    }
+    if c, err := gparser.VarToIni(value); err != nil || string(c) != "[redis]\ndb = 1\n" {
+        t.Error("unexpected ini:", string(c), err)
+    }
+}
+
+func Test_LoadContentAuto(t *testing.T) {
+    contents := map[string]string {
+        "json" : `{"redis":{"addr":"127.0.0.1:6379","db":1}}`,
+        "xml"  : `<?xml version="1.0"?><redis><addr>127.0.0.1:6379</addr><db>1</db></redis>`,
+        "yaml" : "# comment\nredis:\n  addr: 127.0.0.1:6379\n  db: 1\n",
+        "toml" : "[redis]\naddr = \"127.0.0.1:6379\"\ndb = 1\n",
+        "ini"  : "; comment\n[redis]\naddr = 127.0.0.1:6379\ndb = 1\n",
+    }
+    for dataType, content := range contents {
+        p, err := gparser.LoadContentAuto([]byte(content))
+        if err != nil {
+            t.Fatal(dataType, err)
+        }
+        if p.GetString("redis.addr") != "127.0.0.1:6379" || p.GetInt("redis.db") != 1 {
+            t.Error("unexpected content:", dataType, p.Get())
+        }
+    }
+    if _, err := gparser.LoadContentAuto([]byte("just some text")); err == nil {
+        t.Error("plain text should not be detected")
+    }
+    if _, err := gparser.LoadContentAuto([]byte("  ")); err == nil {
+        t.Error("empty content should not be detected")
+    }
+}
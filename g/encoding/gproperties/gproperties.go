@@ -0,0 +1,288 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gproperties provides accessing and converting for Java properties content.
+//
+// 解析规则参考java.util.Properties: # 及 ! 开头的行为注释，键值分隔符为 = : 或者空白，
+// 行尾的 \ 表示续行，支持 \t \n \r \f \uXXXX 等转义(内容使用UTF-8编码)。
+// 解析时键名按照层级分隔符(默认为".")展开为嵌套的map，例如: redis.addr=127.0.0.1 解析为 {"redis": {"addr": "127.0.0.1"}}，
+// 当同一个键名既有值又有子键时(例如: a=1 及 a.b=2)，子键保持原有的键名(a.b)存放在上一级中；
+// 编码时嵌套的map及数组使用分隔符展开为扁平的键名，数组的键名为元素索引，例如: hosts.0=a。
+package gproperties
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 默认的层级分隔符
+const gDEFAULT_SEPARATOR = "."
+
+// 将变量编码为properties内容，v应当为map或者struct(按照gconv.Map的规则转换)，键名按照字母排序
+func Encode(v interface{}, separator...string) ([]byte, error) {
+    sep := getSeparator(separator)
+    if sep == "" {
+        sep = gDEFAULT_SEPARATOR
+    }
+    flat := make(map[string]string)
+    flatten(flat, "", reflect.ValueOf(gconv.Map(v)), sep)
+    keys := make([]string, 0, len(flat))
+    for k := range flat {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    buffer := bytes.NewBuffer(nil)
+    for _, k := range keys {
+        buffer.WriteString(escape(k, true) + "=" + escape(flat[k], false) + "\n")
+    }
+    return buffer.Bytes(), nil
+}
+
+// 将嵌套的值展开为扁平的键值
+func flatten(flat map[string]string, prefix string, rv reflect.Value, sep string) {
+    for (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+        rv = rv.Elem()
+    }
+    join := func(key string) string {
+        if prefix == "" {
+            return key
+        }
+        return prefix + sep + key
+    }
+    switch rv.Kind() {
+        case reflect.Map:
+            for _, k := range rv.MapKeys() {
+                flatten(flat, join(gconv.String(k.Interface())), rv.MapIndex(k), sep)
+            }
+            return
+        case reflect.Struct:
+            if m := gconv.Map(rv.Interface()); len(m) > 0 {
+                flatten(flat, prefix, reflect.ValueOf(m), sep)
+                return
+            }
+        case reflect.Slice, reflect.Array:
+            if rv.Type().Elem().Kind() != reflect.Uint8 {
+                for i := 0; i < rv.Len(); i++ {
+                    flatten(flat, join(strconv.Itoa(i)), rv.Index(i), sep)
+                }
+                return
+            }
+    }
+    if !rv.IsValid() || ((rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil()) {
+        flat[prefix] = ""
+        return
+    }
+    flat[prefix] = gconv.String(rv.Interface())
+}
+
+// 转义键名或者键值，键名中的空白及分隔符均需转义，键值只需转义开头的空白
+func escape(s string, isKey bool) string {
+    buffer := bytes.NewBuffer(nil)
+    for i, r := range s {
+        switch r {
+            case '\\': buffer.WriteString(`\\`)
+            case '\t': buffer.WriteString(`\t`)
+            case '\n': buffer.WriteString(`\n`)
+            case '\r': buffer.WriteString(`\r`)
+            case '\f': buffer.WriteString(`\f`)
+            case '=', ':', '#', '!':
+                if isKey || (i == 0 && (r == '#' || r == '!')) {
+                    buffer.WriteByte('\\')
+                }
+                buffer.WriteRune(r)
+            case ' ':
+                if isKey || i == 0 {
+                    buffer.WriteByte('\\')
+                }
+                buffer.WriteRune(r)
+            default:
+                buffer.WriteRune(r)
+        }
+    }
+    return buffer.String()
+}
+
+// 解析properties内容，返回解析后的map，separator为键名的层级分隔符(默认为".")，传递空字符串时不进行嵌套
+func Decode(data []byte, separator...string) (map[string]interface{}, error) {
+    flat, keys, err := parse(data)
+    if err != nil {
+        return nil, err
+    }
+    sep    := getSeparator(separator)
+    result := make(map[string]interface{})
+    // 按照键名排序后写入，保证父级键名(例如: a)总在子键(例如: a.b)之前处理
+    sort.Strings(keys)
+    for _, key := range keys {
+        if sep == "" {
+            result[key] = flat[key]
+            continue
+        }
+        set(result, key, strings.Split(key, sep), flat[key], sep)
+    }
+    return result, nil
+}
+
+// 按照层级写入键值，遇到已存在的非map值时，剩余的键名作为整体写入当前层级
+func set(m map[string]interface{}, key string, parts []string, value string, sep string) {
+    for i, part := range parts {
+        if i == len(parts) - 1 {
+            if _, ok := m[part]; !ok {
+                m[part] = value
+                return
+            }
+            break
+        }
+        switch v := m[part].(type) {
+            case nil:
+                sub    := make(map[string]interface{})
+                m[part] = sub
+                m       = sub
+            case map[string]interface{}:
+                m = v
+            default:
+                m[strings.Join(parts[i : ], sep)] = value
+                return
+        }
+    }
+    // 键名已存在(例如同时存在 a.b 及 a 下的 b 键名)，使用完整的键名
+    m[strings.Join(parts, sep)] = value
+}
+
+// 解析为扁平的键值，同时返回键名列表
+func parse(data []byte) (map[string]string, []string, error) {
+    var (
+        flat  = make(map[string]string)
+        keys  = make([]string, 0)
+        lines = strings.Split(strings.TrimPrefix(string(data), "\uFEFF"), "\n")
+    )
+    for i := 0; i < len(lines); i++ {
+        line := strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t\f")
+        if line == "" || line[0] == '#' || line[0] == '!' {
+            continue
+        }
+        // 行尾奇数个 \ 表示续行，续行的开头空白被忽略
+        number := i + 1
+        for endsWithContinuation(line) && i + 1 < len(lines) {
+            i++
+            line = line[ : len(line) - 1] + strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t\f")
+        }
+        if endsWithContinuation(line) {
+            line = line[ : len(line) - 1]
+        }
+        key, value := splitKeyValue(line)
+        k, err := unescape(key)
+        if err != nil {
+            return nil, nil, errors.New(fmt.Sprintf("properties: line %d: %s", number, err.Error()))
+        }
+        v, err := unescape(value)
+        if err != nil {
+            return nil, nil, errors.New(fmt.Sprintf("properties: line %d: %s", number, err.Error()))
+        }
+        if _, ok := flat[k]; !ok {
+            keys = append(keys, k)
+        }
+        flat[k] = v
+    }
+    return flat, keys, nil
+}
+
+func endsWithContinuation(line string) bool {
+    count := 0
+    for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+        count++
+    }
+    return count % 2 == 1
+}
+
+// 拆分键名及键值(均未反转义)，分隔符为第一个未转义的 = : 或者空白，分隔符两侧的空白被忽略
+func splitKeyValue(line string) (string, string) {
+    for i := 0; i < len(line); i++ {
+        switch line[i] {
+            case '\\':
+                i++
+            case '=', ':':
+                return line[ : i], strings.TrimLeft(line[i + 1 : ], " \t\f")
+            case ' ', '\t', '\f':
+                rest := strings.TrimLeft(line[i : ], " \t\f")
+                if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+                    rest = strings.TrimLeft(rest[1 : ], " \t\f")
+                }
+                return line[ : i], rest
+        }
+    }
+    return line, ""
+}
+
+// 反转义
+func unescape(s string) (string, error) {
+    if strings.IndexByte(s, '\\') < 0 {
+        return s, nil
+    }
+    buffer := bytes.NewBuffer(nil)
+    for i := 0; i < len(s); i++ {
+        if s[i] != '\\' || i == len(s) - 1 {
+            buffer.WriteByte(s[i])
+            continue
+        }
+        i++
+        switch s[i] {
+            case 't': buffer.WriteByte('\t')
+            case 'n': buffer.WriteByte('\n')
+            case 'r': buffer.WriteByte('\r')
+            case 'f': buffer.WriteByte('\f')
+            case 'u':
+                if i + 4 >= len(s) {
+                    return "", errors.New(fmt.Sprintf(`invalid unicode escape "%s"`, s[i - 1 : ]))
+                }
+                n, err := strconv.ParseUint(s[i + 1 : i + 5], 16, 16)
+                if err != nil {
+                    return "", errors.New(fmt.Sprintf(`invalid unicode escape "%s"`, s[i - 1 : i + 5]))
+                }
+                r := rune(n)
+                i += 4
+                // UTF-16代理对
+                if utf8.ValidRune(r) {
+                    buffer.WriteRune(r)
+                } else if r >= 0xd800 && r < 0xdc00 && i + 6 < len(s) && s[i + 1] == '\\' && s[i + 2] == 'u' {
+                    if low, err := strconv.ParseUint(s[i + 3 : i + 7], 16, 16); err == nil && low >= 0xdc00 && low < 0xe000 {
+                        buffer.WriteRune((r - 0xd800) << 10 + (rune(low) - 0xdc00) + 0x10000)
+                        i += 6
+                        continue
+                    }
+                    buffer.WriteRune(utf8.RuneError)
+                } else {
+                    buffer.WriteRune(utf8.RuneError)
+                }
+            default:
+                buffer.WriteByte(s[i])
+        }
+    }
+    return buffer.String(), nil
+}
+
+// 将properties内容转换为JSON内容
+func ToJson(data []byte, separator...string) ([]byte, error) {
+    m, err := Decode(data, separator...)
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(m)
+}
+
+func getSeparator(separator []string) string {
+    if len(separator) > 0 {
+        return separator[0]
+    }
+    return gDEFAULT_SEPARATOR
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gproperties_test
+
+import (
+    "testing"
+    "github.com/gogf/gf/g/encoding/gproperties"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+var propertiesContent = `
+# comment
+! comment
+name = gf
+redis.addr:127.0.0.1:6379
+redis.db 1
+message = hello \
+          world
+key\ with\ space = 中文
+log.appender = console
+log.appender.layout = pattern
+`
+
+func Test_Decode(t *testing.T) {
+    gtest.Case(t, func() {
+        m, err := gproperties.Decode([]byte(propertiesContent))
+        gtest.Assert(err, nil)
+        gtest.Assert(m["name"],           "gf")
+        gtest.Assert(m["message"],        "hello world")
+        gtest.Assert(m["key with space"], "中文")
+        gtest.Assert(m["redis"], map[string]interface{} {
+            "addr" : "127.0.0.1:6379",
+            "db"   : "1",
+        })
+        // 键名同时存在值及子键时，子键保持原有的键名
+        gtest.Assert(m["log"], map[string]interface{} {
+            "appender"        : "console",
+            "appender.layout" : "pattern",
+        })
+        // 不进行嵌套
+        m, err  = gproperties.Decode([]byte(propertiesContent), "")
+        gtest.Assert(err, nil)
+        gtest.Assert(m["redis.db"], "1")
+        _, err  = gproperties.Decode([]byte(`a = \u12`))
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_Encode(t *testing.T) {
+    gtest.Case(t, func() {
+        b, err := gproperties.Encode(map[string]interface{} {
+            "name"  : " gf",
+            "a=b"   : "c:d",
+            "redis" : map[string]interface{} {
+                "addr"  : "127.0.0.1:6379",
+                "hosts" : []string{"a", "b"},
+            },
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(b), "a\\=b=c:d\nname=\\ gf\nredis.addr=127.0.0.1:6379\nredis.hosts.0=a\nredis.hosts.1=b\n")
+        m, err := gproperties.Decode(b)
+        gtest.Assert(err, nil)
+        gtest.Assert(m["name"], " gf")
+        gtest.Assert(m["a=b"],  "c:d")
+        gtest.Assert(m["redis"].(map[string]interface{})["hosts"], map[string]interface{}{"0" : "a", "1" : "b"})
+    })
+}
@@ -0,0 +1,290 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gurl
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "net/url"
+    "reflect"
+    "sort"
+    "strings"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 默认的签名参数名称
+const gDEFAULT_SIGN_KEY = "sign"
+
+// URL构建器，用于解析URL并修改各个组成部分及查询参数，所有修改方法均返回自身以便链式调用。
+// 注意URL对象不是并发安全的。
+type URL struct {
+    url   *url.URL
+    query url.Values
+}
+
+// 解析URL字符串，创建URL构建器
+func Parse(str string) (*URL, error) {
+    u, err := url.Parse(str)
+    if err != nil {
+        return nil, err
+    }
+    query, err := url.ParseQuery(u.RawQuery)
+    if err != nil {
+        return nil, err
+    }
+    return &URL {
+        url   : u,
+        query : query,
+    }, nil
+}
+
+// 获取协议，例如: https
+func (u *URL) Scheme() string {
+    return u.url.Scheme
+}
+
+// 获取主机名称(不包含端口)
+func (u *URL) Host() string {
+    return u.url.Hostname()
+}
+
+// 获取端口，没有指定时为空
+func (u *URL) Port() string {
+    return u.url.Port()
+}
+
+// 获取路径(未转义)
+func (u *URL) Path() string {
+    return u.url.Path
+}
+
+// 获取锚点
+func (u *URL) Fragment() string {
+    return u.url.Fragment
+}
+
+// 获取用户名
+func (u *URL) User() string {
+    return u.url.User.Username()
+}
+
+// 获取密码
+func (u *URL) Password() string {
+    password, _ := u.url.User.Password()
+    return password
+}
+
+// 设置协议
+func (u *URL) SetScheme(scheme string) *URL {
+    u.url.Scheme = scheme
+    return u
+}
+
+// 设置主机，可以包含端口，例如: goframe.org:8080
+func (u *URL) SetHost(host string) *URL {
+    u.url.Host = host
+    return u
+}
+
+// 设置端口，port为空时去掉端口
+func (u *URL) SetPort(port string) *URL {
+    host := u.url.Hostname()
+    if strings.Contains(host, ":") {
+        // IPv6地址
+        host = "[" + host + "]"
+    }
+    if port != "" {
+        host += ":" + port
+    }
+    u.url.Host = host
+    return u
+}
+
+// 设置路径(未转义的路径)
+func (u *URL) SetPath(path string) *URL {
+    u.url.Path    = path
+    u.url.RawPath = ""
+    return u
+}
+
+// 在当前路径后追加路径项，每个路径项作为一级路径进行转义(路径项中的 / 同样会被转义)，
+// 例如: Parse("http://a.com/api").JoinPath("user", "a/b") => http://a.com/api/user/a%2Fb
+func (u *URL) JoinPath(elements...string) *URL {
+    path := strings.TrimRight(u.url.EscapedPath(), "/")
+    for _, element := range elements {
+        path += "/" + url.PathEscape(element)
+    }
+    if path == "" {
+        path = "/"
+    }
+    u.url.Path, _ = url.PathUnescape(path)
+    u.url.RawPath = path
+    return u
+}
+
+// 设置锚点
+func (u *URL) SetFragment(fragment string) *URL {
+    u.url.Fragment = fragment
+    return u
+}
+
+// 设置用户名及密码，user为空时去掉认证信息
+func (u *URL) SetUser(user string, password...string) *URL {
+    switch {
+        case user == "":
+            u.url.User = nil
+        case len(password) > 0:
+            u.url.User = url.UserPassword(user, password[0])
+        default:
+            u.url.User = url.User(user)
+    }
+    return u
+}
+
+// 获取查询参数的值，不存在时返回空字符串
+func (u *URL) Get(key string) string {
+    return u.query.Get(key)
+}
+
+// 获取所有的查询参数(副本)
+func (u *URL) Query() url.Values {
+    query := make(url.Values, len(u.query))
+    for k, v := range u.query {
+        query[k] = append([]string(nil), v...)
+    }
+    return query
+}
+
+// 设置查询参数，替换已有的值，value为slice/array时设置为多个值
+func (u *URL) Set(key string, value interface{}) *URL {
+    u.query[key] = toQueryValues(value)
+    return u
+}
+
+// 增加查询参数，保留已有的值
+func (u *URL) Add(key string, value interface{}) *URL {
+    u.query[key] = append(u.query[key], toQueryValues(value)...)
+    return u
+}
+
+// 删除查询参数
+func (u *URL) Del(keys...string) *URL {
+    for _, key := range keys {
+        u.query.Del(key)
+    }
+    return u
+}
+
+// 使用map设置查询参数，替换已有的值
+func (u *URL) SetMap(data map[string]interface{}) *URL {
+    for k, v := range data {
+        u.Set(k, v)
+    }
+    return u
+}
+
+// 使用struct设置查询参数，替换已有的值，参数名称使用gconv/json标签或者属性名称(规则同gconv.Map)
+func (u *URL) SetStruct(pointer interface{}) *URL {
+    return u.SetMap(gconv.Map(pointer))
+}
+
+// 清空所有的查询参数
+func (u *URL) ClearQuery() *URL {
+    u.query = make(url.Values)
+    return u
+}
+
+// 使用secret对当前的查询参数进行签名(参考SignQuery)，并将签名设置到signKey参数(默认为sign)
+func (u *URL) Sign(secret string, signKey...string) *URL {
+    key := getSignKey(signKey)
+    u.query.Del(key)
+    u.query.Set(key, SignQuery(u.query, secret, key))
+    return u
+}
+
+// 校验当前查询参数中的签名是否正确
+func (u *URL) Verify(secret string, signKey...string) bool {
+    return VerifyQuery(u.query, secret, signKey...)
+}
+
+// 生成URL字符串，查询参数按照参数名称排序
+func (u *URL) String() string {
+    c         := *u.url
+    c.RawQuery = u.query.Encode()
+    return c.String()
+}
+
+// 拼接URL路径，规则同URL.JoinPath，base可以为完整的URL或者路径
+func JoinPath(base string, elements...string) (string, error) {
+    u, err := Parse(base)
+    if err != nil {
+        return "", err
+    }
+    return u.JoinPath(elements...).String(), nil
+}
+
+// 使用HMAC-SHA256对查询参数进行签名，返回十六进制的签名字符串。
+// 签名内容为除signKey(默认为sign)以外的参数按照参数名称排序后编码的查询字符串(同url.Values.Encode)。
+func SignQuery(values url.Values, secret string, signKey...string) string {
+    key  := getSignKey(signKey)
+    keys := make([]string, 0, len(values))
+    for k := range values {
+        if k != key {
+            keys = append(keys, k)
+        }
+    }
+    sort.Strings(keys)
+    buffer := make([]string, 0, len(keys))
+    for _, k := range keys {
+        for _, v := range values[k] {
+            buffer = append(buffer, url.QueryEscape(k) + "=" + url.QueryEscape(v))
+        }
+    }
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(strings.Join(buffer, "&")))
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 校验查询参数中signKey(默认为sign)参数的签名是否正确，使用常量时间比较防止时序攻击
+func VerifyQuery(values url.Values, secret string, signKey...string) bool {
+    sign := values.Get(getSignKey(signKey))
+    if sign == "" {
+        return false
+    }
+    return hmac.Equal([]byte(sign), []byte(SignQuery(values, secret, signKey...)))
+}
+
+// 获取签名参数名称
+func getSignKey(signKey []string) string {
+    if len(signKey) > 0 && signKey[0] != "" {
+        return signKey[0]
+    }
+    return gDEFAULT_SIGN_KEY
+}
+
+// 将参数值转换为字符串列表
+func toQueryValues(value interface{}) []string {
+    if value == nil {
+        return []string{""}
+    }
+    switch v := value.(type) {
+        case []byte:
+            return []string{string(v)}
+        case []string:
+            return append([]string(nil), v...)
+    }
+    rv := reflect.ValueOf(value)
+    if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+        values := make([]string, rv.Len())
+        for i := 0; i < rv.Len(); i++ {
+            values[i] = gconv.String(rv.Index(i).Interface())
+        }
+        return values
+    }
+    return []string{gconv.String(value)}
+}
@@ -22,7 +22,7 @@ func Decode(v []byte) (interface{}, error) {
 }
 
 func DecodeTo(v []byte, result interface{}) error {
-    return yaml.Unmarshal(v, &result)
+    return yaml.Unmarshal(v, result)
 }
 
 func ToJson(v []byte) ([]byte, error) {
@@ -23,6 +23,7 @@ import (
     "github.com/gogf/gf/g/os/gview"
     "github.com/gogf/gf/g/util/gconv"
     "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/text/gstr"
 )
 
 const (
@@ -169,9 +170,11 @@ func Database(name...string) gdb.DB {
                 }
                 gdb.AddConfigGroup(group, cg)
             }
-            // 使用gfsnotify进行文件监控，当配置文件有任何变化时，清空数据库配置缓存
+            // 使用gfsnotify进行文件监控，当配置文件有任何变化时，清空数据库配置缓存并关闭原有的数据库对象
             gfsnotify.Add(config.GetFilePath(), func(event *gfsnotify.Event) {
-                instances.Remove(key)
+                if db, ok := instances.Remove(key).(gdb.DB); ok {
+                    db.Close()
+                }
             })
         }
         if db, err := gdb.New(name...); err == nil {
@@ -197,9 +200,17 @@ func Redis(name...string) *gredis.Redis {
     key    := fmt.Sprintf("%s.%s", gFRAME_CORE_COMPONENT_NAME_REDIS, group)
     result := instances.GetOrSetFuncLock(key, func() interface{} {
         if m := config.GetMap("redis"); m != nil {
-            // host:port[,db[,pass]]
+            // host:port[,db[,pass]] 或者 redis[s]://[[user]:pass@]host[:port][/db]
             if v, ok := m[group]; ok {
-                line     := gconv.String(v)
+                line := gconv.String(v)
+                if gstr.Contains(line, "://") {
+                    if redisConfig, err := gredis.ParseURL(line); err == nil {
+                        return gredis.New(redisConfig)
+                    } else {
+                        glog.Errorfln(`invalid redis node configuration: "%s", %s`, line, err.Error())
+                    }
+                    return nil
+                }
                 array, _ := gregex.MatchString(`(.+):(\d+),{0,1}(\d*),{0,1}(.*)`, line)
                 if len(array) > 4 {
                     return gredis.New(gredis.Config{
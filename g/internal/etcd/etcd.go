@@ -0,0 +1,150 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// etcd v3 HTTP(gRPC gateway)接口的简易客户端，供配置管理(gcfg)及分布式锁(gmlock)的etcd适配器共用。
+package etcd
+
+import (
+    "bytes"
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "net/http"
+    "strings"
+    "sync"
+)
+
+const (
+    DEFAULT_ADDRESS = "http://127.0.0.1:2379" // 默认的etcd服务地址
+)
+
+// 客户端配置
+type Config struct {
+    Address  string       // etcd服务地址，默认为http://127.0.0.1:2379
+    Username string       // 用户名(开启认证时需要)
+    Password string       // 密码
+    Client   *http.Client // 自定义的HTTP客户端(非必需)
+}
+
+// etcd客户端，开启认证时自动获取token，token失效时重新获取
+type Client struct {
+    config Config
+    mu     sync.Mutex
+    token  string // 认证token
+}
+
+// etcd接口返回的数据头
+type Header struct {
+    Revision string `json:"revision"`
+}
+
+// 创建etcd客户端
+func New(config Config) *Client {
+    if config.Address == "" {
+        config.Address = DEFAULT_ADDRESS
+    }
+    if config.Client == nil {
+        config.Client = http.DefaultClient
+    }
+    config.Address = strings.TrimRight(config.Address, "/")
+    return &Client {
+        config : config,
+    }
+}
+
+// 按照etcd接口的要求将键名或者键值编码为base64字符串
+func Encode(s string) string {
+    return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// 执行etcd接口请求并解析返回结果，result为nil时忽略返回内容
+func (c *Client) Call(ctx context.Context, path string, data interface{}, result interface{}) error {
+    response, err := c.Request(ctx, path, data)
+    if err != nil {
+        return err
+    }
+    defer response.Body.Close()
+    if result == nil {
+        return nil
+    }
+    return json.NewDecoder(response.Body).Decode(result)
+}
+
+// 执行etcd接口请求，返回状态码为200的响应，调用方需要关闭响应内容
+func (c *Client) Request(ctx context.Context, path string, data interface{}) (*http.Response, error) {
+    for retried := false; ; retried = true {
+        token, err := c.getToken(ctx, retried)
+        if err != nil {
+            return nil, err
+        }
+        response, err := c.post(ctx, path, data, token)
+        if err != nil {
+            return nil, err
+        }
+        if response.StatusCode == http.StatusOK {
+            return response, nil
+        }
+        content, _ := ioutil.ReadAll(response.Body)
+        response.Body.Close()
+        if response.StatusCode == http.StatusUnauthorized && token != "" && !retried {
+            continue
+        }
+        return nil, errors.New(fmt.Sprintf(`etcd request failed: %s %s`, response.Status, strings.TrimSpace(string(content))))
+    }
+}
+
+// 获取认证token，未配置用户名时返回空字符串
+func (c *Client) getToken(ctx context.Context, refresh bool) (string, error) {
+    if c.config.Username == "" {
+        return "", nil
+    }
+    c.mu.Lock()
+    token := c.token
+    c.mu.Unlock()
+    if token != "" && !refresh {
+        return token, nil
+    }
+    response, err := c.post(ctx, "/v3/auth/authenticate", map[string]string {
+        "name"     : c.config.Username,
+        "password" : c.config.Password,
+    }, "")
+    if err != nil {
+        return "", err
+    }
+    defer response.Body.Close()
+    if response.StatusCode != http.StatusOK {
+        return "", errors.New(fmt.Sprintf(`etcd authenticate failed: %s`, response.Status))
+    }
+    result := struct {
+        Token string `json:"token"`
+    }{}
+    if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+        return "", err
+    }
+    c.mu.Lock()
+    c.token = result.Token
+    c.mu.Unlock()
+    return result.Token, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, data interface{}, token string) (*http.Response, error) {
+    body, err := json.Marshal(data)
+    if err != nil {
+        return nil, err
+    }
+    request, err := http.NewRequest("POST", c.config.Address + path, bytes.NewReader(body))
+    if err != nil {
+        return nil, err
+    }
+    request.Header.Set("Content-Type", "application/json")
+    if token != "" {
+        request.Header.Set("Authorization", token)
+    }
+    return c.config.Client.Do(request.WithContext(ctx))
+}
@@ -11,9 +11,12 @@ import (
     "github.com/gogf/gf/g/encoding/gjson"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/text/gregex"
+    "github.com/gogf/gf/g/util/gvalid"
     "github.com/gogf/gf/third/github.com/fatih/structs"
     "io/ioutil"
     "net/http"
+    "sort"
+    "strconv"
     "strings"
 )
 
@@ -37,6 +40,7 @@ type Request struct {
     parsedHost    string                  // 解析过后不带端口号的服务器域名名称
     clientIp      string                  // 解析过后的客户端IP地址
     rawContent    []byte                  // 客户端提交的原始参数
+    locales       []string                // 请求的语言(按照优先级排列)
     isFileRequest bool                    // 是否为静态文件请求(非服务请求，当静态文件存在时，优先级会被服务请求高，被识别为文件请求)
 }
 
@@ -155,6 +159,27 @@ func (r *Request) GetToStruct(object interface{}, mapping...map[string]string) {
     r.GetRequestToStruct(object, mapping...)
 }
 
+// 将所有的request参数映射到struct属性上，并使用struct属性的gvalid标签对映射后的数据进行校验(支持自定义校验规则)，
+// 参数object应当为一个struct对象的指针，校验通过时返回nil
+func (r *Request) Parse(object interface{}, mapping...map[string]string) *gvalid.Error {
+    r.GetRequestToStruct(object, mapping...)
+    return gvalid.Locale(r.GetLocales()...).CheckStruct(object, nil)
+}
+
+// 设置当前请求的语言(按照优先级排列)，将会覆盖Accept-Language请求头中的语言，
+// 用于请求参数校验错误消息等多语言处理
+func (r *Request) SetLocales(locales...string) {
+    r.locales = locales
+}
+
+// 获取当前请求的语言(按照优先级排列)，未通过SetLocales设置时从Accept-Language请求头中解析
+func (r *Request) GetLocales() []string {
+    if r.locales == nil {
+        r.locales = parseAcceptLanguage(r.Header.Get("Accept-Language"))
+    }
+    return r.locales
+}
+
 // 仅退出当前逻辑执行函数, 如:服务函数、HOOK函数
 func (r *Request) Exit() {
     panic(gEXCEPTION_EXIT)
@@ -219,6 +244,41 @@ func (r *Request) GetReferer() string {
     return r.Header.Get("Referer")
 }
 
+// 解析Accept-Language请求头，例如: zh-CN,zh;q=0.9,en;q=0.8，返回按照权重降序排列的语言列表
+func parseAcceptLanguage(header string) []string {
+    type language struct {
+        name   string
+        weight float64
+    }
+    languages := make([]language, 0)
+    for _, item := range strings.Split(header, ",") {
+        array  := strings.Split(item, ";")
+        name   := strings.TrimSpace(array[0])
+        weight := float64(1)
+        if name == "" || name == "*" {
+            continue
+        }
+        for _, param := range array[1 : ] {
+            if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+                if v, err := strconv.ParseFloat(param[2 : ], 64); err == nil {
+                    weight = v
+                }
+            }
+        }
+        if weight > 0 {
+            languages = append(languages, language{name, weight})
+        }
+    }
+    sort.SliceStable(languages, func(i, j int) bool {
+        return languages[i].weight > languages[j].weight
+    })
+    locales := make([]string, len(languages))
+    for i, v := range languages {
+        locales[i] = v.name
+    }
+    return locales
+}
+
 // 获得结构体对象的参数名称标签，构成map返回
 func (r *Request) getStructParamsTagMap(object interface{}) map[string]string {
     tagmap := make(map[string]string)
@@ -159,7 +159,11 @@ func (r *Response) WriteStatus(status int, content...string) {
 // 静态文件处理
 func (r *Response) ServeFile(path string) {
     // 首先判断是否给定的path已经是一个绝对路径
-    path = gfile.RealPath(path)
+    if realPath := gfile.RealPath(path); realPath != "" {
+        path = realPath
+    } else {
+        path = resourceFilePath(path)
+    }
     if path == "" {
         r.WriteStatus(http.StatusNotFound)
         return
@@ -170,7 +174,11 @@ func (r *Response) ServeFile(path string) {
 // 静态文件下载处理
 func (r *Response) ServeFileDownload(path string, name...string) {
     // 首先判断是否给定的path已经是一个绝对路径
-    path = gfile.RealPath(path)
+    if realPath := gfile.RealPath(path); realPath != "" {
+        path = realPath
+    } else {
+        path = resourceFilePath(path)
+    }
     if path == "" {
         r.WriteStatus(http.StatusNotFound)
         return
@@ -8,6 +8,7 @@
 package ghttp
 
 import (
+    "strings"
     "github.com/gogf/gf/g/os/gview"
     "github.com/gogf/gf/g/frame/gins"
 )
@@ -73,6 +74,12 @@ func (r *Response) buildInVars(params map[string]interface{}) map[string]interfa
     }
     params["Cookie"]  = r.request.Cookie.Map()
     params["Session"] = r.request.Session.Data()
+    // 未指定模板翻译语言时，使用客户端Accept-Language中的首选语言
+    if _, ok := params[gview.I18N_LANGUAGE_KEY]; !ok {
+        if language := r.request.Header.Get("Accept-Language"); language != "" {
+            params[gview.I18N_LANGUAGE_KEY] = strings.TrimSpace(strings.Split(strings.Split(language, ",")[0], ";")[0])
+        }
+    }
     return params
 }
 
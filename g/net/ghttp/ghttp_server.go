@@ -18,6 +18,7 @@ import (
     "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/os/glog"
     "github.com/gogf/gf/g/os/gproc"
+    "github.com/gogf/gf/g/os/gsession"
     "github.com/gogf/gf/g/os/gtimer"
     "github.com/gogf/gf/g/text/gregex"
     "github.com/gogf/gf/g/util/gconv"
@@ -52,7 +53,7 @@ type (
         hsmu             sync.RWMutex                     // status handler互斥锁
         statusHandlerMap map[string]HandlerFunc           // 不同状态码下的注册处理方法(例如404状态时的处理方法)
         // SESSION
-        sessions         *gcache.Cache                    // Session内存缓存
+        sessionManager   *gsession.Manager                // Session管理器
         // Logger
         logger           *glog.Logger                     // 日志管理对象
     }
@@ -213,7 +214,7 @@ func GetServer(name...interface{}) (*Server) {
         serveCache       : gcache.New(),
         hooksCache       : gcache.New(),
         routesMap        : make(map[string][]registeredRouteItem),
-        sessions         : gcache.New(),
+        sessionManager   : gsession.New(gDEFAULT_SESSION_MAX_AGE*time.Second),
         servedCount      : gtype.NewInt(),
         logger           : glog.New(),
     }
@@ -237,6 +238,12 @@ func (s *Server) Start() error {
         return errors.New("server is already running")
     }
 
+    // Session配置
+    s.sessionManager.SetTTL(time.Duration(s.config.SessionMaxAge)*time.Second)
+    if s.config.SessionStorage != nil {
+        s.sessionManager.SetStorage(s.config.SessionStorage)
+    }
+
     // 底层http server配置
     if s.config.Handler == nil {
         s.config.Handler = http.HandlerFunc(s.defaultHttpHandle)
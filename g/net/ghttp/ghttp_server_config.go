@@ -10,6 +10,7 @@ import (
     "fmt"
     "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/os/gsession"
     "net/http"
     "strconv"
     "time"
@@ -62,6 +63,7 @@ type ServerConfig struct {
     // SESSION
     SessionMaxAge     int                   // Session有效期
     SessionIdName     string                // SessionId名称
+    SessionStorage    gsession.Storage      // Session存储适配器(默认为内存存储)
 
     // IP访问控制
     DenyIps           []string              // 不允许访问的ip列表，支持ip前缀过滤，如: 10 将不允许10开头的ip访问
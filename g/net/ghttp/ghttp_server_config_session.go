@@ -6,7 +6,10 @@
 
 package ghttp
 
-import "github.com/gogf/gf/g/os/glog"
+import (
+    "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/os/gsession"
+)
 
 // 设置http server参数 - SessionMaxAge
 func (s *Server) SetSessionMaxAge(age int) {
@@ -26,6 +29,16 @@ func (s *Server) SetSessionIdName(name string) {
     s.config.SessionIdName = name
 }
 
+// 设置http server参数 - SessionStorage，Session存储适配器(默认为内存存储)，
+// 例如: gsession.NewStorageRedis(g.Redis())
+func (s *Server) SetSessionStorage(storage gsession.Storage) {
+    if s.Status() == SERVER_STATUS_RUNNING {
+        glog.Error(gCHANGE_CONFIG_WHILE_RUNNING_ERROR)
+        return
+    }
+    s.config.SessionStorage = storage
+}
+
 // 获取http server参数 - SessionMaxAge
 func (s *Server) GetSessionMaxAge() int {
     return s.config.SessionMaxAge
@@ -5,6 +5,7 @@
 // You can obtain one at https://github.com/gogf/gf.
 
 // 静态文件搜索优先级: ServerPaths > ServerRoot > SearchPath
+// 目录在磁盘中不存在时，可以使用打包资源(gres)中的同名目录
 
 package ghttp
 
@@ -61,6 +62,9 @@ func (s *Server)SetServerRoot(root string) {
     if path == "" {
         path = gfile.RealPath(gfile.MainPkgPath() + gfile.Separator + root)
     }
+    if path == "" {
+        path = resourceDirPath(root)
+    }
     if path == "" {
         glog.Fatal(fmt.Sprintf(`[ghttp] SetServerRoot failed: path "%s" does not exist`, root))
     }
@@ -79,6 +83,9 @@ func (s *Server) AddSearchPath(path string) {
     if realPath == "" {
         realPath = gfile.RealPath(gfile.MainPkgPath() + gfile.Separator + path)
     }
+    if realPath == "" {
+        realPath = resourceDirPath(path)
+    }
     if realPath == "" {
         glog.Fatal(fmt.Sprintf(`[ghttp] AddSearchPath failed: path "%s" does not exist`, path))
     }
@@ -97,6 +104,9 @@ func (s *Server) AddStaticPath(prefix string, path string) {
     if realPath == "" {
         realPath = gfile.RealPath(gfile.MainPkgPath() + gfile.Separator + path)
     }
+    if realPath == "" {
+        realPath = resourceDirPath(path)
+    }
     if realPath == "" {
         glog.Fatal(fmt.Sprintf(`[ghttp] AddStaticPath failed: path "%s" does not exist`, path))
     }
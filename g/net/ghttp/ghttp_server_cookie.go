@@ -10,6 +10,7 @@
 package ghttp
 
 import (
+    "github.com/gogf/gf/g/os/gsession"
     "github.com/gogf/gf/g/os/gtime"
     "net/http"
     "time"
@@ -81,7 +82,7 @@ func (c *Cookie) SessionId() string {
     c.init()
     id := c.Get(c.server.GetSessionIdName())
     if id == "" {
-        id = makeSessionId()
+        id = gsession.NewSessionId()
         c.SetSessionId(id)
     }
     return id
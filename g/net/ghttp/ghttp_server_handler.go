@@ -10,6 +10,7 @@ package ghttp
 import (
     "fmt"
     "github.com/gogf/gf/g/encoding/ghtml"
+    "github.com/gogf/gf/g/os/gres"
     "github.com/gogf/gf/g/os/gspath"
     "github.com/gogf/gf/g/os/gtime"
     "net/http"
@@ -151,7 +152,10 @@ func (s *Server) searchStaticFile(uri string) (filePath string, isDir bool) {
                 if len(uri) > len(item.prefix) && uri[len(item.prefix)] != '/' {
                     continue
                 }
-                return gspath.Search(item.path, uri[len(item.prefix):], s.config.IndexFiles...)
+                if filePath, isDir = gspath.Search(item.path, uri[len(item.prefix):], s.config.IndexFiles...); filePath != "" {
+                    return filePath, isDir
+                }
+                return searchResourceFile(item.path, uri[len(item.prefix):], s.config.IndexFiles...)
             }
         }
     }
@@ -162,6 +166,12 @@ func (s *Server) searchStaticFile(uri string) (filePath string, isDir bool) {
                 return filePath, isDir
             }
         }
+        // 最后查找打包资源
+        for _, path := range s.config.SearchPaths {
+            if filePath, isDir = searchResourceFile(path, uri, s.config.IndexFiles...); filePath != "" {
+                return filePath, isDir
+            }
+        }
     }
     return "", false
 }
@@ -218,9 +228,15 @@ func (s *Server) niceCallFunc(f func()) {
     f()
 }
 
-// http server静态文件处理，path可以为相对路径也可以为绝对路径
+// http server静态文件处理，path可以为相对路径也可以为绝对路径，或者打包资源中的文件路径
 func (s *Server) serveFile(r *Request, path string) {
-    f, err := os.Open(path)
+    var f http.File
+    var err error
+    if strings.HasPrefix(path, gRESOURCE_PATH_PREFIX) {
+        f, err = gres.Open(path[len(gRESOURCE_PATH_PREFIX) : ])
+    } else {
+        f, err = os.Open(path)
+    }
     if err != nil {
         r.Response.WriteStatus(http.StatusForbidden)
         return
@@ -0,0 +1,51 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+// 打包资源(gres)静态文件服务.
+
+package ghttp
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "path"
+    "strings"
+)
+
+const (
+    gRESOURCE_PATH_PREFIX = "resource://" // 打包资源中的静态文件路径前缀
+)
+
+// 静态文件目录在磁盘中不存在时，判断是否为打包资源中的目录，是则返回资源目录路径，否则返回空字符串
+func resourceDirPath(path string) string {
+    if f := gres.Get(path); f != nil && f.IsDir() {
+        return f.Path()
+    }
+    return ""
+}
+
+// 在打包资源中查找静态文件，dir为静态文件目录(使用目录名称作为资源目录)，
+// uri先单独进行路径规范化，防止通过"../"访问静态文件目录以外的资源文件
+func searchResourceFile(dir string, uri string, indexFiles...string) (filePath string, isDir bool) {
+    if gres.IsEmpty() {
+        return "", false
+    }
+    uri = path.Clean("/" + strings.Replace(uri, "\\", "/", -1))
+    if f := gres.Search(gfile.Basename(dir), uri, indexFiles...); f != nil {
+        return gRESOURCE_PATH_PREFIX + f.Path(), f.IsDir()
+    }
+    return "", false
+}
+
+// 获取文件路径对应的打包资源文件路径，不存在时返回空字符串
+func resourceFilePath(path string) string {
+    if strings.HasPrefix(path, gRESOURCE_PATH_PREFIX) {
+        return path
+    }
+    if f := gres.Get(path); f != nil {
+        return gRESOURCE_PATH_PREFIX + f.Path()
+    }
+    return ""
+}
@@ -8,27 +8,16 @@
 package ghttp
 
 import (
-    "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/container/gvar"
-    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/os/gsession"
     "github.com/gogf/gf/g/util/gconv"
-    "github.com/gogf/gf/g/util/grand"
-    "strconv"
-    "strings"
     "time"
 )
 
-// SESSION对象
+// SESSION对象，会话数据由Server的gsession会话管理器管理
 type Session struct {
-    id      string                   // SessionId
-    data    *gmap.StringInterfaceMap // Session数据
-    server  *Server                  // 所属Server
-    request *Request                 // 关联的请求
-}
-
-// 生成一个唯一的SessionId字符串，长度16位
-func makeSessionId() string {
-    return strings.ToUpper(strconv.FormatInt(gtime.Nanosecond(), 32) + grand.RandStr(3))
+    session *gsession.Session // 底层会话对象(延迟初始化)
+    request *Request          // 关联的请求
 }
 
 // 获取或者生成一个session对象(延迟初始化)
@@ -41,28 +30,36 @@ func GetSession(r *Request) *Session {
     }
 }
 
-// 执行初始化(用于延迟初始化)
+// 执行初始化(用于延迟初始化)，客户端未提交SessionId时生成新的SessionId并写入Cookie
 func (s *Session) init() {
-    if len(s.id) == 0 {
-        s.id     = s.request.Cookie.SessionId()
-        s.server = s.request.Server
-        s.data   = s.server.sessions.GetOrSetFuncLock(s.id, func() interface{} {
-            return gmap.NewStringInterfaceMap()
-        }, s.server.GetSessionMaxAge()).(*gmap.StringInterfaceMap)
+    if s.session == nil {
+        s.session = s.request.Server.sessionManager.New(s.request.Cookie.SessionId())
+    }
+}
+
+// 判断客户端是否已提交SessionId，未提交时读取操作无需创建会话
+func (s *Session) exists() bool {
+    return s.session != nil || s.request.Cookie.GetSessionId() != ""
+}
+
+// 会话存储操作失败时记录错误日志
+func (s *Session) handleError(err error) {
+    if err != nil {
+        s.request.Server.handleErrorLog(err, s.request)
     }
 }
 
 // 获取/创建SessionId
 func (s *Session) Id() string {
     s.init()
-    return s.id
+    return s.session.Id()
 }
 
 // 获取当前session所有数据
 func (s *Session) Data() map[string]interface{} {
-    if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
+    if s.exists() {
         s.init()
-        return s.data.Map()
+        return s.session.Map()
     }
     return nil
 }
@@ -70,64 +67,67 @@ func (s *Session) Data() map[string]interface{} {
 // 设置session
 func (s *Session) Set(key string, value interface{}) {
     s.init()
-    s.data.Set(key, value)
+    s.handleError(s.session.Set(key, value))
 }
 
 // 批量设置(BatchSet别名)
 func (s *Session) Sets(m map[string]interface{}) {
-    s.init()
     s.BatchSet(m)
 }
 
 // 批量设置
 func (s *Session) BatchSet(m map[string]interface{}) {
     s.init()
-    s.data.BatchSet(m)
+    s.handleError(s.session.Sets(m))
 }
 
 // 判断键名是否存在
 func (s *Session) Contains (key string) bool {
-    if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
+    if s.exists() {
         s.init()
-        return s.data.Contains(key)
+        return s.session.Contains(key)
     }
     return false
 }
 
-// 获取SESSION
-func (s *Session) Get (key string) interface{}  {
-    if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
+// 获取SESSION，键名不存在时返回默认值def(可选)
+func (s *Session) Get (key string, def...interface{}) interface{}  {
+    if s.exists() {
         s.init()
-        return s.data.Get(key)
+        return s.session.Get(key, def...)
+    }
+    if len(def) > 0 {
+        return def[0]
     }
     return nil
 }
 
 // 获取SESSION，建议都用该方法获取参数
-func (s *Session) GetVar(key string) gvar.VarRead  {
-    return gvar.NewRead(s.Get(key), true)
+func (s *Session) GetVar(key string, def...interface{}) gvar.VarRead  {
+    return gvar.NewRead(s.Get(key, def...), true)
 }
 
 // 删除session
 func (s *Session) Remove(key string) {
-    if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
+    if s.exists() {
         s.init()
-        s.data.Remove(key)
+        s.handleError(s.session.Remove(key))
     }
 }
 
 // 清空session
 func (s *Session) Clear() {
-    if len(s.id) > 0 || s.request.Cookie.GetSessionId() != "" {
+    if s.exists() {
         s.init()
-        s.data.Clear()
+        s.handleError(s.session.Clear())
     }
 }
 
-// 更新过期时间(如果用在守护进程中长期使用，需要手动调用进行更新，防止超时被清除)
+// 更新过期时间并保存修改的会话数据，请求结束时自动调用
+// (如果用在守护进程中长期使用，需要手动调用进行更新，防止超时被清除)
 func (s *Session) UpdateExpire() {
-    if len(s.id) > 0 && s.data.Size() > 0 {
-        s.server.sessions.Set(s.id, s.data, s.server.GetSessionMaxAge()*1000)
+    if s.session != nil {
+        s.handleError(s.session.Close())
     }
 }
 
@@ -8,10 +8,12 @@
 package ghttp_test
 
 import (
+    "errors"
     "fmt"
     "github.com/gogf/gf/g"
     "github.com/gogf/gf/g/net/ghttp"
     "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gvalid"
     "testing"
     "time"
 )
@@ -146,3 +148,43 @@ func Test_Params_Basic(t *testing.T) {
         gtest.Assert(client.PostContent("/struct", `id=1&name=john&password1=123&password2=456`), `1john123456`)
     })
 }
+
+func Test_Params_Parse(t *testing.T) {
+    type User struct {
+        Id   int    `gvalid:"id@required|min:1"`
+        Code string `gvalid:"code@ghttp-code:GF"`
+    }
+    gvalid.RegisterRule("ghttp-code", func(value interface{}, params string, data map[string]interface{}) error {
+        if len(fmt.Sprint(value)) < 2 || fmt.Sprint(value)[ : 2] != params {
+            return errors.New("invalid code")
+        }
+        return nil
+    })
+    defer gvalid.DeleteRule("ghttp-code")
+    p := ports.PopRand()
+    s := g.Server(p)
+    s.BindHandler("/parse", func(r *ghttp.Request){
+        user := new(User)
+        if err := r.Parse(user); err != nil {
+            r.Response.Write(err.String())
+        } else {
+            r.Response.Write(user.Id, user.Code)
+        }
+    })
+    s.SetPort(p)
+    s.SetDumpRouteMap(false)
+    s.Start()
+    defer s.Shutdown()
+
+    // 等待启动完成
+    time.Sleep(time.Second)
+    gtest.Case(t, func() {
+        client := ghttp.NewClient()
+        client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+        gtest.Assert(client.GetContent("/parse",  "id=1&code=GF001"), `1GF001`)
+        gtest.Assert(client.PostContent("/parse", "id=1&code=XX001"), `invalid code`)
+        gtest.Assert(client.GetContent("/parse",  "id=0&code=GF001"), `字段最小值为1`)
+        client.SetHeader("Accept-Language", "fr;q=0.5, en-US, *")
+        gtest.Assert(client.GetContent("/parse",  "id=0&code=GF001"), `The field must be at least 1`)
+    })
+}
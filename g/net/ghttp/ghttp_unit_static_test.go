@@ -0,0 +1,65 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 静态文件服务测试
+package ghttp_test
+
+import (
+    "bufio"
+    "fmt"
+    "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/net/ghttp"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "net"
+    "net/http"
+    "testing"
+    "time"
+)
+
+func Test_Static_Resource(t *testing.T) {
+    dir := gfile.TempDir() + gfile.Separator + "ghttp_static_" + gconv.String(gtime.Nanosecond())
+    gfile.PutContents(dir + "/ghttp_static_public/index.html",  "index")
+    gfile.PutContents(dir + "/ghttp_static_config/config.toml", "password=secret")
+    defer gfile.Remove(dir)
+    data, err := gres.Pack(dir + "/ghttp_static_public," + dir + "/ghttp_static_config")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := gres.Add(string(data)); err != nil {
+        t.Fatal(err)
+    }
+
+    p := ports.PopRand()
+    s := g.Server(p)
+    s.SetServerRoot("ghttp_static_public")
+    s.SetPort(p)
+    s.SetDumpRouteMap(false)
+    s.Start()
+    defer s.Shutdown()
+
+    // 等待启动完成
+    time.Sleep(time.Second)
+    gtest.Case(t, func() {
+        client := ghttp.NewClient()
+        client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", p))
+        gtest.Assert(client.GetContent("/index.html"), "index")
+        // 原始请求中的"../"不能访问静态目录以外的资源文件
+        for _, uri := range []string{"/../ghttp_static_config/config.toml", "/%2e%2e/ghttp_static_config/config.toml"} {
+            conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", p))
+            gtest.Assert(err, nil)
+            fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: 127.0.0.1\r\nConnection: close\r\n\r\n", uri)
+            response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+            gtest.Assert(err, nil)
+            gtest.Assert(response.StatusCode, http.StatusNotFound)
+            response.Body.Close()
+            conn.Close()
+        }
+    })
+}
@@ -13,6 +13,11 @@ package gcache
 // 全局缓存管理对象
 var cache = New()
 
+// 替换全局缓存对象的适配器，例如替换为共享的Redis缓存: SetAdapter(NewRedisAdapter(redis))
+func SetAdapter(adapter Adapter) {
+    cache.SetAdapter(adapter)
+}
+
 // (使用全局KV缓存对象)设置kv缓存键值对，过期时间单位为**毫秒**
 func Set(key interface{}, value interface{}, expire int)  {
     cache.Set(key, value, expire)
@@ -44,7 +49,7 @@ func GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{}
     return cache.GetOrSetFunc(key, f, expire)
 }
 
-// 与GetOrSetFunc不同的是，f是在写锁机制内执行
+// 与GetOrSetFunc不同的是，同一键名的f同时只会有一个在执行，其他调用者等待并共享其执行结果
 func GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{} {
     return cache.GetOrSetFuncLock(key, f, expire)
 }
@@ -88,3 +93,18 @@ func Values() []interface{} {
 func Size() int {
     return cache.Size()
 }
+
+// 获取全局缓存的统计信息
+func GetStats() Stats {
+    return cache.Stats()
+}
+
+// 将全局缓存数据(包含过期时间)保存到指定的快照文件
+func Dump(path string) error {
+    return cache.Dump(path)
+}
+
+// 从指定的快照文件恢复全局缓存数据
+func Load(path string) error {
+    return cache.Load(path)
+}
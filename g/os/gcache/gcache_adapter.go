@@ -0,0 +1,49 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+// 缓存适配器接口，Cache对象的所有操作均通过适配器完成，默认使用进程内的内存缓存适配器，
+// 可以通过NewWithAdapter/SetAdapter替换为共享的缓存(例如: NewRedisAdapter)。
+// 过期时间单位均为毫秒，expire为0表示不过期，小于0表示立即过期。
+type Adapter interface {
+    // 设置键值对
+    Set(key interface{}, value interface{}, expire int)
+    // 当键名不存在时写入，并返回true；否则返回false
+    SetIfNotExist(key interface{}, value interface{}, expire int) bool
+    // 批量设置键值对
+    BatchSet(data map[interface{}]interface{}, expire int)
+    // 获取键值，不存在时返回nil
+    Get(key interface{}) interface{}
+    // 当键名存在时返回其键值，否则写入指定的键值
+    GetOrSet(key interface{}, value interface{}, expire int) interface{}
+    // 当键名存在时返回其键值，否则写入f生成的键值(f可能被并发执行)
+    GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{}
+    // 同GetOrSetFunc，但是保证同一键名的f只会被执行一次，其他调用者等待执行结果
+    GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{}
+    // 是否存在指定的键名
+    Contains(key interface{}) bool
+    // 删除键值对，并返回被删除的键值
+    Remove(key interface{}) interface{}
+    // 批量删除键值对
+    BatchRemove(keys []interface{})
+    // 返回所有的键值对(不包含已过期数据)
+    Data() map[interface{}]interface{}
+    // 返回所有的键名
+    Keys() []interface{}
+    // 返回所有的键名字符串
+    KeyStrings() []string
+    // 返回所有的键值
+    Values() []interface{}
+    // 返回键值对数量
+    Size() int
+    // 清空所有数据
+    Clear()
+    // 返回统计信息
+    Stats() Stats
+    // 关闭适配器，释放适配器占用的资源
+    Close()
+}
@@ -0,0 +1,390 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/encoding/gmsgpack"
+    "github.com/gogf/gf/g/util/gconv"
+    "github.com/gogf/gf/third/github.com/gomodule/redigo/redis"
+)
+
+// Redis客户端接口(例如: *gredis.Redis)，Redis缓存适配器只依赖该接口执行命令
+type RedisClient interface {
+    Do(command string, args ...interface{}) (interface{}, error)
+}
+
+// Redis缓存适配器配置
+type RedisAdapterConfig struct {
+    // 键名前缀，例如: "cache:"，Data/Keys/Values/Size/Clear操作只处理带有该前缀的键名，
+    // 为空时将处理当前db中的所有键名
+    Prefix      string
+    // 键值的序列化方法，默认使用MessagePack(整数解码为int64/uint64，struct序列化为map)
+    Encoder     func(value interface{}) ([]byte, error)
+    // 键值的反序列化方法，需要与Encoder对应
+    Decoder     func(data []byte) (interface{}, error)
+    // GetOrSetFuncLock的分布式锁超时时间，默认为10秒，锁持有者超过该时间未完成时其他调用者将重新竞争锁，
+    // 锁的键名为 前缀+键名+":gcache-lock"，Data/Keys/Values/Size/Clear操作会忽略锁的键名
+    LockTimeout time.Duration
+    // Redis操作失败时的回调函数，适配器接口不返回错误，操作失败时按照键名不存在处理
+    OnError     func(err error)
+}
+
+// Redis缓存适配器，多个进程使用相同的Redis及键名前缀时共享缓存数据
+type redisAdapter struct {
+    redis  RedisClient
+    config RedisAdapterConfig
+    hits   *gtype.Int64 // 当前进程的命中次数
+    misses *gtype.Int64 // 当前进程的未命中次数
+}
+
+const (
+    gREDIS_DEFAULT_LOCK_TIMEOUT = 10 * time.Second    // 默认的分布式锁超时时间
+    gREDIS_LOCK_WAIT_INTERVAL   = 10 * time.Millisecond // 等待分布式锁的检查间隔
+    gREDIS_SCAN_COUNT           = 1000                  // SCAN命令每次迭代的数量
+    gREDIS_LOCK_SUFFIX          = ":gcache-lock"        // 分布式锁的键名后缀
+    // 释放分布式锁的脚本，只有锁的值与持有者的令牌一致时才删除，避免删除锁超时后被其他调用者获得的锁
+    gREDIS_UNLOCK_SCRIPT        = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+// 创建Redis缓存适配器，redis为Redis客户端(例如: gredis.New(...))，注意Close适配器时不会关闭redis对象
+func NewRedisAdapter(redis RedisClient, config...RedisAdapterConfig) Adapter {
+    a := &redisAdapter {
+        redis  : redis,
+        hits   : gtype.NewInt64(),
+        misses : gtype.NewInt64(),
+    }
+    if len(config) > 0 {
+        a.config = config[0]
+    }
+    if a.config.Encoder == nil {
+        a.config.Encoder = gmsgpack.Encode
+    }
+    if a.config.Decoder == nil {
+        a.config.Decoder = func(data []byte) (interface{}, error) {
+            return gmsgpack.Decode(data)
+        }
+    }
+    if a.config.LockTimeout <= 0 {
+        a.config.LockTimeout = gREDIS_DEFAULT_LOCK_TIMEOUT
+    }
+    return a
+}
+
+func (a *redisAdapter) Set(key interface{}, value interface{}, expire int) {
+    a.set(key, value, expire, false)
+}
+
+func (a *redisAdapter) SetIfNotExist(key interface{}, value interface{}, expire int) bool {
+    return a.set(key, value, expire, true)
+}
+
+func (a *redisAdapter) BatchSet(data map[interface{}]interface{}, expire int) {
+    if expire < 0 {
+        keys := make([]interface{}, 0, len(data))
+        for k := range data {
+            keys = append(keys, k)
+        }
+        a.BatchRemove(keys)
+        return
+    }
+    for k, v := range data {
+        b, err := a.config.Encoder(v)
+        if err != nil {
+            a.error(err)
+            continue
+        }
+        if _, err := a.redis.Do("SET", a.args(k, b, expire, false)...); err != nil {
+            a.error(err)
+            return
+        }
+    }
+}
+
+func (a *redisAdapter) Get(key interface{}) interface{} {
+    v, ok := a.get(key)
+    if ok {
+        a.hits.Add(1)
+    } else {
+        a.misses.Add(1)
+    }
+    return v
+}
+
+func (a *redisAdapter) GetOrSet(key interface{}, value interface{}, expire int) interface{} {
+    if v, ok := a.get(key); ok {
+        return v
+    }
+    if a.set(key, value, expire, true) {
+        return value
+    }
+    // 其他进程已经写入
+    if v, ok := a.get(key); ok {
+        return v
+    }
+    return value
+}
+
+func (a *redisAdapter) GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{} {
+    if v, ok := a.get(key); ok {
+        return v
+    }
+    return a.GetOrSet(key, f(), expire)
+}
+
+// 使用分布式锁(键名为 前缀+键名+":gcache-lock")保证多个进程中只有一个调用者执行f，其他调用者等待执行结果。
+// 锁的值为持有者的随机令牌，释放时只删除自己持有的锁。f返回nil时不写入缓存，等待的调用者将重新竞争锁。
+func (a *redisAdapter) GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{} {
+    lockKey  := a.key(key) + gREDIS_LOCK_SUFFIX
+    token    := newLockToken()
+    deadline := time.Now().Add(a.config.LockTimeout)
+    for {
+        if v, ok := a.get(key); ok {
+            return v
+        }
+        reply, err := a.redis.Do("SET", lockKey, token, "NX", "PX", int64(a.config.LockTimeout / time.Millisecond))
+        if err != nil {
+            a.error(err)
+            return f()
+        }
+        if reply != nil {
+            // 获得锁之后再次检查，避免在获取锁的过程中其他调用者已经完成写入
+            value, ok := a.get(key)
+            if !ok {
+                if value = f(); value != nil {
+                    a.set(key, value, expire, false)
+                }
+            }
+            if _, err := a.redis.Do("EVAL", gREDIS_UNLOCK_SCRIPT, 1, lockKey, token); err != nil {
+                a.error(err)
+            }
+            return value
+        }
+        // 等待锁持有者完成，超时后直接执行f，避免调用者无限阻塞
+        if time.Now().After(deadline) {
+            return f()
+        }
+        time.Sleep(gREDIS_LOCK_WAIT_INTERVAL)
+    }
+}
+
+func (a *redisAdapter) Contains(key interface{}) bool {
+    n, err := redis.Int(a.redis.Do("EXISTS", a.key(key)))
+    if err != nil {
+        a.error(err)
+        return false
+    }
+    return n > 0
+}
+
+func (a *redisAdapter) Remove(key interface{}) interface{} {
+    v, _ := a.get(key)
+    if _, err := a.redis.Do("DEL", a.key(key)); err != nil {
+        a.error(err)
+    }
+    return v
+}
+
+func (a *redisAdapter) BatchRemove(keys []interface{}) {
+    if len(keys) == 0 {
+        return
+    }
+    args := make([]interface{}, len(keys))
+    for i, k := range keys {
+        args[i] = a.key(k)
+    }
+    if _, err := a.redis.Do("DEL", args...); err != nil {
+        a.error(err)
+    }
+}
+
+// 返回所有的键值对，键名为去掉前缀的字符串，注意该操作需要遍历所有键名，不适用于数据量较大的场景
+func (a *redisAdapter) Data() map[interface{}]interface{} {
+    data := make(map[interface{}]interface{})
+    for _, k := range a.scan() {
+        if v, ok := a.get(k); ok {
+            data[k] = v
+        }
+    }
+    return data
+}
+
+func (a *redisAdapter) Keys() []interface{} {
+    keys := a.scan()
+    result := make([]interface{}, len(keys))
+    for i, k := range keys {
+        result[i] = k
+    }
+    return result
+}
+
+func (a *redisAdapter) KeyStrings() []string {
+    return a.scan()
+}
+
+func (a *redisAdapter) Values() []interface{} {
+    values := make([]interface{}, 0)
+    for _, k := range a.scan() {
+        if v, ok := a.get(k); ok {
+            values = append(values, v)
+        }
+    }
+    return values
+}
+
+func (a *redisAdapter) Size() int {
+    return len(a.scan())
+}
+
+// 删除所有带有前缀的键名(前缀为空时删除当前db中的所有键名)
+func (a *redisAdapter) Clear() {
+    keys := a.scan()
+    for len(keys) > 0 {
+        n := len(keys)
+        if n > gREDIS_SCAN_COUNT {
+            n = gREDIS_SCAN_COUNT
+        }
+        args := make([]interface{}, n)
+        for i := 0; i < n; i++ {
+            args[i] = a.config.Prefix + keys[i]
+        }
+        if _, err := a.redis.Do("DEL", args...); err != nil {
+            a.error(err)
+            return
+        }
+        keys = keys[n : ]
+    }
+}
+
+// Redis对象由调用方管理，不需要关闭
+// 命中统计只包含当前进程的Get操作，淘汰及过期由Redis服务端处理，因此不进行统计
+func (a *redisAdapter) Stats() Stats {
+    return Stats {
+        Hits   : a.hits.Val(),
+        Misses : a.misses.Val(),
+        Size   : a.Size(),
+    }
+}
+
+func (a *redisAdapter) Close() {}
+
+// 写入键值，nx为true时只在键名不存在时写入，返回是否写入成功
+func (a *redisAdapter) set(key interface{}, value interface{}, expire int, nx bool) bool {
+    if expire < 0 {
+        if !nx {
+            a.Remove(key)
+        }
+        return false
+    }
+    b, err := a.config.Encoder(value)
+    if err != nil {
+        a.error(err)
+        return false
+    }
+    reply, err := a.redis.Do("SET", a.args(key, b, expire, nx)...)
+    if err != nil {
+        a.error(err)
+        return false
+    }
+    return reply != nil
+}
+
+// 读取并解码键值，键名不存在或者读取失败时返回false
+func (a *redisAdapter) get(key interface{}) (interface{}, bool) {
+    b, err := redis.Bytes(a.redis.Do("GET", a.key(key)))
+    if err != nil {
+        if err != redis.ErrNil {
+            a.error(err)
+        }
+        return nil, false
+    }
+    v, err := a.config.Decoder(b)
+    if err != nil {
+        a.error(err)
+        return nil, false
+    }
+    return v, true
+}
+
+// 生成SET命令的参数
+func (a *redisAdapter) args(key interface{}, value []byte, expire int, nx bool) []interface{} {
+    args := []interface{}{a.key(key), value}
+    if expire > 0 {
+        args = append(args, "PX", expire)
+    }
+    if nx {
+        args = append(args, "NX")
+    }
+    return args
+}
+
+// 生成Redis键名
+func (a *redisAdapter) key(key interface{}) string {
+    return a.config.Prefix + gconv.String(key)
+}
+
+// 遍历所有带有前缀的键名，返回去掉前缀的键名
+func (a *redisAdapter) scan() []string {
+    keys   := make([]string, 0)
+    exists := make(map[string]struct{})
+    cursor := "0"
+    for {
+        values, err := redis.Values(a.redis.Do("SCAN", cursor, "MATCH", escapePattern(a.config.Prefix) + "*", "COUNT", gREDIS_SCAN_COUNT))
+        if err != nil || len(values) != 2 {
+            if err != nil {
+                a.error(err)
+            }
+            return keys
+        }
+        cursor, _ = redis.String(values[0], nil)
+        items, _ := redis.Strings(values[1], nil)
+        // SCAN命令可能返回重复的键名，同时忽略分布式锁的键名
+        for _, item := range items {
+            if strings.HasSuffix(item, gREDIS_LOCK_SUFFIX) {
+                continue
+            }
+            if _, ok := exists[item]; !ok {
+                exists[item] = struct{}{}
+                keys = append(keys, item[len(a.config.Prefix) : ])
+            }
+        }
+        if cursor == "0" {
+            return keys
+        }
+    }
+}
+
+// 生成分布式锁的随机令牌
+func newLockToken() string {
+    b := make([]byte, 16)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+// 处理错误
+func (a *redisAdapter) error(err error) {
+    if a.config.OnError != nil {
+        a.config.OnError(err)
+    }
+}
+
+// 转义SCAN命令MATCH参数中的通配符
+func escapePattern(s string) string {
+    buffer := make([]byte, 0, len(s))
+    for i := 0; i < len(s); i++ {
+        switch s[i] {
+            case '*', '?', '[', ']', '\\':
+                buffer = append(buffer, '\\')
+        }
+        buffer = append(buffer, s[i])
+    }
+    return string(buffer)
+}
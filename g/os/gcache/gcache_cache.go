@@ -8,30 +8,207 @@ package gcache
 
 import (
     "github.com/gogf/gf/g/os/gtimer"
-    "sync/atomic"
+    "sync"
     "time"
-    "unsafe"
 )
 
-// 缓存对象。
-// 底层只有一个缓存对象，如果需要提高并发性能，可新增缓存对象无锁哈希表，用键名做固定分区。
+// 缓存对象，所有操作均通过适配器完成，默认使用进程内的内存缓存适配器。
 type Cache struct {
-    *memCache
+    mu      sync.RWMutex
+    adapter Adapter
+    tags    *cacheTags // 标签索引，分组与其父缓存对象共享
+    parent  *Cache     // 分组所属的缓存对象，非分组时为nil
+    name    string     // 分组名称
 }
 
-// Cache对象按照缓存键名首字母做了分组
+// 淘汰算法
+const (
+    POLICY_LRU = "lru" // 淘汰最久未访问的数据(默认)
+    POLICY_LFU = "lfu" // 淘汰访问次数最少的数据，访问次数相同时淘汰最久未访问的数据
+)
+
+// 数据淘汰原因
+const (
+    EVICT_REASON_CAPACITY = "capacity" // 超过最大键值对数量
+    EVICT_REASON_MEMORY   = "memory"   // 超过内存上限
+    EVICT_REASON_EXPIRED  = "expired"  // 数据过期
+)
+
+// 缓存统计信息
+type Stats struct {
+    Hits        int64 // 命中次数
+    Misses      int64 // 未命中次数
+    Evictions   int64 // 因超过容量或者内存上限被淘汰的数据数量
+    Expirations int64 // 因过期被清理的数据数量
+    Size        int   // 当前键值对数量
+}
+
+// 命中率，没有任何访问时返回0
+func (s Stats) HitRate() float64 {
+    if total := s.Hits + s.Misses; total > 0 {
+        return float64(s.Hits)/float64(total)
+    }
+    return 0
+}
+
+// 内存缓存对象配置
+type Config struct {
+    // 最大键值对数量，超过时在写入时同步按照淘汰算法淘汰数据(默认为0表示不进行限制)
+    MaxEntries int
+    // 近似的内存上限(字节)，缓存项大小通过SizeFunc或者反射估算，超过时在写入时同步淘汰数据(默认为0表示不进行限制)
+    MaxMemory  int64
+    // 淘汰算法，POLICY_LRU(默认)或者POLICY_LFU
+    Policy     string
+    // 自定义的缓存项大小(字节)计算方法，默认通过反射估算
+    SizeFunc   func(key, value interface{}) int64
+    // 数据被淘汰时的回调函数，reason为淘汰原因(EVICT_REASON_*)，回调在写入的goroutine中同步执行
+    OnEvict    func(key, value interface{}, reason string)
+    // 过期数据被清理时的回调函数，reason为EVICT_REASON_EXPIRED，回调在异步的过期清理任务中执行，
+    // 可以用于释放缓存数据关联的资源
+    OnExpire   func(key, value interface{}, reason string)
+    // 数据过期后的保留时间(毫秒)，保留期间GetOrSetFuncLock直接返回旧的键值，并在后台异步刷新数据，
+    // 避免热点数据过期时大量请求同时穿透到数据源(默认为0表示不保留)
+    StaleTime  int
+}
+
+// 创建内存缓存对象，lruCap为可选的LRU缓存池大小，超过大小时异步(每秒)按照LRU算法淘汰数据。
+// 需要同步淘汰、内存上限或者LFU算法时使用NewWithConfig。
 func New(lruCap...int) *Cache {
-    c := &Cache {
-        memCache : newMemCache(lruCap...),
+    config := Config{}
+    if len(lruCap) > 0 {
+        config.Policy = POLICY_LRU
     }
+    return NewWithAdapter(newMemCacheAdapter(config, lruCap...))
+}
+
+// 使用指定配置创建内存缓存对象
+func NewWithConfig(config Config) *Cache {
+    return NewWithAdapter(newMemCacheAdapter(config))
+}
+
+// 使用指定的适配器创建缓存对象
+func NewWithAdapter(adapter Adapter) *Cache {
+    return &Cache {
+        adapter : adapter,
+        tags    : newCacheTags(),
+    }
+}
+
+// 创建内存缓存适配器，并启动异步的过期数据清理任务
+func newMemCacheAdapter(config Config, lruCap...int) *memCache {
+    c := newMemCache(config, lruCap...)
     gtimer.AddSingleton(time.Second, c.syncEventAndClearExpired)
     return c
 }
 
+// 替换缓存对象的适配器，原有适配器中的数据不会迁移，并且原有适配器不会被关闭(由调用方决定是否关闭)
+func (c *Cache) SetAdapter(adapter Adapter) {
+    c.mu.Lock()
+    c.adapter = adapter
+    c.mu.Unlock()
+}
+
+// 获取缓存对象当前使用的适配器
+func (c *Cache) GetAdapter() Adapter {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    return c.adapter
+}
+
+// 设置kv缓存键值对，过期时间单位为**毫秒**
+func (c *Cache) Set(key interface{}, value interface{}, expire int) {
+    c.GetAdapter().Set(key, value, expire)
+}
+
+// 当键名不存在时写入，并返回true；否则返回false。
+func (c *Cache) SetIfNotExist(key interface{}, value interface{}, expire int) bool {
+    return c.GetAdapter().SetIfNotExist(key, value, expire)
+}
+
+// 批量设置kv缓存键值对，过期时间单位为**毫秒**
+func (c *Cache) BatchSet(data map[interface{}]interface{}, expire int) {
+    c.GetAdapter().BatchSet(data, expire)
+}
+
+// 获取指定键名的值
+func (c *Cache) Get(key interface{}) interface{} {
+    return c.GetAdapter().Get(key)
+}
+
+// 当键名存在时返回其键值，否则写入指定的键值
+func (c *Cache) GetOrSet(key interface{}, value interface{}, expire int) interface{} {
+    return c.GetAdapter().GetOrSet(key, value, expire)
+}
+
+// 当键名存在时返回其键值，否则写入指定的键值，键值由指定的函数生成
+func (c *Cache) GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{} {
+    return c.GetAdapter().GetOrSetFunc(key, f, expire)
+}
+
+// 与GetOrSetFunc不同的是，同一键名的f同时只会有一个在执行，其他调用者等待并共享其执行结果
+func (c *Cache) GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{} {
+    return c.GetAdapter().GetOrSetFuncLock(key, f, expire)
+}
+
+// 是否存在指定的键名，true表示存在，false表示不存在。
+func (c *Cache) Contains(key interface{}) bool {
+    return c.GetAdapter().Contains(key)
+}
+
+// 删除指定键值对，并返回被删除的键值
+func (c *Cache) Remove(key interface{}) interface{} {
+    c.tags.removeKey(c.rootKey(key))
+    return c.GetAdapter().Remove(key)
+}
+
+// 批量删除指定键值对
+func (c *Cache) BatchRemove(keys []interface{}) {
+    for _, key := range keys {
+        c.tags.removeKey(c.rootKey(key))
+    }
+    c.GetAdapter().BatchRemove(keys)
+}
+
+// 返回缓存的所有数据键值对(不包含已过期数据)
+func (c *Cache) Data() map[interface{}]interface{} {
+    return c.GetAdapter().Data()
+}
+
+// 获得所有的键名，组成数组返回
+func (c *Cache) Keys() []interface{} {
+    return c.GetAdapter().Keys()
+}
+
+// 获得所有的键名，组成字符串数组返回
+func (c *Cache) KeyStrings() []string {
+    return c.GetAdapter().KeyStrings()
+}
+
+// 获得所有的值，组成数组返回
+func (c *Cache) Values() []interface{} {
+    return c.GetAdapter().Values()
+}
+
+// 获得缓存对象的键值对数量
+func (c *Cache) Size() int {
+    return c.GetAdapter().Size()
+}
+
 // 清空缓存中的所有数据
 func (c *Cache) Clear() {
-    // 使用原子操作替换缓存对象
-    old := atomic.SwapPointer((*unsafe.Pointer)(unsafe.Pointer(&c.memCache)), unsafe.Pointer(newMemCache()))
-    // 关闭旧的缓存对象
-    (*memCache)(old).Close()
-}
\ No newline at end of file
+    c.GetAdapter().Clear()
+    c.tags.removeIf(func(key interface{}) bool {
+        _, ok := c.localKey(key)
+        return ok
+    })
+}
+
+// 获取缓存的统计信息
+func (c *Cache) Stats() Stats {
+    return c.GetAdapter().Stats()
+}
+
+// 关闭缓存对象(关闭底层的适配器)
+func (c *Cache) Close() {
+    c.GetAdapter().Close()
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "reflect"
+    "time"
+    "github.com/gogf/gf/g/encoding/gmsgpack"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/os/gtimer"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 快照文件格式版本，快照内容为MessagePack数组: [版本, [键名, 键名类型, 键值, 过期时间(毫秒时间戳，0表示不过期)]...]，
+// 版本1的数据项不包含键名类型
+const gDUMP_VERSION = 2
+
+// 将缓存数据(包含过期时间)保存到指定的快照文件，写入临时文件后再替换，保证快照文件的完整性。
+// 注意只有内存缓存适配器支持导出；基础类型的键名恢复为原有类型(例如int键名恢复后仍然可以使用Get(1)读取)，
+// 键值使用MessagePack序列化，整数将恢复为int64/uint64，struct将恢复为map。
+func (c *Cache) Dump(path string) error {
+    dir := filepath.Dir(path)
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return err
+    }
+    file, err := ioutil.TempFile(dir, filepath.Base(path) + ".tmp")
+    if err != nil {
+        return err
+    }
+    if err = c.DumpTo(file); err == nil {
+        err = file.Sync()
+    }
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    if err == nil {
+        err = os.Rename(file.Name(), path)
+    }
+    if err != nil {
+        os.Remove(file.Name())
+    }
+    return err
+}
+
+// 将缓存数据(包含过期时间)写入到指定的Writer
+func (c *Cache) DumpTo(writer io.Writer) error {
+    m, ok := c.GetAdapter().(*memCache)
+    if !ok {
+        return errors.New("gcache: dumping is only supported by the memory adapter")
+    }
+    content, err := gmsgpack.Encode([]interface{}{gDUMP_VERSION, m.dump()})
+    if err != nil {
+        return err
+    }
+    _, err = writer.Write(content)
+    return err
+}
+
+// 从指定的快照文件恢复缓存数据，已过期的数据将被忽略，快照文件不存在时不做任何处理
+func (c *Cache) Load(path string) error {
+    file, err := os.Open(path)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil
+        }
+        return err
+    }
+    defer file.Close()
+    return c.LoadFrom(file)
+}
+
+// 从指定的Reader恢复缓存数据，已过期的数据将被忽略，快照中的数据将覆盖缓存中相同键名的数据
+func (c *Cache) LoadFrom(reader io.Reader) error {
+    content, err := ioutil.ReadAll(reader)
+    if err != nil {
+        return err
+    }
+    value, err := gmsgpack.Decode(content)
+    if err != nil {
+        return err
+    }
+    array, ok := value.([]interface{})
+    if !ok || len(array) != 2 {
+        return errors.New("gcache: invalid snapshot content")
+    }
+    // 版本1的数据项为: [键名, 键值, 过期时间]
+    version := gconv.Int(array[0])
+    if version != 1 && version != gDUMP_VERSION {
+        return errors.New(fmt.Sprintf("gcache: unsupported snapshot version: %d", version))
+    }
+    items, _ := array[1].([]interface{})
+    now      := gtime.Millisecond()
+    for _, v := range items {
+        item, ok := v.([]interface{})
+        if !ok || len(item) != 2 + version {
+            return errors.New(fmt.Sprintf("gcache: invalid snapshot item: %v", v))
+        }
+        if version == 1 {
+            item = []interface{}{item[0], "", item[1], item[2]}
+        }
+        expire := 0
+        if e := gconv.Int64(item[3]); e > 0 {
+            if e <= now {
+                continue
+            }
+            expire = int(e - now)
+        }
+        c.Set(restoreDumpKey(item[0], gconv.String(item[1])), item[2], expire)
+    }
+    return nil
+}
+
+// 按照指定的时间间隔定期将缓存数据保存到快照文件，保存失败时调用可选的onError回调函数。
+// 返回定时任务对象，可以通过其Close方法停止定期保存。
+func (c *Cache) AutoDump(path string, interval time.Duration, onError...func(err error)) *gtimer.Entry {
+    return gtimer.AddSingleton(interval, func() {
+        if err := c.Dump(path); err != nil && len(onError) > 0 {
+            onError[0](err)
+        }
+    })
+}
+
+// 导出缓存数据项，过期时间为毫秒时间戳，0表示不过期
+func (c *memCache) dump() []interface{} {
+    items := make([]interface{}, 0)
+    c.dataMu.RLock()
+    for k, v := range c.data {
+        if v.IsExpired() {
+            continue
+        }
+        expire := v.e
+        if expire == gDEFAULT_MAX_EXPIRE {
+            expire = 0
+        }
+        items = append(items, []interface{}{k, getDumpKeyType(k), v.v, expire})
+    }
+    c.dataMu.RUnlock()
+    return items
+}
+
+// 获得键名的类型名称，只记录MessagePack序列化后无法还原的基础类型，其他类型返回空字符串
+func getDumpKeyType(key interface{}) string {
+    switch key.(type) {
+        case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+            return reflect.TypeOf(key).String()
+    }
+    return ""
+}
+
+// 根据键名类型还原键名
+func restoreDumpKey(key interface{}, keyType string) interface{} {
+    if keyType == "" {
+        return key
+    }
+    return gconv.Convert(key, keyType)
+}
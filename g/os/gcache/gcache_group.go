@@ -0,0 +1,193 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+    "strings"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 分组适配器，将分组中的键名转换为带有分组名称的键名后，使用所属缓存对象当前的适配器完成操作
+type groupAdapter struct {
+    cache *Cache // 分组所属的缓存对象
+    name  string // 分组名称
+}
+
+// 分组中的键名在所属缓存对象中的实际键名，
+// 在Redis等使用字符串键名的适配器中表示为: 分组名称:键名
+type groupKey struct {
+    group string
+    key   interface{}
+}
+
+func (k groupKey) String() string {
+    return k.group + ":" + gconv.String(k.key)
+}
+
+// 获取指定名称的缓存分组，分组与当前缓存对象共享数据存储(包括适配器、淘汰算法及统计信息)，
+// 但是分组之间的键名互不影响，分组的Keys/Data/Size/Clear等操作只处理分组内的数据。
+// 分组支持嵌套，例如: cache.Group("user").Group("profile")。
+func (c *Cache) Group(name string) *Cache {
+    return &Cache {
+        adapter : &groupAdapter{cache : c, name : name},
+        tags    : c.tags,
+        parent  : c,
+        name    : name,
+    }
+}
+
+// 获取缓存对象的分组名称，非分组的缓存对象返回空字符串
+func (c *Cache) GroupName() string {
+    return c.name
+}
+
+// 获取键名在顶层缓存对象中的实际键名
+func (c *Cache) rootKey(key interface{}) interface{} {
+    if c.parent == nil {
+        return key
+    }
+    return c.parent.rootKey(groupKey{group : c.name, key : key})
+}
+
+// 将顶层缓存对象中的实际键名转换为当前分组中的键名，不属于当前分组时返回false
+func (c *Cache) localKey(key interface{}) (interface{}, bool) {
+    if c.parent == nil {
+        return key, true
+    }
+    parentKey, ok := c.parent.localKey(key)
+    if !ok {
+        return nil, false
+    }
+    if k, ok := parentKey.(groupKey); ok && k.group == c.name {
+        return k.key, true
+    }
+    return nil, false
+}
+
+func (a *groupAdapter) key(key interface{}) interface{} {
+    return groupKey{group : a.name, key : key}
+}
+
+// 将所属缓存对象中的键名转换为分组中的键名，使用字符串键名的适配器(如Redis)返回的键名为字符串
+func (a *groupAdapter) unwrap(key interface{}) (interface{}, bool) {
+    switch k := key.(type) {
+        case groupKey:
+            if k.group == a.name {
+                return k.key, true
+            }
+        case string:
+            if prefix := a.name + ":"; strings.HasPrefix(k, prefix) {
+                return k[len(prefix) : ], true
+            }
+    }
+    return nil, false
+}
+
+func (a *groupAdapter) Set(key interface{}, value interface{}, expire int) {
+    a.cache.Set(a.key(key), value, expire)
+}
+
+func (a *groupAdapter) SetIfNotExist(key interface{}, value interface{}, expire int) bool {
+    return a.cache.SetIfNotExist(a.key(key), value, expire)
+}
+
+func (a *groupAdapter) BatchSet(data map[interface{}]interface{}, expire int) {
+    m := make(map[interface{}]interface{}, len(data))
+    for k, v := range data {
+        m[a.key(k)] = v
+    }
+    a.cache.BatchSet(m, expire)
+}
+
+func (a *groupAdapter) Get(key interface{}) interface{} {
+    return a.cache.Get(a.key(key))
+}
+
+func (a *groupAdapter) GetOrSet(key interface{}, value interface{}, expire int) interface{} {
+    return a.cache.GetOrSet(a.key(key), value, expire)
+}
+
+func (a *groupAdapter) GetOrSetFunc(key interface{}, f func() interface{}, expire int) interface{} {
+    return a.cache.GetOrSetFunc(a.key(key), f, expire)
+}
+
+func (a *groupAdapter) GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{} {
+    return a.cache.GetOrSetFuncLock(a.key(key), f, expire)
+}
+
+func (a *groupAdapter) Contains(key interface{}) bool {
+    return a.cache.Contains(a.key(key))
+}
+
+func (a *groupAdapter) Remove(key interface{}) interface{} {
+    return a.cache.Remove(a.key(key))
+}
+
+func (a *groupAdapter) BatchRemove(keys []interface{}) {
+    array := make([]interface{}, len(keys))
+    for i, k := range keys {
+        array[i] = a.key(k)
+    }
+    a.cache.BatchRemove(array)
+}
+
+func (a *groupAdapter) Data() map[interface{}]interface{} {
+    m := make(map[interface{}]interface{})
+    for k, v := range a.cache.Data() {
+        if key, ok := a.unwrap(k); ok {
+            m[key] = v
+        }
+    }
+    return m
+}
+
+func (a *groupAdapter) Keys() []interface{} {
+    keys := make([]interface{}, 0)
+    for _, k := range a.cache.Keys() {
+        if key, ok := a.unwrap(k); ok {
+            keys = append(keys, key)
+        }
+    }
+    return keys
+}
+
+func (a *groupAdapter) KeyStrings() []string {
+    return gconv.Strings(a.Keys())
+}
+
+func (a *groupAdapter) Values() []interface{} {
+    values := make([]interface{}, 0)
+    for _, v := range a.Data() {
+        values = append(values, v)
+    }
+    return values
+}
+
+func (a *groupAdapter) Size() int {
+    return len(a.Keys())
+}
+
+// 只删除分组内的数据
+func (a *groupAdapter) Clear() {
+    keys := a.cache.Keys()
+    for i := 0; i < len(keys); {
+        if _, ok := a.unwrap(keys[i]); ok {
+            i++
+        } else {
+            keys = append(keys[ : i], keys[i + 1 : ]...)
+        }
+    }
+    a.cache.BatchRemove(keys)
+}
+
+// 统计信息为所属缓存对象的统计信息
+func (a *groupAdapter) Stats() Stats {
+    return a.cache.Stats()
+}
+
+// 分组不会关闭所属缓存对象的适配器
+func (a *groupAdapter) Close() {}
@@ -24,13 +24,20 @@ type memCache struct {
     expireTimeMu sync.RWMutex
     expireSetMu  sync.RWMutex
 
-    cap          int                            // 控制缓存池大小，超过大小则按照LRU算法进行缓存过期处理(默认为0表示不进行限制)
+    config       Config                         // 缓存配置
+    cap          int                            // 控制缓存池大小，超过大小则异步按照LRU算法进行缓存淘汰处理(默认为0表示不进行限制)
+    memory       int64                          // 缓存项的近似内存占用(只有设置内存上限时才计算)
     data         map[interface{}]memCacheItem   // 缓存数据(所有的缓存数据存放哈希表)
     expireTimes  map[interface{}]int64          // 键名对应的分组过期时间(用于相同键名过期时间快速更新)，键值为1秒级时间戳
     expireSets   map[int64]*gset.Set            // 分组过期时间对应的键名列表(用于自动过期快速删除)，键值为1秒级时间戳
 
-    lru          *memCacheLru                   // LRU缓存限制(只有限定cap池大小时才启用)
-    lruGetList   *glist.List                    // Get操作的LRU记录
+    evictor      memCacheEvictor                // 淘汰算法管理对象(只有限定缓存池大小或者内存上限时才启用)
+    callMu       sync.Mutex
+    calls        map[interface{}]*memCacheCall  // 正在执行的GetOrSetFuncLock键值生成调用
+    hits         *gtype.Int64                   // 命中次数
+    misses       *gtype.Int64                   // 未命中次数
+    evictions    *gtype.Int64                   // 淘汰次数
+    expirations  *gtype.Int64                   // 过期清理次数
     eventList    *glist.List                    // 异步处理队列
     closed       *gtype.Bool                    // 关闭事件通知
 }
@@ -39,6 +46,13 @@ type memCache struct {
 type memCacheItem struct {
     v interface{} // 键值
     e int64       // 过期时间
+    s int64       // 近似内存占用(只有设置内存上限时才计算)
+}
+
+// GetOrSetFuncLock中正在执行的键值生成调用，等待的调用者共享执行结果
+type memCacheCall struct {
+    wg sync.WaitGroup
+    v  interface{}
 }
 
 // 异步队列数据项
@@ -52,19 +66,26 @@ const (
     gDEFAULT_MAX_EXPIRE = 9223372036854
 )
 
-// 创建底层的缓存对象
-func newMemCache(lruCap...int) *memCache {
+// 创建底层的缓存对象，lruCap为异步淘汰的缓存池大小
+func newMemCache(config Config, lruCap...int) *memCache {
     c := &memCache {
-        lruGetList  : glist.New(),
+        config      : config,
         data        : make(map[interface{}]memCacheItem),
         expireTimes : make(map[interface{}]int64),
         expireSets  : make(map[int64]*gset.Set),
+        calls       : make(map[interface{}]*memCacheCall),
+        hits        : gtype.NewInt64(),
+        misses      : gtype.NewInt64(),
+        evictions   : gtype.NewInt64(),
+        expirations : gtype.NewInt64(),
         eventList   : glist.New(),
         closed      : gtype.NewBool(),
     }
     if len(lruCap) > 0 {
         c.cap = lruCap[0]
-        c.lru = newMemCacheLru(c)
+    }
+    if c.cap > 0 || config.MaxEntries > 0 || config.MaxMemory > 0 {
+        c.evictor = newMemCacheEvictor(config.Policy)
     }
     return c
 }
@@ -102,9 +123,93 @@ func (c *memCache) getOrNewExpireSet(expire int64) (expireSet *gset.Set) {
 func (c *memCache) Set(key interface{}, value interface{}, expire int) {
     expireTime := c.getInternalExpire(expire)
     c.dataMu.Lock()
-    c.data[key] = memCacheItem{v : value, e : expireTime}
+    c.doSet(key, value, expireTime)
     c.dataMu.Unlock()
+    c.afterSet(key, expireTime)
+}
+
+// 写入缓存项，调用时需要持有dataMu写锁
+func (c *memCache) doSet(key interface{}, value interface{}, expireTime int64) {
+    item := memCacheItem{v : value, e : expireTime}
+    if c.config.MaxMemory > 0 {
+        if c.config.SizeFunc != nil {
+            item.s = c.config.SizeFunc(key, value)
+        } else {
+            item.s = estimateSize(key, value)
+        }
+        if old, ok := c.data[key]; ok {
+            c.memory -= old.s
+        }
+        c.memory += item.s
+    }
+    c.data[key] = item
+}
+
+// 删除缓存项，调用时需要持有dataMu写锁
+func (c *memCache) doRemove(key interface{}) (item memCacheItem, ok bool) {
+    if item, ok = c.data[key]; ok {
+        c.memory -= item.s
+        delete(c.data, key)
+    }
+    return
+}
+
+// 写入缓存项之后的处理：记录过期事件，记录淘汰算法的访问，并在超过限制时同步淘汰数据
+func (c *memCache) afterSet(key interface{}, expireTime int64) {
+    // 开启过期数据保留时，数据在保留时间结束后才被清理
+    if c.config.StaleTime > 0 && expireTime != gDEFAULT_MAX_EXPIRE && expireTime >= gtime.Millisecond() {
+        expireTime += int64(c.config.StaleTime)
+    }
     c.eventList.PushBack(&memCacheEvent{k : key, e : expireTime})
+    if c.evictor != nil {
+        c.evictor.Touch(key)
+        if c.config.MaxEntries > 0 || c.config.MaxMemory > 0 {
+            c.evict(key)
+        }
+    }
+}
+
+// 按照淘汰算法淘汰数据，直到满足配置的键值对数量及内存限制，exclude为刚写入的键名(尽量不淘汰)
+func (c *memCache) evict(exclude interface{}) {
+    for {
+        reason := ""
+        c.dataMu.RLock()
+        switch {
+            case c.config.MaxEntries > 0 && len(c.data) > c.config.MaxEntries:
+                reason = EVICT_REASON_CAPACITY
+            case c.config.MaxMemory > 0 && c.memory > c.config.MaxMemory:
+                reason = EVICT_REASON_MEMORY
+        }
+        c.dataMu.RUnlock()
+        if reason == "" || !c.evictOne(reason, exclude) {
+            return
+        }
+    }
+}
+
+// 淘汰一个数据项，没有可以淘汰的数据时返回false
+func (c *memCache) evictOne(reason string, exclude...interface{}) bool {
+    var excludeKey interface{}
+    if len(exclude) > 0 {
+        excludeKey = exclude[0]
+    }
+    key, ok := c.evictor.Victim(excludeKey)
+    if !ok {
+        return false
+    }
+    c.dataMu.Lock()
+    item, ok := c.doRemove(key)
+    c.dataMu.Unlock()
+    c.expireTimeMu.Lock()
+    delete(c.expireTimes, key)
+    c.expireTimeMu.Unlock()
+    if ok {
+        c.evictions.Add(1)
+        if c.config.OnEvict != nil {
+            c.config.OnEvict(key, item.v, reason)
+        }
+    }
+    return true
 }
 
 // 设置kv缓存键值对，内部会对键名的存在性使用写锁进行二次检索确认，如果存在则不再写入；返回键名对应的键值。
@@ -119,9 +224,9 @@ func (c *memCache) doSetWithLockCheck(key interface{}, value interface{}, expire
     if f, ok := value.(func() interface {}); ok {
         value = f()
     }
-    c.data[key] = memCacheItem{v : value, e : expireTimestamp}
+    c.doSet(key, value, expireTimestamp)
     c.dataMu.Unlock()
-    c.eventList.PushBack(&memCacheEvent{k : key, e : expireTimestamp})
+    c.afterSet(key, expireTimestamp)
     return value
 }
 
@@ -148,21 +253,32 @@ func (c *memCache) BatchSet(data map[interface{}]interface{}, expire int) {
     expireTime := c.getInternalExpire(expire)
     for k, v := range data {
         c.dataMu.Lock()
-        c.data[k] = memCacheItem{v: v, e: expireTime}
+        c.doSet(k, v, expireTime)
         c.dataMu.Unlock()
-        c.eventList.PushBack(&memCacheEvent{k: k, e: expireTime})
+        c.afterSet(k, expireTime)
     }
 }
 
 // 获取指定键名的值
 func (c *memCache) Get(key interface{}) interface{} {
+    v := c.get(key)
+    if v != nil {
+        c.hits.Add(1)
+    } else {
+        c.misses.Add(1)
+    }
+    return v
+}
+
+// 获取指定键名的值，不计入命中统计
+func (c *memCache) get(key interface{}) interface{} {
     c.dataMu.RLock()
     item, ok := c.data[key]
     c.dataMu.RUnlock()
     if ok && !item.IsExpired() {
-        // 增加LRU(Least Recently Used)操作记录
-        if c.cap > 0 {
-            c.lruGetList.PushBack(key)
+        // 记录淘汰算法的访问
+        if c.evictor != nil {
+            c.evictor.Touch(key)
         }
         return item.v
     }
@@ -188,30 +304,94 @@ func (c *memCache) GetOrSetFunc(key interface{}, f func() interface{}, expire in
     }
 }
 
-// 与GetOrSetFunc不同的是，f是在写锁机制内执行
+// 与GetOrSetFunc不同的是，同一键名同时只会有一个f在执行，其他调用者等待并共享其执行结果，
+// f的执行不会阻塞其他键名的读写。
+// 配置了StaleTime时，数据过期后的保留时间内直接返回旧的键值，并在后台异步执行f刷新数据。
 func (c *memCache) GetOrSetFuncLock(key interface{}, f func() interface{}, expire int) interface{} {
-    if v := c.Get(key); v == nil {
-        return c.doSetWithLockCheck(key, f, expire)
-    } else {
+    if v := c.Get(key); v != nil {
         return v
     }
+    if c.config.StaleTime > 0 {
+        c.dataMu.RLock()
+        item, ok := c.data[key]
+        c.dataMu.RUnlock()
+        if ok && !c.isStaleExpired(&item) {
+            c.refresh(key, f, expire)
+            return item.v
+        }
+    }
+    return c.load(key, f, expire)
+}
+
+// 执行f生成键值并写入缓存，同一键名同时只会有一个f在执行
+func (c *memCache) load(key interface{}, f func() interface{}, expire int) interface{} {
+    c.callMu.Lock()
+    if call, ok := c.calls[key]; ok {
+        c.callMu.Unlock()
+        call.wg.Wait()
+        return call.v
+    }
+    call := new(memCacheCall)
+    call.wg.Add(1)
+    c.calls[key] = call
+    c.callMu.Unlock()
+    // f产生panic时也需要唤醒等待的调用者
+    defer func() {
+        c.callMu.Lock()
+        delete(c.calls, key)
+        c.callMu.Unlock()
+        call.wg.Done()
+    }()
+    // 二次检索，其他调用者可能已经完成了写入
+    if v := c.get(key); v != nil {
+        call.v = v
+        return v
+    }
+    call.v = f()
+    c.Set(key, call.v, expire)
+    return call.v
+}
+
+// 在后台异步执行f刷新过期的数据，已经有调用在执行时不重复刷新
+func (c *memCache) refresh(key interface{}, f func() interface{}, expire int) {
+    c.callMu.Lock()
+    _, ok := c.calls[key]
+    c.callMu.Unlock()
+    if ok {
+        return
+    }
+    go func() {
+        // 刷新失败时保留旧的数据，直到保留时间结束
+        defer func() {
+            recover()
+        }()
+        c.load(key, f, expire)
+    }()
+}
+
+// 判断缓存项是否已经超过了过期后的保留时间
+func (c *memCache) isStaleExpired(item *memCacheItem) bool {
+    if !item.IsExpired() {
+        return false
+    }
+    return item.e + int64(c.config.StaleTime) < gtime.Millisecond()
 }
 
 // 是否存在指定的键名，true表示存在，false表示不存在。
 func (c *memCache) Contains(key interface{}) bool {
-    return c.Get(key) != nil
+    return c.get(key) != nil
 }
 
 // 删除指定键值对，并返回被删除的键值
 func (c *memCache) Remove(key interface{}) (value interface{}) {
-    c.dataMu.RLock()
-    item, ok := c.data[key]
-    c.dataMu.RUnlock()
+    c.dataMu.Lock()
+    item, ok := c.doRemove(key)
+    c.dataMu.Unlock()
     if ok {
         value = item.v
-        c.dataMu.Lock()
-        delete(c.data, key)
-        c.dataMu.Unlock()
+        if c.evictor != nil {
+            c.evictor.Remove(key)
+        }
         c.eventList.PushBack(&memCacheEvent{k: key, e: gtime.Millisecond() - 1000})
     }
     return
@@ -276,11 +456,36 @@ func (c *memCache) Size() (size int) {
     return
 }
 
+// 清空缓存中的所有数据
+func (c *memCache) Clear() {
+    c.dataMu.Lock()
+    c.data   = make(map[interface{}]memCacheItem)
+    c.memory = 0
+    if c.evictor != nil {
+        c.evictor.Clear()
+    }
+    c.dataMu.Unlock()
+    c.expireTimeMu.Lock()
+    c.expireTimes = make(map[interface{}]int64)
+    c.expireTimeMu.Unlock()
+    c.expireSetMu.Lock()
+    c.expireSets = make(map[int64]*gset.Set)
+    c.expireSetMu.Unlock()
+}
+
+// 获取缓存的统计信息
+func (c *memCache) Stats() Stats {
+    return Stats {
+        Hits        : c.hits.Val(),
+        Misses      : c.misses.Val(),
+        Evictions   : c.evictions.Val(),
+        Expirations : c.expirations.Val(),
+        Size        : c.Size(),
+    }
+}
+
 // 删除缓存对象
 func (c *memCache) Close()  {
-    if c.cap > 0 {
-        c.lru.Close()
-    }
     c.closed.Set(true)
 }
 
@@ -320,20 +525,10 @@ func (c *memCache) syncEventAndClearExpired() {
             c.expireTimes[event.k] = newExpireTime
             c.expireTimeMu.Unlock()
         }
-        // 写入操作也会增加到LRU(Least Recently Used)操作记录
-        if c.cap > 0 {
-            c.lru.Push(event.k)
-        }
     }
-    // 异步处理读取操作的LRU列表
-    if c.cap > 0 && c.lruGetList.Len() > 0 {
-        for {
-            if v := c.lruGetList.PopFront(); v != nil {
-                c.lru.Push(v)
-            } else {
-                break
-            }
-        }
+    // 异步淘汰超过缓存池大小的数据
+    if c.cap > 0 {
+        for c.Size() > c.cap && c.evictOne(EVICT_REASON_CAPACITY) {}
     }
     // ========================
     // 缓存过期处理
@@ -360,8 +555,14 @@ func (c *memCache) clearByKey(key interface{}, force...bool) {
     // 删除缓存数据
     c.dataMu.Lock()
     // 删除核对，真正的过期才删除
-    if item, ok := c.data[key]; (ok && item.IsExpired()) || (len(force) > 0 && force[0]) {
-        delete(c.data, key)
+    removed := false
+    expired := false
+    item, ok := c.data[key]
+    if ok && c.isStaleExpired(&item) {
+        _, removed = c.doRemove(key)
+        expired    = removed
+    } else if len(force) > 0 && force[0] {
+        _, removed = c.doRemove(key)
     }
     c.dataMu.Unlock()
 
@@ -370,8 +571,15 @@ func (c *memCache) clearByKey(key interface{}, force...bool) {
     delete(c.expireTimes, key)
     c.expireTimeMu.Unlock()
 
-    // 删除LRU管理对象中指定键名
-    if c.cap > 0 {
-        c.lru.Remove(key)
+    // 删除淘汰算法管理对象中指定键名
+    if removed && c.evictor != nil {
+        c.evictor.Remove(key)
+    }
+
+    if expired {
+        c.expirations.Add(1)
+        if c.config.OnExpire != nil {
+            c.config.OnExpire(key, item.v, EVICT_REASON_EXPIRED)
+        }
     }
 }
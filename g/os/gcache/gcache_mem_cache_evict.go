@@ -0,0 +1,259 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+    "container/heap"
+    "container/list"
+    "reflect"
+    "sync"
+)
+
+// 淘汰算法管理对象，记录键名的访问情况并选出需要淘汰的键名，内部并发安全
+type memCacheEvictor interface {
+    Touch(key interface{})  // 记录键名的写入/访问(不存在时添加)
+    Remove(key interface{}) // 删除键名
+    Clear()                 // 清空记录
+    Victim(exclude interface{}) (key interface{}, ok bool) // 选出需要淘汰的键名并从记录中删除，尽量不选择exclude(刚写入的键名)
+}
+
+// 创建淘汰算法管理对象
+func newMemCacheEvictor(policy string) memCacheEvictor {
+    if policy == POLICY_LFU {
+        return newMemCacheLfu()
+    }
+    return newMemCacheLru()
+}
+
+// LRU(Least Recently Used)算法，链表头为最近访问的键名
+type memCacheLru struct {
+    mu   sync.Mutex
+    list *list.List
+    data map[interface{}]*list.Element
+}
+
+func newMemCacheLru() *memCacheLru {
+    return &memCacheLru {
+        list : list.New(),
+        data : make(map[interface{}]*list.Element),
+    }
+}
+
+func (lru *memCacheLru) Touch(key interface{}) {
+    lru.mu.Lock()
+    if e, ok := lru.data[key]; ok {
+        lru.list.MoveToFront(e)
+    } else {
+        lru.data[key] = lru.list.PushFront(key)
+    }
+    lru.mu.Unlock()
+}
+
+func (lru *memCacheLru) Remove(key interface{}) {
+    lru.mu.Lock()
+    if e, ok := lru.data[key]; ok {
+        lru.list.Remove(e)
+        delete(lru.data, key)
+    }
+    lru.mu.Unlock()
+}
+
+func (lru *memCacheLru) Clear() {
+    lru.mu.Lock()
+    lru.list.Init()
+    lru.data = make(map[interface{}]*list.Element)
+    lru.mu.Unlock()
+}
+
+func (lru *memCacheLru) Victim(exclude interface{}) (interface{}, bool) {
+    lru.mu.Lock()
+    defer lru.mu.Unlock()
+    e := lru.list.Back()
+    if e != nil && e.Value == exclude && e.Prev() != nil {
+        e = e.Prev()
+    }
+    if e != nil {
+        lru.list.Remove(e)
+        delete(lru.data, e.Value)
+        return e.Value, true
+    }
+    return nil, false
+}
+
+// LFU(Least Frequently Used)算法，访问次数相同时淘汰最久未访问的键名
+type memCacheLfu struct {
+    mu    sync.Mutex
+    seq   uint64
+    heap  memCacheLfuHeap
+    data  map[interface{}]*memCacheLfuItem
+}
+
+// LFU记录项
+type memCacheLfuItem struct {
+    key   interface{}
+    count uint64 // 访问次数
+    seq   uint64 // 最后访问序号
+    index int    // 在堆中的索引
+}
+
+func newMemCacheLfu() *memCacheLfu {
+    return &memCacheLfu {
+        heap : make(memCacheLfuHeap, 0),
+        data : make(map[interface{}]*memCacheLfuItem),
+    }
+}
+
+func (lfu *memCacheLfu) Touch(key interface{}) {
+    lfu.mu.Lock()
+    lfu.seq++
+    if item, ok := lfu.data[key]; ok {
+        item.count++
+        item.seq = lfu.seq
+        heap.Fix(&lfu.heap, item.index)
+    } else {
+        item = &memCacheLfuItem{key : key, count : 1, seq : lfu.seq}
+        lfu.data[key] = item
+        heap.Push(&lfu.heap, item)
+    }
+    lfu.mu.Unlock()
+}
+
+func (lfu *memCacheLfu) Remove(key interface{}) {
+    lfu.mu.Lock()
+    if item, ok := lfu.data[key]; ok {
+        heap.Remove(&lfu.heap, item.index)
+        delete(lfu.data, key)
+    }
+    lfu.mu.Unlock()
+}
+
+func (lfu *memCacheLfu) Clear() {
+    lfu.mu.Lock()
+    lfu.heap = make(memCacheLfuHeap, 0)
+    lfu.data = make(map[interface{}]*memCacheLfuItem)
+    lfu.mu.Unlock()
+}
+
+func (lfu *memCacheLfu) Victim(exclude interface{}) (interface{}, bool) {
+    lfu.mu.Lock()
+    defer lfu.mu.Unlock()
+    if len(lfu.heap) == 0 {
+        return nil, false
+    }
+    item := heap.Pop(&lfu.heap).(*memCacheLfuItem)
+    // 新写入的键名访问次数最少，避免刚写入就被淘汰
+    if item.key == exclude && len(lfu.heap) > 0 {
+        next := heap.Pop(&lfu.heap).(*memCacheLfuItem)
+        heap.Push(&lfu.heap, item)
+        item  = next
+    }
+    delete(lfu.data, item.key)
+    return item.key, true
+}
+
+// LFU最小堆，实现heap.Interface
+type memCacheLfuHeap []*memCacheLfuItem
+
+func (h memCacheLfuHeap) Len() int {
+    return len(h)
+}
+
+func (h memCacheLfuHeap) Less(i, j int) bool {
+    if h[i].count != h[j].count {
+        return h[i].count < h[j].count
+    }
+    return h[i].seq < h[j].seq
+}
+
+func (h memCacheLfuHeap) Swap(i, j int) {
+    h[i], h[j] = h[j], h[i]
+    h[i].index = i
+    h[j].index = j
+}
+
+func (h *memCacheLfuHeap) Push(x interface{}) {
+    item      := x.(*memCacheLfuItem)
+    item.index = len(*h)
+    *h         = append(*h, item)
+}
+
+func (h *memCacheLfuHeap) Pop() interface{} {
+    old  := *h
+    item := old[len(old) - 1]
+    old[len(old) - 1] = nil
+    *h    = old[ : len(old) - 1]
+    return item
+}
+
+// 每个缓存项的固定内存开销估算(哈希表项、过期时间记录、淘汰算法记录等)
+const gITEM_OVERHEAD = 96
+
+// 估算缓存项占用的内存大小(字节)，只用于内存上限控制，结果为近似值
+func estimateSize(key, value interface{}) int64 {
+    return gITEM_OVERHEAD + sizeOfValue(reflect.ValueOf(key), 0) + sizeOfValue(reflect.ValueOf(value), 0)
+}
+
+// 递归估算变量占用的内存大小，为避免过高的计算开销，最多递归3层，更深的层级按照指针大小计算
+func sizeOfValue(v reflect.Value, depth int) int64 {
+    if !v.IsValid() {
+        return 0
+    }
+    switch v.Kind() {
+        case reflect.String:
+            return int64(v.Type().Size()) + int64(v.Len())
+        case reflect.Ptr, reflect.Interface:
+            if v.IsNil() || depth >= 3 {
+                return int64(v.Type().Size())
+            }
+            return int64(v.Type().Size()) + sizeOfValue(v.Elem(), depth + 1)
+        case reflect.Slice, reflect.Array:
+            size := int64(v.Type().Size())
+            elem := v.Type().Elem()
+            if v.Kind() == reflect.Slice {
+                size += int64(v.Cap()) * int64(elem.Size())
+            }
+            if depth < 3 && isIndirectKind(elem.Kind()) {
+                for i := 0; i < v.Len(); i++ {
+                    size += sizeOfValue(v.Index(i), depth + 1) - int64(elem.Size())
+                }
+            }
+            return size
+        case reflect.Map:
+            size := int64(v.Type().Size())
+            if v.IsNil() {
+                return size
+            }
+            if depth >= 3 {
+                return size + int64(v.Len()) * int64(v.Type().Key().Size() + v.Type().Elem().Size())
+            }
+            iterator := v.MapRange()
+            for iterator.Next() {
+                size += sizeOfValue(iterator.Key(), depth + 1) + sizeOfValue(iterator.Value(), depth + 1)
+            }
+            return size
+        case reflect.Struct:
+            size := int64(v.Type().Size())
+            if depth < 3 {
+                for i := 0; i < v.NumField(); i++ {
+                    if f := v.Field(i); isIndirectKind(f.Kind()) {
+                        size += sizeOfValue(f, depth + 1) - int64(f.Type().Size())
+                    }
+                }
+            }
+            return size
+    }
+    return int64(v.Type().Size())
+}
+
+// 判断类型是否引用了额外的内存
+func isIndirectKind(kind reflect.Kind) bool {
+    switch kind {
+        case reflect.String, reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Struct, reflect.Array:
+            return true
+    }
+    return false
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+    "sync"
+)
+
+// 标签索引清理的最小键名数量
+const gTAGS_PRUNE_SIZE = 1024
+
+// 标签索引，记录标签与顶层缓存对象中实际键名的对应关系。
+// 数据过期或者被淘汰时不会通知标签索引，因此在读取标签时以及索引的键名数量增长到上次清理后的两倍时，
+// 惰性地清理已经不存在的键名的标签。
+type cacheTags struct {
+    mu        sync.Mutex
+    tagKeys   map[string]map[interface{}]struct{} // 标签对应的键名
+    keyTags   map[interface{}]map[string]struct{} // 键名对应的标签
+    pruneSize int                                 // 键名数量超过该值时清理不存在的键名
+}
+
+func newCacheTags() *cacheTags {
+    return &cacheTags {
+        tagKeys   : make(map[string]map[interface{}]struct{}),
+        keyTags   : make(map[interface{}]map[string]struct{}),
+        pruneSize : gTAGS_PRUNE_SIZE,
+    }
+}
+
+// 设置kv缓存键值对，并为其添加标签，之后可以通过RemoveByTag删除带有指定标签的所有数据。
+// 再次调用SetWithTags时键名的标签将被替换，通过Set写入时标签保持不变；
+// 注意标签索引保存在当前进程中，使用共享的缓存适配器(如Redis)时其他进程写入的数据不会被索引。
+func (c *Cache) SetWithTags(key interface{}, value interface{}, expire int, tags...string) {
+    c.Set(key, value, expire)
+    if c.tags.set(c.rootKey(key), tags) {
+        c.tags.prune(c.root().Contains)
+    }
+}
+
+// 获取指定键名的标签，键名已经不存在(例如已过期或者被淘汰)时返回空数组
+func (c *Cache) GetTags(key interface{}) []string {
+    key = c.rootKey(key)
+    if !c.root().Contains(key) {
+        c.tags.removeKey(key)
+    }
+    return c.tags.get(key)
+}
+
+// 删除带有指定标签(任意一个)的所有数据，返回删除的键名数量(不包括已经过期或者被淘汰的数据)。
+// 分组只删除分组内的数据，顶层缓存对象将删除所有分组中的数据。
+func (c *Cache) RemoveByTag(tags...string) int {
+    count := 0
+    keys  := make([]interface{}, 0)
+    for _, key := range c.tags.keys(tags) {
+        if k, ok := c.localKey(key); ok {
+            if c.Contains(k) {
+                count++
+            }
+            // 已经不存在的键名也需要删除，以便清理其标签
+            keys = append(keys, k)
+        }
+    }
+    c.BatchRemove(keys)
+    return count
+}
+
+// 获取顶层缓存对象
+func (c *Cache) root() *Cache {
+    if c.parent == nil {
+        return c
+    }
+    return c.parent.root()
+}
+
+// 设置键名的标签，替换原有的标签，返回索引的键名数量是否已经达到需要清理的数量
+func (t *cacheTags) set(key interface{}, tags []string) bool {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.doRemoveKey(key)
+    if len(tags) == 0 {
+        return false
+    }
+    m := make(map[string]struct{}, len(tags))
+    for _, tag := range tags {
+        m[tag] = struct{}{}
+        if _, ok := t.tagKeys[tag]; !ok {
+            t.tagKeys[tag] = make(map[interface{}]struct{})
+        }
+        t.tagKeys[tag][key] = struct{}{}
+    }
+    t.keyTags[key] = m
+    return len(t.keyTags) > t.pruneSize
+}
+
+// 清理已经不存在的键名的标签，contains判断顶层缓存对象中是否存在指定的键名
+func (t *cacheTags) prune(contains func(key interface{}) bool) {
+    t.removeIf(func(key interface{}) bool {
+        return !contains(key)
+    })
+    t.mu.Lock()
+    if t.pruneSize = 2*len(t.keyTags); t.pruneSize < gTAGS_PRUNE_SIZE {
+        t.pruneSize = gTAGS_PRUNE_SIZE
+    }
+    t.mu.Unlock()
+}
+
+func (t *cacheTags) get(key interface{}) []string {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    tags := make([]string, 0, len(t.keyTags[key]))
+    for tag := range t.keyTags[key] {
+        tags = append(tags, tag)
+    }
+    return tags
+}
+
+// 获取带有指定标签(任意一个)的键名
+func (t *cacheTags) keys(tags []string) []interface{} {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    m := make(map[interface{}]struct{})
+    for _, tag := range tags {
+        for key := range t.tagKeys[tag] {
+            m[key] = struct{}{}
+        }
+    }
+    keys := make([]interface{}, 0, len(m))
+    for key := range m {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+func (t *cacheTags) removeKey(key interface{}) {
+    t.mu.Lock()
+    t.doRemoveKey(key)
+    t.mu.Unlock()
+}
+
+// 删除满足条件的键名的标签
+func (t *cacheTags) removeIf(f func(key interface{}) bool) {
+    t.mu.Lock()
+    for key := range t.keyTags {
+        if f(key) {
+            t.doRemoveKey(key)
+        }
+    }
+    t.mu.Unlock()
+}
+
+// 删除键名的标签，调用时需要持有写锁
+func (t *cacheTags) doRemoveKey(key interface{}) {
+    for tag := range t.keyTags[key] {
+        if keys, ok := t.tagKeys[tag]; ok {
+            delete(keys, key)
+            if len(keys) == 0 {
+                delete(t.tagKeys, tag)
+            }
+        }
+    }
+    delete(t.keyTags, key)
+}
@@ -0,0 +1,329 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gcache_test
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/encoding/gmsgpack"
+    "github.com/gogf/gf/g/os/gcache"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+func TestCache_MaxEntries_LRU(t *testing.T) {
+    gtest.Case(t, func() {
+        evicted := make(map[interface{}]string)
+        cache   := gcache.NewWithConfig(gcache.Config {
+            MaxEntries : 3,
+            OnEvict    : func(key, value interface{}, reason string) {
+                evicted[key] = reason
+            },
+        })
+        for i := 0; i < 3; i++ {
+            cache.Set(i, i, 0)
+        }
+        // 访问0之后，最久未访问的为1
+        gtest.Assert(cache.Get(0), 0)
+        cache.Set(3, 3, 0)
+        gtest.Assert(cache.Size(), 3)
+        gtest.Assert(cache.Get(1), nil)
+        gtest.Assert(cache.Get(0), 0)
+        gtest.Assert(evicted, map[interface{}]string{1 : gcache.EVICT_REASON_CAPACITY})
+        // 删除的数据不会被淘汰
+        cache.Remove(2)
+        cache.Set(4, 4, 0)
+        gtest.Assert(cache.Size(), 3)
+        gtest.Assert(len(evicted), 1)
+    })
+}
+
+func TestCache_MaxEntries_LFU(t *testing.T) {
+    gtest.Case(t, func() {
+        cache := gcache.NewWithConfig(gcache.Config {
+            MaxEntries : 2,
+            Policy     : gcache.POLICY_LFU,
+        })
+        cache.Set(1, 1, 0)
+        cache.Set(2, 2, 0)
+        cache.Get(1)
+        cache.Get(1)
+        cache.Get(2)
+        // 2的访问次数少于1，刚写入的3不会被淘汰
+        cache.Set(3, 3, 0)
+        gtest.Assert(cache.Get(2), nil)
+        gtest.Assert(cache.Get(1), 1)
+        gtest.Assert(cache.Get(3), 3)
+    })
+}
+
+func TestCache_MaxMemory(t *testing.T) {
+    gtest.Case(t, func() {
+        reasons := make([]string, 0)
+        cache   := gcache.NewWithConfig(gcache.Config {
+            MaxMemory : 10 * 1024,
+            OnEvict   : func(key, value interface{}, reason string) {
+                reasons = append(reasons, reason)
+            },
+        })
+        value := strings.Repeat("x", 1024)
+        for i := 0; i < 20; i++ {
+            cache.Set(i, value, 0)
+        }
+        gtest.AssertLT(cache.Size(), 10)
+        gtest.AssertGT(cache.Size(), 5)
+        gtest.Assert(cache.Get(19), value)
+        gtest.Assert(cache.Get(0), nil)
+        gtest.Assert(reasons[0], gcache.EVICT_REASON_MEMORY)
+        // 自定义大小计算
+        cache = gcache.NewWithConfig(gcache.Config {
+            MaxMemory : 100,
+            SizeFunc  : func(key, value interface{}) int64 {
+                return int64(len(value.(string)))
+            },
+        })
+        cache.Set(1, strings.Repeat("x", 60), 0)
+        cache.Set(2, strings.Repeat("x", 30), 0)
+        gtest.Assert(cache.Size(), 2)
+        // 覆盖写入时重新计算大小
+        cache.Set(2, strings.Repeat("x", 41), 0)
+        gtest.Assert(cache.Size(), 1)
+        gtest.Assert(cache.Contains(1), false)
+        cache.Clear()
+        cache.Set(3, strings.Repeat("x", 60), 0)
+        cache.Set(4, strings.Repeat("x", 60), 0)
+        gtest.Assert(cache.Size(), 1)
+    })
+}
+
+func TestCache_GetOrSetFuncLock_SingleFlight(t *testing.T) {
+    gtest.Case(t, func() {
+        cache := gcache.New()
+        count := int32(0)
+        wg    := sync.WaitGroup{}
+        for i := 0; i < 100; i++ {
+            wg.Add(1)
+            go func() {
+                defer wg.Done()
+                v := cache.GetOrSetFuncLock("key", func() interface{} {
+                    atomic.AddInt32(&count, 1)
+                    time.Sleep(100 * time.Millisecond)
+                    return "value"
+                }, 0)
+                gtest.Assert(v, "value")
+            }()
+        }
+        // f执行期间不阻塞其他键名的读写
+        time.Sleep(10 * time.Millisecond)
+        cache.Set("other", 1, 0)
+        gtest.Assert(cache.Get("other"), 1)
+        wg.Wait()
+        gtest.Assert(atomic.LoadInt32(&count), 1)
+    })
+}
+
+func TestCache_StaleTime(t *testing.T) {
+    gtest.Case(t, func() {
+        cache := gcache.NewWithConfig(gcache.Config{StaleTime : 2000})
+        count := int32(0)
+        f     := func() interface{} {
+            return atomic.AddInt32(&count, 1)
+        }
+        gtest.Assert(cache.GetOrSetFuncLock("key", f, 100), 1)
+        time.Sleep(200 * time.Millisecond)
+        // 过期后返回旧值，并在后台刷新
+        gtest.Assert(cache.Get("key"), nil)
+        gtest.Assert(cache.GetOrSetFuncLock("key", f, 100), 1)
+        time.Sleep(50 * time.Millisecond)
+        gtest.Assert(cache.GetOrSetFuncLock("key", f, 100), 2)
+        gtest.Assert(atomic.LoadInt32(&count), 2)
+    })
+}
+
+func TestCache_DumpLoad(t *testing.T) {
+    gtest.Case(t, func() {
+        path  := filepath.Join(os.TempDir(), "gcache_dump_test", "cache.snapshot")
+        defer os.RemoveAll(filepath.Dir(path))
+        cache := gcache.New()
+        cache.Set("k1", "v1", 0)
+        cache.Set("k2", []interface{}{"a", "b"}, 2000)
+        cache.Set("k3", 3, 100)
+        gtest.Assert(cache.Dump(path), nil)
+
+        time.Sleep(200 * time.Millisecond)
+        loaded := gcache.New()
+        gtest.Assert(loaded.Load(path), nil)
+        gtest.Assert(loaded.Get("k1"), "v1")
+        gtest.Assert(loaded.Get("k2"), []interface{}{"a", "b"})
+        // 已过期的数据不会被恢复
+        gtest.Assert(loaded.Contains("k3"), false)
+        gtest.Assert(loaded.Size(), 2)
+        // 过期时间被保留
+        time.Sleep(2000 * time.Millisecond)
+        gtest.Assert(loaded.Get("k2"), nil)
+        gtest.Assert(loaded.Get("k1"), "v1")
+        // 快照文件不存在时不做处理
+        gtest.Assert(loaded.Load(path + ".none"), nil)
+        gtest.AssertNE(loaded.LoadFrom(strings.NewReader("invalid")), nil)
+    })
+    gtest.Case(t, func() {
+        path  := filepath.Join(os.TempDir(), "gcache_auto_dump_test.snapshot")
+        defer os.Remove(path)
+        cache := gcache.New()
+        cache.Set(1, 1, 0)
+        entry := cache.AutoDump(path, 100 * time.Millisecond)
+        time.Sleep(250 * time.Millisecond)
+        entry.Close()
+        loaded := gcache.New()
+        gtest.Assert(loaded.Load(path), nil)
+        // 键名恢复为原有类型
+        gtest.Assert(loaded.Get(1), 1)
+        gtest.Assert(loaded.Get(int64(1)), nil)
+    })
+    gtest.Case(t, func() {
+        buffer := new(bytes.Buffer)
+        cache  := gcache.New()
+        cache.Set(1, "int", 0)
+        cache.Set(uint8(2), "uint8", 0)
+        cache.Set(1.5, "float64", 0)
+        cache.Set("1", "string", 0)
+        gtest.Assert(cache.DumpTo(buffer), nil)
+        loaded := gcache.New()
+        gtest.Assert(loaded.LoadFrom(buffer), nil)
+        gtest.Assert(loaded.Size(),        4)
+        gtest.Assert(loaded.Get(1),        "int")
+        gtest.Assert(loaded.Get(uint8(2)), "uint8")
+        gtest.Assert(loaded.Get(1.5),      "float64")
+        gtest.Assert(loaded.Get("1"),      "string")
+        // 兼容版本1的快照内容
+        content, _ := gmsgpack.Encode([]interface{}{1, []interface{}{[]interface{}{"k", "v", 0}}})
+        gtest.Assert(loaded.LoadFrom(bytes.NewReader(content)), nil)
+        gtest.Assert(loaded.Get("k"), "v")
+    })
+}
+
+func TestCache_Stats(t *testing.T) {
+    gtest.Case(t, func() {
+        mu      := sync.Mutex{}
+        expired := make(map[interface{}]interface{})
+        cache   := gcache.NewWithConfig(gcache.Config {
+            MaxEntries : 2,
+            OnExpire   : func(key, value interface{}, reason string) {
+                mu.Lock()
+                expired[key] = value
+                mu.Unlock()
+                gtest.Assert(reason, gcache.EVICT_REASON_EXPIRED)
+            },
+        })
+        cache.Set(1, 1, 100)
+        cache.Set(2, 2, 0)
+        cache.Get(1)
+        cache.Get(2)
+        cache.Get(3)
+        cache.Contains(3)
+        stats := cache.Stats()
+        gtest.Assert(stats.Hits,      2)
+        gtest.Assert(stats.Misses,    1)
+        gtest.Assert(stats.Size,      2)
+        gtest.Assert(stats.HitRate() > 0.66 && stats.HitRate() < 0.67, true)
+        cache.Set(3, 3, 0)
+        gtest.Assert(cache.Stats().Evictions, 1)
+        // 过期数据由异步任务清理
+        cache.Set(4, 4, 100)
+        time.Sleep(2500 * time.Millisecond)
+        mu.Lock()
+        gtest.Assert(expired, map[interface{}]interface{}{4 : 4})
+        mu.Unlock()
+        gtest.Assert(cache.Stats().Expirations, 1)
+        gtest.Assert(gcache.Stats{}.HitRate(), 0)
+    })
+}
+
+func TestCache_Group(t *testing.T) {
+    gtest.Case(t, func() {
+        cache := gcache.New()
+        user  := cache.Group("user")
+        order := cache.Group("order")
+        cache.Set(1, "root", 0)
+        user.Set(1, "user", 0)
+        order.Set(1, "order", 0)
+        gtest.Assert(cache.Get(1), "root")
+        gtest.Assert(user.Get(1),  "user")
+        gtest.Assert(order.Get(1), "order")
+        gtest.Assert(user.GroupName(), "user")
+        gtest.Assert(user.Keys(), []interface{}{1})
+        gtest.Assert(user.Data(), map[interface{}]interface{}{1 : "user"})
+        gtest.Assert(cache.Size(), 3)
+        // 嵌套分组
+        profile := user.Group("profile")
+        profile.Set(1, "profile", 0)
+        gtest.Assert(profile.Get(1), "profile")
+        gtest.Assert(user.Size(), 2)
+        user.Clear()
+        gtest.Assert(user.Size(),    0)
+        gtest.Assert(profile.Get(1), nil)
+        gtest.Assert(order.Get(1),   "order")
+        gtest.Assert(cache.Size(),   2)
+    })
+}
+
+func TestCache_Tags(t *testing.T) {
+    gtest.Case(t, func() {
+        cache := gcache.New()
+        user  := cache.Group("user")
+        query := cache.Group("query")
+        user.SetWithTags(1, "john", 0, "user:1")
+        query.SetWithTags("select * from user where id=1", "john", 0, "user:1", "table:user")
+        query.SetWithTags("select * from user", "all", 0, "table:user")
+        cache.Set("other", 1, 0)
+        gtest.Assert(query.GetTags("select * from user"), []string{"table:user"})
+        // 分组只删除分组内的数据
+        gtest.Assert(user.RemoveByTag("table:user"), 0)
+        gtest.Assert(query.RemoveByTag("table:user"), 2)
+        gtest.Assert(query.Size(), 0)
+        gtest.Assert(user.Get(1),  "john")
+        // 顶层缓存对象删除所有分组中的数据
+        query.SetWithTags("select * from user where id=1", "john", 0, "user:1")
+        gtest.Assert(cache.RemoveByTag("user:1"), 2)
+        gtest.Assert(user.Contains(1), false)
+        gtest.Assert(cache.Size(), 1)
+        // 删除后标签被清理
+        cache.SetWithTags("k", "v", 0, "t")
+        cache.Remove("k")
+        gtest.Assert(cache.GetTags("k"), []string{})
+        gtest.Assert(cache.RemoveByTag("t"), 0)
+    })
+    // 过期或者被淘汰的数据的标签被清理
+    gtest.Case(t, func() {
+        cache := gcache.NewWithConfig(gcache.Config{MaxEntries : 1})
+        group := cache.Group("group")
+        group.SetWithTags("k1", "v1", 100, "t")
+        time.Sleep(200 * time.Millisecond)
+        gtest.Assert(group.GetTags("k1"), []string{})
+        gtest.Assert(group.RemoveByTag("t"), 0)
+        cache.SetWithTags("k2", "v2", 0, "t")
+        cache.SetWithTags("k3", "v3", 0, "t")
+        gtest.Assert(cache.Contains("k2"), false)
+        gtest.Assert(cache.GetTags("k2"), []string{})
+        gtest.Assert(cache.RemoveByTag("t"), 1)
+        gtest.Assert(cache.Size(), 0)
+        // 大量过期数据的标签在写入时被清理，之后写入的数据不受影响
+        for i := 0; i < 3000; i++ {
+            cache.SetWithTags(i, i, -1, "expired")
+        }
+        cache.SetWithTags("k4", "v4", 0, "t")
+        gtest.Assert(cache.GetTags("k4"), []string{"t"})
+        gtest.Assert(cache.RemoveByTag("expired", "t"), 1)
+    })
+}
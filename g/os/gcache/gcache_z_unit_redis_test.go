@@ -0,0 +1,250 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gcache_test
+
+import (
+    "bufio"
+    "fmt"
+    "net"
+    "path"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/database/gredis"
+    "github.com/gogf/gf/g/os/gcache"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+// 用于测试的简易Redis服务端，只支持适配器使用的命令
+type fakeRedis struct {
+    mu      sync.Mutex
+    data    map[string]string
+    expires map[string]time.Time
+}
+
+func startFakeRedis(t *testing.T) (*gredis.Redis, func()) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    s := &fakeRedis {
+        data    : make(map[string]string),
+        expires : make(map[string]time.Time),
+    }
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            go s.serve(conn)
+        }
+    }()
+    port := listener.Addr().(*net.TCPAddr).Port
+    return gredis.New(gredis.Config{Host : "127.0.0.1", Port : port}), func() { listener.Close() }
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+    defer conn.Close()
+    reader := bufio.NewReader(conn)
+    for {
+        line, err := reader.ReadString('\n')
+        if err != nil {
+            return
+        }
+        n, _ := strconv.Atoi(strings.TrimSpace(line[1 : ]))
+        args := make([]string, n)
+        for i := 0; i < n; i++ {
+            reader.ReadString('\n')
+            value, _ := reader.ReadString('\n')
+            args[i] = strings.TrimSuffix(value, "\r\n")
+        }
+        conn.Write([]byte(s.execute(args)))
+    }
+}
+
+func (s *fakeRedis) get(key string) (string, bool) {
+    if e, ok := s.expires[key]; ok && time.Now().After(e) {
+        delete(s.data, key)
+        delete(s.expires, key)
+    }
+    v, ok := s.data[key]
+    return v, ok
+}
+
+func (s *fakeRedis) execute(args []string) string {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    switch strings.ToUpper(args[0]) {
+        case "PING":
+            return "+PONG\r\n"
+        case "SELECT":
+            return "+OK\r\n"
+        case "GET":
+            if v, ok := s.get(args[1]); ok {
+                return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+            }
+            return "$-1\r\n"
+        case "SET":
+            _, exists := s.get(args[1])
+            expire    := time.Duration(0)
+            for i := 3; i < len(args); i++ {
+                switch strings.ToUpper(args[i]) {
+                    case "NX":
+                        if exists {
+                            return "$-1\r\n"
+                        }
+                    case "PX":
+                        ms, _ := strconv.Atoi(args[i + 1])
+                        expire = time.Duration(ms) * time.Millisecond
+                        i++
+                }
+            }
+            s.data[args[1]] = args[2]
+            delete(s.expires, args[1])
+            if expire > 0 {
+                s.expires[args[1]] = time.Now().Add(expire)
+            }
+            return "+OK\r\n"
+        case "DEL", "EXISTS":
+            count := 0
+            for _, key := range args[1 : ] {
+                if _, ok := s.get(key); ok {
+                    count++
+                    if strings.ToUpper(args[0]) == "DEL" {
+                        delete(s.data, key)
+                    }
+                }
+            }
+            return fmt.Sprintf(":%d\r\n", count)
+        case "EVAL":
+            // 只支持释放锁的脚本: EVAL script 1 key token
+            if v, ok := s.get(args[3]); ok && v == args[4] {
+                delete(s.data, args[3])
+                return ":1\r\n"
+            }
+            return ":0\r\n"
+        case "SCAN":
+            keys := make([]string, 0)
+            for k := range s.data {
+                if matched, _ := path.Match(args[3], k); matched {
+                    if _, ok := s.get(k); ok {
+                        keys = append(keys, fmt.Sprintf("$%d\r\n%s\r\n", len(k), k))
+                    }
+                }
+            }
+            return fmt.Sprintf("*2\r\n$1\r\n0\r\n*%d\r\n%s", len(keys), strings.Join(keys, ""))
+    }
+    return "-ERR unknown command\r\n"
+}
+
+func TestCache_RedisAdapter(t *testing.T) {
+    redis, closeFunc := startFakeRedis(t)
+    defer closeFunc()
+    gtest.Case(t, func() {
+        cache := gcache.NewWithAdapter(gcache.NewRedisAdapter(redis, gcache.RedisAdapterConfig {
+            Prefix  : "test:",
+            OnError : func(err error) { t.Error(err) },
+        }))
+        cache.Set("k1", "v1", 0)
+        cache.Set(2, map[string]interface{}{"name" : "john"}, 0)
+        gtest.Assert(cache.Get("k1"), "v1")
+        gtest.Assert(cache.Get(2),    map[string]interface{}{"name" : "john"})
+        gtest.Assert(cache.Get("k3"), nil)
+        gtest.Assert(cache.Stats().Hits,   2)
+        gtest.Assert(cache.Stats().Misses, 1)
+        gtest.Assert(cache.Contains("k1"), true)
+        gtest.Assert(cache.SetIfNotExist("k1", "v2", 0), false)
+        gtest.Assert(cache.SetIfNotExist("k3", 3, 0), true)
+        gtest.Assert(cache.Get("k3"), 3)
+        gtest.Assert(cache.GetOrSet("k1", "v2", 0), "v1")
+        gtest.Assert(cache.GetOrSetFunc("k4", func() interface{} { return 4 }, 0), 4)
+        gtest.Assert(cache.Size(), 4)
+        gtest.AssertIN("k4", cache.KeyStrings())
+        gtest.Assert(cache.Data()["k1"], "v1")
+        gtest.Assert(cache.Remove("k4"), 4)
+        gtest.Assert(cache.Contains("k4"), false)
+        // 过期
+        cache.BatchSet(map[interface{}]interface{}{"k5" : 5, "k6" : 6}, 100)
+        gtest.Assert(cache.Get("k5"), 5)
+        time.Sleep(150 * time.Millisecond)
+        gtest.Assert(cache.Get("k6"), nil)
+        // 其他前缀的数据不受影响
+        redis.Do("SET", "other", "1")
+        cache.Clear()
+        gtest.Assert(cache.Size(), 0)
+        n, _ := redis.Do("EXISTS", "other")
+        gtest.Assert(n, 1)
+    })
+    gtest.Case(t, func() {
+        cache := gcache.NewWithAdapter(gcache.NewRedisAdapter(redis, gcache.RedisAdapterConfig{Prefix : "lock:"}))
+        count := int32(0)
+        wg    := sync.WaitGroup{}
+        for i := 0; i < 10; i++ {
+            wg.Add(1)
+            go func() {
+                defer wg.Done()
+                v := cache.GetOrSetFuncLock("key", func() interface{} {
+                    atomic.AddInt32(&count, 1)
+                    time.Sleep(50 * time.Millisecond)
+                    return "value"
+                }, 0)
+                gtest.Assert(v, "value")
+            }()
+        }
+        wg.Wait()
+        gtest.Assert(atomic.LoadInt32(&count), 1)
+    })
+    gtest.Case(t, func() {
+        cache := gcache.NewWithAdapter(gcache.NewRedisAdapter(redis, gcache.RedisAdapterConfig{Prefix : "token:"}))
+        v := cache.GetOrSetFuncLock("key", func() interface{} {
+            // 锁的键名不出现在键名列表中
+            gtest.Assert(cache.Size(), 0)
+            gtest.Assert(len(cache.Keys()), 0)
+            // 模拟锁超时后被其他调用者获得
+            redis.Do("SET", "token:key:gcache-lock", "other")
+            return "value"
+        }, 0)
+        gtest.Assert(v, "value")
+        // 释放锁时不删除其他调用者持有的锁
+        lock, _ := redis.Do("GET", "token:key:gcache-lock")
+        gtest.Assert(string(lock.([]byte)), "other")
+        gtest.Assert(cache.Keys(), []interface{}{"key"})
+        cache.Clear()
+        // 正常释放自己持有的锁
+        cache.GetOrSetFuncLock("key2", func() interface{} { return 1 }, 0)
+        n, _ := redis.Do("EXISTS", "token:key2:gcache-lock")
+        gtest.Assert(n, 0)
+    })
+    // 分组
+    gtest.Case(t, func() {
+        cache := gcache.NewWithAdapter(gcache.NewRedisAdapter(redis, gcache.RedisAdapterConfig{Prefix : "group:"}))
+        user  := cache.Group("user")
+        user.Set(1, "john", 0)
+        gtest.Assert(user.Get(1), "john")
+        gtest.Assert(user.Keys(), []interface{}{"1"})
+        v, _ := redis.Do("EXISTS", "group:user:1")
+        gtest.Assert(v, 1)
+        user.Clear()
+        gtest.Assert(cache.Size(), 0)
+    })
+    // 替换全局缓存的适配器
+    gtest.Case(t, func() {
+        cache := gcache.New()
+        cache.Set("k", "memory", 0)
+        cache.SetAdapter(gcache.NewRedisAdapter(redis, gcache.RedisAdapterConfig{Prefix : "swap:"}))
+        gtest.Assert(cache.Get("k"), nil)
+        cache.Set("k", "redis", 0)
+        v, _ := redis.Do("EXISTS", "swap:k")
+        gtest.Assert(v, 1)
+    })
+}
@@ -7,7 +7,14 @@
 // Package gcfg provides reading, caching and managing for configuration files.
 // 
 // 配置管理,
-// 配置文件格式支持：json, xml, toml, yaml/yml
+// 配置文件格式支持：json, xml, toml, yaml/yml, ini, properties；
+// 指定的配置文件不存在时，将按照 toml, yaml, yml, json, xml, ini, properties 的顺序查找同名的其他格式配置文件。
+// 配置内容也可以通过配置数据源适配器(SetAdapter)从etcd、Consul等远程配置中心读取。
+//
+// 配置项的覆盖优先级(从高到低)：
+// 1、命令行选项(需要通过SetFlagPrefix开启)，例如: --config.http.port=8080；
+// 2、环境变量(需要通过SetEnvPrefix开启)，例如: GF_HTTP_PORT=8080；
+// 3、配置文件。
 package gcfg
 
 import (
@@ -23,18 +30,31 @@ import (
     "github.com/gogf/gf/g/os/gfsnotify"
     "github.com/gogf/gf/g/os/glog"
     "github.com/gogf/gf/g/os/gspath"
+    "strings"
+    "sync"
 )
 
 const (
     DEFAULT_CONFIG_FILE = "config.toml" // 默认的配置管理文件名称
 )
 
+// 配置文件不存在时，依次查找的同名配置文件扩展名
+var supportedFileExts = []string{"toml", "yaml", "yml", "json", "xml", "ini", "properties"}
+
 // 配置管理对象
 type Config struct {
-    name   *gtype.String            // 默认配置文件名称
-    paths  *garray.StringArray      // 搜索目录路径
-    jsons  *gmap.StringInterfaceMap // 配置文件对象
-    vc     *gtype.Bool              // 层级检索是否执行分隔符冲突检测(默认为false，检测会比较影响检索效率)
+    name        *gtype.String             // 默认配置文件名称
+    paths       *garray.StringArray       // 搜索目录路径
+    jsons       *gmap.StringInterfaceMap  // 配置文件对象
+    vc          *gtype.Bool               // 层级检索是否执行分隔符冲突检测(默认为false，检测会比较影响检索效率)
+    envPrefix   *gtype.String             // 环境变量覆盖的前缀(为空表示关闭)
+    flagPrefix  *gtype.String             // 命令行选项覆盖的前缀(为空表示关闭)
+    changeMu    sync.Mutex
+    handlers    []*changeHandler          // 配置变更回调
+    pending     map[string]*changePending // 等待通知的配置变更，键名为配置文件绝对路径
+    monitors    map[string]int            // 已经开启监控的配置文件绝对路径及其监控回调ID
+    changeDelay *gtype.Int64              // 配置变更通知的延迟时间
+    adapters    *gmap.StringInterfaceMap  // 配置数据源适配器，键名为配置文件名称
 }
 
 // 生成一个配置管理对象
@@ -44,10 +64,16 @@ func New(path string, file...string) *Config {
         name = file[0]
     }
     c := &Config {
-        name   : gtype.NewString(name),
-        paths  : garray.NewStringArray(),
-        jsons  : gmap.NewStringInterfaceMap(),
-        vc     : gtype.NewBool(),
+        name        : gtype.NewString(name),
+        paths       : garray.NewStringArray(),
+        jsons       : gmap.NewStringInterfaceMap(),
+        vc          : gtype.NewBool(),
+        envPrefix   : gtype.NewString(),
+        flagPrefix  : gtype.NewString(),
+        pending     : make(map[string]*changePending),
+        monitors    : make(map[string]int),
+        changeDelay : gtype.NewInt64(int64(gDEFAULT_CHANGE_DELAY)),
+        adapters    : gmap.NewStringInterfaceMap(),
     }
     if len(path) > 0 {
         c.SetPath(path)
@@ -61,14 +87,7 @@ func (c *Config) filePath(file...string) (path string) {
     if len(file) > 0 {
         name = file[0]
     }
-    c.paths.RLockFunc(func(array []string) {
-        for _, v := range array {
-            if path, _ = gspath.Search(v, name); path != "" {
-                break
-            }
-        }
-    })
-    if path == "" {
+    if path = c.searchFile(name); path == "" {
         buffer := bytes.NewBuffer(nil)
         if c.paths.Len() > 0 {
             buffer.WriteString(fmt.Sprintf("[gcfg] cannot find config file \"%s\" in following paths:", name))
@@ -134,13 +153,35 @@ func (c *Config) GetFilePath(file...string) (path string) {
     if len(file) > 0 {
         name = file[0]
     }
+    return c.searchFile(name)
+}
+
+// 在搜索目录中查找配置文件，不存在时依次查找同名的其他格式配置文件
+func (c *Config) searchFile(name string) (path string) {
+    if c.adapters.Contains(name) {
+        return gADAPTER_PATH_PREFIX + name
+    }
+    names := []string{name}
+    if ext := gfile.Ext(name); ext != "" {
+        base := strings.TrimSuffix(name, ext)
+        for _, v := range supportedFileExts {
+            if "." + v != ext {
+                names = append(names, base + "." + v)
+            }
+        }
+    }
     c.paths.RLockFunc(func(array []string) {
-        for _, v := range array {
-            if path, _ = gspath.Search(v, name); path != "" {
-                break
+        for _, n := range names {
+            for _, v := range array {
+                if path, _ = gspath.Search(v, n); path != "" {
+                    return
+                }
             }
         }
     })
+    if path == "" {
+        path = c.searchResource(names)
+    }
     return
 }
 
@@ -159,9 +200,29 @@ func (c *Config) getJson(file...string) *gjson.Json {
     if r := c.jsons.Get(filePath); r != nil {
         return r.(*gjson.Json)
     }
-    if j, err := gjson.Load(filePath); err == nil {
+    return c.loadJson(filePath)
+}
+
+// 从磁盘(或者配置数据源适配器)读取配置文件内容并缓存，同时开启文件监控
+func (c *Config) loadJson(filePath string) *gjson.Json {
+    j   := (*gjson.Json)(nil)
+    err := error(nil)
+    if adapter, name := c.getAdapter(filePath); adapter != nil {
+        if content, e := adapter.Load(); e != nil {
+            err = e
+        } else {
+            j, err = gjson.LoadContentLenient(content, gfile.Ext(name))
+        }
+    } else if content := getResourceContent(filePath); content != nil {
+        j, err = gjson.LoadContentLenient(content, gfile.Ext(filePath))
+    } else {
+        if j, err = gjson.LoadLenient(filePath); err == nil {
+            c.addMonitor(filePath)
+        }
+    }
+    if err == nil {
         j.SetViolenceCheck(c.vc.Val())
-        c.addMonitor(filePath)
+        c.applyOverrides(j)
         c.jsons.Set(filePath, j)
         return j
     } else {
@@ -349,14 +410,30 @@ func (c *Config) Reload() {
     c.jsons.Clear()
 }
 
-// 添加文件监控
+// 添加文件监控，每个文件只添加一次
 func (c *Config) addMonitor(path string) {
-    // 防止多goroutine同时调用
-    if c.jsons.Get(path) != nil {
+    c.changeMu.Lock()
+    defer c.changeMu.Unlock()
+    if _, ok := c.monitors[path]; ok {
         return
     }
-    gfsnotify.Add(path, func(event *gfsnotify.Event) {
-        // 删除文件内容缓存，下一次查询会自动更新
-        c.jsons.Remove(event.Path)
-    })
+    if callback, err := gfsnotify.Add(path, func(event *gfsnotify.Event) {
+        // 文件被删除时移除监控，文件重新创建后再次加载时重新添加
+        if event.IsRemove() && !gfile.Exists(path) {
+            c.removeMonitor(path)
+        }
+        c.onFileChange(path)
+    }); err == nil {
+        c.monitors[path] = callback.Id
+    }
+}
+
+// 移除文件监控
+func (c *Config) removeMonitor(path string) {
+    c.changeMu.Lock()
+    defer c.changeMu.Unlock()
+    if id, ok := c.monitors[path]; ok {
+        gfsnotify.RemoveCallback(id)
+        delete(c.monitors, path)
+    }
 }
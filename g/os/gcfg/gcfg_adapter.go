@@ -0,0 +1,96 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/os/glog"
+)
+
+const (
+    gADAPTER_PATH_PREFIX         = "adapter://"    // 使用配置数据源适配器的配置文件路径前缀
+    gADAPTER_WATCH_RETRY_INTERVAL = 3 * time.Second // 监听失败时的重试间隔
+)
+
+// 配置数据源适配器，用于从远程配置中心(例如: etcd、Consul)读取配置内容
+type Adapter interface {
+    // 读取配置内容
+    Load() ([]byte, error)
+    // 开始监听配置变化(非阻塞)，配置内容发生变化时调用notify
+    Watch(notify func()) error
+    // 停止监听并释放资源
+    Close() error
+}
+
+// 使用配置数据源适配器替代指定名称(默认为默认的配置文件名称)的配置文件，配置内容的格式由名称的扩展名确定。
+// 设置之后该名称的配置读取(Get*/GetStruct)以及变更回调(OnChange)均通过适配器完成，例如:
+// c.SetAdapter(gcfg.NewConsulAdapter(gcfg.ConsulConfig{Key : "app/config.toml"}), "config.toml")
+func (c *Config) SetAdapter(adapter Adapter, file...string) error {
+    name := c.name.Val()
+    if len(file) > 0 {
+        name = file[0]
+    }
+    path := gADAPTER_PATH_PREFIX + name
+    if err := adapter.Watch(func() {
+        c.onFileChange(path)
+    }); err != nil {
+        return err
+    }
+    if old, ok := c.adapters.Get(name).(Adapter); ok {
+        old.Close()
+    }
+    c.adapters.Set(name, adapter)
+    c.jsons.Remove(path)
+    return nil
+}
+
+// 移除指定名称(默认为默认的配置文件名称)的配置数据源适配器，并关闭适配器
+func (c *Config) RemoveAdapter(file...string) {
+    name := c.name.Val()
+    if len(file) > 0 {
+        name = file[0]
+    }
+    if adapter, ok := c.adapters.Remove(name).(Adapter); ok {
+        adapter.Close()
+    }
+    c.jsons.Remove(gADAPTER_PATH_PREFIX + name)
+}
+
+// 获得配置文件路径对应的适配器以及配置文件名称，非适配器路径时返回nil
+func (c *Config) getAdapter(path string) (Adapter, string) {
+    if !strings.HasPrefix(path, gADAPTER_PATH_PREFIX) {
+        return nil, ""
+    }
+    name       := path[len(gADAPTER_PATH_PREFIX) : ]
+    adapter, _ := c.adapters.Get(name).(Adapter)
+    return adapter, name
+}
+
+// 循环执行配置监听，直到closed关闭；watch返回错误时等待一段时间后重试
+func watchLoop(name string, closed chan struct{}, watch func() error) {
+    for {
+        select {
+            case <- closed:
+                return
+            default:
+        }
+        if err := watch(); err != nil {
+            select {
+                case <- closed:
+                    return
+                default:
+            }
+            glog.Errorfln(`[gcfg] %s watch failed: %s`, name, err.Error())
+            select {
+                case <- closed:
+                    return
+                case <- time.After(gADAPTER_WATCH_RETRY_INTERVAL):
+            }
+        }
+    }
+}
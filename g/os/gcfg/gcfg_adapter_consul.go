@@ -0,0 +1,156 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "net/http"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+)
+
+const (
+    gCONSUL_DEFAULT_ADDRESS   = "http://127.0.0.1:8500" // 默认的Consul服务地址
+    gCONSUL_DEFAULT_WAIT_TIME = 5 * time.Minute         // 默认的阻塞查询等待时间
+)
+
+// Consul KV配置数据源配置
+type ConsulConfig struct {
+    Address    string        // Consul服务地址，默认为http://127.0.0.1:8500
+    Key        string        // 配置内容所在的键名，例如: app/config.toml
+    Token      string        // ACL Token(非必需)
+    Datacenter string        // 数据中心(非必需)
+    WaitTime   time.Duration // 监听配置变化时阻塞查询的最长等待时间，默认为5分钟
+    Client     *http.Client  // 自定义的HTTP客户端(非必需)
+}
+
+// Consul KV配置数据源适配器，通过Consul的HTTP API读取配置内容，并使用阻塞查询(blocking query)监听配置变化
+type consulAdapter struct {
+    config ConsulConfig
+    mu     sync.Mutex
+    index  string             // 最近一次读取到的X-Consul-Index
+    cancel context.CancelFunc // 取消正在执行的阻塞查询
+    closed chan struct{}
+}
+
+// 创建Consul KV配置数据源适配器
+func NewConsulAdapter(config ConsulConfig) Adapter {
+    if config.Address == "" {
+        config.Address = gCONSUL_DEFAULT_ADDRESS
+    }
+    if config.WaitTime <= 0 {
+        config.WaitTime = gCONSUL_DEFAULT_WAIT_TIME
+    }
+    if config.Client == nil {
+        config.Client = http.DefaultClient
+    }
+    config.Address = strings.TrimRight(config.Address, "/")
+    config.Key     = strings.Trim(config.Key, "/")
+    return &consulAdapter {
+        config : config,
+        closed : make(chan struct{}),
+    }
+}
+
+func (a *consulAdapter) Load() ([]byte, error) {
+    content, index, err := a.get(context.Background(), "")
+    if err != nil {
+        return nil, err
+    }
+    a.mu.Lock()
+    a.index = index
+    a.mu.Unlock()
+    return content, nil
+}
+
+func (a *consulAdapter) Watch(notify func()) error {
+    go watchLoop("consul", a.closed, func() error {
+        a.mu.Lock()
+        select {
+            case <- a.closed:
+                a.mu.Unlock()
+                return nil
+            default:
+        }
+        index       := a.index
+        ctx, cancel := context.WithCancel(context.Background())
+        a.cancel     = cancel
+        a.mu.Unlock()
+        defer cancel()
+        // 键名不存在时仍然返回索引，键名被创建后可以收到通知
+        _, newIndex, err := a.get(ctx, index)
+        if err != nil && newIndex == "" {
+            return err
+        }
+        a.mu.Lock()
+        a.index = newIndex
+        a.mu.Unlock()
+        // 第一次查询只用于获得当前的索引
+        if index != "" && newIndex != index {
+            notify()
+        }
+        return nil
+    })
+    return nil
+}
+
+func (a *consulAdapter) Close() error {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    select {
+        case <- a.closed:
+            return nil
+        default:
+            close(a.closed)
+    }
+    if a.cancel != nil {
+        a.cancel()
+    }
+    return nil
+}
+
+// 读取键值，index不为空时执行阻塞查询，返回配置内容及最新的索引
+func (a *consulAdapter) get(ctx context.Context, index string) ([]byte, string, error) {
+    query := url.Values{}
+    query.Set("raw", "")
+    if a.config.Datacenter != "" {
+        query.Set("dc", a.config.Datacenter)
+    }
+    if index != "" {
+        query.Set("index", index)
+        query.Set("wait",  fmt.Sprintf("%ds", int(a.config.WaitTime / time.Second)))
+    }
+    request, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/kv/%s?%s", a.config.Address, a.config.Key, query.Encode()), nil)
+    if err != nil {
+        return nil, "", err
+    }
+    if a.config.Token != "" {
+        request.Header.Set("X-Consul-Token", a.config.Token)
+    }
+    response, err := a.config.Client.Do(request.WithContext(ctx))
+    if err != nil {
+        return nil, "", err
+    }
+    defer response.Body.Close()
+    content, err := ioutil.ReadAll(response.Body)
+    if err != nil {
+        return nil, "", err
+    }
+    switch response.StatusCode {
+        case http.StatusOK:
+        case http.StatusNotFound:
+            return nil, response.Header.Get("X-Consul-Index"), errors.New(fmt.Sprintf(`consul key "%s" not found`, a.config.Key))
+        default:
+            return nil, "", errors.New(fmt.Sprintf(`consul request failed: %s %s`, response.Status, strings.TrimSpace(string(content))))
+    }
+    return content, response.Header.Get("X-Consul-Index"), nil
+}
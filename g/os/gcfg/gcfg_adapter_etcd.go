@@ -0,0 +1,157 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "strconv"
+    "sync"
+    "github.com/gogf/gf/g/internal/etcd"
+)
+
+// etcd配置数据源配置
+type EtcdConfig struct {
+    Address  string       // etcd服务地址，默认为http://127.0.0.1:2379
+    Key      string       // 配置内容所在的键名，例如: /app/config.toml
+    Username string       // 用户名(开启认证时需要)
+    Password string       // 密码
+    Client   *http.Client // 自定义的HTTP客户端(非必需)
+}
+
+// etcd配置数据源适配器，通过etcd v3的HTTP(gRPC gateway)接口读取配置内容，并使用watch接口监听配置变化
+type etcdAdapter struct {
+    config   EtcdConfig
+    client   *etcd.Client
+    mu       sync.Mutex
+    revision int64              // 最近一次读取到的数据版本
+    cancel   context.CancelFunc // 取消正在执行的watch请求
+    closed   chan struct{}
+}
+
+// 创建etcd配置数据源适配器
+func NewEtcdAdapter(config EtcdConfig) Adapter {
+    return &etcdAdapter {
+        config : config,
+        client : etcd.New(etcd.Config {
+            Address  : config.Address,
+            Username : config.Username,
+            Password : config.Password,
+            Client   : config.Client,
+        }),
+        closed : make(chan struct{}),
+    }
+}
+
+func (a *etcdAdapter) Load() ([]byte, error) {
+    result := struct {
+        Header etcd.Header `json:"header"`
+        Kvs    []struct {
+            Value string `json:"value"`
+        } `json:"kvs"`
+    }{}
+    response, err := a.client.Request(context.Background(), "/v3/kv/range", map[string]interface{} {
+        "key" : a.encodeKey(),
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer response.Body.Close()
+    if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+        return nil, err
+    }
+    revision, _ := strconv.ParseInt(result.Header.Revision, 10, 64)
+    a.mu.Lock()
+    a.revision = revision
+    a.mu.Unlock()
+    if len(result.Kvs) == 0 {
+        return nil, errors.New(fmt.Sprintf(`etcd key "%s" not found`, a.config.Key))
+    }
+    return base64.StdEncoding.DecodeString(result.Kvs[0].Value)
+}
+
+func (a *etcdAdapter) Watch(notify func()) error {
+    go watchLoop("etcd", a.closed, func() error {
+        a.mu.Lock()
+        select {
+            case <- a.closed:
+                a.mu.Unlock()
+                return nil
+            default:
+        }
+        request := map[string]interface{} {
+            "key" : a.encodeKey(),
+        }
+        if a.revision > 0 {
+            request["start_revision"] = a.revision + 1
+        }
+        ctx, cancel := context.WithCancel(context.Background())
+        a.cancel     = cancel
+        a.mu.Unlock()
+        defer cancel()
+        response, err := a.client.Request(ctx, "/v3/watch", map[string]interface{}{"create_request" : request})
+        if err != nil {
+            return err
+        }
+        defer response.Body.Close()
+        // watch接口持续返回JSON对象，每个对象包含一组变更事件
+        decoder := json.NewDecoder(response.Body)
+        for {
+            result := struct {
+                Result struct {
+                    Header   etcd.Header   `json:"header"`
+                    Events   []interface{} `json:"events"`
+                    Canceled bool          `json:"canceled"`
+                } `json:"result"`
+                Error *struct {
+                    Message string `json:"message"`
+                } `json:"error"`
+            }{}
+            if err := decoder.Decode(&result); err != nil {
+                return err
+            }
+            if result.Error != nil {
+                return errors.New(result.Error.Message)
+            }
+            if result.Result.Canceled {
+                return errors.New("etcd watch canceled")
+            }
+            if len(result.Result.Events) > 0 {
+                if revision, err := strconv.ParseInt(result.Result.Header.Revision, 10, 64); err == nil {
+                    a.mu.Lock()
+                    a.revision = revision
+                    a.mu.Unlock()
+                }
+                notify()
+            }
+        }
+    })
+    return nil
+}
+
+func (a *etcdAdapter) Close() error {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    select {
+        case <- a.closed:
+            return nil
+        default:
+            close(a.closed)
+    }
+    if a.cancel != nil {
+        a.cancel()
+    }
+    return nil
+}
+
+func (a *etcdAdapter) encodeKey() string {
+    return etcd.Encode(a.config.Key)
+}
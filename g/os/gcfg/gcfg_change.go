@@ -0,0 +1,105 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "reflect"
+    "time"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gtimer"
+)
+
+const (
+    gDEFAULT_CHANGE_DELAY = 500 * time.Millisecond // 默认的配置变更通知延迟时间
+)
+
+// 配置变更回调
+type changeHandler struct {
+    pattern string                     // 监听的配置项，为空表示整个配置文件
+    file    []string                   // 监听的配置文件名称，为空表示默认的配置文件
+    f       func(old, new *gjson.Json) // 回调函数
+}
+
+// 等待通知的配置变更
+type changePending struct {
+    old   *gjson.Json   // 变更前的配置内容(第一次变更之前的内容)
+    entry *gtimer.Entry // 延迟通知的定时任务
+}
+
+// 注册配置变更回调函数，配置文件发生变化并且指定配置项(pattern为空时表示整个配置文件)的值发生改变时，
+// 回调函数将在延迟时间(默认为500毫秒，可通过SetChangeDelay修改)之后被调用，延迟时间内的多次变更只通知一次。
+// 回调函数的参数为变更前后的完整配置内容(变更前的配置未加载时old为nil)，例如: new.GetString("logger.level")。
+func (c *Config) OnChange(pattern string, f func(old, new *gjson.Json), file...string) {
+    c.changeMu.Lock()
+    c.handlers = append(c.handlers, &changeHandler {
+        pattern : pattern,
+        file    : file,
+        f       : f,
+    })
+    c.changeMu.Unlock()
+    // 加载配置文件并开启监控，用于获取变更前的配置内容
+    c.getJson(file...)
+}
+
+// 设置配置变更通知的延迟时间，用于合并短时间内的多次文件变更事件
+func (c *Config) SetChangeDelay(delay time.Duration) {
+    c.changeDelay.Set(int64(delay))
+}
+
+// 配置文件发生变化，清除配置缓存，并在延迟时间之后执行变更回调
+func (c *Config) onFileChange(path string) {
+    old, _ := c.jsons.Get(path).(*gjson.Json)
+    // 删除文件内容缓存，下一次查询会自动更新
+    c.jsons.Remove(path)
+    c.changeMu.Lock()
+    defer c.changeMu.Unlock()
+    if len(c.handlers) == 0 {
+        return
+    }
+    pending, ok := c.pending[path]
+    if ok {
+        pending.entry.Close()
+    } else {
+        pending = &changePending{old : old}
+        c.pending[path] = pending
+    }
+    pending.entry = gtimer.AddOnce(time.Duration(c.changeDelay.Val()), func() {
+        c.changeMu.Lock()
+        delete(c.pending, path)
+        c.changeMu.Unlock()
+        // 配置文件被删除时不通知，重新创建后old参数为nil
+        if adapter, _ := c.getAdapter(path); adapter == nil && !gfile.Exists(path) {
+            return
+        }
+        c.notifyChange(path, pending.old, c.loadJson(path))
+    })
+}
+
+// 执行指定配置文件的变更回调，只有监听的配置项发生改变时才会调用
+func (c *Config) notifyChange(path string, old, new *gjson.Json) {
+    if new == nil {
+        return
+    }
+    c.changeMu.Lock()
+    handlers := make([]*changeHandler, len(c.handlers))
+    copy(handlers, c.handlers)
+    c.changeMu.Unlock()
+    for _, h := range handlers {
+        if c.GetFilePath(h.file...) != path {
+            continue
+        }
+        var oldValue interface{}
+        if old != nil {
+            oldValue = old.Get(h.pattern)
+        }
+        if reflect.DeepEqual(oldValue, new.Get(h.pattern)) {
+            continue
+        }
+        h.f(old, new)
+    }
+}
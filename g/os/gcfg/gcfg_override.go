@@ -0,0 +1,101 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "encoding/json"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gcmd"
+)
+
+// 设置环境变量覆盖的前缀，设置后名称为 前缀_层级_键名 的环境变量将覆盖配置文件中对应的配置项，
+// 例如前缀为GF时，环境变量GF_HTTP_PORT将覆盖配置项http.port(键名不区分大小写，键名中的"-"使用"_"表示)。
+// 前缀为空字符串时表示关闭(默认关闭)。
+func (c *Config) SetEnvPrefix(prefix string) {
+    c.envPrefix.Set(prefix)
+    c.Reload()
+}
+
+// 设置命令行选项覆盖的前缀，设置后名称为 前缀.层级.键名 的命令行选项将覆盖配置文件以及环境变量中对应的配置项，
+// 例如前缀为config时，命令行选项 --config.http.port=8080 将覆盖配置项http.port。
+// 前缀为空字符串时表示关闭(默认关闭)。
+func (c *Config) SetFlagPrefix(prefix string) {
+    c.flagPrefix.Set(prefix)
+    c.Reload()
+}
+
+// 使用环境变量及命令行选项覆盖配置项，优先级：命令行选项 > 环境变量 > 配置文件
+func (c *Config) applyOverrides(j *gjson.Json) {
+    if prefix := c.envPrefix.Val(); prefix != "" {
+        prefix = strings.ToUpper(prefix) + "_"
+        envs  := os.Environ()
+        // 排序保证同一配置项存在多个环境变量时的覆盖顺序稳定
+        sort.Strings(envs)
+        for _, env := range envs {
+            array := strings.SplitN(env, "=", 2)
+            if len(array) != 2 || len(array[0]) <= len(prefix) || !strings.HasPrefix(strings.ToUpper(array[0]), prefix) {
+                continue
+            }
+            path := matchEnvPath(j.Get(), strings.Split(array[0][len(prefix) : ], "_"))
+            j.Set(path, parseOverrideValue(array[1]))
+        }
+    }
+    if prefix := c.flagPrefix.Val(); prefix != "" {
+        prefix += "."
+        for k, v := range gcmd.Option.GetAll() {
+            if len(k) > len(prefix) && strings.HasPrefix(k, prefix) {
+                j.Set(k[len(prefix) : ], parseOverrideValue(v))
+            }
+        }
+    }
+}
+
+// 将环境变量名称中的层级匹配到配置数据的层级路径，由于"_"既可能是层级分隔符也可能是键名的一部分，
+// 优先匹配配置数据中已经存在的最长键名，无法匹配时使用小写的键名创建新的配置项。
+func matchEnvPath(data interface{}, segments []string) string {
+    if len(segments) == 0 {
+        return ""
+    }
+    switch v := data.(type) {
+        case map[string]interface{}:
+            for n := len(segments); n > 0; n-- {
+                name := strings.Join(segments[ : n], "_")
+                for k, child := range v {
+                    if !strings.EqualFold(strings.Replace(k, "-", "_", -1), name) {
+                        continue
+                    }
+                    if n == len(segments) {
+                        return k
+                    }
+                    return k + "." + matchEnvPath(child, segments[n : ])
+                }
+            }
+        case []interface{}:
+            if i, err := strconv.Atoi(segments[0]); err == nil && i >= 0 && i < len(v) {
+                if len(segments) == 1 {
+                    return segments[0]
+                }
+                return segments[0] + "." + matchEnvPath(v[i], segments[1 : ])
+            }
+    }
+    return strings.ToLower(strings.Join(segments, "."))
+}
+
+// 解析覆盖的配置值，数值、布尔值以及JSON数组/对象按照JSON解析，其他按照字符串处理
+func parseOverrideValue(value string) interface{} {
+    var result interface{}
+    if err := json.Unmarshal([]byte(value), &result); err == nil && result != nil {
+        if _, ok := result.(string); !ok {
+            return result
+        }
+    }
+    return value
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "strings"
+)
+
+const (
+    gRESOURCE_PATH_PREFIX = "resource://" // 打包资源(gres)中的配置文件路径前缀
+    gDEFAULT_RESOURCE_DIR = "config"      // 打包资源中默认的配置目录
+)
+
+// 配置文件在磁盘中不存在时从打包资源中查找，依次查找配置目录同名的资源目录、默认的config资源目录以及资源根目录
+func (c *Config) searchResource(names []string) string {
+    if gres.IsEmpty() {
+        return ""
+    }
+    dirs := make([]string, 0)
+    for _, path := range c.paths.Slice() {
+        dirs = append(dirs, gfile.Basename(path))
+    }
+    dirs = append(dirs, gDEFAULT_RESOURCE_DIR, "")
+    for _, name := range names {
+        for _, dir := range dirs {
+            if f := gres.Get(dir + "/" + name); f != nil && !f.IsDir() {
+                return gRESOURCE_PATH_PREFIX + f.Path()
+            }
+        }
+    }
+    return ""
+}
+
+// 获取打包资源中的配置文件内容，filePath不是资源路径时返回nil
+func getResourceContent(filePath string) []byte {
+    if !strings.HasPrefix(filePath, gRESOURCE_PATH_PREFIX) {
+        return nil
+    }
+    if content := gres.GetContent(filePath[len(gRESOURCE_PATH_PREFIX) : ]); content != nil {
+        return content
+    }
+    return []byte{}
+}
@@ -0,0 +1,269 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+    "errors"
+    "reflect"
+    "sort"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/util/gvalid"
+)
+
+// 配置项映射到struct时的错误项
+type FieldError struct {
+    Path    string // 配置项的层级路径
+    Rule    string // 失败的规则，缺失的配置项为required，类型转换失败为convert，其他为gvalid的校验规则名称
+    Message string // 错误信息
+}
+
+// 配置项映射到struct时的错误，包含所有缺失、类型转换失败以及校验失败的配置项
+type StructError []*FieldError
+
+func (e *FieldError) Error() string {
+    if e.Path == "" {
+        return e.Message
+    }
+    return e.Path + ": " + e.Message
+}
+
+func (e StructError) Error() string {
+    messages := make([]string, len(e))
+    for i, v := range e {
+        messages[i] = v.Error()
+    }
+    return strings.Join(messages, "; ")
+}
+
+var (
+    timeType  = reflect.TypeOf(time.Time{})
+    gtimeType = reflect.TypeOf(gtime.Time{})
+)
+
+// 将指定配置项(pattern为空时表示整个配置文件)映射到struct对象，pointer应当为struct对象指针：
+// 1、属性对应的配置键名由gconv/json标签指定，否则使用属性名称匹配(忽略大小写及 _ - 空格)；
+// 2、配置项不存在时使用default标签指定的默认值(数值、布尔值以及JSON数组/对象按照JSON解析)；
+// 3、映射完成后使用gvalid标签校验属性值(包括嵌套的struct属性)，gvalid规则包含required时配置项必须存在或者有默认值。
+// 所有缺失以及校验失败的配置项将一次性通过StructError返回，便于在程序启动时发现配置错误。
+func (c *Config) GetStruct(pattern string, pointer interface{}, file...string) error {
+    rv := reflect.ValueOf(pointer)
+    if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+        return errors.New("object pointer should be a non-nil pointer to struct")
+    }
+    j := c.getJson(file...)
+    if j == nil {
+        return errors.New("config file not found")
+    }
+    data, _ := j.Get(pattern).(map[string]interface{})
+    errs    := make(StructError, 0)
+    data     = applyStructDefaults(rv.Elem().Type(), data, pattern, &errs)
+    if err := gjson.New(data).ToStruct(pointer); err != nil {
+        errs = append(errs, &FieldError{Path : pattern, Rule : "convert", Message : err.Error()})
+    } else {
+        validateStruct(rv.Elem(), data, pattern, &errs)
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+
+// 将default标签指定的默认值写入到缺失的配置项中，并记录缺失的必需配置项；
+// 返回新的配置数据，原有的配置数据不会被修改。
+func applyStructDefaults(t reflect.Type, data map[string]interface{}, path string, errs *StructError) map[string]interface{} {
+    result := make(map[string]interface{}, len(data))
+    for k, v := range data {
+        result[k] = v
+    }
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        key   := getStructFieldKey(field)
+        if key == "-" || (field.PkgPath != "" && !field.Anonymous) {
+            continue
+        }
+        ft := field.Type
+        if ft.Kind() == reflect.Ptr {
+            ft = ft.Elem()
+        }
+        isStruct := ft.Kind() == reflect.Struct && ft != timeType && ft != gtimeType
+        // 匿名嵌入的struct属性展开到外层
+        if field.Anonymous && isStruct && key == field.Name {
+            result = applyStructDefaults(ft, result, path, errs)
+            continue
+        }
+        name := matchDataKey(result, key, field.Name)
+        if isStruct {
+            sub, ok := result[name].(map[string]interface{})
+            if name == "" || ok {
+                if name == "" {
+                    name = key
+                }
+                if sub = applyStructDefaults(ft, sub, joinPath(path, name), errs); len(sub) > 0 || ok {
+                    result[name] = sub
+                }
+                continue
+            }
+        }
+        if name != "" {
+            continue
+        }
+        if def, ok := field.Tag.Lookup("default"); ok {
+            result[key] = parseOverrideValue(def)
+        } else if isRequiredField(field) {
+            *errs = append(*errs, &FieldError {
+                Path    : joinPath(path, key),
+                Rule    : "required",
+                Message : "config item is required",
+            })
+        }
+    }
+    return result
+}
+
+// 使用gvalid标签递归校验struct属性，data为属性对应的配置数据(用于获得配置项的实际键名)，
+// 已经记录错误的配置项不再重复记录
+func validateStruct(v reflect.Value, data map[string]interface{}, path string, errs *StructError) {
+    t    := v.Type()
+    keys := make(map[string]string)
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if field.PkgPath != "" && !field.Anonymous {
+            continue
+        }
+        key := getStructFieldKey(field)
+        if name := matchDataKey(data, key, field.Name); name != "" {
+            key = name
+        }
+        keys[field.Name] = key
+        if name, _ := parseValidTag(field.Tag.Get("gvalid")); name != "" {
+            keys[name] = key
+        }
+        fv := v.Field(i)
+        if fv.Kind() == reflect.Ptr {
+            if fv.IsNil() {
+                continue
+            }
+            fv = fv.Elem()
+        }
+        if fv.Kind() == reflect.Struct && fv.Type() != timeType && fv.Type() != gtimeType && key != "-" {
+            if field.Anonymous && getStructFieldKey(field) == field.Name {
+                validateStruct(fv, data, path, errs)
+            } else {
+                sub, _ := data[key].(map[string]interface{})
+                validateStruct(fv, sub, joinPath(path, key), errs)
+            }
+        }
+    }
+    e := gvalid.CheckStruct(v.Interface(), nil)
+    if e == nil {
+        return
+    }
+    names := make([]string, 0)
+    for name := range e.Maps() {
+        // 嵌套struct的校验错误(键名带有层级)在上面递归校验时已经按照配置键名处理
+        if strings.Contains(name, ".") {
+            continue
+        }
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        key, ok := keys[name]
+        if !ok {
+            key = name
+        }
+        itemPath := joinPath(path, key)
+        if errs.contains(itemPath) {
+            continue
+        }
+        rules := make([]string, 0)
+        for rule := range e.Maps()[name] {
+            rules = append(rules, rule)
+        }
+        sort.Strings(rules)
+        for _, rule := range rules {
+            *errs = append(*errs, &FieldError {
+                Path    : itemPath,
+                Rule    : rule,
+                Message : e.Maps()[name][rule],
+            })
+        }
+    }
+}
+
+// 判断指定配置项是否已经存在错误
+func (e StructError) contains(path string) bool {
+    for _, v := range e {
+        if v.Path == path {
+            return true
+        }
+    }
+    return false
+}
+
+// 获得属性对应的配置键名，优先使用gconv标签(第一个键名)，其次使用json标签，否则使用属性名称
+func getStructFieldKey(field reflect.StructField) string {
+    if tag := field.Tag.Get("gconv"); tag != "" {
+        return strings.TrimSpace(strings.Split(tag, ",")[0])
+    }
+    if tag := field.Tag.Get("json"); tag != "" {
+        if name := strings.TrimSpace(strings.Split(tag, ",")[0]); name != "" {
+            return name
+        }
+    }
+    return field.Name
+}
+
+// 查找配置数据中与属性对应的键名，优先精确匹配标签键名，其次忽略大小写及 _ - 空格 匹配，不存在时返回空字符串
+func matchDataKey(data map[string]interface{}, key string, name string) string {
+    if _, ok := data[key]; ok {
+        return key
+    }
+    replacer := strings.NewReplacer("_", "", "-", "", " ", "")
+    for _, v := range []string{key, name} {
+        v = strings.ToLower(replacer.Replace(v))
+        for k := range data {
+            if strings.ToLower(replacer.Replace(k)) == v {
+                return k
+            }
+        }
+    }
+    return ""
+}
+
+// 判断属性的gvalid规则是否包含required
+func isRequiredField(field reflect.StructField) bool {
+    _, rule := parseValidTag(field.Tag.Get("gvalid"))
+    for _, v := range strings.Split(rule, "|") {
+        if strings.TrimSpace(v) == "required" {
+            return true
+        }
+    }
+    return false
+}
+
+// 解析gvalid标签，格式: [别名@]校验规则[#错误提示]，返回别名及校验规则
+func parseValidTag(tag string) (name, rule string) {
+    if i := strings.Index(tag, "#"); i >= 0 {
+        tag = tag[ : i]
+    }
+    if i := strings.Index(tag, "@"); i >= 0 {
+        return strings.TrimSpace(tag[ : i]), strings.TrimSpace(tag[i + 1 : ])
+    }
+    return "", strings.TrimSpace(tag)
+}
+
+// 拼接配置项层级路径
+func joinPath(path, key string) string {
+    if path == "" {
+        return key
+    }
+    return path + "." + key
+}
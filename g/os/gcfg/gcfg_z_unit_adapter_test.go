@@ -0,0 +1,152 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gcfg_test
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "sync"
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gcfg"
+    "github.com/gogf/gf/g/test/gtest"
+)
+
+// 用于测试的配置中心，保存一个键值，并在键值更新时通知等待的监听请求
+type fakeConfigServer struct {
+    mu      sync.Mutex
+    value   string
+    index   int
+    changed chan struct{}
+}
+
+func newFakeConfigServer(value string) *fakeConfigServer {
+    return &fakeConfigServer{value : value, index : 1, changed : make(chan struct{})}
+}
+
+func (s *fakeConfigServer) get() (string, int, chan struct{}) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.value, s.index, s.changed
+}
+
+func (s *fakeConfigServer) set(value string) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.value = value
+    s.index++
+    close(s.changed)
+    s.changed = make(chan struct{})
+}
+
+// Consul KV HTTP API: GET /v1/kv/<key>?raw[&index=<index>&wait=<wait>]
+func (s *fakeConfigServer) consulHandler(w http.ResponseWriter, r *http.Request) {
+    if r.URL.Path != "/v1/kv/app/config.json" {
+        w.Header().Set("X-Consul-Index", "1")
+        w.WriteHeader(http.StatusNotFound)
+        return
+    }
+    value, index, changed := s.get()
+    if i, _ := strconv.Atoi(r.URL.Query().Get("index")); i > 0 && i == index {
+        select {
+            case <- changed:
+            case <- time.After(time.Second):
+            case <- r.Context().Done():
+                return
+        }
+        value, index, _ = s.get()
+    }
+    w.Header().Set("X-Consul-Index", strconv.Itoa(index))
+    fmt.Fprint(w, value)
+}
+
+// etcd v3 HTTP API: POST /v3/kv/range, POST /v3/watch
+func (s *fakeConfigServer) etcdHandler(w http.ResponseWriter, r *http.Request) {
+    value, index, changed := s.get()
+    switch r.URL.Path {
+        case "/v3/kv/range":
+            json.NewEncoder(w).Encode(map[string]interface{} {
+                "header" : map[string]interface{}{"revision" : strconv.Itoa(index)},
+                "kvs"    : []interface{}{map[string]interface{}{"value" : base64.StdEncoding.EncodeToString([]byte(value))}},
+            })
+        case "/v3/watch":
+            json.NewEncoder(w).Encode(map[string]interface{}{"result" : map[string]interface{}{"created" : true}})
+            w.(http.Flusher).Flush()
+            for {
+                select {
+                    case <- changed:
+                    case <- r.Context().Done():
+                        return
+                }
+                _, index, changed = s.get()
+                json.NewEncoder(w).Encode(map[string]interface{} {
+                    "result" : map[string]interface{} {
+                        "header" : map[string]interface{}{"revision" : strconv.Itoa(index)},
+                        "events" : []interface{}{map[string]interface{}{"type" : "PUT"}},
+                    },
+                })
+                w.(http.Flusher).Flush()
+            }
+        default:
+            w.WriteHeader(http.StatusNotFound)
+    }
+}
+
+// 测试适配器的读取及变更通知
+func testConfigAdapter(adapter gcfg.Adapter, server *fakeConfigServer) {
+    c := gcfg.New("")
+    c.SetChangeDelay(100 * time.Millisecond)
+    gtest.Assert(c.SetAdapter(adapter, "config.json"), nil)
+    defer c.RemoveAdapter("config.json")
+    gtest.Assert(c.GetString("name", "config.json"), "john")
+    gtest.Assert(c.GetFilePath("config.json"), "adapter://config.json")
+    changes := make(chan string, 10)
+    c.OnChange("name", func(old, new *gjson.Json) {
+        changes <- old.GetString("name") + "=>" + new.GetString("name")
+    }, "config.json")
+    // 等待监听请求建立
+    time.Sleep(200 * time.Millisecond)
+    server.set(`{"name":"smith"}`)
+    select {
+        case v := <- changes:
+            gtest.Assert(v, "john=>smith")
+        case <- time.After(3 * time.Second):
+            gtest.Fatal("change callback not called")
+    }
+    gtest.Assert(c.GetString("name", "config.json"), "smith")
+}
+
+func TestConfig_ConsulAdapter(t *testing.T) {
+    server := newFakeConfigServer(`{"name":"john"}`)
+    ts     := httptest.NewServer(http.HandlerFunc(server.consulHandler))
+    defer ts.Close()
+    gtest.Case(t, func() {
+        testConfigAdapter(gcfg.NewConsulAdapter(gcfg.ConsulConfig{Address : ts.URL, Key : "app/config.json"}), server)
+    })
+    gtest.Case(t, func() {
+        adapter := gcfg.NewConsulAdapter(gcfg.ConsulConfig{Address : ts.URL, Key : "none"})
+        _, err  := adapter.Load()
+        gtest.AssertNE(err, nil)
+        adapter.Close()
+    })
+}
+
+func TestConfig_EtcdAdapter(t *testing.T) {
+    server := newFakeConfigServer(`{"name":"john"}`)
+    ts     := httptest.NewServer(http.HandlerFunc(server.etcdHandler))
+    defer ts.Close()
+    gtest.Case(t, func() {
+        testConfigAdapter(gcfg.NewEtcdAdapter(gcfg.EtcdConfig{Address : ts.URL, Key : "/app/config.json"}), server)
+    })
+}
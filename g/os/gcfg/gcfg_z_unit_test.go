@@ -0,0 +1,164 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gcfg_test
+
+import (
+    "testing"
+    "time"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gcfg"
+    "github.com/gogf/gf/g/os/genv"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+)
+
+// 创建临时配置目录，返回目录路径
+func createConfigDir(files map[string]string) string {
+    dir := gfile.TempDir() + gfile.Separator + "gcfg_test_" + gconv.String(gtime.Nanosecond())
+    gfile.Mkdir(dir)
+    for name, content := range files {
+        gfile.PutContents(dir + gfile.Separator + name, content)
+    }
+    return dir
+}
+
+func TestConfig_Formats(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := createConfigDir(map[string]string {
+            "config.yaml" : "http:\n  port: 8080\n",
+            "db.toml"     : "[database]\nhost = \"127.0.0.1\"\n",
+        })
+        defer gfile.Remove(dir)
+        // 默认的config.toml不存在时查找config.yaml
+        c := gcfg.New(dir)
+        gtest.Assert(c.GetInt("http.port"), 8080)
+        gtest.Assert(c.GetFilePath(), dir + gfile.Separator + "config.yaml")
+        gtest.Assert(c.GetString("database.host", "db.toml"), "127.0.0.1")
+        gtest.Assert(c.GetString("database.host", "db.json"), "127.0.0.1")
+    })
+}
+
+func TestConfig_EnvOverride(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := createConfigDir(map[string]string {
+            "config.toml" : "[http]\nport = 8080\nmax-conns = 10\n[database]\nmax_idle = 1\n",
+        })
+        defer gfile.Remove(dir)
+        genv.Set("GCFGTEST_HTTP_PORT",          "9090")
+        genv.Set("GCFGTEST_HTTP_MAX_CONNS",     "20")
+        genv.Set("GCFGTEST_DATABASE_MAX_IDLE",  "5")
+        genv.Set("GCFGTEST_LOGGER_LEVEL",       "debug")
+        genv.Set("GCFGTEST_DATABASE_DEBUG",     "true")
+        defer func() {
+            for _, k := range []string{"HTTP_PORT", "HTTP_MAX_CONNS", "DATABASE_MAX_IDLE", "LOGGER_LEVEL", "DATABASE_DEBUG"} {
+                genv.Remove("GCFGTEST_" + k)
+            }
+        }()
+        c := gcfg.New(dir)
+        gtest.Assert(c.GetInt("http.port"), 8080)
+        c.SetEnvPrefix("GCFGTEST")
+        gtest.Assert(c.GetInt("http.port"),          9090)
+        gtest.Assert(c.GetInt("http.max-conns"),     20)
+        gtest.Assert(c.GetInt("database.max_idle"),  5)
+        gtest.Assert(c.Get("database.debug"),        true)
+        gtest.Assert(c.GetString("logger.level"),    "debug")
+        c.SetEnvPrefix("")
+        gtest.Assert(c.GetInt("http.port"),          8080)
+        gtest.Assert(c.Get("logger.level"),          nil)
+    })
+}
+
+func TestConfig_OnChange(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := createConfigDir(map[string]string {
+            "config.toml" : "[logger]\nlevel = \"info\"\n[http]\nport = 8080\n",
+        })
+        defer gfile.Remove(dir)
+        path    := dir + gfile.Separator + "config.toml"
+        count   := gtype.NewInt()
+        levels  := make(chan []string, 10)
+        c       := gcfg.New(dir)
+        c.SetChangeDelay(200 * time.Millisecond)
+        c.OnChange("logger.level", func(old, new *gjson.Json) {
+            count.Add(1)
+            levels <- []string{old.GetString("logger.level"), new.GetString("logger.level")}
+        })
+        // 不相关的配置项变化不会通知
+        gfile.PutContents(path, "[logger]\nlevel = \"info\"\n[http]\nport = 9090\n")
+        time.Sleep(500 * time.Millisecond)
+        gtest.Assert(count.Val(), 0)
+        gtest.Assert(c.GetInt("http.port"), 9090)
+        // 延迟时间内的多次变更只通知一次
+        gfile.PutContents(path, "[logger]\nlevel = \"debug\"\n[http]\nport = 9090\n")
+        time.Sleep(50 * time.Millisecond)
+        gfile.PutContents(path, "[logger]\nlevel = \"error\"\n[http]\nport = 9090\n")
+        select {
+            case v := <- levels:
+                gtest.Assert(v, []string{"info", "error"})
+            case <- time.After(2 * time.Second):
+                t.Error("change callback not called")
+        }
+        time.Sleep(300 * time.Millisecond)
+        gtest.Assert(count.Val(), 1)
+    })
+}
+
+func TestConfig_GetStruct(t *testing.T) {
+    type Redis struct {
+        Host string `gvalid:"required"`
+        Port int    `default:"6379" gvalid:"between:1,65535"`
+    }
+    type Server struct {
+        Address  string        `gconv:"address" gvalid:"required"`
+        Timeout  int           `default:"30"`
+        Hosts    []string      `default:"[\"127.0.0.1\"]"`
+        Email    string        `gvalid:"email"`
+        MaxConns int           `gconv:"max-conns" gvalid:"min:1"`
+        Redis    Redis
+    }
+    dir := createConfigDir(map[string]string {
+        "config.toml" : "[server]\naddress = \":80\"\nmax-conns = 100\n[server.redis]\nhost = \"127.0.0.1\"\n" +
+                        "[invalid]\nemail = \"john\"\nmax-conns = 0\n[invalid.redis]\nport = 70000\n",
+    })
+    defer gfile.Remove(dir)
+    c := gcfg.New(dir)
+    gtest.Case(t, func() {
+        s := new(Server)
+        gtest.Assert(c.GetStruct("server", s), nil)
+        gtest.Assert(s.Address,    ":80")
+        gtest.Assert(s.Timeout,    30)
+        gtest.Assert(s.Hosts,      []string{"127.0.0.1"})
+        gtest.Assert(s.MaxConns,   100)
+        gtest.Assert(s.Redis.Host, "127.0.0.1")
+        gtest.Assert(s.Redis.Port, 6379)
+        // 原有配置不会被默认值修改
+        gtest.Assert(c.Get("server.timeout"), nil)
+    })
+    gtest.Case(t, func() {
+        err := c.GetStruct("invalid", new(Server))
+        gtest.AssertNE(err, nil)
+        paths := make([]string, 0)
+        for _, v := range err.(gcfg.StructError) {
+            paths = append(paths, v.Path + "@" + v.Rule)
+        }
+        gtest.Assert(paths, []string {
+            "invalid.address@required",
+            "invalid.redis.Host@required",
+            "invalid.redis.port@between",
+            "invalid.email@email",
+            "invalid.max-conns@min",
+        })
+    })
+    gtest.Case(t, func() {
+        gtest.AssertNE(c.GetStruct("server", Server{}), nil)
+    })
+}
@@ -21,6 +21,10 @@ const (
     STATUS_STOPPED = gtimer.STATUS_STOPPED
     STATUS_CLOSED  = gtimer.STATUS_CLOSED
 
+    MODE_CONCURRENT = 0 // 任务运行模式：并发运行，上一次运行未结束时也会开始新的运行(默认)
+    MODE_SKIP       = 1 // 任务运行模式：上一次运行未结束时跳过本次运行
+    MODE_QUEUE      = 2 // 任务运行模式：上一次运行未结束时将本次运行加入队列，等待运行结束后依次执行
+
     gDEFAULT_TIMES = math.MaxInt32
 )
 
@@ -34,6 +38,11 @@ func Add(pattern string, job func(), name ... string) (*Entry, error) {
     return defaultCron.Add(pattern, job, name...)
 }
 
+// 使用选项添加定时任务，例如: gcron.AddEntry("0 0 9 * * *", job, gcron.WithLocation(loc))
+func AddEntry(pattern string, job func(), options...EntryOption) (*Entry, error) {
+    return defaultCron.AddEntry(pattern, job, options...)
+}
+
 // 添加单例运行定时任务
 func AddSingleton(pattern string, job func(), name ... string) (*Entry, error) {
     return defaultCron.AddSingleton(pattern, job, name...)
@@ -98,3 +107,18 @@ func Start(name string) {
 func Stop(name string) {
     defaultCron.Stop(name)
 }
+
+// 设置默认定时任务管理对象的持久化存储
+func SetStore(store Store) {
+    defaultCron.SetStore(store)
+}
+
+// 设置默认定时任务管理对象错过运行的处理策略
+func SetMissedPolicy(policy int) {
+    defaultCron.SetMissedPolicy(policy)
+}
+
+// 设置默认定时任务管理对象的任务错误处理方法
+func SetErrorHandler(handler ErrorHandler) {
+    defaultCron.SetErrorHandler(handler)
+}
@@ -7,8 +7,6 @@
 package gcron
 
 import (
-    "errors"
-    "fmt"
     "github.com/gogf/gf/g/container/garray"
     "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/container/gtype"
@@ -18,28 +16,32 @@ import (
 
 // 定时任务管理对象
 type Cron struct {
-    idgen    *gtype.Int               // 用于唯一名称生成
-    status   *gtype.Int               // 定时任务状态(0: 未执行; 1: 运行中; 2: 已停止; -1:删除关闭)
-    entries  *gmap.StringInterfaceMap // 所有的定时任务项
+    idgen        *gtype.Int               // 用于唯一名称生成
+    status       *gtype.Int               // 定时任务状态(0: 未执行; 1: 运行中; 2: 已停止; -1:删除关闭)
+    entries      *gmap.StringInterfaceMap // 所有的定时任务项
+    store        *gtype.Interface         // 定时任务持久化存储(Store)
+    missedPolicy *gtype.Int               // 错过运行的处理策略
+    errorHandler *gtype.Interface         // 任务错误处理方法
 }
 
 // 创建自定义的定时任务管理对象
 func New() *Cron {
     return &Cron {
-        idgen    : gtype.NewInt(1000000),
-        status   : gtype.NewInt(STATUS_RUNNING),
-        entries  : gmap.NewStringInterfaceMap(),
+        idgen        : gtype.NewInt(1000000),
+        status       : gtype.NewInt(STATUS_RUNNING),
+        entries      : gmap.NewStringInterfaceMap(),
+        store        : gtype.NewInterface(),
+        missedPolicy : gtype.NewInt(MISSED_SKIP),
+        errorHandler : gtype.NewInterface(),
     }
 }
 
 // 添加定时任务
 func (c *Cron) Add(pattern string, job func(), name ... string) (*Entry, error) {
     if len(name) > 0 {
-        if c.Search(name[0]) != nil {
-            return nil, errors.New(fmt.Sprintf(`cron job "%s" already exists`, name[0]))
-        }
+        return c.AddEntry(pattern, job, WithName(name[0]))
     }
-    return c.addEntry(pattern, job, false, gDEFAULT_TIMES, name...)
+    return c.AddEntry(pattern, job)
 }
 
 // 添加单例运行定时任务
@@ -7,52 +7,107 @@
 package gcron
 
 import (
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/internal/mutex"
     "github.com/gogf/gf/g/os/gtimer"
     "strconv"
     "time"
 )
 
-// 定时任务项
+// 定时任务项，创建之后会被修改的属性均为并发安全的对象，可以在任务运行期间读取
 type Entry struct {
-    cron       *Cron         // 所属定时任务
-    entry      *gtimer.Entry // 定时器任务对象
-    schedule   *cronSchedule // 定时任务配置对象
-    Name       string        // 定时任务名称
-    Job        func()        // 注册定时任务方法
-    Time       time.Time     // 注册时间
+    cron          *Cron            // 所属定时任务
+    entry         *gtimer.Entry    // 定时器任务对象
+    schedule      *cronSchedule    // 定时任务配置对象
+    mode          *gtype.Int       // 任务运行模式
+    maxConcurrent *gtype.Int       // 最大同时运行数量(0表示使用运行模式的默认值)
+    mu            *mutex.Mutex     // 运行计数互斥锁，running及queued只在持有锁时修改
+    running       *gtype.Int       // 正在运行的数量
+    queued        *gtype.Int       // 等待运行的数量(MODE_QUEUE)
+    persistent    bool             // 是否记录到持久化存储(只记录指定名称的定时任务)
+    location      *gtype.Interface // 定时格式所在的时区(*time.Location)
+    lastCheck     *gtype.Int64     // 最近一次检查的时间(纳秒时间戳，0表示未检查)，用于夏令时切换的判断
+    failures      *gtype.Int       // 连续失败次数
+    maxFailures   *gtype.Int       // 连续失败次数阈值
+    Name          string           // 定时任务名称
+    Job           func()           // 注册定时任务方法
+    Time          time.Time        // 注册时间
 }
 
 // 创建定时任务
-func (c *Cron) addEntry(pattern string, job func(), singleton bool, times int, name ... string) (*Entry, error) {
+func (c *Cron) addEntry(pattern string, job func(), option *entryOption) (*Entry, error) {
     schedule, err := newSchedule(pattern)
     if err != nil {
         return nil, err
     }
     entry := &Entry {
-        cron      : c,
-        schedule  : schedule,
-        Job       : job,
-        Time      : time.Now(),
+        cron          : c,
+        schedule      : schedule,
+        mode          : gtype.NewInt(option.mode),
+        maxConcurrent : gtype.NewInt(option.maxConcurrent),
+        mu            : mutex.New(),
+        running       : gtype.NewInt(),
+        queued        : gtype.NewInt(),
+        location      : gtype.NewInterface(option.location),
+        lastCheck     : gtype.NewInt64(),
+        failures      : gtype.NewInt(),
+        maxFailures   : gtype.NewInt(),
+        Job           : job,
+        Time          : time.Now(),
     }
-    if len(name) > 0 {
-        entry.Name = name[0]
+    if option.name != "" {
+        entry.Name       = option.name
+        entry.persistent = c.GetStore() != nil
     } else {
         entry.Name = strconv.Itoa(c.idgen.Add(1))
     }
-    entry.entry = gtimer.AddEntry(time.Second, entry.check, singleton, times, gtimer.STATUS_STOPPED)
+    // 任务的运行模式由定时任务自身控制，定时器任务不使用单例模式
+    entry.entry = gtimer.AddEntry(time.Second, entry.check, false, option.times, gtimer.STATUS_STOPPED)
     entry.entry.Start()
     c.entries.Set(entry.Name, entry)
+    if entry.persistent {
+        c.restoreEntry(entry)
+    }
     return entry, nil
 }
 
-// 是否单例运行
+// 是否单例运行(MODE_SKIP)
 func (entry *Entry) IsSingleton() bool {
-    return entry.entry.IsSingleton()
+    return entry.mode.Val() == MODE_SKIP
 }
 
-// 设置单例运行
+// 设置单例运行，enabled为true时等同于SetMode(MODE_SKIP)，否则等同于SetMode(MODE_CONCURRENT)
 func (entry *Entry) SetSingleton(enabled bool) {
-    entry.entry.SetSingleton(true)
+    if enabled {
+        entry.mode.Set(MODE_SKIP)
+    } else {
+        entry.mode.Set(MODE_CONCURRENT)
+    }
+}
+
+// 获取任务运行模式
+func (entry *Entry) Mode() int {
+    return entry.mode.Val()
+}
+
+// 设置任务运行模式(MODE_CONCURRENT/MODE_SKIP/MODE_QUEUE)，决定上一次运行未结束时如何处理新的运行
+func (entry *Entry) SetMode(mode int) {
+    entry.mode.Set(mode)
+}
+
+// 获取最大同时运行数量，0表示使用运行模式的默认值(MODE_CONCURRENT不限制，MODE_SKIP/MODE_QUEUE为1)
+func (entry *Entry) MaxConcurrent() int {
+    return entry.maxConcurrent.Val()
+}
+
+// 设置最大同时运行数量，达到数量时新的运行将按照运行模式被跳过(MODE_CONCURRENT/MODE_SKIP)或者加入队列(MODE_QUEUE)
+func (entry *Entry) SetMaxConcurrent(max int) {
+    entry.maxConcurrent.Set(max)
+}
+
+// 获取正在运行的数量
+func (entry *Entry) Running() int {
+    return entry.running.Val()
 }
 
 // 设置任务的运行次数
@@ -70,8 +125,9 @@ func (entry *Entry) SetStatus(status int) int {
     return entry.entry.SetStatus(status)
 }
 
-// 启动定时任务
+// 启动定时任务(同时清除连续失败次数)
 func (entry *Entry) Start() {
+    entry.failures.Set(0)
     entry.entry.Start()
 }
 
@@ -86,9 +142,27 @@ func (entry *Entry) Close() {
     entry.entry.Close()
 }
 
+// 获取定时格式所在的时区
+func (entry *Entry) Location() *time.Location {
+    return entry.location.Val().(*time.Location)
+}
+
+// 设置定时格式所在的时区
+func (entry *Entry) SetLocation(location *time.Location) {
+    if location == nil {
+        location = time.Local
+    }
+    entry.location.Set(location)
+}
+
 // 定时任务检查执行
 func (entry *Entry) check() {
-    if entry.schedule.meet(time.Now()) {
+    now  := time.Now().In(entry.Location())
+    last := time.Time{}
+    if n := entry.lastCheck.Set(now.UnixNano()); n > 0 {
+        last = time.Unix(0, n)
+    }
+    if entry.schedule.meetAt(now, last) {
         switch entry.cron.status.Val() {
             case STATUS_STOPPED:
                 return
@@ -104,7 +178,80 @@ func (entry *Entry) check() {
                         entry.cron.Remove(entry.Name)
                     }
                 }()
-                entry.Job()
+                entry.run()
         }
     }
 }
+
+// 按照运行模式执行任务，MODE_QUEUE模式下会在运行结束后继续执行队列中等待的运行
+func (entry *Entry) run() {
+    entry.mu.Lock()
+    if limit := entry.limit(); limit > 0 && entry.running.Val() >= limit {
+        if entry.mode.Val() == MODE_QUEUE {
+            entry.queued.Add(1)
+        }
+        entry.mu.Unlock()
+        return
+    }
+    entry.running.Add(1)
+    entry.mu.Unlock()
+    defer func() {
+        entry.mu.Lock()
+        entry.running.Add(-1)
+        entry.mu.Unlock()
+    }()
+    for {
+        entry.saveLastRun(time.Now())
+        if !entry.call() || !entry.dequeue() {
+            return
+        }
+    }
+}
+
+// 按顺序补运行错过的运行，times为错过的运行时间，补运行期间计入正在运行的数量
+func (entry *Entry) runMissed(times []time.Time) {
+    entry.mu.Lock()
+    entry.running.Add(1)
+    entry.mu.Unlock()
+    defer func() {
+        entry.mu.Lock()
+        entry.running.Add(-1)
+        entry.mu.Unlock()
+    }()
+    for _, t := range times {
+        if entry.entry.Status() == STATUS_CLOSED || entry.cron.status.Val() == STATUS_CLOSED {
+            return
+        }
+        entry.saveLastRun(t)
+        if !entry.call() {
+            return
+        }
+    }
+}
+
+// 从队列中取出一次等待的运行，任务或者定时任务管理对象已停止时清空队列
+func (entry *Entry) dequeue() bool {
+    entry.mu.Lock()
+    defer entry.mu.Unlock()
+    if entry.queued.Val() == 0 {
+        return false
+    }
+    if entry.entry.Status() == STATUS_STOPPED || entry.entry.Status() == STATUS_CLOSED ||
+        entry.cron.status.Val() == STATUS_STOPPED || entry.cron.status.Val() == STATUS_CLOSED {
+        entry.queued.Set(0)
+        return false
+    }
+    entry.queued.Add(-1)
+    return true
+}
+
+// 获得当前的最大同时运行数量，0表示不限制
+func (entry *Entry) limit() int {
+    if max := entry.maxConcurrent.Val(); max > 0 {
+        return max
+    }
+    if entry.mode.Val() == MODE_CONCURRENT {
+        return 0
+    }
+    return 1
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+    "fmt"
+    "strings"
+    "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/os/gtimer"
+)
+
+// 定时任务运行错误
+type JobError = gtimer.JobError
+
+// 定时任务错误处理方法
+type ErrorHandler = func(entry *Entry, err *JobError)
+
+// 将返回错误的任务方法包装为任务方法，任务返回的错误将被交给错误处理方法处理并计入连续失败次数，例如:
+// gcron.Add("0 */5 * * * *", gcron.ErrorJob(func() error { return sync() }))
+func ErrorJob(job func() error) func() {
+    return gtimer.ErrorJob(job)
+}
+
+// 设置任务错误处理方法，为nil时使用默认的错误处理方法(使用glog输出错误及调用栈)
+func (c *Cron) SetErrorHandler(handler ErrorHandler) {
+    c.errorHandler.Set(handler)
+}
+
+// 设置任务连续失败次数的阈值，达到阈值时任务将被停止(可通过Start重新启动)，0表示不限制
+func (entry *Entry) SetMaxFailures(max int) {
+    entry.maxFailures.Set(max)
+}
+
+// 获取任务连续失败次数的阈值
+func (entry *Entry) MaxFailures() int {
+    return entry.maxFailures.Val()
+}
+
+// 获取任务连续失败次数，任务成功运行后清零
+func (entry *Entry) Failures() int {
+    return entry.failures.Val()
+}
+
+// 执行一次任务方法，捕获panic以及返回的错误，任务中调用gtimer.Exit时关闭任务并返回false
+func (entry *Entry) call() bool {
+    exit, err := gtimer.Call(entry.Job)
+    switch {
+        case exit:
+            entry.Close()
+            return false
+        case err != nil:
+            entry.fail(err)
+        default:
+            entry.failures.Set(0)
+    }
+    return true
+}
+
+// 记录任务运行失败，达到连续失败次数阈值时停止任务，并调用错误处理方法
+func (entry *Entry) fail(err *JobError) {
+    err.Failures = entry.failures.Add(1)
+    if max := entry.maxFailures.Val(); max > 0 && err.Failures >= max {
+        entry.Stop()
+        err.Disabled = true
+    }
+    handler, _ := entry.cron.errorHandler.Val().(ErrorHandler)
+    if handler == nil {
+        handler = defaultErrorHandler
+    }
+    handler(entry, err)
+}
+
+// 默认的任务错误处理方法
+func defaultErrorHandler(entry *Entry, err *JobError) {
+    content := fmt.Sprintf(`[gcron] job "%s" failed (%d consecutive): %s`, entry.Name, err.Failures, err.Error())
+    if err.Disabled {
+        content += ", job stopped after reaching max failures"
+    }
+    if err.Stack != "" {
+        content += "\n" + strings.TrimRight(err.Stack, "\n")
+    }
+    glog.Backtrace(false).Errorfln("%s", content)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+    "errors"
+    "fmt"
+    "time"
+)
+
+// 定时任务选项，用于AddEntry
+type EntryOption func(option *entryOption)
+
+// 定时任务选项集合
+type entryOption struct {
+    name          string         // 定时任务名称
+    location      *time.Location // 定时格式所在的时区
+    mode          int            // 任务运行模式
+    maxConcurrent int            // 最大同时运行数量
+    times         int            // 运行次数
+}
+
+// 指定定时任务名称，以便于后续检索、删除以及持久化记录
+func WithName(name string) EntryOption {
+    return func(option *entryOption) {
+        option.name = name
+    }
+}
+
+// 指定定时格式所在的时区，默认为本地时区，例如: time.LoadLocation("Asia/Shanghai")
+func WithLocation(location *time.Location) EntryOption {
+    return func(option *entryOption) {
+        option.location = location
+    }
+}
+
+// 指定任务运行模式(MODE_CONCURRENT/MODE_SKIP/MODE_QUEUE)
+func WithMode(mode int) EntryOption {
+    return func(option *entryOption) {
+        option.mode = mode
+    }
+}
+
+// 指定最大同时运行数量
+func WithMaxConcurrent(max int) EntryOption {
+    return func(option *entryOption) {
+        option.maxConcurrent = max
+    }
+}
+
+// 指定运行次数
+func WithTimes(times int) EntryOption {
+    return func(option *entryOption) {
+        option.times = times
+    }
+}
+
+// 使用选项添加定时任务，例如:
+// gcron.AddEntry("0 0 9 * * 1-5", job, gcron.WithName("report"), gcron.WithLocation(loc))
+func (c *Cron) AddEntry(pattern string, job func(), options...EntryOption) (*Entry, error) {
+    option := &entryOption {
+        location : time.Local,
+        mode     : MODE_CONCURRENT,
+        times    : gDEFAULT_TIMES,
+    }
+    for _, f := range options {
+        f(option)
+    }
+    if option.location == nil {
+        option.location = time.Local
+    }
+    if option.name != "" && c.Search(option.name) != nil {
+        return nil, errors.New(fmt.Sprintf(`cron job "%s" already exists`, option.name))
+    }
+    return c.addEntry(pattern, job, option)
+}
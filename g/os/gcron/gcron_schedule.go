@@ -242,4 +242,118 @@ func (s *cronSchedule) meet(t time.Time) bool {
         }
         return true
     }
-}
\ No newline at end of file
+}
+
+// 判断给定的时间是否满足schedule，并按照给定时间所在的时区处理夏令时切换：
+// 1、时钟回拨时重复出现的本地时间只在第一次出现时满足；
+// 2、时钟拨快时被跳过的本地时间在切换之后的第一次检查时满足。
+// last为上一次检查的时间，为零值时不处理被跳过的本地时间。
+func (s *cronSchedule) meetAt(t time.Time, last time.Time) bool {
+    if s.every != 0 {
+        return s.meet(t)
+    }
+    if s.isRepeated(t) {
+        return false
+    }
+    if s.meet(t) {
+        return true
+    }
+    if last.IsZero() || !t.After(last) || t.Sub(last) > time.Minute {
+        return false
+    }
+    last = last.In(t.Location())
+    _, offset     := t.Zone()
+    _, lastOffset := last.Zone()
+    if offset <= lastOffset {
+        return false
+    }
+    // 检查两次检查之间被跳过的本地时间
+    to := wallTime(t)
+    for w := wallTime(last).Add(time.Second); w.Before(to); w = w.Add(time.Second) {
+        if s.meet(w) {
+            return true
+        }
+    }
+    return false
+}
+
+// 判断给定的时间是否为时钟回拨后第二次出现的本地时间
+func (s *cronSchedule) isRepeated(t time.Time) bool {
+    _, offset := t.Zone()
+    for _, d := range []time.Duration{30*time.Minute, time.Hour, 2*time.Hour} {
+        if _, o := t.Add(-d).Zone(); time.Duration(o - offset)*time.Second == d {
+            return true
+        }
+    }
+    return false
+}
+
+// 将给定时间的本地时间转换为UTC时区的相同本地时间，用于不受夏令时影响的本地时间计算
+func wallTime(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// 获得给定时间之后(不包含给定时间)第一个满足schedule的时间，5年内不存在满足条件的时间时返回零值
+func (s *cronSchedule) next(t time.Time) time.Time {
+    if s.every != 0 {
+        create := time.Unix(s.create, 0).In(t.Location())
+        if !t.After(create) {
+            return create.Add(time.Duration(s.every)*time.Second)
+        }
+        n := (t.Unix() - s.create)/s.every + 1
+        return create.Add(time.Duration(n*s.every)*time.Second)
+    }
+    t      = t.Truncate(time.Second).Add(time.Second)
+    limit := t.AddDate(5, 0, 0)
+    for t.Before(limit) {
+        if _, ok := s.month[int(t.Month())]; !ok {
+            t = s.jump(t, time.Date(t.Year(), t.Month() + 1, 1, 0, 0, 0, 0, t.Location()))
+            continue
+        }
+        _, dayOk  := s.day[t.Day()]
+        _, weekOk := s.week[int(t.Weekday())]
+        if !dayOk || !weekOk {
+            t = s.jump(t, time.Date(t.Year(), t.Month(), t.Day() + 1, 0, 0, 0, 0, t.Location()))
+            continue
+        }
+        // 小时以内的跳转使用时间差计算，避免夏令时切换时time.Date返回重复的时间
+        if _, ok := s.hour[t.Hour()]; !ok {
+            t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+            continue
+        }
+        if _, ok := s.minute[t.Minute()]; !ok {
+            t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+            continue
+        }
+        if _, ok := s.second[t.Second()]; !ok || s.isRepeated(t) {
+            t = t.Add(time.Second)
+            continue
+        }
+        return t
+    }
+    return time.Time{}
+}
+
+// 跳转到指定时间，保证时间向后推进
+func (s *cronSchedule) jump(from, to time.Time) time.Time {
+    if !to.After(from) {
+        return from.Add(time.Hour)
+    }
+    return to
+}
+
+// 获得(from, to]时间范围内满足schedule的时间，最多返回limit个
+func (s *cronSchedule) missed(from, to time.Time, limit int) []time.Time {
+    times := make([]time.Time, 0)
+    // 按照间隔运行的定时任务以上一次运行时间为基准计算
+    if s.every != 0 {
+        for t := from.Add(time.Duration(s.every)*time.Second); !t.After(to) && len(times) < limit; t = t.Add(time.Duration(s.every)*time.Second) {
+            times = append(times, t)
+        }
+        return times
+    }
+    for t := s.next(from); !t.IsZero() && !t.After(to) && len(times) < limit; t = s.next(t) {
+        times = append(times, t)
+    }
+    return times
+}
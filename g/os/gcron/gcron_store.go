@@ -0,0 +1,117 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+    "time"
+    "github.com/gogf/gf/g/os/glog"
+)
+
+const (
+    MISSED_SKIP     = 0 // 错过运行的处理策略：跳过错过的运行(默认)
+    MISSED_RUN_ALL  = 1 // 错过运行的处理策略：按顺序补运行所有错过的运行
+    MISSED_RUN_ONCE = 2 // 错过运行的处理策略：存在错过的运行时补运行一次
+
+    gMAX_MISSED_RUNS = 1000 // MISSED_RUN_ALL策略下最多补运行的次数
+)
+
+// 定时任务持久化记录
+type StoreRecord struct {
+    Name    string `json:"name"`     // 定时任务名称
+    Pattern string `json:"pattern"`  // 定时格式
+    LastRun int64  `json:"last_run"` // 最近一次运行时间戳(秒)，0表示未运行过
+}
+
+// 定时任务持久化存储接口，用于记录定时任务的定时格式以及最近一次运行时间，
+// 以便于进程重启后重新注册同名定时任务时，按照错过运行的处理策略补运行重启期间错过的运行。
+// 内置的实现有基于本地文件的NewFileStore，基于数据库的gdb.NewCronStore以及基于Redis的gredis.NewCronStore。
+type Store interface {
+    // 获取指定名称的定时任务记录，记录不存在时返回nil
+    Get(name string) (*StoreRecord, error)
+    // 保存定时任务记录
+    Set(record *StoreRecord) error
+    // 删除指定名称的定时任务记录
+    Remove(name string) error
+}
+
+// 设置定时任务持久化存储，只有指定名称的定时任务会被记录，应当在添加定时任务之前设置
+func (c *Cron) SetStore(store Store) {
+    c.store.Set(store)
+}
+
+// 获取定时任务持久化存储，未设置时返回nil
+func (c *Cron) GetStore() Store {
+    if v := c.store.Val(); v != nil {
+        return v.(Store)
+    }
+    return nil
+}
+
+// 设置错过运行的处理策略(MISSED_SKIP/MISSED_RUN_ALL/MISSED_RUN_ONCE)，在添加已有持久化记录的定时任务时生效
+func (c *Cron) SetMissedPolicy(policy int) {
+    c.missedPolicy.Set(policy)
+}
+
+// 获取错过运行的处理策略
+func (c *Cron) GetMissedPolicy() int {
+    return c.missedPolicy.Val()
+}
+
+// 根据持久化记录恢复定时任务，按照错过运行的处理策略补运行错过的运行，并更新记录的定时格式
+func (c *Cron) restoreEntry(entry *Entry) {
+    store := c.GetStore()
+    if store == nil {
+        return
+    }
+    record, err := store.Get(entry.Name)
+    if err != nil {
+        glog.Errorfln(`[gcron] get record of job "%s" failed: %s`, entry.Name, err.Error())
+        return
+    }
+    if record == nil {
+        record = &StoreRecord{Name : entry.Name}
+    }
+    missed  := make([]time.Time, 0)
+    lastRun := time.Unix(record.LastRun, 0).In(entry.Location())
+    if record.LastRun > 0 {
+        switch c.missedPolicy.Val() {
+            case MISSED_RUN_ALL:
+                missed = entry.schedule.missed(lastRun, time.Now(), gMAX_MISSED_RUNS)
+            case MISSED_RUN_ONCE:
+                // 只补运行一次，运行时间记录为当前时间
+                if len(entry.schedule.missed(lastRun, time.Now(), 1)) > 0 {
+                    missed = append(missed, time.Now())
+                }
+        }
+    }
+    record.Pattern = entry.schedule.pattern
+    if err := store.Set(record); err != nil {
+        glog.Errorfln(`[gcron] save record of job "%s" failed: %s`, entry.Name, err.Error())
+    }
+    if len(missed) > 0 {
+        go entry.runMissed(missed)
+    }
+}
+
+// 记录定时任务的最近一次运行时间
+func (entry *Entry) saveLastRun(t time.Time) {
+    if !entry.persistent {
+        return
+    }
+    store := entry.cron.GetStore()
+    if store == nil {
+        return
+    }
+    record := &StoreRecord {
+        Name    : entry.Name,
+        Pattern : entry.schedule.pattern,
+        LastRun : t.Unix(),
+    }
+    if err := store.Set(record); err != nil {
+        glog.Errorfln(`[gcron] save record of job "%s" failed: %s`, entry.Name, err.Error())
+    }
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+    "encoding/json"
+    "sync"
+    "github.com/gogf/gf/g/os/gfile"
+)
+
+// 基于本地文件的定时任务持久化存储，所有记录以JSON格式保存在同一个文件中
+type fileStore struct {
+    mu   sync.Mutex
+    path string
+}
+
+// 创建基于本地文件的定时任务持久化存储，文件不存在时自动创建
+func NewFileStore(path string) Store {
+    return &fileStore {
+        path : path,
+    }
+}
+
+func (s *fileStore) Get(name string) (*StoreRecord, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    records, err := s.load()
+    if err != nil {
+        return nil, err
+    }
+    return records[name], nil
+}
+
+func (s *fileStore) Set(record *StoreRecord) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    records, err := s.load()
+    if err != nil {
+        return err
+    }
+    records[record.Name] = record
+    return s.save(records)
+}
+
+func (s *fileStore) Remove(name string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    records, err := s.load()
+    if err != nil {
+        return err
+    }
+    if _, ok := records[name]; !ok {
+        return nil
+    }
+    delete(records, name)
+    return s.save(records)
+}
+
+// 读取文件中的所有记录
+func (s *fileStore) load() (map[string]*StoreRecord, error) {
+    records := make(map[string]*StoreRecord)
+    if content := gfile.GetBinContents(s.path); len(content) > 0 {
+        if err := json.Unmarshal(content, &records); err != nil {
+            return nil, err
+        }
+    }
+    return records, nil
+}
+
+// 将所有记录写入文件
+func (s *fileStore) save(records map[string]*StoreRecord) error {
+    content, err := json.Marshal(records)
+    if err != nil {
+        return err
+    }
+    return gfile.PutBinContents(s.path, content)
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+
+package gcron_test
+
+import (
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/os/gcron"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestCron_Entry_Mode(t *testing.T) {
+    // 默认并发运行
+    gtest.Case(t, func() {
+        cron    := gcron.New()
+        count   := gtype.NewInt()
+        _, err  := cron.Add("* * * * * *", func() {
+            count.Add(1)
+            time.Sleep(1500*time.Millisecond)
+        })
+        gtest.Assert(err, nil)
+        time.Sleep(3500*time.Millisecond)
+        gtest.Assert(count.Val(), 3)
+        cron.Close()
+    })
+    // 上一次运行未结束时跳过
+    gtest.Case(t, func() {
+        cron       := gcron.New()
+        count      := gtype.NewInt()
+        entry, err := cron.Add("* * * * * *", func() {
+            count.Add(1)
+            time.Sleep(1500*time.Millisecond)
+        })
+        gtest.Assert(err, nil)
+        entry.SetMode(gcron.MODE_SKIP)
+        gtest.Assert(entry.IsSingleton(), true)
+        time.Sleep(3500*time.Millisecond)
+        gtest.Assert(count.Val(), 2)
+        cron.Close()
+    })
+    // 上一次运行未结束时加入队列
+    gtest.Case(t, func() {
+        cron       := gcron.New()
+        count      := gtype.NewInt()
+        entry, err := cron.Add("* * * * * *", func() {
+            count.Add(1)
+            time.Sleep(1200*time.Millisecond)
+        })
+        gtest.Assert(err, nil)
+        entry.SetMode(gcron.MODE_QUEUE)
+        gtest.Assert(entry.IsSingleton(), false)
+        time.Sleep(2600*time.Millisecond)
+        gtest.Assert(count.Val(), 2)
+        gtest.Assert(entry.Running(), 1)
+        cron.Close()
+    })
+}
+
+func TestCron_Entry_MaxConcurrent(t *testing.T) {
+    gtest.Case(t, func() {
+        cron       := gcron.New()
+        count      := gtype.NewInt()
+        entry, err := cron.Add("* * * * * *", func() {
+            count.Add(1)
+            time.Sleep(3000*time.Millisecond)
+        })
+        gtest.Assert(err, nil)
+        entry.SetMaxConcurrent(2)
+        gtest.Assert(entry.MaxConcurrent(), 2)
+        time.Sleep(3500*time.Millisecond)
+        gtest.Assert(count.Val(), 2)
+        gtest.Assert(entry.Running(), 2)
+        cron.Close()
+    })
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+
+package gcron_test
+
+import (
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/os/gcron"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/test/gtest"
+    "os"
+    "testing"
+    "time"
+)
+
+func TestCron_Store(t *testing.T) {
+    path := gfile.TempDir() + gfile.Separator + "gcron_store_test.json"
+    defer os.Remove(path)
+    gtest.Case(t, func() {
+        store  := gcron.NewFileStore(path)
+        record := &gcron.StoreRecord {
+            Name    : "job",
+            Pattern : "0 * * * * *",
+            LastRun : 100,
+        }
+        gtest.Assert(store.Set(record), nil)
+        r, err := store.Get("job")
+        gtest.Assert(err, nil)
+        gtest.Assert(r, record)
+        r, err = store.Get("none")
+        gtest.Assert(err, nil)
+        gtest.Assert(r == nil, true)
+        gtest.Assert(store.Remove("job"), nil)
+        r, err = store.Get("job")
+        gtest.Assert(err, nil)
+        gtest.Assert(r == nil, true)
+    })
+    // 记录最近运行时间
+    gtest.Case(t, func() {
+        cron   := gcron.New()
+        store  := gcron.NewFileStore(path)
+        count  := gtype.NewInt()
+        cron.SetStore(store)
+        _, err := cron.Add("* * * * * *", func() {
+            count.Add(1)
+        }, "record")
+        gtest.Assert(err, nil)
+        time.Sleep(1500*time.Millisecond)
+        cron.Close()
+        gtest.Assert(count.Val(), 1)
+        r, err := store.Get("record")
+        gtest.Assert(err, nil)
+        gtest.Assert(r.Pattern, "* * * * * *")
+        gtest.AssertGT(r.LastRun, time.Now().Unix() - 3)
+    })
+}
+
+func TestCron_MissedPolicy(t *testing.T) {
+    path := gfile.TempDir() + gfile.Separator + "gcron_missed_test.json"
+    defer os.Remove(path)
+    lastRun := time.Now().Add(-time.Hour).Truncate(time.Minute).Unix()
+    cases   := map[int]int {
+        gcron.MISSED_SKIP     : 0,
+        gcron.MISSED_RUN_ALL  : 60,
+        gcron.MISSED_RUN_ONCE : 1,
+    }
+    for policy, expect := range cases {
+        gtest.Case(t, func() {
+            store := gcron.NewFileStore(path)
+            gtest.Assert(store.Set(&gcron.StoreRecord{Name : "missed", LastRun : lastRun}), nil)
+            cron  := gcron.New()
+            count := gtype.NewInt()
+            cron.SetStore(store)
+            cron.SetMissedPolicy(policy)
+            // 每分钟的第0秒运行，1个小时内错过60次运行
+            _, err := cron.Add("0 * * * * *", func() {
+                count.Add(1)
+            }, "missed")
+            gtest.Assert(err, nil)
+            time.Sleep(300*time.Millisecond)
+            cron.Close()
+            gtest.Assert(count.Val(), expect)
+            r, err := store.Get("missed")
+            gtest.Assert(err, nil)
+            gtest.Assert(r.Pattern, "0 * * * * *")
+            if expect > 0 {
+                gtest.AssertGT(r.LastRun, time.Now().Unix() - 61)
+            } else {
+                gtest.Assert(r.LastRun, lastRun)
+            }
+        })
+    }
+}
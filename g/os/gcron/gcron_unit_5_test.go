@@ -0,0 +1,71 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+
+package gcron
+
+import (
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/test/gtest"
+    "strconv"
+    "testing"
+    "time"
+)
+
+func TestSchedule_DST(t *testing.T) {
+    loc, err := time.LoadLocation("America/New_York")
+    if err != nil {
+        t.Skip(err)
+    }
+    // 2019-03-10 02:00:00 时钟拨快到 03:00:00
+    gtest.Case(t, func() {
+        s, err := newSchedule("0 30 2 * * *")
+        gtest.Assert(err, nil)
+        last := time.Date(2019, 3, 10, 1, 59, 59, 0, loc)
+        now  := last.Add(time.Second)
+        gtest.Assert(now.Hour(), 3)
+        gtest.Assert(s.meetAt(now, last), true)
+        gtest.Assert(s.meetAt(now.Add(time.Second), now), false)
+        s, err = newSchedule("0 30 4 * * *")
+        gtest.Assert(err, nil)
+        gtest.Assert(s.meetAt(now, last), false)
+    })
+    // 2019-11-03 02:00:00 时钟回拨到 01:00:00
+    gtest.Case(t, func() {
+        s, err := newSchedule("0 30 1 * * *")
+        gtest.Assert(err, nil)
+        first  := time.Date(2019, 11, 3, 5, 30, 0, 0, time.UTC).In(loc)
+        second := first.Add(time.Hour)
+        gtest.Assert(first.Hour(), 1)
+        gtest.Assert(second.Hour(), 1)
+        gtest.Assert(s.meetAt(first, first.Add(-time.Second)), true)
+        gtest.Assert(s.meetAt(second, second.Add(-time.Second)), false)
+        gtest.Assert(s.next(first.Add(-time.Second)), first)
+        gtest.Assert(s.next(first).Equal(time.Date(2019, 11, 4, 1, 30, 0, 0, loc)), true)
+    })
+}
+
+func TestCron_WithLocation(t *testing.T) {
+    gtest.Case(t, func() {
+        local  := time.FixedZone("TEST", 0)
+        other  := time.FixedZone("TEST", 12*3600)
+        hour   := time.Now().In(local).Hour()
+        cron   := New()
+        count1 := gtype.NewInt()
+        count2 := gtype.NewInt()
+        entry1, err := cron.AddEntry("* * " + strconv.Itoa(hour) + " * * *", func() { count1.Add(1) }, WithLocation(local))
+        gtest.Assert(err, nil)
+        entry2, err := cron.AddEntry("* * " + strconv.Itoa(hour) + " * * *", func() { count2.Add(1) }, WithLocation(other), WithName("other"))
+        gtest.Assert(err, nil)
+        gtest.Assert(entry1.Location() == local, true)
+        gtest.Assert(entry2.Location() == other, true)
+        gtest.Assert(cron.Search("other") == entry2, true)
+        time.Sleep(1500*time.Millisecond)
+        cron.Close()
+        gtest.AssertGT(count1.Val(), 0)
+        gtest.Assert(count2.Val(), 0)
+    })
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+
+package gcron_test
+
+import (
+    "errors"
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/os/gcron"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestCron_ErrorHandler(t *testing.T) {
+    gtest.Case(t, func() {
+        cron := gcron.New()
+        errs := garray.New()
+        cron.SetErrorHandler(func(entry *gcron.Entry, err *gcron.JobError) {
+            errs.Append(entry.Name + ":" + err.Error())
+        })
+        entry1, err := cron.Add("* * * * * *", func() {
+            panic("job panic")
+        }, "panic")
+        gtest.Assert(err, nil)
+        entry2, err := cron.Add("* * * * * *", gcron.ErrorJob(func() error {
+            return errors.New("job error")
+        }), "error")
+        gtest.Assert(err, nil)
+        entry2.SetMaxFailures(2)
+        time.Sleep(2500*time.Millisecond)
+        cron.Close()
+        gtest.Assert(entry1.Failures(), 2)
+        gtest.Assert(entry2.Failures(), 2)
+        gtest.Assert(entry2.Status(), gcron.STATUS_STOPPED)
+        gtest.Assert(errs.Len(), 4)
+        gtest.Assert(errs.Contains("panic:panic: job panic"), true)
+        gtest.Assert(errs.Contains("error:job error"), true)
+    })
+}
@@ -9,12 +9,42 @@
 // 环境变量管理
 package genv
 
-import "os"
+import (
+    "errors"
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
 
 func All() []string {
     return os.Environ()
 }
 
+// 获取所有的环境变量，返回键值对map
+func Map() map[string]string {
+    m := make(map[string]string)
+    for _, item := range os.Environ() {
+        if i := strings.Index(item, "="); i > 0 {
+            m[item[ : i]] = item[i + 1 : ]
+        }
+    }
+    return m
+}
+
+// 获取指定前缀的所有环境变量，返回的键名已去掉前缀，
+// 例如: MapWithPrefix("GF_")返回的GF_DB_HOST键名为DB_HOST
+func MapWithPrefix(prefix string) map[string]string {
+    m := make(map[string]string)
+    for k, v := range Map() {
+        if strings.HasPrefix(k, prefix) && len(k) > len(prefix) {
+            m[k[len(prefix) : ]] = v
+        }
+    }
+    return m
+}
+
 // 获取环境变量，并可以指定当环境变量不存在时的默认值
 func Get(k string, def...string) string {
     v, ok := os.LookupEnv(k)
@@ -24,10 +54,79 @@ func Get(k string, def...string) string {
     return v
 }
 
+// 获取环境变量，环境变量不存在时产生panic，用于获取程序运行必需的配置
+func MustGet(k string) string {
+    v, ok := os.LookupEnv(k)
+    if !ok {
+        panic(errors.New(fmt.Sprintf(`environment variable "%s" not found`, k)))
+    }
+    return v
+}
+
+// 判断环境变量是否存在
+func Contains(k string) bool {
+    _, ok := os.LookupEnv(k)
+    return ok
+}
+
+// 获取整型环境变量，环境变量不存在或者不是合法的整数时返回默认值(默认为0)
+func GetInt(k string, def...int) int {
+    if v, ok := lookup(k); ok {
+        if i, err := strconv.Atoi(v); err == nil {
+            return i
+        }
+    }
+    if len(def) > 0 {
+        return def[0]
+    }
+    return 0
+}
+
+// 获取布尔型环境变量，支持1/t/true/y/yes/on及0/f/false/n/no/off(不区分大小写)，
+// 环境变量不存在或者无法识别时返回默认值(默认为false)
+func GetBool(k string, def...bool) bool {
+    if v, ok := lookup(k); ok {
+        switch strings.ToLower(v) {
+            case "1", "t", "true",  "y", "yes", "on":
+                return true
+            case "0", "f", "false", "n", "no",  "off":
+                return false
+        }
+    }
+    if len(def) > 0 {
+        return def[0]
+    }
+    return false
+}
+
+// 获取时间长度环境变量，格式同time.ParseDuration(例如: 300ms, 1h30m)，纯数字时单位为秒，
+// 环境变量不存在或者格式错误时返回默认值(默认为0)
+func GetDuration(k string, def...time.Duration) time.Duration {
+    if v, ok := lookup(k); ok {
+        if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+            return time.Duration(i)*time.Second
+        }
+        if d, err := time.ParseDuration(v); err == nil {
+            return d
+        }
+    }
+    if len(def) > 0 {
+        return def[0]
+    }
+    return 0
+}
+
 func Set(k, v string) error {
     return os.Setenv(k, v)
 }
 
 func Remove(k string) error {
     return os.Unsetenv(k)
-}
\ No newline at end of file
+}
+
+// 获取去掉首尾空白的环境变量值，值为空时当作不存在
+func lookup(k string) (string, bool) {
+    v, ok := os.LookupEnv(k)
+    v      = strings.TrimSpace(v)
+    return v, ok && v != ""
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genv
+
+import (
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "strings"
+)
+
+const (
+    gDEFAULT_DOTENV_FILE = ".env" // 默认加载的.env文件
+)
+
+// 加载.env文件到环境变量，files为空时加载当前工作目录下的.env文件。
+// 已存在的环境变量不会被覆盖(系统环境变量优先)，多个文件中存在相同键名时以先加载的文件为准。
+func Load(files...string) error {
+    return loadFiles(false, files)
+}
+
+// 加载.env文件到环境变量，files为空时加载当前工作目录下的.env文件。
+// 已存在的环境变量将会被覆盖，多个文件中存在相同键名时以后加载的文件为准。
+func Overload(files...string) error {
+    return loadFiles(true, files)
+}
+
+// 读取.env文件内容，返回键值对map，不修改环境变量
+func Read(files...string) (map[string]string, error) {
+    if len(files) == 0 {
+        files = []string{gDEFAULT_DOTENV_FILE}
+    }
+    m := make(map[string]string)
+    for _, file := range files {
+        content, err := ioutil.ReadFile(file)
+        if err != nil {
+            return nil, err
+        }
+        data, err := Parse(string(content))
+        if err != nil {
+            return nil, errors.New(fmt.Sprintf(`parse env file "%s" failed: %s`, file, err.Error()))
+        }
+        for k, v := range data {
+            m[k] = v
+        }
+    }
+    return m, nil
+}
+
+// 解析.env格式的内容，格式如下:
+// # 注释
+// KEY=value                 # 行尾注释
+// export KEY=value
+// KEY='原样输出的内容'
+// KEY="支持\n转义及${OTHER}变量引用的内容"
+// 未使用引号及使用双引号的值支持$VAR/${VAR}变量引用，优先使用已解析的键值，其次使用环境变量。
+func Parse(content string) (map[string]string, error) {
+    m     := make(map[string]string)
+    lines := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+    for i := 0; i < len(lines); i++ {
+        line := strings.TrimSpace(lines[i])
+        if line == "" || line[0] == '#' {
+            continue
+        }
+        line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+        pos := strings.Index(line, "=")
+        if pos <= 0 {
+            return nil, errors.New(fmt.Sprintf(`invalid line %d: %s`, i + 1, line))
+        }
+        key   := strings.TrimSpace(line[ : pos])
+        value := strings.TrimSpace(line[pos + 1 : ])
+        if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+            // 引号内容支持多行
+            quote := value[0]
+            end   := closingQuote(value, quote)
+            for end < 0 && i + 1 < len(lines) {
+                i++
+                value += "\n" + lines[i]
+                end    = closingQuote(value, quote)
+            }
+            if end < 0 {
+                return nil, errors.New(fmt.Sprintf(`unterminated quoted value for key "%s"`, key))
+            }
+            value = value[1 : end]
+            if quote == '"' {
+                value = expandValue(unescapeValue(value), m)
+            }
+        } else {
+            if pos := strings.Index(value, " #"); pos >= 0 {
+                value = strings.TrimSpace(value[ : pos])
+            }
+            value = expandValue(value, m)
+        }
+        m[key] = value
+    }
+    return m, nil
+}
+
+// 加载.env文件，override表示是否覆盖已存在的环境变量
+func loadFiles(override bool, files []string) error {
+    if len(files) == 0 {
+        files = []string{gDEFAULT_DOTENV_FILE}
+    }
+    for _, file := range files {
+        data, err := Read(file)
+        if err != nil {
+            return err
+        }
+        for k, v := range data {
+            if _, ok := os.LookupEnv(k); ok && !override {
+                continue
+            }
+            if err := os.Setenv(k, v); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// 查找结束引号的位置(跳过转义的引号)，不存在时返回-1
+func closingQuote(value string, quote byte) int {
+    for i := 1; i < len(value); i++ {
+        switch value[i] {
+            case '\\':
+                if quote == '"' {
+                    i++
+                }
+            case quote:
+                return i
+        }
+    }
+    return -1
+}
+
+// 处理双引号内容中的转义字符
+func unescapeValue(value string) string {
+    return strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`, `\$`, "\x00").Replace(value)
+}
+
+// 替换内容中的$VAR/${VAR}变量引用，转义的\$保持原样
+func expandValue(value string, m map[string]string) string {
+    value = os.Expand(value, func(name string) string {
+        if v, ok := m[name]; ok {
+            return v
+        }
+        return os.Getenv(name)
+    })
+    return strings.Replace(value, "\x00", "$", -1)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package genv_test
+
+import (
+    "github.com/gogf/gf/g/os/genv"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestGetTyped(t *testing.T) {
+    gtest.Case(t, func() {
+        genv.Set("GENV_TEST_INT",      "100")
+        genv.Set("GENV_TEST_BOOL",     "yes")
+        genv.Set("GENV_TEST_DURATION", "1m30s")
+        genv.Set("GENV_TEST_SECONDS",  "5")
+        genv.Set("GENV_TEST_INVALID",  "abc")
+        defer func() {
+            for _, k := range []string{"GENV_TEST_INT", "GENV_TEST_BOOL", "GENV_TEST_DURATION", "GENV_TEST_SECONDS", "GENV_TEST_INVALID"} {
+                genv.Remove(k)
+            }
+        }()
+        gtest.Assert(genv.GetInt("GENV_TEST_INT"),             100)
+        gtest.Assert(genv.GetInt("GENV_TEST_INVALID", 10),     10)
+        gtest.Assert(genv.GetInt("GENV_TEST_NONE", 20),        20)
+        gtest.Assert(genv.GetBool("GENV_TEST_BOOL"),           true)
+        gtest.Assert(genv.GetBool("GENV_TEST_INVALID", true),  true)
+        gtest.Assert(genv.GetBool("GENV_TEST_NONE"),           false)
+        gtest.Assert(genv.GetDuration("GENV_TEST_DURATION") == 90*time.Second, true)
+        gtest.Assert(genv.GetDuration("GENV_TEST_SECONDS")  == 5*time.Second,  true)
+        gtest.Assert(genv.GetDuration("GENV_TEST_NONE", time.Minute) == time.Minute, true)
+        gtest.Assert(genv.MustGet("GENV_TEST_INT"), "100")
+        gtest.Assert(genv.Contains("GENV_TEST_NONE"), false)
+
+        m := genv.MapWithPrefix("GENV_TEST_")
+        gtest.Assert(m["INT"],  "100")
+        gtest.Assert(m["BOOL"], "yes")
+
+        defer func() {
+            gtest.AssertNE(recover(), nil)
+        }()
+        genv.MustGet("GENV_TEST_NONE")
+    })
+}
+
+func TestParse(t *testing.T) {
+    gtest.Case(t, func() {
+        m, err := genv.Parse(`
+# comment
+export NAME=john   # inline comment
+GREETING="hello ${NAME}\nworld"
+RAW='hello ${NAME}'
+MULTI="line1
+line2"
+EMPTY=
+`)
+        gtest.Assert(err, nil)
+        gtest.Assert(m["NAME"],     "john")
+        gtest.Assert(m["GREETING"], "hello john\nworld")
+        gtest.Assert(m["RAW"],      "hello ${NAME}")
+        gtest.Assert(m["MULTI"],    "line1\nline2")
+        gtest.Assert(m["EMPTY"],    "")
+
+        _, err = genv.Parse("INVALID")
+        gtest.AssertNE(err, nil)
+        _, err = genv.Parse(`KEY="unterminated`)
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestLoad(t *testing.T) {
+    gtest.Case(t, func() {
+        path1 := gfile.TempDir() + gfile.Separator + "genv_test_1.env"
+        path2 := gfile.TempDir() + gfile.Separator + "genv_test_2.env"
+        gfile.PutContents(path1, "GENV_LOAD_A=1\nGENV_LOAD_B=1")
+        gfile.PutContents(path2, "GENV_LOAD_B=2\nGENV_LOAD_C=2")
+        defer gfile.Remove(path1)
+        defer gfile.Remove(path2)
+        defer func() {
+            for _, k := range []string{"GENV_LOAD_A", "GENV_LOAD_B", "GENV_LOAD_C"} {
+                genv.Remove(k)
+            }
+        }()
+        genv.Set("GENV_LOAD_A", "0")
+        gtest.Assert(genv.Load(path1, path2), nil)
+        gtest.Assert(genv.Get("GENV_LOAD_A"), "0")
+        gtest.Assert(genv.Get("GENV_LOAD_B"), "1")
+        gtest.Assert(genv.Get("GENV_LOAD_C"), "2")
+
+        gtest.Assert(genv.Overload(path1, path2), nil)
+        gtest.Assert(genv.Get("GENV_LOAD_A"), "1")
+        gtest.Assert(genv.Get("GENV_LOAD_B"), "2")
+
+        gtest.AssertNE(genv.Load(path1 + ".none"), nil)
+    })
+}
@@ -162,11 +162,13 @@ func Rename(src string, dst string) error {
     return Move(src, dst)
 }
 
-// Copy file from src to dst.
+// Copy file from src to dst, it copies directory recursively if <src> is a directory.
 //
-// 文件复制.
-// @TODO 支持目录复制.
+// 文件复制，src为目录时递归复制目录(参考CopyDir).
 func Copy(src string, dst string) error {
+    if IsDir(src) {
+        return CopyDir(src, dst)
+    }
     srcFile, err := Open(src)
     if err != nil {
         return err
@@ -206,16 +208,19 @@ func DirNames(path string) ([]string, error) {
 
 // Glob returns the names of all files matching pattern or nil
 // if there is no matching file. The syntax of patterns is the same
-// as in Match. The pattern may describe hierarchical names such as
+// as in Match, and additionally supports "**" matching any number of directories
+// and brace expansion, eg: /var/www/**/*.{html,tpl}.
+// The pattern may describe hierarchical names such as
 // /usr/*/bin/ed (assuming the Separator is '/').
 //
 // Glob ignores file system errors such as I/O errors reading directories.
 // The only possible returned error is ErrBadPattern, when pattern
 // is malformed.
 //
-// 文件名正则匹配查找，第二个可选参数指定返回的列表是否仅为文件名(非绝对路径)，默认返回绝对路径
+// 文件名模式匹配查找，除filepath.Match语法外，支持"**"匹配任意层级目录以及"{a,b}"形式的候选项展开，
+// 第二个可选参数指定返回的列表是否仅为文件名(非绝对路径)，默认返回绝对路径
 func Glob(pattern string, onlyNames...bool) ([]string, error) {
-    if list, err := filepath.Glob(pattern); err == nil {
+    if list, err := doGlob(pattern); err == nil {
         if len(onlyNames) > 0 && onlyNames[0] && len(list) > 0 {
             array := make([]string, len(list))
             for k, v := range list {
@@ -15,6 +15,8 @@ import (
 const (
     // 方法中涉及到读取的时候的缓冲大小
     gREAD_BUFFER      = 1024
+    // 原子写入新文件时的默认权限
+    gATOMIC_PERM      = 0644
     // 方法中涉及到文件指针池的默认缓存时间(毫秒)
     //gFILE_POOL_EXPIRE = 60000
 )
@@ -81,6 +83,60 @@ func PutBinContentsAppend(path string, content []byte) error {
     return putContents(path, content, os.O_WRONLY|os.O_CREATE|os.O_APPEND, gDEFAULT_PERM)
 }
 
+// (文本)原子写入文件内容
+func PutContentsAtomic(path string, content string) error {
+    return PutBinContentsAtomic(path, []byte(content))
+}
+
+// (二进制)原子写入文件内容，先写入同目录下的临时文件并fsync，再重命名为目标文件，
+// 保证其他进程读取到的文件内容要么是旧内容要么是完整的新内容，写入失败或者进程崩溃不会损坏原有文件。
+// 目标文件已存在时保留其权限，否则使用默认权限(0644)。
+func PutBinContentsAtomic(path string, content []byte) error {
+    dir := Dir(path)
+    if !Exists(dir) {
+        if err := Mkdir(dir); err != nil {
+            return err
+        }
+    }
+    perm := os.FileMode(gATOMIC_PERM)
+    if info, err := os.Stat(path); err == nil {
+        perm = info.Mode().Perm()
+    }
+    f, err := ioutil.TempFile(dir, "." + Basename(path) + ".tmp")
+    if err != nil {
+        return err
+    }
+    tmp := f.Name()
+    if err = writeAndSync(f, content, perm); err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    if err = os.Rename(tmp, path); err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    // 同步目录项，保证重命名操作落盘(部分平台不支持目录同步，忽略错误)
+    if d, err := os.Open(dir); err == nil {
+        d.Sync()
+        d.Close()
+    }
+    return nil
+}
+
+// 写入内容并fsync，最后关闭文件
+func writeAndSync(f *os.File, content []byte, perm os.FileMode) error {
+    defer f.Close()
+    if n, err := f.Write(content); err != nil {
+        return err
+    } else if n < len(content) {
+        return io.ErrShortWrite
+    }
+    if err := f.Chmod(perm); err != nil {
+        return err
+    }
+    return f.Sync()
+}
+
 // 获得文件内容下一个指定字节的位置
 func GetNextCharOffset(reader io.ReaderAt, char byte, start int64) int64 {
     buffer := make([]byte, gREAD_BUFFER)
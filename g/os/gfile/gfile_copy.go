@@ -0,0 +1,332 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfile
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+const (
+    SYMLINK_KEEP   = 0 // 符号链接处理方式：复制符号链接本身(默认)
+    SYMLINK_FOLLOW = 1 // 符号链接处理方式：复制符号链接指向的文件/目录
+    SYMLINK_SKIP   = 2 // 符号链接处理方式：忽略符号链接
+)
+
+// CopyOption is the option for CopyDir/MoveDir.
+//
+// 目录复制/移动选项
+type CopyOption struct {
+    // 包含的文件名称模式(filepath.Match语法)，匹配相对路径(使用'/'分隔)或者文件名，为空表示包含所有文件
+    Include  []string
+    // 排除的文件/目录名称模式，匹配相对路径或者文件名，排除的目录将不会被遍历
+    Exclude  []string
+    // 符号链接处理方式，SYMLINK_KEEP/SYMLINK_FOLLOW/SYMLINK_SKIP
+    Symlink  int
+    // 是否保留源文件/目录的权限
+    KeepPerm bool
+    // 进度回调方法，每个文件复制/移动完成后调用
+    Progress func(progress CopyProgress)
+}
+
+// CopyProgress is the progress of CopyDir/MoveDir, which is passed to CopyOption.Progress.
+//
+// 目录复制/移动进度
+type CopyProgress struct {
+    Path       string // 当前完成的源文件路径
+    Files      int    // 已完成的文件数量
+    TotalFiles int    // 需要处理的文件总数
+    Bytes      int64  // 已完成的文件字节数
+    TotalBytes int64  // 需要处理的文件总字节数
+}
+
+// 需要复制/移动的文件项
+type copyItem struct {
+    src  string      // 源路径
+    dst  string      // 目标路径
+    info os.FileInfo // 源文件信息(SYMLINK_FOLLOW时为链接指向的文件信息)
+    link string      // 符号链接指向的路径(SYMLINK_KEEP时有效)
+    // 是否为跟随的符号链接或者位于跟随的符号链接目录中(SYMLINK_FOLLOW时有效)，移动时不会修改链接指向的文件
+    linked bool
+    // 是否为源目录中跟随的符号链接(不包括链接目录中的符号链接)，移动时删除符号链接本身
+    unlink bool
+}
+
+// CopyDir copies directory <src> to <dst> recursively with the optional <option>,
+// which supports include/exclude filters, symlink handling, permission preservation and progress callback.
+//
+// 目录递归复制，可选参数option支持文件包含/排除过滤、符号链接处理方式、权限保留以及进度回调。
+func CopyDir(src string, dst string, option...CopyOption) error {
+    opt := CopyOption{}
+    if len(option) > 0 {
+        opt = option[0]
+    }
+    items, err := scanCopyItems(src, dst, opt)
+    if err != nil {
+        return err
+    }
+    return doCopyItems(items, opt, false)
+}
+
+// MoveDir moves directory <src> to <dst> recursively with the optional <option>.
+// It renames the whole directory if no filter is specified, or else it moves only the matched files,
+// and removes the source directories which become empty.
+//
+// 目录递归移动，未设置过滤条件时优先直接重命名目录，否则只移动匹配的文件，并删除移动后为空的源目录。
+// 跨设备无法重命名时使用复制后删除的方式。
+func MoveDir(src string, dst string, option...CopyOption) error {
+    opt := CopyOption{}
+    if len(option) > 0 {
+        opt = option[0]
+    }
+    if len(opt.Include) == 0 && len(opt.Exclude) == 0 && opt.Symlink == SYMLINK_KEEP && opt.Progress == nil && !Exists(dst) {
+        if err := Mkdir(Dir(dst)); err != nil {
+            return err
+        }
+        if err := os.Rename(src, dst); err == nil {
+            return nil
+        }
+    }
+    items, err := scanCopyItems(src, dst, opt)
+    if err != nil {
+        return err
+    }
+    if err := doCopyItems(items, opt, true); err != nil {
+        return err
+    }
+    removeEmptyDirs(src)
+    return nil
+}
+
+// 遍历源目录，生成需要复制/移动的文件项列表(目录项在其子项之前)
+func scanCopyItems(src string, dst string, opt CopyOption) ([]copyItem, error) {
+    info, err := os.Stat(src)
+    if err != nil {
+        return nil, err
+    }
+    if !info.IsDir() {
+        return nil, errors.New(fmt.Sprintf(`"%s" is not a directory`, src))
+    }
+    if dstAbs, err := filepath.Abs(dst); err == nil {
+        if srcAbs, err := filepath.Abs(src); err == nil && strings.HasPrefix(dstAbs + Separator, srcAbs + Separator) {
+            return nil, errors.New(fmt.Sprintf(`cannot copy directory "%s" into itself "%s"`, src, dst))
+        }
+    }
+    items   := []copyItem{{src : src, dst : dst, info : info}}
+    visited := map[string]bool{}
+    if realPath, err := filepath.EvalSymlinks(src); err == nil {
+        visited[realPath] = true
+    }
+    if err := doScanCopyItems(src, dst, "", false, opt, visited, &items); err != nil {
+        return nil, err
+    }
+    return items, nil
+}
+
+// 递归遍历目录，rel为相对于源目录的路径(使用'/'分隔)，linked表示src是否位于跟随的符号链接目录中，
+// visited用于防止跟随符号链接时的循环遍历
+func doScanCopyItems(src string, dst string, rel string, linked bool, opt CopyOption, visited map[string]bool, items *[]copyItem) error {
+    names, err := DirNames(src)
+    if err != nil {
+        return err
+    }
+    for _, name := range names {
+        path    := src + Separator + name
+        target  := dst + Separator + name
+        relPath := name
+        if rel != "" {
+            relPath = rel + "/" + name
+        }
+        if matchCopyPatterns(opt.Exclude, relPath, name) {
+            continue
+        }
+        info, err := os.Lstat(path)
+        if err != nil {
+            return err
+        }
+        link     := ""
+        followed := linked
+        unlink   := false
+        if info.Mode() & os.ModeSymlink != 0 {
+            switch opt.Symlink {
+                case SYMLINK_SKIP:
+                    continue
+                case SYMLINK_FOLLOW:
+                    if info, err = os.Stat(path); err != nil {
+                        return err
+                    }
+                    followed = true
+                    unlink   = !linked
+                default:
+                    if link, err = os.Readlink(path); err != nil {
+                        return err
+                    }
+            }
+        }
+        if info.IsDir() && link == "" {
+            if realPath, err := filepath.EvalSymlinks(path); err == nil {
+                if visited[realPath] {
+                    continue
+                }
+                visited[realPath] = true
+            }
+            *items = append(*items, copyItem{src : path, dst : target, info : info, linked : followed, unlink : unlink})
+            if err := doScanCopyItems(path, target, relPath, followed, opt, visited, items); err != nil {
+                return err
+            }
+            continue
+        }
+        if len(opt.Include) > 0 && !matchCopyPatterns(opt.Include, relPath, name) {
+            continue
+        }
+        *items = append(*items, copyItem{src : path, dst : target, info : info, link : link, linked : followed, unlink : unlink})
+    }
+    return nil
+}
+
+// 判断相对路径或者文件名是否匹配任一模式
+func matchCopyPatterns(patterns []string, rel string, name string) bool {
+    for _, p := range patterns {
+        if ok, _ := filepath.Match(p, rel); ok {
+            return true
+        }
+        if ok, _ := filepath.Match(p, name); ok {
+            return true
+        }
+    }
+    return false
+}
+
+// 执行文件项的复制/移动
+func doCopyItems(items []copyItem, opt CopyOption, move bool) error {
+    progress := CopyProgress{}
+    for _, item := range items {
+        if !item.info.IsDir() {
+            progress.TotalFiles++
+            if item.link == "" {
+                progress.TotalBytes += item.info.Size()
+            }
+        }
+    }
+    for _, item := range items {
+        if item.info.IsDir() {
+            if err := Mkdir(item.dst); err != nil {
+                return err
+            }
+            if opt.KeepPerm {
+                if err := os.Chmod(item.dst, item.info.Mode().Perm()); err != nil {
+                    return err
+                }
+            }
+            continue
+        }
+        if err := copyItemFile(item, opt, move); err != nil {
+            return err
+        }
+        progress.Path   = item.src
+        progress.Files += 1
+        if item.link == "" {
+            progress.Bytes += item.info.Size()
+        }
+        if opt.Progress != nil {
+            opt.Progress(progress)
+        }
+    }
+    // 移动时删除跟随的符号链接目录本身(链接指向的目录保持不变)
+    if move {
+        for _, item := range items {
+            if item.unlink && item.info.IsDir() {
+                if err := os.Remove(item.src); err != nil {
+                    return err
+                }
+            }
+        }
+    }
+    return nil
+}
+
+// 复制/移动单个文件或者符号链接
+func copyItemFile(item copyItem, opt CopyOption, move bool) error {
+    if item.link != "" {
+        if _, err := os.Lstat(item.dst); err == nil {
+            if err := os.Remove(item.dst); err != nil {
+                return err
+            }
+        }
+        if err := os.Symlink(item.link, item.dst); err != nil {
+            return err
+        }
+        if move {
+            return os.Remove(item.src)
+        }
+        return nil
+    }
+    // 跟随符号链接时不能重命名或者删除链接指向的文件，只删除符号链接本身
+    if move && !item.linked {
+        if err := os.Rename(item.src, item.dst); err == nil {
+            return nil
+        }
+    }
+    if err := copyFileWithPerm(item.src, item.dst, item.info.Mode().Perm(), opt.KeepPerm); err != nil {
+        return err
+    }
+    if move && (!item.linked || item.unlink) {
+        return os.Remove(item.src)
+    }
+    return nil
+}
+
+// 复制文件内容，keepPerm为true时保留源文件权限
+func copyFileWithPerm(src string, dst string, perm os.FileMode, keepPerm bool) error {
+    srcFile, err := os.Open(src)
+    if err != nil {
+        return err
+    }
+    defer srcFile.Close()
+    flagPerm := os.FileMode(gDEFAULT_PERM)
+    if keepPerm {
+        flagPerm = perm
+    }
+    dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, flagPerm)
+    if err != nil {
+        return err
+    }
+    defer dstFile.Close()
+    if _, err = io.Copy(dstFile, srcFile); err != nil {
+        return err
+    }
+    if keepPerm {
+        if err = dstFile.Chmod(perm); err != nil {
+            return err
+        }
+    }
+    return dstFile.Sync()
+}
+
+// 递归删除空目录(包含path本身)，返回path是否已被删除
+func removeEmptyDirs(path string) bool {
+    names, err := DirNames(path)
+    if err != nil {
+        return false
+    }
+    empty := true
+    for _, name := range names {
+        sub := path + Separator + name
+        if info, err := os.Lstat(sub); err == nil && info.IsDir() && removeEmptyDirs(sub) {
+            continue
+        }
+        empty = false
+    }
+    if empty {
+        return os.Remove(path) == nil
+    }
+    return false
+}
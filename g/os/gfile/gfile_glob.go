@@ -0,0 +1,258 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfile
+
+import (
+    "bufio"
+    "bytes"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "github.com/gogf/gf/g/text/gregex"
+)
+
+// SearchResult is the matched line of SearchContent.
+//
+// 文件内容检索结果项
+type SearchResult struct {
+    Path    string // 文件绝对路径
+    Line    int    // 行号(从1开始)
+    Content string // 行内容(不包含换行符)
+}
+
+// 文件内容检索时单行的最大读取长度，超过的部分不参与匹配
+const gSEARCH_MAX_LINE = 1024*1024
+
+// 文件名模式匹配查找(支持"**"及"{a,b}"展开)，返回排序后的去重结果
+func doGlob(pattern string) ([]string, error) {
+    if !strings.Contains(pattern, "**") && !strings.Contains(pattern, "{") {
+        return filepath.Glob(pattern)
+    }
+    patterns, err := expandBraces(pattern)
+    if err != nil {
+        return nil, err
+    }
+    result := ([]string)(nil)
+    exists := make(map[string]struct{})
+    for _, p := range patterns {
+        list := ([]string)(nil)
+        if strings.Contains(p, "**") {
+            list, err = globStar(p)
+        } else {
+            list, err = filepath.Glob(p)
+        }
+        if err != nil {
+            return nil, err
+        }
+        for _, v := range list {
+            if _, ok := exists[v]; !ok {
+                exists[v] = struct{}{}
+                result    = append(result, v)
+            }
+        }
+    }
+    sort.Strings(result)
+    return result, nil
+}
+
+// 展开模式中的"{a,b}"候选项，支持嵌套，例如: *.{js,c{ss,off}} 展开为 *.js, *.css, *.coff
+func expandBraces(pattern string) ([]string, error) {
+    start := strings.IndexByte(pattern, '{')
+    if start == -1 {
+        if strings.IndexByte(pattern, '}') != -1 {
+            return nil, filepath.ErrBadPattern
+        }
+        return []string{pattern}, nil
+    }
+    depth := 0
+    items := make([]string, 0)
+    last  := start + 1
+    for i := start; i < len(pattern); i++ {
+        switch pattern[i] {
+            case '{':
+                depth++
+            case ',':
+                if depth == 1 {
+                    items = append(items, pattern[last : i])
+                    last  = i + 1
+                }
+            case '}':
+                depth--
+                if depth == 0 {
+                    items = append(items, pattern[last : i])
+                    result := make([]string, 0)
+                    for _, item := range items {
+                        list, err := expandBraces(pattern[ : start] + item + pattern[i + 1 : ])
+                        if err != nil {
+                            return nil, err
+                        }
+                        result = append(result, list...)
+                    }
+                    return result, nil
+                }
+        }
+    }
+    return nil, filepath.ErrBadPattern
+}
+
+// 使用包含"**"的模式递归查找文件，从模式中不包含通配符的最长目录开始遍历
+func globStar(pattern string) ([]string, error) {
+    pattern  = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+    expr, err := globToRegex(pattern)
+    if err != nil {
+        return nil, err
+    }
+    regex, err := regexp.Compile(expr)
+    if err != nil {
+        return nil, filepath.ErrBadPattern
+    }
+    // 遍历的根目录
+    segments := strings.Split(pattern, "/")
+    root     := ""
+    for i, s := range segments {
+        if strings.ContainsAny(s, "*?[") {
+            root = strings.Join(segments[ : i], "/")
+            if root == "" && i > 0 {
+                root = "/"
+            }
+            break
+        }
+    }
+    if root == "" {
+        root = "."
+    }
+    list := ([]string)(nil)
+    filepath.Walk(filepath.FromSlash(root), func(path string, info os.FileInfo, err error) error {
+        if err == nil && regex.MatchString(filepath.ToSlash(path)) {
+            list = append(list, path)
+        }
+        return nil
+    })
+    return list, nil
+}
+
+// 将glob模式转换为正则表达式:
+// "**/"匹配零个或者多个目录，"/**"结尾匹配所有子文件/目录，"*"匹配目录名称以外的任意字符，"?"匹配单个字符
+func globToRegex(pattern string) (string, error) {
+    buffer := bytes.NewBufferString("^")
+    for i := 0; i < len(pattern); i++ {
+        c := pattern[i]
+        switch c {
+            case '*':
+                if i + 1 < len(pattern) && pattern[i + 1] == '*' {
+                    if i + 2 < len(pattern) && pattern[i + 2] == '/' {
+                        buffer.WriteString("(?:.*/)?")
+                        i += 2
+                    } else {
+                        buffer.WriteString(".*")
+                        i += 1
+                    }
+                } else {
+                    buffer.WriteString("[^/]*")
+                }
+            case '?':
+                buffer.WriteString("[^/]")
+            case '[':
+                end := strings.IndexByte(pattern[i : ], ']')
+                if end == -1 {
+                    return "", filepath.ErrBadPattern
+                }
+                class := pattern[i + 1 : i + end]
+                if strings.HasPrefix(class, "!") {
+                    class = "^" + class[1 : ]
+                }
+                buffer.WriteString("[" + class + "]")
+                i += end
+            default:
+                buffer.WriteString(regexp.QuoteMeta(string(c)))
+        }
+    }
+    buffer.WriteString("$")
+    return buffer.String(), nil
+}
+
+// SearchContent searches the files matching <pattern> under directory <path> recursively,
+// and returns the lines matching the regular expression <regex> with file path and line number.
+// The parameter <pattern> is the same as ScanDir, which supports multiple patterns separated by ','.
+//
+// 递归检索目录下文件名称匹配pattern(同ScanDir，使用','分隔多个模式)的文件，
+// 返回文件内容中匹配正则表达式regex的行(包含文件路径、行号及行内容)，按照文件路径及行号排序。
+func SearchContent(path string, pattern string, regex string) ([]SearchResult, error) {
+    if err := gregex.Validate(regex); err != nil {
+        return nil, err
+    }
+    files, err := ScanDir(path, pattern, true)
+    if err != nil {
+        return nil, err
+    }
+    result := ([]SearchResult)(nil)
+    for _, file := range files {
+        if IsDir(file) {
+            continue
+        }
+        list, err := searchFileContent(file, regex)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, list...)
+    }
+    return result, nil
+}
+
+// 检索单个文件中匹配正则表达式的行
+func searchFileContent(path string, regex string) ([]SearchResult, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    result := ([]SearchResult)(nil)
+    reader := bufio.NewReader(f)
+    number := 0
+    for {
+        line, err := readLine(reader, gSEARCH_MAX_LINE)
+        if len(line) > 0 || err == nil {
+            number++
+            if gregex.IsMatch(regex, line) {
+                result = append(result, SearchResult {
+                    Path    : path,
+                    Line    : number,
+                    Content : string(line),
+                })
+            }
+        }
+        if err == io.EOF {
+            break
+        } else if err != nil {
+            return nil, err
+        }
+    }
+    return result, nil
+}
+
+// 读取一行内容(不包含换行符)，超过max长度的部分将被丢弃，文件结束时返回io.EOF
+func readLine(reader *bufio.Reader, max int) ([]byte, error) {
+    line := ([]byte)(nil)
+    for {
+        part, isPrefix, err := reader.ReadLine()
+        if err != nil {
+            return line, err
+        }
+        if len(line) < max {
+            line = append(line, part...)
+            if len(line) > max {
+                line = line[ : max]
+            }
+        }
+        if !isPrefix {
+            return line, nil
+        }
+    }
+}
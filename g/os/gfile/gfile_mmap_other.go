@@ -0,0 +1,58 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package gfile
+
+import (
+    "io"
+    "io/ioutil"
+    "os"
+)
+
+// OpenMmap opens file <path> for reading. The platform does not support mmap,
+// so the file is read using file pointer. The returned reader should be closed after use.
+//
+// 打开大文件读取对象，当前平台不支持mmap，使用文件指针读取，使用完毕后需要调用Close关闭
+func OpenMmap(path string) (*MmapReader, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    info, err := f.Stat()
+    if err != nil {
+        f.Close()
+        return nil, err
+    }
+    return &MmapReader{file : f, size : info.Size()}, nil
+}
+
+// ReadAt implements the io.ReaderAt interface.
+//
+// 读取指定偏移量的文件内容(io.ReaderAt接口实现)
+func (m *MmapReader) ReadAt(p []byte, off int64) (int, error) {
+    return m.file.ReadAt(p, off)
+}
+
+// Bytes returns the file content. The platform does not support mmap,
+// so the whole file content is read into memory.
+//
+// 返回文件内容，当前平台不支持mmap，会将文件内容全部读取到内存中
+func (m *MmapReader) Bytes() []byte {
+    data, err := ioutil.ReadAll(io.NewSectionReader(m.file, 0, m.size))
+    if err != nil {
+        return nil
+    }
+    return data
+}
+
+// Close closes the file.
+//
+// 关闭文件
+func (m *MmapReader) Close() error {
+    return m.file.Close()
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package gfile
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "syscall"
+)
+
+// 可以映射的最大文件大小(32位平台上为2GB)
+const gMMAP_MAX_SIZE = int64(^uint(0) >> 1)
+
+// OpenMmap opens file <path> for reading using mmap.
+// The returned reader should be closed after use.
+//
+// 使用mmap内存映射打开文件，使用完毕后需要调用Close关闭
+func OpenMmap(path string) (*MmapReader, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    info, err := f.Stat()
+    if err != nil {
+        return nil, err
+    }
+    m := &MmapReader{size : info.Size()}
+    if m.size > gMMAP_MAX_SIZE {
+        return nil, errors.New(fmt.Sprintf(`file "%s" is too large to mmap: %d bytes`, path, m.size))
+    }
+    // 空文件不进行映射，使用非nil的空数据以便与已关闭的状态区分
+    m.data = []byte{}
+    if m.size > 0 {
+        m.data, err = syscall.Mmap(int(f.Fd()), 0, int(m.size), syscall.PROT_READ, syscall.MAP_SHARED)
+        if err != nil {
+            return nil, err
+        }
+    }
+    return m, nil
+}
+
+// ReadAt implements the io.ReaderAt interface.
+//
+// 读取指定偏移量的文件内容(io.ReaderAt接口实现)，Close之后返回os.ErrClosed
+func (m *MmapReader) ReadAt(p []byte, off int64) (int, error) {
+    if m.data == nil {
+        return 0, os.ErrClosed
+    }
+    if off < 0 {
+        return 0, os.ErrInvalid
+    }
+    if off >= m.size {
+        return 0, io.EOF
+    }
+    n := copy(p, m.data[off : ])
+    if n < len(p) {
+        return n, io.EOF
+    }
+    return n, nil
+}
+
+// Bytes returns the mapped file content, which is invalid after Close.
+//
+// 返回内存映射的文件内容(不会产生拷贝)，Close之后不可再使用
+func (m *MmapReader) Bytes() []byte {
+    return m.data
+}
+
+// Close unmaps the file.
+//
+// 关闭内存映射
+func (m *MmapReader) Close() error {
+    if m.data == nil {
+        return nil
+    }
+    data  := m.data
+    m.data = nil
+    if len(data) == 0 {
+        return nil
+    }
+    return syscall.Munmap(data)
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfile
+
+import (
+    "bufio"
+    "io"
+    "os"
+    "strings"
+)
+
+const (
+    // 分块读取文件的默认块大小
+    gDEFAULT_CHUNK_SIZE = 1024*1024
+)
+
+// MmapReader is the reader for very large files, which maps the file into memory on supported platforms.
+//
+// 大文件读取对象，在支持的平台上使用mmap内存映射读取文件，不会将文件内容全部载入内存，
+// 不支持mmap的平台上使用文件指针读取。MmapReader实现了io.ReaderAt接口。
+type MmapReader struct {
+    data []byte   // 内存映射的文件内容(关闭之后以及不支持mmap的平台为nil)
+    file *os.File // 不支持mmap的平台使用的文件指针
+    size int64    // 文件大小
+}
+
+// ReadLines reads file <path> line by line, and calls <callback> with each line without line feed.
+// It stops reading if <callback> returns false.
+//
+// 按行读取文件内容，每读取一行(不包含换行符)调用一次callback，callback返回false时停止读取。
+// 与GetContents不同的是不会将文件内容全部载入内存，适用于大文件。
+func ReadLines(path string, callback func(line string) bool) error {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return readLines(f, callback)
+}
+
+// ReadChunks reads file <path> chunk by chunk with the size of <size>(1MB in default),
+// and calls <callback> with each chunk. It stops reading if <callback> returns false.
+// Note that the chunk buffer is reused between callbacks.
+//
+// 按块读取文件内容，块大小默认为1MB，每读取一块调用一次callback，callback返回false时停止读取。
+// 注意callback参数的缓冲区在每次调用之间会被复用，需要保存内容时请进行拷贝。
+func ReadChunks(path string, callback func(chunk []byte) bool, size...int) error {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    chunkSize := gDEFAULT_CHUNK_SIZE
+    if len(size) > 0 && size[0] > 0 {
+        chunkSize = size[0]
+    }
+    buffer := make([]byte, chunkSize)
+    for {
+        n, err := io.ReadFull(f, buffer)
+        if n > 0 && !callback(buffer[ : n]) {
+            return nil
+        }
+        if err == io.EOF || err == io.ErrUnexpectedEOF {
+            return nil
+        } else if err != nil {
+            return err
+        }
+    }
+}
+
+// 按行读取内容，callback返回false时停止读取
+func readLines(reader io.Reader, callback func(line string) bool) error {
+    buffer := bufio.NewReader(reader)
+    for {
+        line, err := buffer.ReadString('\n')
+        if len(line) > 0 || err == nil {
+            if !callback(strings.TrimRight(line, "\r\n")) {
+                return nil
+            }
+        }
+        if err == io.EOF {
+            return nil
+        } else if err != nil {
+            return err
+        }
+    }
+}
+
+// Len returns the size of the file.
+//
+// 文件大小
+func (m *MmapReader) Len() int64 {
+    return m.size
+}
+
+// ReadLines reads the file line by line, see ReadLines.
+//
+// 按行读取文件内容，callback返回false时停止读取
+func (m *MmapReader) ReadLines(callback func(line string) bool) error {
+    return readLines(io.NewSectionReader(m, 0, m.size), callback)
+}
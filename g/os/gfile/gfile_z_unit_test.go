@@ -0,0 +1,279 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gfile_test
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/test/gtest"
+    "io"
+    "os"
+    "testing"
+    "time"
+)
+
+// 创建测试目录，返回目录绝对路径
+func testDir(name string) string {
+    path := gfile.TempDir() + gfile.Separator + "gfile_test_" + name + "_" + time.Now().Format("150405.000000")
+    gfile.Mkdir(path)
+    return path
+}
+
+func TestCopyDir(t *testing.T) {
+    gtest.Case(t, func() {
+        src := testDir("copy_src")
+        dst := testDir("copy_dst") + gfile.Separator + "out"
+        defer gfile.Remove(src)
+        defer gfile.Remove(gfile.Dir(dst))
+        gfile.PutContents(src + "/a.txt", "aaa")
+        gfile.PutContents(src + "/b.log", "bb")
+        gfile.PutContents(src + "/sub/c.txt", "c")
+        gfile.PutContents(src + "/tmp/d.txt", "d")
+        os.Chmod(src + "/a.txt", 0600)
+        os.Symlink("a.txt", src + "/link.txt")
+
+        progress := make([]gfile.CopyProgress, 0)
+        err := gfile.CopyDir(src, dst, gfile.CopyOption {
+            Include  : []string{"*.txt"},
+            Exclude  : []string{"tmp"},
+            KeepPerm : true,
+            Progress : func(p gfile.CopyProgress) {
+                progress = append(progress, p)
+            },
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(gfile.GetContents(dst + "/a.txt"), "aaa")
+        gtest.Assert(gfile.GetContents(dst + "/sub/c.txt"), "c")
+        gtest.Assert(gfile.Exists(dst + "/b.log"), false)
+        gtest.Assert(gfile.Exists(dst + "/tmp"), false)
+        info, _ := os.Stat(dst + "/a.txt")
+        gtest.Assert(info.Mode().Perm(), os.FileMode(0600))
+        link, _ := os.Readlink(dst + "/link.txt")
+        gtest.Assert(link, "a.txt")
+
+        gtest.Assert(len(progress), 3)
+        last := progress[len(progress) - 1]
+        gtest.Assert(last.Files, 3)
+        gtest.Assert(last.TotalFiles, 3)
+        gtest.Assert(last.Bytes, 4)
+        gtest.Assert(last.TotalBytes, 4)
+
+        // 跟随符号链接
+        gfile.Remove(dst)
+        gtest.Assert(gfile.CopyDir(src, dst, gfile.CopyOption{Symlink : gfile.SYMLINK_FOLLOW}), nil)
+        info, _ = os.Lstat(dst + "/link.txt")
+        gtest.Assert(info.Mode() & os.ModeSymlink == 0, true)
+        gtest.Assert(gfile.GetContents(dst + "/link.txt"), "aaa")
+
+        // 不能复制到自身子目录
+        gtest.AssertNE(gfile.CopyDir(src, src + "/sub/copy"), nil)
+        gtest.AssertNE(gfile.CopyDir(src + "/a.txt", dst), nil)
+    })
+}
+
+func TestMoveDir(t *testing.T) {
+    gtest.Case(t, func() {
+        src := testDir("move_src")
+        dst := testDir("move_dst")
+        defer gfile.Remove(src)
+        defer gfile.Remove(dst)
+        gfile.PutContents(src + "/a.txt", "a")
+        gfile.PutContents(src + "/b.log", "b")
+        gfile.PutContents(src + "/sub/c.txt", "c")
+
+        gtest.Assert(gfile.MoveDir(src, dst, gfile.CopyOption{Include : []string{"*.txt"}}), nil)
+        gtest.Assert(gfile.GetContents(dst + "/a.txt"), "a")
+        gtest.Assert(gfile.GetContents(dst + "/sub/c.txt"), "c")
+        gtest.Assert(gfile.Exists(src + "/a.txt"), false)
+        gtest.Assert(gfile.Exists(src + "/sub"), false)
+        gtest.Assert(gfile.Exists(src + "/b.log"), true)
+
+        gtest.Assert(gfile.MoveDir(src, dst + "/all"), nil)
+        gtest.Assert(gfile.Exists(src), false)
+        gtest.Assert(gfile.GetContents(dst + "/all/b.log"), "b")
+    })
+    // 跟随符号链接时只删除符号链接本身，不删除链接指向的源目录之外的文件
+    gtest.Case(t, func() {
+        src    := testDir("move_link_src")
+        dst    := testDir("move_link_dst")
+        target := testDir("move_link_target")
+        defer gfile.Remove(src)
+        defer gfile.Remove(dst)
+        defer gfile.Remove(target)
+        gfile.PutContents(src + "/a.txt", "a")
+        gfile.PutContents(target + "/b.txt", "b")
+        gfile.PutContents(target + "/sub/c.txt", "c")
+        gtest.Assert(os.Symlink(target, src + "/dir"), nil)
+        gtest.Assert(os.Symlink(target + "/b.txt", src + "/b.txt"), nil)
+
+        gtest.Assert(gfile.MoveDir(src, dst, gfile.CopyOption{Symlink : gfile.SYMLINK_FOLLOW}), nil)
+        gtest.Assert(gfile.GetContents(dst + "/a.txt"),         "a")
+        gtest.Assert(gfile.GetContents(dst + "/b.txt"),         "b")
+        gtest.Assert(gfile.GetContents(dst + "/dir/b.txt"),     "b")
+        gtest.Assert(gfile.GetContents(dst + "/dir/sub/c.txt"), "c")
+        gtest.Assert(gfile.Exists(src), false)
+        gtest.Assert(gfile.GetContents(target + "/b.txt"),     "b")
+        gtest.Assert(gfile.GetContents(target + "/sub/c.txt"), "c")
+    })
+}
+
+func TestPutContentsAtomic(t *testing.T) {
+    gtest.Case(t, func() {
+        dir  := testDir("atomic")
+        path := dir + "/conf/app.toml"
+        defer gfile.Remove(dir)
+        gtest.Assert(gfile.PutContentsAtomic(path, "v1"), nil)
+        gtest.Assert(gfile.GetContents(path), "v1")
+        info, _ := os.Stat(path)
+        gtest.Assert(info.Mode().Perm(), os.FileMode(0644))
+
+        os.Chmod(path, 0600)
+        gtest.Assert(gfile.PutBinContentsAtomic(path, []byte("v2")), nil)
+        gtest.Assert(gfile.GetContents(path), "v2")
+        info, _ = os.Stat(path)
+        gtest.Assert(info.Mode().Perm(), os.FileMode(0600))
+        // 不残留临时文件
+        names, _ := gfile.DirNames(dir + "/conf")
+        gtest.Assert(names, []string{"app.toml"})
+    })
+}
+
+func TestGlob(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := testDir("glob")
+        defer gfile.Remove(dir)
+        gfile.PutContents(dir + "/index.html", "")
+        gfile.PutContents(dir + "/main.go", "")
+        gfile.PutContents(dir + "/a/b/c.go", "")
+        gfile.PutContents(dir + "/a/d.tpl", "")
+        gfile.PutContents(dir + "/a/e.txt", "")
+
+        list, err := gfile.Glob(dir + "/**/*.go")
+        gtest.Assert(err, nil)
+        gtest.Assert(list, []string{dir + "/a/b/c.go", dir + "/main.go"})
+
+        list, err = gfile.Glob(dir + "/**/*.{html,tpl}", true)
+        gtest.Assert(err, nil)
+        gtest.Assert(list, []string{"d.tpl", "index.html"})
+
+        list, err = gfile.Glob(dir + "/a/**")
+        gtest.Assert(err, nil)
+        gtest.Assert(len(list), 4)
+
+        list, err = gfile.Glob(dir + "/{main,a/e}.{go,txt}")
+        gtest.Assert(err, nil)
+        gtest.Assert(list, []string{dir + "/a/e.txt", dir + "/main.go"})
+
+        list, err = gfile.Glob(dir + "/*.go")
+        gtest.Assert(list, []string{dir + "/main.go"})
+
+        _, err = gfile.Glob(dir + "/{a,b")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestSearchContent(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := testDir("search")
+        defer gfile.Remove(dir)
+        gfile.PutContents(dir + "/a.go", "package a\n\n// TODO: fix\nfunc A() {}")
+        gfile.PutContents(dir + "/sub/b.go", "// TODO first\npackage b")
+        gfile.PutContents(dir + "/c.txt", "TODO")
+
+        result, err := gfile.SearchContent(dir, "*.go", `TODO`)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(result), 2)
+        gtest.Assert(result[0].Path, dir + "/a.go")
+        gtest.Assert(result[0].Line, 3)
+        gtest.Assert(result[0].Content, "// TODO: fix")
+        gtest.Assert(result[1].Path, dir + "/sub/b.go")
+        gtest.Assert(result[1].Line, 1)
+
+        result, err = gfile.SearchContent(dir, "*.go,*.txt", `^func|^TODO$`)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(result), 2)
+        gtest.Assert(result[0].Line, 4)
+        gtest.Assert(result[1].Path, dir + "/c.txt")
+
+        _, err = gfile.SearchContent(dir, "*", `(`)
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestReadLinesAndChunks(t *testing.T) {
+    gtest.Case(t, func() {
+        dir  := testDir("read")
+        path := dir + "/data.txt"
+        defer gfile.Remove(dir)
+        gfile.PutContents(path, "line1\r\nline2\n\nline4")
+
+        lines := make([]string, 0)
+        gtest.Assert(gfile.ReadLines(path, func(line string) bool {
+            lines = append(lines, line)
+            return true
+        }), nil)
+        gtest.Assert(lines, []string{"line1", "line2", "", "line4"})
+
+        lines = lines[ : 0]
+        gfile.ReadLines(path, func(line string) bool {
+            lines = append(lines, line)
+            return len(lines) < 2
+        })
+        gtest.Assert(lines, []string{"line1", "line2"})
+
+        chunks := make([]string, 0)
+        gtest.Assert(gfile.ReadChunks(path, func(chunk []byte) bool {
+            chunks = append(chunks, string(chunk))
+            return true
+        }, 8), nil)
+        gtest.Assert(chunks, []string{"line1\r\nl", "ine2\n\nli", "ne4"})
+
+        gtest.AssertNE(gfile.ReadLines(dir + "/none", func(line string) bool { return true }), nil)
+    })
+}
+
+func TestMmapReader(t *testing.T) {
+    gtest.Case(t, func() {
+        dir  := testDir("mmap")
+        path := dir + "/data.txt"
+        defer gfile.Remove(dir)
+        gfile.PutContents(path, "hello\nworld")
+
+        m, err := gfile.OpenMmap(path)
+        gtest.Assert(err, nil)
+        gtest.Assert(m.Len(), 11)
+        gtest.Assert(string(m.Bytes()), "hello\nworld")
+        buffer := make([]byte, 5)
+        n, err := m.ReadAt(buffer, 6)
+        gtest.Assert(n, 5)
+        gtest.Assert(string(buffer), "world")
+        n, _ = m.ReadAt(buffer, 8)
+        gtest.Assert(string(buffer[ : n]), "rld")
+        lines := make([]string, 0)
+        m.ReadLines(func(line string) bool {
+            lines = append(lines, line)
+            return true
+        })
+        gtest.Assert(lines, []string{"hello", "world"})
+        gtest.Assert(m.Close(), nil)
+        // 关闭之后不可再读取
+        _, err = m.ReadAt(buffer, 0)
+        gtest.AssertNE(err, nil)
+        gtest.Assert(m.Close(), nil)
+
+        gfile.PutContents(path, "")
+        m, err = gfile.OpenMmap(path)
+        gtest.Assert(err, nil)
+        gtest.Assert(m.Len(), 0)
+        _, err = m.ReadAt(buffer, 0)
+        gtest.Assert(err, io.EOF)
+        gtest.Assert(m.Close(), nil)
+        _, err = m.ReadAt(buffer, 0)
+        gtest.AssertNE(err, nil)
+    })
+}
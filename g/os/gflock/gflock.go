@@ -10,24 +10,37 @@
 package gflock
 
 import (
+    "path/filepath"
     "sync"
+    "time"
     "github.com/gogf/gf/third/github.com/theckman/go-flock"
     "github.com/gogf/gf/g/os/gfile"
 )
 
+const (
+    // 超时加锁时重试的时间间隔
+    gRETRY_INTERVAL = 10*time.Millisecond
+)
+
 // 文件锁
 type Locker struct {
     mu    sync.RWMutex // 用于外部接口调用的互斥锁(阻塞机制)
     flock *flock.Flock // 底层文件锁对象
 }
 
-// 创建文件锁
+// 创建文件锁，file为绝对路径时直接使用该文件作为锁文件(可用于多个进程间对同一文件的协同写入)，
+// 否则在系统临时目录的gflock目录下创建锁文件
 func New(file string) *Locker {
-    dir := gfile.TempDir() + gfile.Separator + "gflock"
-    if !gfile.Exists(dir) {
+    path := file
+    if !filepath.IsAbs(file) {
+        dir := gfile.TempDir() + gfile.Separator + "gflock"
+        if !gfile.Exists(dir) {
+            gfile.Mkdir(dir)
+        }
+        path = dir + gfile.Separator + file
+    } else if dir := gfile.Dir(file); !gfile.Exists(dir) {
         gfile.Mkdir(dir)
     }
-    path := dir + gfile.Separator + file
     lock := flock.NewFlock(path)
     return &Locker{
         flock : lock,
@@ -43,22 +56,56 @@ func (l *Locker) IsLocked() bool {
     return l.flock.Locked()
 }
 
-// 尝试Lock文件，如果失败立即返回
+// 尝试Lock文件，如果失败立即返回(当前进程或者其他进程已加锁时均返回失败)
 func (l *Locker) TryLock() bool {
-    ok, _ := l.flock.TryLock()
-    if ok {
-        l.mu.Lock()
+    if !l.mu.TryLock() {
+        return false
     }
-    return ok
+    if ok, _ := l.flock.TryLock(); ok {
+        return true
+    }
+    l.mu.Unlock()
+    return false
 }
 
 // 尝试RLock文件，如果失败立即返回
 func (l *Locker) TryRLock() bool {
-    ok, _ := l.flock.TryRLock()
-    if ok {
-        l.mu.RLock()
+    if !l.mu.TryRLock() {
+        return false
+    }
+    if ok, _ := l.flock.TryRLock(); ok {
+        return true
+    }
+    l.mu.RUnlock()
+    return false
+}
+
+// 在指定的超时时间内尝试Lock文件，超时返回false
+func (l *Locker) LockTimeout(timeout time.Duration) bool {
+    return tryWithTimeout(l.TryLock, timeout)
+}
+
+// 在指定的超时时间内尝试RLock文件，超时返回false
+func (l *Locker) RLockTimeout(timeout time.Duration) bool {
+    return tryWithTimeout(l.TryRLock, timeout)
+}
+
+// 按照固定时间间隔重试加锁，直到成功或者超时
+func tryWithTimeout(try func() bool, timeout time.Duration) bool {
+    deadline := time.Now().Add(timeout)
+    for {
+        if try() {
+            return true
+        }
+        left := deadline.Sub(time.Now())
+        if left <= 0 {
+            return false
+        }
+        if left > gRETRY_INTERVAL {
+            left = gRETRY_INTERVAL
+        }
+        time.Sleep(left)
     }
-    return ok
 }
 
 func (l *Locker) Lock() {
@@ -71,6 +118,11 @@ func (l *Locker) UnLock() {
     l.mu.Unlock()
 }
 
+// 实现sync.Locker接口，同UnLock
+func (l *Locker) Unlock() {
+    l.UnLock()
+}
+
 func (l *Locker) RLock() {
     l.mu.RLock()
     l.flock.RLock()
@@ -0,0 +1,50 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gflock_test
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gflock"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestLocker_TryLock(t *testing.T) {
+    gtest.Case(t, func() {
+        path := gfile.TempDir() + gfile.Separator + "gflock_test" + gfile.Separator + "test.lock"
+        defer gfile.Remove(gfile.Dir(path))
+        // 不同的Locker对象使用不同的文件描述符，与多个进程间的加锁行为一致
+        l1 := gflock.New(path)
+        l2 := gflock.New(path)
+        gtest.Assert(l1.Path(), path)
+        gtest.Assert(l1.TryLock(), true)
+        gtest.Assert(l1.TryLock(), false)
+        gtest.Assert(l2.TryLock(), false)
+        gtest.Assert(l2.TryRLock(), false)
+
+        start := time.Now()
+        gtest.Assert(l2.LockTimeout(100*time.Millisecond), false)
+        gtest.Assert(time.Since(start) >= 100*time.Millisecond, true)
+
+        go func() {
+            time.Sleep(100*time.Millisecond)
+            l1.Unlock()
+        }()
+        gtest.Assert(l2.LockTimeout(time.Second), true)
+        gtest.Assert(l1.RLockTimeout(50*time.Millisecond), false)
+        l2.Unlock()
+
+        gtest.Assert(l1.TryRLock(), true)
+        gtest.Assert(l2.TryRLock(), true)
+        gtest.Assert(l2.TryLock(), false)
+        l1.RUnlock()
+        l2.RUnlock()
+    })
+}
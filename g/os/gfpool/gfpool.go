@@ -10,13 +10,22 @@
 package gfpool
 
 import (
+    "errors"
     "fmt"
     "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/container/gpool"
     "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/os/gfsnotify"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/os/gtimer"
     "os"
     "sync"
+    "time"
+)
+
+const (
+    gDEFAULT_SWEEP_INTERVAL = 60*time.Second // 闲置指针池的清理检查间隔
+    gDEFAULT_POOL_IDLE      = 60000          // (毫秒)指针池没有任何打开的文件指针并且闲置超过该时间后被清理
 )
 
 // 文件指针池
@@ -25,6 +34,29 @@ type Pool struct {
     pool       *gpool.Pool       // 底层对象池
     inited     *gtype.Bool       // 是否初始化(在执行第一次File方法后初始化，主要用于监听的添加，但是只能添加一次)
     expire     int               // 过期时间
+    path       string            // 文件路径
+    closed     *gtype.Bool       // 指针池是否已关闭
+    maxOpen    *gtype.Int        // 最大打开的文件指针数量，0表示不限制
+    opened     *gtype.Int        // 当前打开的文件指针数量(使用中+闲置)
+    inUse      *gtype.Int        // 当前使用中的文件指针数量
+    gets       *gtype.Int64      // 获取文件指针的总次数
+    misses     *gtype.Int64      // 新打开文件指针的总次数
+    expired    *gtype.Int64      // 过期(或者重建)关闭的文件指针总数
+    rejected   *gtype.Int64      // 超过最大打开数量被拒绝的总次数
+    lastUsed   *gtype.Int64      // (毫秒)最后一次获取或者归还文件指针的时间
+}
+
+// 文件指针池统计信息
+type Stats struct {
+    Path     string // 文件路径
+    MaxOpen  int    // 最大打开的文件指针数量，0表示不限制
+    Open     int    // 当前打开的文件指针数量(使用中+闲置)
+    InUse    int    // 当前使用中的文件指针数量
+    Idle     int    // 当前闲置的文件指针数量
+    Hits     int64  // 复用闲置文件指针的总次数
+    Misses   int64  // 新打开文件指针的总次数
+    Expired  int64  // 过期(或者重建)关闭的文件指针总数
+    Rejected int64  // 超过最大打开数量被拒绝的总次数
 }
 
 // 文件指针池指针
@@ -38,8 +70,56 @@ type File struct {
     path   string           // 绝对路径
 }
 
-// 全局指针池，expire < 0表示不过期，expire = 0表示使用完立即回收，expire > 0表示超时回收
-var pools = gmap.NewStringInterfaceMap()
+var (
+    // 全局指针池，expire < 0表示不过期，expire = 0表示使用完立即回收，expire > 0表示超时回收
+    pools   = gmap.NewStringInterfaceMap()
+    // 新建指针池默认的最大打开文件指针数量，0表示不限制
+    maxOpen = gtype.NewInt()
+)
+
+func init() {
+    gtimer.AddSingleton(gDEFAULT_SWEEP_INTERVAL, sweepPools)
+}
+
+// 设置新建指针池默认的单个文件路径最大打开的文件指针数量，0表示不限制(默认)，
+// 超过该数量时获取文件指针将会返回错误，而不是无限制地打开新的文件描述符。
+func SetMaxOpen(max int) {
+    maxOpen.Set(max)
+}
+
+// 获取新建指针池默认的最大打开文件指针数量
+func GetMaxOpen() int {
+    return maxOpen.Val()
+}
+
+// 获取全局指针池(Open/OpenFile创建)的统计信息
+func GetStats() []Stats {
+    stats := make([]Stats, 0)
+    pools.RLockFunc(func(m map[string]interface{}) {
+        for _, v := range m {
+            stats = append(stats, v.(*Pool).Stats())
+        }
+    })
+    return stats
+}
+
+// 清理全局指针池中没有打开任何文件指针并且闲置超时的指针池
+func sweepPools() {
+    now := gtime.Millisecond()
+    pools.LockFunc(func(m map[string]interface{}) {
+        for k, v := range m {
+            p    := v.(*Pool)
+            idle := int64(gDEFAULT_POOL_IDLE)
+            if int64(p.expire) > idle {
+                idle = int64(p.expire)
+            }
+            if p.opened.Val() == 0 && p.inUse.Val() == 0 && now - p.lastUsed.Val() > idle {
+                delete(m, k)
+                p.Close()
+            }
+        }
+    })
+}
 
 // 获得文件对象，并自动创建指针池(过期时间单位：毫秒)
 func Open(path string, flag int, perm os.FileMode, expire...int) (file *File, err error) {
@@ -66,9 +146,19 @@ func New(path string, flag int, perm os.FileMode, expire...int) *Pool {
         fpExpire = expire[0]
     }
     p := &Pool {
-        id     : gtype.NewInt(),
-        expire : fpExpire,
-        inited : gtype.NewBool(),
+        id       : gtype.NewInt(),
+        expire   : fpExpire,
+        inited   : gtype.NewBool(),
+        path     : path,
+        closed   : gtype.NewBool(),
+        maxOpen  : gtype.NewInt(maxOpen.Val()),
+        opened   : gtype.NewInt(),
+        inUse    : gtype.NewInt(),
+        gets     : gtype.NewInt64(),
+        misses   : gtype.NewInt64(),
+        expired  : gtype.NewInt64(),
+        rejected : gtype.NewInt64(),
+        lastUsed : gtype.NewInt64(gtime.Millisecond()),
     }
     p.pool = newFilePool(p, path, flag, perm, fpExpire)
     return p
@@ -77,10 +167,18 @@ func New(path string, flag int, perm os.FileMode, expire...int) *Pool {
 // 创建文件指针池
 func newFilePool(p *Pool, path string, flag int, perm os.FileMode, expire int) *gpool.Pool {
     pool := gpool.New(expire, func() (interface{}, error) {
+        // 先占用计数再打开文件，保证并发情况下打开数量不超过限制
+        if n := p.opened.Add(1); p.maxOpen.Val() > 0 && n > p.maxOpen.Val() {
+            p.opened.Add(-1)
+            p.rejected.Add(1)
+            return nil, errors.New(fmt.Sprintf(`too many open file pointers for "%s": %d`, path, p.maxOpen.Val()))
+        }
         file, err := os.OpenFile(path, flag, perm)
         if err != nil {
+            p.opened.Add(-1)
             return nil, err
         }
+        p.misses.Add(1)
         return &File {
             File   : file,
             pool   : p,
@@ -90,25 +188,54 @@ func newFilePool(p *Pool, path string, flag int, perm os.FileMode, expire int) *
             path   : path,
         }, nil
     }, func(i interface{}) {
-        i.(*File).File.Close()
+        p.expired.Add(1)
+        i.(*File).close()
     })
     return pool
 }
 
+// 设置指针池最大打开的文件指针数量(使用中+闲置)，0表示不限制
+func (p *Pool) SetMaxOpen(max int) {
+    p.maxOpen.Set(max)
+}
+
+// 获取指针池的统计信息
+func (p *Pool) Stats() Stats {
+    gets   := p.gets.Val()
+    misses := p.misses.Val()
+    return Stats {
+        Path     : p.path,
+        MaxOpen  : p.maxOpen.Val(),
+        Open     : p.opened.Val(),
+        InUse    : p.inUse.Val(),
+        Idle     : p.pool.Size(),
+        Hits     : gets - misses,
+        Misses   : misses,
+        Expired  : p.expired.Val(),
+        Rejected : p.rejected.Val(),
+    }
+}
+
 // 获得一个文件打开指针
 func (p *Pool) File() (*File, error) {
+    p.lastUsed.Set(gtime.Millisecond())
     if v, err := p.pool.Get(); err != nil {
         return nil, err
     } else {
+        p.gets.Add(1)
+        p.inUse.Add(1)
         f         := v.(*File)
         stat, err := os.Stat(f.path)
         if f.flag & os.O_CREATE > 0 {
            if os.IsNotExist(err) {
                if file, err := os.OpenFile(f.path, f.flag, f.perm); err != nil {
+                   f.release()
                    return nil, err
                } else {
+                   f.File.Close()
                    f.File = file
                    if stat, err = f.Stat(); err != nil {
+                       f.release()
                        return nil, err
                    }
                }
@@ -117,16 +244,19 @@ func (p *Pool) File() (*File, error) {
         if f.flag & os.O_TRUNC > 0 {
            if stat.Size() > 0 {
                if err := f.Truncate(0); err != nil {
+                   f.release()
                    return nil, err
                }
            }
         }
         if f.flag & os.O_APPEND > 0 {
            if _, err := f.Seek(0, 2); err != nil {
+               f.release()
                return nil, err
            }
         } else {
            if _, err := f.Seek(0, 0); err != nil {
+               f.release()
                return nil, err
            }
         }
@@ -150,15 +280,34 @@ func (p *Pool) File() (*File, error) {
     }
 }
 
-// 关闭指针池
+// 关闭指针池，闲置的文件指针立即关闭，使用中的文件指针在归还时关闭
 func (p *Pool) Close() {
+    p.closed.Set(true)
+    p.pool.Clear()
     p.pool.Close()
 }
 
-// 获得底层文件指针(返回error是标准库io.ReadWriteCloser接口实现)
+// 归还文件指针到池中(返回error是标准库io.ReadWriteCloser接口实现)，
+// 指针池已重建、已关闭或者使用完立即回收(expire < 0)时直接关闭底层文件指针
 func (f *File) Close() error {
-    if f.poolid == f.pool.id.Val() {
+    f.pool.lastUsed.Set(gtime.Millisecond())
+    f.pool.inUse.Add(-1)
+    if f.poolid == f.pool.id.Val() && !f.pool.closed.Val() && f.pool.expire >= 0 {
         f.pool.pool.Put(f)
+    } else {
+        f.close()
     }
     return nil
 }
+
+// 获取文件指针后处理失败时，关闭并丢弃该文件指针
+func (f *File) release() {
+    f.pool.inUse.Add(-1)
+    f.close()
+}
+
+// 关闭底层文件指针
+func (f *File) close() {
+    f.File.Close()
+    f.pool.opened.Add(-1)
+}
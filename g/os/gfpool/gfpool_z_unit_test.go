@@ -0,0 +1,103 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gfpool_test
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gfpool"
+    "github.com/gogf/gf/g/test/gtest"
+    "os"
+    "testing"
+    "time"
+)
+
+func TestPool_MaxOpenAndStats(t *testing.T) {
+    gtest.Case(t, func() {
+        path := gfile.TempDir() + gfile.Separator + "gfpool_test_max_open"
+        defer gfile.Remove(path)
+        pool := gfpool.New(path, os.O_RDWR|os.O_CREATE, 0666)
+        defer pool.Close()
+        pool.SetMaxOpen(2)
+
+        f1, err := pool.File()
+        gtest.Assert(err, nil)
+        f2, err := pool.File()
+        gtest.Assert(err, nil)
+        _, err = pool.File()
+        gtest.AssertNE(err, nil)
+
+        stats := pool.Stats()
+        gtest.Assert(stats.Path, path)
+        gtest.Assert(stats.MaxOpen, 2)
+        gtest.Assert(stats.Open, 2)
+        gtest.Assert(stats.InUse, 2)
+        gtest.Assert(stats.Misses, 2)
+        gtest.Assert(stats.Rejected, 1)
+
+        f1.Close()
+        f2.Close()
+        f3, err := pool.File()
+        gtest.Assert(err, nil)
+        stats = pool.Stats()
+        gtest.Assert(stats.Open, 2)
+        gtest.Assert(stats.InUse, 1)
+        gtest.Assert(stats.Idle, 1)
+        gtest.Assert(stats.Hits, 1)
+        f3.Close()
+
+        pool.Close()
+        stats = pool.Stats()
+        gtest.Assert(stats.Open, 0)
+        gtest.Assert(stats.Idle, 0)
+    })
+}
+
+func TestPool_Expire(t *testing.T) {
+    gtest.Case(t, func() {
+        path := gfile.TempDir() + gfile.Separator + "gfpool_test_expire"
+        defer gfile.Remove(path)
+        // 使用完立即回收
+        pool := gfpool.New(path, os.O_RDWR|os.O_CREATE, 0666, -1)
+        f, err := pool.File()
+        gtest.Assert(err, nil)
+        f.Close()
+        gtest.Assert(pool.Stats().Open, 0)
+        pool.Close()
+
+        // 闲置超时回收
+        pool = gfpool.New(path, os.O_RDWR|os.O_CREATE, 0666, 100)
+        defer pool.Close()
+        f, err = pool.File()
+        gtest.Assert(err, nil)
+        f.Close()
+        gtest.Assert(pool.Stats().Open, 1)
+        time.Sleep(1500*time.Millisecond)
+        stats := pool.Stats()
+        gtest.Assert(stats.Open, 0)
+        gtest.Assert(stats.Expired, 1)
+    })
+}
+
+func TestOpen_Stats(t *testing.T) {
+    gtest.Case(t, func() {
+        path := gfile.TempDir() + gfile.Separator + "gfpool_test_open"
+        defer gfile.Remove(path)
+        f, err := gfpool.Open(path, os.O_RDWR|os.O_CREATE, 0666)
+        gtest.Assert(err, nil)
+        found := false
+        for _, s := range gfpool.GetStats() {
+            if s.Path == path {
+                found = true
+                gtest.Assert(s.InUse, 1)
+            }
+        }
+        gtest.Assert(found, true)
+        f.Close()
+    })
+}
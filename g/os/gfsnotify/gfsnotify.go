@@ -18,6 +18,7 @@ import (
     "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/os/gcache"
     "github.com/gogf/gf/third/github.com/fsnotify/fsnotify"
+    "sync"
 )
 
 // 监听管理对象
@@ -26,6 +27,7 @@ type Watcher struct {
     events         *gqueue.Queue            // 过滤后的事件通知，不会出现重复事件
     cache          *gcache.Cache            // 缓存对象，主要用于事件重复过滤
     callbacks      *gmap.StringInterfaceMap // 注册的所有绝对路径(文件/目录)及其对应的回调函数列表map
+    pollers        *gmap.StringInterfaceMap // 轮询监听的绝对路径(文件/目录)及其对应的轮询监听对象map
     closeChan      chan struct{}            // 关闭事件
 }
 
@@ -36,6 +38,9 @@ type Callback struct {
     Path      string              // 监听的文件/目录
     elem      *glist.Element      // 指向回调函数链表中的元素项位置(便于删除)
     recursive bool                // 当目录时，是否递归监听(使用在子文件/目录回溯查找回调函数时)
+    mu        sync.Mutex          // 监听选项及合并事件的互斥锁
+    option    *WatchOption        // 监听选项(使用AddWithOption添加时有效)
+    pending   map[string]*Event   // 事件合并窗口中等待回调的事件(键名为文件路径)
 }
 
 // 监听事件对象
@@ -44,6 +49,7 @@ type Event struct {
     Path    string           // 文件绝对路径
     Op      Op               // 触发监听的文件操作
     Watcher *Watcher         // 事件对应的监听对象
+    polled  bool             // 是否为轮询监听产生的事件
 }
 
 // 按位进行识别的操作集合
@@ -73,18 +79,18 @@ var (
     callbackIdGenerator = gtype.NewInt()
 )
 
-// 创建监听管理对象，主要注意的是创建监听对象会占用系统的inotify句柄数量，受到 fs.inotify.max_user_instances 的限制
+// 创建监听管理对象，主要注意的是创建监听对象会占用系统的inotify句柄数量，受到 fs.inotify.max_user_instances 的限制。
+// 当系统不支持inotify或者inotify句柄不足时，自动使用轮询的方式进行监听。
 func New() (*Watcher, error) {
     w := &Watcher {
         cache     : gcache.New(),
         events    : gqueue.New(),
         closeChan : make(chan struct{}),
         callbacks : gmap.NewStringInterfaceMap(),
+        pollers   : gmap.NewStringInterfaceMap(),
     }
     if watcher, err := fsnotify.NewWatcher(); err == nil {
         w.watcher = watcher
-    } else {
-        return nil, err
     }
     w.startWatchLoop()
     w.startEventLoop()
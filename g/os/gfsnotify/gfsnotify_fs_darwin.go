@@ -0,0 +1,34 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfsnotify
+
+import "syscall"
+
+// 网络文件系统的类型名称
+var networkFsTypes = map[string]struct{} {
+    "nfs"    : {},
+    "smbfs"  : {},
+    "afpfs"  : {},
+    "webdav" : {},
+}
+
+// 判断路径是否位于网络文件系统(NFS/SMB等)上，网络文件系统上无法通过文件事件监听其他主机产生的修改
+func isNetworkFs(path string) bool {
+    stat := syscall.Statfs_t{}
+    if err := syscall.Statfs(path, &stat); err != nil {
+        return false
+    }
+    name := make([]byte, 0, len(stat.Fstypename))
+    for _, c := range stat.Fstypename {
+        if c == 0 {
+            break
+        }
+        name = append(name, byte(c))
+    }
+    _, ok := networkFsTypes[string(name)]
+    return ok
+}
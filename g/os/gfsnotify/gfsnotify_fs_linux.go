@@ -0,0 +1,30 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfsnotify
+
+import "syscall"
+
+// 网络文件系统的类型标识(statfs f_type)
+var networkFsTypes = map[uint32]struct{} {
+    0x6969     : {}, // NFS
+    0x517B     : {}, // SMB
+    0xFF534D42 : {}, // CIFS
+    0xFE534D42 : {}, // SMB2
+    0x01021997 : {}, // 9P
+    0x73757245 : {}, // CODA
+    0x5346414F : {}, // AFS
+}
+
+// 判断路径是否位于网络文件系统(NFS/SMB等)上，网络文件系统上无法通过inotify监听其他主机产生的修改
+func isNetworkFs(path string) bool {
+    stat := syscall.Statfs_t{}
+    if err := syscall.Statfs(path, &stat); err != nil {
+        return false
+    }
+    _, ok := networkFsTypes[uint32(stat.Type)]
+    return ok
+}
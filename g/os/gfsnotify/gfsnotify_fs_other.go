@@ -0,0 +1,14 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build !linux,!darwin
+
+package gfsnotify
+
+// 判断路径是否位于网络文件系统上，当前平台不支持检测
+func isNetworkFs(path string) bool {
+    return false
+}
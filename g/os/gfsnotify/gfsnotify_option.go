@@ -0,0 +1,118 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfsnotify
+
+import (
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// 监听选项
+type WatchOption struct {
+    Recursive bool          // 当监听目录时，是否递归监听(包括之后新创建的子目录)
+    Debounce  time.Duration // 事件合并窗口，同一路径在窗口时间内产生的多个事件合并为一次回调(Op为合并后的操作集合)，0表示不合并
+    Include   []string      // 包含的文件名称模式(filepath.Match语法)，匹配文件名或者相对于监听目录的路径(使用'/'分隔)，为空表示包含所有文件
+    Exclude   []string      // 排除的文件/目录名称模式，匹配文件名、相对路径或者相对路径中的任一级目录名称
+    Poll      bool          // 是否使用轮询的方式监听(通过对比修改时间及文件大小)，系统不支持inotify或者路径位于网络文件系统(NFS/SMB等)时自动使用
+    Interval  time.Duration // 轮询监听的检查时间间隔，默认为1秒
+}
+
+// 使用选项添加对指定文件/目录的监听，例如忽略编辑器产生的临时文件并合并短时间内的重复事件:
+// gfsnotify.AddWithOption(path, callback, gfsnotify.WatchOption{Recursive : true, Debounce : 100*time.Millisecond, Exclude : []string{"*.swp", "*~", ".git"}})
+func AddWithOption(path string, callbackFunc func(event *Event), option WatchOption) (callback *Callback, err error) {
+    return defaultWatcher.AddWithOption(path, callbackFunc, option)
+}
+
+// 使用选项添加对指定文件/目录的监听
+func (w *Watcher) AddWithOption(path string, callbackFunc func(event *Event), option WatchOption) (callback *Callback, err error) {
+    callback, err = w.add(path, callbackFunc, []bool{option.Recursive}, option.Poll, option.Interval)
+    if err != nil {
+        return nil, err
+    }
+    callback.mu.Lock()
+    callback.option  = &option
+    callback.pending = make(map[string]*Event)
+    callback.mu.Unlock()
+    return callback, nil
+}
+
+// 执行回调方法(异步)，根据监听选项进行事件过滤及合并
+func (c *Callback) dispatch(event *Event) {
+    c.mu.Lock()
+    option := c.option
+    c.mu.Unlock()
+    if option == nil {
+        go c.Func(event)
+        return
+    }
+    if !c.match(option, event.Path) {
+        return
+    }
+    if option.Debounce <= 0 {
+        go c.Func(event)
+        return
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if pending, ok := c.pending[event.Path]; ok {
+        pending.Op |= event.Op
+        return
+    }
+    c.pending[event.Path] = &Event {
+        event   : event.event,
+        Path    : event.Path,
+        Op      : event.Op,
+        Watcher : event.Watcher,
+    }
+    time.AfterFunc(option.Debounce, func() {
+        c.mu.Lock()
+        pending := c.pending[event.Path]
+        delete(c.pending, event.Path)
+        c.mu.Unlock()
+        if pending != nil {
+            c.Func(pending)
+        }
+    })
+}
+
+// 判断事件路径是否满足监听选项的包含/排除条件
+func (c *Callback) match(option *WatchOption, path string) bool {
+    if len(option.Include) == 0 && len(option.Exclude) == 0 {
+        return true
+    }
+    name := filepath.Base(path)
+    rel  := name
+    if r, err := filepath.Rel(c.Path, path); err == nil && r != "." && !strings.HasPrefix(r, "..") {
+        rel = filepath.ToSlash(r)
+    }
+    for _, p := range option.Exclude {
+        if matchPattern(p, name) || matchPattern(p, rel) {
+            return false
+        }
+        for _, s := range strings.Split(rel, "/") {
+            if matchPattern(p, s) {
+                return false
+            }
+        }
+    }
+    if len(option.Include) == 0 {
+        return true
+    }
+    for _, p := range option.Include {
+        if matchPattern(p, name) || matchPattern(p, rel) {
+            return true
+        }
+    }
+    return false
+}
+
+// 文件名称模式匹配
+func matchPattern(pattern string, name string) bool {
+    match, err := filepath.Match(pattern, name)
+    return err == nil && match
+}
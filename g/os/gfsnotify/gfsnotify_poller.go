@@ -0,0 +1,157 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gfsnotify
+
+import (
+    "github.com/gogf/gf/g/os/gtimer"
+    "github.com/gogf/gf/third/github.com/fsnotify/fsnotify"
+    "os"
+    "sync"
+    "time"
+)
+
+const (
+    gDEFAULT_POLL_INTERVAL = time.Second // 轮询监听默认的检查时间间隔
+)
+
+// 轮询监听对象，定时扫描文件/目录，通过对比修改时间、大小及权限产生事件，
+// 用于不支持inotify的系统或者网络文件系统(NFS/SMB等)
+type poller struct {
+    mu        sync.Mutex
+    path      string               // 监听的文件/目录绝对路径
+    recursive bool                 // 当目录时，是否递归扫描子级目录
+    entry     *gtimer.Entry        // 定时扫描任务
+    states    map[string]fileState // 上一次扫描的文件状态(键名为文件绝对路径)
+}
+
+// 轮询扫描的文件状态
+type fileState struct {
+    mtime int64       // 修改时间(纳秒)
+    size  int64       // 文件大小
+    mode  os.FileMode // 文件权限及类型
+}
+
+// 添加轮询监听，同一路径只会创建一个轮询监听对象，recursive为true时将会替换原有的非递归轮询监听
+func (w *Watcher) addPoller(path string, recursive bool, interval time.Duration) {
+    if interval <= 0 {
+        interval = gDEFAULT_POLL_INTERVAL
+    }
+    w.pollers.LockFunc(func(m map[string]interface{}) {
+        if v, ok := m[path]; ok {
+            if !recursive || v.(*poller).recursive {
+                return
+            }
+            v.(*poller).entry.Close()
+        }
+        p := &poller {
+            path      : path,
+            recursive : recursive,
+        }
+        p.states = p.scan()
+        p.entry  = gtimer.AddSingleton(interval, func() {
+            p.check(w)
+        })
+        m[path] = p
+    })
+}
+
+// 移除指定路径的轮询监听
+func (w *Watcher) removePoller(path string) bool {
+    if v := w.pollers.Remove(path); v != nil {
+        v.(*poller).entry.Close()
+        return true
+    }
+    return false
+}
+
+// 关闭所有的轮询监听
+func (w *Watcher) closePollers() {
+    w.pollers.LockFunc(func(m map[string]interface{}) {
+        for k, v := range m {
+            v.(*poller).entry.Close()
+            delete(m, k)
+        }
+    })
+}
+
+// 扫描文件/目录，返回当前的文件状态
+func (p *poller) scan() map[string]fileState {
+    states := make(map[string]fileState)
+    info, err := os.Stat(p.path)
+    if err != nil {
+        return states
+    }
+    states[p.path] = newFileState(info)
+    if info.IsDir() {
+        p.doScan(p.path, states)
+    }
+    return states
+}
+
+// 扫描目录下的文件状态
+func (p *poller) doScan(path string, states map[string]fileState) {
+    file, err := os.Open(path)
+    if err != nil {
+        return
+    }
+    infos, _ := file.Readdir(-1)
+    file.Close()
+    for _, info := range infos {
+        subPath := path + string(os.PathSeparator) + info.Name()
+        states[subPath] = newFileState(info)
+        if info.IsDir() && p.recursive {
+            p.doScan(subPath, states)
+        }
+    }
+}
+
+// 对比扫描结果，产生文件事件
+func (p *poller) check(w *Watcher) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    states := p.scan()
+    for path, state := range states {
+        old, ok := p.states[path]
+        switch {
+            case !ok:
+                w.pushPollEvent(path, CREATE)
+            case old.mode != state.mode:
+                w.pushPollEvent(path, CHMOD)
+            case old.mtime != state.mtime || old.size != state.size:
+                // 目录的修改时间变化由子级文件的创建/删除引起，不产生事件
+                if !state.mode.IsDir() {
+                    w.pushPollEvent(path, WRITE)
+                }
+        }
+    }
+    for path := range p.states {
+        if _, ok := states[path]; !ok {
+            w.pushPollEvent(path, REMOVE)
+        }
+    }
+    p.states = states
+}
+
+// 添加轮询监听产生的事件到事件队列
+func (w *Watcher) pushPollEvent(path string, op Op) {
+    w.events.Push(&Event {
+        event   : fsnotify.Event{Name : path, Op : fsnotify.Op(op)},
+        Path    : path,
+        Op      : op,
+        Watcher : w,
+        polled  : true,
+    })
+}
+
+// 生成文件状态
+func newFileState(info os.FileInfo) fileState {
+    return fileState {
+        mtime : info.ModTime().UnixNano(),
+        size  : info.Size(),
+        mode  : info.Mode(),
+    }
+}
@@ -10,16 +10,29 @@ import (
     "errors"
     "fmt"
     "github.com/gogf/gf/g/container/glist"
+    "time"
 )
 
 // 添加监控，path参数支持文件或者目录路径，recursive为非必需参数，默认为非递归监控(当path为目录时)。
 // 如果添加目录，这里只会返回目录的callback，按照callback删除时会递归删除。
 func (w *Watcher) Add(path string, callbackFunc func(event *Event), recursive...bool) (callback *Callback, err error) {
+    return w.add(path, callbackFunc, recursive, false, 0)
+}
+
+// 添加监控，poll为true、系统不支持inotify或者路径位于网络文件系统时使用轮询监听，interval为轮询检查时间间隔
+func (w *Watcher) add(path string, callbackFunc func(event *Event), recursive []bool, poll bool, interval time.Duration) (callback *Callback, err error) {
     // 首先添加这个文件/目录
     callback, err = w.addWithCallbackFunc(path, callbackFunc, recursive...)
     if err != nil {
         return nil, err
     }
+    // 使用轮询监听
+    if poll || w.watcher == nil || isNetworkFs(callback.Path) {
+        w.addPoller(callback.Path, len(recursive) == 0 || recursive[0], interval)
+        return
+    }
+    // 添加底层监听
+    w.watcher.Add(callback.Path)
     // 如果需要递归，那么递归添加其下的子级目录，
     // 注意!!
     // 1、这里只递归添加**目录**, 而非文件，因为监控了目录即监控了其下一级的文件;
@@ -61,8 +74,6 @@ func (w *Watcher) addWithCallbackFunc(path string, callbackFunc func(event *Even
         }
         callback.elem = list.PushBack(callback)
     })
-    // 添加底层监听
-    w.watcher.Add(path)
     // 添加成功后会注册该callback id到全局的哈希表
     callbackIdMap.Set(callback.Id, callback)
     return
@@ -70,9 +81,11 @@ func (w *Watcher) addWithCallbackFunc(path string, callbackFunc func(event *Even
 
 // 关闭监听管理对象
 func (w *Watcher) Close() {
-    w.events.Close()
-    w.watcher.Close()
     close(w.closeChan)
+    w.closePollers()
+    if w.watcher != nil {
+        w.watcher.Close()
+    }
 }
 
 // 递归移除对指定文件/目录的所有监听回调
@@ -88,6 +101,10 @@ func (w *Watcher) Remove(path string) error {
             }
         }
     }
+    // 轮询监听直接移除轮询监听对象
+    if w.removePoller(path) || w.watcher == nil {
+        return nil
+    }
     // 其次递归判断所有的子级是否可删除监听
     if subPaths, err := fileScanDir(path, "*", true); err == nil && len(subPaths) > 0 {
         for _, subPath := range subPaths {
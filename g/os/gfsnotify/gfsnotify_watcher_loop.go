@@ -12,11 +12,21 @@ import (
 
 // 监听循环
 func (w *Watcher) startWatchLoop() {
+    // 不支持inotify时只使用轮询监听
+    if w.watcher == nil {
+        go func() {
+            <- w.closeChan
+            w.events.Close()
+        }()
+        return
+    }
     go func() {
         for {
             select {
-                // 关闭事件
-                case <- w.closeChan: return
+                // 关闭事件，由监听循环关闭事件队列，防止关闭后继续写入事件队列
+                case <- w.closeChan:
+                    w.events.Close()
+                    return
 
                     // 监听事件
                 case ev := <- w.watcher.Events:
@@ -83,10 +93,15 @@ func (w *Watcher) startEventLoop() {
                 // 如果该路径一个回调也没有，那么没有必要执行后续逻辑，删除对该文件的监听
                 callbacks := w.getCallbacks(event.Path)
                 if len(callbacks) == 0 {
-                    w.watcher.Remove(event.Path)
+                    if !event.polled {
+                        w.watcher.Remove(event.Path)
+                    }
                     continue
                 }
                 switch {
+                    // 轮询监听产生的事件已经是对比文件状态后的真实事件，不需要额外处理
+                    case event.polled:
+
                     // 如果是删除操作，那么需要判断是否文件真正不存在了，如果存在，那么将此事件认为“假删除”
                     case event.IsRemove():
                         if fileExists(event.Path) {
@@ -127,7 +142,7 @@ func (w *Watcher) startEventLoop() {
                 }
                 // 执行回调处理，异步处理
                 for _, callback := range callbacks {
-                    go callback.Func(event)
+                    callback.dispatch(event)
                 }
 
             } else {
@@ -0,0 +1,98 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gfsnotify_test
+
+import (
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gfsnotify"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestWatcher_AddWithOption(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := gfile.TempDir() + gfile.Separator + "gfsnotify_test_" + time.Now().Format("150405.000000")
+        gfile.Mkdir(dir)
+        defer gfile.Remove(dir)
+        watcher, err := gfsnotify.New()
+        gtest.Assert(err, nil)
+        defer watcher.Close()
+
+        events := garray.NewStringArray()
+        _, err  = watcher.AddWithOption(dir, func(event *gfsnotify.Event) {
+            events.Append(gfile.Basename(event.Path))
+        }, gfsnotify.WatchOption {
+            Recursive : true,
+            Debounce  : 200*time.Millisecond,
+            Exclude   : []string{"*.swp", "tmp"},
+        })
+        gtest.Assert(err, nil)
+
+        // 新创建的子目录自动加入监听
+        gfile.Mkdir(dir + "/sub")
+        time.Sleep(100*time.Millisecond)
+        for i := 0; i < 5; i++ {
+            gfile.PutContentsAppend(dir + "/sub/a.txt", "a")
+        }
+        gfile.PutContents(dir + "/b.swp", "b")
+        gfile.Mkdir(dir + "/tmp")
+        time.Sleep(100*time.Millisecond)
+        gfile.PutContents(dir + "/tmp/c.txt", "c")
+        time.Sleep(500*time.Millisecond)
+
+        // 多次写入事件合并为一次回调，排除的文件/目录不会触发回调
+        gtest.Assert(events.Slice(), []string{"sub", "a.txt"})
+    })
+}
+
+func TestWatcher_Poll(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := gfile.TempDir() + gfile.Separator + "gfsnotify_poll_" + time.Now().Format("150405.000000")
+        gfile.Mkdir(dir + "/sub")
+        gfile.PutContents(dir + "/sub/a.txt", "a")
+        defer gfile.Remove(dir)
+        watcher, err := gfsnotify.New()
+        gtest.Assert(err, nil)
+        defer watcher.Close()
+
+        events := garray.NewStringArray()
+        _, err  = watcher.AddWithOption(dir, func(event *gfsnotify.Event) {
+            if event.IsCreate() {
+                events.Append("create:" + gfile.Basename(event.Path))
+            }
+            if event.IsWrite() {
+                events.Append("write:" + gfile.Basename(event.Path))
+            }
+            if event.IsRemove() {
+                events.Append("remove:" + gfile.Basename(event.Path))
+            }
+        }, gfsnotify.WatchOption {
+            Recursive : true,
+            Poll      : true,
+            Interval  : 50*time.Millisecond,
+        })
+        gtest.Assert(err, nil)
+
+        gfile.PutContents(dir + "/sub/a.txt", "aa")
+        time.Sleep(200*time.Millisecond)
+        gfile.PutContents(dir + "/b.txt", "b")
+        time.Sleep(200*time.Millisecond)
+        gfile.Remove(dir + "/sub/a.txt")
+        time.Sleep(200*time.Millisecond)
+        gtest.Assert(events.Slice(), []string{"write:a.txt", "create:b.txt", "remove:a.txt"})
+
+        // 移除监听后不再产生事件
+        gtest.Assert(watcher.Remove(dir), nil)
+        gfile.PutContents(dir + "/c.txt", "c")
+        time.Sleep(200*time.Millisecond)
+        gtest.Assert(events.Len(), 3)
+    })
+}
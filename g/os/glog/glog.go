@@ -7,13 +7,14 @@
 
 // Package glog implements powerful and easy-to-use levelled logging functionality.
 // 
-// 日志模块, 直接文件/输出操作，没有异步逻辑，没有使用缓存或者通道
+// 日志模块, 默认直接文件/输出操作，没有异步逻辑；可通过SetAsync开启异步模式，使用有界缓冲区由后台goroutine写入
 package glog
 
 import (
     "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/internal/cmdenv"
     "io"
+    "time"
 )
 
 const (
@@ -26,6 +27,11 @@ const (
     LEVEL_CRIT
 )
 
+const (
+    FORMAT_TEXT = 0 // 日志输出格式：文本格式(默认)
+    FORMAT_JSON = 1 // 日志输出格式：JSON格式，每行一个JSON对象
+)
+
 var (
     // default level for log
     defaultLevel = gtype.NewInt(LEVEL_ALL)
@@ -133,6 +139,98 @@ func SetBacktrace(enabled bool) {
     logger.SetBacktrace(enabled)
 }
 
+// SetFormat sets the output format of logging content for default logger, FORMAT_TEXT or FORMAT_JSON.
+//
+// 设置默认日志对象的输出格式
+func SetFormat(format int) {
+    logger.SetFormat(format)
+}
+
+// GetFormat returns the output format of logging content for default logger.
+//
+// 获取默认日志对象的输出格式
+func GetFormat() int {
+    return logger.GetFormat()
+}
+
+// SetRotateSize sets the file size in bytes for rotating the logging file of default logger.
+//
+// 设置默认日志对象按照文件大小切分日志文件的大小阈值(字节)，0表示不切分
+func SetRotateSize(size int64) {
+    logger.SetRotateSize(size)
+}
+
+// SetRotateBackups sets the max number of rotated logging files to keep for default logger.
+//
+// 设置默认日志对象最多保留的历史日志文件数量，0表示不限制
+func SetRotateBackups(backups int) {
+    logger.SetRotateBackups(backups)
+}
+
+// SetRotateAge sets the max age of rotated logging files to keep for default logger.
+//
+// 设置默认日志对象历史日志文件的最长保留时间，0表示不限制
+func SetRotateAge(age time.Duration) {
+    logger.SetRotateAge(age)
+}
+
+// SetRotateCompress enables/disables the gzip compression of rotated logging files for default logger.
+//
+// 设置默认日志对象是否使用gzip压缩历史日志文件
+func SetRotateCompress(enabled bool) {
+    logger.SetRotateCompress(enabled)
+}
+
+// SetSampling enables sampling and rate limiting of logging contents for default logger,
+// see Logger.SetSampling.
+//
+// 设置默认日志对象的日志采样及限流：每秒内前first条日志全部输出，之后每thereafter条输出1条
+func SetSampling(first int, thereafter int, bySite...bool) {
+    logger.SetSampling(first, thereafter, bySite...)
+}
+
+// SetAsync enables the asynchronous logging mode for default logger, see Logger.SetAsync.
+//
+// 开启默认日志对象的异步日志写入模式，size <= 0时表示关闭异步模式
+func SetAsync(size int, policy...int) {
+    logger.SetAsync(size, policy...)
+}
+
+// Flush blocks until all the buffered logging contents of default logger are written in asynchronous mode.
+//
+// 阻塞等待默认日志对象异步缓冲区中的日志写入完成
+func Flush() {
+    logger.Flush()
+}
+
+// Close flushes the buffered logging contents and disables the asynchronous mode of default logger.
+//
+// 将默认日志对象异步缓冲区中的日志写入完成并关闭异步模式
+func Close() {
+    logger.Close()
+}
+
+// AddHook adds a <hook> for default logger, which receives every logging entry matching <level>.
+//
+// 添加默认日志对象的日志Hook，level参数指定该Hook接收的日志级别(默认LEVEL_ALL)
+func AddHook(hook Hook, level...int) {
+    logger.AddHook(hook, level...)
+}
+
+// AddWriter adds a <writer> for default logger, which receives the formatted logging content matching <level>.
+//
+// 添加默认日志对象额外的日志输出IO接口
+func AddWriter(writer io.Writer, level...int) {
+    logger.AddWriter(writer, level...)
+}
+
+// ClearHooks removes all hooks of default logger.
+//
+// 清空默认日志对象的日志Hook
+func ClearHooks() {
+    logger.ClearHooks()
+}
+
 // To is a chaining function, 
 // which redirects current logging content output to the sepecified <writer>.
 // 
@@ -189,6 +287,29 @@ func Header(enabled bool) *Logger {
     return logger.Header(enabled)
 }
 
+// Format is a chaining function,
+// which sets the output format for the current logging content output.
+//
+// 设置日志输出格式(FORMAT_TEXT/FORMAT_JSON)
+func Format(format int) *Logger {
+    return logger.Format(format)
+}
+
+// Fields is a chaining function,
+// which attaches key/value <fields> to the current logging content output.
+//
+// 设置日志附加的键值对字段
+func Fields(fields map[string]interface{}) *Logger {
+    return logger.Fields(fields)
+}
+
+// With returns a child logger of default logger with the key/value pairs <keyValues> attached to every logging content.
+//
+// 创建附加指定键值对字段的子Logger
+func With(keyValues...interface{}) *Logger {
+    return logger.With(keyValues...)
+}
+
 func Print(v ...interface{}) {
     logger.Print(v ...)
 }
@@ -0,0 +1,56 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build !windows,!nacl,!plan9
+
+package glog
+
+import (
+    "log/syslog"
+    "strings"
+)
+
+// SyslogHook is the hook which sends logging entries to syslog.
+//
+// 将日志内容发送到syslog的Hook
+type SyslogHook struct {
+    writer *syslog.Writer
+}
+
+// NewSyslogHook creates a syslog hook, using syslog.Dial(<network>, <raddr>, syslog.LOG_INFO, <tag>).
+// It connects to the local syslog server if <network> is empty.
+//
+// 创建syslog Hook，network为空时连接本地syslog服务
+func NewSyslogHook(network, raddr string, tag string) (*SyslogHook, error) {
+    writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO, tag)
+    if err != nil {
+        return nil, err
+    }
+    return &SyslogHook{writer}, nil
+}
+
+// Fire sends the logging entry to syslog with the severity mapped from its level.
+//
+// 按照日志级别对应的syslog严重等级发送日志内容
+func (h *SyslogHook) Fire(entry *Entry) error {
+    s := strings.TrimRight(entry.Content, "\r\n")
+    switch entry.LevelName {
+        case "DEBU": return h.writer.Debug(s)
+        case "NOTI": return h.writer.Notice(s)
+        case "WARN": return h.writer.Warning(s)
+        case "ERRO": return h.writer.Err(s)
+        case "CRIT", "FATA", "PANI":
+            return h.writer.Crit(s)
+    }
+    return h.writer.Info(s)
+}
+
+// Close closes the connection to syslog.
+//
+// 关闭syslog连接
+func (h *SyslogHook) Close() error {
+    return h.writer.Close()
+}
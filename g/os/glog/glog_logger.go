@@ -21,7 +21,6 @@ import (
     "runtime"
     "strings"
     "sync"
-    "time"
 )
 
 type Logger struct {
@@ -35,6 +34,16 @@ type Logger struct {
     btStatus     *gtype.Int          // 是否当打印错误时同时开启backtrace打印(默认-1，表示默认打印逻辑 - 错误才打印)
     printHeader  *gtype.Bool         // 是否不打印前缀信息(时间，级别等)
     alsoStdPrint *gtype.Bool         // 控制台打印开关，当输出到文件/自定义输出时也同时打印到终端
+    format       *gtype.Int          // 日志输出格式(FORMAT_TEXT/FORMAT_JSON)
+    rotateSize   *gtype.Int64        // 按照文件大小切分日志文件的大小阈值(字节)，0表示不切分
+    maxBackups   *gtype.Int          // 最多保留的历史日志文件数量，0表示不限制
+    maxAge       *gtype.Int64        // 历史日志文件最长保留时间(纳秒)，0表示不限制
+    compress     *gtype.Bool         // 是否使用gzip压缩历史日志文件
+    lastFile     *gtype.String       // 最近一次写入的日志文件路径，用于判断按照日期格式切分的日志文件是否发生变化
+    fields       []logField          // 每条日志附加的键值对字段
+    hooks        []logHook           // 日志Hook，用于将日志内容同时输出到其他目标
+    sampler      *gtype.Interface    // 日志采样器(*logSampler)，为nil表示不采样
+    async        *gtype.Interface    // 异步日志写入对象(*asyncWriter)，为nil表示同步写入
 }
 
 const (
@@ -71,6 +80,14 @@ func New() *Logger {
         btStatus     : gtype.NewInt(-1),
         printHeader  : gtype.NewBool(true),
         alsoStdPrint : gtype.NewBool(true),
+        format       : gtype.NewInt(FORMAT_TEXT),
+        rotateSize   : gtype.NewInt64(),
+        maxBackups   : gtype.NewInt(),
+        maxAge       : gtype.NewInt64(),
+        compress     : gtype.NewBool(),
+        lastFile     : gtype.NewString(),
+        sampler      : gtype.NewInterface(),
+        async        : gtype.NewInterface(),
     }
 }
 
@@ -85,9 +102,19 @@ func (l *Logger) Clone() *Logger {
         file         : l.file.Clone(),
         level        : l.level.Clone(),
         btSkip       : l.btSkip.Clone(),
-        btStatus    : l.btStatus.Clone(),
+        btStatus     : l.btStatus.Clone(),
         printHeader  : l.printHeader.Clone(),
         alsoStdPrint : l.alsoStdPrint.Clone(),
+        format       : l.format.Clone(),
+        fields       : l.getFields(),
+        hooks        : l.getHooks(),
+        sampler      : l.sampler.Clone(),
+        async        : l.async.Clone(),
+        rotateSize   : l.rotateSize.Clone(),
+        maxBackups   : l.maxBackups.Clone(),
+        maxAge       : l.maxAge.Clone(),
+        compress     : l.compress.Clone(),
+        lastFile     : gtype.NewString(),
     }
 }
 
@@ -155,30 +182,39 @@ func (l *Logger) GetWriter() io.Writer {
     return r
 }
 
-// getFilePointer returns the file pinter for file logging.
-// It returns nil if file logging disabled, or file open fails.
+// getFilePath returns the file path for file logging.
+// It returns empty string if file logging disabled, or the directory creation fails.
 //
-// 获取默认的文件IO.
-func (l *Logger) getFilePointer() *gfpool.File {
-    if path := l.path.Val(); path != "" {
-        // 文件名称中使用"{}"包含的内容使用gtime格式化
-        file, _ := gregex.ReplaceStringFunc(`{.+?}`, l.file.Val(), func(s string) string {
-            return gtime.Now().Format(strings.Trim(s, "{}"))
-        })
-        // 如果日志目录不存在则创建目录路径
-        if !gfile.Exists(path) {
-            if err := gfile.Mkdir(path); err != nil {
-                fmt.Fprintln(os.Stderr, fmt.Sprintf(`[glog] mkdir "%s" failed: %s`, path, err.Error()))
-                return nil
-            }
-        }
-        fpath   := path + gfile.Separator + file
-        if fp, err := gfpool.Open(fpath, gDEFAULT_FILE_POOL_FLAGS, gDEFAULT_FPOOL_PERM, gDEFAULT_FPOOL_EXPIRE); err == nil {
-            return fp
-        } else {
-            fmt.Fprintln(os.Stderr, err)
+// 获取当前的日志文件路径.
+func (l *Logger) getFilePath() string {
+    path := l.path.Val()
+    if path == "" {
+        return ""
+    }
+    // 文件名称中使用"{}"包含的内容使用gtime格式化
+    file, _ := gregex.ReplaceStringFunc(`{.+?}`, l.file.Val(), func(s string) string {
+        return gtime.Now().Format(strings.Trim(s, "{}"))
+    })
+    // 如果日志目录不存在则创建目录路径
+    if !gfile.Exists(path) {
+        if err := gfile.Mkdir(path); err != nil {
+            fmt.Fprintln(os.Stderr, fmt.Sprintf(`[glog] mkdir "%s" failed: %s`, path, err.Error()))
+            return ""
         }
     }
+    return path + gfile.Separator + file
+}
+
+// getFilePointer returns the file pinter of given <fpath> for file logging.
+// It returns nil if file open fails.
+//
+// 获取文件IO.
+func (l *Logger) getFilePointer(fpath string) *gfpool.File {
+    if fp, err := gfpool.Open(fpath, gDEFAULT_FILE_POOL_FLAGS, gDEFAULT_FPOOL_PERM, gDEFAULT_FPOOL_EXPIRE); err == nil {
+        return fp
+    } else {
+        fmt.Fprintln(os.Stderr, err)
+    }
     return nil
 }
 
@@ -226,24 +262,49 @@ func (l *Logger) SetStdPrint(enabled bool) {
 }
 
 // 这里的写锁保证统一时刻只会写入一行日志，防止串日志的情况
-func (l *Logger) print(std io.Writer, s string) {
-    // 优先使用自定义的IO输出
-    if l.printHeader.Val() {
-        s = l.format(s)
-    }
+func (l *Logger) print(std io.Writer, level string, msg string, backtrace string) {
+    s      := l.formatContent(level, msg, backtrace)
     writer := l.GetWriter()
+    fpath  := ""
+    if writer == nil {
+        fpath = l.getFilePath()
+    }
+    // 需要发送给额外的日志Hook的日志内容项
+    hooks := l.matchHooks(level)
+    entry := (*Entry)(nil)
+    if len(hooks) > 0 {
+        entry = l.newEntry(level, msg, backtrace, s)
+    }
+    if w := l.getAsync(); w != nil {
+        // 异步写入，FATA/PANI日志需要在进程退出前同步写入
+        w.put(func() {
+            l.output(std, writer, fpath, s)
+            fireHooks(hooks, entry)
+        }, level == "FATA" || level == "PANI")
+        return
+    }
+    l.output(std, writer, fpath, s)
+    fireHooks(hooks, entry)
+}
+
+// 将日志内容写入到自定义的writer，或者日志文件及标准输出
+func (l *Logger) output(std io.Writer, writer io.Writer, fpath string, s string) {
     if writer == nil {
         // 如果设置的writer为空，那么其次判断是否有文件输出设置
         // 内部使用了内存锁，保证在glog中对同一个日志文件的并发写入不会串日志(并发安全)
-        if f := l.getFilePointer(); f != nil {
-            defer f.Close()
+        if fpath != "" {
             key := l.path.Val()
             gmlock.Lock(key)
-            _, err := io.WriteString(f, s)
-            gmlock.Unlock(key)
-            if err != nil {
-                fmt.Fprintln(os.Stderr, err.Error())
+            // 按照文件大小切分日志文件
+            l.rotateBySize(fpath, len(s))
+            if f := l.getFilePointer(fpath); f != nil {
+                if _, err := io.WriteString(f, s); err != nil {
+                    fmt.Fprintln(os.Stderr, err.Error())
+                }
+                f.Close()
             }
+            gmlock.Unlock(key)
+            l.checkRotation(fpath)
         }
         // 当没有设置writer时，需要判断是否允许输出到标准输出
         if l.alsoStdPrint.Val() {
@@ -263,25 +324,36 @@ func (l *Logger) doStdLockPrint(std io.Writer, s string) {
     stdMu.Unlock()
 }
 
-// 核心打印数据方法(标准输出)
-func (l *Logger) stdPrint(s string) {
-    l.print(os.Stdout, s)
+// 核心打印数据方法(标准输出)，level为日志级别名称(例如: INFO)，为空表示不带级别
+func (l *Logger) stdPrint(level string, s string) {
+    if !l.sampled(level) {
+        return
+    }
+    l.print(os.Stdout, level, s, "")
 }
 
 // 核心打印数据方法(标准错误)
-func (l *Logger) errPrint(s string) {
+func (l *Logger) errPrint(level string, s string) {
+    if !l.sampled(level) {
+        return
+    }
     // 记录调用回溯信息
-    status := l.btStatus.Val()
+    backtrace := ""
+    status    := l.btStatus.Val()
     if status == -1 || status == 1 {
-        s = l.appendBacktrace(s)
+        backtrace = l.GetBacktrace()
     }
     // 防止串日志情况，这里不使用stderr，而是使用stdout
-    l.print(os.Stdout, s)
+    l.print(os.Stdout, level, s, backtrace)
 }
 
 // 输出内容中添加回溯信息
 func (l *Logger) appendBacktrace(s string, skip...int) string {
-    trace := l.GetBacktrace(skip...)
+    return joinBacktrace(s, l.GetBacktrace(skip...))
+}
+
+// 将回溯信息添加到输出内容末尾
+func joinBacktrace(s string, trace string) string {
     if trace != "" {
         backtrace := "Backtrace:" + ln + trace
         if len(s) > 0 {
@@ -320,7 +392,7 @@ func (l *Logger) GetBacktrace(skip...int) string {
     // 首先定位业务文件开始位置
     for i := 0; i < 10; i++ {
         if _, file, _, ok := runtime.Caller(i); ok {
-            if !gregex.IsMatchString("/g/os/glog/glog.+$", file) {
+            if !isGlogFile(file) {
                 from = i
                 break
             }
@@ -342,167 +414,163 @@ func (l *Logger) GetBacktrace(skip...int) string {
     return backtrace
 }
 
-func (l *Logger) format(s string) string {
-    return time.Now().Format("2006-01-02 15:04:05.000 ") + s
-}
-
 func (l *Logger) Print(v ...interface{}) {
-    l.stdPrint(fmt.Sprintln(v...))
+    l.stdPrint("", fmt.Sprintln(v...))
 }
 
 func (l *Logger) Printf(format string, v ...interface{}) {
-    l.stdPrint(fmt.Sprintf(format, v...))
+    l.stdPrint("", fmt.Sprintf(format, v...))
 }
 
 func (l *Logger) Println(v ...interface{}) {
-    l.stdPrint(fmt.Sprintln(v...))
+    l.stdPrint("", fmt.Sprintln(v...))
 }
 
 func (l *Logger) Printfln(format string, v ...interface{}) {
-    l.stdPrint(fmt.Sprintf(format + ln, v...))
+    l.stdPrint("", fmt.Sprintf(format + ln, v...))
 }
 
 // Fatal prints the logging content with [FATA] header and newline, then exit the current process.
 func (l *Logger) Fatal(v ...interface{}) {
-    l.errPrint("[FATA] " + fmt.Sprintln(v...))
+    l.errPrint("FATA", fmt.Sprintln(v...))
     os.Exit(1)
 }
 
 // Fatalf prints the logging content with [FATA] header and custom format, then exit the current process.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-    l.errPrint("[FATA] " + fmt.Sprintf(format, v...))
+    l.errPrint("FATA", fmt.Sprintf(format, v...))
     os.Exit(1)
 }
 
 // Fatalf prints the logging content with [FATA] header, custom format and newline, then exit the current process.
 func (l *Logger) Fatalfln(format string, v ...interface{}) {
-    l.errPrint("[FATA] " + fmt.Sprintf(format + ln, v...))
+    l.errPrint("FATA", fmt.Sprintf(format + ln, v...))
     os.Exit(1)
 }
 
 func (l *Logger) Panic(v ...interface{}) {
     s := fmt.Sprintln(v...)
-    l.errPrint("[PANI] " + s)
+    l.errPrint("PANI", s)
     panic(s)
 }
 
 func (l *Logger) Panicf(format string, v ...interface{}) {
     s := fmt.Sprintf(format, v...)
-    l.errPrint("[PANI] " + s)
+    l.errPrint("PANI", s)
     panic(s)
 }
 
 func (l *Logger) Panicfln(format string, v ...interface{}) {
     s := fmt.Sprintf(format + ln, v...)
-    l.errPrint("[PANI] " + s)
+    l.errPrint("PANI", s)
     panic(s)
 }
 
 func (l *Logger) Info(v ...interface{}) {
     if l.checkLevel(LEVEL_INFO) {
-        l.stdPrint("[INFO] " + fmt.Sprintln(v...))
+        l.stdPrint("INFO", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Infof(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_INFO) {
-        l.stdPrint("[INFO] " + fmt.Sprintf(format, v...))
+        l.stdPrint("INFO", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Infofln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_INFO) {
-        l.stdPrint("[INFO] " + fmt.Sprintf(format, v...) + ln)
+        l.stdPrint("INFO", fmt.Sprintf(format, v...) + ln)
     }
 }
 
 func (l *Logger) Debug(v ...interface{}) {
     if l.checkLevel(LEVEL_DEBU) {
-        l.stdPrint("[DEBU] " + fmt.Sprintln(v...))
+        l.stdPrint("DEBU", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Debugf(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_DEBU) {
-        l.stdPrint("[DEBU] " + fmt.Sprintf(format, v...))
+        l.stdPrint("DEBU", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Debugfln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_DEBU) {
-        l.stdPrint("[DEBU] " + fmt.Sprintf(format, v...) + ln)
+        l.stdPrint("DEBU", fmt.Sprintf(format, v...) + ln)
     }
 }
 
 func (l *Logger) Notice(v ...interface{}) {
     if l.checkLevel(LEVEL_NOTI) {
-        l.errPrint("[NOTI] " + fmt.Sprintln(v...))
+        l.errPrint("NOTI", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Noticef(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_NOTI) {
-        l.errPrint("[NOTI] " + fmt.Sprintf(format, v...))
+        l.errPrint("NOTI", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Noticefln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_NOTI) {
-        l.errPrint("[NOTI] " + fmt.Sprintf(format, v...) + ln)
+        l.errPrint("NOTI", fmt.Sprintf(format, v...) + ln)
     }
 }
 
 func (l *Logger) Warning(v ...interface{}) {
     if l.checkLevel(LEVEL_WARN) {
-        l.errPrint("[WARN] " + fmt.Sprintln(v...))
+        l.errPrint("WARN", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Warningf(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_WARN) {
-        l.errPrint("[WARN] " + fmt.Sprintf(format, v...))
+        l.errPrint("WARN", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Warningfln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_WARN) {
-        l.errPrint("[WARN] " + fmt.Sprintf(format, v...) + ln)
+        l.errPrint("WARN", fmt.Sprintf(format, v...) + ln)
     }
 }
 
 func (l *Logger) Error(v ...interface{}) {
     if l.checkLevel(LEVEL_ERRO) {
-        l.errPrint("[ERRO] " + fmt.Sprintln(v...))
+        l.errPrint("ERRO", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_ERRO) {
-        l.errPrint("[ERRO] " + fmt.Sprintf(format, v...))
+        l.errPrint("ERRO", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Errorfln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_ERRO) {
-        l.errPrint("[ERRO] " + fmt.Sprintf(format, v...) + ln)
+        l.errPrint("ERRO", fmt.Sprintf(format, v...) + ln)
     }
 }
 
 func (l *Logger) Critical(v ...interface{}) {
     if l.checkLevel(LEVEL_CRIT) {
-        l.errPrint("[CRIT] " + fmt.Sprintln(v...))
+        l.errPrint("CRIT", fmt.Sprintln(v...))
     }
 }
 
 func (l *Logger) Criticalf(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_CRIT) {
-        l.errPrint("[CRIT] " + fmt.Sprintf(format, v...))
+        l.errPrint("CRIT", fmt.Sprintf(format, v...))
     }
 }
 
 func (l *Logger) Criticalfln(format string, v ...interface{}) {
     if l.checkLevel(LEVEL_CRIT) {
-        l.errPrint("[CRIT] " + fmt.Sprintf(format, v...) + ln)
+        l.errPrint("CRIT", fmt.Sprintf(format, v...) + ln)
     }
 }
 
@@ -0,0 +1,166 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+    "github.com/gogf/gf/g/container/gtype"
+    "sync"
+)
+
+const (
+    ASYNC_BLOCK = 0 // 异步缓冲区已满时的处理策略：阻塞等待缓冲区可写(默认)
+    ASYNC_DROP  = 1 // 异步缓冲区已满时的处理策略：丢弃当前日志
+)
+
+// 异步日志写入对象，日志写入操作通过有界缓冲区交给后台goroutine顺序执行
+type asyncWriter struct {
+    mu      sync.RWMutex   // 缓冲区关闭锁，保证关闭后不再写入缓冲区
+    cond    *sync.Cond     // 等待缓冲区日志写入完成的条件变量
+    queue   chan func()    // 日志写入缓冲区
+    policy  int            // 缓冲区已满时的处理策略
+    pending int            // 尚未写入完成的日志条数(使用cond.L保护)
+    closed  bool           // 是否已关闭(使用mu保护)
+    dropped *gtype.Int64   // 因缓冲区已满被丢弃的日志条数
+}
+
+// SetAsync enables the asynchronous logging mode with a buffer of <size> logging contents,
+// which are written to file/stdout/hooks by a background goroutine in order.
+// The optional parameter <policy> specifies the policy when the buffer is full, ASYNC_BLOCK(default) or ASYNC_DROP.
+// It disables the asynchronous mode if <size> <= 0. The buffered logging contents are flushed before
+// the previous asynchronous mode is replaced or disabled. Fatal and panic logging contents are always
+// written synchronously after the buffer is flushed.
+//
+// 开启异步日志写入模式，size为缓冲区大小(日志条数)，日志内容由后台goroutine按顺序写入文件/终端/Hook，
+// 可选参数policy为缓冲区已满时的处理策略：ASYNC_BLOCK(默认，阻塞等待)或者ASYNC_DROP(丢弃日志)。
+// size <= 0时表示关闭异步模式。替换或者关闭异步模式前会将缓冲区中的日志写入完成。
+// FATA/PANI日志会在缓冲区写入完成后同步写入，保证进程退出前日志不丢失。
+// 通过链式操作或者With创建的Logger与当前Logger共享异步缓冲区。
+func (l *Logger) SetAsync(size int, policy...int) {
+    w := (*asyncWriter)(nil)
+    if size > 0 {
+        w = &asyncWriter {
+            queue   : make(chan func(), size),
+            cond    : sync.NewCond(&sync.Mutex{}),
+            dropped : gtype.NewInt64(),
+        }
+        if len(policy) > 0 {
+            w.policy = policy[0]
+        }
+        go w.loop()
+    }
+    if old, ok := l.async.Val().(*asyncWriter); ok && old != nil {
+        l.async.Set(w)
+        old.close()
+    } else {
+        l.async.Set(w)
+    }
+}
+
+// IsAsync checks and returns whether the asynchronous logging mode is enabled.
+//
+// 判断是否开启了异步日志写入模式
+func (l *Logger) IsAsync() bool {
+    return l.getAsync() != nil
+}
+
+// GetAsyncDropped returns the count of logging contents dropped because the buffer is full.
+//
+// 获取异步模式下因缓冲区已满(ASYNC_DROP策略)被丢弃的日志条数
+func (l *Logger) GetAsyncDropped() int64 {
+    if w := l.getAsync(); w != nil {
+        return w.dropped.Val()
+    }
+    return 0
+}
+
+// Flush blocks until all the buffered logging contents are written in asynchronous mode.
+//
+// 阻塞等待异步缓冲区中的日志写入完成
+func (l *Logger) Flush() {
+    if w := l.getAsync(); w != nil {
+        w.flush()
+    }
+}
+
+// Close flushes the buffered logging contents and disables the asynchronous mode,
+// which should be called before the process exits for graceful shutdown.
+//
+// 将异步缓冲区中的日志写入完成并关闭异步模式，用于进程退出前的平滑关闭
+func (l *Logger) Close() {
+    l.SetAsync(0)
+}
+
+// 获取异步日志写入对象，未开启异步模式时返回nil
+func (l *Logger) getAsync() *asyncWriter {
+    if w, ok := l.async.Val().(*asyncWriter); ok {
+        return w
+    }
+    return nil
+}
+
+// 添加日志写入操作到缓冲区，direct为true或者已关闭时等待缓冲区写入完成后同步执行
+func (w *asyncWriter) put(f func(), direct bool) {
+    w.mu.RLock()
+    if w.closed || direct {
+        w.mu.RUnlock()
+        w.flush()
+        f()
+        return
+    }
+    w.cond.L.Lock()
+    w.pending++
+    w.cond.L.Unlock()
+    if w.policy == ASYNC_DROP {
+        select {
+            case w.queue <- f:
+            default:
+                w.dropped.Add(1)
+                w.done()
+        }
+    } else {
+        w.queue <- f
+    }
+    w.mu.RUnlock()
+}
+
+// 后台goroutine，按顺序执行缓冲区中的日志写入操作，缓冲区关闭后退出
+func (w *asyncWriter) loop() {
+    for f := range w.queue {
+        f()
+        w.done()
+    }
+}
+
+// 日志写入完成
+func (w *asyncWriter) done() {
+    w.cond.L.Lock()
+    w.pending--
+    if w.pending == 0 {
+        w.cond.Broadcast()
+    }
+    w.cond.L.Unlock()
+}
+
+// 阻塞等待缓冲区日志写入完成
+func (w *asyncWriter) flush() {
+    w.cond.L.Lock()
+    for w.pending > 0 {
+        w.cond.Wait()
+    }
+    w.cond.L.Unlock()
+}
+
+// 关闭缓冲区，等待缓冲区日志写入完成
+func (w *asyncWriter) close() {
+    w.mu.Lock()
+    if !w.closed {
+        w.closed = true
+        close(w.queue)
+    }
+    w.mu.Unlock()
+    w.flush()
+}
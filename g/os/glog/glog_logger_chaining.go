@@ -7,6 +7,7 @@
 package glog
 
 import (
+    "fmt"
     "github.com/gogf/gf/g/os/gfile"
     "io"
 )
@@ -121,4 +122,54 @@ func (l *Logger) Header(enabled bool) *Logger {
     }
     logger.printHeader.Set(enabled)
     return logger
-}
\ No newline at end of file
+}
+// Format is a chaining function,
+// which sets the output format for the current logging content output.
+//
+// 设置日志输出格式(FORMAT_TEXT/FORMAT_JSON)
+func (l *Logger) Format(format int) *Logger {
+    logger := (*Logger)(nil)
+    if l.pr == nil {
+        logger = l.Clone()
+    } else {
+        logger = l
+    }
+    logger.SetFormat(format)
+    return logger
+}
+
+// Fields is a chaining function,
+// which attaches key/value <fields> to the current logging content output.
+//
+// 设置日志附加的键值对字段
+func (l *Logger) Fields(fields map[string]interface{}) *Logger {
+    logger := (*Logger)(nil)
+    if l.pr == nil {
+        logger = l.Clone()
+    } else {
+        logger = l
+    }
+    logger.SetFields(fields)
+    return logger
+}
+
+// With returns a child logger with the key/value pairs <keyValues> attached to every logging content,
+// eg: logger.With("request_id", id, "user", uid).
+// Unlike other chaining functions, the returned logger can be saved and reused,
+// and the chaining functions(eg: Cat/File) on it return new loggers inheriting its fields.
+//
+// 创建附加指定键值对字段的子Logger，参数为键值对列表，缺少值的键使用nil值。
+// 与其他链式操作不同，返回的子Logger可以保存并重复使用(例如在中间件中为每个请求创建)，
+// 在子Logger上的链式操作(例如Cat/File)将会返回继承其字段的新Logger，不会修改子Logger本身。
+func (l *Logger) With(keyValues...interface{}) *Logger {
+    logger   := l.Clone()
+    logger.pr = nil
+    for i := 0; i < len(keyValues); i += 2 {
+        var value interface{}
+        if i + 1 < len(keyValues) {
+            value = keyValues[i + 1]
+        }
+        logger.fields = mergeField(logger.fields, fmt.Sprintf("%v", keyValues[i]), value)
+    }
+    return logger
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "runtime"
+    "sort"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/text/gregex"
+)
+
+// 日志键值对字段
+type logField struct {
+    key   string
+    value interface{}
+}
+
+var (
+    // JSON格式中的日志级别名称
+    jsonLevelNames = map[string]string {
+        "DEBU" : "debug",
+        "INFO" : "info",
+        "NOTI" : "notice",
+        "WARN" : "warning",
+        "ERRO" : "error",
+        "CRIT" : "critical",
+        "FATA" : "fatal",
+        "PANI" : "panic",
+    }
+    // JSON格式中的保留键名，与之冲突的字段键名将添加"fields."前缀
+    jsonReservedKeys = map[string]struct{} {
+        "time"      : {},
+        "level"     : {},
+        "caller"    : {},
+        "msg"       : {},
+        "backtrace" : {},
+    }
+)
+
+// SetFormat sets the output format of logging content, FORMAT_TEXT or FORMAT_JSON.
+//
+// 设置日志输出格式，FORMAT_JSON格式下每条日志输出为一行JSON对象，
+// 包含time、level、caller、msg以及附加的键值对字段，错误日志的回溯信息使用backtrace字段。
+func (l *Logger) SetFormat(format int) {
+    l.format.Set(format)
+}
+
+// GetFormat returns the output format of logging content.
+//
+// 获取日志输出格式
+func (l *Logger) GetFormat() int {
+    return l.format.Val()
+}
+
+// SetFields sets the key/value fields which are attached to every logging content.
+// The fields are merged into existing fields, and the keys are sorted for output.
+//
+// 设置每条日志附加的键值对字段，与已有字段合并(相同键名覆盖)
+func (l *Logger) SetFields(fields map[string]interface{}) {
+    keys := make([]string, 0, len(fields))
+    for k := range fields {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    for _, k := range keys {
+        l.fields = mergeField(l.fields, k, fields[k])
+    }
+}
+
+// 获取附加的键值对字段(拷贝)
+func (l *Logger) getFields() []logField {
+    l.mu.RLock()
+    defer l.mu.RUnlock()
+    if len(l.fields) == 0 {
+        return nil
+    }
+    fields := make([]logField, len(l.fields))
+    copy(fields, l.fields)
+    return fields
+}
+
+// 合并键值对字段，相同键名覆盖原有的值，否则添加到末尾
+func mergeField(fields []logField, key string, value interface{}) []logField {
+    for i, f := range fields {
+        if f.key == key {
+            fields[i].value = value
+            return fields
+        }
+    }
+    return append(fields, logField{key, value})
+}
+
+// 按照日志输出格式生成日志内容
+func (l *Logger) formatContent(level string, s string, backtrace string) string {
+    fields := l.getFields()
+    if l.format.Val() == FORMAT_JSON {
+        return l.formatJson(level, s, backtrace, fields)
+    }
+    if len(fields) > 0 {
+        content := strings.TrimRight(s, "\r\n")
+        for _, f := range fields {
+            content += fmt.Sprintf(" %s=%v", f.key, f.value)
+        }
+        s = content + s[len(strings.TrimRight(s, "\r\n")) : ]
+    }
+    if level != "" {
+        s = "[" + level + "] " + s
+    }
+    if l.printHeader.Val() {
+        s = time.Now().Format("2006-01-02 15:04:05.000 ") + s
+    }
+    return joinBacktrace(s, backtrace)
+}
+
+// 生成JSON格式的日志内容
+func (l *Logger) formatJson(level string, s string, backtrace string, fields []logField) string {
+    buffer := bytes.NewBuffer(nil)
+    buffer.WriteByte('{')
+    writeJsonField(buffer, "time", time.Now().Format("2006-01-02T15:04:05.000Z07:00"), true)
+    if level != "" {
+        name, ok := jsonLevelNames[level]
+        if !ok {
+            name = strings.ToLower(level)
+        }
+        writeJsonField(buffer, "level", name, false)
+    }
+    if caller := l.getCaller(); caller != "" {
+        writeJsonField(buffer, "caller", caller, false)
+    }
+    writeJsonField(buffer, "msg", strings.TrimRight(s, "\r\n"), false)
+    for _, f := range fields {
+        key := f.key
+        if _, ok := jsonReservedKeys[key]; ok {
+            key = "fields." + key
+        }
+        writeJsonField(buffer, key, f.value, false)
+    }
+    if backtrace != "" {
+        writeJsonField(buffer, "backtrace", strings.TrimRight(backtrace, "\r\n"), false)
+    }
+    buffer.WriteByte('}')
+    buffer.WriteString(ln)
+    return buffer.String()
+}
+
+// 写入JSON键值对，无法JSON编码的值使用字符串形式
+func writeJsonField(buffer *bytes.Buffer, key string, value interface{}, first bool) {
+    if !first {
+        buffer.WriteByte(',')
+    }
+    k, _ := json.Marshal(key)
+    buffer.Write(k)
+    buffer.WriteByte(':')
+    if err, ok := value.(error); ok {
+        value = err.Error()
+    }
+    v, err := json.Marshal(value)
+    if err != nil {
+        v, _ = json.Marshal(fmt.Sprintf("%v", value))
+    }
+    buffer.Write(v)
+}
+
+// 获取日志调用方的文件及行号(从glog包之外的第一个调用开始，并跳过backtrace skip条数)，格式: file:line
+func (l *Logger) getCaller() string {
+    for i := 0; i < 20; i++ {
+        _, file, _, ok := runtime.Caller(i)
+        if !ok {
+            break
+        }
+        if !isGlogFile(file) {
+            if _, file, line, ok := runtime.Caller(i + l.btSkip.Val()); ok {
+                return fmt.Sprintf("%s:%d", file, line)
+            }
+            break
+        }
+    }
+    return ""
+}
+
+// 判断文件是否为glog包的源码文件(不包含单元测试文件)
+func isGlogFile(file string) bool {
+    return gregex.IsMatchString("/g/os/glog/glog.+$", file) && !strings.HasSuffix(file, "_test.go")
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "time"
+)
+
+// Entry is the logging entry passed to hooks.
+//
+// 日志内容项，用于传递给Hook
+type Entry struct {
+    Time      time.Time              // 日志时间
+    Level     int                    // 日志级别(LEVEL_*)，Print系列方法输出的日志为0，Fatal/Panic为LEVEL_CRIT
+    LevelName string                 // 日志级别名称(例如: INFO)，Print系列方法输出的日志为空
+    Message   string                 // 日志内容(不包含换行符)
+    Fields    map[string]interface{} // 附加的键值对字段
+    Caller    string                 // 日志调用方文件及行号，格式: file:line
+    Backtrace string                 // 回溯信息
+    Content   string                 // 按照日志输出格式生成的完整日志内容
+}
+
+// Hook is the interface for logging sinks, eg: syslog, kafka, http collector, etc.
+//
+// 日志Hook接口，用于将日志内容同时输出到其他目标(例如: syslog、kafka、http收集服务等)
+type Hook interface {
+    Fire(entry *Entry) error
+}
+
+// HookFunc is the function adapter for Hook.
+//
+// Hook接口的方法适配类型
+type HookFunc func(entry *Entry) error
+
+// 日志Hook项
+type logHook struct {
+    hook  Hook // Hook对象
+    level int  // 该Hook接收的日志级别
+}
+
+// 写入IO接口的Hook
+type writerHook struct {
+    writer io.Writer
+}
+
+var (
+    // 日志级别名称对应的级别
+    levelValues = map[string]int {
+        "DEBU" : LEVEL_DEBU,
+        "INFO" : LEVEL_INFO,
+        "NOTI" : LEVEL_NOTI,
+        "WARN" : LEVEL_WARN,
+        "ERRO" : LEVEL_ERRO,
+        "CRIT" : LEVEL_CRIT,
+        "FATA" : LEVEL_CRIT,
+        "PANI" : LEVEL_CRIT,
+    }
+)
+
+// Fire calls f(entry).
+func (f HookFunc) Fire(entry *Entry) error {
+    return f(entry)
+}
+
+// 写入格式化后的日志内容
+func (h *writerHook) Fire(entry *Entry) error {
+    _, err := io.WriteString(h.writer, entry.Content)
+    return err
+}
+
+// AddHook adds a <hook> which receives every logging entry matching <level>, in addition to file/stdout.
+// The default <level> is LEVEL_ALL. Logging entries without level (Print*) are sent to all hooks.
+//
+// 添加日志Hook，level参数指定该Hook接收的日志级别(默认LEVEL_ALL)，
+// Print系列方法输出的无级别日志将会发送给所有的Hook。Hook不影响原有的文件/终端输出。
+func (l *Logger) AddHook(hook Hook, level...int) {
+    mask := LEVEL_ALL
+    if len(level) > 0 {
+        mask = level[0]
+    }
+    l.mu.Lock()
+    l.hooks = append(l.hooks, logHook{hook, mask})
+    l.mu.Unlock()
+}
+
+// AddWriter adds a <writer> which receives the formatted logging content matching <level>,
+// in addition to file/stdout.
+//
+// 添加日志输出IO接口，与SetWriter不同的是，该IO接口作为额外的输出目标，不影响原有的文件/终端输出
+func (l *Logger) AddWriter(writer io.Writer, level...int) {
+    l.AddHook(&writerHook{writer}, level...)
+}
+
+// ClearHooks removes all hooks of the logger.
+//
+// 清空日志Hook
+func (l *Logger) ClearHooks() {
+    l.mu.Lock()
+    l.hooks = nil
+    l.mu.Unlock()
+}
+
+// 获取日志Hook(拷贝)
+func (l *Logger) getHooks() []logHook {
+    l.mu.RLock()
+    defer l.mu.RUnlock()
+    if len(l.hooks) == 0 {
+        return nil
+    }
+    hooks := make([]logHook, len(l.hooks))
+    copy(hooks, l.hooks)
+    return hooks
+}
+
+// 获取接收指定级别日志的Hook
+func (l *Logger) matchHooks(level string) []logHook {
+    hooks := l.getHooks()
+    if len(hooks) == 0 {
+        return nil
+    }
+    value   := levelValues[level]
+    matched := hooks[ : 0]
+    for _, h := range hooks {
+        if value == 0 || h.level & value > 0 {
+            matched = append(matched, h)
+        }
+    }
+    return matched
+}
+
+// 生成发送给Hook的日志内容项
+func (l *Logger) newEntry(level string, s string, backtrace string, content string) *Entry {
+    entry := &Entry {
+        Time      : time.Now(),
+        Level     : levelValues[level],
+        LevelName : level,
+        Message   : strings.TrimRight(s, "\r\n"),
+        Fields    : make(map[string]interface{}),
+        Caller    : l.getCaller(),
+        Backtrace : backtrace,
+        Content   : content,
+    }
+    for _, f := range l.getFields() {
+        entry.Fields[f.key] = f.value
+    }
+    return entry
+}
+
+// 将日志内容发送给Hook，Hook返回的错误输出到标准错误
+func fireHooks(hooks []logHook, entry *Entry) {
+    for _, h := range hooks {
+        if err := h.hook.Fire(entry); err != nil {
+            fmt.Fprintln(os.Stderr, err.Error())
+        }
+    }
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+    "compress/gzip"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gmlock"
+)
+
+const (
+    gROTATE_TIME_FORMAT   = "20060102150405.000000"           // 按照文件大小切分时历史日志文件名称中的时间格式
+    gROTATE_TIME_PATTERN  = `\.\d{14}\.\d{6}`                  // 历史日志文件名称中的时间格式匹配规则
+    gROTATE_LOCK_PREFIX   = "glog.rotate:"                     // 清理历史日志文件时的内存锁键名前缀
+    gCOMPRESS_FILE_SUFFIX = ".gz"                              // 压缩后的历史日志文件后缀
+)
+
+// SetRotateSize sets the file size in bytes for rotating the logging file, 0 means no size-based rotation.
+// The rotated file is renamed with a datetime suffix before its extension, eg: 2019-01-01.20190101150405.000001.log.
+//
+// 设置按照文件大小切分日志文件的大小阈值(字节)，日志文件写入后超过该大小时将被重命名为历史日志文件，0表示不切分。
+func (l *Logger) SetRotateSize(size int64) {
+    l.rotateSize.Set(size)
+}
+
+// SetRotateBackups sets the max number of rotated logging files to keep, 0 means no limit.
+//
+// 设置最多保留的历史日志文件数量(包括按照日期格式切分以及按照文件大小切分的日志文件)，0表示不限制。
+func (l *Logger) SetRotateBackups(backups int) {
+    l.maxBackups.Set(backups)
+}
+
+// SetRotateAge sets the max age of rotated logging files to keep, 0 means no limit.
+//
+// 设置历史日志文件的最长保留时间(按照文件修改时间计算)，0表示不限制。
+func (l *Logger) SetRotateAge(age time.Duration) {
+    l.maxAge.Set(int64(age))
+}
+
+// SetRotateCompress enables/disables the gzip compression of rotated logging files.
+//
+// 设置是否使用gzip压缩历史日志文件，压缩后的文件名称添加.gz后缀。
+func (l *Logger) SetRotateCompress(enabled bool) {
+    l.compress.Set(enabled)
+}
+
+// 日志文件超过大小阈值时重命名为历史日志文件，需要在日志目录的内存锁中调用
+func (l *Logger) rotateBySize(fpath string, length int) {
+    size := l.rotateSize.Val()
+    if size <= 0 {
+        return
+    }
+    stat, err := os.Stat(fpath)
+    if err != nil || stat.Size() == 0 || stat.Size() + int64(length) <= size {
+        return
+    }
+    ext    := filepath.Ext(fpath)
+    backup := strings.TrimSuffix(fpath, ext) + "." + time.Now().Format(gROTATE_TIME_FORMAT) + ext
+    if err := os.Rename(fpath, backup); err != nil {
+        fmt.Fprintln(os.Stderr, fmt.Sprintf(`[glog] rotate "%s" failed: %s`, fpath, err.Error()))
+        return
+    }
+    // 保证切分后检查历史日志文件
+    l.lastFile.Set("")
+}
+
+// 日志文件发生变化(按照日期格式切分或者按照文件大小切分)时，异步压缩以及清理历史日志文件
+func (l *Logger) checkRotation(fpath string) {
+    if l.lastFile.Set(fpath) == fpath {
+        return
+    }
+    if !l.compress.Val() && l.maxBackups.Val() <= 0 && l.maxAge.Val() <= 0 {
+        return
+    }
+    go l.cleanBackups(fpath)
+}
+
+// 压缩以及清理历史日志文件，fpath为当前正在写入的日志文件
+func (l *Logger) cleanBackups(fpath string) {
+    dir := filepath.Dir(fpath)
+    gmlock.Lock(gROTATE_LOCK_PREFIX + dir)
+    defer gmlock.Unlock(gROTATE_LOCK_PREFIX + dir)
+    pattern := l.backupPattern()
+    names, _ := gfile.DirNames(dir)
+    backups  := make([]os.FileInfo, 0)
+    for _, name := range names {
+        path := dir + gfile.Separator + name
+        if path == fpath || !pattern.MatchString(name) {
+            continue
+        }
+        // 压缩历史日志文件
+        if l.compress.Val() && !strings.HasSuffix(name, gCOMPRESS_FILE_SUFFIX) {
+            if err := compressFile(path); err != nil {
+                fmt.Fprintln(os.Stderr, fmt.Sprintf(`[glog] compress "%s" failed: %s`, path, err.Error()))
+            } else {
+                path += gCOMPRESS_FILE_SUFFIX
+            }
+        }
+        if stat, err := os.Stat(path); err == nil && !stat.IsDir() {
+            backups = append(backups, &backupFileInfo{stat, path})
+        }
+    }
+    // 按照修改时间从新到旧排序
+    sort.Slice(backups, func(i, j int) bool {
+        return backups[i].ModTime().After(backups[j].ModTime())
+    })
+    maxBackups := l.maxBackups.Val()
+    maxAge     := time.Duration(l.maxAge.Val())
+    for i, stat := range backups {
+        if (maxBackups > 0 && i >= maxBackups) || (maxAge > 0 && time.Since(stat.ModTime()) > maxAge) {
+            if err := os.Remove(stat.(*backupFileInfo).path); err != nil {
+                fmt.Fprintln(os.Stderr, err.Error())
+            }
+        }
+    }
+}
+
+// 历史日志文件信息
+type backupFileInfo struct {
+    os.FileInfo
+    path string
+}
+
+// 获取历史日志文件的名称匹配规则，文件名称格式中"{}"包含的内容匹配任意字符
+func (l *Logger) backupPattern() *regexp.Regexp {
+    file  := l.file.Val()
+    ext   := filepath.Ext(file)
+    if strings.Contains(ext, "}") {
+        ext = ""
+    }
+    parts := regexp.MustCompile(`{.+?}`).Split(strings.TrimSuffix(file, ext), -1)
+    for i, part := range parts {
+        parts[i] = regexp.QuoteMeta(part)
+    }
+    return regexp.MustCompile(fmt.Sprintf(
+        `^%s(%s)?%s(%s)?$`,
+        strings.Join(parts, ".+?"), gROTATE_TIME_PATTERN, regexp.QuoteMeta(ext), regexp.QuoteMeta(gCOMPRESS_FILE_SUFFIX),
+    ))
+}
+
+// 使用gzip压缩文件，压缩成功后删除原文件
+func compressFile(path string) error {
+    src, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer src.Close()
+    dst, err := os.OpenFile(path + gCOMPRESS_FILE_SUFFIX, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, gDEFAULT_FPOOL_PERM)
+    if err != nil {
+        return err
+    }
+    writer := gzip.NewWriter(dst)
+    if _, err = io.Copy(writer, src); err == nil {
+        err = writer.Close()
+    }
+    if e := dst.Close(); err == nil {
+        err = e
+    }
+    if err != nil {
+        os.Remove(path + gCOMPRESS_FILE_SUFFIX)
+        return err
+    }
+    src.Close()
+    return os.Remove(path)
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+    "sync"
+    "time"
+)
+
+// 日志采样器，每秒内同一采样键的前first条日志全部输出，之后每thereafter条输出1条
+type logSampler struct {
+    mu         sync.Mutex
+    first      int            // 每秒全部输出的日志条数
+    thereafter int            // 超过first条之后每多少条输出1条，0表示不再输出
+    bySite     bool           // 是否按照调用位置(file:line)分别采样
+    second     int64          // 当前计数的时间(秒)
+    counts     map[string]int // 当前秒内各采样键的日志条数
+    dropped    int64          // 被丢弃的日志总条数
+}
+
+// SetSampling enables sampling and rate limiting of logging contents:
+// in every second, the first <first> logging contents are written,
+// and then only every <thereafter>th logging content is written (none if <thereafter> is 0).
+// The counting is per logger in default, or per call site(file:line) if <bySite> is true.
+// Sampling is disabled if <first> is negative. Fatal and panic logging contents are never dropped.
+//
+// 设置日志采样及限流：每秒内前first条日志全部输出，之后每thereafter条输出1条(thereafter为0时不再输出)，
+// 默认对整个Logger计数，bySite为true时按照日志调用位置(file:line)分别计数，防止热点错误日志写满磁盘或者压垮远程日志服务。
+// first小于0时表示关闭采样。FATA/PANI日志不会被丢弃。
+// 通过链式操作或者With创建的Logger与当前Logger共享采样计数。
+func (l *Logger) SetSampling(first int, thereafter int, bySite...bool) {
+    if first < 0 {
+        l.sampler.Set((*logSampler)(nil))
+        return
+    }
+    if thereafter < 0 {
+        thereafter = 0
+    }
+    l.sampler.Set(&logSampler {
+        first      : first,
+        thereafter : thereafter,
+        bySite     : len(bySite) > 0 && bySite[0],
+        counts     : make(map[string]int),
+    })
+}
+
+// GetSampledOut returns the count of logging contents dropped by sampling.
+//
+// 获取被采样丢弃的日志总条数
+func (l *Logger) GetSampledOut() int64 {
+    if s, ok := l.sampler.Val().(*logSampler); ok && s != nil {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        return s.dropped
+    }
+    return 0
+}
+
+// 判断当前日志是否允许输出(根据采样设置)
+func (l *Logger) sampled(level string) bool {
+    s, ok := l.sampler.Val().(*logSampler)
+    if !ok || s == nil || level == "FATA" || level == "PANI" {
+        return true
+    }
+    key := ""
+    if s.bySite {
+        key = l.getCaller()
+    }
+    return s.allow(key, time.Now().Unix())
+}
+
+// 采样计数，返回是否允许输出
+func (s *logSampler) allow(key string, second int64) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if second != s.second {
+        s.second = second
+        s.counts = make(map[string]int)
+    }
+    s.counts[key]++
+    n := s.counts[key]
+    if n <= s.first || (s.thereafter > 0 && (n - s.first) % s.thereafter == 0) {
+        return true
+    }
+    s.dropped++
+    return false
+}
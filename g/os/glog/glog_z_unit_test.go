@@ -0,0 +1,241 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package glog_test
+
+import (
+    "bytes"
+    "encoding/json"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/glog"
+    "github.com/gogf/gf/g/test/gtest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestLogger_Json(t *testing.T) {
+    gtest.Case(t, func() {
+        buffer := bytes.NewBuffer(nil)
+        logger := glog.New()
+        logger.SetWriter(buffer)
+        logger.SetFormat(glog.FORMAT_JSON)
+        gtest.Assert(logger.GetFormat(), glog.FORMAT_JSON)
+        logger.Fields(map[string]interface{}{"user" : 1, "msg" : "conflict"}).Infofln("hello %s", "world")
+        m := make(map[string]interface{})
+        gtest.Assert(json.Unmarshal(buffer.Bytes(), &m), nil)
+        gtest.Assert(m["level"], "info")
+        gtest.Assert(m["msg"], "hello world")
+        gtest.Assert(m["user"], 1)
+        gtest.Assert(m["fields.msg"], "conflict")
+        gtest.Assert(m["time"] != nil, true)
+        gtest.Assert(strings.Contains(m["caller"].(string), "glog_z_unit_test.go"), true)
+        gtest.Assert(m["backtrace"], nil)
+        // 错误日志包含回溯信息，每条日志一行
+        buffer.Reset()
+        logger.Error("error")
+        logger.Print("print")
+        lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+        gtest.Assert(len(lines), 2)
+        m = make(map[string]interface{})
+        gtest.Assert(json.Unmarshal([]byte(lines[0]), &m), nil)
+        gtest.Assert(m["level"], "error")
+        gtest.Assert(m["backtrace"] != nil, true)
+        m = make(map[string]interface{})
+        gtest.Assert(json.Unmarshal([]byte(lines[1]), &m), nil)
+        gtest.Assert(m["level"], nil)
+        gtest.Assert(m["msg"], "print")
+    })
+}
+
+func TestLogger_TextFields(t *testing.T) {
+    gtest.Case(t, func() {
+        buffer := bytes.NewBuffer(nil)
+        logger := glog.New()
+        logger.SetWriter(buffer)
+        logger.Header(false).Fields(map[string]interface{}{"b" : 2, "a" : "x"}).Info("hello")
+        gtest.Assert(buffer.String(), "[INFO] hello a=x b=2\n")
+    })
+}
+
+func TestLogger_Rotate(t *testing.T) {
+    path := gfile.TempDir() + gfile.Separator + "glog_rotate_test"
+    defer gfile.Remove(path)
+    gtest.Case(t, func() {
+        logger := glog.New()
+        gtest.Assert(logger.SetPath(path), nil)
+        logger.SetFile("app-{Ymd}.log")
+        logger.SetStdPrint(false)
+        logger.SetRotateSize(100)
+        logger.SetRotateBackups(2)
+        logger.SetRotateCompress(true)
+        for i := 0; i < 5; i++ {
+            logger.Println(strings.Repeat("x", 60))
+            time.Sleep(10*time.Millisecond)
+        }
+        time.Sleep(200*time.Millisecond)
+        names, err := gfile.DirNames(path)
+        gtest.Assert(err, nil)
+        current := "app-" + time.Now().Format("20060102") + ".log"
+        backups := 0
+        for _, name := range names {
+            if name == current {
+                continue
+            }
+            gtest.Assert(strings.HasSuffix(name, ".log.gz"), true)
+            gtest.Assert(strings.HasPrefix(name, "app-"), true)
+            backups++
+        }
+        gtest.Assert(backups, 2)
+        gtest.Assert(gfile.Size(path + gfile.Separator + current) <= 100, true)
+    })
+}
+
+func TestLogger_Hook(t *testing.T) {
+    gtest.Case(t, func() {
+        output  := bytes.NewBuffer(nil)
+        errors  := bytes.NewBuffer(nil)
+        entries := make([]*glog.Entry, 0)
+        logger  := glog.New()
+        logger.SetWriter(output)
+        logger.SetBacktrace(false)
+        logger.AddWriter(errors, glog.LEVEL_ERRO | glog.LEVEL_CRIT)
+        logger.AddHook(glog.HookFunc(func(entry *glog.Entry) error {
+            entries = append(entries, entry)
+            return nil
+        }))
+        logger.Fields(map[string]interface{}{"id" : 1}).Infofln("info")
+        logger.Errorfln("error")
+        logger.Println("print")
+
+        gtest.Assert(strings.Count(output.String(), "\n"), 3)
+        gtest.Assert(strings.Count(errors.String(), "\n"), 2)
+        gtest.Assert(strings.Contains(errors.String(), "[ERRO] error"), true)
+        gtest.Assert(strings.Contains(errors.String(), "print"), true)
+        gtest.Assert(len(entries), 3)
+        gtest.Assert(entries[0].Level, glog.LEVEL_INFO)
+        gtest.Assert(entries[0].LevelName, "INFO")
+        gtest.Assert(entries[0].Message, "info")
+        gtest.Assert(entries[0].Fields["id"], 1)
+        gtest.Assert(strings.Contains(entries[0].Caller, "glog_z_unit_test.go"), true)
+        gtest.Assert(entries[1].Level, glog.LEVEL_ERRO)
+        gtest.Assert(entries[2].Level, 0)
+
+        // 链式操作使用拷贝的Logger，不影响原有Logger的Hook
+        logger.Level(glog.LEVEL_ALL).ClearHooks()
+        logger.Errorfln("error")
+        gtest.Assert(len(entries), 4)
+        logger.ClearHooks()
+        logger.Errorfln("error")
+        gtest.Assert(len(entries), 4)
+    })
+}
+
+func TestLogger_With(t *testing.T) {
+    gtest.Case(t, func() {
+        buffer := bytes.NewBuffer(nil)
+        logger := glog.New()
+        logger.SetWriter(buffer)
+        logger.SetPath("/tmp/glog-with")
+        child := logger.Header(false).With("request_id", "abc", "user", 1, "extra")
+        child.Info("first")
+        child.Fields(map[string]interface{}{"step" : 2}).Info("second")
+        child.Info("third")
+        logger.Header(false).Info("parent")
+        gtest.Assert(buffer.String(),
+            "[INFO] first request_id=abc user=1 extra=<nil>\n" +
+            "[INFO] second request_id=abc user=1 extra=<nil> step=2\n" +
+            "[INFO] third request_id=abc user=1 extra=<nil>\n" +
+            "[INFO] parent\n",
+        )
+        gtest.Assert(child.Cat("sub").GetPath(), "/tmp/glog-with/sub")
+        gtest.Assert(child.GetPath(), "/tmp/glog-with")
+        gtest.Assert(child.File("a.log").With("k", "v") != child, true)
+    })
+}
+
+func TestLogger_Sampling(t *testing.T) {
+    // 等待到新的一秒开始，防止计数跨秒
+    time.Sleep(time.Duration(1000000000 - time.Now().Nanosecond()))
+    gtest.Case(t, func() {
+        buffer := bytes.NewBuffer(nil)
+        logger := glog.New()
+        logger.SetWriter(buffer)
+        logger.SetSampling(2, 3)
+        for i := 0; i < 10; i++ {
+            logger.Error(i)
+        }
+        gtest.Assert(strings.Count(buffer.String(), "[ERRO]"), 4)
+        gtest.Assert(logger.GetSampledOut(), 6)
+
+        buffer.Reset()
+        logger.SetSampling(1, 0, true)
+        for i := 0; i < 3; i++ {
+            logger.Info("a")
+            logger.Info("b")
+        }
+        gtest.Assert(strings.Count(buffer.String(), "\n"), 2)
+        gtest.Assert(logger.GetSampledOut(), 4)
+
+        buffer.Reset()
+        logger.SetSampling(-1, 0)
+        for i := 0; i < 3; i++ {
+            logger.Info("c")
+        }
+        gtest.Assert(strings.Count(buffer.String(), "\n"), 3)
+    })
+}
+
+// 阻塞写入的Writer，用于测试异步缓冲区已满的情况
+type blockingWriter struct {
+    gate   chan struct{}
+    buffer *bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+    <-w.gate
+    return w.buffer.Write(p)
+}
+
+func TestLogger_Async(t *testing.T) {
+    gtest.Case(t, func() {
+        buffer := bytes.NewBuffer(nil)
+        logger := glog.New()
+        logger.SetWriter(buffer)
+        logger.SetAsync(100)
+        gtest.Assert(logger.IsAsync(), true)
+        for i := 0; i < 50; i++ {
+            logger.Infofln("%d", i)
+        }
+        logger.Flush()
+        gtest.Assert(strings.Count(buffer.String(), "\n"), 50)
+        gtest.Assert(strings.HasSuffix(buffer.String(), "[INFO] 49\n"), true)
+        logger.Close()
+        gtest.Assert(logger.IsAsync(), false)
+        logger.Info("sync")
+        gtest.Assert(strings.Count(buffer.String(), "\n"), 51)
+    })
+
+    gtest.Case(t, func() {
+        writer := &blockingWriter{make(chan struct{}), bytes.NewBuffer(nil)}
+        logger := glog.New()
+        logger.SetWriter(writer)
+        logger.SetAsync(2, glog.ASYNC_DROP)
+        // 第1条日志被后台goroutine取出后阻塞，之后2条进入缓冲区，其余丢弃
+        logger.Info(0)
+        time.Sleep(100*time.Millisecond)
+        for i := 1; i < 10; i++ {
+            logger.Info(i)
+        }
+        gtest.Assert(logger.GetAsyncDropped(), 7)
+        close(writer.gate)
+        logger.Close()
+        gtest.Assert(strings.Count(writer.buffer.String(), "\n"), 3)
+        gtest.Assert(strings.HasSuffix(writer.buffer.String(), "[INFO] 2\n"), true)
+    })
+}
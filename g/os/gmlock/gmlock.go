@@ -9,7 +9,10 @@
 // 内存锁.
 package gmlock
 
-import "time"
+import (
+    "context"
+    "time"
+)
 
 var (
     locker = New()
@@ -43,4 +46,39 @@ func RLock(key string) {
 // 解除基于内存锁的读锁
 func RUnlock(key string) {
     locker.RUnlock(key)
-}
\ No newline at end of file
+}
+
+// 内存写锁，在timeout时间内尝试加锁，成功返回true，超时返回false; 过期时间默认为0表示不过期
+func TryLockTimeout(key string, timeout time.Duration, expire...time.Duration) bool {
+    return locker.TryLockTimeout(key, timeout, expire...)
+}
+
+// 内存读锁，在timeout时间内尝试加锁，成功返回true，超时返回false
+func TryRLockTimeout(key string, timeout time.Duration) bool {
+    return locker.TryRLockTimeout(key, timeout)
+}
+
+// 内存写锁，阻塞等待直到加锁成功，或者ctx结束(返回ctx.Err())
+func LockContext(ctx context.Context, key string, expire...time.Duration) error {
+    return locker.LockContext(ctx, key, expire...)
+}
+
+// 内存读锁，阻塞等待直到加锁成功，或者ctx结束(返回ctx.Err())
+func RLockContext(ctx context.Context, key string) error {
+    return locker.RLockContext(ctx, key)
+}
+
+// 可重入写锁，同一持有者(owner)可以重复加锁，需要执行相同次数的UnlockReentrant才会解锁
+func LockReentrant(key string, owner string) {
+    locker.LockReentrant(key, owner)
+}
+
+// 不阻塞的可重入写锁，如果锁成功(或者同一持有者重入)返回true，失败则返回false
+func TryLockReentrant(key string, owner string) bool {
+    return locker.TryLockReentrant(key, owner)
+}
+
+// 解除可重入写锁，只有持有者才能解锁，非持有者调用时返回false
+func UnlockReentrant(key string, owner string) bool {
+    return locker.UnlockReentrant(key, owner)
+}
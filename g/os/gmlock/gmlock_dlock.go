@@ -0,0 +1,211 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/util/grand"
+    "sync"
+    "time"
+)
+
+const (
+    DLOCK_ADAPTER_MEMORY = "memory" // 分布式锁数据源：进程内存(单实例)
+    DLOCK_ADAPTER_REDIS  = "redis"  // 分布式锁数据源：Redis
+    DLOCK_ADAPTER_ETCD   = "etcd"   // 分布式锁数据源：etcd
+)
+
+const (
+    gDLOCK_DEFAULT_TTL      = 30*time.Second        // 默认的锁租约时间
+    gDLOCK_RETRY_INTERVAL   = 50*time.Millisecond   // 阻塞加锁时的重试间隔
+    gDLOCK_RENEW_TIMEOUT    = 5*time.Second         // 单次续约请求的超时时间
+)
+
+// 分布式锁数据源适配器，所有操作均需要保证原子性
+type Adapter interface {
+    // 尝试获取锁，owner为本次加锁的唯一标识，ttl为锁的租约时间。
+    // 成功时返回对于同一key单调递增的fencing token，锁被其他持有者占用时ok为false
+    Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (token int64, ok bool, err error)
+    // 续约，锁已过期或者已不属于owner时返回false
+    Renew(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+    // 释放锁，锁已不属于owner时不做任何操作
+    Release(ctx context.Context, key string, owner string) error
+}
+
+// 分布式锁配置，通过修改配置即可在内存锁与集群锁之间切换
+type DLockConfig struct {
+    Adapter string        // 数据源类型：memory(默认)/redis/etcd
+    Ttl     time.Duration // 锁的租约时间，持有期间自动续约，默认为30秒
+    Prefix  string        // 锁键名前缀
+    Redis   RedisClient   // Adapter为redis时使用的Redis客户端，例如: gredis.New(config)
+    Etcd    EtcdConfig    // Adapter为etcd时的etcd配置
+}
+
+// 分布式锁管理对象
+type DLocker struct {
+    adapter Adapter       // 数据源适配器
+    ttl     time.Duration // 锁的租约时间
+}
+
+// 获取成功的分布式锁，持有期间后台goroutine自动续约，直到Unlock或者续约失败(Lost)
+type Lease struct {
+    locker *DLocker
+    key    string
+    owner  string
+    token  int64
+    once   sync.Once
+    stop   chan struct{} // 停止续约
+    lost   chan struct{} // 锁丢失(续约失败或者已解锁)
+}
+
+// 使用指定的数据源适配器创建分布式锁管理对象，ttl为锁的租约时间(默认为30秒)
+func NewDLocker(adapter Adapter, ttl...time.Duration) *DLocker {
+    d := &DLocker {
+        adapter : adapter,
+        ttl     : gDLOCK_DEFAULT_TTL,
+    }
+    if len(ttl) > 0 && ttl[0] > 0 {
+        d.ttl = ttl[0]
+    }
+    return d
+}
+
+// 根据配置创建分布式锁管理对象，例如:
+// gmlock.NewDLockerByConfig(gmlock.DLockConfig{Adapter : "redis", Redis : gredis.New(gredis.Config{Host : "127.0.0.1", Port : 6379})})
+func NewDLockerByConfig(config DLockConfig) (*DLocker, error) {
+    var adapter Adapter
+    switch config.Adapter {
+        case "", DLOCK_ADAPTER_MEMORY:
+            adapter = NewMemoryAdapter()
+        case DLOCK_ADAPTER_REDIS:
+            if config.Redis == nil {
+                return nil, errors.New("redis client is required for redis lock adapter")
+            }
+            adapter = NewRedisAdapter(config.Redis, config.Prefix)
+        case DLOCK_ADAPTER_ETCD:
+            if config.Prefix != "" && config.Etcd.Prefix == "" {
+                config.Etcd.Prefix = config.Prefix
+            }
+            adapter = NewEtcdAdapter(config.Etcd)
+        default:
+            return nil, errors.New(fmt.Sprintf(`unsupported lock adapter "%s"`, config.Adapter))
+    }
+    return NewDLocker(adapter, config.Ttl), nil
+}
+
+// 尝试获取分布式锁，锁被其他持有者占用时返回nil, nil
+func (d *DLocker) TryLock(ctx context.Context, key string) (*Lease, error) {
+    owner := grand.RandStr(32)
+    token, ok, err := d.adapter.Acquire(ctx, key, owner, d.ttl)
+    if err != nil || !ok {
+        return nil, err
+    }
+    lease := &Lease {
+        locker : d,
+        key    : key,
+        owner  : owner,
+        token  : token,
+        stop   : make(chan struct{}),
+        lost   : make(chan struct{}),
+    }
+    go lease.renew()
+    return lease, nil
+}
+
+// 获取分布式锁，锁被占用时阻塞等待，直到加锁成功或者ctx结束(返回ctx.Err())
+func (d *DLocker) Lock(ctx context.Context, key string) (*Lease, error) {
+    for {
+        lease, err := d.TryLock(ctx, key)
+        if err != nil || lease != nil {
+            return lease, err
+        }
+        select {
+            case <- ctx.Done():
+                return nil, ctx.Err()
+            case <- time.After(gDLOCK_RETRY_INTERVAL):
+        }
+    }
+}
+
+// 在timeout时间内尝试获取分布式锁，超时返回nil, nil
+func (d *DLocker) TryLockTimeout(key string, timeout time.Duration) (*Lease, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    lease, err := d.Lock(ctx, key)
+    if err == context.DeadlineExceeded {
+        return nil, nil
+    }
+    return lease, err
+}
+
+// 获取分布式锁后执行f，执行完毕后自动解锁
+func (d *DLocker) LockFunc(ctx context.Context, key string, f func(lease *Lease)) error {
+    lease, err := d.Lock(ctx, key)
+    if err != nil {
+        return err
+    }
+    defer lease.Unlock()
+    f(lease)
+    return nil
+}
+
+// 锁的键名
+func (l *Lease) Key() string {
+    return l.key
+}
+
+// 锁的fencing token，对于同一key单调递增。写入共享资源时携带该值，
+// 资源方拒绝比已处理过的token更小的请求，即可避免锁过期后旧持有者的写入
+func (l *Lease) Token() int64 {
+    return l.token
+}
+
+// 锁丢失(续约失败、已过期或者已解锁)时关闭的通道
+func (l *Lease) Lost() <-chan struct{} {
+    return l.lost
+}
+
+// 释放分布式锁，只会释放本次获取的锁(锁过期后被其他持有者获取时不会误删)
+func (l *Lease) Unlock() error {
+    err := error(nil)
+    l.once.Do(func() {
+        close(l.stop)
+        ctx, cancel := context.WithTimeout(context.Background(), gDLOCK_RENEW_TIMEOUT)
+        defer cancel()
+        err = l.locker.adapter.Release(ctx, l.key, l.owner)
+    })
+    return err
+}
+
+// 后台续约，每隔租约时间的1/3续约一次，续约失败超过租约时间时认为锁已丢失
+func (l *Lease) renew() {
+    defer close(l.lost)
+    ttl      := l.locker.ttl
+    ticker   := time.NewTicker(ttl/3)
+    renewed  := time.Now()
+    defer ticker.Stop()
+    for {
+        select {
+            case <- l.stop:
+                return
+            case <- ticker.C:
+                ctx, cancel := context.WithTimeout(context.Background(), gDLOCK_RENEW_TIMEOUT)
+                ok, err := l.locker.adapter.Renew(ctx, l.key, l.owner, ttl)
+                cancel()
+                if err == nil && !ok {
+                    return
+                }
+                if err == nil {
+                    renewed = time.Now()
+                } else if time.Since(renewed) >= ttl {
+                    return
+                }
+        }
+    }
+}
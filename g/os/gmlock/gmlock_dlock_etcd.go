@@ -0,0 +1,146 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock
+
+import (
+    "context"
+    "github.com/gogf/gf/g/internal/etcd"
+    "github.com/gogf/gf/g/util/gconv"
+    "net/http"
+    "sync"
+    "time"
+)
+
+// etcd分布式锁适配器配置
+type EtcdConfig struct {
+    Address  string       // etcd服务地址，默认为http://127.0.0.1:2379
+    Prefix   string       // 锁键名前缀
+    Username string       // 用户名(开启认证时需要)
+    Password string       // 密码
+    Client   *http.Client // 自定义的HTTP客户端(非必需)
+}
+
+// etcd分布式锁适配器，通过etcd v3的HTTP(gRPC gateway)接口实现。
+// 锁键名绑定到租约(lease)，续约即为租约的keepalive，租约过期后锁键名由etcd自动删除；
+// 加锁成功时的集群revision作为fencing token(全局单调递增)。
+type etcdAdapter struct {
+    config EtcdConfig
+    client *etcd.Client
+    mu     sync.Mutex
+    leases map[string]int64 // 锁键名+owner对应的租约ID
+}
+
+// 创建etcd分布式锁适配器
+func NewEtcdAdapter(config EtcdConfig) Adapter {
+    return &etcdAdapter {
+        config : config,
+        client : etcd.New(etcd.Config {
+            Address  : config.Address,
+            Username : config.Username,
+            Password : config.Password,
+            Client   : config.Client,
+        }),
+        leases : make(map[string]int64),
+    }
+}
+
+func (a *etcdAdapter) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (int64, bool, error) {
+    seconds := int64((ttl + time.Second - 1)/time.Second)
+    if seconds < 1 {
+        seconds = 1
+    }
+    grant := struct {
+        ID string `json:"ID"`
+    }{}
+    if err := a.client.Call(ctx, "/v3/lease/grant", map[string]interface{} {"TTL" : seconds}, &grant); err != nil {
+        return 0, false, err
+    }
+    leaseId := gconv.Int64(grant.ID)
+    result  := struct {
+        Header    etcd.Header `json:"header"`
+        Succeeded bool       `json:"succeeded"`
+    }{}
+    err := a.client.Call(ctx, "/v3/kv/txn", map[string]interface{} {
+        "compare" : []interface{} {
+            map[string]interface{} {
+                "key"             : etcd.Encode(a.lockKey(key)),
+                "result"          : "EQUAL",
+                "target"          : "CREATE",
+                "create_revision" : "0",
+            },
+        },
+        "success" : []interface{} {
+            map[string]interface{} {
+                "request_put" : map[string]interface{} {
+                    "key"   : etcd.Encode(a.lockKey(key)),
+                    "value" : etcd.Encode(owner),
+                    "lease" : grant.ID,
+                },
+            },
+        },
+    }, &result)
+    if err != nil || !result.Succeeded {
+        a.revoke(leaseId)
+        return 0, false, err
+    }
+    a.mu.Lock()
+    a.leases[key + "@" + owner] = leaseId
+    a.mu.Unlock()
+    return gconv.Int64(result.Header.Revision), true, nil
+}
+
+func (a *etcdAdapter) Renew(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+    a.mu.Lock()
+    leaseId, ok := a.leases[key + "@" + owner]
+    a.mu.Unlock()
+    if !ok {
+        return false, nil
+    }
+    result := struct {
+        Result struct {
+            TTL string `json:"TTL"`
+        } `json:"result"`
+    }{}
+    if err := a.client.Call(ctx, "/v3/lease/keepalive", map[string]interface{} {"ID" : leaseId}, &result); err != nil {
+        return false, err
+    }
+    // 租约已过期时TTL为0或者负数
+    if gconv.Int64(result.Result.TTL) <= 0 {
+        a.mu.Lock()
+        delete(a.leases, key + "@" + owner)
+        a.mu.Unlock()
+        return false, nil
+    }
+    return true, nil
+}
+
+func (a *etcdAdapter) Release(ctx context.Context, key string, owner string) error {
+    a.mu.Lock()
+    leaseId, ok := a.leases[key + "@" + owner]
+    delete(a.leases, key + "@" + owner)
+    a.mu.Unlock()
+    if !ok {
+        return nil
+    }
+    // 撤销租约时etcd会删除绑定到该租约的锁键名，因此不会误删其他持有者的锁
+    return a.client.Call(ctx, "/v3/lease/revoke", map[string]interface{} {"ID" : leaseId}, nil)
+}
+
+// 撤销未使用的租约(忽略错误，租约到期后由etcd自动回收)
+func (a *etcdAdapter) revoke(leaseId int64) {
+    if leaseId == 0 {
+        return
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), gDLOCK_RENEW_TIMEOUT)
+    defer cancel()
+    a.client.Call(ctx, "/v3/lease/revoke", map[string]interface{} {"ID" : leaseId}, nil)
+}
+
+// 锁键名
+func (a *etcdAdapter) lockKey(key string) string {
+    return a.config.Prefix + key
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+// 进程内存分布式锁适配器，仅在单个进程内有效，用于单实例部署以及测试
+type memoryAdapter struct {
+    mu     sync.Mutex
+    locks  map[string]*memoryLockItem // 锁键名对应的持有信息
+    tokens map[string]int64           // 锁键名对应的fencing token(锁释放后保留，保证单调递增)
+}
+
+// 内存锁持有信息
+type memoryLockItem struct {
+    owner  string
+    expire time.Time
+}
+
+// 创建进程内存分布式锁适配器
+func NewMemoryAdapter() Adapter {
+    return &memoryAdapter {
+        locks  : make(map[string]*memoryLockItem),
+        tokens : make(map[string]int64),
+    }
+}
+
+func (a *memoryAdapter) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (int64, bool, error) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    if item, ok := a.locks[key]; ok && time.Now().Before(item.expire) {
+        return 0, false, nil
+    }
+    a.locks[key] = &memoryLockItem {
+        owner  : owner,
+        expire : time.Now().Add(ttl),
+    }
+    a.tokens[key]++
+    return a.tokens[key], true, nil
+}
+
+func (a *memoryAdapter) Renew(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    item, ok := a.locks[key]
+    if !ok || item.owner != owner || !time.Now().Before(item.expire) {
+        return false, nil
+    }
+    item.expire = time.Now().Add(ttl)
+    return true, nil
+}
+
+func (a *memoryAdapter) Release(ctx context.Context, key string, owner string) error {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    if item, ok := a.locks[key]; ok && item.owner == owner {
+        delete(a.locks, key)
+    }
+    return nil
+}
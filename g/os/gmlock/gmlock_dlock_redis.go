@@ -0,0 +1,106 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock
+
+import (
+    "context"
+    "github.com/gogf/gf/g/util/gconv"
+    "time"
+)
+
+const (
+    // 加锁脚本：SET NX PX成功后递增fencing token，KEYS[1]为锁键名，KEYS[2]为token键名
+    gREDIS_ACQUIRE_SCRIPT = `
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+    return redis.call("INCR", KEYS[2])
+end
+return 0`
+    // 续约脚本：仅当锁仍属于owner时才更新过期时间
+    gREDIS_RENEW_SCRIPT   = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`
+    // 解锁脚本：仅当锁仍属于owner时才删除
+    gREDIS_RELEASE_SCRIPT = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("DEL", KEYS[1])
+end
+return 0`
+)
+
+// Redis分布式锁适配器使用的Redis客户端，*gredis.Redis实现了该接口
+type RedisClient interface {
+    Do(command string, args ...interface{}) (interface{}, error)
+}
+
+// Redis分布式锁适配器，使用Lua脚本保证操作的原子性
+type redisAdapter struct {
+    redis  RedisClient
+    prefix string
+}
+
+// 创建Redis分布式锁适配器，prefix为锁键名前缀(可选)，例如: gmlock.NewRedisAdapter(gredis.New(config))。
+// 锁键名与token键名使用相同的hash tag，集群模式下分配到同一个槽位。
+func NewRedisAdapter(redis RedisClient, prefix...string) Adapter {
+    a := &redisAdapter {
+        redis : redis,
+    }
+    if len(prefix) > 0 {
+        a.prefix = prefix[0]
+    }
+    return a
+}
+
+func (a *redisAdapter) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (int64, bool, error) {
+    if err := ctx.Err(); err != nil {
+        return 0, false, err
+    }
+    r, err := a.redis.Do("EVAL", gREDIS_ACQUIRE_SCRIPT, 2, a.lockKey(key), a.tokenKey(key), owner, ttlMilliseconds(ttl))
+    if err != nil {
+        return 0, false, err
+    }
+    token := gconv.Int64(r)
+    return token, token > 0, nil
+}
+
+func (a *redisAdapter) Renew(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+    if err := ctx.Err(); err != nil {
+        return false, err
+    }
+    r, err := a.redis.Do("EVAL", gREDIS_RENEW_SCRIPT, 1, a.lockKey(key), owner, ttlMilliseconds(ttl))
+    if err != nil {
+        return false, err
+    }
+    return gconv.Int64(r) > 0, nil
+}
+
+func (a *redisAdapter) Release(ctx context.Context, key string, owner string) error {
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+    _, err := a.redis.Do("EVAL", gREDIS_RELEASE_SCRIPT, 1, a.lockKey(key), owner)
+    return err
+}
+
+// 锁键名
+func (a *redisAdapter) lockKey(key string) string {
+    return a.prefix + "{" + key + "}"
+}
+
+// fencing token键名
+func (a *redisAdapter) tokenKey(key string) string {
+    return a.prefix + "{" + key + "}:token"
+}
+
+// 租约时间转换为毫秒，最小为1毫秒
+func ttlMilliseconds(ttl time.Duration) int64 {
+    if ms := int64(ttl/time.Millisecond); ms > 0 {
+        return ms
+    }
+    return 1
+}
@@ -7,12 +7,17 @@
 package gmlock
 
 import (
+    "context"
     "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/os/gtimer"
     "time"
 )
 
-// 内存锁管理对象
+const (
+    gRETRY_INTERVAL = time.Millisecond // 超时锁的重试间隔
+)
+
+// 内存锁管理对象，不再使用的锁(没有持有者及等待者)会被自动清除
 type Locker struct {
     m *gmap.StringInterfaceMap
 }
@@ -37,7 +42,9 @@ func (l *Locker) Lock(key string, expire...time.Duration) {
 // 解除基于内存锁的写锁
 func (l *Locker) Unlock(key string) {
     if v := l.m.Get(key); v != nil {
-        v.(*Mutex).Unlock()
+        if mu := v.(*Mutex); mu.doUnlock() {
+            l.release(key, mu)
+        }
     }
 }
 
@@ -54,8 +61,75 @@ func (l *Locker) RLock(key string) {
 // 解除基于内存锁的读锁
 func (l *Locker) RUnlock(key string) {
     if v := l.m.Get(key); v != nil {
-        v.(*Mutex).RUnlock()
+        if mu := v.(*Mutex); mu.doRUnlock() {
+            l.release(key, mu)
+        }
+    }
+}
+
+// 内存写锁，在timeout时间内尝试加锁，成功返回true，超时返回false; 过期时间默认为0表示不过期
+func (l *Locker) TryLockTimeout(key string, timeout time.Duration, expire...time.Duration) bool {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    return l.LockContext(ctx, key, expire...) == nil
+}
+
+// 内存读锁，在timeout时间内尝试加锁，成功返回true，超时返回false
+func (l *Locker) TryRLockTimeout(key string, timeout time.Duration) bool {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    return l.RLockContext(ctx, key) == nil
+}
+
+// 内存写锁，阻塞等待直到加锁成功，或者ctx结束(返回ctx.Err()); 过期时间默认为0表示不过期
+func (l *Locker) LockContext(ctx context.Context, key string, expire...time.Duration) error {
+    return l.tryUntil(ctx, func() bool {
+        return l.TryLock(key, expire...)
+    })
+}
+
+// 内存读锁，阻塞等待直到加锁成功，或者ctx结束(返回ctx.Err())
+func (l *Locker) RLockContext(ctx context.Context, key string) error {
+    return l.tryUntil(ctx, func() bool {
+        return l.TryRLock(key)
+    })
+}
+
+// 可重入写锁，owner为持有者标识(例如请求ID/任务ID)，同一持有者可以重复加锁而不会死锁，
+// 需要执行相同次数的UnlockReentrant才会解锁
+func (l *Locker) LockReentrant(key string, owner string) {
+    l.acquire(key).LockReentrant(owner)
+}
+
+// 不阻塞的可重入写锁，如果锁成功(或者同一持有者重入)返回true，失败则返回false
+func (l *Locker) TryLockReentrant(key string, owner string) bool {
+    mu := l.acquire(key)
+    if mu.TryLockReentrant(owner) {
+        return true
+    }
+    l.release(key, mu)
+    return false
+}
+
+// 解除可重入写锁，只有持有者才能解锁，非持有者调用时返回false
+func (l *Locker) UnlockReentrant(key string, owner string) bool {
+    if v := l.m.Get(key); v != nil {
+        mu := v.(*Mutex)
+        mu.omu.Lock()
+        held := mu.ocount > 0 && mu.owner == owner
+        mu.omu.Unlock()
+        if held {
+            mu.UnlockReentrant(owner)
+            l.release(key, mu)
+            return true
+        }
     }
+    return false
+}
+
+// 获取当前使用中(被持有或者等待中)的锁数量
+func (l *Locker) Size() int {
+    return l.m.Size()
 }
 
 // 获得过期时间，没有设置时默认为0不过期
@@ -69,40 +143,80 @@ func (l *Locker) getExpire(expire...time.Duration) time.Duration {
 
 // 内存写锁，当try==true时，如果锁成功返回true，失败则返回false；try==false时，成功时立即返回，否则阻塞等待
 func (l *Locker) doLock(key string, expire time.Duration, try bool) bool {
-    mu := l.getOrNewMutex(key)
+    mu := l.acquire(key)
     ok := true
     if try {
         ok = mu.TryLock()
     } else {
         mu.Lock()
     }
-    if ok && expire > 0 {
+    if !ok {
+        l.release(key, mu)
+        return false
+    }
+    if expire > 0 {
         // 异步goroutine计时处理
         wid := mu.wid.Val()
         gtimer.AddOnce(expire, func() {
-            if wid == mu.wid.Val() {
-                mu.Unlock()
+            if wid == mu.wid.Val() && mu.doUnlock() {
+                l.release(key, mu)
             }
         })
     }
-    return ok
+    return true
 }
 
 // 内存读锁，当try==true时，如果锁成功返回true，失败则返回false；try==false时，成功时立即返回，否则阻塞等待
 func (l *Locker) doRLock(key string, try bool) bool {
-    mu := l.getOrNewMutex(key)
+    mu := l.acquire(key)
     ok := true
     if try {
         ok = mu.TryRLock()
     } else {
         mu.RLock()
     }
+    if !ok {
+        l.release(key, mu)
+    }
     return ok
 }
 
-// 根据指定key查询或者创建新的Mutex
-func (l *Locker) getOrNewMutex(key string) (*Mutex) {
-    return l.m.GetOrSetFuncLock(key, func() interface{} {
-        return NewMutex()
-    }).(*Mutex)
+// 每隔gRETRY_INTERVAL尝试执行一次f，直到f返回true或者ctx结束
+func (l *Locker) tryUntil(ctx context.Context, f func() bool) error {
+    for {
+        if f() {
+            return nil
+        }
+        select {
+            case <- ctx.Done():
+                return ctx.Err()
+            case <- time.After(gRETRY_INTERVAL):
+        }
+    }
+}
+
+// 根据指定key查询或者创建新的Mutex，并增加引用计数
+func (l *Locker) acquire(key string) (mu *Mutex) {
+    l.m.LockFunc(func(m map[string]interface{}) {
+        if v, ok := m[key]; ok {
+            mu = v.(*Mutex)
+        } else {
+            mu     = NewMutex()
+            m[key] = mu
+        }
+        mu.refs++
+    })
+    return
+}
+
+// 减少Mutex的引用计数，没有引用时从管理对象中删除，防止不再使用的锁无限增长
+func (l *Locker) release(key string, mu *Mutex) {
+    l.m.LockFunc(func(m map[string]interface{}) {
+        mu.refs--
+        if mu.refs <= 0 {
+            if v, ok := m[key]; ok && v == mu {
+                delete(m, key)
+            }
+        }
+    })
 }
@@ -17,6 +17,10 @@ type Mutex struct {
     wid    *gtype.Int64        // 当前Lock产生的唯一id(主要用于计时Unlock的校验)
     rcount *gtype.Int          // RLock次数
     wcount *gtype.Int          // Lock次数
+    omu    sync.Mutex          // 可重入锁持有者信息的互斥锁
+    owner  string              // 可重入写锁的持有者标识
+    ocount int                 // 可重入写锁的重入次数
+    refs   int                 // 使用该锁的引用计数(由Locker维护，用于清除不再使用的锁)
 }
 
 // 创建一把内存锁使用的底层RWMutex
@@ -36,14 +40,21 @@ func (l *Mutex) Lock() {
 
 // 安全的Unlock
 func (l *Mutex) Unlock() {
+    l.doUnlock()
+}
+
+// 安全的Unlock，返回是否执行了解锁
+func (l *Mutex) doUnlock() bool {
     if l.wcount.Val() > 0 {
         if l.wcount.Add(-1) >= 0 {
             l.mu.Unlock()
+            return true
         } else {
             // 标准库这里会panic
             l.wcount.Add(1)
         }
     }
+    return false
 }
 
 func (l *Mutex) RLock() {
@@ -53,14 +64,21 @@ func (l *Mutex) RLock() {
 
 // 安全的RUnlock
 func (l *Mutex) RUnlock() {
+    l.doRUnlock()
+}
+
+// 安全的RUnlock，返回是否执行了解锁
+func (l *Mutex) doRUnlock() bool {
     if l.rcount.Val() > 0 {
         if l.rcount.Add(-1) >= 0 {
             l.mu.RUnlock()
+            return true
         } else {
             // 标准库这里会panic
             l.rcount.Add(1)
         }
     }
+    return false
 }
 
 // 不阻塞Lock
@@ -90,3 +108,59 @@ func (l *Mutex) TryRLock() bool {
     return false
 }
 
+// 可重入写锁，owner为持有者标识(例如请求ID)，同一持有者可以重复加锁，需要执行相同次数的UnlockReentrant才会解锁
+func (l *Mutex) LockReentrant(owner string) {
+    if l.reenter(owner) {
+        return
+    }
+    l.Lock()
+    l.setOwner(owner)
+}
+
+// 不阻塞的可重入写锁，如果锁成功(或者重入成功)返回true，失败则返回false
+func (l *Mutex) TryLockReentrant(owner string) bool {
+    if l.reenter(owner) {
+        return true
+    }
+    if l.TryLock() {
+        l.setOwner(owner)
+        return true
+    }
+    return false
+}
+
+// 解除可重入写锁，只有持有者才能解锁，返回是否释放了写锁(重入次数减为0)
+func (l *Mutex) UnlockReentrant(owner string) bool {
+    l.omu.Lock()
+    if l.ocount == 0 || l.owner != owner {
+        l.omu.Unlock()
+        return false
+    }
+    l.ocount--
+    if l.ocount > 0 {
+        l.omu.Unlock()
+        return false
+    }
+    l.owner = ""
+    l.omu.Unlock()
+    return l.doUnlock()
+}
+
+// 判断是否为同一持有者的重入，是则增加重入次数
+func (l *Mutex) reenter(owner string) bool {
+    l.omu.Lock()
+    defer l.omu.Unlock()
+    if l.ocount > 0 && l.owner == owner {
+        l.ocount++
+        return true
+    }
+    return false
+}
+
+// 加锁成功后设置持有者
+func (l *Mutex) setOwner(owner string) {
+    l.omu.Lock()
+    l.owner  = owner
+    l.ocount = 1
+    l.omu.Unlock()
+}
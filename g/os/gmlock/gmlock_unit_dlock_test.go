@@ -0,0 +1,82 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock_test
+
+import (
+    "context"
+    "github.com/gogf/gf/g/os/gmlock"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestDLocker_TryLock(t *testing.T) {
+    gtest.Case(t, func() {
+        locker, err := gmlock.NewDLockerByConfig(gmlock.DLockConfig{})
+        gtest.Assert(err, nil)
+        ctx := context.Background()
+
+        lease1, err := locker.TryLock(ctx, "dlock")
+        gtest.Assert(err, nil)
+        gtest.AssertNE(lease1, nil)
+        gtest.Assert(lease1.Key(), "dlock")
+        lease2, err := locker.TryLock(ctx, "dlock")
+        gtest.Assert(err, nil)
+        gtest.Assert(lease2 == nil, true)
+
+        gtest.Assert(lease1.Unlock(), nil)
+        <- lease1.Lost()
+        lease2, err = locker.TryLock(ctx, "dlock")
+        gtest.Assert(err, nil)
+        gtest.AssertNE(lease2, nil)
+        // fencing token单调递增
+        gtest.Assert(lease2.Token() > lease1.Token(), true)
+        // 重复解锁不会释放其他持有者的锁
+        gtest.Assert(lease1.Unlock(), nil)
+        lease3, _ := locker.TryLock(ctx, "dlock")
+        gtest.Assert(lease3 == nil, true)
+        lease2.Unlock()
+
+        _, err = gmlock.NewDLockerByConfig(gmlock.DLockConfig{Adapter : "unknown"})
+        gtest.AssertNE(err, nil)
+        // 未指定Redis客户端
+        _, err = gmlock.NewDLockerByConfig(gmlock.DLockConfig{Adapter : "redis"})
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestDLocker_Renew(t *testing.T) {
+    gtest.Case(t, func() {
+        locker := gmlock.NewDLocker(gmlock.NewMemoryAdapter(), 60*time.Millisecond)
+        lease, err := locker.TryLock(context.Background(), "dlock_renew")
+        gtest.Assert(err, nil)
+        // 超过租约时间后由于自动续约仍然持有锁
+        time.Sleep(200*time.Millisecond)
+        l, _ := locker.TryLockTimeout("dlock_renew", 10*time.Millisecond)
+        gtest.Assert(l == nil, true)
+        select {
+            case <- lease.Lost():
+                t.Error("lease lost")
+            default:
+        }
+        go func() {
+            time.Sleep(50*time.Millisecond)
+            lease.Unlock()
+        }()
+        l, err = locker.TryLockTimeout("dlock_renew", time.Second)
+        gtest.Assert(err, nil)
+        gtest.AssertNE(l, nil)
+        l.Unlock()
+
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+        defer cancel()
+        l, _ = locker.Lock(context.Background(), "dlock_renew")
+        _, err = locker.Lock(ctx, "dlock_renew")
+        gtest.Assert(err, context.DeadlineExceeded)
+        l.Unlock()
+    })
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmlock_test
+
+import (
+    "context"
+    "github.com/gogf/gf/g/os/gmlock"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "testing"
+    "time"
+)
+
+func TestLocker_TryLockTimeout(t *testing.T) {
+    gtest.Case(t, func() {
+        key := "test_timeout"
+        gmlock.Lock(key)
+        go func() {
+            time.Sleep(100*time.Millisecond)
+            gmlock.Unlock(key)
+        }()
+        gtest.Assert(gmlock.TryLockTimeout(key, 10*time.Millisecond), false)
+        gtest.Assert(gmlock.TryRLockTimeout(key, 10*time.Millisecond), false)
+        gtest.Assert(gmlock.TryLockTimeout(key, time.Second), true)
+        gmlock.Unlock(key)
+
+        gtest.Assert(gmlock.TryRLockTimeout(key, time.Second), true)
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+        defer cancel()
+        gtest.Assert(gmlock.LockContext(ctx, key), context.DeadlineExceeded)
+        gmlock.RUnlock(key)
+        gtest.Assert(gmlock.LockContext(context.Background(), key), nil)
+        gtest.Assert(gmlock.RLockContext(ctx, key), context.DeadlineExceeded)
+        gmlock.Unlock(key)
+    })
+}
+
+func TestLocker_Reentrant(t *testing.T) {
+    gtest.Case(t, func() {
+        key := "test_reentrant"
+        gmlock.LockReentrant(key, "a")
+        gmlock.LockReentrant(key, "a")
+        gtest.Assert(gmlock.TryLockReentrant(key, "a"), true)
+        gtest.Assert(gmlock.TryLockReentrant(key, "b"), false)
+        gtest.Assert(gmlock.TryLock(key), false)
+        gtest.Assert(gmlock.UnlockReentrant(key, "b"), false)
+        gtest.Assert(gmlock.UnlockReentrant(key, "a"), true)
+        gtest.Assert(gmlock.UnlockReentrant(key, "a"), true)
+        gtest.Assert(gmlock.TryLockReentrant(key, "b"), false)
+        gtest.Assert(gmlock.UnlockReentrant(key, "a"), true)
+        gtest.Assert(gmlock.UnlockReentrant(key, "a"), false)
+        gtest.Assert(gmlock.TryLockReentrant(key, "b"), true)
+        gtest.Assert(gmlock.UnlockReentrant(key, "b"), true)
+    })
+}
+
+func TestLocker_Cleanup(t *testing.T) {
+    gtest.Case(t, func() {
+        locker := gmlock.New()
+        for i := 0; i < 100; i++ {
+            key := "test_cleanup_" + gconv.String(i)
+            locker.Lock(key)
+            locker.RLock(key + "r")
+            locker.LockReentrant(key + "o", "owner")
+            gtest.Assert(locker.TryLock(key), false)
+        }
+        gtest.Assert(locker.Size(), 300)
+        for i := 0; i < 100; i++ {
+            key := "test_cleanup_" + gconv.String(i)
+            locker.Unlock(key)
+            locker.RUnlock(key + "r")
+            locker.UnlockReentrant(key + "o", "owner")
+        }
+        gtest.Assert(locker.Size(), 0)
+
+        locker.Lock("expire", 10*time.Millisecond)
+        gtest.Assert(locker.Size(), 1)
+        time.Sleep(100*time.Millisecond)
+        gtest.Assert(locker.Size(), 0)
+
+        // 等待中的锁不会被清除
+        locker.Lock("wait")
+        go func() {
+            locker.Lock("wait")
+            locker.Unlock("wait")
+        }()
+        time.Sleep(10*time.Millisecond)
+        locker.Unlock("wait")
+        time.Sleep(10*time.Millisecond)
+        gtest.Assert(locker.Size(), 0)
+    })
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gproc
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "time"
+)
+
+const (
+    gCMD_WAIT_DELAY = time.Second // 进程被终止后等待输出管道关闭的最长时间(防止后台子进程持有管道造成阻塞)
+)
+
+// 指令执行选项
+type CmdOption struct {
+    Context context.Context   // 上下文对象，上下文结束时终止进程(包括其创建的子进程)
+    Timeout time.Duration     // 执行超时时间，超时后终止进程，0表示不限制
+    Env     map[string]string // 附加的环境变量，覆盖当前进程的同名环境变量
+    Dir     string            // 工作目录，默认为当前工作目录
+    Stdin   io.Reader         // 标准输入，默认为空
+    Stdout  func(line string) // 标准输出的逐行回调方法(不包含换行符)，在进程执行过程中实时调用
+    Stderr  func(line string) // 标准错误输出的逐行回调方法(不包含换行符)，与Stdout在不同的goroutine中调用
+}
+
+// 指令执行结果
+type CmdResult struct {
+    Stdout   string        // 标准输出内容
+    Stderr   string        // 标准错误输出内容
+    ExitCode int           // 进程退出码，进程被信号终止(例如超时)时为-1
+    TimedOut bool          // 是否因为执行超时被终止
+    Duration time.Duration // 执行耗时
+}
+
+// 逐行回调的输出对象，同时保存完整的输出内容
+type lineWriter struct {
+    buffer   bytes.Buffer      // 完整的输出内容
+    line     []byte            // 尚未输出换行符的行内容
+    callback func(line string) // 逐行回调方法
+}
+
+// 阻塞执行shell指令，并输出结果到终端，超过timeout时间后终止进程并返回错误
+func ShellRunWithTimeout(cmd string, timeout time.Duration) error {
+    p := NewProcess(getShell(), []string{getShellOption(), cmd})
+    _, err := runProcess(p, CmdOption{Timeout : timeout}, false)
+    return err
+}
+
+// 阻塞执行shell指令，返回执行结果(包括输出内容及退出码)，
+// 可选参数option用于设置超时时间、环境变量、工作目录以及输出的逐行回调。
+// 进程退出码不为0时同时返回错误，此时执行结果仍然有效。
+func ShellCmd(cmd string, option...CmdOption) (*CmdResult, error) {
+    return Cmd(getShell(), []string{getShellOption(), cmd}, option...)
+}
+
+// 阻塞执行指定的可执行文件，path为文件路径或者环境变量PATH中的文件名称，返回执行结果(包括输出内容及退出码)，
+// 参数不经过shell解析。进程退出码不为0时同时返回错误，此时执行结果仍然有效。
+func Cmd(path string, args []string, option...CmdOption) (*CmdResult, error) {
+    opt := CmdOption{}
+    if len(option) > 0 {
+        opt = option[0]
+    }
+    if bin := searchBinFromEnvPath(path); bin != "" {
+        path = bin
+    }
+    return runProcess(NewProcess(path, args), opt, true)
+}
+
+// 按照选项执行进程，capture为true时收集进程的输出内容，否则直接输出到终端
+func runProcess(p *Process, opt CmdOption, capture bool) (*CmdResult, error) {
+    if opt.Dir != "" {
+        p.Dir = opt.Dir
+    }
+    for k, v := range opt.Env {
+        p.Env = append(p.Env, fmt.Sprintf("%s=%s", k, v))
+    }
+    stdout := &lineWriter{callback : opt.Stdout}
+    stderr := &lineWriter{callback : opt.Stderr}
+    if capture {
+        p.Stdin  = opt.Stdin
+        p.Stdout = stdout
+        p.Stderr = stderr
+    }
+    setProcessGroup(&p.Cmd)
+    p.WaitDelay = gCMD_WAIT_DELAY
+    ctx := opt.Context
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if opt.Timeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, opt.Timeout)
+        defer cancel()
+    }
+    start := time.Now()
+    if _, err := p.Start(); err != nil {
+        return nil, err
+    }
+    done := make(chan struct{})
+    go func() {
+        select {
+            case <- ctx.Done():
+                killProcessGroup(p.Process)
+            case <- done:
+        }
+    }()
+    err := p.Wait()
+    close(done)
+    stdout.flush()
+    stderr.flush()
+    result := &CmdResult {
+        Stdout   : stdout.buffer.String(),
+        Stderr   : stderr.buffer.String(),
+        ExitCode : -1,
+        Duration : time.Since(start),
+    }
+    if p.ProcessState != nil {
+        result.ExitCode = p.ProcessState.ExitCode()
+    }
+    if ctx.Err() != nil {
+        if ctx.Err() == context.DeadlineExceeded {
+            result.TimedOut = true
+            err = errors.New(fmt.Sprintf(`command "%s" timed out after %s`, p.Path, opt.Timeout))
+        } else {
+            err = ctx.Err()
+        }
+    }
+    return result, err
+}
+
+// 写入输出内容，每输出完整的一行执行一次回调
+func (w *lineWriter) Write(p []byte) (int, error) {
+    w.buffer.Write(p)
+    if w.callback == nil {
+        return len(p), nil
+    }
+    w.line = append(w.line, p...)
+    for {
+        i := bytes.IndexByte(w.line, '\n')
+        if i < 0 {
+            break
+        }
+        w.callback(string(bytes.TrimRight(w.line[ : i], "\r")))
+        w.line = w.line[i + 1 : ]
+    }
+    return len(p), nil
+}
+
+// 回调最后一行没有换行符的输出内容
+func (w *lineWriter) flush() {
+    if w.callback != nil && len(w.line) > 0 {
+        w.callback(string(w.line))
+        w.line = nil
+    }
+}
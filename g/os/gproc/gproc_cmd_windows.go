@@ -0,0 +1,22 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gproc
+
+import (
+    "os"
+    "os/exec"
+)
+
+// windows下不支持进程组，不做处理
+func setProcessGroup(cmd *exec.Cmd) {
+
+}
+
+// 终止进程
+func killProcessGroup(process *os.Process) error {
+    return process.Kill()
+}
@@ -9,7 +9,12 @@
 package gproc
 
 import (
+    "errors"
+    "net"
     "os"
+    "sync"
+    "time"
+    "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/util/gconv"
     "github.com/gogf/gf/g/container/gmap"
@@ -21,11 +26,57 @@ var commReceiveQueues = gmap.NewStringInterfaceMap()
 // (用于发送)已建立的PID对应的Conn通信对象，键值为一个Pool，防止并行使用同一个通信对象造成数据重叠
 var commPidConnMap    = gmap.NewIntInterfaceMap()
 
-// TCP通信数据结构定义
+// 进程间通信数据结构定义
 type Msg struct {
-    Pid   int     // PID，来源哪个进程
-    Data  []byte  // 数据
-    Group string  // 分组名称
+    Pid     int         // PID，来源哪个进程
+    Data    []byte      // 数据
+    Group   string      // 分组名称
+    seq     uint32      // 请求消息序号
+    conn    *commConn   // 请求消息的通信对象，用于返回回复数据，非请求消息为nil
+    replied *gtype.Bool // 请求消息是否已回复
+}
+
+// 进程间通信连接对象，写入数据帧时加锁，防止并行写入造成数据重叠
+type commConn struct {
+    mu   sync.Mutex
+    conn net.Conn
+}
+
+// 判断消息是否为请求消息(通过Request发送，需要使用Reply返回回复数据)
+func (msg *Msg) IsRequest() bool {
+    return msg.conn != nil
+}
+
+// 向请求消息的发送进程返回回复数据，每个请求消息只能回复一次
+func (msg *Msg) Reply(data []byte) error {
+    if msg.conn == nil {
+        return errors.New("message is not a request")
+    }
+    if msg.replied.Set(true) {
+        return errors.New("message has already been replied")
+    }
+    return msg.conn.send(&commFrame {
+        typ      : gFRAME_TYPE_REPLY,
+        seq      : msg.seq,
+        sender   : Pid(),
+        receiver : msg.Pid,
+        group    : msg.Group,
+        data     : data,
+    })
+}
+
+// 写入数据帧
+func (c *commConn) send(f *commFrame) error {
+    f.time = time.Now().UnixNano()
+    buffer, err := encodeFrame(f)
+    if err != nil {
+        return err
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.conn.SetWriteDeadline(time.Now().Add(gPROC_COMM_SEND_TIMEOUT*time.Millisecond))
+    _, err = c.conn.Write(buffer)
+    return err
 }
 
 // 获取指定进程的通信文件地址
@@ -33,6 +84,16 @@ func getCommFilePath(pid int) string {
     return getCommDirPath() + gfile.Separator + gconv.String(pid)
 }
 
+// 获取指定进程的Unix Socket通信文件所在的私有目录地址(权限为0700)
+func getCommSockDirPath(pid int) string {
+    return getCommFilePath(pid) + ".d"
+}
+
+// 获取指定进程的Unix Socket通信文件地址
+func getCommSockPath(pid int) string {
+    return getCommSockDirPath(pid) + gfile.Separator + "comm.sock"
+}
+
 // 获取进程间通信目录地址
 func getCommDirPath() string {
     tempDir := os.Getenv(gPROC_TEMP_DIR_ENV_KEY)
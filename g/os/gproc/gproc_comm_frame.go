@@ -0,0 +1,201 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gproc
+
+import (
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/encoding/gbinary"
+    "hash/crc32"
+    "io"
+    "os"
+    "sync"
+    "time"
+)
+
+const (
+    gPROC_COMM_SECRET_ENV_KEY    = "GPROC_COMM_SECRET" // 进程间通信共享密钥的环境变量名称(子进程通过该环境变量继承密钥)
+    gPROC_COMM_MAX_FRAME_SIZE    = 64*1024*1024        // 单个数据帧的最大长度(字节)
+    gPROC_COMM_AUTH_EXPIRE       = 30*time.Second      // 认证数据帧的有效时间，有效时间内通过随机数防止重放
+    gPROC_COMM_FRAME_VERSION     = 1                   // 数据帧格式版本
+    gPROC_COMM_FRAME_HEADER_SIZE = 31                  // 数据帧固定头部长度(版本+类型+序号+随机数+时间+发送PID+接收PID+分组长度)
+)
+
+const (
+    gFRAME_TYPE_SEND    = 1 // 数据帧类型：发送消息(对方返回确认帧)
+    gFRAME_TYPE_REQUEST = 2 // 数据帧类型：请求消息(对方通过Msg.Reply返回回复帧)
+    gFRAME_TYPE_ACK     = 3 // 数据帧类型：确认
+    gFRAME_TYPE_REPLY   = 4 // 数据帧类型：回复
+    gFRAME_TYPE_ERROR   = 5 // 数据帧类型：错误信息
+)
+
+var (
+    // 进程间通信共享密钥，为空表示不进行认证
+    commSecret = gtype.NewString(os.Getenv(gPROC_COMM_SECRET_ENV_KEY))
+    // 有效时间内已经接收的认证数据帧随机数，用于拒绝重放的数据帧
+    commNonces = &nonceCache{nonces : make(map[nonceKey]int64)}
+)
+
+// 已接收的数据帧随机数缓存
+type nonceCache struct {
+    mu      sync.Mutex
+    nonces  map[nonceKey]int64 // 随机数对应的数据帧发送时间(纳秒)
+    cleared int64              // 最近一次清理过期随机数的时间(纳秒)
+}
+
+// 数据帧随机数键名，不同发送进程的随机数互不影响
+type nonceKey struct {
+    sender int
+    nonce  uint64
+}
+
+// 进程间通信数据帧
+type commFrame struct {
+    typ      int    // 数据帧类型
+    seq      uint32 // 序号，回复帧与请求帧的序号相同
+    nonce    uint64 // 随机数，每个数据帧唯一，用于防止重放
+    time     int64  // 发送时间(纳秒)
+    sender   int    // 发送进程PID
+    receiver int    // 接收进程PID
+    group    string // 分组名称
+    data     []byte // 数据
+}
+
+// 设置进程间通信的共享密钥，设置后所有的通信数据帧都将使用HMAC-SHA256进行签名及认证，
+// 未携带正确签名的数据帧将会被拒绝。密钥同时写入环境变量，由gproc创建的子进程自动继承。
+// 参与通信的进程需要使用相同的密钥，secret为空表示关闭认证。
+func SetCommSecret(secret string) {
+    commSecret.Set(secret)
+    os.Setenv(gPROC_COMM_SECRET_ENV_KEY, secret)
+}
+
+// 数据帧打包，数据格式：
+// 长度(32bit，不包含自身)|校验(32bit，CRC32)|版本(8bit)|类型(8bit)|序号(32bit)|随机数(64bit)|时间(64bit)|
+// 发送进程PID(32bit)|接收进程PID(32bit)|分组长度(8bit)|分组名称(变长)|签名长度(8bit)|签名(变长，HMAC-SHA256)|数据(变长)
+func encodeFrame(f *commFrame) ([]byte, error) {
+    if len(f.group) > 0xff {
+        return nil, errors.New(fmt.Sprintf(`group name too long: %d`, len(f.group)))
+    }
+    nonce := make([]byte, 8)
+    if _, err := rand.Read(nonce); err != nil {
+        return nil, err
+    }
+    f.nonce = gbinary.BeDecodeToUint64(nonce)
+    header := make([]byte, 0, gPROC_COMM_FRAME_HEADER_SIZE + len(f.group))
+    header  = append(header, gPROC_COMM_FRAME_VERSION)
+    header  = append(header, byte(f.typ))
+    header  = append(header, gbinary.BeEncodeUint32(f.seq)...)
+    header  = append(header, nonce...)
+    header  = append(header, gbinary.BeEncodeInt64(f.time)...)
+    header  = append(header, gbinary.BeEncodeUint32(uint32(f.sender))...)
+    header  = append(header, gbinary.BeEncodeUint32(uint32(f.receiver))...)
+    header  = append(header, byte(len(f.group)))
+    header  = append(header, f.group...)
+    mac    := signFrame(header, f.data)
+    length := 4 + len(header) + 1 + len(mac) + len(f.data)
+    if length > gPROC_COMM_MAX_FRAME_SIZE {
+        return nil, errors.New(fmt.Sprintf(`frame too large: %d`, length))
+    }
+    buffer := make([]byte, 0, 4 + length)
+    buffer  = append(buffer, gbinary.BeEncodeUint32(uint32(length))...)
+    buffer  = append(buffer, 0, 0, 0, 0)
+    buffer  = append(buffer, header...)
+    buffer  = append(buffer, byte(len(mac)))
+    buffer  = append(buffer, mac...)
+    buffer  = append(buffer, f.data...)
+    copy(buffer[4 : 8], gbinary.BeEncodeUint32(crc32.ChecksumIEEE(buffer[8 : ])))
+    return buffer, nil
+}
+
+// 从数据流中读取并解析一个数据帧，同时进行校验及认证
+func readFrame(reader io.Reader) (*commFrame, error) {
+    buffer := make([]byte, 4)
+    if _, err := io.ReadFull(reader, buffer); err != nil {
+        return nil, err
+    }
+    length := int(gbinary.BeDecodeToUint32(buffer))
+    if length < 4 + gPROC_COMM_FRAME_HEADER_SIZE + 1 || length > gPROC_COMM_MAX_FRAME_SIZE {
+        return nil, errors.New(fmt.Sprintf(`invalid frame length: %d`, length))
+    }
+    buffer = make([]byte, length)
+    if _, err := io.ReadFull(reader, buffer); err != nil {
+        return nil, err
+    }
+    if gbinary.BeDecodeToUint32(buffer[ : 4]) != crc32.ChecksumIEEE(buffer[4 : ]) {
+        return nil, errors.New("invalid frame checksum")
+    }
+    if version := buffer[4]; version != gPROC_COMM_FRAME_VERSION {
+        return nil, errors.New(fmt.Sprintf(`unsupported frame version: %d`, version))
+    }
+    groupLen := int(buffer[4 + gPROC_COMM_FRAME_HEADER_SIZE - 1])
+    macPos   := 4 + gPROC_COMM_FRAME_HEADER_SIZE + groupLen
+    if macPos >= length || macPos + 1 + int(buffer[macPos]) > length {
+        return nil, errors.New("invalid frame header")
+    }
+    header := buffer[4 : macPos]
+    mac    := buffer[macPos + 1 : macPos + 1 + int(buffer[macPos])]
+    f      := &commFrame {
+        typ      : int(header[1]),
+        seq      : gbinary.BeDecodeToUint32(header[2 : 6]),
+        nonce    : gbinary.BeDecodeToUint64(header[6 : 14]),
+        time     : gbinary.BeDecodeToInt64(header[14 : 22]),
+        sender   : int(gbinary.BeDecodeToUint32(header[22 : 26])),
+        receiver : int(gbinary.BeDecodeToUint32(header[26 : 30])),
+        group    : string(header[gPROC_COMM_FRAME_HEADER_SIZE : ]),
+        data     : buffer[macPos + 1 + len(mac) : ],
+    }
+    if commSecret.Val() != "" {
+        if !hmac.Equal(mac, signFrame(header, f.data)) {
+            return nil, errors.New("authentication failed")
+        }
+        if d := time.Since(time.Unix(0, f.time)); d > gPROC_COMM_AUTH_EXPIRE || d < -gPROC_COMM_AUTH_EXPIRE {
+            return nil, errors.New("authentication expired")
+        }
+        if !commNonces.add(f.sender, f.nonce, f.time) {
+            return nil, errors.New("replayed frame")
+        }
+    }
+    return f, nil
+}
+
+// 记录数据帧的随机数，随机数在有效时间内已经存在时返回false。
+// 超过有效时间的数据帧会被拒绝，因此只需要保留有效时间内的随机数。
+func (c *nonceCache) add(sender int, nonce uint64, frameTime int64) bool {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    now := time.Now().UnixNano()
+    if now - c.cleared > int64(gPROC_COMM_AUTH_EXPIRE) {
+        for k, t := range c.nonces {
+            if now - t > int64(gPROC_COMM_AUTH_EXPIRE) {
+                delete(c.nonces, k)
+            }
+        }
+        c.cleared = now
+    }
+    key := nonceKey{sender : sender, nonce : nonce}
+    if _, ok := c.nonces[key]; ok {
+        return false
+    }
+    c.nonces[key] = frameTime
+    return true
+}
+
+// 使用共享密钥计算数据帧签名，未设置密钥时返回nil
+func signFrame(header []byte, data []byte) []byte {
+    secret := commSecret.Val()
+    if secret == "" {
+        return nil
+    }
+    h := hmac.New(sha256.New, []byte(secret))
+    h.Write(header)
+    h.Write(data)
+    return h.Sum(nil)
+}
@@ -11,12 +11,12 @@ package gproc
 
 import (
     "fmt"
+    "io"
     "net"
+    "os"
     "github.com/gogf/gf/g/os/glog"
-    "github.com/gogf/gf/g/net/gtcp"
     "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/util/gconv"
-    "github.com/gogf/gf/g/encoding/gbinary"
     "github.com/gogf/gf/g/container/gqueue"
     "github.com/gogf/gf/g/container/gtype"
 )
@@ -27,16 +27,18 @@ const (
 )
 
 var (
-    // 是否已开启TCP端口监听服务
-    tcpListened = gtype.NewBool()
+    // 是否已开启进程间通信监听服务
+    commListened = gtype.NewBool()
 )
 
 // 获取其他进程传递到当前进程的消息包，阻塞执行。
-// 进程只有在执行该方法后才会打开请求端口，默认情况下不允许进程间通信。
+// 进程只有在执行该方法后才会打开通信监听服务，默认情况下不允许进程间通信。
+// 优先使用Unix Socket进行通信(仅当前用户可访问)，系统不支持时使用本地TCP端口。
+// 通过Request发送的请求消息需要使用Msg.Reply返回回复数据。
 func Receive(group...string) *Msg {
     // 一个进程只能开启一个监听goroutine
-    if tcpListened.Set(true) == false {
-        go startTcpListening()
+    if commListened.Set(true) == false {
+        go startCommListening()
     }
     queue     := (*gqueue.Queue)(nil)
     groupName := gPROC_COMM_DEAFULT_GRUOP_NAME
@@ -62,101 +64,109 @@ func Receive(group...string) *Msg {
     return nil
 }
 
-// 创建本地进程TCP通信服务
-func startTcpListening() {
-    var listen *net.TCPListener
-    for i := gPROC_DEFAULT_TCP_PORT; ; i++ {
-        addr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("127.0.0.1:%d", i))
-        if err != nil {
-            continue
-        }
-        listen, err = net.ListenTCP("tcp", addr)
-        if err != nil {
-            continue
-        }
-        // 将监听的端口保存到通信文件中(字符串类型存放)
-        gfile.PutContents(getCommFilePath(Pid()), gconv.String(i))
-        break
+// 创建本地进程通信服务，优先使用Unix Socket，失败时使用TCP端口
+func startCommListening() {
+    listen, err := listenUnix()
+    if err != nil {
+        listen = listenTcp()
     }
     for  {
         if conn, err := listen.Accept(); err != nil {
             glog.Error(err)
         } else if conn != nil {
-            go tcpServiceHandler(gtcp.NewConnByNetConn(conn))
+            go commServiceHandler(&commConn{conn : conn})
         }
     }
 }
 
-// TCP数据通信处理回调函数
-func tcpServiceHandler(conn *gtcp.Conn) {
-    retry := gtcp.Retry {
-        Count   : 3,
-        Interval: 10,
+// 创建Unix Socket监听，仅允许当前用户的进程访问。
+// 通信文件创建在权限为0700的私有目录中，避免net.Listen创建文件之后、修改权限之前的时间窗口内被其他用户连接。
+func listenUnix() (net.Listener, error) {
+    dir  := getCommSockDirPath(Pid())
+    path := getCommSockPath(Pid())
+    if err := gfile.Mkdir(getCommDirPath()); err != nil {
+        return nil, err
     }
-    for {
-        var result []byte
-        buffer, err := conn.Recv(-1, retry)
-        if len(buffer) > 0 {
-            var msgs []*Msg
-            for _, msg := range bufferToMsgs(buffer) {
-                if v := commReceiveQueues.Get(msg.Group); v != nil {
-                    msgs = append(msgs, msg)
-                } else {
-                    result = []byte(fmt.Sprintf("group [%s] does not exist", msg.Group))
-                    break
-                }
-            }
-            // 成功时会返回ok给peer
-            if len(result) == 0 {
-                result = []byte("ok")
-                for _, msg := range msgs {
-                    if v := commReceiveQueues.Get(msg.Group); v != nil {
-                        v.(*gqueue.Queue).Push(msg)
-                    }
-                }
-            }
-        }
-        // 产生错误(或者对方已经关闭链接)时，退出接收循环
-        if err == nil {
-            conn.Send(result, retry)
-        } else {
-            conn.Close()
-            return
-        }
+    // 删除同PID旧进程残留的通信目录，私有目录必须由当前进程新建(已存在时说明被抢先创建，返回错误)
+    os.RemoveAll(dir)
+    if err := os.Mkdir(dir, 0700); err != nil {
+        return nil, err
+    }
+    listen, err := net.Listen("unix", path)
+    if err != nil {
+        return nil, err
+    }
+    if err := os.Chmod(path, 0600); err != nil {
+        listen.Close()
+        return nil, err
     }
+    return listen, nil
 }
 
-// 数据解包，防止黏包
-// 数据格式：总长度(24bit)|发送进程PID(24bit)|接收进程PID(24bit)|分组长度(8bit)|分组名称(变长)|校验(32bit)|参数(变长)
-func bufferToMsgs(buffer []byte) []*Msg {
-    s    := 0
-    msgs := make([]*Msg, 0)
-    for s < len(buffer) {
-        // 长度解析及校验
-        length := gbinary.DecodeToInt(buffer[s : s + 3])
-        if length < 14 || length > len(buffer) {
-            s++
+// 创建本地TCP端口监听，端口被占用时递增
+func listenTcp() net.Listener {
+    for i := gPROC_DEFAULT_TCP_PORT; ; i++ {
+        listen, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", i))
+        if err != nil {
             continue
         }
-        // 分组信息解析
-        groupLen  := gbinary.DecodeToInt(buffer[s + 9 : s + 10])
-        // checksum校验(仅对参数做校验，提高校验效率)
-        checksum1 := gbinary.DecodeToUint32(buffer[s + 10 + groupLen : s + 10 + groupLen + 4])
-        checksum2 := gtcp.Checksum(buffer[s + 10 + groupLen + 4 : s + length])
-        if checksum1 != checksum2 {
-            s++
+        // 将监听的端口保存到通信文件中(字符串类型存放)
+        gfile.PutContents(getCommFilePath(Pid()), gconv.String(i))
+        return listen
+    }
+}
+
+// 进程间通信数据处理回调函数，数据帧校验或者认证失败时返回错误信息并关闭连接
+func commServiceHandler(conn *commConn) {
+    defer conn.conn.Close()
+    for {
+        f, err := readFrame(conn.conn)
+        if err != nil {
+            // 对方已经关闭链接时，直接退出接收循环
+            if err != io.EOF {
+                conn.send(&commFrame{typ : gFRAME_TYPE_ERROR, sender : Pid(), data : []byte(err.Error())})
+            }
+            return
+        }
+        reply := &commFrame {
+            typ      : gFRAME_TYPE_ACK,
+            seq      : f.seq,
+            sender   : Pid(),
+            receiver : f.sender,
+            group    : f.group,
+        }
+        queue := commReceiveQueues.Get(f.group)
+        switch {
+            case f.receiver != Pid():
+                reply.typ  = gFRAME_TYPE_ERROR
+                reply.data = []byte(fmt.Sprintf("pid [%d] does not match", f.receiver))
+            case f.typ != gFRAME_TYPE_SEND && f.typ != gFRAME_TYPE_REQUEST:
+                reply.typ  = gFRAME_TYPE_ERROR
+                reply.data = []byte(fmt.Sprintf("invalid frame type: %d", f.typ))
+            case queue == nil:
+                reply.typ  = gFRAME_TYPE_ERROR
+                reply.data = []byte(fmt.Sprintf("group [%s] does not exist", f.group))
+        }
+        if reply.typ == gFRAME_TYPE_ERROR {
+            conn.send(reply)
             continue
         }
-        // 接收进程PID校验
-        if Pid() ==  gbinary.DecodeToInt(buffer[s + 6 : s + 9]) {
-            msgs = append(msgs, &Msg {
-                Pid   : gbinary.DecodeToInt(buffer[s + 3 : s + 6]),
-                Data  : buffer[s + 10 + groupLen + 4 : s + length],
-                Group : string(buffer[s + 10 : s + 10 + groupLen]),
-            })
+        msg := &Msg {
+            Pid   : f.sender,
+            Data  : f.data,
+            Group : f.group,
+        }
+        // 请求消息由接收方通过Msg.Reply回复，发送消息直接返回确认
+        if f.typ == gFRAME_TYPE_REQUEST {
+            msg.seq     = f.seq
+            msg.conn    = conn
+            msg.replied = gtype.NewBool()
+            queue.(*gqueue.Queue).Push(msg)
+        } else {
+            queue.(*gqueue.Queue).Push(msg)
+            if err := conn.send(reply); err != nil {
+                return
+            }
         }
-        s += length
     }
-    return msgs
 }
-
@@ -7,77 +7,103 @@
 package gproc
 
 import (
-    "bytes"
     "errors"
     "fmt"
-    "github.com/gogf/gf/g/encoding/gbinary"
-    "github.com/gogf/gf/g/net/gtcp"
+    "github.com/gogf/gf/g/container/gtype"
     "github.com/gogf/gf/g/os/gfcache"
+    "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/os/glog"
     "github.com/gogf/gf/g/util/gconv"
-    "io"
+    "net"
     "time"
 )
 
 const (
-    gPROC_COMM_FAILURE_RETRY_COUNT   = 3    // 失败重试次数
-    gPROC_COMM_FAILURE_RETRY_TIMEOUT = 1000 // (毫秒)失败重试间隔
-    gPROC_COMM_SEND_TIMEOUT          = 5000 // (毫秒)发送超时时间
-    gPROC_COMM_DEAFULT_GRUOP_NAME    = ""   // 默认分组名称
+    gPROC_COMM_FAILURE_RETRY_COUNT   = 3     // 失败重试次数
+    gPROC_COMM_FAILURE_RETRY_TIMEOUT = 1000  // (毫秒)失败重试间隔
+    gPROC_COMM_SEND_TIMEOUT          = 5000  // (毫秒)发送超时时间
+    gPROC_COMM_REQUEST_TIMEOUT       = 10000 // (毫秒)请求默认超时时间
+    gPROC_COMM_DEAFULT_GRUOP_NAME    = ""    // 默认分组名称
 )
 
-// 向指定gproc进程发送数据.
-// 数据格式：总长度(24bit)|发送进程PID(24bit)|接收进程PID(24bit)|分组长度(8bit)|分组名称(变长)|校验(32bit)|参数(变长)
+var (
+    // 数据帧序号
+    commSeq = gtype.NewUint32()
+)
+
+// 向指定gproc进程发送数据，等待对方确认接收后返回。
 func Send(pid int, data []byte, group...string) error {
+    _, err := doRequest(pid, gFRAME_TYPE_SEND, data, gPROC_COMM_SEND_TIMEOUT*time.Millisecond, group...)
+    return err
+}
+
+// 向指定gproc进程发送请求数据，并阻塞等待对方通过Msg.Reply返回的回复数据，默认超时时间为10秒。
+func Request(pid int, data []byte, group...string) ([]byte, error) {
+    return RequestWithTimeout(pid, data, gPROC_COMM_REQUEST_TIMEOUT*time.Millisecond, group...)
+}
+
+// 向指定gproc进程发送请求数据，并阻塞等待对方的回复数据，超时后返回错误。
+func RequestWithTimeout(pid int, data []byte, timeout time.Duration, group...string) ([]byte, error) {
+    return doRequest(pid, gFRAME_TYPE_REQUEST, data, timeout, group...)
+}
+
+// 执行发送流程，仅在建立连接失败时进行重试(防止对方重复接收)
+func doRequest(pid int, typ int, data []byte, timeout time.Duration, group...string) ([]byte, error) {
     groupName := gPROC_COMM_DEAFULT_GRUOP_NAME
     if len(group) > 0 {
         groupName = group[0]
     }
-    buffer := make([]byte, 0)
-    buffer  = append(buffer, gbinary.EncodeByLength(3, len(groupName) + len(data) + 14)...)
-    buffer  = append(buffer, gbinary.EncodeByLength(3, Pid())...)
-    buffer  = append(buffer, gbinary.EncodeByLength(3, pid)...)
-    buffer  = append(buffer, gbinary.EncodeByLength(1, len(groupName))...)
-    buffer  = append(buffer, []byte(groupName)...)
-    buffer  = append(buffer, gbinary.EncodeUint32(gtcp.Checksum(data))...)
-    buffer  = append(buffer, data...)
-    // 执行发送流程
     var err  error
-    var buf  []byte
-    var conn *gtcp.Conn
+    var conn net.Conn
     for i := gPROC_COMM_FAILURE_RETRY_COUNT; i > 0; i-- {
         if conn, err = getConnByPid(pid); err == nil {
-            defer conn.Close()
-            buf, err = conn.SendRecvWithTimeout(buffer, -1, gPROC_COMM_SEND_TIMEOUT*time.Millisecond)
-            if len(buf) > 0 {
-                // 如果有返回值，如果不是"ok"，那么表示是错误信息
-                if !bytes.EqualFold(buf, []byte("ok")) {
-                    err = errors.New(string(buf))
-                    break
-                }
-            }
-            // EOF不算异常错误
-            if err == nil || err == io.EOF {
-                break
-            } else {
-                glog.Error(err)
-            }
+            break
         }
+        glog.Error(err)
         time.Sleep(gPROC_COMM_FAILURE_RETRY_TIMEOUT*time.Millisecond)
     }
-    return err
+    if err != nil {
+        return nil, err
+    }
+    defer conn.Close()
+    request := &commFrame {
+        typ      : typ,
+        seq      : commSeq.Add(1),
+        sender   : Pid(),
+        receiver : pid,
+        group    : groupName,
+        data     : data,
+    }
+    if err := (&commConn{conn : conn}).send(request); err != nil {
+        return nil, err
+    }
+    conn.SetReadDeadline(time.Now().Add(timeout))
+    for {
+        f, err := readFrame(conn)
+        if err != nil {
+            return nil, err
+        }
+        switch f.typ {
+            case gFRAME_TYPE_ERROR:
+                return nil, errors.New(string(f.data))
+            case gFRAME_TYPE_ACK, gFRAME_TYPE_REPLY:
+                // 忽略序号不匹配的数据帧
+                if f.seq == request.seq && f.sender == pid {
+                    return f.data, nil
+                }
+        }
+    }
 }
 
-
-// 获取指定进程的TCP通信对象
-func getConnByPid(pid int) (*gtcp.Conn, error) {
+// 获取指定进程的通信对象，优先使用Unix Socket
+func getConnByPid(pid int) (net.Conn, error) {
+    timeout := gPROC_COMM_SEND_TIMEOUT*time.Millisecond
+    if path := getCommSockPath(pid); gfile.Exists(path) {
+        return net.DialTimeout("unix", path, timeout)
+    }
     port := getPortByPid(pid)
     if port > 0 {
-        if conn, err := gtcp.NewConn(fmt.Sprintf("127.0.0.1:%d", port)); err == nil {
-            return conn, nil
-        } else {
-            return nil, err
-        }
+        return net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), timeout)
     }
     return nil, errors.New(fmt.Sprintf("could not find port for pid: %d" , pid))
 }
@@ -87,4 +113,4 @@ func getPortByPid(pid int) int {
     path    := getCommFilePath(pid)
     content := gfcache.GetContents(path)
     return gconv.Int(content)
-}
\ No newline at end of file
+}
@@ -0,0 +1,411 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gproc
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "runtime"
+    "sync"
+    "time"
+)
+
+const (
+    RESTART_NEVER      = 0 // 重启策略：不重启(默认)
+    RESTART_ALWAYS     = 1 // 重启策略：进程退出后总是重启
+    RESTART_ON_FAILURE = 2 // 重启策略：进程异常退出(退出码非0或者被信号终止)后重启
+)
+
+const (
+    EVENT_START     = "start"     // 生命周期事件：进程启动
+    EVENT_EXIT      = "exit"      // 生命周期事件：进程退出
+    EVENT_RESTART   = "restart"   // 生命周期事件：进程即将重启
+    EVENT_UNHEALTHY = "unhealthy" // 生命周期事件：健康检查连续失败，进程将被终止
+    EVENT_GIVEUP    = "giveup"    // 生命周期事件：超过最大重试次数，放弃重启
+    EVENT_STOP      = "stop"      // 生命周期事件：进程被Supervisor停止
+)
+
+const (
+    gSUPERVISOR_DEFAULT_BACKOFF         = time.Second      // 默认的初始重启间隔
+    gSUPERVISOR_DEFAULT_MAX_BACKOFF     = 30*time.Second   // 默认的最大重启间隔
+    gSUPERVISOR_DEFAULT_HEALTH_INTERVAL = 10*time.Second   // 默认的健康检查间隔
+    gSUPERVISOR_DEFAULT_HEALTH_FAILURES = 3                // 默认的健康检查连续失败次数阈值
+    gSUPERVISOR_DEFAULT_STOP_TIMEOUT    = 10*time.Second   // 默认的停止等待时间，超时后强制终止
+)
+
+// 进程守护管理器，管理子进程的启动、重启、健康检查及停止
+type Supervisor struct {
+    mu       sync.RWMutex
+    programs map[string]*Program              // 注册的守护进程(键名为名称)
+    handlers []func(event *SupervisorEvent)   // 生命周期事件处理方法
+}
+
+// 守护进程配置及运行状态
+type Program struct {
+    Name           string                  // 名称(唯一)
+    Path           string                  // 可执行文件路径
+    Args           []string                // 执行参数
+    Env            []string                // 环境变量，为空时使用当前进程的环境变量
+    Dir            string                  // 工作目录，为空时使用当前工作目录
+    Stdout         io.Writer               // 标准输出，为空时使用当前进程的标准输出
+    Stderr         io.Writer               // 标准错误，为空时使用当前进程的标准错误
+    Restart        int                     // 重启策略，RESTART_NEVER/RESTART_ALWAYS/RESTART_ON_FAILURE
+    MaxRetries     int                     // 最大连续重启次数，0表示不限制
+    Backoff        time.Duration           // 初始重启间隔(默认1秒)，连续重启时每次翻倍
+    MaxBackoff     time.Duration           // 最大重启间隔(默认30秒)，进程运行时间超过该值后重置重启间隔及重试次数
+    HealthCheck    func(p *Process) error  // 健康检查方法，返回error表示检查失败
+    HealthInterval time.Duration           // 健康检查间隔(默认10秒)
+    HealthFailures int                     // 健康检查连续失败多少次后终止进程(默认3次)，终止后按照重启策略处理
+    StopTimeout    time.Duration           // 停止进程时等待进程退出的时间(默认10秒)，超时后强制终止
+
+    mu             sync.RWMutex
+    supervisor     *Supervisor             // 所属管理器
+    process        *Process                // 当前运行的进程
+    restarts       int                     // 累计重启次数
+    running        bool                    // 守护goroutine是否正在运行
+    stopChan       chan struct{}           // 停止信号
+    doneChan       chan struct{}           // 守护goroutine结束信号
+}
+
+// 生命周期事件
+type SupervisorEvent struct {
+    Name     string    // 守护进程名称
+    Type     string    // 事件类型(EVENT_*)
+    Pid      int       // 进程ID
+    Err      error     // 进程启动/退出/健康检查的错误信息
+    Restarts int       // 累计重启次数
+    Time     time.Time // 事件时间
+}
+
+// 创建进程守护管理器
+func NewSupervisor() *Supervisor {
+    return &Supervisor {
+        programs : make(map[string]*Program),
+    }
+}
+
+// 注册守护进程(不启动)
+func (s *Supervisor) Add(program *Program) error {
+    if program.Name == "" || program.Path == "" {
+        return errors.New("program name and path cannot be empty")
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if _, ok := s.programs[program.Name]; ok {
+        return errors.New(fmt.Sprintf(`program "%s" already exists`, program.Name))
+    }
+    program.supervisor = s
+    s.programs[program.Name] = program
+    return nil
+}
+
+// 获取指定名称的守护进程
+func (s *Supervisor) Get(name string) *Program {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.programs[name]
+}
+
+// 停止并移除指定名称的守护进程
+func (s *Supervisor) Remove(name string) {
+    if p := s.Get(name); p != nil {
+        p.Stop()
+        s.mu.Lock()
+        delete(s.programs, name)
+        s.mu.Unlock()
+    }
+}
+
+// 添加生命周期事件处理方法
+func (s *Supervisor) OnEvent(handler func(event *SupervisorEvent)) {
+    s.mu.Lock()
+    s.handlers = append(s.handlers, handler)
+    s.mu.Unlock()
+}
+
+// 启动指定名称的守护进程
+func (s *Supervisor) Start(name string) error {
+    if p := s.Get(name); p != nil {
+        return p.Start()
+    }
+    return errors.New(fmt.Sprintf(`program "%s" not found`, name))
+}
+
+// 停止指定名称的守护进程(不再重启)
+func (s *Supervisor) Stop(name string) error {
+    if p := s.Get(name); p != nil {
+        p.Stop()
+        return nil
+    }
+    return errors.New(fmt.Sprintf(`program "%s" not found`, name))
+}
+
+// 启动所有的守护进程
+func (s *Supervisor) StartAll() {
+    for _, p := range s.Programs() {
+        p.Start()
+    }
+}
+
+// 停止所有的守护进程
+func (s *Supervisor) StopAll() {
+    for _, p := range s.Programs() {
+        p.Stop()
+    }
+}
+
+// 获取所有的守护进程
+func (s *Supervisor) Programs() []*Program {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    programs := make([]*Program, 0, len(s.programs))
+    for _, p := range s.programs {
+        programs = append(programs, p)
+    }
+    return programs
+}
+
+// 触发生命周期事件
+func (s *Supervisor) emit(event *SupervisorEvent) {
+    s.mu.RLock()
+    handlers := s.handlers
+    s.mu.RUnlock()
+    for _, handler := range handlers {
+        handler(event)
+    }
+}
+
+// 启动守护进程，已经启动时直接返回
+func (p *Program) Start() error {
+    if p.supervisor == nil {
+        return errors.New(fmt.Sprintf(`program "%s" is not added to supervisor`, p.Name))
+    }
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.running {
+        return nil
+    }
+    p.running  = true
+    p.stopChan = make(chan struct{})
+    p.doneChan = make(chan struct{})
+    go p.loop(p.stopChan, p.doneChan)
+    return nil
+}
+
+// 停止守护进程，先发送中断信号，超过StopTimeout后强制终止，阻塞等待进程退出
+func (p *Program) Stop() {
+    p.mu.Lock()
+    if !p.running {
+        p.mu.Unlock()
+        return
+    }
+    p.running = false
+    close(p.stopChan)
+    done    := p.doneChan
+    process := p.process
+    p.mu.Unlock()
+    if process != nil {
+        if runtime.GOOS == "windows" {
+            process.Kill()
+        } else {
+            process.Signal(os.Interrupt)
+        }
+        select {
+            case <- done:
+            case <- time.After(p.stopTimeout()):
+                process.Kill()
+        }
+    }
+    <- done
+}
+
+// 当前运行的进程ID，没有运行时返回0
+func (p *Program) Pid() int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    if p.process != nil {
+        return p.process.Pid()
+    }
+    return 0
+}
+
+// 守护进程是否处于运行状态(包括等待重启)
+func (p *Program) IsRunning() bool {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.running
+}
+
+// 累计重启次数
+func (p *Program) Restarts() int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.restarts
+}
+
+// 守护goroutine，运行进程并按照重启策略进行重启
+func (p *Program) loop(stopChan, doneChan chan struct{}) {
+    defer close(doneChan)
+    retries := 0
+    backoff := p.Backoff
+    if backoff <= 0 {
+        backoff = gSUPERVISOR_DEFAULT_BACKOFF
+    }
+    maxBackoff := p.MaxBackoff
+    if maxBackoff <= 0 {
+        maxBackoff = gSUPERVISOR_DEFAULT_MAX_BACKOFF
+    }
+    for {
+        startTime := time.Now()
+        err       := p.runOnce(stopChan)
+        select {
+            case <- stopChan:
+                p.emit(EVENT_STOP, 0, nil)
+                return
+            default:
+        }
+        if p.Restart == RESTART_NEVER || (p.Restart == RESTART_ON_FAILURE && err == nil) {
+            p.finish()
+            return
+        }
+        // 进程运行时间足够长，认为已恢复正常，重置重启间隔及重试次数
+        if time.Since(startTime) >= maxBackoff {
+            retries = 0
+            backoff = p.Backoff
+            if backoff <= 0 {
+                backoff = gSUPERVISOR_DEFAULT_BACKOFF
+            }
+        }
+        retries++
+        if p.MaxRetries > 0 && retries > p.MaxRetries {
+            p.emit(EVENT_GIVEUP, 0, err)
+            p.finish()
+            return
+        }
+        select {
+            case <- stopChan:
+                p.emit(EVENT_STOP, 0, nil)
+                return
+            case <- time.After(backoff):
+        }
+        if backoff *= 2; backoff > maxBackoff {
+            backoff = maxBackoff
+        }
+        p.mu.Lock()
+        p.restarts++
+        p.mu.Unlock()
+        p.emit(EVENT_RESTART, 0, err)
+    }
+}
+
+// 运行一次进程，阻塞等待进程退出，返回进程启动或者退出的错误
+func (p *Program) runOnce(stopChan chan struct{}) error {
+    process := (*Process)(nil)
+    if p.Env != nil {
+        process = NewProcess(p.Path, p.Args, p.Env)
+    } else {
+        process = NewProcess(p.Path, p.Args)
+    }
+    if p.Dir != "" {
+        process.Dir = p.Dir
+    }
+    if p.Stdout != nil {
+        process.Stdout = p.Stdout
+    }
+    if p.Stderr != nil {
+        process.Stderr = p.Stderr
+    }
+    process.Stdin = nil
+    p.mu.Lock()
+    select {
+        case <- stopChan:
+            p.mu.Unlock()
+            return nil
+        default:
+    }
+    pid, err := process.Start()
+    if err != nil {
+        p.mu.Unlock()
+        p.emit(EVENT_EXIT, 0, err)
+        return err
+    }
+    p.process = process
+    p.mu.Unlock()
+    p.emit(EVENT_START, pid, nil)
+    // 健康检查
+    healthDone := make(chan struct{})
+    if p.HealthCheck != nil {
+        go p.checkHealth(process, healthDone)
+    }
+    err = process.Wait()
+    close(healthDone)
+    p.mu.Lock()
+    p.process = nil
+    p.mu.Unlock()
+    p.emit(EVENT_EXIT, pid, err)
+    return err
+}
+
+// 定时执行健康检查，连续失败达到阈值后终止进程
+func (p *Program) checkHealth(process *Process, done chan struct{}) {
+    interval := p.HealthInterval
+    if interval <= 0 {
+        interval = gSUPERVISOR_DEFAULT_HEALTH_INTERVAL
+    }
+    threshold := p.HealthFailures
+    if threshold <= 0 {
+        threshold = gSUPERVISOR_DEFAULT_HEALTH_FAILURES
+    }
+    ticker   := time.NewTicker(interval)
+    failures := 0
+    defer ticker.Stop()
+    for {
+        select {
+            case <- done:
+                return
+            case <- ticker.C:
+                if err := p.HealthCheck(process); err != nil {
+                    if failures++; failures >= threshold {
+                        p.emit(EVENT_UNHEALTHY, process.Pid(), err)
+                        process.Kill()
+                        return
+                    }
+                } else {
+                    failures = 0
+                }
+        }
+    }
+}
+
+// 守护goroutine正常结束(不再重启)
+func (p *Program) finish() {
+    p.mu.Lock()
+    p.running = false
+    p.mu.Unlock()
+}
+
+// 停止进程时等待进程退出的时间
+func (p *Program) stopTimeout() time.Duration {
+    if p.StopTimeout > 0 {
+        return p.StopTimeout
+    }
+    return gSUPERVISOR_DEFAULT_STOP_TIMEOUT
+}
+
+// 触发生命周期事件
+func (p *Program) emit(eventType string, pid int, err error) {
+    p.supervisor.emit(&SupervisorEvent {
+        Name     : p.Name,
+        Type     : eventType,
+        Pid      : pid,
+        Err      : err,
+        Restarts : p.Restarts(),
+        Time     : time.Now(),
+    })
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试(数据帧)
+
+package gproc
+
+import (
+    "bytes"
+    "github.com/gogf/gf/g/encoding/gbinary"
+    "github.com/gogf/gf/g/test/gtest"
+    "hash/crc32"
+    "testing"
+    "time"
+)
+
+func Test_Frame(t *testing.T) {
+    gtest.Case(t, func() {
+        defer SetCommSecret("")
+        frame := &commFrame {
+            typ      : gFRAME_TYPE_REQUEST,
+            seq      : 100,
+            time     : time.Now().UnixNano(),
+            sender   : 1,
+            receiver : 2,
+            group    : "group",
+            data     : []byte("data"),
+        }
+        for _, secret := range []string{"", "secret"} {
+            SetCommSecret(secret)
+            buffer, err := encodeFrame(frame)
+            gtest.Assert(err, nil)
+            f, err := readFrame(bytes.NewReader(buffer))
+            gtest.Assert(err, nil)
+            gtest.Assert(f.typ,      frame.typ)
+            gtest.Assert(f.seq,      frame.seq)
+            gtest.Assert(f.time,     frame.time)
+            gtest.Assert(f.sender,   frame.sender)
+            gtest.Assert(f.receiver, frame.receiver)
+            gtest.Assert(f.group,    frame.group)
+            gtest.Assert(f.data,     frame.data)
+        }
+        // 签名错误
+        SetCommSecret("secret1")
+        buffer, _ := encodeFrame(frame)
+        SetCommSecret("secret2")
+        _, err := readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "authentication failed")
+        // 未签名
+        SetCommSecret("")
+        buffer, _ = encodeFrame(frame)
+        SetCommSecret("secret")
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "authentication failed")
+        // 数据损坏
+        buffer, _ = encodeFrame(frame)
+        buffer[len(buffer) - 1]++
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "invalid frame checksum")
+        // 重放
+        buffer, _ = encodeFrame(frame)
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err, nil)
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "replayed frame")
+        // 不支持的版本
+        buffer, _ = encodeFrame(frame)
+        buffer[8] = gPROC_COMM_FRAME_VERSION + 1
+        copy(buffer[4 : 8], gbinary.BeEncodeUint32(crc32.ChecksumIEEE(buffer[8 : ])))
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "unsupported frame version: 2")
+        // 过期
+        frame.time = time.Now().Add(-time.Hour).UnixNano()
+        buffer, _ = encodeFrame(frame)
+        _, err = readFrame(bytes.NewReader(buffer))
+        gtest.Assert(err.Error(), "authentication expired")
+    })
+}
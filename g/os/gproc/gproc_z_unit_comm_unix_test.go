@@ -0,0 +1,46 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build !windows
+
+// 单元测试(Unix Socket通信文件)
+
+package gproc
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/test/gtest"
+    "net"
+    "os"
+    "testing"
+)
+
+func Test_ListenUnix(t *testing.T) {
+    gtest.Case(t, func() {
+        tempDir := gfile.TempDir() + gfile.Separator + "gproc_listen_unix_test"
+        gtest.Assert(gfile.Mkdir(tempDir), nil)
+        defer os.RemoveAll(tempDir)
+        defer os.Setenv(gPROC_TEMP_DIR_ENV_KEY, os.Getenv(gPROC_TEMP_DIR_ENV_KEY))
+        os.Setenv(gPROC_TEMP_DIR_ENV_KEY, tempDir)
+
+        // 同PID旧进程残留的通信目录会被删除后重新创建
+        gtest.Assert(os.MkdirAll(getCommSockDirPath(Pid()), 0777), nil)
+        listen, err := listenUnix()
+        gtest.Assert(err, nil)
+        defer listen.Close()
+
+        info, err := os.Stat(getCommSockDirPath(Pid()))
+        gtest.Assert(err, nil)
+        gtest.Assert(info.Mode().Perm(), os.FileMode(0700))
+        info, err = os.Stat(getCommSockPath(Pid()))
+        gtest.Assert(err, nil)
+        gtest.Assert(info.Mode().Perm(), os.FileMode(0600))
+
+        conn, err := net.Dial("unix", getCommSockPath(Pid()))
+        gtest.Assert(err, nil)
+        conn.Close()
+    })
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// +build !windows
+
+// 单元测试
+
+package gproc_test
+
+import (
+    "errors"
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gproc"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestSupervisor_OnFailure(t *testing.T) {
+    gtest.Case(t, func() {
+        events     := garray.NewStringArray()
+        supervisor := gproc.NewSupervisor()
+        supervisor.OnEvent(func(event *gproc.SupervisorEvent) {
+            events.Append(event.Type)
+        })
+        gtest.Assert(supervisor.Add(&gproc.Program {
+            Name       : "fail",
+            Path       : "/bin/sh",
+            Args       : []string{"-c", "exit 1"},
+            Restart    : gproc.RESTART_ON_FAILURE,
+            MaxRetries : 2,
+            Backoff    : 10*time.Millisecond,
+        }), nil)
+        gtest.AssertNE(supervisor.Add(&gproc.Program{Name : "fail", Path : "/bin/sh"}), nil)
+        gtest.Assert(supervisor.Start("fail"), nil)
+        time.Sleep(500*time.Millisecond)
+        gtest.Assert(events.Slice(), []string{
+            "start", "exit", "restart",
+            "start", "exit", "restart",
+            "start", "exit", "giveup",
+        })
+        gtest.Assert(supervisor.Get("fail").Restarts(), 2)
+        gtest.Assert(supervisor.Get("fail").IsRunning(), false)
+    })
+}
+
+func TestSupervisor_AlwaysAndStop(t *testing.T) {
+    gtest.Case(t, func() {
+        events     := garray.NewStringArray()
+        supervisor := gproc.NewSupervisor()
+        supervisor.OnEvent(func(event *gproc.SupervisorEvent) {
+            events.Append(event.Type)
+        })
+        supervisor.Add(&gproc.Program {
+            Name        : "sleep",
+            Path        : "/bin/sh",
+            Args        : []string{"-c", "sleep 10"},
+            Restart     : gproc.RESTART_ALWAYS,
+            StopTimeout : 100*time.Millisecond,
+        })
+        supervisor.StartAll()
+        time.Sleep(200*time.Millisecond)
+        gtest.AssertGT(supervisor.Get("sleep").Pid(), 0)
+        supervisor.StopAll()
+        gtest.Assert(supervisor.Get("sleep").Pid(), 0)
+        gtest.Assert(events.Slice(), []string{"start", "exit", "stop"})
+    })
+}
+
+func TestSupervisor_HealthCheck(t *testing.T) {
+    gtest.Case(t, func() {
+        events     := garray.NewStringArray()
+        supervisor := gproc.NewSupervisor()
+        supervisor.OnEvent(func(event *gproc.SupervisorEvent) {
+            events.Append(event.Type)
+        })
+        supervisor.Add(&gproc.Program {
+            Name           : "unhealthy",
+            Path           : "/bin/sh",
+            Args           : []string{"-c", "sleep 10"},
+            Restart        : gproc.RESTART_ON_FAILURE,
+            Backoff        : 10*time.Millisecond,
+            HealthInterval : 20*time.Millisecond,
+            HealthFailures : 2,
+            StopTimeout    : 100*time.Millisecond,
+            HealthCheck    : func(p *gproc.Process) error {
+                return errors.New("unhealthy")
+            },
+        })
+        supervisor.Start("unhealthy")
+        time.Sleep(150*time.Millisecond)
+        supervisor.Remove("unhealthy")
+        gtest.Assert(supervisor.Get("unhealthy"), nil)
+        gtest.Assert(events.Slice()[ : 5], []string{"start", "unhealthy", "exit", "restart", "start"})
+        gtest.Assert(events.Slice()[events.Len() - 1], "stop")
+    })
+}
+
+func TestComm_SendAndRequest(t *testing.T) {
+    gtest.Case(t, func() {
+        gproc.SetCommSecret("secret")
+        defer gproc.SetCommSecret("")
+        go func() {
+            for {
+                msg := gproc.Receive("test")
+                if msg.IsRequest() {
+                    gtest.Assert(msg.Reply(append([]byte("reply:"), msg.Data...)), nil)
+                    gtest.AssertNE(msg.Reply(nil), nil)
+                } else {
+                    gtest.AssertNE(msg.Reply(nil), nil)
+                }
+            }
+        }()
+        time.Sleep(100*time.Millisecond)
+        gtest.Assert(gproc.Send(gproc.Pid(), []byte("hello"), "test"), nil)
+        data, err := gproc.Request(gproc.Pid(), []byte("ping"), "test")
+        gtest.Assert(err, nil)
+        gtest.Assert(string(data), "reply:ping")
+        // 不存在的分组
+        _, err = gproc.Request(gproc.Pid(), []byte("ping"), "none")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestComm_RequestTimeout(t *testing.T) {
+    gtest.Case(t, func() {
+        go gproc.Receive("timeout")
+        time.Sleep(100*time.Millisecond)
+        _, err := gproc.RequestWithTimeout(gproc.Pid(), []byte("ping"), 100*time.Millisecond, "timeout")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestShellCmd(t *testing.T) {
+    gtest.Case(t, func() {
+        lines  := garray.NewStringArray()
+        result, err := gproc.ShellCmd(`echo -n $GPROC_TEST_KEY; echo " $(pwd)"; echo error >&2; printf "last"`, gproc.CmdOption {
+            Env    : map[string]string{"GPROC_TEST_KEY" : "value"},
+            Dir    : gfile.TempDir(),
+            Stdout : func(line string) {
+                lines.Append(line)
+            },
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(result.ExitCode, 0)
+        gtest.Assert(result.Stdout, "value " + gfile.RealPath(gfile.TempDir()) + "\nlast")
+        gtest.Assert(result.Stderr, "error\n")
+        gtest.Assert(lines.Slice(), []string{"value " + gfile.RealPath(gfile.TempDir()), "last"})
+
+        result, err = gproc.ShellCmd("exit 3")
+        gtest.AssertNE(err, nil)
+        gtest.Assert(result.ExitCode, 3)
+
+        result, err = gproc.Cmd("echo", []string{"a b", "c"})
+        gtest.Assert(err, nil)
+        gtest.Assert(result.Stdout, "a b c\n")
+    })
+}
+
+func TestShellCmd_Timeout(t *testing.T) {
+    gtest.Case(t, func() {
+        start := time.Now()
+        result, err := gproc.ShellCmd("sleep 10 & sleep 10", gproc.CmdOption {
+            Timeout : 100*time.Millisecond,
+        })
+        gtest.AssertNE(err, nil)
+        gtest.Assert(result.TimedOut, true)
+        gtest.Assert(result.ExitCode, -1)
+        gtest.Assert(time.Since(start) < 2*time.Second, true)
+
+        gtest.AssertNE(gproc.ShellRunWithTimeout("sleep 10", 100*time.Millisecond), nil)
+        gtest.Assert(gproc.ShellRunWithTimeout("exit 0", time.Second), nil)
+    })
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gres provides resource management for files packed into the binary.
+//
+// 资源管理，将任意文件/目录打包进可执行文件(生成的Go文件或者追加到可执行文件末尾的数据块)，
+// 并提供类似文件系统的访问接口，gview模板、gcfg配置文件以及ghttp静态文件在磁盘中不存在时自动从资源中查找。
+package gres
+
+var (
+    // 默认的资源管理对象
+    defaultResource = New()
+)
+
+func init() {
+    // 自动加载追加到当前可执行文件末尾的资源数据(如果存在)
+    defaultResource.loadExecutable()
+}
+
+// 添加打包后的资源内容(例如生成的Go文件中的数据)到默认的资源管理对象中，prefix为资源路径前缀(可选)
+func Add(content string, prefix...string) error {
+    return defaultResource.Add(content, prefix...)
+}
+
+// 加载打包后的资源文件到默认的资源管理对象中，prefix为资源路径前缀(可选)
+func Load(path string, prefix...string) error {
+    return defaultResource.Load(path, prefix...)
+}
+
+// 获取指定路径的资源文件(或者目录)，不存在时返回nil
+func Get(path string) *File {
+    return defaultResource.Get(path)
+}
+
+// 判断指定路径的资源文件(或者目录)是否存在
+func Contains(path string) bool {
+    return defaultResource.Contains(path)
+}
+
+// 判断默认的资源管理对象中是否没有任何资源
+func IsEmpty() bool {
+    return defaultResource.IsEmpty()
+}
+
+// 获取指定资源文件的内容，不存在或者为目录时返回nil
+func GetContent(path string) []byte {
+    return defaultResource.GetContent(path)
+}
+
+// 打开指定资源文件，返回的对象实现了http.File接口
+func Open(path string) (*Reader, error) {
+    return defaultResource.Open(path)
+}
+
+// 在指定资源目录中查找文件，indexFiles为目录的默认索引文件(可选)，例如: index.html
+func Search(dir string, name string, indexFiles...string) *File {
+    return defaultResource.Search(dir, name, indexFiles...)
+}
+
+// 获取指定资源目录下的文件列表，pattern支持多个文件名匹配模式(使用','分隔)，recursive表示是否递归获取
+func ScanDir(path string, pattern string, recursive...bool) []*File {
+    return defaultResource.ScanDir(path, pattern, recursive...)
+}
+
+// 获取默认的资源管理对象中所有的资源路径(已排序)
+func Paths() []string {
+    return defaultResource.Paths()
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gres
+
+import (
+    "archive/zip"
+    "bytes"
+    "io"
+    "io/ioutil"
+    "os"
+    "path"
+    "sync"
+)
+
+// 资源文件(或者目录)
+type File struct {
+    name     string     // 资源路径
+    file     *zip.File  // 底层数据
+    resource *Resource  // 所属资源管理对象
+    once     sync.Once
+    content  []byte     // 解压后的文件内容(首次读取时缓存)
+}
+
+// 资源文件读取对象，实现了http.File接口，可直接用于http.ServeContent等方法
+type Reader struct {
+    *bytes.Reader
+    file *File
+}
+
+// 创建资源目录对象(打包数据中不包含该目录条目时使用)
+func newDirFile(r *Resource, name string) *File {
+    header := &zip.FileHeader{Name : name + "/"}
+    header.SetMode(os.ModeDir|0755)
+    return &File {
+        name     : name,
+        file     : &zip.File{FileHeader : *header},
+        resource : r,
+    }
+}
+
+// 资源路径，例如: template/index.html
+func (f *File) Path() string {
+    return f.name
+}
+
+// 文件名称，例如: index.html
+func (f *File) Name() string {
+    return path.Base(f.name)
+}
+
+// 是否为目录
+func (f *File) IsDir() bool {
+    return f.file.FileInfo().IsDir()
+}
+
+// 文件信息
+func (f *File) Stat() os.FileInfo {
+    return f.file.FileInfo()
+}
+
+// 文件内容，目录或者读取失败时返回nil
+func (f *File) Content() []byte {
+    if f.IsDir() {
+        return nil
+    }
+    f.once.Do(func() {
+        reader, err := f.file.Open()
+        if err != nil {
+            return
+        }
+        defer reader.Close()
+        f.content, _ = ioutil.ReadAll(reader)
+    })
+    return f.content
+}
+
+// 打开文件，返回的对象实现了http.File接口
+func (f *File) Open() (*Reader, error) {
+    return &Reader {
+        Reader : bytes.NewReader(f.Content()),
+        file   : f,
+    }, nil
+}
+
+// 关闭文件(无需释放任何资源)
+func (r *Reader) Close() error {
+    return nil
+}
+
+// 文件信息
+func (r *Reader) Stat() (os.FileInfo, error) {
+    return r.file.Stat(), nil
+}
+
+// 获取目录下的文件信息列表，count <= 0表示获取所有
+func (r *Reader) Readdir(count int) ([]os.FileInfo, error) {
+    if !r.file.IsDir() {
+        return nil, &os.PathError{Op : "readdir", Path : r.file.name, Err : os.ErrInvalid}
+    }
+    files := r.file.resource.ScanDir(r.file.name, "*")
+    infos := make([]os.FileInfo, 0, len(files))
+    for _, file := range files {
+        infos = append(infos, file.Stat())
+    }
+    if count > 0 && len(infos) > count {
+        infos = infos[ : count]
+    }
+    if count > 0 && len(infos) == 0 {
+        return infos, io.EOF
+    }
+    return infos, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gres
+
+import (
+    "archive/zip"
+    "bytes"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/encoding/gbinary"
+    "github.com/gogf/gf/g/os/gfile"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+const (
+    // 追加到可执行文件末尾的资源数据标识，数据格式：资源数据|资源数据长度(64bit)|标识
+    gPACK_BINARY_MAGIC = "GFRES\x00\x01\x00"
+)
+
+// 打包文件/目录，srcPaths支持多个路径(使用','分隔)，目录以其名称作为资源路径的第一级，
+// 例如: Pack("public,template,config")打包后的资源路径为public/...、template/...以及config/...，
+// prefix为资源路径前缀(可选)。
+func Pack(srcPaths string, prefix...string) ([]byte, error) {
+    namePrefix := ""
+    if len(prefix) > 0 {
+        namePrefix = formatPath(prefix[0])
+    }
+    buffer := bytes.NewBuffer(nil)
+    writer := zip.NewWriter(buffer)
+    for _, srcPath := range strings.Split(srcPaths, ",") {
+        srcPath = strings.TrimSpace(srcPath)
+        if srcPath == "" {
+            continue
+        }
+        realPath := gfile.RealPath(srcPath)
+        if realPath == "" {
+            return nil, errors.New(fmt.Sprintf(`path "%s" does not exist`, srcPath))
+        }
+        if err := packPath(writer, realPath, namePrefix); err != nil {
+            return nil, err
+        }
+    }
+    if err := writer.Close(); err != nil {
+        return nil, err
+    }
+    return buffer.Bytes(), nil
+}
+
+// 打包文件/目录并保存为资源文件，可通过Load方法加载
+func PackToFile(srcPaths string, dstPath string, prefix...string) error {
+    data, err := Pack(srcPaths, prefix...)
+    if err != nil {
+        return err
+    }
+    return gfile.PutBinContents(dstPath, data)
+}
+
+// 打包文件/目录并生成Go文件，pkgName为生成文件的包名(默认为目标文件所在目录名称)，
+// 生成的Go文件在init方法中将资源添加到默认的资源管理对象中，编译后资源即打包进可执行文件。
+func PackToGoFile(srcPaths string, goFilePath string, pkgName string, prefix...string) error {
+    data, err := Pack(srcPaths, prefix...)
+    if err != nil {
+        return err
+    }
+    if pkgName == "" {
+        absPath, err := filepath.Abs(goFilePath)
+        if err != nil {
+            return err
+        }
+        pkgName = filepath.Base(filepath.Dir(absPath))
+    }
+    content := fmt.Sprintf(
+        "// Code generated by gres. DO NOT EDIT.\n\npackage %s\n\nimport \"github.com/gogf/gf/g/os/gres\"\n\n" +
+        "func init() {\n    if err := gres.Add(%q); err != nil {\n        panic(err)\n    }\n}\n",
+        pkgName, data,
+    )
+    return gfile.PutContents(goFilePath, content)
+}
+
+// 打包文件/目录并追加到指定可执行文件末尾，程序运行时自动加载
+func PackToBinary(srcPaths string, binaryPath string, prefix...string) error {
+    data, err := Pack(srcPaths, prefix...)
+    if err != nil {
+        return err
+    }
+    data = append(data, gbinary.BeEncodeInt64(int64(len(data)))...)
+    data = append(data, gPACK_BINARY_MAGIC...)
+    return gfile.PutBinContentsAppend(binaryPath, data)
+}
+
+// 打包单个文件/目录
+func packPath(writer *zip.Writer, realPath string, prefix string) error {
+    root := filepath.Dir(realPath)
+    return filepath.Walk(realPath, func(path string, info os.FileInfo, err error) error {
+        if err != nil {
+            return err
+        }
+        relative, err := filepath.Rel(root, path)
+        if err != nil {
+            return err
+        }
+        header, err := zip.FileInfoHeader(info)
+        if err != nil {
+            return err
+        }
+        header.Name = formatPath(prefix + "/" + filepath.ToSlash(relative))
+        if info.IsDir() {
+            header.Name += "/"
+            _, err = writer.CreateHeader(header)
+            return err
+        }
+        header.Method = zip.Deflate
+        w, err := writer.CreateHeader(header)
+        if err != nil {
+            return err
+        }
+        file, err := os.Open(path)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        _, err = io.Copy(w, file)
+        return err
+    })
+}
+
+// 加载追加到当前可执行文件末尾的资源数据
+func (r *Resource) loadExecutable() error {
+    path, err := os.Executable()
+    if err != nil {
+        return err
+    }
+    file, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    info, err := file.Stat()
+    if err != nil {
+        return err
+    }
+    footerSize := int64(8 + len(gPACK_BINARY_MAGIC))
+    if info.Size() < footerSize {
+        return nil
+    }
+    footer := make([]byte, footerSize)
+    if _, err := file.ReadAt(footer, info.Size() - footerSize); err != nil {
+        return err
+    }
+    if string(footer[8 : ]) != gPACK_BINARY_MAGIC {
+        return nil
+    }
+    length := gbinary.BeDecodeToInt64(footer[ : 8])
+    if length <= 0 || length > info.Size() - footerSize {
+        return errors.New("invalid resource data in executable")
+    }
+    data, err := ioutil.ReadAll(io.NewSectionReader(file, info.Size() - footerSize - length, length))
+    if err != nil {
+        return err
+    }
+    return r.addBytes(data)
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gres
+
+import (
+    "archive/zip"
+    "bytes"
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "path"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+)
+
+// 资源管理对象
+type Resource struct {
+    mu    sync.RWMutex
+    files map[string]*File // 资源路径(不包含开头的'/')对应的文件
+}
+
+// 创建资源管理对象
+func New() *Resource {
+    return &Resource {
+        files : make(map[string]*File),
+    }
+}
+
+// 添加打包后的资源内容，prefix为资源路径前缀(可选)，已存在的资源路径将会被覆盖
+func (r *Resource) Add(content string, prefix...string) error {
+    return r.addBytes([]byte(content), prefix...)
+}
+
+// 加载打包后的资源文件，prefix为资源路径前缀(可选)
+func (r *Resource) Load(path string, prefix...string) error {
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    return r.addBytes(data, prefix...)
+}
+
+// 添加打包后的资源数据
+func (r *Resource) addBytes(data []byte, prefix...string) error {
+    reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+    if err != nil {
+        return errors.New(fmt.Sprintf(`invalid resource content: %s`, err.Error()))
+    }
+    namePrefix := ""
+    if len(prefix) > 0 {
+        namePrefix = formatPath(prefix[0])
+    }
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    for _, zf := range reader.File {
+        name := formatPath(namePrefix + "/" + zf.Name)
+        if name == "" {
+            continue
+        }
+        r.files[name] = &File {
+            name     : name,
+            file     : zf,
+            resource : r,
+        }
+        // 补全上级目录
+        for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+            if _, ok := r.files[dir]; ok {
+                break
+            }
+            r.files[dir] = newDirFile(r, dir)
+        }
+    }
+    return nil
+}
+
+// 获取指定路径的资源文件(或者目录)，不存在时返回nil
+func (r *Resource) Get(path string) *File {
+    name := formatPath(path)
+    if name == "" {
+        return nil
+    }
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.files[name]
+}
+
+// 判断指定路径的资源文件(或者目录)是否存在
+func (r *Resource) Contains(path string) bool {
+    return r.Get(path) != nil
+}
+
+// 判断是否没有任何资源
+func (r *Resource) IsEmpty() bool {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return len(r.files) == 0
+}
+
+// 获取指定资源文件的内容，不存在或者为目录时返回nil
+func (r *Resource) GetContent(path string) []byte {
+    if file := r.Get(path); file != nil {
+        return file.Content()
+    }
+    return nil
+}
+
+// 打开指定资源文件，返回的对象实现了http.File接口
+func (r *Resource) Open(path string) (*Reader, error) {
+    file := r.Get(path)
+    if file == nil {
+        return nil, &os.PathError{Op : "open", Path : path, Err : os.ErrNotExist}
+    }
+    return file.Open()
+}
+
+// 在指定资源目录中查找文件，name为目录时依次查找indexFiles，均不存在时返回目录本身。
+// name单独进行路径规范化，结果不在dir目录中(例如包含"../")时返回nil。
+func (r *Resource) Search(dir string, name string, indexFiles...string) *File {
+    dir   = formatPath(dir)
+    path := formatPath(dir + "/" + formatPath(name))
+    if dir != "" && path != dir && !strings.HasPrefix(path, dir + "/") {
+        return nil
+    }
+    file := r.Get(path)
+    if file == nil || !file.IsDir() {
+        return file
+    }
+    for _, index := range indexFiles {
+        if f := r.Get(file.name + "/" + index); f != nil {
+            return f
+        }
+    }
+    return file
+}
+
+// 获取指定资源目录下的文件列表(按照路径排序)，pattern支持多个文件名匹配模式(使用','分隔)，
+// 例如: "*.html,*.tpl"，recursive表示是否递归获取
+func (r *Resource) ScanDir(path string, pattern string, recursive...bool) []*File {
+    dir      := formatPath(path)
+    patterns := strings.Split(pattern, ",")
+    files    := make([]*File, 0)
+    r.mu.RLock()
+    for name, file := range r.files {
+        if dir != "" && !strings.HasPrefix(name, dir + "/") {
+            continue
+        }
+        relative := strings.TrimPrefix(name[len(dir) : ], "/")
+        if strings.Contains(relative, "/") && (len(recursive) == 0 || !recursive[0]) {
+            continue
+        }
+        for _, p := range patterns {
+            if ok, _ := filepath.Match(strings.TrimSpace(p), file.Name()); ok {
+                files = append(files, file)
+                break
+            }
+        }
+    }
+    r.mu.RUnlock()
+    sort.Slice(files, func(i, j int) bool {
+        return files[i].name < files[j].name
+    })
+    return files
+}
+
+// 获取所有的资源路径(已排序)
+func (r *Resource) Paths() []string {
+    r.mu.RLock()
+    paths := make([]string, 0, len(r.files))
+    for name := range r.files {
+        paths = append(paths, name)
+    }
+    r.mu.RUnlock()
+    sort.Strings(paths)
+    return paths
+}
+
+// 格式化资源路径：统一使用'/'分隔，去掉开头的'/'及'./'以及结尾的'/'
+func formatPath(p string) string {
+    p = path.Clean("/" + strings.Replace(p, "\\", "/", -1))
+    return strings.TrimPrefix(p, "/")
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gres_test
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "io/ioutil"
+    "strings"
+    "testing"
+)
+
+// 创建用于打包的测试目录
+func testDir() string {
+    dir := gfile.TempDir() + gfile.Separator + "gres_test_" + gconv.String(gtime.Nanosecond())
+    gfile.PutContents(dir + "/public/index.html",     "index")
+    gfile.PutContents(dir + "/public/css/style.css",  "body{}")
+    gfile.PutContents(dir + "/config/config.toml",    "name = \"gres\"")
+    return dir
+}
+
+func TestPack(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := testDir()
+        defer gfile.Remove(dir)
+        data, err := gres.Pack(dir + "/public," + dir + "/config")
+        gtest.Assert(err, nil)
+
+        r := gres.New()
+        gtest.Assert(r.IsEmpty(), true)
+        gtest.Assert(r.Add(string(data)), nil)
+        gtest.Assert(r.Paths(), []string{
+            "config", "config/config.toml", "public", "public/css", "public/css/style.css", "public/index.html",
+        })
+        gtest.Assert(string(r.GetContent("public/index.html")),    "index")
+        gtest.Assert(string(r.GetContent("/public/css/style.css")), "body{}")
+        gtest.Assert(r.Get("public").IsDir(), true)
+        gtest.Assert(r.Get("public/index.html").Name(), "index.html")
+        gtest.Assert(r.Get("public/none") == nil, true)
+        gtest.Assert(r.Search("public", "/", "index.htm", "index.html").Path(), "public/index.html")
+        gtest.Assert(r.Search("public", "/css/../index.html").Path(), "public/index.html")
+        // 不能通过"../"访问目录以外的资源文件
+        gtest.Assert(r.Search("public", "/../config/config.toml") == nil, true)
+        gtest.Assert(r.Search("public", "../../config/config.toml") == nil, true)
+        gtest.Assert(r.Search("public", "..\\config\\config.toml") == nil, true)
+
+        files := r.ScanDir("public", "*.html,*.css")
+        gtest.Assert(len(files), 1)
+        files  = r.ScanDir("public", "*.html,*.css", true)
+        gtest.Assert(len(files), 2)
+        gtest.Assert(files[0].Path(), "public/css/style.css")
+
+        reader, err := r.Open("public/index.html")
+        gtest.Assert(err, nil)
+        content, _ := ioutil.ReadAll(reader)
+        gtest.Assert(string(content), "index")
+        reader, err = r.Open("public")
+        gtest.Assert(err, nil)
+        infos, err := reader.Readdir(-1)
+        gtest.Assert(err, nil)
+        gtest.Assert(len(infos), 2)
+        _, err = r.Open("none")
+        gtest.AssertNE(err, nil)
+
+        // 资源路径前缀
+        r = gres.New()
+        gtest.Assert(r.Add(string(data), "assets"), nil)
+        gtest.Assert(string(r.GetContent("assets/public/index.html")), "index")
+    })
+}
+
+func TestPackToFile(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := testDir()
+        defer gfile.Remove(dir)
+        path := dir + "/data.bin"
+        gtest.Assert(gres.PackToFile(dir + "/public", path), nil)
+        r := gres.New()
+        gtest.Assert(r.Load(path), nil)
+        gtest.Assert(string(r.GetContent("public/index.html")), "index")
+
+        goFile := dir + "/packed/data.go"
+        gtest.Assert(gres.PackToGoFile(dir + "/config", goFile, ""), nil)
+        content := gfile.GetContents(goFile)
+        gtest.Assert(strings.Contains(content, "package packed"), true)
+        gtest.Assert(strings.Contains(content, "gres.Add("), true)
+
+        gtest.AssertNE(gres.PackToFile(dir + "/none", path), nil)
+        gtest.AssertNE(r.Add("invalid"), nil)
+    })
+}
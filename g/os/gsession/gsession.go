@@ -0,0 +1,31 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gsession implements session management with pluggable storage.
+//
+// 会话管理，与具体的网络协议无关，可用于ghttp、gtcp以及后台守护进程等场景，
+// 会话数据通过存储适配器保存(内存、文件、Redis)，支持不同的有效期刷新策略。
+package gsession
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "strings"
+)
+
+const (
+    REFRESH_ON_ACCESS = 0 // 有效期刷新策略：每次访问会话时刷新有效期(滑动过期，默认)
+    REFRESH_ON_WRITE  = 1 // 有效期刷新策略：仅在会话数据修改时刷新有效期
+)
+
+// 生成一个唯一的SessionId字符串，由crypto/rand生成的128位随机数组成(32个十六进制字符)，无法被猜测
+func NewSessionId() string {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        panic(err)
+    }
+    return strings.ToUpper(hex.EncodeToString(b))
+}
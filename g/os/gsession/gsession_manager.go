@@ -0,0 +1,85 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "github.com/gogf/gf/g/container/gtype"
+    "sync"
+    "time"
+)
+
+// 会话管理器
+type Manager struct {
+    mu      sync.RWMutex
+    ttl     time.Duration // 会话有效期
+    storage Storage       // 会话存储适配器
+    policy  *gtype.Int    // 有效期刷新策略
+}
+
+// 创建会话管理器，ttl为会话有效期，storage为存储适配器(默认为内存存储)
+func New(ttl time.Duration, storage...Storage) *Manager {
+    m := &Manager {
+        ttl    : ttl,
+        policy : gtype.NewInt(REFRESH_ON_ACCESS),
+    }
+    if len(storage) > 0 && storage[0] != nil {
+        m.storage = storage[0]
+    } else {
+        m.storage = NewStorageMemory()
+    }
+    return m
+}
+
+// 获取指定SessionId的会话对象，id为空时在首次写入数据时自动生成SessionId。
+// 会话数据在首次访问时才从存储中读取，使用完毕后需要调用Close方法保存数据及刷新有效期。
+func (m *Manager) New(id...string) *Session {
+    s := &Session {
+        manager : m,
+    }
+    if len(id) > 0 {
+        s.id = id[0]
+    }
+    return s
+}
+
+// 设置会话有效期
+func (m *Manager) SetTTL(ttl time.Duration) {
+    m.mu.Lock()
+    m.ttl = ttl
+    m.mu.Unlock()
+}
+
+// 获取会话有效期
+func (m *Manager) GetTTL() time.Duration {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    return m.ttl
+}
+
+// 设置会话存储适配器
+func (m *Manager) SetStorage(storage Storage) {
+    m.mu.Lock()
+    m.storage = storage
+    m.mu.Unlock()
+}
+
+// 获取会话存储适配器
+func (m *Manager) GetStorage() Storage {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+    return m.storage
+}
+
+// 设置有效期刷新策略：REFRESH_ON_ACCESS(默认)/REFRESH_ON_WRITE
+func (m *Manager) SetRefreshPolicy(policy int) {
+    m.policy.Set(policy)
+}
+
+// 获取有效期刷新策略
+func (m *Manager) GetRefreshPolicy() int {
+    return m.policy.Val()
+}
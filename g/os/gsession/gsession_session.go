@@ -0,0 +1,188 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gvar"
+    "sync"
+)
+
+// 会话对象，并发安全
+type Session struct {
+    mu      sync.Mutex
+    id      string                   // SessionId
+    data    *gmap.StringInterfaceMap // 会话数据(首次访问时从存储中读取)
+    loaded  bool                     // 是否已从存储中读取数据
+    exists  bool                     // 存储中是否已存在该会话
+    dirty   bool                     // 数据是否已修改
+    manager *Manager                 // 所属会话管理器
+}
+
+// 从存储中读取会话数据(延迟初始化)，create表示SessionId为空时是否生成新的SessionId
+func (s *Session) init(create bool) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.loaded {
+        return nil
+    }
+    if s.id == "" {
+        if !create {
+            return nil
+        }
+        s.id = NewSessionId()
+    } else {
+        data, err := s.manager.GetStorage().Get(s.id, s.manager.GetTTL())
+        if err != nil {
+            return err
+        }
+        if data != nil {
+            s.data   = data
+            s.exists = true
+        }
+    }
+    if s.data == nil {
+        s.data = gmap.NewStringInterfaceMap()
+    }
+    s.loaded = true
+    return nil
+}
+
+// 标记数据已修改
+func (s *Session) markDirty() {
+    s.mu.Lock()
+    s.dirty = true
+    s.mu.Unlock()
+}
+
+// 获取SessionId，为空时生成新的SessionId
+func (s *Session) Id() string {
+    s.init(true)
+    return s.id
+}
+
+// 获取所有的会话数据(复制的map)
+func (s *Session) Map() map[string]interface{} {
+    if s.init(false); s.data != nil {
+        return s.data.Map()
+    }
+    return nil
+}
+
+// 获取会话数据数量
+func (s *Session) Size() int {
+    if s.init(false); s.data != nil {
+        return s.data.Size()
+    }
+    return 0
+}
+
+// 设置会话数据
+func (s *Session) Set(key string, value interface{}) error {
+    if err := s.init(true); err != nil {
+        return err
+    }
+    s.data.Set(key, value)
+    s.markDirty()
+    return nil
+}
+
+// 批量设置会话数据
+func (s *Session) Sets(m map[string]interface{}) error {
+    if err := s.init(true); err != nil {
+        return err
+    }
+    s.data.BatchSet(m)
+    s.markDirty()
+    return nil
+}
+
+// 判断键名是否存在
+func (s *Session) Contains(key string) bool {
+    if s.init(false); s.data != nil {
+        return s.data.Contains(key)
+    }
+    return false
+}
+
+// 获取会话数据，键名不存在时返回默认值def(可选)
+func (s *Session) Get(key string, def...interface{}) interface{} {
+    if s.init(false); s.data != nil {
+        if v := s.data.Get(key); v != nil {
+            return v
+        }
+    }
+    if len(def) > 0 {
+        return def[0]
+    }
+    return nil
+}
+
+// 获取会话数据的只读泛型变量，用于类型转换，例如: GetVar("uid").Int()
+func (s *Session) GetVar(key string, def...interface{}) gvar.VarRead {
+    return gvar.NewRead(s.Get(key, def...), true)
+}
+
+// 删除会话数据
+func (s *Session) Remove(key string) error {
+    if err := s.init(false); err != nil || s.data == nil {
+        return err
+    }
+    s.data.Remove(key)
+    s.markDirty()
+    return nil
+}
+
+// 清空会话数据
+func (s *Session) Clear() error {
+    if err := s.init(false); err != nil || s.data == nil {
+        return err
+    }
+    s.data.Clear()
+    s.markDirty()
+    return nil
+}
+
+// 销毁会话，删除存储中的会话数据
+func (s *Session) Destroy() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.id == "" {
+        return nil
+    }
+    if s.data != nil {
+        s.data.Clear()
+    }
+    s.dirty  = false
+    s.exists = false
+    return s.manager.GetStorage().Remove(s.id)
+}
+
+// 关闭会话：数据已修改时保存到存储中，否则根据刷新策略刷新有效期。
+// 长期持有会话对象时(例如守护进程)也需要定期调用，防止会话超时被清除。
+func (s *Session) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if !s.loaded || s.id == "" {
+        return nil
+    }
+    storage := s.manager.GetStorage()
+    ttl     := s.manager.GetTTL()
+    if s.dirty {
+        s.dirty = false
+        if s.data.Size() == 0 {
+            s.exists = false
+            return storage.Remove(s.id)
+        }
+        s.exists = true
+        return storage.Set(s.id, s.data, ttl)
+    }
+    if s.exists && s.manager.GetRefreshPolicy() == REFRESH_ON_ACCESS {
+        return storage.UpdateTTL(s.id, ttl)
+    }
+    return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "github.com/gogf/gf/g/container/gmap"
+    "time"
+)
+
+// 会话存储适配器
+type Storage interface {
+    // 获取会话数据，会话不存在或者已过期时返回nil
+    Get(id string, ttl time.Duration) (*gmap.StringInterfaceMap, error)
+    // 保存会话数据并设置有效期
+    Set(id string, data *gmap.StringInterfaceMap, ttl time.Duration) error
+    // 刷新会话有效期
+    UpdateTTL(id string, ttl time.Duration) error
+    // 删除会话
+    Remove(id string) error
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gtimer"
+    "os"
+    "regexp"
+    "time"
+)
+
+const (
+    gFILE_STORAGE_CLEAN_INTERVAL = time.Minute // 文件会话存储过期文件的清理间隔
+)
+
+var (
+    // 合法的SessionId，防止通过SessionId访问存储目录以外的文件
+    sessionIdRegex = regexp.MustCompile(`^[\w\-]+$`)
+)
+
+// 文件会话存储，每个会话保存为存储目录下以SessionId命名的JSON文件，
+// 文件修改时间设置为会话的过期时间(每个会话可以使用不同的有效期)，过期文件由后台定时清理
+type StorageFile struct {
+    path  string
+    entry *gtimer.Entry // 后台清理任务
+}
+
+// 创建文件会话存储，path为存储目录(不存在时自动创建)，不再使用时需要调用Close停止后台清理任务
+func NewStorageFile(path string) (*StorageFile, error) {
+    if !gfile.Exists(path) {
+        if err := gfile.Mkdir(path); err != nil {
+            return nil, err
+        }
+    }
+    s := &StorageFile {
+        path : gfile.RealPath(path),
+    }
+    s.entry = gtimer.AddSingleton(gFILE_STORAGE_CLEAN_INTERVAL, s.clean)
+    return s, nil
+}
+
+func (s *StorageFile) Get(id string, ttl time.Duration) (*gmap.StringInterfaceMap, error) {
+    path, err := s.filePath(id)
+    if err != nil {
+        return nil, err
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        return nil, nil
+    }
+    if time.Now().After(info.ModTime()) {
+        os.Remove(path)
+        return nil, nil
+    }
+    m := make(map[string]interface{})
+    if err := json.Unmarshal(gfile.GetBinContents(path), &m); err != nil {
+        return nil, err
+    }
+    return gmap.NewStringInterfaceMapFrom(m), nil
+}
+
+func (s *StorageFile) Set(id string, data *gmap.StringInterfaceMap, ttl time.Duration) error {
+    path, err := s.filePath(id)
+    if err != nil {
+        return err
+    }
+    content, err := json.Marshal(data.Map())
+    if err != nil {
+        return err
+    }
+    if err := gfile.PutBinContents(path, content); err != nil {
+        return err
+    }
+    return s.expire(path, ttl)
+}
+
+func (s *StorageFile) UpdateTTL(id string, ttl time.Duration) error {
+    path, err := s.filePath(id)
+    if err != nil {
+        return err
+    }
+    return s.expire(path, ttl)
+}
+
+func (s *StorageFile) Remove(id string) error {
+    path, err := s.filePath(id)
+    if err != nil {
+        return err
+    }
+    if gfile.Exists(path) {
+        return os.Remove(path)
+    }
+    return nil
+}
+
+// 停止后台清理任务(不删除已保存的会话文件)
+func (s *StorageFile) Close() {
+    s.entry.Close()
+}
+
+// 获取会话文件路径
+func (s *StorageFile) filePath(id string) (string, error) {
+    if !sessionIdRegex.MatchString(id) {
+        return "", errors.New(fmt.Sprintf(`invalid session id "%s"`, id))
+    }
+    return s.path + gfile.Separator + id, nil
+}
+
+// 将会话文件的修改时间设置为过期时间
+func (s *StorageFile) expire(path string, ttl time.Duration) error {
+    now := time.Now()
+    return os.Chtimes(path, now, now.Add(ttl))
+}
+
+// 清理过期的会话文件，只处理文件名为合法SessionId的文件
+func (s *StorageFile) clean() {
+    files, err := gfile.ScanDir(s.path, "*")
+    if err != nil {
+        return
+    }
+    now := time.Now()
+    for _, file := range files {
+        if !sessionIdRegex.MatchString(gfile.Basename(file)) {
+            continue
+        }
+        if info, err := os.Stat(file); err == nil && !info.IsDir() && now.After(info.ModTime()) {
+            os.Remove(file)
+        }
+    }
+}
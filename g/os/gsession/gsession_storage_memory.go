@@ -0,0 +1,57 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/os/gcache"
+    "time"
+)
+
+// 内存会话存储，相同SessionId的会话对象共享同一个数据map，并发请求之间的修改立即可见
+type StorageMemory struct {
+    cache *gcache.Cache
+}
+
+// 创建内存会话存储
+func NewStorageMemory() *StorageMemory {
+    return &StorageMemory {
+        cache : gcache.New(),
+    }
+}
+
+func (s *StorageMemory) Get(id string, ttl time.Duration) (*gmap.StringInterfaceMap, error) {
+    if v := s.cache.Get(id); v != nil {
+        return v.(*gmap.StringInterfaceMap), nil
+    }
+    return nil, nil
+}
+
+func (s *StorageMemory) Set(id string, data *gmap.StringInterfaceMap, ttl time.Duration) error {
+    s.cache.Set(id, data, ttlMilliseconds(ttl))
+    return nil
+}
+
+func (s *StorageMemory) UpdateTTL(id string, ttl time.Duration) error {
+    if v := s.cache.Get(id); v != nil {
+        s.cache.Set(id, v, ttlMilliseconds(ttl))
+    }
+    return nil
+}
+
+func (s *StorageMemory) Remove(id string) error {
+    s.cache.Remove(id)
+    return nil
+}
+
+// 有效期转换为毫秒，最小为1毫秒(gcache中0表示不过期)
+func ttlMilliseconds(ttl time.Duration) int {
+    if ms := int(ttl/time.Millisecond); ms > 0 {
+        return ms
+    }
+    return 1
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "encoding/json"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/database/gredis"
+    "github.com/gogf/gf/g/util/gconv"
+    "time"
+)
+
+const (
+    gDEFAULT_REDIS_KEY_PREFIX = "gsession:" // Redis会话存储默认的键名前缀
+)
+
+// Redis会话存储，会话数据以JSON格式保存，有效期由Redis自动管理
+type StorageRedis struct {
+    redis  *gredis.Redis
+    prefix string
+}
+
+// 创建Redis会话存储，prefix为键名前缀(默认为gsession:)
+func NewStorageRedis(redis *gredis.Redis, prefix...string) *StorageRedis {
+    s := &StorageRedis {
+        redis  : redis,
+        prefix : gDEFAULT_REDIS_KEY_PREFIX,
+    }
+    if len(prefix) > 0 {
+        s.prefix = prefix[0]
+    }
+    return s
+}
+
+func (s *StorageRedis) Get(id string, ttl time.Duration) (*gmap.StringInterfaceMap, error) {
+    r, err := s.redis.Do("GET", s.prefix + id)
+    if err != nil || r == nil {
+        return nil, err
+    }
+    m := make(map[string]interface{})
+    if err := json.Unmarshal(gconv.Bytes(r), &m); err != nil {
+        return nil, err
+    }
+    return gmap.NewStringInterfaceMapFrom(m), nil
+}
+
+func (s *StorageRedis) Set(id string, data *gmap.StringInterfaceMap, ttl time.Duration) error {
+    content, err := json.Marshal(data.Map())
+    if err != nil {
+        return err
+    }
+    _, err = s.redis.Do("SET", s.prefix + id, content, "PX", ttlMilliseconds(ttl))
+    return err
+}
+
+func (s *StorageRedis) UpdateTTL(id string, ttl time.Duration) error {
+    _, err := s.redis.Do("PEXPIRE", s.prefix + id, ttlMilliseconds(ttl))
+    return err
+}
+
+func (s *StorageRedis) Remove(id string) error {
+    _, err := s.redis.Do("DEL", s.prefix + id)
+    return err
+}
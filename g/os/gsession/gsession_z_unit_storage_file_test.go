@@ -0,0 +1,45 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/os/gtimer"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "os"
+    "testing"
+    "time"
+)
+
+func TestStorageFile_Clean(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := gfile.TempDir() + gfile.Separator + "gsession_clean_" + gconv.String(gtime.Nanosecond())
+        defer gfile.Remove(dir)
+        storage, err := NewStorageFile(dir)
+        gtest.Assert(err, nil)
+        storage.Close()
+        gtest.Assert(storage.entry.Status(), gtimer.STATUS_CLOSED)
+
+        data := gmap.NewStringInterfaceMapFrom(map[string]interface{}{"k" : 1})
+        gtest.Assert(storage.Set("long",  data, time.Hour), nil)
+        gtest.Assert(storage.Set("short", data, time.Millisecond), nil)
+        // 存储目录中的其他文件即使修改时间已过期也不清理
+        other := dir + gfile.Separator + "other.txt"
+        gtest.Assert(gfile.PutContents(other, "other"), nil)
+        past := time.Now().Add(-time.Hour)
+        gtest.Assert(os.Chtimes(other, past, past), nil)
+        time.Sleep(10*time.Millisecond)
+
+        storage.clean()
+        gtest.Assert(gfile.Exists(dir + gfile.Separator + "long"),  true)
+        gtest.Assert(gfile.Exists(dir + gfile.Separator + "short"), false)
+        gtest.Assert(gfile.Exists(other), true)
+    })
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gsession_test
+
+import (
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gsession"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "testing"
+    "time"
+)
+
+func TestNewSessionId(t *testing.T) {
+    gtest.Case(t, func() {
+        ids := make(map[string]struct{})
+        for i := 0; i < 1000; i++ {
+            id := gsession.NewSessionId()
+            gtest.Assert(len(id), 32)
+            ids[id] = struct{}{}
+        }
+        gtest.Assert(len(ids), 1000)
+    })
+}
+
+func TestSession_Memory(t *testing.T) {
+    gtest.Case(t, func() {
+        manager := gsession.New(time.Second)
+        s1      := manager.New()
+        gtest.Assert(s1.Get("none", 1), 1)
+        gtest.Assert(s1.Set("uid", 100), nil)
+        gtest.Assert(s1.Sets(map[string]interface{}{"name" : "john"}), nil)
+        id := s1.Id()
+        gtest.AssertNE(id, "")
+        gtest.Assert(s1.Close(), nil)
+
+        // 相同SessionId的会话共享数据
+        s2 := manager.New(id)
+        gtest.Assert(s2.GetVar("uid").Int(), 100)
+        gtest.Assert(s2.Get("name"), "john")
+        gtest.Assert(s2.Contains("uid"), true)
+        gtest.Assert(s2.Size(), 2)
+        gtest.Assert(s2.Remove("uid"), nil)
+        gtest.Assert(s2.Close(), nil)
+        gtest.Assert(manager.New(id).Contains("uid"), false)
+
+        // 清空后会话被删除
+        s3 := manager.New(id)
+        gtest.Assert(s3.Clear(), nil)
+        gtest.Assert(s3.Close(), nil)
+        gtest.Assert(manager.New(id).Map(), map[string]interface{}{})
+
+        // 未提交SessionId的只读操作不创建会话
+        s4 := manager.New()
+        gtest.Assert(s4.Contains("uid"), false)
+        gtest.Assert(s4.Close(), nil)
+    })
+}
+
+func TestSession_RefreshPolicy(t *testing.T) {
+    gtest.Case(t, func() {
+        manager := gsession.New(500*time.Millisecond)
+        s := manager.New()
+        s.Set("k", "v")
+        s.Close()
+        id := s.Id()
+        // 访问时刷新有效期
+        for i := 0; i < 3; i++ {
+            time.Sleep(300*time.Millisecond)
+            s = manager.New(id)
+            gtest.Assert(s.Get("k"), "v")
+            s.Close()
+        }
+        // 仅在写入时刷新有效期
+        manager.SetRefreshPolicy(gsession.REFRESH_ON_WRITE)
+        gtest.Assert(manager.GetRefreshPolicy(), gsession.REFRESH_ON_WRITE)
+        for i := 0; i < 2; i++ {
+            time.Sleep(300*time.Millisecond)
+            s = manager.New(id)
+            s.Get("k")
+            s.Close()
+        }
+        time.Sleep(1500*time.Millisecond)
+        gtest.Assert(manager.New(id).Get("k"), nil)
+    })
+}
+
+func TestSession_File(t *testing.T) {
+    gtest.Case(t, func() {
+        dir := gfile.TempDir() + gfile.Separator + "gsession_test_" + gconv.String(gtime.Nanosecond())
+        defer gfile.Remove(dir)
+        storage, err := gsession.NewStorageFile(dir)
+        gtest.Assert(err, nil)
+        defer storage.Close()
+        manager := gsession.New(300*time.Millisecond, storage)
+
+        s := manager.New()
+        s.Set("uid",  100)
+        s.Set("tags", []string{"a", "b"})
+        gtest.Assert(s.Close(), nil)
+        gtest.Assert(gfile.Exists(dir + gfile.Separator + s.Id()), true)
+
+        s = manager.New(s.Id())
+        gtest.Assert(s.GetVar("uid").Int(), 100)
+        gtest.Assert(s.GetVar("tags").Strings(), []string{"a", "b"})
+        gtest.Assert(s.Destroy(), nil)
+        gtest.Assert(gfile.Exists(dir + gfile.Separator + s.Id()), false)
+
+        s = manager.New()
+        s.Set("uid", 200)
+        s.Close()
+        time.Sleep(500*time.Millisecond)
+        gtest.Assert(manager.New(s.Id()).Get("uid"), nil)
+
+        // 每个会话使用各自的有效期，较短的有效期不影响其他会话
+        gtest.Assert(storage.Set("long", gmap.NewStringInterfaceMapFrom(map[string]interface{}{"k" : 1}), time.Hour), nil)
+        gtest.Assert(storage.Set("short", gmap.NewStringInterfaceMapFrom(map[string]interface{}{"k" : 2}), 100*time.Millisecond), nil)
+        time.Sleep(200*time.Millisecond)
+        data, err := storage.Get("short", 100*time.Millisecond)
+        gtest.Assert(err,  nil)
+        gtest.Assert(data, nil)
+        data, err  = storage.Get("long", 100*time.Millisecond)
+        gtest.Assert(err,          nil)
+        gtest.Assert(data.Get("k"), 1)
+
+        // 非法的SessionId
+        s = manager.New("../invalid")
+        gtest.AssertNE(s.Set("uid", 1), nil)
+    })
+}
@@ -6,7 +6,10 @@
 
 package gtime
 
-import "time"
+import (
+    "strings"
+    "time"
+)
 
 type Time struct {
     time.Time
@@ -164,4 +167,23 @@ func (t *Time) Round(d time.Duration) *Time {
 func (t *Time) Truncate(d time.Duration) *Time {
     t.Time = t.Time.Truncate(d)
     return t
-}
\ No newline at end of file
+}
+// 实现json.Marshaler接口，JSON编码时使用与String方法一致的格式(Y-m-d H:i:s)
+func (t *Time) MarshalJSON() ([]byte, error) {
+    return []byte(`"` + t.String() + `"`), nil
+}
+
+// 实现json.Unmarshaler接口，支持StrToTime可识别的所有日期时间格式
+func (t *Time) UnmarshalJSON(b []byte) error {
+    s := strings.Trim(string(b), `"`)
+    if s == "" || s == "null" {
+        t.Time = time.Time{}
+        return nil
+    }
+    newTime, err := StrToTime(s)
+    if err != nil {
+        return err
+    }
+    t.Time = newTime.Time
+    return nil
+}
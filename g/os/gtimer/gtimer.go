@@ -99,6 +99,16 @@ func DelayAddTimes(delay time.Duration, interval time.Duration, times int, job J
     defaultTimer.DelayAddTimes(delay, interval, times, job)
 }
 
+// 获取默认定时器中所有未关闭的任务项，按照下一次运行时间从小到大排序
+func Jobs() []*Entry {
+    return defaultTimer.Jobs()
+}
+
+// 设置默认定时器的任务错误处理方法
+func SetErrorHandler(handler ErrorHandler) {
+    defaultTimer.SetErrorHandler(handler)
+}
+
 // 在Job方法中调用，停止当前运行的任务。
 func Exit() {
     panic(gPANIC_EXIT)
@@ -23,6 +23,13 @@ type Entry struct {
     createMs      int64         // 创建时间(毫秒)
     intervalMs    int64         // 间隔时间(毫秒)
     rawIntervalMs int64         // 原始间隔
+    root          *Entry        // 添加时返回的原始任务项，层级entry共享
+    generation    *gtype.Int64  // 任务调度版本，Reset/Resume时递增，层级entry共享
+    gen           int64         // 当前entry所属的调度版本，旧版本的entry将被丢弃
+    nextMs        *gtype.Int64  // 下一次运行时间(毫秒)，层级entry共享
+    pausedMs      *gtype.Int64  // 暂停时距离下一次运行的剩余时间(毫秒)，-1表示未暂停，层级entry共享
+    failures      *gtype.Int    // 连续失败次数，层级entry共享
+    maxFailures   *gtype.Int    // 连续失败次数阈值，层级entry共享
 }
 
 // 任务执行方法
@@ -50,7 +57,13 @@ func (w *wheel) addEntry(interval time.Duration, job JobFunc, singleton bool, ti
         createMs      : nowMs,
         intervalMs    : ms,
         rawIntervalMs : ms,
+        generation    : gtype.NewInt64(),
+        nextMs        : gtype.NewInt64(nowMs + ms),
+        pausedMs      : gtype.NewInt64(-1),
+        failures      : gtype.NewInt(),
+        maxFailures   : gtype.NewInt(),
     }
+    entry.root = entry
     // 安装任务
     w.slots[(ticks + num) % w.number].PushBack(entry)
     return entry
@@ -75,6 +88,16 @@ func (w *wheel) addEntryByParent(interval int64, parent *Entry) *Entry {
         createMs      : nowMs,
         intervalMs    : interval,
         rawIntervalMs : parent.rawIntervalMs,
+        root          : parent.root,
+        generation    : parent.generation,
+        gen           : parent.gen,
+        nextMs        : parent.nextMs,
+        pausedMs      : parent.pausedMs,
+        failures      : parent.failures,
+        maxFailures   : parent.maxFailures,
+    }
+    if entry.gen == entry.generation.Val() {
+        entry.nextMs.Set(nowMs + interval)
     }
     w.slots[(ticks + num) % w.number].PushBack(entry)
     return entry
@@ -90,8 +113,10 @@ func (entry *Entry) SetStatus(status int) int {
     return entry.status.Set(status)
 }
 
-// 启动当前任务
+// 启动当前任务(同时清除暂停状态以及连续失败次数)
 func (entry *Entry) Start() {
+    entry.pausedMs.Set(-1)
+    entry.failures.Set(0)
     entry.status.Set(STATUS_READY)
 }
 
@@ -105,6 +130,79 @@ func (entry *Entry) Close() {
     entry.status.Set(STATUS_CLOSED)
 }
 
+// 暂停当前任务，并记录距离下一次运行的剩余时间，通过Resume恢复后将在剩余时间之后运行
+func (entry *Entry) Pause() {
+    if entry.status.Val() == STATUS_CLOSED {
+        return
+    }
+    leftMs := entry.nextMs.Val() - time.Now().UnixNano()/1e6
+    if leftMs < 0 {
+        leftMs = 0
+    }
+    entry.pausedMs.Set(leftMs)
+    entry.status.Set(STATUS_STOPPED)
+}
+
+// 恢复暂停的任务，任务将在暂停时的剩余时间之后运行，之后按照原有间隔运行；
+// 对于未暂停的任务等同于Start
+func (entry *Entry) Resume() {
+    if entry.status.Val() == STATUS_CLOSED {
+        return
+    }
+    if leftMs := entry.pausedMs.Set(-1); leftMs >= 0 {
+        entry.reschedule(leftMs)
+    }
+    entry.status.Set(STATUS_READY)
+}
+
+// 重置当前任务的计时，任务将从当前时间开始，在一个完整的间隔之后运行；
+// 对于暂停的任务，恢复后将在一个完整的间隔之后运行
+func (entry *Entry) Reset() {
+    if entry.status.Val() == STATUS_CLOSED {
+        return
+    }
+    if entry.pausedMs.Val() >= 0 {
+        entry.pausedMs.Set(entry.rawIntervalMs)
+        return
+    }
+    entry.reschedule(entry.rawIntervalMs)
+}
+
+// 判断当前任务是否已暂停
+func (entry *Entry) IsPaused() bool {
+    return entry.pausedMs.Val() >= 0 && entry.status.Val() == STATUS_STOPPED
+}
+
+// 获取任务的运行间隔
+func (entry *Entry) Interval() time.Duration {
+    return time.Duration(entry.rawIntervalMs)*time.Millisecond
+}
+
+// 获取任务的下一次运行时间，已关闭的任务返回零值；
+// 对于暂停的任务，返回从当前时间开始计算剩余时间的运行时间
+func (entry *Entry) Next() time.Time {
+    if entry.status.Val() == STATUS_CLOSED {
+        return time.Time{}
+    }
+    ms := entry.nextMs.Val()
+    if entry.IsPaused() {
+        ms = time.Now().UnixNano()/1e6 + entry.pausedMs.Val()
+    }
+    return time.Unix(0, ms*1e6)
+}
+
+// 获取任务剩余的运行次数
+func (entry *Entry) Times() int {
+    return entry.times.Val()
+}
+
+// 按照新的调度版本重新安装任务，旧版本的层级entry将在下一次检查时被丢弃
+func (entry *Entry) reschedule(intervalMs int64) {
+    parent    := *entry.root
+    parent.gen = entry.generation.Add(1)
+    entry.wheel.timer.doAddEntryByParent(intervalMs, &parent)
+}
+
 // 是否单例运行
 func (entry *Entry) IsSingleton() bool {
     return entry.singleton.Val()
@@ -127,6 +225,10 @@ func (entry *Entry) Run() {
 
 // 检测当前任务是否可运行。
 func (entry *Entry) check(nowTicks int64, nowMs int64) (runnable, addable bool) {
+    // 任务已被重新安装(Reset/Resume)
+    if entry.gen != entry.generation.Val() {
+        return false, false
+    }
     switch entry.status.Val() {
         case STATUS_STOPPED:
             return false, true
@@ -0,0 +1,123 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtimer
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "runtime/debug"
+    "time"
+)
+
+// 任务运行错误
+type JobError struct {
+    Err      error  // 任务返回的错误，或者panic内容转换的错误
+    Panic    bool   // 是否由panic产生
+    Stack    string // panic时的调用栈
+    Failures int    // 连续失败次数(包含本次)
+    Disabled bool   // 是否因为连续失败次数达到阈值而被停止
+}
+
+// 任务错误处理方法，entry为添加任务时返回的任务项
+type ErrorHandler = func(entry *Entry, err *JobError)
+
+// 通过panic传递的任务返回错误
+type jobFailure struct {
+    err error
+}
+
+func (e *JobError) Error() string {
+    if e.Panic {
+        return "panic: " + e.Err.Error()
+    }
+    return e.Err.Error()
+}
+
+// 将返回错误的任务方法包装为任务方法，任务返回的错误将被交给错误处理方法处理并计入连续失败次数，例如:
+// gtimer.Add(time.Second, gtimer.ErrorJob(func() error { return sync() }))
+func ErrorJob(job func() error) JobFunc {
+    return func() {
+        if err := job(); err != nil {
+            panic(&jobFailure{err})
+        }
+    }
+}
+
+// 执行任务方法，捕获任务的panic以及ErrorJob返回的错误，任务中调用Exit时exit为true
+func Call(job JobFunc) (exit bool, err *JobError) {
+    defer func() {
+        if v := recover(); v != nil {
+            exit, err = recoverJob(v)
+        }
+    }()
+    job()
+    return
+}
+
+// 将recover得到的内容转换为任务运行错误
+func recoverJob(v interface{}) (exit bool, err *JobError) {
+    switch value := v.(type) {
+        case string:
+            if value == gPANIC_EXIT {
+                return true, nil
+            }
+            return false, &JobError{Err : errors.New(value), Panic : true, Stack : string(debug.Stack())}
+        case *jobFailure:
+            return false, &JobError{Err : value.err}
+        case error:
+            return false, &JobError{Err : value, Panic : true, Stack : string(debug.Stack())}
+        default:
+            return false, &JobError{Err : errors.New(fmt.Sprintf("%v", v)), Panic : true, Stack : string(debug.Stack())}
+    }
+}
+
+// 设置任务错误处理方法，为nil时使用默认的错误处理方法(输出错误及调用栈到标准错误输出)
+func (t *Timer) SetErrorHandler(handler ErrorHandler) {
+    t.errorHandler.Set(handler)
+}
+
+// 设置任务连续失败次数的阈值，达到阈值时任务将被停止(可通过Start重新启动)，0表示不限制
+func (entry *Entry) SetMaxFailures(max int) {
+    entry.maxFailures.Set(max)
+}
+
+// 获取任务连续失败次数的阈值
+func (entry *Entry) MaxFailures() int {
+    return entry.maxFailures.Val()
+}
+
+// 获取任务连续失败次数，任务成功运行后清零
+func (entry *Entry) Failures() int {
+    return entry.failures.Val()
+}
+
+// 记录任务运行失败，达到连续失败次数阈值时停止任务，并调用错误处理方法
+func (entry *Entry) fail(err *JobError) {
+    err.Failures = entry.failures.Add(1)
+    if max := entry.maxFailures.Val(); max > 0 && err.Failures >= max {
+        entry.status.Set(STATUS_STOPPED)
+        err.Disabled = true
+    }
+    handler, _ := entry.wheel.timer.errorHandler.Val().(ErrorHandler)
+    if handler == nil {
+        handler = defaultErrorHandler
+    }
+    handler(entry.root, err)
+}
+
+// 默认的任务错误处理方法
+func defaultErrorHandler(entry *Entry, err *JobError) {
+    content := fmt.Sprintf("%s [gtimer] job failed (%d consecutive): %s\n", time.Now().Format("2006-01-02 15:04:05.000"), err.Failures, err.Error())
+    if err.Stack != "" {
+        content += err.Stack
+    }
+    if err.Disabled {
+        content += "[gtimer] job stopped after reaching max failures\n"
+    }
+    fmt.Fprint(os.Stderr, content)
+}
@@ -53,24 +53,25 @@ func (w *wheel) proceed() {
                 if runnable {
                     // 异步执行运行
                     go func(entry *Entry) {
-                        defer func() {
-                            if err := recover(); err != nil {
-                                if err != gPANIC_EXIT {
-                                    panic(err)
-                                } else {
-                                    entry.Close()
-                                }
-                            }
-                            if entry.Status() == STATUS_RUNNING {
-                                entry.SetStatus(STATUS_READY)
-                            }
-                        }()
-                        entry.job()
+                        exit, err := Call(entry.job)
+                        switch {
+                            case exit:
+                                entry.Close()
+                            case err != nil:
+                                entry.fail(err)
+                            default:
+                                entry.failures.Set(0)
+                        }
+                        if entry.Status() == STATUS_RUNNING {
+                            entry.SetStatus(STATUS_READY)
+                        }
                     }(entry)
                 }
                 // 是否继续添运行, 滚动任务
                 if addable {
                     entry.wheel.timer.doAddEntryByParent(entry.rawIntervalMs, entry)
+                } else if entry.status.Val() == STATUS_CLOSED {
+                    entry.wheel.timer.entries.Remove(entry.root)
                 }
             }
         }(l, n)
@@ -8,17 +8,21 @@ package gtimer
 
 import (
     "github.com/gogf/gf/g/container/glist"
+    "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/container/gtype"
+    "sort"
     "time"
 )
 
 // 定时器/分层时间轮
 type Timer struct {
-    status     *gtype.Int      // 定时器状态
-    wheels     []*wheel        // 分层时间轮对象
-    length     int             // 分层层数
-    number     int             // 每一层Slot Number
-    intervalMs int64           // 最小时间刻度(毫秒)
+    status       *gtype.Int       // 定时器状态
+    wheels       []*wheel         // 分层时间轮对象
+    length       int              // 分层层数
+    number       int              // 每一层Slot Number
+    intervalMs   int64            // 最小时间刻度(毫秒)
+    entries      *gmap.Map        // 所有已添加并且未关闭的任务项
+    errorHandler *gtype.Interface // 任务错误处理方法
 }
 
 // 单层时间轮
@@ -33,18 +37,28 @@ type wheel struct {
     intervalMs int64           // 时间间隔(slot时间长度, 毫秒)
 }
 
-// 创建分层时间轮
+// 创建分层时间轮，slot为每一层时间轮的刻度数量，interval为最底层时间轮的刻度间隔(最小为1毫秒)，
+// level为时间轮的层数(默认为6)，第n层的刻度间隔为第n-1层的时间轮总长度(slot*interval)。
+// 参数不合法时使用默认值，层数及刻度数量决定了可高效管理的最大任务间隔，刻度间隔决定了任务运行的精度。
 func New(slot int, interval time.Duration, level...int) *Timer {
     length := gDEFAULT_WHEEL_LEVEL
-    if len(level) > 0 {
+    if len(level) > 0 && level[0] > 0 {
         length = level[0]
     }
+    if slot <= 0 {
+        slot = gDEFAULT_SLOT_NUMBER
+    }
+    if interval < time.Millisecond {
+        interval = gDEFAULT_WHEEL_INTERVAL*time.Millisecond
+    }
     t := &Timer {
-        status     : gtype.NewInt(STATUS_RUNNING),
-        wheels     : make([]*wheel, length),
-        length     : length,
-        number     : slot,
-        intervalMs : interval.Nanoseconds()/1e6,
+        status       : gtype.NewInt(STATUS_RUNNING),
+        wheels       : make([]*wheel, length),
+        length       : length,
+        number       : slot,
+        intervalMs   : interval.Nanoseconds()/1e6,
+        entries      : gmap.New(),
+        errorHandler : gtype.NewInterface(),
     }
     for i := 0; i < length; i++ {
         if i > 0 {
@@ -153,9 +167,46 @@ func (t *Timer) Close() {
     t.status.Set(STATUS_CLOSED)
 }
 
+// 获取时间轮的层数
+func (t *Timer) Level() int {
+    return t.length
+}
+
+// 获取每一层时间轮的刻度数量
+func (t *Timer) Slots() int {
+    return t.number
+}
+
+// 获取最底层时间轮的刻度间隔
+func (t *Timer) Interval() time.Duration {
+    return time.Duration(t.intervalMs)*time.Millisecond
+}
+
+// 获取所有未关闭的任务项，按照下一次运行时间从小到大排序
+func (t *Timer) Jobs() []*Entry {
+    entries := make([]*Entry, 0, t.entries.Size())
+    t.entries.RLockFunc(func(m map[interface{}]interface{}) {
+        for k := range m {
+            if entry := k.(*Entry); entry.Status() != STATUS_CLOSED {
+                entries = append(entries, entry)
+            }
+        }
+    })
+    next := make(map[*Entry]time.Time, len(entries))
+    for _, entry := range entries {
+        next[entry] = entry.Next()
+    }
+    sort.Slice(entries, func(i, j int) bool {
+        return next[entries[i]].Before(next[entries[j]])
+    })
+    return entries
+}
+
 // 添加定时任务
 func (t *Timer) doAddEntry(interval time.Duration, job JobFunc, singleton bool, times int, status int) *Entry {
-    return t.wheels[t.getLevelByIntervalMs(interval.Nanoseconds()/1e6)].addEntry(interval, job, singleton, times, status)
+    entry := t.wheels[t.getLevelByIntervalMs(interval.Nanoseconds()/1e6)].addEntry(interval, job, singleton, times, status)
+    t.entries.Set(entry, entry)
+    return entry
 }
 
 // 添加定时任务，给定父级Entry, 间隔参数参数为毫秒数.
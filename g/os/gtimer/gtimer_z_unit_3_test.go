@@ -0,0 +1,79 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Pause/Resume/Reset & Jobs
+
+package gtimer_test
+
+import (
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/os/gtimer"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestEntry_Pause_Resume(t *testing.T) {
+    timer := New()
+    array := garray.New()
+    entry := timer.Add(400*time.Millisecond, func() {
+        array.Append(1)
+    })
+    time.Sleep(200*time.Millisecond)
+    entry.Pause()
+    gtest.Assert(entry.IsPaused(), true)
+    gtest.Assert(entry.Status(), gtimer.STATUS_STOPPED)
+    time.Sleep(500*time.Millisecond)
+    gtest.Assert(array.Len(), 0)
+    // 恢复后在剩余的约200毫秒之后运行
+    entry.Resume()
+    gtest.Assert(entry.IsPaused(), false)
+    time.Sleep(300*time.Millisecond)
+    gtest.Assert(array.Len(), 1)
+    time.Sleep(400*time.Millisecond)
+    gtest.Assert(array.Len(), 2)
+    entry.Close()
+}
+
+func TestEntry_Reset(t *testing.T) {
+    timer := New()
+    array := garray.New()
+    entry := timer.Add(400*time.Millisecond, func() {
+        array.Append(1)
+    })
+    time.Sleep(300*time.Millisecond)
+    entry.Reset()
+    time.Sleep(300*time.Millisecond)
+    gtest.Assert(array.Len(), 0)
+    time.Sleep(200*time.Millisecond)
+    gtest.Assert(array.Len(), 1)
+    entry.Close()
+}
+
+func TestTimer_Jobs(t *testing.T) {
+    timer  := gtimer.New(10, 10*time.Millisecond, 3)
+    gtest.Assert(timer.Level(), 3)
+    gtest.Assert(timer.Slots(), 10)
+    gtest.Assert(timer.Interval(), 10*time.Millisecond)
+    entry1 := timer.Add(2*time.Second, func() {})
+    entry2 := timer.Add(time.Second, func() {})
+    entry3 := timer.AddOnce(100*time.Millisecond, func() {})
+    jobs   := timer.Jobs()
+    gtest.Assert(len(jobs), 3)
+    gtest.Assert(jobs[0], entry3)
+    gtest.Assert(jobs[1], entry2)
+    gtest.Assert(jobs[2], entry1)
+    gtest.Assert(entry2.Interval(), time.Second)
+    gtest.AssertLTE(entry2.Next().Sub(time.Now()).Nanoseconds()/1e6, 1000)
+    gtest.AssertGT(entry2.Next().Sub(time.Now()).Nanoseconds()/1e6, 900)
+    time.Sleep(300*time.Millisecond)
+    jobs = timer.Jobs()
+    gtest.Assert(len(jobs), 2)
+    gtest.Assert(jobs[0], entry2)
+    entry1.Close()
+    gtest.Assert(len(timer.Jobs()), 1)
+    gtest.Assert(entry1.Next().IsZero(), true)
+}
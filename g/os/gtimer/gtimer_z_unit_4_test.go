@@ -0,0 +1,78 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Error Handling
+
+package gtimer_test
+
+import (
+    "errors"
+    "github.com/gogf/gf/g/container/garray"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/os/gtimer"
+    "github.com/gogf/gf/g/test/gtest"
+    "testing"
+    "time"
+)
+
+func TestTimer_ErrorHandler(t *testing.T) {
+    timer  := New()
+    errs   := garray.New()
+    timer.SetErrorHandler(func(entry *gtimer.Entry, err *gtimer.JobError) {
+        errs.Append(err)
+    })
+    // panic
+    entry1 := timer.AddOnce(100*time.Millisecond, func() {
+        panic("job panic")
+    })
+    time.Sleep(250*time.Millisecond)
+    gtest.Assert(errs.Len(), 1)
+    err := errs.Get(0).(*gtimer.JobError)
+    gtest.Assert(err.Panic, true)
+    gtest.Assert(err.Error(), "panic: job panic")
+    gtest.Assert(err.Stack != "", true)
+    gtest.Assert(err.Failures, 1)
+    gtest.Assert(entry1.Failures(), 1)
+    // 返回错误
+    entry2 := timer.AddOnce(100*time.Millisecond, gtimer.ErrorJob(func() error {
+        return errors.New("job error")
+    }))
+    time.Sleep(250*time.Millisecond)
+    gtest.Assert(errs.Len(), 2)
+    err = errs.Get(1).(*gtimer.JobError)
+    gtest.Assert(err.Panic, false)
+    gtest.Assert(err.Error(), "job error")
+    gtest.Assert(err.Stack, "")
+    gtest.Assert(entry2.Failures(), 1)
+}
+
+func TestEntry_MaxFailures(t *testing.T) {
+    timer   := New()
+    count   := garray.New()
+    failing := gtype.NewBool(true)
+    timer.SetErrorHandler(func(entry *gtimer.Entry, err *gtimer.JobError) {})
+    entry := timer.Add(100*time.Millisecond, gtimer.ErrorJob(func() error {
+        count.Append(1)
+        if failing.Val() {
+            return errors.New("error")
+        }
+        return nil
+    }))
+    entry.SetMaxFailures(3)
+    gtest.Assert(entry.MaxFailures(), 3)
+    time.Sleep(550*time.Millisecond)
+    gtest.Assert(count.Len(), 3)
+    gtest.Assert(entry.Failures(), 3)
+    gtest.Assert(entry.Status(), gtimer.STATUS_STOPPED)
+    // 重新启动后成功运行，连续失败次数清零
+    failing.Set(false)
+    entry.Start()
+    gtest.Assert(entry.Failures(), 0)
+    time.Sleep(150*time.Millisecond)
+    gtest.AssertGT(count.Len(), 3)
+    gtest.Assert(entry.Failures(), 0)
+    entry.Close()
+}
@@ -18,7 +18,6 @@ import (
     "github.com/gogf/gf/g/encoding/ghash"
     "github.com/gogf/gf/g/encoding/ghtml"
     "github.com/gogf/gf/g/encoding/gurl"
-    "github.com/gogf/gf/g/os/gfcache"
     "github.com/gogf/gf/g/os/gfile"
     "github.com/gogf/gf/g/os/glog"
     "github.com/gogf/gf/g/os/gspath"
@@ -37,6 +36,9 @@ type View struct {
     data       map[string]interface{}  // 模板变量
     funcmap    map[string]interface{}  // FuncMap
     delimiters []string                // 模板变量分隔符号
+    i18n       *viewI18n               // 国际化翻译数据
+    cache      *viewCache              // 模板解析缓存
+    escape     int                     // 模板输出转义策略
 }
 
 // 模板变量
@@ -74,6 +76,8 @@ func New(path...string) *View {
         data       : make(map[string]interface{}),
         funcmap    : make(map[string]interface{}),
         delimiters : make([]string, 2),
+        i18n       : newViewI18n(),
+        cache      : newViewCache(),
     }
     if len(path) > 0 && len(path[0]) > 0 {
         view.SetPath(path[0])
@@ -101,6 +105,9 @@ func New(path...string) *View {
     view.BindFunc("tolower",     view.funcToLower)
     view.BindFunc("nl2br",       view.funcNl2Br)
     view.BindFunc("include",     view.funcInclude)
+    view.BindFunc("raw",         view.funcRaw)
+    view.BindFunc("T",           view.funcT)
+    view.BindFunc("Tn",          view.funcTn)
     return view
 }
 
@@ -121,6 +128,7 @@ func (view *View) SetPath(path string) error {
     }
     view.paths.Clear()
     view.paths.Append(realPath)
+    view.ClearCache()
     //glog.Debug("[gview] SetPath:", realPath)
     return nil
 }
@@ -141,6 +149,7 @@ func (view *View) AddPath(path string) error {
         return nil
     }
     view.paths.Append(realPath)
+    view.ClearCache()
     //glog.Debug("[gview] AddPath:", realPath)
     return nil
 }
@@ -161,9 +170,38 @@ func (view *View) Assign(key string, value interface{}) {
     view.mu.Unlock()
 }
 
-// 解析模板，返回解析后的内容
+// 解析模板，返回解析后的内容。
+// 模板支持布局继承，在模板开头使用{{extends "layout.html"}}声明继承的布局模板，
+// 并通过{{define "name"}}...{{end}}覆盖布局模板中使用{{block "name" .}}...{{end}}声明的同名区块。
 func (view *View) Parse(file string, params Params, funcmap...map[string]interface{}) ([]byte, error) {
-    path := ""
+    path, err := view.searchFile(file)
+    if err != nil {
+        return nil, err
+    }
+    // 执行模板解析，互斥锁主要是用于funcmap
+    view.mu.RLock()
+    defer view.mu.RUnlock()
+    tpl, err := view.getTemplate(path, view.i18nFuncMap(params, funcmap...)...)
+    if err != nil {
+        return nil, err
+    }
+    return view.executeTemplate(tpl, params)
+}
+
+// 直接解析模板内容，返回解析后的内容(模板内容不会被缓存)
+func (view *View) ParseContent(content string, params Params, funcmap...map[string]interface{}) ([]byte, error) {
+    view.mu.RLock()
+    defer view.mu.RUnlock()
+    name        := gconv.String(ghash.BKDRHash64([]byte(content)))
+    tpl, _, err := view.parseTemplate(name, content, view.i18nFuncMap(params, funcmap...)...)
+    if err != nil {
+        return nil, err
+    }
+    return view.executeTemplate(tpl, params)
+}
+
+// 在模板目录中查找模板文件，返回模板文件的绝对路径
+func (view *View) searchFile(file string) (path string, err error) {
     view.paths.RLockFunc(func(array []string) {
         for _, v := range array {
             if path, _ = gspath.Search(v, file); path != "" {
@@ -171,6 +209,9 @@ func (view *View) Parse(file string, params Params, funcmap...map[string]interfa
             }
         }
     })
+    if path == "" {
+        path = view.searchResource(file)
+    }
     if path == "" {
         buffer := bytes.NewBuffer(nil)
         if view.paths.Len() > 0 {
@@ -184,87 +225,45 @@ func (view *View) Parse(file string, params Params, funcmap...map[string]interfa
             buffer.WriteString(fmt.Sprintf("[gview] cannot find template file \"%s\" with no path set/add", file))
         }
         glog.Error(buffer.String())
-        return nil, errors.New(fmt.Sprintf(`tpl "%s" not found`, file))
+        return "", errors.New(fmt.Sprintf(`tpl "%s" not found`, file))
     }
-    content := gfcache.GetContents(path)
-    // 执行模板解析，互斥锁主要是用于funcmap
-    view.mu.RLock()
-    defer view.mu.RUnlock()
-    buffer := bytes.NewBuffer(nil)
-    tplobj := template.New(path).Delims(view.delimiters[0], view.delimiters[1]).Funcs(view.funcmap)
-    if len(funcmap) > 0 {
-        tplobj = tplobj.Funcs(funcmap[0])
-    }
-    if tpl, err := tplobj.Parse(content); err != nil {
-        return nil, err
-    } else {
-        // 注意模板变量赋值不能改变已有的params或者view.data的值，因为这两个变量都是指针
-        // 因此在必要条件下，需要合并两个map的值到一个新的map
-        vars := (map[string]interface{})(nil)
-        if len(view.data) > 0 {
-            if len(params) > 0 {
-                vars = make(map[string]interface{}, len(view.data) + len(params))
-                for k, v := range params {
-                    vars[k] = v
-                }
-                for k, v := range view.data {
-                    vars[k] = v
-                }
-            } else {
-                vars = view.data
+    return path, nil
+}
+
+// 执行模板，返回解析后的内容
+func (view *View) executeTemplate(tpl *template.Template, params Params) ([]byte, error) {
+    // 注意模板变量赋值不能改变已有的params或者view.data的值，因为这两个变量都是指针
+    // 因此在必要条件下，需要合并两个map的值到一个新的map
+    vars := (map[string]interface{})(nil)
+    if len(view.data) > 0 {
+        if len(params) > 0 {
+            vars = make(map[string]interface{}, len(view.data) + len(params))
+            for k, v := range params {
+                vars[k] = v
+            }
+            for k, v := range view.data {
+                vars[k] = v
             }
         } else {
-            vars = params
-        }
-        if err := tpl.Execute(buffer, vars); err != nil {
-            return nil, err
+            vars = view.data
         }
+    } else {
+        vars = params
     }
-    return buffer.Bytes(), nil
-}
-
-// 直接解析模板内容，返回解析后的内容
-func (view *View) ParseContent(content string, params Params, funcmap...map[string]interface{}) ([]byte, error) {
-    view.mu.RLock()
-    defer view.mu.RUnlock()
-    name   := gconv.String(ghash.BKDRHash64([]byte(content)))
     buffer := bytes.NewBuffer(nil)
-    tplobj := template.New(name).Delims(view.delimiters[0], view.delimiters[1]).Funcs(view.funcmap)
-    if len(funcmap) > 0 {
-        tplobj = tplobj.Funcs(funcmap[0])
-    }
-    if tpl, err := tplobj.Parse(content); err != nil {
+    if err := tpl.Execute(buffer, vars); err != nil {
         return nil, err
-    } else {
-        // 注意模板变量赋值不能改变已有的params或者view.data的值，因为这两个变量都是指针
-        // 因此在必要条件下，需要合并两个map的值到一个新的map
-        vars := (map[string]interface{})(nil)
-        if len(view.data) > 0 {
-            if len(params) > 0 {
-                vars = make(map[string]interface{}, len(view.data) + len(params))
-                for k, v := range params {
-                    vars[k] = v
-                }
-                for k, v := range view.data {
-                    vars[k] = v
-                }
-            } else {
-                vars = view.data
-            }
-        } else {
-            vars = params
-        }
-        if err := tpl.Execute(buffer, vars); err != nil {
-            return nil, err
-        }
     }
     return buffer.Bytes(), nil
 }
 
-// 设置模板变量解析分隔符号
+// 设置模板变量解析分隔符号，用于避免与前端框架的模板语法冲突，例如: SetDelimiters("${", "}")
 func (view *View) SetDelimiters(left, right string) {
+    view.mu.Lock()
     view.delimiters[0] = left
     view.delimiters[1] = right
+    view.mu.Unlock()
+    view.ClearCache()
 }
 
 // 绑定自定义函数，该函数是全局有效，即调用之后每个线程都会生效，因此有并发安全控制
@@ -272,19 +271,45 @@ func (view *View) BindFunc(name string, function interface{}) {
     view.mu.Lock()
     view.funcmap[name] = function
     view.mu.Unlock()
+    view.ClearCache()
+}
+
+// 模板内置方法：include，参数可以为模板变量map，或者键值对形式的模板变量，例如:
+// {{include "header.html" .}} 或者 {{include "header.html" "title" "首页" "active" 1}}
+func (view *View) funcInclude(file string, data...interface{}) HTML {
+    return view.include("", file, data...)
 }
 
-// 模板内置方法：include
-func (view *View) funcInclude(file string, data...map[string]interface{}) string {
+// 解析被包含的模板，language不为空时将其作为被包含模板的当前语言(模板变量中未指定语言时)
+func (view *View) include(language string, file string, data...interface{}) HTML {
     var m map[string]interface{} = nil
-    if len(data) > 0 {
-        m = data[0]
+    if len(data) == 1 {
+        m = gconv.Map(data[0])
+    } else if len(data) > 1 {
+        m = make(map[string]interface{}, len(data)/2)
+        for i := 0; i + 1 < len(data); i += 2 {
+            m[gconv.String(data[i])] = data[i + 1]
+        }
+    }
+    if _, ok := m[I18N_LANGUAGE_KEY]; !ok && language != "" {
+        // 不能修改调用方传递的模板变量
+        params := make(map[string]interface{}, len(m) + 1)
+        for k, v := range m {
+            params[k] = v
+        }
+        params[I18N_LANGUAGE_KEY] = language
+        m = params
     }
     content, err := view.Parse(file, m)
     if err != nil {
-        return err.Error()
+        return HTML(ghtml.SpecialChars(err.Error()))
     }
-    return string(content)
+    return HTML(content)
+}
+
+// 模板内置方法：raw，输出内容不进行转义
+func (view *View) funcRaw(str interface{}) HTML {
+    return HTML(gconv.String(str))
 }
 
 // 模板内置方法：text
@@ -293,8 +318,8 @@ func (view *View) funcText(html interface{}) string {
 }
 
 // 模板内置方法：html
-func (view *View) funcHtmlEncode(html interface{}) string {
-    return ghtml.Entities(gconv.String(html))
+func (view *View) funcHtmlEncode(html interface{}) HTML {
+    return HTML(ghtml.Entities(gconv.String(html)))
 }
 
 // 模板内置方法：htmldecode
@@ -343,9 +368,15 @@ func (view *View) funcStrLimit(length int, suffix string, str interface{}) strin
     return gstr.StrLimit(gconv.String(str), length, suffix)
 }
 
-// 模板内置方法：highlight
-func (view *View) funcHighlight(key string, color string, str interface{}) string {
-    return gstr.Replace(gconv.String(str), key, fmt.Sprintf(`<span style="color:%s;">%s</span>`, color, key))
+// 模板内置方法：highlight，开启输出转义时先对内容进行转义
+func (view *View) funcHighlight(key string, color string, str interface{}) HTML {
+    content := gconv.String(str)
+    if view.escape != ESCAPE_NONE {
+        content = ghtml.SpecialChars(content)
+        key     = ghtml.SpecialChars(key)
+        color   = ghtml.SpecialChars(color)
+    }
+    return HTML(gstr.Replace(content, key, fmt.Sprintf(`<span style="color:%s;">%s</span>`, color, key)))
 }
 
 // 模板内置方法：hidestr
@@ -363,9 +394,13 @@ func (view *View) funcToLower(str interface{}) string {
     return gstr.ToLower(gconv.String(str))
 }
 
-// 模板内置方法：nl2br
-func (view *View) funcNl2Br(str interface{}) string {
-    return gstr.Nl2Br(gconv.String(str))
+// 模板内置方法：nl2br，开启输出转义时先对内容进行转义
+func (view *View) funcNl2Br(str interface{}) HTML {
+    content := gconv.String(str)
+    if view.escape != ESCAPE_NONE {
+        content = ghtml.SpecialChars(content)
+    }
+    return HTML(gstr.Nl2Br(content))
 }
 
 
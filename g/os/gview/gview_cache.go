@@ -0,0 +1,187 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gview
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gfsnotify"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/os/gview/internal/text/template"
+    "strings"
+)
+
+const (
+    // 预编译时默认查找的模板文件名称模式
+    gDEFAULT_PRECOMPILE_PATTERN = "*.html,*.htm,*.tpl,*.tmpl"
+)
+
+// 模板解析缓存，模板文件(包括继承的布局模板)发生变化时通过gfsnotify自动清除对应的缓存
+type viewCache struct {
+    enabled  *gtype.Bool                // 是否开启缓存
+    entries  *gmap.StringInterfaceMap   // 模板文件绝对路径对应的缓存项
+    watched  *gmap.StringInterfaceMap   // 已添加文件监控的文件路径
+}
+
+// 模板解析缓存项
+type viewCacheEntry struct {
+    tpl   *template.Template // 解析后的模板对象
+    files []string           // 模板依赖的文件列表(模板文件及继承的布局模板)
+}
+
+// 创建模板解析缓存
+func newViewCache() *viewCache {
+    return &viewCache {
+        enabled : gtype.NewBool(true),
+        entries : gmap.NewStringInterfaceMap(),
+        watched : gmap.NewStringInterfaceMap(),
+    }
+}
+
+// 设置是否开启模板解析缓存(默认开启)。开启时模板文件只在首次使用或者发生变化时解析；
+// 关闭时(开发模式)每次解析都会重新读取并解析模板文件，用于文件监控不可用的开发环境。
+func (view *View) SetCache(enabled bool) {
+    view.cache.enabled.Set(enabled)
+    if !enabled {
+        view.ClearCache()
+    }
+}
+
+// 判断是否开启了模板解析缓存
+func (view *View) IsCache() bool {
+    return view.cache.enabled.Val()
+}
+
+// 清除所有的模板解析缓存
+func (view *View) ClearCache() {
+    view.cache.entries.Clear()
+}
+
+// 预编译模板目录下的所有模板文件(*.html/*.htm/*.tpl/*.tmpl)并写入缓存，
+// 返回所有模板文件的解析错误，用于在服务启动时发现模板错误，而不是在首次渲染时。
+// 可选参数funcmap用于声明解析时才绑定的模板函数(例如ghttp的get/post/request)，否则使用这些函数的模板将会解析失败。
+func (view *View) Precompile(funcmap...map[string]interface{}) error {
+    view.mu.RLock()
+    defer view.mu.RUnlock()
+    buffer := bytes.NewBuffer(nil)
+    for _, path := range view.paths.Slice() {
+        files, err := gfile.ScanDir(path, gDEFAULT_PRECOMPILE_PATTERN, true)
+        if err != nil {
+            return err
+        }
+        for _, file := range files {
+            if gfile.IsDir(file) {
+                continue
+            }
+            if _, err := view.getTemplate(file, funcmap...); err != nil {
+                if buffer.Len() > 0 {
+                    buffer.WriteString("\n")
+                }
+                buffer.WriteString(fmt.Sprintf(`%s: %s`, file, err.Error()))
+            }
+        }
+    }
+    // 打包资源中的模板文件
+    if !gres.IsEmpty() {
+        for _, dir := range view.resourceDirs() {
+            if dir == "" {
+                continue
+            }
+            for _, file := range gres.ScanDir(dir, gDEFAULT_PRECOMPILE_PATTERN, true) {
+                if file.IsDir() {
+                    continue
+                }
+                if _, err := view.getTemplate(gRESOURCE_PATH_PREFIX + file.Path(), funcmap...); err != nil {
+                    if buffer.Len() > 0 {
+                        buffer.WriteString("\n")
+                    }
+                    buffer.WriteString(fmt.Sprintf(`%s: %s`, file.Path(), err.Error()))
+                }
+            }
+        }
+    }
+    if buffer.Len() > 0 {
+        return errors.New(buffer.String())
+    }
+    return nil
+}
+
+// 获取模板文件解析后的模板对象，开启缓存时优先从缓存中获取。
+// 缓存的模板对象与解析时绑定的函数无关(模板引用的函数在解析时必须已存在)，
+// 因此使用缓存时需要复制模板对象后再绑定本次解析的函数。
+func (view *View) getTemplate(path string, funcmap...map[string]interface{}) (*template.Template, error) {
+    if !view.cache.enabled.Val() {
+        tpl, _, err := view.parseTemplate(path, view.getContents(path), funcmap...)
+        return tpl, err
+    }
+    if v := view.cache.entries.Get(path); v != nil {
+        tpl := v.(*viewCacheEntry).tpl
+        if len(funcmap) > 0 && len(funcmap[0]) > 0 {
+            tpl, err := tpl.Clone()
+            if err != nil {
+                return nil, err
+            }
+            return tpl.Funcs(funcmap[0]), nil
+        }
+        return tpl, nil
+    }
+    tpl, layouts, err := view.parseTemplate(path, view.getContents(path), funcmap...)
+    if err != nil {
+        return nil, err
+    }
+    entry := &viewCacheEntry {
+        tpl   : tpl,
+        files : append([]string{path}, layouts...),
+    }
+    // 文件监控失败时不进行缓存，防止模板文件变化后仍然使用旧的缓存
+    monitored := true
+    for _, file := range entry.files {
+        if !view.addMonitor(file) {
+            monitored = false
+        }
+    }
+    if monitored {
+        view.cache.entries.Set(path, entry)
+    }
+    // 缓存的模板对象不能被本次解析的函数修改，因此返回复制的对象
+    if len(funcmap) > 0 && len(funcmap[0]) > 0 {
+        return tpl.Clone()
+    }
+    return tpl, nil
+}
+
+// 添加模板文件监控，文件发生变化时清除依赖该文件的模板缓存，返回是否监控成功
+func (view *View) addMonitor(path string) bool {
+    // 打包资源中的模板文件不会发生变化
+    if strings.HasPrefix(path, gRESOURCE_PATH_PREFIX) {
+        return true
+    }
+    if !view.cache.watched.SetIfNotExist(path, struct{}{}) {
+        return true
+    }
+    _, err := gfsnotify.Add(path, func(event *gfsnotify.Event) {
+        view.cache.entries.LockFunc(func(m map[string]interface{}) {
+            for k, v := range m {
+                for _, file := range v.(*viewCacheEntry).files {
+                    if file == event.Path {
+                        delete(m, k)
+                        break
+                    }
+                }
+            }
+        })
+    })
+    if err != nil {
+        view.cache.watched.Remove(path)
+        return false
+    }
+    return true
+}
@@ -0,0 +1,741 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gview
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/encoding/ghtml"
+    "github.com/gogf/gf/g/os/gview/internal/text/template"
+    "github.com/gogf/gf/g/os/gview/internal/text/template/parse"
+    "net/url"
+    "sort"
+    "strings"
+    "unicode/utf8"
+)
+
+const (
+    ESCAPE_AUTO = 0 // 模板输出转义策略：根据输出位置自动转义(HTML文本/属性、JS代码/字符串、CSS、URL属性)，默认
+    ESCAPE_HTML = 1 // 模板输出转义策略：所有输出均使用HTML转义
+    ESCAPE_NONE = 2 // 模板输出转义策略：不转义(用于生成非HTML内容)
+)
+
+// 不需要转义的HTML内容，模板函数返回该类型时输出内容不会被转义，例如: {{raw .content}}
+type HTML string
+
+const (
+    gESCAPE_FUNC_HTML      = "_gview_escape_html"      // 转义方法：HTML文本/属性
+    gESCAPE_FUNC_JS        = "_gview_escape_js"        // 转义方法：JS值
+    gESCAPE_FUNC_JS_STRING = "_gview_escape_js_string" // 转义方法：JS字符串内容
+    gESCAPE_FUNC_JS_REGEXP = "_gview_escape_js_regexp" // 转义方法：JS正则表达式内容
+    gESCAPE_FUNC_JS_ATTR   = "_gview_escape_js_attr"   // 转义方法：事件属性(on*)中的JS值
+    gESCAPE_FUNC_CSS       = "_gview_escape_css"       // 转义方法：style标签中的CSS值
+    gESCAPE_FUNC_CSS_ATTR  = "_gview_escape_css_attr"  // 转义方法：style属性中的CSS值
+    gESCAPE_FUNC_URL       = "_gview_escape_url"       // 转义方法：URL属性的开头部分(过滤不安全的协议)
+    gESCAPE_FUNC_URL_PART  = "_gview_escape_url_part"  // 转义方法：URL属性的参数部分
+    gESCAPE_FUNC_COMMENT   = "_gview_escape_comment"   // 转义方法：JS注释中的输出(不输出内容)
+    gESCAPE_UNQUOTED       = "_unquoted"               // 转义方法后缀：不带引号的属性值
+    gESCAPE_FAILSAFE       = "ZgotmplZ"                // 输出内容不安全时使用的替代内容
+)
+
+const (
+    gESCAPE_STATE_TEXT   = iota // HTML文本
+    gESCAPE_STATE_TAG           // HTML标签内(属性名称位置)
+    gESCAPE_STATE_ATTR          // HTML属性值
+    gESCAPE_STATE_SCRIPT        // script标签内的JS代码
+    gESCAPE_STATE_STYLE         // style标签内的CSS代码
+)
+
+var (
+    // 转义方法
+    escapeFuncs = map[string]interface{} {
+        gESCAPE_FUNC_HTML      : escapeHtml,
+        gESCAPE_FUNC_JS        : escapeJs,
+        gESCAPE_FUNC_JS_STRING : escapeJsString,
+        gESCAPE_FUNC_JS_REGEXP : escapeJsRegexp,
+        gESCAPE_FUNC_JS_ATTR   : escapeJsAttr,
+        gESCAPE_FUNC_CSS       : escapeCss,
+        gESCAPE_FUNC_CSS_ATTR  : escapeCssAttr,
+        gESCAPE_FUNC_URL       : escapeUrl,
+        gESCAPE_FUNC_URL_PART  : escapeUrlPart,
+        gESCAPE_FUNC_COMMENT   : escapeComment,
+        // 不带引号的属性值
+        gESCAPE_FUNC_HTML     + gESCAPE_UNQUOTED : escapeUnquoted(escapeHtml),
+        gESCAPE_FUNC_JS_ATTR  + gESCAPE_UNQUOTED : escapeUnquoted(escapeJsAttr),
+        gESCAPE_FUNC_CSS_ATTR + gESCAPE_UNQUOTED : escapeUnquoted(escapeCssAttr),
+        gESCAPE_FUNC_URL      + gESCAPE_UNQUOTED : escapeUnquoted(escapeUrl),
+    }
+    // 值为URL的属性
+    escapeUrlAttrs = map[string]bool {
+        "href" : true, "src" : true, "action" : true, "formaction" : true, "cite" : true,
+        "background" : true, "poster" : true, "longdesc" : true, "codebase" : true, "data" : true,
+    }
+    // 允许的URL协议
+    escapeSafeSchemes = map[string]bool {
+        "http" : true, "https" : true, "mailto" : true, "ftp" : true, "tel" : true,
+    }
+    // 之后的'/'为正则表达式开始的JS关键字
+    escapeJsRegexpKeywords = map[string]bool {
+        "break" : true, "case" : true, "continue" : true, "delete" : true, "do" : true, "else" : true, "finally" : true,
+        "in" : true, "instanceof" : true, "return" : true, "throw" : true, "try" : true, "typeof" : true, "void" : true,
+    }
+)
+
+// 模板输出位置上下文
+type escapeContext struct {
+    state     int    // 当前位置状态
+    element   string // 当前标签名称(小写)
+    attr      string // 当前属性名称(小写)
+    quote     byte   // 属性值的引号，或者JS代码中字符串的引号('/'表示正则表达式)，0表示不在引号中
+    attrStart bool   // 是否位于属性值开头
+    jsBraces  string // script中模板字符串插值(${...})的括号栈，'$'表示插值的开始，'{'表示插值中的代码块
+    jsComment byte   // JS注释，'/'表示单行注释，'*'表示多行注释，0表示不在注释中
+    jsRegexp  bool   // JS代码中之后的'/'是否为正则表达式的开始(否则为除号)
+    jsClass   bool   // 是否位于JS正则表达式的字符集合([...])中
+}
+
+// 模板转义器，模板在不同的上下文中调用(例如: script标签中的{{template "name" .}})时，
+// 复制模板并按照调用位置的上下文转义，调用处使用复制后的模板名称
+type escaper struct {
+    view     *View
+    tpl      *template.Template
+    trees    map[string]*parse.Tree    // 转义前的模板语法树副本
+    output   map[string]escapeContext  // 已经转义(或者正在转义)的模板结束位置的上下文
+    progress map[string]bool           // 正在转义的模板，值为true表示被递归调用
+}
+
+// 设置模板输出的转义策略：ESCAPE_AUTO(默认)、ESCAPE_HTML、ESCAPE_NONE。
+// 自动转义时根据输出位置选择转义方式：HTML文本及属性使用HTML转义，script标签及on*事件属性中输出JS值
+// (位于JS字符串中时转义为字符串内容，JS注释中不输出)，style标签及style属性中过滤不安全的CSS值，
+// href/src等URL属性开头过滤javascript:等不安全协议，参数部分进行URL编码。
+// 不需要转义的内容可以使用raw方法输出，例如: {{raw .content}}，或者由模板函数返回gview.HTML类型。
+// 自动转义时if/range/with的各个分支(以及range的循环体)结束位置的上下文需要一致，否则解析模板时返回错误。
+func (view *View) SetEscape(policy int) {
+    view.mu.Lock()
+    view.escape = policy
+    view.mu.Unlock()
+    view.ClearCache()
+}
+
+// 获取模板输出的转义策略
+func (view *View) GetEscape() int {
+    view.mu.RLock()
+    defer view.mu.RUnlock()
+    return view.escape
+}
+
+// 为模板对象中所有模板的输出添加转义方法(需要在解析完成后调用)
+func (view *View) escapeTemplate(tpl *template.Template) error {
+    if view.escape == ESCAPE_NONE {
+        return nil
+    }
+    e := &escaper {
+        view     : view,
+        tpl      : tpl,
+        trees    : make(map[string]*parse.Tree),
+        output   : make(map[string]escapeContext),
+        progress : make(map[string]bool),
+    }
+    names := make([]string, 0)
+    for _, t := range tpl.Templates() {
+        if t.Tree != nil && t.Tree.Root != nil {
+            e.trees[t.Name()] = t.Tree.Copy()
+            names = append(names, t.Name())
+        }
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if _, _, err := e.escapeTemplate(name, escapeContext{}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 按照调用位置的上下文转义模板，返回实际调用的模板名称及模板结束位置的上下文，
+// 在HTML文本中调用时使用原模板，其他上下文中使用复制的模板
+func (e *escaper) escapeTemplate(name string, ctx escapeContext) (string, escapeContext, error) {
+    if e.trees[name] == nil {
+        // 模板不存在时由模板执行时返回错误
+        return name, ctx, nil
+    }
+    derived := name
+    if ctx != (escapeContext{}) {
+        derived = fmt.Sprintf("%s$%+v", name, ctx)
+    }
+    if end, ok := e.output[derived]; ok {
+        if _, ok := e.progress[derived]; ok {
+            e.progress[derived] = true
+        }
+        return derived, end, nil
+    }
+    tree := e.tpl.Lookup(name).Tree
+    if derived != name {
+        tree      = e.trees[name].Copy()
+        tree.Name = derived
+        if _, err := e.tpl.AddParseTree(derived, tree); err != nil {
+            return name, ctx, err
+        }
+    }
+    // 递归调用时假设模板结束位置的上下文与开始位置一致
+    e.output[derived]   = ctx
+    e.progress[derived] = false
+    end, err := e.escapeNode(tree, tree.Root, ctx)
+    if err != nil {
+        return name, ctx, err
+    }
+    if e.progress[derived] && end != ctx {
+        return name, ctx, errors.New(fmt.Sprintf(`template "%s" is called recursively and ends in a different context`, name))
+    }
+    delete(e.progress, derived)
+    e.output[derived] = end
+    return derived, end, nil
+}
+
+// 遍历模板语法树，返回节点结束位置的上下文
+func (e *escaper) escapeNode(tree *parse.Tree, node parse.Node, ctx escapeContext) (escapeContext, error) {
+    var err error
+    switch n := node.(type) {
+        case *parse.ListNode:
+            if n != nil {
+                for _, child := range n.Nodes {
+                    if ctx, err = e.escapeNode(tree, child, ctx); err != nil {
+                        return ctx, err
+                    }
+                }
+            }
+        case *parse.TextNode:
+            ctx = ctx.feed(n.Text)
+        case *parse.ActionNode:
+            if len(n.Pipe.Decl) == 0 {
+                name := ctx.escapeFunc(e.view.escape)
+                n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode {
+                    NodeType : parse.NodeCommand,
+                    Pos      : n.Position(),
+                    Args     : []parse.Node{parse.NewIdentifier(name).SetTree(nil).SetPos(n.Position())},
+                })
+                ctx.attrStart = false
+                // JS代码中输出的值之后的'/'为除号
+                if ctx.state == gESCAPE_STATE_SCRIPT && ctx.quote == 0 && ctx.jsComment == 0 {
+                    ctx.jsRegexp = false
+                }
+            }
+        case *parse.TemplateNode:
+            name, end, err := e.escapeTemplate(n.Name, ctx)
+            if err != nil {
+                return ctx, err
+            }
+            n.Name = name
+            return end, nil
+        case *parse.IfNode:
+            return e.escapeBranch(tree, n, ctx, n.List, n.ElseList, false)
+        case *parse.RangeNode:
+            return e.escapeBranch(tree, n, ctx, n.List, n.ElseList, true)
+        case *parse.WithNode:
+            return e.escapeBranch(tree, n, ctx, n.List, n.ElseList, false)
+    }
+    return ctx, nil
+}
+
+// 计算分支结束位置的上下文，loop为true时(range)循环体结束位置的上下文需要与开头一致
+func (e *escaper) escapeBranch(tree *parse.Tree, node parse.Node, ctx escapeContext, list, elseList *parse.ListNode, loop bool) (escapeContext, error) {
+    end, err := e.escapeNode(tree, list, ctx)
+    if err != nil {
+        return ctx, err
+    }
+    if loop {
+        if end, err = e.joinContext(tree, node, end, ctx); err != nil {
+            return ctx, err
+        }
+    }
+    elseEnd, err := e.escapeNode(tree, elseList, ctx)
+    if err != nil {
+        return ctx, err
+    }
+    return e.joinContext(tree, node, end, elseEnd)
+}
+
+// 合并分支结束位置的上下文，仅是否位于URL属性值开头不同时按照参数部分转义(更严格)，
+// 其他不一致的情况下无法确定后续输出的转义方式，自动转义时返回错误
+func (e *escaper) joinContext(tree *parse.Tree, node parse.Node, a, b escapeContext) (escapeContext, error) {
+    if a == b {
+        return a, nil
+    }
+    joined, other := a, b
+    joined.attrStart, other.attrStart = false, false
+    if joined == other || e.view.escape != ESCAPE_AUTO {
+        return joined, nil
+    }
+    location, context := tree.ErrorContext(node)
+    return a, errors.New(fmt.Sprintf(`%s: branches of %s end in different contexts`, location, context))
+}
+
+// 根据上下文及转义策略获取转义方法名称
+func (ctx escapeContext) escapeFunc(policy int) string {
+    name := gESCAPE_FUNC_HTML
+    if policy == ESCAPE_AUTO {
+        name = ctx.contextFunc()
+    }
+    // 不带引号的属性值需要额外转义空白及引号等字符，URL参数部分已经进行URL编码
+    if ctx.state == gESCAPE_STATE_ATTR && ctx.quote == 0 && name != gESCAPE_FUNC_URL_PART {
+        name += gESCAPE_UNQUOTED
+    }
+    return name
+}
+
+// 根据输出位置获取转义方法名称
+func (ctx escapeContext) contextFunc() string {
+    switch ctx.state {
+        case gESCAPE_STATE_SCRIPT:
+            switch {
+                case ctx.jsComment != 0:
+                    return gESCAPE_FUNC_COMMENT
+                case ctx.quote == '/':
+                    return gESCAPE_FUNC_JS_REGEXP
+                case ctx.quote != 0:
+                    return gESCAPE_FUNC_JS_STRING
+            }
+            return gESCAPE_FUNC_JS
+        case gESCAPE_STATE_STYLE:
+            return gESCAPE_FUNC_CSS
+        case gESCAPE_STATE_ATTR:
+            if strings.HasPrefix(ctx.attr, "on") {
+                return gESCAPE_FUNC_JS_ATTR
+            }
+            if ctx.attr == "style" {
+                return gESCAPE_FUNC_CSS_ATTR
+            }
+            if escapeUrlAttrs[ctx.attr] {
+                if ctx.attrStart {
+                    return gESCAPE_FUNC_URL
+                }
+                return gESCAPE_FUNC_URL_PART
+            }
+    }
+    return gESCAPE_FUNC_HTML
+}
+
+// 根据模板文本内容计算上下文
+func (ctx escapeContext) feed(text []byte) escapeContext {
+    for i := 0; i < len(text); i++ {
+        c := text[i]
+        switch ctx.state {
+            case gESCAPE_STATE_TEXT:
+                if c != '<' {
+                    continue
+                }
+                // HTML注释
+                if bytes.HasPrefix(text[i : ], []byte("<!--")) {
+                    if j := bytes.Index(text[i + 4 : ], []byte("-->")); j >= 0 {
+                        i += 4 + j + 2
+                    } else {
+                        i = len(text)
+                    }
+                    continue
+                }
+                j := i + 1
+                for j < len(text) && (isAsciiLetter(text[j]) || (j > i + 1 && text[j] >= '0' && text[j] <= '9')) {
+                    j++
+                }
+                if j > i + 1 {
+                    ctx.state   = gESCAPE_STATE_TAG
+                    ctx.element = strings.ToLower(string(text[i + 1 : j]))
+                    i = j - 1
+                } else if j < len(text) && text[j] == '/' {
+                    ctx.state   = gESCAPE_STATE_TAG
+                    ctx.element = ""
+                    i = j
+                }
+
+            case gESCAPE_STATE_TAG:
+                switch {
+                    case c == '>':
+                        switch ctx.element {
+                            case "script":
+                                ctx.state    = gESCAPE_STATE_SCRIPT
+                                ctx.quote    = 0
+                                ctx.jsBraces = ""
+                                ctx.jsRegexp = true
+                            case "style":
+                                ctx.state = gESCAPE_STATE_STYLE
+                            default:
+                                ctx.state = gESCAPE_STATE_TEXT
+                        }
+                        // 标签结束后不再需要标签及属性名称，保证相同位置的上下文一致
+                        ctx.element = ""
+                        ctx.attr    = ""
+                    case isAsciiLetter(c):
+                        j := i
+                        for j < len(text) && text[j] != '=' && text[j] != '>' && text[j] != '/' && !isSpace(text[j]) {
+                            j++
+                        }
+                        ctx.attr = strings.ToLower(string(text[i : j]))
+                        for j < len(text) && isSpace(text[j]) {
+                            j++
+                        }
+                        if j < len(text) && text[j] == '=' {
+                            j++
+                            for j < len(text) && isSpace(text[j]) {
+                                j++
+                            }
+                            ctx.state     = gESCAPE_STATE_ATTR
+                            ctx.attrStart = true
+                            ctx.quote     = 0
+                            if j < len(text) && (text[j] == '"' || text[j] == '\'') {
+                                ctx.quote = text[j]
+                                j++
+                            }
+                        }
+                        i = j - 1
+                }
+
+            case gESCAPE_STATE_ATTR:
+                if (ctx.quote != 0 && c == ctx.quote) || (ctx.quote == 0 && (isSpace(c) || c == '>')) {
+                    ctx.state     = gESCAPE_STATE_TAG
+                    ctx.attr      = ""
+                    ctx.quote     = 0
+                    ctx.attrStart = false
+                    if c == '>' {
+                        i--
+                    }
+                } else {
+                    ctx.attrStart = false
+                }
+
+            case gESCAPE_STATE_SCRIPT:
+                // 无论是否位于JS字符串或者注释中，HTML都在</script处结束script标签
+                if c == '<' && hasPrefixFold(text[i : ], "</script") {
+                    ctx = escapeContext{state : gESCAPE_STATE_TAG}
+                    i  += 7
+                    continue
+                }
+                i = ctx.feedJs(text, i)
+
+            case gESCAPE_STATE_STYLE:
+                if c == '<' && hasPrefixFold(text[i : ], "</style") {
+                    ctx = escapeContext{state : gESCAPE_STATE_TAG}
+                    i  += 6
+                }
+        }
+    }
+    return ctx
+}
+
+// 计算JS代码中第i个字符之后的上下文，返回最后处理的字符位置
+func (ctx *escapeContext) feedJs(text []byte, i int) int {
+    c    := text[i]
+    next := byte(0)
+    if i + 1 < len(text) {
+        next = text[i + 1]
+    }
+    switch {
+        // 单行注释
+        case ctx.jsComment == '/':
+            if c == '\n' || c == '\r' {
+                ctx.jsComment = 0
+            }
+
+        // 多行注释
+        case ctx.jsComment == '*':
+            if c == '*' && next == '/' {
+                ctx.jsComment = 0
+                i++
+            }
+
+        // 正则表达式
+        case ctx.quote == '/':
+            switch {
+                case c == '\\':
+                    i++
+                case c == '[':
+                    ctx.jsClass = true
+                case c == ']':
+                    ctx.jsClass = false
+                case c == '/' && !ctx.jsClass:
+                    ctx.quote    = 0
+                    ctx.jsRegexp = false
+            }
+
+        // 字符串
+        case ctx.quote != 0:
+            if c == '\\' {
+                i++
+            } else if c == ctx.quote {
+                ctx.quote    = 0
+                ctx.jsRegexp = false
+            } else if ctx.quote == '`' && c == '$' && next == '{' {
+                // 模板字符串中的插值为JS代码
+                ctx.quote     = 0
+                ctx.jsBraces += "$"
+                ctx.jsRegexp  = true
+                i++
+            }
+
+        case isSpace(c):
+
+        case c == '/' && (next == '/' || next == '*'):
+            ctx.jsComment = next
+            i++
+
+        case c == '/':
+            if ctx.jsRegexp {
+                ctx.quote   = '/'
+                ctx.jsClass = false
+            } else {
+                ctx.jsRegexp = true
+            }
+
+        case c == '"' || c == '\'' || c == '`':
+            ctx.quote = c
+
+        case c == '{' && ctx.jsBraces != "":
+            ctx.jsBraces += "{"
+            ctx.jsRegexp  = true
+
+        case c == '}' && ctx.jsBraces != "":
+            // 插值结束后回到模板字符串中
+            if ctx.jsBraces[len(ctx.jsBraces) - 1] == '$' {
+                ctx.quote = '`'
+            }
+            ctx.jsBraces = ctx.jsBraces[ : len(ctx.jsBraces) - 1]
+            ctx.jsRegexp = true
+
+        // 标识符、关键字及数字，关键字之后的'/'为正则表达式
+        case isJsIdentChar(c):
+            j := i
+            for j < len(text) && isJsIdentChar(text[j]) {
+                j++
+            }
+            ctx.jsRegexp = escapeJsRegexpKeywords[string(text[i : j])]
+            i = j - 1
+
+        // 数字中的小数点、后缀的++/--以及括号结束之后的'/'为除号
+        case c == ')' || c == ']':
+            ctx.jsRegexp = false
+        case c == '.':
+            ctx.jsRegexp = !(i > 0 && text[i - 1] >= '0' && text[i - 1] <= '9')
+        case c == '+' || c == '-':
+            ctx.jsRegexp = !(i > 0 && text[i - 1] == c)
+
+        default:
+            ctx.jsRegexp = true
+    }
+    return i
+}
+
+// 判断内容是否以指定的前缀开始(不区分大小写)
+func hasPrefixFold(text []byte, prefix string) bool {
+    return len(text) >= len(prefix) && strings.EqualFold(string(text[ : len(prefix)]), prefix)
+}
+
+// 判断是否为JS标识符(或者数字)的字符
+func isJsIdentChar(c byte) bool {
+    return isAsciiLetter(c) || (c >= '0' && c <= '9') || c == '_' || c == '$' || c >= utf8.RuneSelf
+}
+
+// 判断是否为ASCII字母
+func isAsciiLetter(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// 判断是否为空白字符
+func isSpace(c byte) bool {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// 获取需要转义的输出内容，HTML类型的内容不需要转义
+func escapeValue(args []interface{}) (value interface{}, raw bool) {
+    if len(args) == 0 {
+        return nil, false
+    }
+    value = args[len(args) - 1]
+    if h, ok := value.(HTML); ok {
+        return string(h), true
+    }
+    return value, false
+}
+
+// 转换为输出字符串，与模板默认输出方式保持一致
+func escapeString(value interface{}) string {
+    if value == nil {
+        return ""
+    }
+    return fmt.Sprint(value)
+}
+
+// 转义方法：HTML文本/属性
+func escapeHtml(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    return ghtml.SpecialChars(escapeString(value))
+}
+
+// 转义方法：JS值，字符串输出为带引号的JS字符串
+func escapeJs(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    b, err := json.Marshal(value)
+    if err != nil {
+        return "null"
+    }
+    return strings.NewReplacer("\u2028", `\u2028`, "\u2029", `\u2029`).Replace(string(b))
+}
+
+// 转义方法：JS字符串内容，'$'及花括号也进行转义，防止在模板字符串(`...`)中形成插值
+func escapeJsString(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    s      := escapeString(value)
+    buffer := bytes.NewBuffer(nil)
+    for _, r := range s {
+        switch r {
+            case '\\': buffer.WriteString(`\\`)
+            case '\n': buffer.WriteString(`\n`)
+            case '\r': buffer.WriteString(`\r`)
+            case '\t': buffer.WriteString(`\t`)
+            case '\'', '"', '`', '<', '>', '&', '=', '$', '{', '}', '\u2028', '\u2029':
+                buffer.WriteString(fmt.Sprintf(`\u%04x`, r))
+            default:
+                if r < ' ' || r == utf8.RuneError {
+                    buffer.WriteString(fmt.Sprintf(`\u%04x`, r))
+                } else {
+                    buffer.WriteRune(r)
+                }
+        }
+    }
+    return buffer.String()
+}
+
+// 转义方法：JS正则表达式内容，正则表达式的特殊字符使用'\\'转义，空值输出为(?:)，防止形成注释
+func escapeJsRegexp(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    s := escapeJsString(value)
+    if s == "" {
+        return "(?:)"
+    }
+    buffer := bytes.NewBuffer(nil)
+    for i := 0; i < len(s); i++ {
+        switch s[i] {
+            case '.', '+', '*', '?', '(', ')', '[', ']', '|', '^', '/', '-':
+                buffer.WriteByte('\\')
+        }
+        buffer.WriteByte(s[i])
+    }
+    return buffer.String()
+}
+
+// 转义方法：事件属性中的JS值
+func escapeJsAttr(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    return ghtml.SpecialChars(escapeJs(value))
+}
+
+// 转义方法：CSS值，包含可能改变CSS结构的字符(引号、括号、分号、'/'、'\\'等)或者expression等
+// 可执行脚本的内容时输出ZgotmplZ，例如: expression(...)、url(javascript:...)
+func escapeCss(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    s  := escapeString(value)
+    id := make([]byte, 0, len(s))
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        switch c {
+            case 0, '"', '\'', '(', ')', '/', ';', '@', '[', '\\', ']', '`', '{', '}', '<', '>':
+                return gESCAPE_FAILSAFE
+            case '-':
+                // 防止形成<!--或者-->
+                if i > 0 && s[i - 1] == '-' {
+                    return gESCAPE_FAILSAFE
+                }
+                id = append(id, c)
+            default:
+                if isAsciiLetter(c) || (c >= '0' && c <= '9') || c == '_' || c >= utf8.RuneSelf {
+                    id = append(id, c)
+                }
+        }
+    }
+    lower := strings.ToLower(string(id))
+    if strings.Contains(lower, "expression") || strings.Contains(lower, "mozbinding") {
+        return gESCAPE_FAILSAFE
+    }
+    return s
+}
+
+// 转义方法：style属性中的CSS值
+func escapeCssAttr(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    return ghtml.SpecialChars(escapeCss(value))
+}
+
+// 转义方法：JS注释中的输出，不输出任何内容
+func escapeComment(args...interface{}) string {
+    return ""
+}
+
+// 转义方法：URL属性开头，不安全的协议(例如javascript:)替换为#ZgotmplZ
+func escapeUrl(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    s := escapeString(value)
+    if i := strings.IndexAny(s, ":/?#"); i > 0 && s[i] == ':' {
+        if !escapeSafeSchemes[strings.ToLower(strings.TrimSpace(s[ : i]))] {
+            return "#" + gESCAPE_FAILSAFE
+        }
+    }
+    return ghtml.SpecialChars(s)
+}
+
+// 转义方法：URL属性的参数部分
+func escapeUrlPart(args...interface{}) string {
+    value, raw := escapeValue(args)
+    if raw {
+        return value.(string)
+    }
+    return ghtml.SpecialChars(url.QueryEscape(escapeString(value)))
+}
+
+// 不带引号的属性值转义时需要额外转义的字符，防止输出内容结束属性值或者增加新的属性
+var escapeUnquotedReplacer = strings.NewReplacer(
+    " ", "&#32;", "\t", "&#9;", "\n", "&#10;", "\r", "&#13;", "\f", "&#12;",
+    "=", "&#61;", "<", "&lt;", ">", "&gt;", "`", "&#96;", `"`, "&#34;", "'", "&#39;",
+)
+
+// 生成不带引号的属性值的转义方法，空值输出为ZgotmplZ，防止后续的内容成为属性值
+func escapeUnquoted(f func(args...interface{}) string) func(args...interface{}) string {
+    return func(args...interface{}) string {
+        value, raw := escapeValue(args)
+        if raw {
+            return value.(string)
+        }
+        s := f(args...)
+        if s == "" {
+            return gESCAPE_FAILSAFE
+        }
+        return escapeUnquotedReplacer.Replace(s)
+    }
+}
@@ -0,0 +1,283 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gview
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/util/gconv"
+    "strings"
+    "sync"
+)
+
+const (
+    // 模板变量中指定当前请求语言的键名，设置后模板中的T/Tn方法优先使用该语言
+    I18N_LANGUAGE_KEY      = "I18nLanguage"
+    // 默认的回退语言
+    gDEFAULT_I18N_LANGUAGE = "en"
+)
+
+// 视图国际化翻译数据，使用独立的互斥锁(模板执行过程中会调用翻译方法)
+type viewI18n struct {
+    mu       sync.RWMutex
+    language string                 // 视图当前语言
+    fallback string                 // 默认回退语言
+    data     map[string]*gjson.Json // 语言对应的翻译数据
+}
+
+var (
+    // 没有复数形式的语言
+    i18nNoPluralLanguages = map[string]bool {
+        "zh" : true, "ja" : true, "ko" : true, "vi" : true, "th" : true, "id" : true, "ms" : true,
+    }
+    // 0和1均使用单数形式的语言
+    i18nZeroOneLanguages  = map[string]bool {
+        "fr" : true, "pt" : true,
+    }
+)
+
+// 创建国际化翻译数据对象
+func newViewI18n() *viewI18n {
+    return &viewI18n {
+        fallback : gDEFAULT_I18N_LANGUAGE,
+        data     : make(map[string]*gjson.Json),
+    }
+}
+
+// 设置视图的当前语言，模板中的T/Tn方法默认使用该语言进行翻译，
+// 单次解析可通过模板变量I18N_LANGUAGE_KEY指定其他语言(例如根据请求的Accept-Language)。
+func (view *View) SetLanguage(language string) {
+    view.i18n.mu.Lock()
+    view.i18n.language = formatLanguage(language)
+    view.i18n.mu.Unlock()
+}
+
+// 获取视图的当前语言
+func (view *View) GetLanguage() string {
+    view.i18n.mu.RLock()
+    defer view.i18n.mu.RUnlock()
+    return view.i18n.language
+}
+
+// 设置默认的回退语言(默认为en)，当前语言不存在翻译内容时使用回退语言进行翻译
+func (view *View) SetFallbackLanguage(language string) {
+    view.i18n.mu.Lock()
+    view.i18n.fallback = formatLanguage(language)
+    view.i18n.mu.Unlock()
+}
+
+// 添加指定语言的翻译内容，键名支持层级访问(例如: user.name)，
+// 复数形式的翻译内容为包含zero/one/other键名的map，例如: {"apple" : {"one" : "%d apple", "other" : "%d apples"}}
+func (view *View) AddTranslations(language string, data map[string]interface{}) {
+    language = formatLanguage(language)
+    view.i18n.mu.Lock()
+    defer view.i18n.mu.Unlock()
+    j, ok := view.i18n.data[language]
+    if !ok {
+        j = gjson.New(nil)
+        view.i18n.data[language] = j
+    }
+    for k, v := range data {
+        j.Set(k, v)
+    }
+}
+
+// 从目录中加载翻译文件，目录中的文件名称(不包含扩展名)为语言名称(例如: zh-CN.toml)，
+// 或者子目录名称为语言名称，子目录下的所有文件合并为该语言的翻译内容(例如: zh-CN/user.json)。
+// 文件格式支持json/xml/yaml/toml/ini。
+func (view *View) LoadTranslations(path string) error {
+    realPath := gfile.RealPath(path)
+    if realPath == "" {
+        return errors.New(fmt.Sprintf(`path "%s" does not exist`, path))
+    }
+    names, err := gfile.DirNames(realPath)
+    if err != nil {
+        return err
+    }
+    for _, name := range names {
+        filePath := realPath + gfile.Separator + name
+        if gfile.IsDir(filePath) {
+            files, err := gfile.ScanDir(filePath, "*")
+            if err != nil {
+                return err
+            }
+            for _, file := range files {
+                if err := view.loadTranslationFile(name, file); err != nil {
+                    return err
+                }
+            }
+        } else {
+            if err := view.loadTranslationFile(strings.TrimSuffix(name, gfile.Ext(name)), filePath); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// 加载单个翻译文件
+func (view *View) loadTranslationFile(language string, path string) error {
+    if gfile.IsDir(path) {
+        return nil
+    }
+    j, err := gjson.Load(path)
+    if err != nil {
+        return errors.New(fmt.Sprintf(`load translation file "%s" failed: %s`, path, err.Error()))
+    }
+    view.AddTranslations(language, j.ToMap())
+    return nil
+}
+
+// 使用指定语言翻译key，language为空时使用视图当前语言，args不为空时使用fmt.Sprintf格式化翻译内容。
+// 指定语言不存在翻译内容时，依次使用其主语言(例如zh-CN的zh)、视图当前语言以及回退语言，均不存在时返回key。
+func (view *View) Translate(language string, key string, args...interface{}) string {
+    value := view.getTranslation(language, key)
+    if value == nil {
+        return key
+    }
+    // 复数形式的翻译内容默认使用other形式
+    if m, ok := value.(map[string]interface{}); ok {
+        value = m["other"]
+    }
+    return formatTranslation(gconv.String(value), args)
+}
+
+// 使用指定语言翻译key的复数形式，根据count及语言的复数规则选择zero/one/other形式的翻译内容，
+// args为空时使用count作为格式化参数。
+func (view *View) TranslatePlural(language string, key string, count interface{}, args...interface{}) string {
+    value := view.getTranslation(language, key)
+    if value == nil {
+        return key
+    }
+    if len(args) == 0 {
+        args = []interface{}{count}
+    }
+    if m, ok := value.(map[string]interface{}); ok {
+        n    := gconv.Int64(count)
+        form := pluralForm(view.resolveLanguage(language, key), n)
+        if v, ok := m["zero"]; ok && n == 0 {
+            value = v
+        } else if v, ok := m[form]; ok {
+            value = v
+        } else {
+            value = m["other"]
+        }
+    }
+    return formatTranslation(gconv.String(value), args)
+}
+
+// 获取key的翻译内容
+func (view *View) getTranslation(language string, key string) interface{} {
+    view.i18n.mu.RLock()
+    defer view.i18n.mu.RUnlock()
+    for _, lang := range view.languageChain(language) {
+        if j, ok := view.i18n.data[lang]; ok {
+            if v := j.Get(key); v != nil {
+                return v
+            }
+        }
+    }
+    return nil
+}
+
+// 获取实际存在key翻译内容的语言，用于选择复数规则
+func (view *View) resolveLanguage(language string, key string) string {
+    view.i18n.mu.RLock()
+    defer view.i18n.mu.RUnlock()
+    for _, lang := range view.languageChain(language) {
+        if j, ok := view.i18n.data[lang]; ok && j.Get(key) != nil {
+            return lang
+        }
+    }
+    return ""
+}
+
+// 翻译时依次查找的语言列表(需要在读锁中调用)
+func (view *View) languageChain(language string) []string {
+    chain := make([]string, 0, 5)
+    for _, lang := range []string{formatLanguage(language), view.i18n.language, view.i18n.fallback} {
+        if lang == "" {
+            continue
+        }
+        chain = append(chain, lang)
+        if i := strings.Index(lang, "-"); i > 0 {
+            chain = append(chain, lang[ : i])
+        }
+    }
+    return chain
+}
+
+// 生成指定语言的模板翻译方法，用于覆盖单次解析的T/Tn方法
+func (view *View) i18nFuncMap(params Params, funcmap...map[string]interface{}) []map[string]interface{} {
+    language := ""
+    if v, ok := params[I18N_LANGUAGE_KEY]; ok {
+        language = gconv.String(v)
+    }
+    if language == "" {
+        return funcmap
+    }
+    m := make(map[string]interface{})
+    if len(funcmap) > 0 {
+        for k, v := range funcmap[0] {
+            m[k] = v
+        }
+    }
+    m["T"] = func(key string, args...interface{}) string {
+        return view.Translate(language, key, args...)
+    }
+    m["Tn"] = func(key string, count interface{}, args...interface{}) string {
+        return view.TranslatePlural(language, key, count, args...)
+    }
+    // 被包含的模板使用相同的语言
+    m["include"] = func(file string, data...interface{}) HTML {
+        return view.include(language, file, data...)
+    }
+    return []map[string]interface{}{m}
+}
+
+// 模板内置方法：T
+func (view *View) funcT(key string, args...interface{}) string {
+    return view.Translate("", key, args...)
+}
+
+// 模板内置方法：Tn
+func (view *View) funcTn(key string, count interface{}, args...interface{}) string {
+    return view.TranslatePlural("", key, count, args...)
+}
+
+// 根据语言的复数规则获取复数形式名称：one/other
+func pluralForm(language string, n int64) string {
+    if i := strings.Index(language, "-"); i > 0 {
+        language = language[ : i]
+    }
+    switch {
+        case i18nNoPluralLanguages[language]:
+            return "other"
+        case i18nZeroOneLanguages[language]:
+            if n == 0 || n == 1 {
+                return "one"
+            }
+        case n == 1:
+            return "one"
+    }
+    return "other"
+}
+
+// 格式化翻译内容，翻译内容不包含格式化占位符时忽略参数
+func formatTranslation(text string, args []interface{}) string {
+    if len(args) == 0 || !strings.Contains(text, "%") {
+        return text
+    }
+    return fmt.Sprintf(text, args...)
+}
+
+// 格式化语言名称，例如: zh_CN转换为zh-cn
+func formatLanguage(language string) string {
+    return strings.ToLower(strings.Replace(strings.TrimSpace(language), "_", "-", -1))
+}
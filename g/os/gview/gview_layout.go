@@ -0,0 +1,90 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gview
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gview/internal/text/template"
+    "github.com/gogf/gf/g/text/gregex"
+    "regexp"
+)
+
+// 模板继承链中的模板项
+type layoutItem struct {
+    name    string // 模板名称(文件绝对路径)
+    content string // 模板内容(已去掉extends声明)
+}
+
+// 解析模板内容(包括其继承的布局模板)，返回可执行的模板对象，以及继承的布局模板文件列表。
+// 继承时从最顶层的布局模板开始依次解析，子模板中的define定义将覆盖布局模板中的同名block，
+// 最终执行的是最顶层的布局模板。
+func (view *View) parseTemplate(name string, content string, funcmap...map[string]interface{}) (*template.Template, []string, error) {
+    chain   := []layoutItem{}
+    visited := map[string]bool{name : true}
+    layouts := []string(nil)
+    for {
+        parent, rest := view.getExtends(content)
+        chain = append(chain, layoutItem{name, rest})
+        if parent == "" {
+            break
+        }
+        path, err := view.searchLayout(name, parent)
+        if err != nil {
+            return nil, nil, err
+        }
+        if visited[path] {
+            return nil, nil, errors.New(fmt.Sprintf(`tpl "%s" has circular extends of "%s"`, name, parent))
+        }
+        visited[path] = true
+        name          = path
+        content       = view.getContents(path)
+        layouts       = append(layouts, path)
+    }
+    root := chain[len(chain) - 1]
+    tpl  := template.New(root.name).Delims(view.delimiters[0], view.delimiters[1]).Funcs(escapeFuncs).Funcs(view.funcmap)
+    if len(funcmap) > 0 {
+        tpl = tpl.Funcs(funcmap[0])
+    }
+    if _, err := tpl.Parse(root.content); err != nil {
+        return nil, nil, err
+    }
+    for i := len(chain) - 2; i >= 0; i-- {
+        if _, err := tpl.New(chain[i].name).Parse(chain[i].content); err != nil {
+            return nil, nil, err
+        }
+    }
+    if err := view.escapeTemplate(tpl); err != nil {
+        return nil, nil, err
+    }
+    return tpl, layouts, nil
+}
+
+// 获取模板开头的extends声明，返回继承的布局模板名称及去掉声明后的模板内容
+func (view *View) getExtends(content string) (parent string, rest string) {
+    pattern := fmt.Sprintf(`^\s*%s-?\s*extends\s+["\x60]([^"\x60]+)["\x60]\s*-?%s`,
+        regexp.QuoteMeta(view.delimiters[0]), regexp.QuoteMeta(view.delimiters[1]))
+    match, _ := gregex.MatchString(pattern, content)
+    if len(match) < 2 {
+        return "", content
+    }
+    return match[1], content[len(match[0]) : ]
+}
+
+// 查找布局模板文件，优先查找当前模板文件所在目录，其次查找模板目录
+func (view *View) searchLayout(name string, parent string) (string, error) {
+    if gfile.Exists(name) {
+        if path := gfile.RealPath(gfile.Dir(name) + gfile.Separator + parent); path != "" && !gfile.IsDir(path) {
+            return path, nil
+        }
+    }
+    if path := searchResourceLayout(name, parent); path != "" {
+        return path, nil
+    }
+    return view.searchFile(parent)
+}
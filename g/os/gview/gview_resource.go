@@ -0,0 +1,67 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gview
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "path"
+    "strings"
+)
+
+const (
+    gRESOURCE_PATH_PREFIX = "resource://" // 打包资源(gres)中的模板文件路径前缀
+    gDEFAULT_RESOURCE_DIR = "template"    // 打包资源中默认的模板目录
+)
+
+// 模板文件在磁盘中不存在时从打包资源中查找，依次查找模板目录同名的资源目录、默认的template资源目录以及资源根目录
+func (view *View) searchResource(file string) string {
+    if gres.IsEmpty() {
+        return ""
+    }
+    for _, dir := range view.resourceDirs() {
+        if f := gres.Get(dir + "/" + file); f != nil && !f.IsDir() {
+            return gRESOURCE_PATH_PREFIX + f.Path()
+        }
+    }
+    return ""
+}
+
+// 模板文件对应的打包资源目录列表(已去重)
+func (view *View) resourceDirs() []string {
+    dirs := make([]string, 0)
+    seen := make(map[string]bool)
+    for _, dir := range append(view.paths.Slice(), gDEFAULT_RESOURCE_DIR, "") {
+        if dir != "" {
+            dir = gfile.Basename(dir)
+        }
+        if !seen[dir] {
+            seen[dir] = true
+            dirs      = append(dirs, dir)
+        }
+    }
+    return dirs
+}
+
+// 获取模板文件内容，支持打包资源中的模板文件
+func (view *View) getContents(file string) string {
+    if strings.HasPrefix(file, gRESOURCE_PATH_PREFIX) {
+        return string(gres.GetContent(file[len(gRESOURCE_PATH_PREFIX) : ]))
+    }
+    return gfile.GetContents(file)
+}
+
+// 在打包资源中查找与模板文件同目录的布局模板
+func searchResourceLayout(name string, parent string) string {
+    if !strings.HasPrefix(name, gRESOURCE_PATH_PREFIX) {
+        return ""
+    }
+    if f := gres.Get(path.Dir(name[len(gRESOURCE_PATH_PREFIX) : ]) + "/" + parent); f != nil && !f.IsDir() {
+        return gRESOURCE_PATH_PREFIX + f.Path()
+    }
+    return ""
+}
@@ -0,0 +1,280 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 单元测试
+
+package gview_test
+
+import (
+    "fmt"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/os/gview"
+    "github.com/gogf/gf/g/test/gtest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// 创建测试模板目录，files为文件名称到内容的映射
+func testView(files map[string]string) (*gview.View, string) {
+    dir := fmt.Sprintf("%s/gview_test_%d", gfile.TempDir(), gtime.Nanosecond())
+    for name, content := range files {
+        gfile.PutContents(dir + "/" + name, content)
+    }
+    return gview.New(dir), dir
+}
+
+func TestView_Extends(t *testing.T) {
+    gtest.Case(t, func() {
+        view, dir := testView(map[string]string {
+            "base.html"           : `<title>{{block "title" .}}default{{end}}</title>{{include "header.html" "name" .name}}|{{block "content" .}}{{end}}|{{block "footer" .}}footer{{end}}`,
+            "header.html"         : `<h1>{{.name}}</h1>`,
+            "layout/page.html"    : `{{extends "base.html"}}{{define "content"}}page:{{block "body" .}}{{end}}{{end}}{{define "footer"}}page footer{{end}}`,
+            "layout/index.html"   : "{{extends \"page.html\"}}\n{{define \"title\"}}Index{{end}}{{define \"body\"}}{{.name}}{{end}}",
+            "circle1.html"        : `{{extends "circle2.html"}}`,
+            "circle2.html"        : `{{extends "circle1.html"}}`,
+        })
+        defer gfile.Remove(dir)
+        content, err := view.Parse("layout/index.html", gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<title>Index</title><h1>john</h1>|page:john|page footer`)
+
+        content, err = view.Parse("base.html", gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<title>default</title><h1>john</h1>||footer`)
+
+        content, err = view.ParseContent(`{{extends "base.html"}}{{define "title"}}Content{{end}}`, gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<title>Content</title><h1>john</h1>||footer`)
+
+        _, err = view.Parse("circle1.html", nil)
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func TestView_Include(t *testing.T) {
+    gtest.Case(t, func() {
+        view, dir := testView(map[string]string {
+            "item.html" : `{{.name}}:{{.age}}`,
+        })
+        defer gfile.Remove(dir)
+        content, err := view.ParseContent(`{{include "item.html" .}},{{include "item.html" "name" "smith" "age" 20}}`, gview.Params {
+            "name" : "john",
+            "age"  : 18,
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `john:18,smith:20`)
+    })
+}
+
+func TestView_I18n(t *testing.T) {
+    gtest.Case(t, func() {
+        view, dir := testView(map[string]string {
+            "i18n/en.json"         : `{"hello" : "Hello %s", "apple" : {"zero" : "no apples", "one" : "%d apple", "other" : "%d apples"}}`,
+            "i18n/zh-CN/user.toml" : "hello = \"你好 %s\"\n[apple]\nother = \"%d个苹果\"\n",
+            "i18n/fr.yaml"         : "apple:\n  one: \"%d pomme\"\n  other: \"%d pommes\"\n",
+            "apple.html"           : `{{Tn "apple" .count}}`,
+        })
+        defer gfile.Remove(dir)
+        gtest.Assert(view.LoadTranslations(dir + "/i18n"), nil)
+        view.AddTranslations("en", map[string]interface{}{"user.name" : "Name"})
+        tpl := `{{T "hello" "john"}}|{{Tn "apple" 0}}|{{Tn "apple" 1}}|{{Tn "apple" 2}}|{{T "user.name"}}|{{T "missing"}}`
+
+        content, err := view.ParseContent(tpl, nil)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `Hello john|no apples|1 apple|2 apples|Name|missing`)
+
+        view.SetLanguage("zh_CN")
+        gtest.Assert(view.GetLanguage(), "zh-cn")
+        content, err = view.ParseContent(tpl, nil)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `你好 john|0个苹果|1个苹果|2个苹果|Name|missing`)
+
+        // 单次解析指定语言，不存在的翻译内容依次回退到视图当前语言及默认语言
+        content, err = view.ParseContent(tpl, gview.Params{gview.I18N_LANGUAGE_KEY : "fr-FR"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `你好 john|0 pomme|1 pomme|2 pommes|Name|missing`)
+
+        // 被包含的模板使用相同的语言
+        content, err = view.ParseContent(`{{include "apple.html" "count" 2}}|{{include "apple.html" .}}`, gview.Params {
+            gview.I18N_LANGUAGE_KEY : "fr",
+            "count"                 : 1,
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `2 pommes|1 pomme`)
+
+        gtest.Assert(view.Translate("en", "hello", "smith"), "Hello smith")
+        gtest.Assert(view.TranslatePlural("en", "apple", 3), "3 apples")
+    })
+}
+
+func TestView_Cache(t *testing.T) {
+    gtest.Case(t, func() {
+        view, dir := testView(map[string]string {
+            "base.html"  : `base:{{block "content" .}}{{end}}`,
+            "index.html" : `{{extends "base.html"}}{{define "content"}}{{.name}}{{end}}`,
+        })
+        defer gfile.Remove(dir)
+        gtest.Assert(view.IsCache(), true)
+        gtest.Assert(view.Precompile(), nil)
+        content, err := view.Parse("index.html", gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "base:john")
+
+        // 布局模板变化后自动清除缓存
+        gfile.PutContents(dir + "/base.html", `layout:{{block "content" .}}{{end}}`)
+        time.Sleep(200*time.Millisecond)
+        content, err = view.Parse("index.html", gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "layout:john")
+
+        // 单次解析绑定的函数
+        gfile.PutContents(dir + "/func.html", `{{hello .name}}`)
+        time.Sleep(200*time.Millisecond)
+        gtest.AssertNE(view.Precompile(), nil)
+        gtest.Assert(view.Precompile(gview.FuncMap{"hello" : func(s string) string { return "" }}), nil)
+        content, err = view.Parse("func.html", gview.Params{"name" : "john"}, gview.FuncMap {
+            "hello" : func(s string) string { return "hello " + s },
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "hello john")
+
+        // 关闭缓存
+        view.SetCache(false)
+        gfile.PutContents(dir + "/error.html", `{{if}}`)
+        err = view.Precompile(gview.FuncMap{"hello" : strings.ToUpper})
+        gtest.AssertNE(err, nil)
+        gtest.Assert(strings.Contains(err.Error(), "error.html"), true)
+        gtest.Assert(strings.Contains(err.Error(), "func.html"), false)
+    })
+}
+
+func TestView_Escape(t *testing.T) {
+    gtest.Case(t, func() {
+        view   := gview.New()
+        params := gview.Params {
+            "text" : `<b>"a" & 'b'</b>`,
+            "url"  : `javascript:alert(1)`,
+            "link" : `/user?id=1`,
+            "q"    : `a b&c`,
+        }
+        content, err := view.ParseContent(`<p title="{{.text}}">{{.text}}</p>{{raw .text}}`, params)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<p title="&lt;b&gt;&#34;a&#34; &amp; &#39;b&#39;&lt;/b&gt;">&lt;b&gt;&#34;a&#34; &amp; &#39;b&#39;&lt;/b&gt;</p><b>"a" & 'b'</b>`)
+
+        content, err = view.ParseContent(`<a href="{{.url}}">x</a><a href='{{.link}}&q={{.q}}'>y</a>`, params)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<a href="#ZgotmplZ">x</a><a href='/user?id=1&q=a+b%26c'>y</a>`)
+
+        content, err = view.ParseContent(`<script>var a = {{.text}}, b = "{{.text}}";</script><button onclick="f({{.q}})">{{.q}}</button>`, params)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<script>var a = "\u003cb\u003e\"a\" \u0026 'b'\u003c/b\u003e", b = "\u003cb\u003e\u0022a\u0022 \u0026 \u0027b\u0027\u003c/b\u003e";</script><button onclick="f(&#34;a b\u0026c&#34;)">a b&amp;c</button>`)
+
+        // 不带引号的属性值
+        content, err = view.ParseContent(`<p title={{.q}} class={{.empty}}>x</p><a href={{.url}} onclick={{.q}}>y</a><a href=/a?q={{.q}}>z</a>`, gview.Params {
+            "q"     : "a onmouseover=alert(1)",
+            "url"   : "/a b",
+            "empty" : "",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<p title=a&#32;onmouseover&#61;alert(1) class=ZgotmplZ>x</p><a href=/a&#32;b onclick=&#34;a&#32;onmouseover&#61;alert(1)&#34;>y</a><a href=/a?q=a+onmouseover%3Dalert%281%29>z</a>`)
+
+        // JS模板字符串
+        content, err = view.ParseContent("<script>var a = `{{.v}}`, b = `x${ {{.v}} }y{{.v}}`, c = {a : 1, b : {{.v}}};</script>", gview.Params {
+            "v" : "${alert(1)}",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "<script>var a = `\\u0024\\u007balert(1)\\u007d`, b = `x${ \"${alert(1)}\" }y\\u0024\\u007balert(1)\\u007d`, c = {a : 1, b : \"${alert(1)}\"};</script>")
+
+        // 分支结束位置的上下文不一致
+        _, err = view.ParseContent(`{{if .q}}<a href="{{else}}<b>{{end}}{{.q}}`, params)
+        gtest.AssertNE(err, nil)
+        _, err = view.ParseContent(`{{range .list}}<p title="{{.}}{{end}}`, gview.Params{"list" : []int{1, 2}})
+        gtest.AssertNE(err, nil)
+        content, err = view.ParseContent(`<a href="{{if .q}}/a?q={{end}}{{.url}}">x</a>{{range .list}}<i>{{.}}</i>{{end}}`, gview.Params {
+            "url"  : "javascript:alert(1)",
+            "list" : []string{"<1>"},
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<a href="javascript%3Aalert%281%29">x</a><i>&lt;1&gt;</i>`)
+
+        // JS注释及正则表达式
+        content, err = view.ParseContent("<script>// don't\nvar x = {{.v}}; /* it's {{.v}} */ var r = /['\"]/g, s = '{{.v}}', d = 4 / 2 / {{.v}}, re = /a{{.v}}/;</script>", gview.Params {
+            "v" : "1;alert(1)//",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "<script>// don't\nvar x = \"1;alert(1)//\"; /* it's  */ var r = /['\"]/g, s = '1;alert(1)//', d = 4 / 2 / \"1;alert(1)//\", re = /a1;alert\\(1\\)\\/\\//;</script>")
+
+        // 在不同的上下文中调用的模板
+        content, err = view.ParseContent(`{{define "inc"}}{{.v}}{{end}}<script>var x = {{template "inc" .}};</script><a title={{template "inc" .}}>{{template "inc" .}}</a>`, gview.Params {
+            "v" : "1;alert(1)//",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<script>var x = "1;alert(1)//";</script><a title=1;alert(1)//>1;alert(1)//</a>`)
+        _, err = view.ParseContent(`{{define "loop"}}{{if .}}<a href="{{template "loop" .}}{{end}}{{end}}{{template "loop" .}}`, params)
+        gtest.AssertNE(err, nil)
+
+        // CSS
+        content, err = view.ParseContent(`<style>p { color: {{.color}}; width: {{.expr}} }</style><p style="color: {{.color}}; background: {{.url}}">x</p><p style={{.color}}>y</p>`, gview.Params {
+            "color" : "red",
+            "expr"  : "expression(alert(1))",
+            "url"   : "url(javascript:alert(1))",
+        })
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<style>p { color: red; width: ZgotmplZ }</style><p style="color: red; background: ZgotmplZ">x</p><p style=red>y</p>`)
+        content, err = view.ParseContent(`<style>{{.v}}</style><p style="{{.v}}">`, gview.Params{"v" : "x</style><script>"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<style>ZgotmplZ</style><p style="ZgotmplZ">`)
+
+        view.SetEscape(gview.ESCAPE_HTML)
+        gtest.Assert(view.GetEscape(), gview.ESCAPE_HTML)
+        content, err = view.ParseContent(`<script>var a = "{{.q}}";</script>{{nl2br "a<\nb"}}`, params)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), "<script>var a = \"a b&amp;c\";</script>a&lt;<br>b")
+
+        view.SetEscape(gview.ESCAPE_NONE)
+        content, err = view.ParseContent(`{{.text}}`, params)
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), params["text"])
+    })
+}
+
+func TestView_Delimiters(t *testing.T) {
+    gtest.Case(t, func() {
+        view, dir := testView(map[string]string {
+            "base.html"  : `<div>${block "content" .}${end}</div>`,
+            "index.html" : `${extends "base.html"}${define "content"}{{ vue }} ${.name}${end}`,
+        })
+        defer gfile.Remove(dir)
+        view.SetDelimiters("${", "}")
+        content, err := view.Parse("index.html", gview.Params{"name" : "<john>"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<div>{{ vue }} &lt;john&gt;</div>`)
+    })
+}
+
+func TestView_Resource(t *testing.T) {
+    gtest.Case(t, func() {
+        _, src := testView(map[string]string {
+            "template/gres_layout.html"     : `<b>{{block "body" .}}{{end}}</b>`,
+            "template/gres/index.html"      : `{{extends "../gres_layout.html"}}{{define "body"}}{{.name}}{{end}}`,
+        })
+        defer gfile.Remove(src)
+        data, err := gres.Pack(src + "/template")
+        gtest.Assert(err, nil)
+        gtest.Assert(gres.Add(string(data)), nil)
+
+        // 模板目录中不存在时从打包资源中查找
+        view, dir := testView(map[string]string{"local.html" : "local"})
+        defer gfile.Remove(dir)
+        content, err := view.Parse("gres/index.html", gview.Params{"name" : "john"})
+        gtest.Assert(err, nil)
+        gtest.Assert(string(content), `<b>john</b>`)
+        gtest.Assert(view.Precompile(), nil)
+    })
+}
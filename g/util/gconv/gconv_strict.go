@@ -0,0 +1,380 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gconv
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "reflect"
+    "strconv"
+    "strings"
+)
+
+// 带错误返回的类型转换(以E结尾的方法)。
+// 与默认的转换方法不同，当变量无法转换为目标类型(例如: 非数字字符串转换为整数、数值超出目标类型范围、
+// 小数转换为整数)时返回错误，而不是返回零值，以便区分"0"与"非数字"。
+
+// 构造转换失败错误
+func convertError(i interface{}, t string, reason string) error {
+    return errors.New(fmt.Sprintf(`cannot convert "%s" to %s: %s`, String(i), t, reason))
+}
+
+// 将变量i转换为字符串指定的类型t，转换失败时返回错误；不支持的类型t原样返回变量i
+func ConvertE(i interface{}, t string, extraParams...interface{}) (interface{}, error) {
+    switch t {
+        case "int":             return IntE(i)
+        case "int8":            return Int8E(i)
+        case "int16":           return Int16E(i)
+        case "int32":           return Int32E(i)
+        case "int64":           return Int64E(i)
+        case "uint":            return UintE(i)
+        case "uint8":           return Uint8E(i)
+        case "uint16":          return Uint16E(i)
+        case "uint32":          return Uint32E(i)
+        case "uint64":          return Uint64E(i)
+        case "float32":         return Float32E(i)
+        case "float64":         return Float64E(i)
+        case "bool":            return BoolE(i)
+        case "string":          return String(i), nil
+        case "[]byte":          return Bytes(i), nil
+        case "[]int":           return IntsE(i)
+        case "[]string":        return Strings(i), nil
+        case "[]float64":       return FloatsE(i)
+        case "time.Time":
+            if len(extraParams) > 0 {
+                return TimeE(i, String(extraParams[0]))
+            }
+            return TimeE(i)
+
+        case "time.Duration":   return DurationE(i)
+        default:
+            return i, nil
+    }
+}
+
+func IntE(i interface{}) (int, error) {
+    if v, ok := i.(int); ok {
+        return v, nil
+    }
+    v, err := intE(i, "int", strconv.IntSize)
+    return int(v), err
+}
+
+func Int8E(i interface{}) (int8, error) {
+    v, err := intE(i, "int8", 8)
+    return int8(v), err
+}
+
+func Int16E(i interface{}) (int16, error) {
+    v, err := intE(i, "int16", 16)
+    return int16(v), err
+}
+
+func Int32E(i interface{}) (int32, error) {
+    v, err := intE(i, "int32", 32)
+    return int32(v), err
+}
+
+func Int64E(i interface{}) (int64, error) {
+    return intE(i, "int64", 64)
+}
+
+func UintE(i interface{}) (uint, error) {
+    if v, ok := i.(uint); ok {
+        return v, nil
+    }
+    v, err := uintE(i, "uint", strconv.IntSize)
+    return uint(v), err
+}
+
+func Uint8E(i interface{}) (uint8, error) {
+    v, err := uintE(i, "uint8", 8)
+    return uint8(v), err
+}
+
+func Uint16E(i interface{}) (uint16, error) {
+    v, err := uintE(i, "uint16", 16)
+    return uint16(v), err
+}
+
+func Uint32E(i interface{}) (uint32, error) {
+    v, err := uintE(i, "uint32", 32)
+    return uint32(v), err
+}
+
+func Uint64E(i interface{}) (uint64, error) {
+    return uintE(i, "uint64", 64)
+}
+
+func Float32E(i interface{}) (float32, error) {
+    if v, ok := i.(float32); ok {
+        return v, nil
+    }
+    v, err := Float64E(i)
+    if err != nil {
+        return 0, convertError(i, "float32", "invalid syntax")
+    }
+    if !math.IsInf(v, 0) && math.Abs(v) > math.MaxFloat32 {
+        return 0, convertError(i, "float32", "value out of range")
+    }
+    return float32(v), nil
+}
+
+func Float64E(i interface{}) (float64, error) {
+    switch value := i.(type) {
+        case nil:
+            return 0, convertError(i, "float64", "nil value")
+        case float64: return value, nil
+        case float32: return float64(value), nil
+        case int:     return float64(value), nil
+        case int8:    return float64(value), nil
+        case int16:   return float64(value), nil
+        case int32:   return float64(value), nil
+        case int64:   return float64(value), nil
+        case uint:    return float64(value), nil
+        case uint8:   return float64(value), nil
+        case uint16:  return float64(value), nil
+        case uint32:  return float64(value), nil
+        case uint64:  return float64(value), nil
+        case bool:
+            if value {
+                return 1, nil
+            }
+            return 0, nil
+    }
+    s := strings.TrimSpace(String(i))
+    if s == "" {
+        return 0, convertError(i, "float64", "empty value")
+    }
+    v, err := strconv.ParseFloat(s, 64)
+    if err != nil {
+        if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+            return 0, convertError(i, "float64", "value out of range")
+        }
+        return 0, convertError(i, "float64", "invalid syntax")
+    }
+    return v, nil
+}
+
+// true: 1, t, true, y, yes, on；false: 0, f, false, n, no, off(不区分大小写)；数值类型非0为true
+func BoolE(i interface{}) (bool, error) {
+    if i == nil {
+        return false, convertError(i, "bool", "nil value")
+    }
+    if v, ok := i.(bool); ok {
+        return v, nil
+    }
+    switch strings.ToLower(strings.TrimSpace(String(i))) {
+        case "1", "t", "true", "y", "yes", "on":
+            return true, nil
+        case "0", "f", "false", "n", "no", "off":
+            return false, nil
+    }
+    if v, err := Float64E(i); err == nil {
+        return v != 0, nil
+    }
+    return false, convertError(i, "bool", "invalid syntax")
+}
+
+// 任意类型转换为[]int类型，任意元素转换失败时返回错误
+func IntsE(i interface{}) ([]int, error) {
+    if i == nil {
+        return nil, nil
+    }
+    if r, ok := i.([]int); ok {
+        return r, nil
+    }
+    array := make([]int, 0)
+    err   := eachElement(i, func(v interface{}) error {
+        n, err := IntE(v)
+        if err == nil {
+            array = append(array, n)
+        }
+        return err
+    })
+    return array, err
+}
+
+// 任意类型转换为[]float64类型，任意元素转换失败时返回错误
+func FloatsE(i interface{}) ([]float64, error) {
+    if i == nil {
+        return nil, nil
+    }
+    if r, ok := i.([]float64); ok {
+        return r, nil
+    }
+    array := make([]float64, 0)
+    err   := eachElement(i, func(v interface{}) error {
+        n, err := Float64E(v)
+        if err == nil {
+            array = append(array, n)
+        }
+        return err
+    })
+    return array, err
+}
+
+// 任意类型转换为map[string]interface{}类型，变量不是map或者struct时返回错误
+func MapE(value interface{}, noTagCheck...bool) (map[string]interface{}, error) {
+    if value == nil {
+        return nil, nil
+    }
+    if m := Map(value, noTagCheck...); m != nil {
+        return m, nil
+    }
+    return nil, errors.New(fmt.Sprintf(`cannot convert type "%s" to map`, reflect.TypeOf(value).String()))
+}
+
+// 同Struct，但是属性值无法转换为属性类型时(例如: 非数字字符串赋值给int属性)返回错误
+func StructE(params interface{}, objPointer interface{}, attrMapping...map[string]string) error {
+    return doStruct(params, objPointer, true, attrMapping...)
+}
+
+// 遍历slice/array的每一个元素，非slice/array类型时作为单个元素处理([]byte作为单个元素)
+func eachElement(i interface{}, f func(v interface{}) error) error {
+    if _, ok := i.([]byte); !ok {
+        if rv := reflect.ValueOf(i); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+            for n := 0; n < rv.Len(); n++ {
+                if err := f(rv.Index(n).Interface()); err != nil {
+                    return err
+                }
+            }
+            return nil
+        }
+    }
+    return f(i)
+}
+
+// 带错误检测的有符号整数转换，bitSize为目标类型的位数
+func intE(i interface{}, t string, bitSize int) (int64, error) {
+    v := int64(0)
+    switch value := i.(type) {
+        case nil:
+            return 0, convertError(i, t, "nil value")
+        case int:    v = int64(value)
+        case int8:   v = int64(value)
+        case int16:  v = int64(value)
+        case int32:  v = int64(value)
+        case int64:  v = value
+        case uint, uint8, uint16, uint32, uint64:
+            u := Uint64(value)
+            if u > math.MaxInt64 {
+                return 0, convertError(i, t, "value out of range")
+            }
+            v = int64(u)
+        case bool:
+            if value {
+                v = 1
+            }
+        default:
+            n, err := parseIntE(i, t)
+            if err != nil {
+                return 0, err
+            }
+            v = n
+    }
+    if bitSize < 64 && (v < -1 << uint(bitSize - 1) || v > 1 << uint(bitSize - 1) - 1) {
+        return 0, convertError(i, t, "value out of range")
+    }
+    return v, nil
+}
+
+// 带错误检测的无符号整数转换，bitSize为目标类型的位数
+func uintE(i interface{}, t string, bitSize int) (uint64, error) {
+    v := uint64(0)
+    switch value := i.(type) {
+        case nil:
+            return 0, convertError(i, t, "nil value")
+        case uint:   v = uint64(value)
+        case uint8:  v = uint64(value)
+        case uint16: v = uint64(value)
+        case uint32: v = uint64(value)
+        case uint64: v = value
+        case bool:
+            if value {
+                v = 1
+            }
+        default:
+            n := int64(0)
+            // 超出int64范围的十进制无符号整数
+            if s := strings.TrimSpace(String(i)); s != "" && s[0] != '-' {
+                if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+                    v = u
+                    break
+                }
+            }
+            switch value := i.(type) {
+                case int:   n = int64(value)
+                case int8:  n = int64(value)
+                case int16: n = int64(value)
+                case int32: n = int64(value)
+                case int64: n = value
+                default:
+                    parsed, err := parseIntE(i, t)
+                    if err != nil {
+                        return 0, err
+                    }
+                    n = parsed
+            }
+            if n < 0 {
+                return 0, convertError(i, t, "negative value")
+            }
+            v = uint64(n)
+    }
+    if bitSize < 64 && v > 1 << uint(bitSize) - 1 {
+        return 0, convertError(i, t, "value out of range")
+    }
+    return v, nil
+}
+
+// 将浮点数或者字符串解析为整数，解析规则同Int64(支持十六进制、八进制及整数值的浮点数)
+func parseIntE(i interface{}, t string) (int64, error) {
+    f := float64(0)
+    switch value := i.(type) {
+        case float32: f = float64(value)
+        case float64: f = value
+        default:
+            s := strings.TrimSpace(String(i))
+            if s == "" {
+                return 0, convertError(i, t, "empty value")
+            }
+            // 按照十六进制解析
+            if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+                v, err := strconv.ParseInt(s[2 : ], 16, 64)
+                if err != nil {
+                    return 0, convertError(i, t, "invalid syntax")
+                }
+                return v, nil
+            }
+            // 按照八进制解析
+            if len(s) > 1 && s[0] == '0' {
+                if v, err := strconv.ParseInt(s[1 : ], 8, 64); err == nil {
+                    return v, nil
+                }
+            }
+            // 按照十进制解析
+            v, err := strconv.ParseInt(s, 10, 64)
+            if err == nil {
+                return v, nil
+            }
+            if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+                return 0, convertError(i, t, "value out of range")
+            }
+            // 按照浮点数解析
+            if f, err = strconv.ParseFloat(s, 64); err != nil {
+                return 0, convertError(i, t, "invalid syntax")
+            }
+    }
+    if f != math.Trunc(f) {
+        return 0, convertError(i, t, "not an integer")
+    }
+    if f < math.MinInt64 || f >= math.MaxInt64 {
+        return 0, convertError(i, t, "value out of range")
+    }
+    return int64(f), nil
+}
@@ -10,17 +10,29 @@ import (
     "errors"
     "fmt"
     "github.com/gogf/gf/g/text/gstr"
-    "github.com/gogf/gf/third/github.com/fatih/structs"
     "reflect"
     "strings"
 )
 
+// 结构体属性信息(匿名结构体属性展开后)
+type structAttr struct {
+    name  string // 属性名称
+    index []int  // 属性在结构体中的索引路径
+}
+
 // 将params键值对参数映射到对应的struct对象属性上，第三个参数mapping为非必需，表示自定义名称与属性名称的映射关系。
 // 需要注意：
 // 1、第二个参数应当为struct对象指针；
 // 2、struct对象的**公开属性(首字母大写)**才能被映射赋值；
 // 3、map中的键名可以为小写，映射转换时会自动将键名首字母转为大写做匹配映射，如果无法匹配则忽略；
+// 4、属性为struct/struct指针/struct数组/struct的map时会递归转换，每一层均支持gconv/json标签；
+// 5、匿名结构体(包括匿名结构体指针)的属性会被展开，可直接使用其属性名称进行映射；
 func Struct(params interface{}, objPointer interface{}, attrMapping...map[string]string) error {
+    return doStruct(params, objPointer, false, attrMapping...)
+}
+
+// 将params键值对参数映射到对应的struct对象属性上，strict为true时属性值转换失败将会返回错误
+func doStruct(params interface{}, objPointer interface{}, strict bool, attrMapping...map[string]string) error {
     if params == nil {
         return nil
     }
@@ -46,15 +58,35 @@ func Struct(params interface{}, objPointer interface{}, attrMapping...map[string
     if v, ok := objPointer.(reflect.Value); ok {
         elem = v
     } else {
-        elem = reflect.ValueOf(objPointer).Elem()
+        v := reflect.ValueOf(objPointer)
+        if v.Kind() != reflect.Ptr || v.IsNil() {
+            return errors.New(fmt.Sprintf(`object pointer should be type of *struct, but got "%s"`, v.Kind()))
+        }
+        elem = v.Elem()
+    }
+    // 指向结构体指针的指针，属性为nil时自动创建对象
+    for elem.Kind() == reflect.Ptr {
+        if elem.IsNil() {
+            elem.Set(reflect.New(elem.Type().Elem()))
+        }
+        elem = elem.Elem()
+    }
+    if elem.Kind() != reflect.Struct {
+        return errors.New(fmt.Sprintf(`object pointer should be type of *struct, but got "%s"`, elem.Type().String()))
     }
     // 如果给定的参数不是map类型，那么直接将参数值映射到第一个属性上
     if !isParamMap {
-        if err := bindVarToStructByIndex(elem, 0, params); err != nil {
+        if err := bindVarToStructByIndex(elem, 0, params, strict); err != nil {
             return err
         }
         return nil
     }
+    // 结构体属性(包括匿名结构体展开的属性)
+    attrs   := getStructAttrs(elem.Type())
+    attrMap := make(map[string]*structAttr, len(attrs))
+    for _, attr := range attrs {
+        attrMap[attr.name] = attr
+    }
     // 已执行过转换的属性，只执行一次转换
     dmap := make(map[string]bool)
     // 首先按照传递的映射关系进行匹配
@@ -62,7 +94,7 @@ func Struct(params interface{}, objPointer interface{}, attrMapping...map[string
         for mappingk, mappingv := range attrMapping[0] {
             if v, ok := paramsMap[mappingk]; ok {
                 dmap[mappingv] = true
-                if err := bindVarToStructAttr(elem, mappingv, v); err != nil {
+                if err := bindVarToStructAttr(elem, attrMap[mappingv], v, strict); err != nil {
                     return err
                 }
             }
@@ -70,24 +102,19 @@ func Struct(params interface{}, objPointer interface{}, attrMapping...map[string
     }
     // 其次匹配对象定义时绑定的属性名称
     // 标签映射关系map，如果有的话
-    tagmap := getTagMapOfStruct(objPointer)
+    tagmap := getTagMapOfStruct(elem.Type())
     for tagk, tagv := range tagmap {
         if _, ok := dmap[tagv]; ok {
             continue
         }
         if v, ok := paramsMap[tagk]; ok {
             dmap[tagv] = true
-            if err := bindVarToStructAttr(elem, tagv, v); err != nil {
+            if err := bindVarToStructAttr(elem, attrMap[tagv], v, strict); err != nil {
                 return err
             }
         }
     }
     // 最后按照默认规则进行匹配
-    attrMap  := make(map[string]struct{})
-    elemType := elem.Type()
-    for i := 0; i < elem.NumField(); i++ {
-        attrMap[elemType.Field(i).Name] = struct{}{}
-    }
     for mapk, mapv := range paramsMap {
         name := ""
         for _, checkName := range []string {
@@ -104,13 +131,13 @@ func Struct(params interface{}, objPointer interface{}, attrMapping...map[string
                 continue
             }
             // 循环查找属性名称进行匹配
-            for value, _ := range attrMap {
-                if strings.EqualFold(checkName, value) {
-                    name = value
+            for _, attr := range attrs {
+                if strings.EqualFold(checkName, attr.name) {
+                    name = attr.name
                     break
                 }
-                if strings.EqualFold(checkName, gstr.Replace(value, "_", "")) {
-                    name = value
+                if strings.EqualFold(checkName, gstr.Replace(attr.name, "_", "")) {
+                    name = attr.name
                     break
                 }
             }
@@ -118,138 +145,271 @@ func Struct(params interface{}, objPointer interface{}, attrMapping...map[string
                 break
             }
         }
-        // 如果没有匹配到属性名称，放弃
-        if name == "" {
+        // 如果没有匹配到属性名称，或者该属性已经转换过，放弃
+        if name == "" || dmap[name] {
             continue
         }
-        if err := bindVarToStructAttr(elem, name, mapv); err != nil {
+        dmap[name] = true
+        if err := bindVarToStructAttr(elem, attrMap[name], mapv, strict); err != nil {
             return err
         }
     }
     return nil
 }
 
-// 解析指针对象的tag
-func getTagMapOfStruct(objPointer interface{}) map[string]string {
-    tagmap := make(map[string]string)
-    // 反射类型判断
-    fields := ([]*structs.Field)(nil)
-    if v, ok := objPointer.(reflect.Value); ok {
-        fields = structs.Fields(v.Interface())
-    } else {
-        fields = structs.Fields(objPointer)
+// 获取结构体类型的所有属性，匿名结构体(及匿名结构体指针)的属性会被展开，
+// 外层属性优先于匿名结构体中的同名属性
+func getStructAttrs(elemType reflect.Type) []*structAttr {
+    return doGetStructAttrs(elemType, map[reflect.Type]bool{elemType : true})
+}
+
+// 获取结构体类型的所有属性，visited为当前展开路径中的结构体类型，
+// 用于忽略循环嵌套的匿名结构体(例如: type Node struct{ *Node })，防止无限递归
+func doGetStructAttrs(elemType reflect.Type, visited map[reflect.Type]bool) []*structAttr {
+    attrs     := make([]*structAttr, 0)
+    names     := make(map[string]bool)
+    anonymous := make([]reflect.StructField, 0)
+    for i := 0; i < elemType.NumField(); i++ {
+        field := elemType.Field(i)
+        if field.Anonymous {
+            anonymous = append(anonymous, field)
+        }
+        // 非公开属性无法赋值
+        if field.PkgPath != "" {
+            continue
+        }
+        names[field.Name] = true
+        attrs = append(attrs, &structAttr {
+            name  : field.Name,
+            index : field.Index,
+        })
+    }
+    for _, field := range anonymous {
+        fieldType := field.Type
+        if fieldType.Kind() == reflect.Ptr {
+            fieldType = fieldType.Elem()
+        }
+        if fieldType.Kind() != reflect.Struct || visited[fieldType] {
+            continue
+        }
+        visited[fieldType] = true
+        subAttrs := doGetStructAttrs(fieldType, visited)
+        delete(visited, fieldType)
+        for _, attr := range subAttrs {
+            if names[attr.name] {
+                continue
+            }
+            names[attr.name] = true
+            attrs = append(attrs, &structAttr {
+                name  : attr.name,
+                index : append(append([]int{}, field.Index...), attr.index...),
+            })
+        }
     }
+    return attrs
+}
+
+// 解析结构体类型的tag(包括匿名结构体展开的属性)，返回tag名称到属性名称的映射
+func getTagMapOfStruct(elemType reflect.Type) map[string]string {
+    tagmap := make(map[string]string)
     // 将struct中定义的属性转换名称构建成tagmap
-    for _, field := range fields {
-        tag := field.Tag("gconv")
+    for _, attr := range getStructAttrs(elemType) {
+        field := elemType.FieldByIndex(attr.index)
+        tag   := field.Tag.Get("gconv")
         if tag == "" {
-            tag = field.Tag("json")
+            tag = field.Tag.Get("json")
         }
         if tag != "" {
             for _, v := range strings.Split(tag, ",") {
-                tagmap[strings.TrimSpace(v)] = field.Name()
+                v = strings.TrimSpace(v)
+                if v == "" || v == "-" || v == "omitempty" {
+                    continue
+                }
+                if _, ok := tagmap[v]; !ok {
+                    tagmap[v] = attr.name
+                }
             }
         }
     }
     return tagmap
 }
 
-// 将参数值绑定到对象指定名称的属性上
-func bindVarToStructAttr(elem reflect.Value, name string, value interface{}) (err error) {
-    structFieldValue := elem.FieldByName(name)
+// 根据索引路径获取结构体属性，路径中的匿名结构体指针为nil时自动创建对象
+func getStructFieldByIndex(elem reflect.Value, index []int) reflect.Value {
+    for i, x := range index {
+        if i > 0 && elem.Kind() == reflect.Ptr {
+            if elem.IsNil() {
+                if !elem.CanSet() {
+                    return reflect.Value{}
+                }
+                elem.Set(reflect.New(elem.Type().Elem()))
+            }
+            elem = elem.Elem()
+        }
+        elem = elem.Field(x)
+    }
+    return elem
+}
+
+// 将参数值绑定到对象指定的属性上
+func bindVarToStructAttr(elem reflect.Value, attr *structAttr, value interface{}, strict bool) error {
     // 键名与对象属性匹配检测，map中如果有struct不存在的属性，那么不做处理，直接return
-    if !structFieldValue.IsValid() {
+    if attr == nil {
         return nil
     }
-    // CanSet的属性必须为公开属性(首字母大写)
-    if !structFieldValue.CanSet() {
+    structFieldValue := getStructFieldByIndex(elem, attr.index)
+    if !structFieldValue.IsValid() || !structFieldValue.CanSet() {
         return nil
     }
-    // 必须将value转换为struct属性的数据类型，这里必须用到gconv包
-    defer func() {
-        // 如果转换失败，那么可能是类型不匹配造成(例如属性包含自定义类型)，那么执行递归转换
-        if recover() != nil {
-            err = bindVarToReflectValue(structFieldValue, value)
-        }
-    }()
-    structFieldValue.Set(reflect.ValueOf(Convert(value, structFieldValue.Type().String())))
+    if err := bindVarToValue(structFieldValue, value, strict); err != nil {
+        return errors.New(fmt.Sprintf(`bind value to attribute "%s" failed: %s`, attr.name, err.Error()))
+    }
     return nil
 }
 
 // 将参数值绑定到对象指定索引位置的属性上
-func bindVarToStructByIndex(elem reflect.Value, index int, value interface{}) (err error) {
-    structFieldValue := elem.FieldByIndex([]int{index})
-    // 键名与对象属性匹配检测
-    if !structFieldValue.IsValid() {
+func bindVarToStructByIndex(elem reflect.Value, index int, value interface{}, strict bool) error {
+    if elem.NumField() <= index {
         return nil
     }
+    structFieldValue := elem.Field(index)
     // CanSet的属性必须为公开属性(首字母大写)
     if !structFieldValue.CanSet() {
         return nil
     }
+    return bindVarToValue(structFieldValue, value, strict)
+}
+
+// 将参数值绑定到反射对象上，优先使用基本类型转换，转换失败时执行反射类型转换
+func bindVarToValue(reflectValue reflect.Value, value interface{}, strict bool) (err error) {
+    if value == nil {
+        return nil
+    }
+    // 类型一致时直接赋值
+    if v := reflect.ValueOf(value); v.Type().AssignableTo(reflectValue.Type()) {
+        reflectValue.Set(v)
+        return nil
+    }
+    switch reflectValue.Kind() {
+        // 复杂类型直接执行反射类型转换
+        case reflect.Struct, reflect.Map, reflect.Ptr:
+            if reflectValue.Type().String() != "time.Time" {
+                return bindVarToReflectValue(reflectValue, value, strict)
+            }
+    }
     // 必须将value转换为struct属性的数据类型，这里必须用到gconv包
     defer func() {
         // 如果转换失败，那么可能是类型不匹配造成(例如属性包含自定义类型)，那么执行递归转换
         if recover() != nil {
-            err = bindVarToReflectValue(structFieldValue, value)
+            err = bindVarToReflectValue(reflectValue, value, strict)
         }
     }()
-    structFieldValue.Set(reflect.ValueOf(Convert(value, structFieldValue.Type().String())))
+    if strict {
+        converted, err := ConvertE(value, reflectValue.Type().String())
+        if err != nil {
+            return err
+        }
+        reflectValue.Set(reflect.ValueOf(converted))
+        return nil
+    }
+    reflectValue.Set(reflect.ValueOf(Convert(value, reflectValue.Type().String())))
     return nil
 }
 
 // 当默认的基本类型转换失败时，通过recover判断后执行反射类型转换(处理复杂类型)
-func bindVarToReflectValue(structFieldValue reflect.Value, value interface{}) error {
+func bindVarToReflectValue(structFieldValue reflect.Value, value interface{}, strict bool) error {
+    if value == nil {
+        return nil
+    }
+    v := reflect.ValueOf(value)
     switch structFieldValue.Kind() {
-        // 属性为结构体
+        // 属性为结构体，参数为结构体时按照属性名称(及标签)转换为map后映射
         case reflect.Struct:
-            Struct(value, structFieldValue)
+            if k := reflect.Indirect(v).Kind(); k == reflect.Struct {
+                if v.Kind() == reflect.Ptr && v.IsNil() {
+                    return nil
+                }
+                value = Map(value)
+            }
+            return doStruct(value, structFieldValue, strict)
 
         // 属性为数组类型
-        case reflect.Slice: fallthrough
-        case reflect.Array:
+        case reflect.Slice, reflect.Array:
+            if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+                v = reflect.ValueOf([]interface{}{value})
+            }
             a := reflect.Value{}
-            v := reflect.ValueOf(value)
-            if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
-                if v.Len() > 0 {
-                    a  = reflect.MakeSlice(structFieldValue.Type(), v.Len(), v.Len())
-                    t := a.Index(0).Type()
-                    for i := 0; i < v.Len(); i++ {
-                        if t.Kind() == reflect.Ptr {
-                            e := reflect.New(t.Elem()).Elem()
-                            Struct(v.Index(i).Interface(), e)
-                            a.Index(i).Set(e.Addr())
-                        } else {
-                            e := reflect.New(t).Elem()
-                            Struct(v.Index(i).Interface(), e)
-                            a.Index(i).Set(e)
-                        }
-                    }
-                }
+            if structFieldValue.Kind() == reflect.Array {
+                a = reflect.New(structFieldValue.Type()).Elem()
             } else {
-                a  = reflect.MakeSlice(structFieldValue.Type(), 1, 1)
-                t := a.Index(0).Type()
-                if t.Kind() == reflect.Ptr {
-                    e := reflect.New(t.Elem()).Elem()
-                    Struct(value, e)
-                    a.Index(0).Set(e.Addr())
-                } else {
-                    e := reflect.New(t).Elem()
-                    Struct(value, e)
-                    a.Index(0).Set(e)
+                a = reflect.MakeSlice(structFieldValue.Type(), v.Len(), v.Len())
+            }
+            for i := 0; i < v.Len() && i < a.Len(); i++ {
+                if err := bindVarToValue(a.Index(i), v.Index(i).Interface(), strict); err != nil {
+                    return err
                 }
             }
             structFieldValue.Set(a)
 
-        // 属性为指针类型
+        // 属性为map类型，键值分别转换
+        case reflect.Map:
+            if v.Kind() == reflect.Ptr || v.Kind() == reflect.Struct {
+                v = reflect.ValueOf(Map(value))
+            }
+            if v.Kind() != reflect.Map {
+                return errors.New(fmt.Sprintf(`cannot convert "%s" to type "%s"`, v.Type().String(), structFieldValue.Type().String()))
+            }
+            mapType := structFieldValue.Type()
+            m       := reflect.MakeMapWithSize(mapType, v.Len())
+            for _, key := range v.MapKeys() {
+                mk := reflect.New(mapType.Key()).Elem()
+                mv := reflect.New(mapType.Elem()).Elem()
+                if err := bindVarToValue(mk, key.Interface(), strict); err != nil {
+                    return err
+                }
+                if err := bindVarToValue(mv, v.MapIndex(key).Interface(), strict); err != nil {
+                    return err
+                }
+                m.SetMapIndex(mk, mv)
+            }
+            structFieldValue.Set(m)
+
+        // 属性为指针类型，已有对象时在原有对象上赋值
         case reflect.Ptr:
-            e := reflect.New(structFieldValue.Type().Elem()).Elem()
-            Struct(value, e)
-            structFieldValue.Set(e.Addr())
+            if v.Kind() == reflect.Ptr && v.IsNil() {
+                return nil
+            }
+            e := structFieldValue
+            if e.IsNil() {
+                e = reflect.New(structFieldValue.Type().Elem())
+            }
+            if err := bindVarToValue(e.Elem(), value, strict); err != nil {
+                return err
+            }
+            structFieldValue.Set(e)
+
+        // 属性为接口类型，直接赋值
+        case reflect.Interface:
+            if !v.Type().Implements(structFieldValue.Type()) {
+                return errors.New(fmt.Sprintf(`type "%s" does not implement "%s"`, v.Type().String(), structFieldValue.Type().String()))
+            }
+            structFieldValue.Set(v)
 
         default:
+            // 基本类型的自定义类型(例如: type Status int)，按照底层类型转换
+            if kind := structFieldValue.Kind(); kind >= reflect.Bool && kind <= reflect.Float64 || kind == reflect.String {
+                converted := Convert(value, kind.String())
+                if strict {
+                    v, err := ConvertE(value, kind.String())
+                    if err != nil {
+                        return err
+                    }
+                    converted = v
+                }
+                structFieldValue.Set(reflect.ValueOf(converted).Convert(structFieldValue.Type()))
+                return nil
+            }
             return errors.New(fmt.Sprintf(`cannot convert to type "%s"`, structFieldValue.Type().String()))
     }
     return nil
 }
-
@@ -0,0 +1,207 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gconv
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+)
+
+// StructToStruct的属性匹配报告
+type StructReport struct {
+    UnmatchedSrc []string // 源对象中没有匹配到目标属性的属性名称
+    UnmatchedDst []string // 目标对象中没有被赋值的属性名称
+}
+
+// 结构体属性信息
+type structField struct {
+    name  string        // 属性名称
+    names []string      // 用于匹配的名称(属性名称及gconv/json标签名称)
+    value reflect.Value // 属性值
+}
+
+// 将src结构体对象的属性复制到不同类型的dst结构体对象中(dst必须为结构体指针)，
+// 第三个参数mapping为非必需，表示src属性名称(或者标签名称)与dst属性名称(或者标签名称)的映射关系。
+// 匹配规则依次为：自定义映射关系、标签名称(gconv/json)、属性名称(不区分大小写并忽略'_')，
+// 属性类型不同时使用gconv的类型转换，嵌套的结构体属性递归复制。
+// 返回的报告中包含未匹配的属性名称，以便发现DTO与模型之间遗漏的属性。
+func StructToStruct(src interface{}, dst interface{}, mapping...map[string]string) (StructReport, error) {
+    report  := StructReport{}
+    srcElem := reflect.ValueOf(src)
+    for srcElem.Kind() == reflect.Ptr {
+        srcElem = srcElem.Elem()
+    }
+    if srcElem.Kind() != reflect.Struct {
+        return report, errors.New(fmt.Sprintf(`src should be type of struct, but got "%s"`, srcElem.Kind()))
+    }
+    dstValue := reflect.ValueOf(dst)
+    if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() || dstValue.Elem().Kind() != reflect.Struct {
+        return report, errors.New(fmt.Sprintf(`dst should be type of struct pointer, but got "%s"`, dstValue.Kind()))
+    }
+    return structToStruct(srcElem, dstValue.Elem(), mapping...)
+}
+
+// 结构体属性复制
+func structToStruct(srcElem reflect.Value, dstElem reflect.Value, mapping...map[string]string) (StructReport, error) {
+    report    := StructReport{}
+    dstFields := getStructFields(dstElem)
+    matched   := make(map[string]bool)
+    // 目标属性的名称索引：标签名称及属性名称优先，其次为不区分大小写的名称
+    dstIndex  := make(map[string]*structField)
+    for i := len(dstFields) - 1; i >= 0; i-- {
+        for _, name := range dstFields[i].names {
+            dstIndex[name] = dstFields[i]
+        }
+    }
+    for _, field := range dstFields {
+        if _, ok := dstIndex[formatFieldName(field.name)]; !ok {
+            dstIndex[formatFieldName(field.name)] = field
+        }
+    }
+    for _, srcField := range getStructFields(srcElem) {
+        dstField := (*structField)(nil)
+        if len(mapping) > 0 && len(mapping[0]) > 0 {
+            for _, name := range srcField.names {
+                if target, ok := mapping[0][name]; ok {
+                    dstField = dstIndex[target]
+                    break
+                }
+            }
+        }
+        if dstField == nil {
+            for _, name := range srcField.names {
+                if dstField = dstIndex[name]; dstField != nil {
+                    break
+                }
+            }
+        }
+        if dstField == nil {
+            dstField = dstIndex[formatFieldName(srcField.name)]
+        }
+        if dstField == nil || matched[dstField.name] {
+            report.UnmatchedSrc = append(report.UnmatchedSrc, srcField.name)
+            continue
+        }
+        matched[dstField.name] = true
+        if err := bindStructFieldValue(dstField.value, srcField.value); err != nil {
+            return report, errors.New(fmt.Sprintf(`convert field "%s" to "%s" failed: %s`, srcField.name, dstField.name, err.Error()))
+        }
+    }
+    for _, field := range dstFields {
+        if !matched[field.name] {
+            report.UnmatchedDst = append(report.UnmatchedDst, field.name)
+        }
+    }
+    return report, nil
+}
+
+// 将源属性值赋值到目标属性上，类型不同时进行类型转换
+func bindStructFieldValue(dst reflect.Value, src reflect.Value) (err error) {
+    if src.Type().AssignableTo(dst.Type()) {
+        dst.Set(src)
+        return nil
+    }
+    // 嵌套的结构体(或者结构体指针)递归复制
+    srcElem := src
+    for srcElem.Kind() == reflect.Ptr {
+        if srcElem.IsNil() {
+            return nil
+        }
+        srcElem = srcElem.Elem()
+    }
+    dstType := dst.Type()
+    if dstType.Kind() == reflect.Ptr {
+        dstType = dstType.Elem()
+    }
+    if srcElem.Kind() == reflect.Struct && dstType.Kind() == reflect.Struct && srcElem.Type().String() != "time.Time" {
+        e := reflect.New(dstType)
+        if _, err := structToStruct(srcElem, e.Elem()); err != nil {
+            return err
+        }
+        if dst.Kind() == reflect.Ptr {
+            dst.Set(e)
+        } else {
+            dst.Set(e.Elem())
+        }
+        return nil
+    }
+    value := srcElem.Interface()
+    defer func() {
+        if recover() != nil {
+            err = bindVarToReflectValue(dst, value, false)
+        }
+    }()
+    dst.Set(reflect.ValueOf(Convert(value, dst.Type().String())))
+    return nil
+}
+
+// 获取结构体所有可访问的公开属性，匿名结构体属性展开，忽略gconv/json标签为"-"的属性
+func getStructFields(elem reflect.Value) []*structField {
+    return doGetStructFields(elem, map[reflect.Type]bool{elem.Type() : true})
+}
+
+// 获取结构体属性，visited为当前展开路径中的结构体类型，用于忽略循环嵌套的匿名结构体
+func doGetStructFields(elem reflect.Value, visited map[reflect.Type]bool) []*structField {
+    fields   := make([]*structField, 0)
+    elemType := elem.Type()
+    for i := 0; i < elem.NumField(); i++ {
+        field := elemType.Field(i)
+        if field.PkgPath != "" && !field.Anonymous {
+            continue
+        }
+        value := elem.Field(i)
+        if field.Anonymous {
+            for value.Kind() == reflect.Ptr && !value.IsNil() {
+                value = value.Elem()
+            }
+            if value.Kind() == reflect.Struct {
+                if !visited[value.Type()] {
+                    visited[value.Type()] = true
+                    fields = append(fields, doGetStructFields(value, visited)...)
+                    delete(visited, value.Type())
+                }
+                continue
+            }
+            if field.PkgPath != "" {
+                continue
+            }
+        }
+        names := []string{field.Name}
+        tag   := field.Tag.Get("gconv")
+        if tag == "" {
+            tag = field.Tag.Get("json")
+            // json:"-,"表示键名为"-"
+            if tag == "-" {
+                continue
+            }
+            if i := strings.Index(tag, ","); i >= 0 {
+                tag = tag[ : i]
+            }
+        } else if tag == "-" {
+            // 标签为"-"的属性不参与转换
+            continue
+        }
+        for _, v := range strings.Split(tag, ",") {
+            if v = strings.TrimSpace(v); v != "" && v != "-" {
+                names = append(names, v)
+            }
+        }
+        fields = append(fields, &structField {
+            name  : field.Name,
+            names : names,
+            value : value,
+        })
+    }
+    return fields
+}
+
+// 格式化属性名称，用于不区分大小写及忽略'_'的匹配
+func formatFieldName(name string) string {
+    return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
@@ -7,22 +7,59 @@
 package gconv
 
 import (
-    "time"
+    "errors"
+    "fmt"
     "github.com/gogf/gf/g/os/gtime"
     "github.com/gogf/gf/g/text/gstr"
+    "math"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+var (
+    // 自动解析时间字符串时，gtime无法识别的情况下依次尝试的标准库时间格式
+    timeLayouts = []string {
+        time.RFC3339Nano,
+        time.RFC1123Z,
+        time.RFC1123,
+        time.RFC850,
+        time.RFC822Z,
+        time.RFC822,
+        time.UnixDate,
+        time.RubyDate,
+        time.ANSIC,
+    }
+    // 纯数字的紧凑日期时间格式，优先于时间戳解析，例如: 20190102、20190102150405
+    compactTimeLayouts = []string {
+        "20060102",
+        "20060102150405",
+    }
+    // 时间字符串结尾的时区信息，例如: Z、+08:00、+0800、CST
+    timeZoneRegex     = regexp.MustCompile(`([zZ]|[+-]\d{2}:?\d{2}|\s[A-Z]{3,5})$`)
+    // 时间长度字符串中的天数，例如: 1d、1.5d
+    durationDayRegex  = regexp.MustCompile(`(\d+(?:\.\d+)?)d`)
 )
 
-// 将变量i转换为time.Time类型
+// 将变量i转换为time.Time类型，转换失败时返回零值
 func Time(i interface{}, format...string) time.Time {
-    return GTime(i, format...).Time
+    t, _ := TimeE(i, format...)
+    return t
 }
 
-// 将变量i转换为time.Time类型
+// 将变量i转换为time.Duration类型(TimeDuration别名)
 func TimeDuration(i interface{}) time.Duration {
-    return time.Duration(Int64(i))
+    return Duration(i)
+}
+
+// 将变量i转换为time.Duration类型，转换失败时返回0
+func Duration(i interface{}) time.Duration {
+    d, _ := DurationE(i)
+    return d
 }
 
-// 将变量i转换为time.Time类型
+// 将变量i转换为*gtime.Time类型
 func GTime(i interface{}, format...string) *gtime.Time {
     s := String(i)
     if len(s) == 0 {
@@ -39,4 +76,141 @@ func GTime(i interface{}, format...string) *gtime.Time {
         t, _ := gtime.StrToTime(s)
         return t
     }
-}
\ No newline at end of file
+}
+
+// 将变量i转换为time.Time类型，转换失败时返回错误。
+// 支持的转换：
+// 1、time.Time/*time.Time/gtime.Time/*gtime.Time；
+// 2、Unix时间戳(整数或者浮点数)，根据数值大小自动识别秒/毫秒/微秒/纳秒；
+// 3、时间字符串，format指定一个或者多个格式时依次使用这些格式解析，格式可以为gtime格式(例如: Y-m-d H:i:s)
+//    或者标准库layout(例如: 2006-01-02 15:04:05)；未指定格式时自动识别常用的日期时间格式及RFC标准格式。
+func TimeE(i interface{}, format...string) (time.Time, error) {
+    return parseTime(i, time.Local, format...)
+}
+
+// 将变量i转换为指定时区的time.Time类型，zone为时区名称(例如: Asia/Shanghai、UTC)，
+// 不包含时区信息的时间字符串按照该时区解析，返回的时间对象也使用该时区。
+func TimeInZoneE(i interface{}, zone string, format...string) (time.Time, error) {
+    location, err := time.LoadLocation(zone)
+    if err != nil {
+        return time.Time{}, err
+    }
+    t, err := parseTime(i, location, format...)
+    if err != nil {
+        return t, err
+    }
+    return t.In(location), nil
+}
+
+// 将变量i转换为time.Duration类型，转换失败时返回错误。
+// 整数及数字字符串的单位为纳秒(与time.Duration一致)，其他字符串格式同time.ParseDuration，
+// 并支持天数单位d，例如: "1.5h"、"90s"、"1h30m"、"2d"。
+func DurationE(i interface{}) (time.Duration, error) {
+    switch value := i.(type) {
+        case nil:
+            return 0, errors.New("cannot convert nil to time.Duration")
+        case time.Duration:
+            return value, nil
+        case *time.Duration:
+            return *value, nil
+        case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+            return time.Duration(Int64(value)), nil
+        case float32, float64:
+            return time.Duration(Float64(value)), nil
+    }
+    s := strings.TrimSpace(String(i))
+    if s == "" {
+        return 0, errors.New("cannot convert empty string to time.Duration")
+    }
+    if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+        return time.Duration(n), nil
+    }
+    if strings.Contains(s, "d") {
+        s = durationDayRegex.ReplaceAllStringFunc(s, func(day string) string {
+            n, _ := strconv.ParseFloat(day[ : len(day) - 1], 64)
+            return strconv.FormatFloat(n*24, 'f', -1, 64) + "h"
+        })
+    }
+    d, err := time.ParseDuration(s)
+    if err != nil {
+        return 0, errors.New(fmt.Sprintf(`cannot convert "%s" to time.Duration: %s`, String(i), err.Error()))
+    }
+    return d, nil
+}
+
+// 将变量i转换为time.Time类型，location为不包含时区信息的时间字符串使用的时区
+func parseTime(i interface{}, location *time.Location, format...string) (time.Time, error) {
+    switch value := i.(type) {
+        case nil:
+            return time.Time{}, errors.New("cannot convert nil to time.Time")
+        case time.Time:
+            return value, nil
+        case *time.Time:
+            return *value, nil
+        case gtime.Time:
+            return value.Time, nil
+        case *gtime.Time:
+            return value.Time, nil
+        case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+            return timestampToTime(Float64(value)), nil
+        case float32, float64:
+            return timestampToTime(Float64(value)), nil
+    }
+    s := strings.TrimSpace(String(i))
+    if s == "" {
+        return time.Time{}, errors.New("cannot convert empty string to time.Time")
+    }
+    if len(format) > 0 {
+        for _, f := range format {
+            // 包含标准库参考时间的格式按照layout解析，否则按照gtime格式解析
+            if strings.Contains(f, "2006") {
+                if t, err := time.ParseInLocation(f, s, location); err == nil {
+                    return t, nil
+                }
+            } else if t, err := gtime.StrToTimeFormat(s, f); err == nil {
+                return timeInLocation(t.Time, s, location), nil
+            }
+        }
+        return time.Time{}, errors.New(fmt.Sprintf(`cannot convert "%s" to time.Time with format "%s"`, s, strings.Join(format, `", "`)))
+    }
+    for _, layout := range compactTimeLayouts {
+        if len(s) == len(layout) {
+            if t, err := time.ParseInLocation(layout, s, location); err == nil {
+                return t, nil
+            }
+        }
+    }
+    if n, err := strconv.ParseFloat(s, 64); err == nil && gstr.IsNumeric(s) {
+        return timestampToTime(n), nil
+    }
+    if t, err := gtime.StrToTime(s); err == nil {
+        return timeInLocation(t.Time, s, location), nil
+    }
+    for _, layout := range timeLayouts {
+        if t, err := time.ParseInLocation(layout, s, location); err == nil {
+            return t, nil
+        }
+    }
+    return time.Time{}, errors.New(fmt.Sprintf(`cannot convert "%s" to time.Time: unsupported time format`, s))
+}
+
+// gtime按照本地时区解析不包含时区信息的时间字符串，这里按照location重新解释该时间
+func timeInLocation(t time.Time, s string, location *time.Location) time.Time {
+    if location == time.Local || timeZoneRegex.MatchString(s) {
+        return t
+    }
+    return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+// Unix时间戳转换为时间对象，根据数值大小自动识别秒/毫秒/微秒/纳秒
+func timestampToTime(n float64) time.Time {
+    switch abs := math.Abs(n); {
+        case abs < 1e11:
+            return time.Unix(0, int64(n*1e9))
+        case abs < 1e14:
+            return time.Unix(0, int64(n*1e6))
+        case abs < 1e17:
+            return time.Unix(0, int64(n*1e3))
+    }
+    return time.Unix(0, int64(n))
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gconv_test
+
+import (
+    "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "testing"
+)
+
+func Test_IntE(t *testing.T) {
+    gtest.Case(t, func() {
+        v, err := gconv.IntE("0")
+        gtest.Assert(err, nil)
+        gtest.Assert(v, 0)
+        v, err = gconv.IntE(" 123 ")
+        gtest.Assert(err, nil)
+        gtest.Assert(v, 123)
+        v, err = gconv.IntE("0x10")
+        gtest.Assert(err, nil)
+        gtest.Assert(v, 16)
+        v, err = gconv.IntE(2.0)
+        gtest.Assert(err, nil)
+        gtest.Assert(v, 2)
+        _, err = gconv.IntE("abc")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.IntE("")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.IntE(nil)
+        gtest.AssertNE(err, nil)
+        _, err = gconv.IntE("1.5")
+        gtest.AssertNE(err, nil)
+
+        i8, err := gconv.Int8E("-128")
+        gtest.Assert(err, nil)
+        gtest.Assert(i8, -128)
+        _, err = gconv.Int8E(300)
+        gtest.AssertNE(err, nil)
+        _, err = gconv.Int64E("99999999999999999999")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.Int64E(uint64(1) << 63)
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_UintE(t *testing.T) {
+    gtest.Case(t, func() {
+        v, err := gconv.Uint64E("18446744073709551615")
+        gtest.Assert(err, nil)
+        gtest.Assert(v == 18446744073709551615, true)
+        u8, err := gconv.Uint8E("255")
+        gtest.Assert(err, nil)
+        gtest.Assert(u8, 255)
+        _, err = gconv.Uint8E("256")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.UintE(-1)
+        gtest.AssertNE(err, nil)
+        _, err = gconv.UintE("-1")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.Uint32E("x")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_FloatE_BoolE(t *testing.T) {
+    gtest.Case(t, func() {
+        f, err := gconv.Float64E("1.25")
+        gtest.Assert(err, nil)
+        gtest.Assert(f, 1.25)
+        _, err = gconv.Float64E("1.2.3")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.Float32E("1e300")
+        gtest.AssertNE(err, nil)
+
+        for _, v := range []interface{}{"yes", "ON", "1", true, 2} {
+            b, err := gconv.BoolE(v)
+            gtest.Assert(err, nil)
+            gtest.Assert(b, true)
+        }
+        for _, v := range []interface{}{"no", "Off", "0", false, 0} {
+            b, err := gconv.BoolE(v)
+            gtest.Assert(err, nil)
+            gtest.Assert(b, false)
+        }
+        _, err = gconv.BoolE("maybe")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_SliceE_MapE_ConvertE(t *testing.T) {
+    gtest.Case(t, func() {
+        ints, err := gconv.IntsE(g.Slice{"1", 2, 3.0})
+        gtest.Assert(err, nil)
+        gtest.Assert(ints, []int{1, 2, 3})
+        _, err = gconv.IntsE([]string{"1", "a"})
+        gtest.AssertNE(err, nil)
+        floats, err := gconv.FloatsE("1.5")
+        gtest.Assert(err, nil)
+        gtest.Assert(floats, []float64{1.5})
+
+        m, err := gconv.MapE(map[int]string{1 : "a"})
+        gtest.Assert(err, nil)
+        gtest.Assert(m, g.Map{"1" : "a"})
+        _, err = gconv.MapE(1)
+        gtest.AssertNE(err, nil)
+
+        v, err := gconv.ConvertE("10", "int8")
+        gtest.Assert(err, nil)
+        gtest.Assert(v, int8(10))
+        _, err = gconv.ConvertE("a", "uint")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_StructE(t *testing.T) {
+    type Item struct {
+        Price float64
+    }
+    type Order struct {
+        Id    int
+        Count uint8
+        Paid  bool
+        Items []Item
+        Tags  map[string]int
+    }
+    gtest.Case(t, func() {
+        order := new(Order)
+        err   := gconv.StructE(g.Map{
+            "id"    : "1",
+            "count" : 2,
+            "paid"  : "yes",
+            "items" : g.Slice{g.Map{"price" : "9.9"}},
+            "tags"  : g.Map{"a" : "1"},
+        }, order)
+        gtest.Assert(err, nil)
+        gtest.Assert(order.Id, 1)
+        gtest.Assert(order.Count, 2)
+        gtest.Assert(order.Paid, true)
+        gtest.Assert(order.Items[0].Price, 9.9)
+        gtest.Assert(order.Tags["a"], 1)
+
+        gtest.AssertNE(gconv.StructE(g.Map{"id" : "abc"}, new(Order)), nil)
+        gtest.AssertNE(gconv.StructE(g.Map{"count" : 1000}, new(Order)), nil)
+        gtest.AssertNE(gconv.StructE(g.Map{"items" : g.Slice{g.Map{"price" : "free"}}}, new(Order)), nil)
+        gtest.AssertNE(gconv.StructE(g.Map{"tags" : g.Map{"a" : "x"}}, new(Order)), nil)
+        // 默认的Struct方法不检查转换错误
+        gtest.Assert(gconv.Struct(g.Map{"id" : "abc"}, new(Order)), nil)
+    })
+}
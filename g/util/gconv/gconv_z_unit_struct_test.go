@@ -314,3 +314,176 @@ func Test_Struct_Attr_Struct_Slice_Ptr(t *testing.T) {
         }
     })
 }
+
+func Test_StructToStruct(t *testing.T) {
+    type Base struct {
+        Id int64
+    }
+    type Address struct {
+        City string
+    }
+    type UserModel struct {
+        Base
+        Name     string
+        Password string
+        Age      string
+        Address  Address
+        Created  string `json:"created_at"`
+    }
+    type Location struct {
+        City string
+    }
+    type UserDto struct {
+        Id        int
+        UserName  string
+        Age       int
+        Location  *Location `gconv:"address"`
+        CreatedAt string    `json:"created_at"`
+        Remark    string
+    }
+    gtest.Case(t, func() {
+        model := &UserModel {
+            Base     : Base{Id : 1},
+            Name     : "john",
+            Password : "123456",
+            Age      : "18",
+            Address  : Address{City : "Chengdu"},
+            Created  : "2019-01-01",
+        }
+        dto := new(UserDto)
+        report, err := gconv.StructToStruct(model, dto, map[string]string{"Name" : "UserName"})
+        gtest.Assert(err, nil)
+        gtest.Assert(dto.Id,            1)
+        gtest.Assert(dto.UserName,      "john")
+        gtest.Assert(dto.Age,           18)
+        gtest.Assert(dto.Location.City, "Chengdu")
+        gtest.Assert(dto.CreatedAt,     "2019-01-01")
+        gtest.Assert(report.UnmatchedSrc, []string{"Password"})
+        gtest.Assert(report.UnmatchedDst, []string{"Remark"})
+
+        _, err = gconv.StructToStruct(model, *dto)
+        gtest.AssertNE(err, nil)
+        _, err = gconv.StructToStruct(1, dto)
+        gtest.AssertNE(err, nil)
+    })
+    // 标签为"-"的属性不参与转换
+    gtest.Case(t, func() {
+        type User struct {
+            Name     string
+            Password string `json:"-"`
+            Token    string `gconv:"-"`
+        }
+        type UserDto struct {
+            Name     string
+            Password string
+            Token    string `json:"token"`
+            Secret   string `gconv:"-" json:"secret"`
+        }
+        dto    := &UserDto{Secret : "secret"}
+        report, err := gconv.StructToStruct(&User{Name : "john", Password : "123456", Token : "token"}, dto)
+        gtest.Assert(err, nil)
+        gtest.Assert(dto.Name,     "john")
+        gtest.Assert(dto.Password, "")
+        gtest.Assert(dto.Token,    "")
+        gtest.Assert(dto.Secret,   "secret")
+        gtest.Assert(len(report.UnmatchedSrc), 0)
+        gtest.Assert(report.UnmatchedDst, []string{"Password", "Token"})
+    })
+}
+
+// 多层嵌套的struct/struct指针/匿名struct/struct的map，每一层均支持标签
+func Test_Struct_Deep(t *testing.T) {
+    type Status int
+    type Base struct {
+        Id         int    `gconv:"uid"`
+        CreateTime string `json:"create_time"`
+    }
+    type Meta struct {
+        Version int
+    }
+    type Address struct {
+        City   string `gconv:"city_name"`
+        Zip    *int
+        Status Status
+    }
+    type User struct {
+        Base
+        *Meta
+        Name      string
+        Address   *Address
+        Addresses []Address
+        Tags      [2]string
+        Contacts  map[string]*Address
+    }
+    gtest.Case(t, func() {
+        user   := new(User)
+        params := g.Map{
+            "uid"         : "10",
+            "create_time" : "2019-06-01",
+            "version"     : 2,
+            "name"        : "john",
+            "address"     : g.Map{"city_name" : "Shanghai", "zip" : "200000", "status" : "1"},
+            "addresses"   : g.Slice{
+                g.Map{"city_name" : "Beijing"},
+                g.Map{"city_name" : "Shenzhen"},
+            },
+            "tags"        : g.Slice{"a", "b", "c"},
+            "contacts"    : g.Map{
+                "home" : g.Map{"city_name" : "Hangzhou"},
+            },
+        }
+        gtest.Assert(gconv.Struct(params, user), nil)
+        gtest.Assert(user.Id, 10)
+        gtest.Assert(user.CreateTime, "2019-06-01")
+        gtest.Assert(user.Meta != nil, true)
+        gtest.Assert(user.Version, 2)
+        gtest.Assert(user.Name, "john")
+        gtest.Assert(user.Address.City, "Shanghai")
+        gtest.Assert(*user.Address.Zip, 200000)
+        gtest.Assert(user.Address.Status == Status(1), true)
+        gtest.Assert(len(user.Addresses), 2)
+        gtest.Assert(user.Addresses[1].City, "Shenzhen")
+        gtest.Assert(user.Tags[0], "a")
+        gtest.Assert(user.Tags[1], "b")
+        gtest.Assert(user.Contacts["home"].City, "Hangzhou")
+    })
+    // 匿名struct通过其名称整体赋值，以及struct类型参数按照属性名称转换
+    gtest.Case(t, func() {
+        type Profile struct {
+            Name    string
+            Address Address
+        }
+        user := new(User)
+        gtest.Assert(gconv.Struct(g.Map{"Base" : g.Map{"uid" : 1}}, user), nil)
+        gtest.Assert(user.Id, 1)
+        gtest.Assert(user.Meta == nil, true)
+        profile := new(Profile)
+        gtest.Assert(gconv.Struct(g.Map{"Address" : &Base{Id : 2}}, profile), nil)
+        gtest.Assert(profile.Address.City, "")
+        gtest.Assert(gconv.Struct(g.Map{"Address" : struct{ City string }{"Wuhan"}}, profile), nil)
+        gtest.Assert(profile.Address.City, "Wuhan")
+        gtest.AssertNE(gconv.Struct(g.Map{"Contacts" : 1}, user), nil)
+    })
+}
+
+// 自身嵌套的匿名结构体不应当无限递归
+func Test_Struct_SelfEmbedded(t *testing.T) {
+    type Node struct {
+        *Node
+        Name string `gconv:"title"`
+    }
+    gtest.Case(t, func() {
+        node := new(Node)
+        gtest.Assert(gconv.Struct(g.Map{"title" : "john"}, node), nil)
+        gtest.Assert(node.Name, "john")
+        gtest.Assert(node.Node == nil, true)
+    })
+    gtest.Case(t, func() {
+        src      := &Node{Name : "john"}
+        src.Node  = src
+        dst      := new(Node)
+        _, err   := gconv.StructToStruct(src, dst)
+        gtest.Assert(err, nil)
+        gtest.Assert(dst.Name, "john")
+    })
+}
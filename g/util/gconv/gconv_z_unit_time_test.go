@@ -23,3 +23,92 @@ func Test_Time(t *testing.T) {
         gtest.AssertEQ(gconv.TimeDuration(100), 100*time.Nanosecond)
     })
 }
+
+func Test_TimeE(t *testing.T) {
+    gtest.Case(t, func() {
+        t1, err := gconv.TimeE("2019-06-01 12:30:00")
+        gtest.Assert(err, nil)
+        gtest.Assert(t1.Equal(time.Date(2019, 6, 1, 12, 30, 0, 0, time.Local)), true)
+        // 多种格式依次尝试
+        t2, err := gconv.TimeE("01/06/2019", "Y-m-d", "02/01/2006")
+        gtest.Assert(err, nil)
+        gtest.Assert(t2.Equal(time.Date(2019, 6, 1, 0, 0, 0, 0, time.Local)), true)
+        t3, err := gconv.TimeE("Sat, 01 Jun 2019 12:30:00 GMT")
+        gtest.Assert(err, nil)
+        gtest.Assert(t3.Equal(time.Date(2019, 6, 1, 12, 30, 0, 0, time.UTC)), true)
+        // 时间戳自动识别秒/毫秒/纳秒
+        for _, v := range []interface{}{1559392200, "1559392200000", int64(1559392200000000000)} {
+            t4, err := gconv.TimeE(v)
+            gtest.Assert(err, nil)
+            gtest.Assert(t4.Unix(), 1559392200)
+        }
+        t5, err := gconv.TimeE(1559392200.5)
+        gtest.Assert(err, nil)
+        gtest.Assert(t5.UnixNano(), 1559392200500000000)
+        // 紧凑日期时间格式优先于时间戳
+        t6, err := gconv.TimeE("20190102")
+        gtest.Assert(err, nil)
+        gtest.Assert(t6.Equal(time.Date(2019, 1, 2, 0, 0, 0, 0, time.Local)), true)
+        t7, err := gconv.TimeE("20190102150405")
+        gtest.Assert(err, nil)
+        gtest.Assert(t7.Equal(time.Date(2019, 1, 2, 15, 4, 5, 0, time.Local)), true)
+        t8, err := gconv.TimeInZoneE("20190102150405", "UTC")
+        gtest.Assert(err, nil)
+        gtest.Assert(t8.Equal(time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC)), true)
+        // 不是有效日期的8位数字仍然按照时间戳解析
+        t9, err := gconv.TimeE("99999999")
+        gtest.Assert(err, nil)
+        gtest.Assert(t9.Unix(), 99999999)
+        // 解析失败返回错误
+        _, err = gconv.TimeE("not a time")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.TimeE("")
+        gtest.AssertNE(err, nil)
+        _, err = gconv.TimeE("2019-06-01", "H:i:s")
+        gtest.AssertNE(err, nil)
+        gtest.Assert(gconv.Time("not a time").IsZero(), true)
+    })
+}
+
+func Test_TimeInZoneE(t *testing.T) {
+    gtest.Case(t, func() {
+        t1, err := gconv.TimeInZoneE("2019-06-01 12:30:00", "Asia/Shanghai")
+        gtest.Assert(err, nil)
+        gtest.Assert(t1.Location().String(), "Asia/Shanghai")
+        gtest.Assert(t1.Hour(), 12)
+        gtest.Assert(t1.UTC().Hour(), 4)
+        // 包含时区信息的字符串转换为目标时区
+        t2, err := gconv.TimeInZoneE("2019-06-01T12:30:00Z", "Asia/Shanghai")
+        gtest.Assert(err, nil)
+        gtest.Assert(t2.Hour(), 20)
+        t3, err := gconv.TimeInZoneE("01/06/2019 08:00", "UTC", "02/01/2006 15:04")
+        gtest.Assert(err, nil)
+        gtest.Assert(t3.Equal(time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC)), true)
+        _, err = gconv.TimeInZoneE("2019-06-01", "Invalid/Zone")
+        gtest.AssertNE(err, nil)
+    })
+}
+
+func Test_DurationE(t *testing.T) {
+    gtest.Case(t, func() {
+        d, err := gconv.DurationE("1.5h")
+        gtest.Assert(err, nil)
+        gtest.Assert(d == 90*time.Minute, true)
+        d, err = gconv.DurationE("90s")
+        gtest.Assert(err, nil)
+        gtest.Assert(d == 90*time.Second, true)
+        d, err = gconv.DurationE("1d12h")
+        gtest.Assert(err, nil)
+        gtest.Assert(d == 36*time.Hour, true)
+        d, err = gconv.DurationE("100")
+        gtest.Assert(err, nil)
+        gtest.Assert(d == 100*time.Nanosecond, true)
+        d, err = gconv.DurationE(time.Second)
+        gtest.Assert(err, nil)
+        gtest.Assert(d == time.Second, true)
+        _, err = gconv.DurationE("abc")
+        gtest.AssertNE(err, nil)
+        gtest.Assert(gconv.Duration("abc") == 0, true)
+        gtest.Assert(gconv.Duration("2m") == 2*time.Minute, true)
+    })
+}
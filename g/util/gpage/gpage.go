@@ -67,6 +67,26 @@ func New(TotalSize, perPage int,  CurrentPage interface{}, url string, router...
     return page
 }
 
+// 分页查询结果信息接口(例如gdb.Model.ScanAndCount返回的*gdb.Pagination)，
+// totalSize小于0表示未统计总记录数
+type Pagination interface {
+    PageInfo() (totalSize, pageSize, currentPage int, hasMore bool)
+}
+
+// 根据分页查询结果创建分页对象，
+// 注意分页查询跳过总记录数统计时无法生成完整的分页条，只能使用上一页/下一页。
+func NewFromPagination(p Pagination, url string, router...*ghttp.Router) *Page {
+    totalSize, pageSize, currentPage, hasMore := p.PageInfo()
+    if totalSize < 0 {
+        // 未统计总记录数时，按照是否存在下一页估算，以便上一页/下一页链接正常生成
+        totalSize = currentPage * pageSize
+        if hasMore {
+            totalSize++
+        }
+    }
+    return New(totalSize, pageSize, currentPage, url, router...)
+}
+
 // 启用AJAX分页
 func (page *Page) EnableAjax(actionName string) {
     page.AjaxActionName = actionName
@@ -11,12 +11,14 @@ package gvalid
 
 import (
     "github.com/gogf/gf/g/text/gregex"
+    "sort"
     "strings"
 )
 
 /*
 参考：https://laravel.com/docs/5.5/validation#available-validation-rules
 规则如下：
+bail                 格式：bail                                  说明：当前数据遇到第一个校验错误时停止后续规则的校验(标记规则，可以出现在任意位置)
 required             格式：required                              说明：必需参数
 required-if          格式：required-if:field,value,...           说明：必需参数(当任意所给定字段值与所给值相等时，即：当field字段的值为value时，当前验证字段为必须参数)
 required-unless      格式：required-unless:field,value,...       说明：必需参数(当所给定字段值与所给值都不相等时，即：当field字段的值不为value时，当前验证字段为必须参数)
@@ -54,9 +56,14 @@ float                格式：float                                 说明：浮
 boolean              格式：boolean                               说明：布尔值(1,true,on,yes:true | 0,false,off,no,"":false)
 same                 格式：same:field                            说明：参数值必需与field参数的值相同
 different            格式：different:field                       说明：参数值不能与field参数的值相同
+gt-field             格式：gt-field:field                        说明：参数值应当大于field参数的值(数字按照数值比较，日期按照时间比较，其他按照字符串比较)
+gte-field            格式：gte-field:field                       说明：参数值应当大于或等于field参数的值(比较方式同gt-field)
+lt-field             格式：lt-field:field                        说明：参数值应当小于field参数的值(比较方式同gt-field)
+lte-field            格式：lte-field:field                       说明：参数值应当小于或等于field参数的值(比较方式同gt-field)
 in                   格式：in:value1,value2,...                  说明：参数值应该在value1,value2,...中(字符串匹配)
 not-in               格式：not-in:value1,value2,...              说明：参数值不应该在value1,value2,...中(字符串匹配)
 regex                格式：regex:pattern                         说明：参数值应当满足正则匹配规则pattern
+此外，可以通过RegisterRule注册自定义校验规则，注册后的使用方式与内置规则相同。
 */
 
 // 自定义错误信息: map[键名] => 字符串|map[规则]错误信息
@@ -65,7 +72,7 @@ type CustomMsg = map[string]interface{}
 // 解析单条sequence tag，格式: [数值键名/别名@]校验规则[#错误提示]，
 // 其中校验规则如果有多个那么以"|"符号分隔，错误提示同理。
 func parseSequenceTag(tag string) (name, rule, msg string) {
-    match, _ := gregex.MatchString(`\s*((\w+)\s*@){0,1}\s*([^#]+)\s*(#\s*(.*)){0,1}\s*`, tag)
+    match, _ := gregex.MatchString(`\s*(([\w\.\-]+)\s*@){0,1}\s*([^#]*)\s*(#\s*(.*)){0,1}\s*`, tag)
     return strings.TrimSpace(match[2]), strings.TrimSpace(match[3]), strings.TrimSpace(match[5])
 }
 
@@ -98,4 +105,29 @@ func parseSequenceTags(tags []string) (rules map[string]string, msgs map[string]
         }
     }
     return
-}
\ No newline at end of file
+}
+// 获取校验数据键名的校验顺序：errorRules(sequence tag)中声明的顺序优先，
+// 其余的键名(例如map类型的校验规则)按照键名排序并追加到errorRules中，保证校验结果的顺序确定
+func sortRuleKeys(checkRules map[string]string, errorRules *[]string) []string {
+    keys  := make([]string, 0, len(checkRules))
+    added := make(map[string]bool)
+    for _, tag := range *errorRules {
+        name, _, _ := parseSequenceTag(tag)
+        if _, ok := checkRules[name]; ok && !added[name] {
+            added[name] = true
+            keys        = append(keys, name)
+        }
+    }
+    others := make([]string, 0)
+    for key := range checkRules {
+        if !added[key] {
+            others = append(others, key)
+        }
+    }
+    sort.Strings(others)
+    for _, key := range others {
+        keys        = append(keys, key)
+        *errorRules = append(*errorRules, key + "@" + checkRules[key])
+    }
+    return keys
+}
@@ -7,7 +7,6 @@
 package gvalid
 
 import (
-    "github.com/gogf/gf/g/container/gmap"
     "github.com/gogf/gf/g/encoding/gjson"
     "github.com/gogf/gf/g/net/gipv4"
     "github.com/gogf/gf/g/net/gipv6"
@@ -24,9 +23,6 @@ const (
 )
 
 var (
-    // 默认错误消息管理对象(并发安全)
-    errorMsgMap  = gmap.NewStringStringMap()
-
     // 单规则正则对象，这里使用包内部变量存储，不需要多次解析
     ruleRegex, _ = regexp.Compile(gSINGLE_RULE_PATTERN)
 
@@ -47,6 +43,7 @@ var (
     }
     // 所有支持的校验规则
     allSupportedRules = map[string]struct{} {
+        "bail"                      : struct{}{},
         "required"                  : struct{}{},
         "required-if"               : struct{}{},
         "required-unless"           : struct{}{},
@@ -84,10 +81,23 @@ var (
         "boolean"                   : struct{}{},
         "same"                      : struct{}{},
         "different"                 : struct{}{},
+        "gt-field"                  : struct{}{},
+        "gte-field"                 : struct{}{},
+        "lt-field"                  : struct{}{},
+        "lte-field"                 : struct{}{},
         "in"                        : struct{}{},
         "not-in"                    : struct{}{},
         "regex"                     : struct{}{},
     }
+    // 与其他字段关联比较的校验规则
+    fieldRelatedRules = map[string]struct{} {
+        "same"      : struct{}{},
+        "different" : struct{}{},
+        "gt-field"  : struct{}{},
+        "gte-field" : struct{}{},
+        "lt-field"  : struct{}{},
+        "lte-field" : struct{}{},
+    }
     // 布尔Map
     boolMap = map[string]struct{} {
         // true
@@ -109,11 +119,19 @@ var (
 // msgs为自定义错误信息，由于同一条数据的校验规则可能存在多条，为方便调用，参数类型支持 string/map[string]string ，允许传递多个自定义的错误信息，如果类型为string，那么中间使用"|"符号分隔多个自定义错误；
 // params参数为联合校验参数，对于需要联合校验的规则有效，如：required-*、same、different；
 func Check(value interface{}, rules string, msgs interface{}, params...map[string]interface{}) *Error {
+    return doCheck(New(), value, rules, msgs, params...)
+}
+
+// 使用校验对象的设置(错误消息语言、bail模式)检测单条数据的规则
+func doCheck(validator *Validator, value interface{}, rules string, msgs interface{}, params...map[string]interface{}) *Error {
+    locales   := validator.locales
     // 内部会将参数全部转换为字符串类型进行校验
     val       := strings.TrimSpace(gconv.String(value))
     data      := make(map[string]string)
+    rawData   := make(map[string]interface{})
     errorMsgs := make(map[string]string)
-    if len(params) > 0 {
+    if len(params) > 0 && params[0] != nil {
+        rawData = params[0]
         for k, v := range params[0] {
             data[k] = gconv.String(v)
         }
@@ -132,7 +150,7 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
     // 规则项预处理, 主要解决规则中存在的"|"关键字符号
     for i := 0; ; {
         array := strings.Split(ruleItems[i], ":")
-        if _, ok := allSupportedRules[array[0]]; !ok {
+        if !isSupportedRule(array[0]) {
             if i > 0 {
                 ruleItems[i - 1] += "|" + ruleItems[i]
                 ruleItems = append(ruleItems[ : i], ruleItems[i + 1 : ]...)
@@ -146,6 +164,14 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
             break
         }
     }
+    // bail模式：遇到第一个校验错误时停止校验当前数据的后续规则
+    bail := validator.bail
+    for _, item := range ruleItems {
+        if strings.TrimSpace(item) == "bail" {
+            bail = true
+            break
+        }
+    }
     for index := 0; index < len(ruleItems); {
         item    := ruleItems[index]
         results := ruleRegex.FindStringSubmatch(item)
@@ -156,6 +182,10 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
             customMsgMap[ruleKey] = strings.TrimSpace(msgArray[index])
         }
         switch ruleKey {
+            // bail模式标记，不执行校验
+            case "bail":
+                match = true
+
             // 必须字段
             case "required":          fallthrough
             case "required-if":       fallthrough
@@ -170,7 +200,7 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
             case "length":            fallthrough
             case "min-length":        fallthrough
             case "max-length":
-                if msg := checkLength(val, ruleKey, ruleVal, customMsgMap); msg != "" {
+                if msg := checkLength(locales, val, ruleKey, ruleVal, customMsgMap); msg != "" {
                     errorMsgs[ruleKey] = msg
                 } else {
                     match = true
@@ -180,7 +210,7 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
             case "min":               fallthrough
             case "max":               fallthrough
             case "between":
-                if msg := checkSize(val, ruleKey, ruleVal, customMsgMap); msg != "" {
+                if msg := checkSize(locales, val, ruleKey, ruleVal, customMsgMap); msg != "" {
                     errorMsgs[ruleKey] = msg
                 } else {
                     match = true
@@ -234,6 +264,12 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
                     }
                 }
 
+            // 与其他字段值比较大小
+            case "gt-field", "gte-field", "lt-field", "lte-field":
+                if v, ok := data[ruleVal]; ok {
+                    match = compareField(ruleKey, val, strings.TrimSpace(v))
+                }
+
             // 字段值应当在指定范围中
             case "in":
                 array := strings.Split(ruleVal, ",")
@@ -377,7 +413,20 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
                 match = gregex.IsMatchString(`^([0-9A-Fa-f]{2}[\-:]){5}[0-9A-Fa-f]{2}$`, val)
 
             default:
-                errorMsgs[ruleKey] = "Invalid rule name:" + ruleKey
+                // 自定义校验规则
+                if f := getRuleFunc(ruleKey); f != nil {
+                    if err := f(value, ruleVal, rawData); err != nil {
+                        if msg, ok := customMsgMap[ruleKey]; ok {
+                            errorMsgs[ruleKey] = msg
+                        } else {
+                            errorMsgs[ruleKey] = err.Error()
+                        }
+                    } else {
+                        match = true
+                    }
+                } else {
+                    errorMsgs[ruleKey] = "Invalid rule name:" + ruleKey
+                }
         }
 
         // 错误消息整合
@@ -388,9 +437,17 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
                 if msg, ok := customMsgMap[ruleKey]; ok {
                     errorMsgs[ruleKey] = msg
                 } else {
-                    errorMsgs[ruleKey] = errorMsgMap.Get(ruleKey)
+                    errorMsgs[ruleKey] = getErrorMsg(locales, ruleKey)
+                }
+                // 关联字段校验规则的错误信息支持:field占位符
+                if _, ok := fieldRelatedRules[ruleKey]; ok {
+                    errorMsgs[ruleKey] = strings.Replace(errorMsgs[ruleKey], ":field", ruleVal, -1)
                 }
             }
+            // 数据为空时非必需规则的错误会被CheckMap/CheckStruct忽略，因此不能中止后续必需规则的校验
+            if _, ok := mustCheckRulesEvenValueEmpty[ruleKey]; bail && (ok || val != "") {
+                break
+            }
         }
         index++
     }
@@ -404,6 +461,49 @@ func Check(value interface{}, rules string, msgs interface{}, params...map[strin
 }
 
 
+// 判断是否为支持的校验规则(内置规则或者已注册的自定义规则)
+func isSupportedRule(rule string) bool {
+    if _, ok := allSupportedRules[rule]; ok {
+        return true
+    }
+    return getRuleFunc(rule) != nil
+}
+
+// 将字段值与关联字段的值比较大小，两者均为数字时按照数值比较，均为日期时按照时间比较，否则按照字符串比较
+func compareField(ruleKey, value, fieldValue string) bool {
+    result := 0
+    if a, err := strconv.ParseFloat(value, 64); err == nil {
+        if b, err := strconv.ParseFloat(fieldValue, 64); err == nil {
+            switch {
+                case a > b: result = 1
+                case a < b: result = -1
+            }
+            return compareResult(ruleKey, result)
+        }
+    }
+    if a, err := gtime.StrToTime(value); err == nil {
+        if b, err := gtime.StrToTime(fieldValue); err == nil {
+            switch {
+                case a.After(b.Time):  result = 1
+                case a.Before(b.Time): result = -1
+            }
+            return compareResult(ruleKey, result)
+        }
+    }
+    return compareResult(ruleKey, strings.Compare(value, fieldValue))
+}
+
+// 根据比较结果判断是否满足比较规则
+func compareResult(ruleKey string, result int) bool {
+    switch ruleKey {
+        case "gt-field":  return result > 0
+        case "gte-field": return result >= 0
+        case "lt-field":  return result < 0
+        case "lte-field": return result <= 0
+    }
+    return false
+}
+
 // 判断必须字段
 func checkRequired(value, ruleKey, ruleVal string, params map[string]string) bool {
     required := false
@@ -415,7 +515,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当任意所给定字段值与所给值相等时)
         case "required-if":
             required = false
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             // 必须为偶数，才能是键值对匹配
             if len(array)%2 == 0 {
                 for i := 0; i < len(array); {
@@ -434,7 +534,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当所给定字段值与所给值都不相等时)
         case "required-unless":
             required = true
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             // 必须为偶数，才能是键值对匹配
             if len(array)%2 == 0 {
                 for i := 0; i < len(array); {
@@ -453,7 +553,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当所给定任意字段值不为空时)
         case "required-with":
             required = false
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             for i := 0; i < len(array); i++ {
                 if params[array[i]] != "" {
                     required = true
@@ -464,7 +564,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当所给定所有字段值都不为空时)
         case "required-with-all":
             required = true
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             for i := 0; i < len(array); i++ {
                 if params[array[i]] == "" {
                     required = false
@@ -475,7 +575,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当所给定任意字段值为空时)
         case "required-without":
             required = false
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             for i := 0; i < len(array); i++ {
                 if params[array[i]] == "" {
                     required = true
@@ -486,7 +586,7 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
         // 必须字段(当所给定所有字段值都为空时)
         case "required-without-all":
             required = true
-            array   := strings.Split(ruleVal, ",")
+            array   := splitRuleParams(ruleVal)
             for i := 0; i < len(array); i++ {
                 if params[array[i]] != "" {
                     required = false
@@ -501,8 +601,17 @@ func checkRequired(value, ruleKey, ruleVal string, params map[string]string) boo
     }
 }
 
+// 解析规则参数，多个参数使用","分隔，去掉参数两边的空白
+func splitRuleParams(ruleVal string) []string {
+    array := strings.Split(ruleVal, ",")
+    for i, v := range array {
+        array[i] = strings.TrimSpace(v)
+    }
+    return array
+}
+
 // 对字段值长度进行检测
-func checkLength(value, ruleKey, ruleVal string, customMsgMap map[string]string) string {
+func checkLength(locales []string, value, ruleKey, ruleVal string, customMsgMap map[string]string) string {
     msg := ""
     switch ruleKey {
         // 长度范围
@@ -522,7 +631,7 @@ func checkLength(value, ruleKey, ruleVal string, customMsgMap map[string]string)
             }
             if len(value) < min || len(value) > max {
                 if v, ok := customMsgMap[ruleKey]; !ok {
-                    msg = errorMsgMap.Get(ruleKey)
+                    msg = getErrorMsg(locales, ruleKey)
                 } else {
                     msg = v
                 }
@@ -536,14 +645,14 @@ func checkLength(value, ruleKey, ruleVal string, customMsgMap map[string]string)
             if min, err := strconv.Atoi(ruleVal); err == nil {
                 if len(value) < min {
                     if v, ok := customMsgMap[ruleKey]; !ok {
-                        msg = errorMsgMap.Get(ruleKey)
+                        msg = getErrorMsg(locales, ruleKey)
                     } else {
                         msg = v
                     }
                     msg = strings.Replace(msg, ":min", strconv.Itoa(min), -1)
                 }
             } else {
-                msg = "校验参数[" + ruleVal + "]应当为整数类型"
+                msg = strings.Replace(getErrorMsg(locales, "rule-param-integer"), ":param", ruleVal, -1)
             }
 
         // 最大长度
@@ -551,21 +660,21 @@ func checkLength(value, ruleKey, ruleVal string, customMsgMap map[string]string)
             if max, err := strconv.Atoi(ruleVal); err == nil {
                 if len(value) > max {
                     if v, ok := customMsgMap[ruleKey]; !ok {
-                        msg = errorMsgMap.Get(ruleKey)
+                        msg = getErrorMsg(locales, ruleKey)
                     } else {
                         msg = v
                     }
                     msg = strings.Replace(msg, ":max", strconv.Itoa(max), -1)
                 }
             } else {
-                msg = "校验参数[" + ruleVal + "]应当为整数类型"
+                msg = strings.Replace(getErrorMsg(locales, "rule-param-integer"), ":param", ruleVal, -1)
             }
     }
     return msg
 }
 
 // 对字段值大小进行检测
-func checkSize(value, ruleKey, ruleVal string, customMsgMap map[string]string) string {
+func checkSize(locales []string, value, ruleKey, ruleVal string, customMsgMap map[string]string) string {
     msg := ""
     switch ruleKey {
         // 大小范围
@@ -586,7 +695,7 @@ func checkSize(value, ruleKey, ruleVal string, customMsgMap map[string]string) s
             if v, err := strconv.ParseFloat(value, 10); err == nil {
                 if v < min || v > max {
                     if v, ok := customMsgMap[ruleKey]; !ok {
-                        msg = errorMsgMap.Get(ruleKey)
+                        msg = getErrorMsg(locales, ruleKey)
                     } else {
                         msg = v
                     }
@@ -594,7 +703,7 @@ func checkSize(value, ruleKey, ruleVal string, customMsgMap map[string]string) s
                     msg = strings.Replace(msg, ":max", strconv.FormatFloat(max, 'f', -1, 64), -1)
                 }
             } else {
-                msg = "输入参数[" + value + "]应当为数字类型"
+                msg = strings.Replace(getErrorMsg(locales, "value-number"), ":value", value, -1)
             }
 
         // 最小值
@@ -603,17 +712,17 @@ func checkSize(value, ruleKey, ruleVal string, customMsgMap map[string]string) s
                 if v, err := strconv.ParseFloat(value, 10); err == nil {
                     if v < min {
                         if v, ok := customMsgMap[ruleKey]; !ok {
-                            msg = errorMsgMap.Get(ruleKey)
+                            msg = getErrorMsg(locales, ruleKey)
                         } else {
                             msg = v
                         }
                         msg = strings.Replace(msg, ":min", strconv.FormatFloat(min, 'f', -1, 64), -1)
                     }
                 } else {
-                    msg = "输入参数[" + value + "]应当为数字类型"
+                    msg = strings.Replace(getErrorMsg(locales, "value-number"), ":value", value, -1)
                 }
             } else {
-                msg = "校验参数[" + ruleVal + "]应当为数字类型"
+                msg = strings.Replace(getErrorMsg(locales, "rule-param-number"), ":param", ruleVal, -1)
             }
 
         // 最大值
@@ -622,17 +731,17 @@ func checkSize(value, ruleKey, ruleVal string, customMsgMap map[string]string) s
                 if v, err := strconv.ParseFloat(value, 10); err == nil {
                     if v > max {
                         if v, ok := customMsgMap[ruleKey]; !ok {
-                            msg = errorMsgMap.Get(ruleKey)
+                            msg = getErrorMsg(locales, ruleKey)
                         } else {
                             msg = v
                         }
                         msg = strings.Replace(msg, ":max", strconv.FormatFloat(max, 'f', -1, 64), -1)
                     }
                 } else {
-                    msg = "输入参数[" + value + "]应当为数字类型"
+                    msg = strings.Replace(getErrorMsg(locales, "value-number"), ":value", value, -1)
                 }
             } else {
-                msg = "校验参数[" + ruleVal + "]应当为数字类型"
+                msg = strings.Replace(getErrorMsg(locales, "rule-param-number"), ":param", ruleVal, -1)
             }
     }
     return msg
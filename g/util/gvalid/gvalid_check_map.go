@@ -15,6 +15,11 @@ import (
 // rules参数支持 []string / map[string]string 类型，前面一种类型支持返回校验结果顺序(具体格式参考struct tag)，后一种不支持；
 // rules参数中得 map[string]string 是一个2维的关联数组，第一维键名为参数键名，第二维为带有错误的校验规则名称，值为错误信息。
 func CheckMap(params interface{}, rules interface{}, msgs...CustomMsg) *Error {
+    return doCheckMap(New(), params, rules, msgs...)
+}
+
+// 使用校验对象的设置(错误消息语言、bail模式)检测键值对参数Map
+func doCheckMap(validator *Validator, params interface{}, rules interface{}, msgs...CustomMsg) *Error {
     // 将参数转换为 map[string]interface{}类型
     data := gconv.Map(params)
     if data == nil {
@@ -77,7 +82,8 @@ func CheckMap(params interface{}, rules interface{}, msgs...CustomMsg) *Error {
     // 开始执行校验: 以校验规则作为基础进行遍历校验
     value := (interface{})(nil)
     // 这里的rule变量为多条校验规则，不包含名字或者错误信息定义
-    for key, rule := range checkRules {
+    for _, key := range sortRuleKeys(checkRules, &errorRules) {
+        rule := checkRules[key]
         // 如果规则为空，那么不执行校验
         if len(rule) == 0 {
             continue
@@ -86,7 +92,7 @@ func CheckMap(params interface{}, rules interface{}, msgs...CustomMsg) *Error {
         if v, ok := data[key]; ok {
             value = v
         }
-        if e := Check(value, rule, customMsgs[key], data); e != nil {
+        if e := doCheck(validator, value, rule, customMsgs[key], data); e != nil {
             _, item := e.FirstItem()
             // 如果值为nil|""，并且不需要require*验证时，其他验证失效
             if value == nil || gconv.String(value) == "" {
@@ -108,6 +114,10 @@ func CheckMap(params interface{}, rules interface{}, msgs...CustomMsg) *Error {
             for k, v := range item {
                 errorMaps[key][k] = v
             }
+            // bail模式下遇到第一个校验错误时停止校验
+            if validator.bail {
+                break
+            }
         }
     }
     if len(errorMaps) > 0 {
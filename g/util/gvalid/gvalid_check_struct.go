@@ -9,12 +9,45 @@ package gvalid
 import (
     "github.com/gogf/gf/g/util/gconv"
     "github.com/gogf/gf/third/github.com/fatih/structs"
+    "reflect"
+    "sort"
     "strings"
+    "time"
 )
 
+var (
+    // 不需要递归校验的结构体类型
+    timeType = reflect.TypeOf(time.Time{})
+)
+
+// 递归校验时已经进入的指针/map，地址相同的匿名属性类型不同，因此同时记录类型
+type visitKey struct {
+    ptr uintptr
+    typ reflect.Type
+}
+
 // 校验struct对象属性，object参数也可以是一个指向对象的指针，返回值同CheckMap方法。
 // struct的数据校验结果信息是顺序的。
+// 属性为struct(指针)、struct的slice/array或者map时会递归校验，错误项的键名为带层级的路径，
+// 例如: items.2.price(层级名称优先使用gvalid标签中的别名，可以使用仅包含别名的标签，例如: gvalid:"items@"，
+// 其次为param及json标签中的名称，最后为属性名称)；
+// 匿名struct的属性不增加层级。
+// rules及msgs参数仅对当前层级有效。
 func CheckStruct(object interface{}, rules interface{}, msgs...CustomMsg) *Error {
+    return doCheckStruct(New(), object, rules, msgs...)
+}
+
+// 使用校验对象的设置(错误消息语言、bail模式)校验struct对象属性
+func doCheckStruct(validator *Validator, object interface{}, rules interface{}, msgs...CustomMsg) *Error {
+    visited := make(map[visitKey]bool)
+    if v := reflect.ValueOf(object); v.Kind() == reflect.Ptr && !v.IsNil() {
+        visited[visitKey{v.Pointer(), v.Type()}] = true
+    }
+    return checkStruct(validator, object, rules, visited, msgs...)
+}
+
+// 校验struct对象属性，visited为当前递归路径上的指针/map，用于防止循环引用(例如: n.Parent = n)导致无限递归
+func checkStruct(validator *Validator, object interface{}, rules interface{}, visited map[visitKey]bool, msgs...CustomMsg) *Error {
     fields       := structs.Fields(object)
     params       := make(map[string]interface{})
     checkRules   := make(map[string]string)
@@ -117,12 +150,17 @@ func CheckStruct(object interface{}, rules interface{}, msgs...CustomMsg) *Error
     // 开始执行校验: 以校验规则作为基础进行遍历校验
     value := (interface{})(nil)
     // 这里的rule变量为多条校验规则，不包含名字或者错误信息定义
-    for key, rule := range checkRules {
+    for _, key := range sortRuleKeys(checkRules, &errorRules) {
+        rule := checkRules[key]
+        // 如果规则为空(例如仅用于指定别名的标签)，那么不执行校验
+        if len(rule) == 0 {
+            continue
+        }
         value = nil
         if v, ok := params[key]; ok {
             value = v
         }
-        if e := Check(value, rule, customMsgs[key], params); e != nil {
+        if e := doCheck(validator, value, rule, customMsgs[key], params); e != nil {
             _, item := e.FirstItem()
             // 如果值为nil|""，并且不需要require*验证时，其他验证失效
             if value == nil || gconv.String(value) == "" {
@@ -144,10 +182,153 @@ func CheckStruct(object interface{}, rules interface{}, msgs...CustomMsg) *Error
             for k, v := range item {
                 errorMaps[key][k] = v
             }
+            // bail模式下遇到第一个校验错误时停止校验
+            if validator.bail {
+                break
+            }
+        }
+    }
+    // 递归校验嵌套的struct属性
+    for _, field := range fields {
+        path := ""
+        if !field.IsEmbedded() {
+            path = getFieldPathName(field)
         }
+        checkNested(validator, reflect.ValueOf(field.Value()), path, &errorRules, errorMaps, visited)
     }
     if len(errorMaps) > 0 {
         return newError(errorRules, errorMaps)
     }
     return nil
 }
+
+// 递归校验嵌套的struct(指针)、slice/array及map，校验结果以path为前缀合并到errorRules及errorMaps中
+func checkNested(validator *Validator, value reflect.Value, path string, errorRules *[]string, errorMaps ErrorMap, visited map[visitKey]bool) {
+    // bail模式下已经存在校验错误时不再继续校验
+    if validator.bail && len(errorMaps) > 0 {
+        return
+    }
+    for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+        if value.IsNil() {
+            return
+        }
+        if value.Kind() == reflect.Ptr {
+            // 循环引用的对象已经在当前路径上校验，不再重复进入
+            key := visitKey{value.Pointer(), value.Type()}
+            if visited[key] {
+                return
+            }
+            visited[key] = true
+            defer delete(visited, key)
+        }
+        value = value.Elem()
+    }
+    if !value.IsValid() || !hasNestedStruct(value.Type()) {
+        return
+    }
+    if value.Kind() == reflect.Map && !value.IsNil() {
+        key := visitKey{value.Pointer(), value.Type()}
+        if visited[key] {
+            return
+        }
+        visited[key] = true
+        defer delete(visited, key)
+    }
+    switch value.Kind() {
+        case reflect.Struct:
+            e := checkStruct(validator, value.Interface(), nil, visited)
+            if e == nil {
+                return
+            }
+            mergeError(e, path, errorRules, errorMaps)
+
+        case reflect.Slice, reflect.Array:
+            for i := 0; i < value.Len(); i++ {
+                checkNested(validator, value.Index(i), joinPath(path, gconv.String(i)), errorRules, errorMaps, visited)
+            }
+
+        case reflect.Map:
+            keys := make([]string, 0, value.Len())
+            vals := make(map[string]reflect.Value, value.Len())
+            for _, k := range value.MapKeys() {
+                key      := gconv.String(k.Interface())
+                keys      = append(keys, key)
+                vals[key] = value.MapIndex(k)
+            }
+            sort.Strings(keys)
+            for _, key := range keys {
+                checkNested(validator, vals[key], joinPath(path, key), errorRules, errorMaps, visited)
+            }
+    }
+}
+
+// 将嵌套struct的校验错误以path为前缀合并，保持嵌套校验结果的顺序
+func mergeError(e *Error, path string, errorRules *[]string, errorMaps ErrorMap) {
+    merged := make(map[string]bool)
+    for _, v := range e.rules {
+        name, rule, _ := parseSequenceTag(v)
+        if _, ok := e.errors[name]; ok && !merged[name] {
+            merged[name] = true
+            *errorRules  = append(*errorRules, joinPath(path, name) + "@" + rule)
+        }
+    }
+    // 没有顺序信息的错误项按照键名排序
+    names := make([]string, 0)
+    for name := range e.errors {
+        if !merged[name] {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        rules := make([]string, 0)
+        for rule := range e.errors[name] {
+            rules = append(rules, rule)
+        }
+        sort.Strings(rules)
+        *errorRules = append(*errorRules, joinPath(path, name) + "@" + strings.Join(rules, "|"))
+    }
+    for name, item := range e.errors {
+        errorMaps[joinPath(path, name)] = item
+    }
+}
+
+// 判断类型是否包含需要递归校验的struct(time.Time等值类型的struct除外)
+func hasNestedStruct(t reflect.Type) bool {
+    for t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    switch t.Kind() {
+        case reflect.Struct:
+            return t != timeType && t.String() != "gtime.Time"
+        case reflect.Slice, reflect.Array, reflect.Map:
+            return hasNestedStruct(t.Elem()) || t.Elem().Kind() == reflect.Interface
+        case reflect.Interface:
+            return true
+    }
+    return false
+}
+
+// 获得属性在错误项层级路径中的名称，优先级: gvalid标签别名 > param标签 > json标签 > 属性名称
+func getFieldPathName(field *structs.Field) string {
+    if tag := field.Tag("gvalid"); tag != "" {
+        if name, _, _ := parseSequenceTag(tag); name != "" {
+            return name
+        }
+    }
+    for _, key := range []string{"param", "json"} {
+        name := strings.TrimSpace(strings.Split(field.Tag(key), ",")[0])
+        if name != "" && name != "-" {
+            return name
+        }
+    }
+    return field.Name()
+}
+
+// 拼接错误项的层级路径
+func joinPath(path, name string) string {
+    if path == "" {
+        return name
+    }
+    return path + "." + name
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 自定义校验规则。
+
+package gvalid
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/text/gregex"
+)
+
+// 自定义规则校验方法：
+// value为待校验的原始数据；
+// params为规则参数(规则名称":"之后的内容，例如"taxid:cn"中的"cn")；
+// data为联合校验的全部数据(CheckMap/CheckStruct时为全部参数，Check时为params参数)；
+// 校验失败时返回错误，错误信息将作为默认的校验错误提示(自定义错误提示优先)。
+type RuleFunc func(value interface{}, params string, data map[string]interface{}) error
+
+var (
+    // 自定义规则校验方法(并发安全)
+    customRuleFuncMap = gmap.NewStringInterfaceMap()
+)
+
+// 注册自定义校验规则，注册后可以与内置规则一样在校验规则字符串及struct tag中使用，例如:
+// gvalid.RegisterRule("mobile-cn-strict", func(value interface{}, params string, data map[string]interface{}) error {...})
+// 规则名称只能包含字母、数字、下划线及"-"，且不能与内置规则同名；重复注册时覆盖之前注册的方法。
+func RegisterRule(rule string, f RuleFunc) error {
+    if !gregex.IsMatchString(`^[\w-]+$`, rule) {
+        return errors.New(fmt.Sprintf(`invalid rule name "%s"`, rule))
+    }
+    if _, ok := allSupportedRules[rule]; ok {
+        return errors.New(fmt.Sprintf(`rule "%s" is a builtin rule`, rule))
+    }
+    if f == nil {
+        return errors.New(fmt.Sprintf(`rule function for "%s" cannot be nil`, rule))
+    }
+    customRuleFuncMap.Set(rule, f)
+    return nil
+}
+
+// 删除已注册的自定义校验规则
+func DeleteRule(rule string) {
+    customRuleFuncMap.Remove(rule)
+}
+
+// 获取已注册的自定义校验规则方法，不存在时返回nil
+func getRuleFunc(rule string) RuleFunc {
+    if f := customRuleFuncMap.Get(rule); f != nil {
+        return f.(RuleFunc)
+    }
+    return nil
+}
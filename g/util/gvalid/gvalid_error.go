@@ -8,7 +8,10 @@
 
 package gvalid
 
-import "strings"
+import (
+    "sort"
+    "strings"
+)
 
 // 校验错误对象
 type Error struct {
@@ -69,11 +72,11 @@ func (e *Error) FirstItem() (key string, msgs map[string]string) {
             }
         }
     }
-    // 无序
-    for k, m := range e.errors {
+    // 无序(按照键名排序)
+    for _, k := range sortedKeys(e.errors) {
         e.firstKey  = k
-        e.firstItem = m
-        return k, m
+        e.firstItem = e.errors[k]
+        return k, e.firstItem
     }
     return "", nil
 }
@@ -95,10 +98,10 @@ func (e *Error) FirstRule() (rule string, err string) {
             }
         }
     }
-    // 无序
-    for _, m := range e.errors {
-        for k, v := range m {
-            return k, v
+    // 无序(按照键名及规则名称排序)
+    for _, k := range sortedKeys(e.errors) {
+        for _, rule := range sortedRules(e.errors[k]) {
+            return rule, e.errors[k][rule]
         }
     }
     return "", ""
@@ -134,11 +137,31 @@ func (e *Error) Strings() (errs []string) {
         }
         return errs
     }
-    // 无序
-    for _, m := range e.errors {
-        for _, err := range m {
-            errs = append(errs, err)
+    // 无序(按照键名及规则名称排序)
+    for _, k := range sortedKeys(e.errors) {
+        for _, rule := range sortedRules(e.errors[k]) {
+            errs = append(errs, e.errors[k][rule])
         }
     }
     return
+}
+
+// 获取排序后的错误项键名
+func sortedKeys(errors ErrorMap) []string {
+    keys := make([]string, 0, len(errors))
+    for k := range errors {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// 获取排序后的错误项规则名称
+func sortedRules(item map[string]string) []string {
+    rules := make([]string, 0, len(item))
+    for rule := range item {
+        rules = append(rules, rule)
+    }
+    sort.Strings(rules)
+    return rules
 }
\ No newline at end of file
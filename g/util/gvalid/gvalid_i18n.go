@@ -0,0 +1,176 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// 校验错误消息的多语言支持。
+
+package gvalid
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gogf/gf/g/container/gmap"
+    "github.com/gogf/gf/g/container/gtype"
+    "github.com/gogf/gf/g/encoding/gjson"
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/util/gconv"
+    "strings"
+)
+
+const (
+    gDEFAULT_LOCALE       = "zh-CN"       // 内置的默认语言
+    gRESOURCE_PATH_PREFIX = "resource://" // 打包资源(gres)中的消息文件路径前缀
+    gLOCALE_FILE_PATTERN  = "*.json,*.xml,*.yaml,*.yml,*.toml"
+)
+
+var (
+    // 各语言的错误消息包: map[语言]*gmap.StringStringMap
+    localeMessages  = gmap.NewStringInterfaceMap()
+    // 各语言的回退语言: map[语言][]string
+    localeFallbacks = gmap.NewStringInterfaceMap()
+    // 默认语言
+    defaultLocale   = gtype.NewString(gDEFAULT_LOCALE)
+)
+
+// 创建使用指定语言错误消息的校验对象，多个语言时按照顺序查找(例如Accept-Language中的语言列表)，例如:
+// gvalid.Locale("en").Check("abc", "integer", nil)
+func Locale(locales...string) *Validator {
+    return New().Locale(locales...)
+}
+
+// 设置默认语言，未指定语言或者指定语言的消息均不存在时使用默认语言的错误消息
+func SetDefaultLocale(locale string) {
+    defaultLocale.Set(locale)
+}
+
+// 获取默认语言
+func GetDefaultLocale() string {
+    return defaultLocale.Val()
+}
+
+// 设置指定语言的错误消息包(与已有消息合并)，msgs为: map[规则名称]错误消息
+func SetLocaleMessages(locale string, msgs map[string]string) {
+    m := localeMessages.GetOrSetFuncLock(formatLocale(locale), func() interface{} {
+        return gmap.NewStringStringMap()
+    }).(*gmap.StringStringMap)
+    m.BatchSet(msgs)
+}
+
+// 设置语言的回退语言，当该语言的消息不存在时依次查找fallbacks中的语言，例如:
+// gvalid.SetLocaleFallback("zh-TW", "zh-HK", "zh-CN")
+// 未设置回退语言时，带地区的语言会自动回退到其主语言(例如: en-US => en)。
+func SetLocaleFallback(locale string, fallbacks...string) {
+    localeFallbacks.Set(formatLocale(locale), fallbacks)
+}
+
+// 从文件加载错误消息包，支持json/xml/yaml/toml格式，文件内容为: 规则名称 => 错误消息，
+// 路径以"resource://"开头或者磁盘中不存在时从打包资源(gres)中读取；
+// locale为消息包语言，不指定时使用文件名称(不含扩展名)，例如: i18n/en-US.json
+func LoadLocaleFile(path string, locale...string) error {
+    content := getLocaleFileContent(path)
+    if content == nil {
+        return errors.New(fmt.Sprintf(`locale file "%s" does not exist`, path))
+    }
+    j, err := gjson.LoadContent(content, gfile.Ext(path))
+    if err != nil {
+        return errors.New(fmt.Sprintf(`parse locale file "%s" failed: %s`, path, err.Error()))
+    }
+    name := ""
+    if len(locale) > 0 && locale[0] != "" {
+        name = locale[0]
+    } else {
+        name = gfile.Basename(path)
+        name = name[ : len(name) - len(gfile.Ext(name))]
+    }
+    msgs := make(map[string]string)
+    for k, v := range j.ToMap() {
+        msgs[k] = gconv.String(v)
+    }
+    SetLocaleMessages(name, msgs)
+    return nil
+}
+
+// 加载目录下的所有消息文件(不递归)，每个文件为一个语言的消息包，语言为文件名称，
+// 路径以"resource://"开头或者磁盘中不存在时从打包资源(gres)中读取
+func LoadLocaleDir(path string) error {
+    files := make([]string, 0)
+    if !strings.HasPrefix(path, gRESOURCE_PATH_PREFIX) && gfile.IsDir(path) {
+        list, err := gfile.ScanDir(path, gLOCALE_FILE_PATTERN)
+        if err != nil {
+            return err
+        }
+        files = list
+    } else {
+        for _, f := range gres.ScanDir(strings.TrimPrefix(path, gRESOURCE_PATH_PREFIX), gLOCALE_FILE_PATTERN) {
+            if !f.IsDir() {
+                files = append(files, gRESOURCE_PATH_PREFIX + f.Path())
+            }
+        }
+    }
+    if len(files) == 0 {
+        return errors.New(fmt.Sprintf(`no locale file found in "%s"`, path))
+    }
+    for _, file := range files {
+        if err := LoadLocaleFile(file); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 获取消息文件内容，文件不存在时返回nil
+func getLocaleFileContent(path string) []byte {
+    if !strings.HasPrefix(path, gRESOURCE_PATH_PREFIX) && gfile.Exists(path) {
+        return []byte(gfile.GetContents(path))
+    }
+    return gres.GetContent(strings.TrimPrefix(path, gRESOURCE_PATH_PREFIX))
+}
+
+// 获取规则的默认错误消息，依次查找locales中的语言(及其回退语言)、默认语言以及内置的默认语言
+func getErrorMsg(locales []string, rule string) string {
+    for _, locale := range localeChain(locales) {
+        if v := localeMessages.Get(locale); v != nil {
+            if msg := v.(*gmap.StringStringMap).Get(rule); msg != "" {
+                return msg
+            }
+        }
+    }
+    return ""
+}
+
+// 生成错误消息的语言查找顺序
+func localeChain(locales []string) []string {
+    chain := make([]string, 0)
+    added := make(map[string]bool)
+    var add func(locale string)
+    add = func(locale string) {
+        locale = formatLocale(locale)
+        if locale == "" || added[locale] {
+            return
+        }
+        added[locale] = true
+        chain = append(chain, locale)
+        if v := localeFallbacks.Get(locale); v != nil {
+            for _, fallback := range v.([]string) {
+                add(fallback)
+            }
+        }
+        if i := strings.Index(locale, "-"); i > 0 {
+            add(locale[ : i])
+        }
+    }
+    for _, locale := range locales {
+        add(locale)
+    }
+    add(defaultLocale.Val())
+    add(gDEFAULT_LOCALE)
+    return chain
+}
+
+// 格式化语言名称，不区分大小写，"_"与"-"等价
+func formatLocale(locale string) string {
+    return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
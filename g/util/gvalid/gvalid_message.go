@@ -47,22 +47,83 @@ var defaultMessages = map[string]string {
     "integer"              : "字段应当为整数",
     "float"                : "字段应当为浮点数",
     "boolean"              : "字段应当为布尔值",
-    "same"                 : "字段值不合法",
-    "different"            : "字段值不合法",
+    "same"                 : "字段值应当与:field字段的值相同",
+    "different"            : "字段值不能与:field字段的值相同",
+    "gt-field"             : "字段值应当大于:field字段的值",
+    "gte-field"            : "字段值应当大于或等于:field字段的值",
+    "lt-field"             : "字段值应当小于:field字段的值",
+    "lte-field"            : "字段值应当小于或等于:field字段的值",
     "in"                   : "字段值不合法",
     "not-in"               : "字段值不合法",
     "regex"                : "字段值不合法",
+    "rule-param-integer"   : "校验参数[:param]应当为整数类型",
+    "rule-param-number"    : "校验参数[:param]应当为数字类型",
+    "value-number"         : "输入参数[:value]应当为数字类型",
 }
 
-// 初始化错误消息管理对象
+// 内置的英文错误消息
+var defaultMessagesEn = map[string]string {
+    "required"             : "The field is required",
+    "required-if"          : "The field is required",
+    "required-unless"      : "The field is required",
+    "required-with"        : "The field is required",
+    "required-with-all"    : "The field is required",
+    "required-without"     : "The field is required",
+    "required-without-all" : "The field is required",
+    "date"                 : "The field is not a valid date",
+    "date-format"          : "The field does not match the date format",
+    "email"                : "The field must be a valid email address",
+    "phone"                : "The field must be a valid mobile phone number",
+    "telephone"            : "The field must be a valid telephone number",
+    "passport"             : "The field must start with a letter, contain only letters, numbers and underscores, and be 6 to 18 characters long",
+    "password"             : "The field must be 6 to 18 visible characters",
+    "password2"            : "The field must be 6 to 18 visible characters, containing upper and lower case letters and numbers",
+    "password3"            : "The field must be 6 to 18 visible characters, containing upper and lower case letters, numbers and special characters",
+    "postcode"             : "The field must be a valid postcode",
+    "id-number"            : "The field must be a valid ID number",
+    "qq"                   : "The field must be a valid QQ number",
+    "ip"                   : "The field must be a valid IP address",
+    "ipv4"                 : "The field must be a valid IPv4 address",
+    "ipv6"                 : "The field must be a valid IPv6 address",
+    "mac"                  : "The field must be a valid MAC address",
+    "url"                  : "The field must be a valid URL",
+    "domain"               : "The field must be a valid domain",
+    "length"               : "The field length must be between :min and :max",
+    "min-length"           : "The field length must be at least :min",
+    "max-length"           : "The field length may not be greater than :max",
+    "between"              : "The field must be between :min and :max",
+    "min"                  : "The field must be at least :min",
+    "max"                  : "The field may not be greater than :max",
+    "json"                 : "The field must be a valid JSON string",
+    "xml"                  : "The field must be a valid XML string",
+    "array"                : "The field must be an array",
+    "integer"              : "The field must be an integer",
+    "float"                : "The field must be a float",
+    "boolean"              : "The field must be a boolean",
+    "same"                 : "The field must match :field",
+    "different"            : "The field must be different from :field",
+    "gt-field"             : "The field must be greater than :field",
+    "gte-field"            : "The field must be greater than or equal to :field",
+    "lt-field"             : "The field must be less than :field",
+    "lte-field"            : "The field must be less than or equal to :field",
+    "in"                   : "The field value is invalid",
+    "not-in"               : "The field value is invalid",
+    "regex"                : "The field value is invalid",
+    "rule-param-integer"   : "The rule parameter [:param] should be an integer",
+    "rule-param-number"    : "The rule parameter [:param] should be a number",
+    "value-number"         : "The value [:value] should be a number",
+}
+
+// 初始化内置的错误消息包
 func init() {
-    errorMsgMap.BatchSet(defaultMessages)
+    SetLocaleMessages(gDEFAULT_LOCALE, defaultMessages)
+    SetLocaleMessages("en", defaultMessagesEn)
 }
 
-// 替换默认的错误提示为指定的自定义提示
+// 替换默认语言(参考SetDefaultLocale)的错误提示为指定的自定义提示
 // 主要作用：
 // 1、便于多语言错误提示设置；
 // 2、默认错误提示信息不满意；
 func SetDefaultErrorMsgs(msgs map[string]string) {
-    errorMsgMap.BatchSet(msgs)
-}
\ No newline at end of file
+    SetLocaleMessages(GetDefaultLocale(), msgs)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gvalid_test
+
+import (
+    "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gvalid"
+    "testing"
+)
+
+func Test_Bail_Rule(t *testing.T) {
+    gtest.Case(t, func() {
+        e := gvalid.Check("abc", "integer|min:10|length:6,16", nil)
+        gtest.Assert(len(e.Map()), 3)
+        e  = gvalid.Check("abc", "bail|integer|min:10|length:6,16", nil)
+        gtest.Assert(len(e.Map()), 1)
+        gtest.Assert(e.Map()["integer"], "字段应当为整数")
+        // bail可以出现在任意位置，自定义错误消息按照规则位置对应
+        e  = gvalid.Check("abc", "integer|length:6,16|bail", "请输入整数|长度错误")
+        gtest.Assert(len(e.Map()), 1)
+        gtest.Assert(e.FirstString(), "请输入整数")
+        // 空值时非必需规则的错误不中止必需规则的校验
+        e  = gvalid.CheckMap(g.Map{}, map[string]string{"id" : "bail|integer|required"})
+        gtest.AssertNE(e, nil)
+        gtest.Assert(e.Map()["required"], "字段不能为空")
+    })
+}
+
+func Test_Bail_Validator(t *testing.T) {
+    rules := []string{
+        "name@required|length:6,16",
+        "age@integer|between:18,30",
+        "email@email",
+    }
+    data := g.Map{
+        "name"  : "john",
+        "age"   : "x",
+        "email" : "x",
+    }
+    gtest.Case(t, func() {
+        e := gvalid.CheckMap(data, rules)
+        gtest.Assert(len(e.Maps()), 3)
+        gtest.Assert(e.Strings(), []string{
+            "字段长度为6到16个字符",
+            "字段应当为整数",
+            "输入参数[x]应当为数字类型",
+            "邮箱地址格式不正确",
+        })
+        e  = gvalid.New().Bail().CheckMap(data, rules)
+        gtest.Assert(len(e.Maps()), 1)
+        gtest.Assert(e.Strings(), []string{"字段长度为6到16个字符"})
+        key, item := e.FirstItem()
+        gtest.Assert(key, "name")
+        gtest.Assert(len(item), 1)
+        e  = gvalid.Locale("en").Bail().CheckMap(data, rules)
+        gtest.Assert(e.FirstString(), "The field length must be between 6 and 16")
+        gtest.Assert(gvalid.New().Bail(false).CheckMap(data, rules).Strings(), gvalid.CheckMap(data, rules).Strings())
+    })
+    // map类型的规则按照键名顺序校验，结果确定
+    gtest.Case(t, func() {
+        rules := map[string]string{
+            "c" : "required",
+            "a" : "required",
+            "b" : "integer|required",
+        }
+        for i := 0; i < 10; i++ {
+            e := gvalid.CheckMap(g.Map{}, rules)
+            key, _ := e.FirstItem()
+            gtest.Assert(key, "a")
+            rule, _ := e.FirstRule()
+            gtest.Assert(rule, "required")
+            e  = gvalid.New().Bail().CheckMap(g.Map{"a" : 1}, rules)
+            key, _  = e.FirstItem()
+            gtest.Assert(key, "b")
+            gtest.Assert(len(e.Maps()), 1)
+        }
+    })
+    // struct嵌套校验
+    gtest.Case(t, func() {
+        type Item struct {
+            Price float64 `gvalid:"price@min:1"`
+        }
+        type Order struct {
+            No    string `gvalid:"no@required"`
+            Items []Item `gvalid:"items@"`
+        }
+        order := &Order{Items : []Item{{0}, {0}}}
+        gtest.Assert(len(gvalid.CheckStruct(order, nil).Maps()), 3)
+        e := gvalid.New().Bail().CheckStruct(order, nil)
+        gtest.Assert(len(e.Maps()), 1)
+        key, _ := e.FirstItem()
+        gtest.Assert(key, "no")
+        order.No = "001"
+        e  = gvalid.New().Bail().CheckStruct(order, nil)
+        gtest.Assert(len(e.Maps()), 1)
+        key, _  = e.FirstItem()
+        gtest.Assert(key, "items.0.price")
+    })
+}
@@ -797,4 +797,75 @@ func Test_Regex2(t *testing.T) {
         gtest.AssertNE(err1.Map()["required"], nil)
         gtest.AssertNE(err2.Map()["min-length"], nil)
     })
-}
\ No newline at end of file
+}
+func Test_CompareField(t *testing.T) {
+    gtest.Case(t, func() {
+        params := g.Map{
+            "min"   : 10,
+            "start" : "2019-06-01",
+            "name"  : "b",
+        }
+        gtest.Assert(gvalid.Check("11", "gt-field:min", nil, params), nil)
+        gtest.AssertNE(gvalid.Check("10", "gt-field:min", nil, params), nil)
+        gtest.Assert(gvalid.Check("10", "gte-field:min", nil, params), nil)
+        // 数值比较而不是字符串比较
+        gtest.AssertNE(gvalid.Check("9", "gte-field:min", nil, params), nil)
+        gtest.Assert(gvalid.Check("9", "lt-field:min", nil, params), nil)
+        gtest.Assert(gvalid.Check("10", "lte-field:min", nil, params), nil)
+        gtest.AssertNE(gvalid.Check("100", "lte-field:min", nil, params), nil)
+        // 日期比较
+        gtest.Assert(gvalid.Check("2019-06-02", "gt-field:start", nil, params), nil)
+        gtest.AssertNE(gvalid.Check("2019-05-31 23:59:59", "gte-field:start", nil, params), nil)
+        // 字符串比较
+        gtest.Assert(gvalid.Check("a", "lt-field:name", nil, params), nil)
+        // 关联字段不存在
+        gtest.AssertNE(gvalid.Check("1", "gt-field:none", nil, params), nil)
+        // 错误信息中的字段名称
+        gtest.Assert(gvalid.Check("1", "gte-field:min", nil, params).FirstString(), "字段值应当大于或等于min字段的值")
+        gtest.Assert(gvalid.Check("1", "same:min", nil, params).FirstString(), "字段值应当与min字段的值相同")
+    })
+}
+
+func Test_ConditionalRules_CheckMap(t *testing.T) {
+    gtest.Case(t, func() {
+        rules := []string{
+            "type@required|in:person,company",
+            "company_name@required-if:type,company",
+            "password@required",
+            "password2@required-with:password|same:password",
+            "nickname@different:name",
+            "end@required-without:days|gte-field:start",
+            "days@required-without:end",
+        }
+        data := g.Map{
+            "type"      : "person",
+            "password"  : "123456",
+            "password2" : "123456",
+            "name"      : "john",
+            "nickname"  : "jo",
+            "start"     : "2019-06-01",
+            "end"       : "2019-06-02",
+        }
+        gtest.Assert(gvalid.CheckMap(data, rules), nil)
+
+        data["type"]      = "company"
+        data["password2"] = "654321"
+        data["nickname"]  = "john"
+        data["end"]       = "2019-05-01"
+        e := gvalid.CheckMap(data, rules)
+        gtest.AssertNE(e, nil)
+        gtest.Assert(len(e.Maps()), 4)
+        gtest.AssertNE(e.Maps()["company_name"]["required-if"], "")
+        gtest.AssertNE(e.Maps()["password2"]["same"], "")
+        gtest.AssertNE(e.Maps()["nickname"]["different"], "")
+        gtest.AssertNE(e.Maps()["end"]["gte-field"], "")
+
+        e = gvalid.CheckMap(g.Map{"type" : "person", "password" : "1"}, rules)
+        gtest.AssertNE(e, nil)
+        gtest.AssertNE(e.Maps()["password2"]["required-with"], "")
+        gtest.AssertNE(e.Maps()["end"]["required-without"], "")
+        gtest.AssertNE(e.Maps()["days"]["required-without"], "")
+        // 规则参数中的空白
+        gtest.AssertNE(gvalid.Check("", "required-if: type, company", nil, g.Map{"type" : "company"}), nil)
+    })
+}
@@ -7,8 +7,10 @@
 package gvalid_test
 
 import (
+    "github.com/gogf/gf/g/test/gtest"
     "github.com/gogf/gf/g/util/gvalid"
     "testing"
+    "time"
 )
 
 func Test_CheckStruct(t *testing.T) {
@@ -32,3 +34,92 @@ func Test_CheckStruct(t *testing.T) {
         t.Error("CheckObject校验失败")
     }
 }
+
+func Test_CheckStruct_Nested(t *testing.T) {
+    type Item struct {
+        Name  string  `gvalid:"name@required"`
+        Price float64 `gvalid:"price@min:0.01"`
+    }
+    type Address struct {
+        City string `gvalid:"city@required"`
+    }
+    type Base struct {
+        Id int `gvalid:"id@min:1"`
+    }
+    type Order struct {
+        Base
+        No       string              `gvalid:"no@required"`
+        Address  *Address            `gvalid:"address@"`
+        Backup   *Address            `param:"backup"`
+        Items    []Item              `gvalid:"items@required"`
+        Extra    map[string]*Item    `json:"extra,omitempty"`
+        Created  time.Time
+    }
+    gtest.Case(t, func() {
+        order := &Order{
+            Base    : Base{Id : 1},
+            No      : "001",
+            Address : &Address{City : "Shanghai"},
+            Items   : []Item{{Name : "apple", Price : 1}},
+            Extra   : map[string]*Item{"gift" : {Name : "pen", Price : 0.5}},
+        }
+        gtest.Assert(gvalid.CheckStruct(order, nil), nil)
+    })
+    gtest.Case(t, func() {
+        order := &Order{
+            Address : &Address{},
+            Backup  : &Address{},
+            Items   : []Item{
+                {Name : "apple", Price : 1},
+                {Name : "pear",  Price : 1},
+                {Name : "",      Price : 0},
+            },
+            Extra   : map[string]*Item{"gift" : {Price : 1}, "none" : nil},
+        }
+        e := gvalid.CheckStruct(order, nil)
+        gtest.AssertNE(e, nil)
+        maps := e.Maps()
+        gtest.Assert(len(maps), 7)
+        gtest.AssertNE(maps["id"]["min"], "")
+        gtest.AssertNE(maps["no"]["required"], "")
+        gtest.AssertNE(maps["address.city"]["required"], "")
+        gtest.AssertNE(maps["backup.city"]["required"], "")
+        gtest.AssertNE(maps["items.2.name"]["required"], "")
+        gtest.AssertNE(maps["items.2.price"]["min"], "")
+        gtest.AssertNE(maps["extra.gift.name"]["required"], "")
+        // 当前层级的错误优先，嵌套的错误按照属性及声明顺序排列
+        key, _ := e.FirstItem()
+        gtest.Assert(key, "no")
+        gtest.Assert(len(e.Strings()), 7)
+    })
+}
+
+// 循环引用的对象不会无限递归
+func Test_CheckStruct_Cyclic(t *testing.T) {
+    type Node struct {
+        Name     string  `gvalid:"name@required"`
+        Parent   *Node
+        Children []*Node `json:"children"`
+    }
+    gtest.Case(t, func() {
+        node         := &Node{}
+        node.Parent   = node
+        node.Children = []*Node{node, {Name : "child", Parent : node}}
+        e := gvalid.CheckStruct(node, nil)
+        gtest.AssertNE(e, nil)
+        maps := e.Maps()
+        gtest.Assert(len(maps), 1)
+        gtest.AssertNE(maps["name"]["required"], "")
+    })
+    // 不同路径上共享的对象仍然会被校验
+    gtest.Case(t, func() {
+        shared := &Node{}
+        root   := Node{Name : "root", Children : []*Node{shared, shared}}
+        e := gvalid.CheckStruct(root, nil)
+        gtest.AssertNE(e, nil)
+        maps := e.Maps()
+        gtest.Assert(len(maps), 2)
+        gtest.AssertNE(maps["children.0.name"]["required"], "")
+        gtest.AssertNE(maps["children.1.name"]["required"], "")
+    })
+}
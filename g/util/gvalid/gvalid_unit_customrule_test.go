@@ -0,0 +1,72 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gvalid_test
+
+import (
+    "errors"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "github.com/gogf/gf/g/util/gvalid"
+    "strings"
+    "testing"
+)
+
+func Test_RegisterRule(t *testing.T) {
+    // 税号：指定前缀，且与country字段关联
+    err := gvalid.RegisterRule("taxid", func(value interface{}, params string, data map[string]interface{}) error {
+        s := gconv.String(value)
+        if params != "" && !strings.HasPrefix(s, params) {
+            return errors.New("税号前缀不正确")
+        }
+        if gconv.String(data["country"]) == "cn" && len(s) != 18 {
+            return errors.New("税号长度不正确")
+        }
+        return nil
+    })
+    gtest.Assert(err, nil)
+    defer gvalid.DeleteRule("taxid")
+
+    gtest.Case(t, func() {
+        gtest.AssertNE(gvalid.RegisterRule("required", func(interface{}, string, map[string]interface{}) error { return nil }), nil)
+        gtest.AssertNE(gvalid.RegisterRule("bad rule", func(interface{}, string, map[string]interface{}) error { return nil }), nil)
+        gtest.AssertNE(gvalid.RegisterRule("nil-rule", nil), nil)
+    })
+
+    gtest.Case(t, func() {
+        gtest.Assert(gvalid.Check("91", "taxid:9", nil), nil)
+        e := gvalid.Check("81", "required|taxid:9", nil)
+        gtest.AssertNE(e, nil)
+        gtest.Assert(e.Map()["taxid"], "税号前缀不正确")
+        // 自定义错误提示优先
+        e  = gvalid.Check("81", "taxid:9", "格式错误")
+        gtest.Assert(e.FirstString(), "格式错误")
+        // 联合校验数据
+        e  = gvalid.Check("9123", "taxid", nil, map[string]interface{}{"country" : "cn"})
+        gtest.Assert(e.FirstString(), "税号长度不正确")
+    })
+
+    gtest.Case(t, func() {
+        e := gvalid.CheckMap(map[string]interface{}{"country" : "cn", "tax" : "123"}, map[string]string{"tax" : "taxid"})
+        gtest.AssertNE(e, nil)
+        gtest.Assert(e.Maps()["tax"]["taxid"], "税号长度不正确")
+        // 空值不执行非required规则
+        gtest.Assert(gvalid.CheckMap(map[string]interface{}{"tax" : ""}, map[string]string{"tax" : "taxid:9"}), nil)
+
+        type Company struct {
+            Tax string `gvalid:"tax@required|taxid:9#请输入税号|税号不合法"`
+        }
+        e = gvalid.CheckStruct(&Company{"8"}, nil)
+        gtest.AssertNE(e, nil)
+        gtest.Assert(e.FirstString(), "税号不合法")
+        gtest.Assert(gvalid.CheckStruct(&Company{"9"}, nil), nil)
+    })
+
+    gtest.Case(t, func() {
+        gvalid.DeleteRule("taxid")
+        gtest.AssertNE(gvalid.Check("9", "taxid", nil), nil)
+    })
+}
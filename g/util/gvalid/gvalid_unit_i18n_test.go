@@ -0,0 +1,81 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gvalid_test
+
+import (
+    "github.com/gogf/gf/g/os/gfile"
+    "github.com/gogf/gf/g/os/gres"
+    "github.com/gogf/gf/g/os/gtime"
+    "github.com/gogf/gf/g/test/gtest"
+    "github.com/gogf/gf/g/util/gconv"
+    "github.com/gogf/gf/g/util/gvalid"
+    "testing"
+)
+
+func Test_Locale(t *testing.T) {
+    gtest.Case(t, func() {
+        // 默认语言
+        gtest.Assert(gvalid.Check("a", "float", nil).FirstString(), "字段应当为浮点数")
+        gtest.Assert(gvalid.Locale("en").Check("a", "float", nil).FirstString(), "The field must be a float")
+        // 带地区的语言回退到主语言，未知语言回退到默认语言
+        gtest.Assert(gvalid.Locale("en-US").Check("a", "float", nil).FirstString(), "The field must be a float")
+        gtest.Assert(gvalid.Locale("fr", "en").Check("a", "float", nil).FirstString(), "The field must be a float")
+        gtest.Assert(gvalid.Locale("fr").Check("a", "float", nil).FirstString(), "字段应当为浮点数")
+        // 占位符替换
+        gtest.Assert(gvalid.Locale("en").Check("abc", "max-length:2", nil).FirstString(), "The field length may not be greater than 2")
+        gtest.Assert(gvalid.Locale("en").Check("a", "min:x", nil).FirstString(), "The rule parameter [x] should be a number")
+        // 自定义错误消息优先
+        gtest.Assert(gvalid.Locale("en").Check("a", "float", "custom").FirstString(), "custom")
+
+        e := gvalid.Locale("en").CheckMap(map[string]interface{}{"age" : "x"}, map[string]string{"age" : "float"})
+        gtest.Assert(e.Maps()["age"]["float"], "The field must be a float")
+        type User struct {
+            Name string `gvalid:"required"`
+        }
+        gtest.Assert(gvalid.Locale("en").CheckStruct(&User{}, nil).FirstString(), "The field is required")
+    })
+    // 部分消息及回退链
+    gtest.Case(t, func() {
+        gvalid.SetLocaleMessages("de", map[string]string{"required" : "Pflichtfeld"})
+        gvalid.SetLocaleFallback("de-AT", "de", "en")
+        gtest.Assert(gvalid.Locale("de_AT").Check("", "required", nil).FirstString(), "Pflichtfeld")
+        gtest.Assert(gvalid.Locale("de-AT").Check("a", "float", nil).FirstString(), "The field must be a float")
+        gtest.Assert(gvalid.Locale("de").Check("a", "float", nil).FirstString(), "字段应当为浮点数")
+
+        gvalid.SetDefaultLocale("en")
+        defer gvalid.SetDefaultLocale("zh-CN")
+        gtest.Assert(gvalid.GetDefaultLocale(), "en")
+        gtest.Assert(gvalid.Check("a", "float", nil).FirstString(), "The field must be a float")
+        gtest.Assert(gvalid.Locale("de").Check("a", "float", nil).FirstString(), "The field must be a float")
+    })
+}
+
+func Test_LoadLocale(t *testing.T) {
+    dir := gfile.TempDir() + gfile.Separator + "gvalid_test_" + gconv.String(gtime.Nanosecond())
+    defer gfile.Remove(dir)
+    gfile.PutContents(dir + "/i18n/ja.json", `{"required" : "必須項目です"}`)
+    gfile.PutContents(dir + "/i18n/ko.yaml", `integer: "정수여야 합니다"`)
+    gfile.PutContents(dir + "/res/es.toml",  `integer = "Debe ser un entero"`)
+    gtest.Case(t, func() {
+        gtest.Assert(gvalid.LoadLocaleDir(dir + "/i18n"), nil)
+        gtest.Assert(gvalid.Locale("ja").Check("", "required", nil).FirstString(), "必須項目です")
+        gtest.Assert(gvalid.Locale("ko").Check("a", "integer", nil).FirstString(), "정수여야 합니다")
+
+        gtest.Assert(gvalid.LoadLocaleFile(dir + "/i18n/ja.json", "ja-JP"), nil)
+        gtest.Assert(gvalid.Locale("ja-JP").Check("", "required", nil).FirstString(), "必須項目です")
+        gtest.AssertNE(gvalid.LoadLocaleFile(dir + "/i18n/none.json"), nil)
+        gtest.AssertNE(gvalid.LoadLocaleDir(dir + "/none"), nil)
+    })
+    // 从打包资源中加载
+    gtest.Case(t, func() {
+        data, err := gres.Pack(dir + "/res", "i18n-res")
+        gtest.Assert(err, nil)
+        gtest.Assert(gres.Add(string(data)), nil)
+        gtest.Assert(gvalid.LoadLocaleDir("resource://i18n-res/res"), nil)
+        gtest.Assert(gvalid.Locale("es").Check("a", "integer", nil).FirstString(), "Debe ser un entero")
+    })
+}
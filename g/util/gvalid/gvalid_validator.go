@@ -0,0 +1,46 @@
+// Copyright 2019 gf Author(https://github.com/gogf/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gvalid
+
+// 校验对象，用于设置单次校验调用的选项(错误消息语言、bail模式)，例如:
+// gvalid.New().Locale("en").Bail().CheckStruct(object, nil)
+type Validator struct {
+    locales []string // 错误消息语言，按照优先级排列
+    bail    bool     // 是否在第一个校验错误时停止校验
+}
+
+// 创建校验对象
+func New() *Validator {
+    return &Validator{}
+}
+
+// 设置错误消息的语言，多个语言时按照顺序查找(例如Accept-Language中的语言列表)
+func (v *Validator) Locale(locales...string) *Validator {
+    v.locales = locales
+    return v
+}
+
+// 设置bail模式：遇到第一个校验错误时停止校验，返回的错误只包含一个字段的一条错误信息
+func (v *Validator) Bail(bail...bool) *Validator {
+    v.bail = len(bail) == 0 || bail[0]
+    return v
+}
+
+// 同Check，使用校验对象的设置
+func (v *Validator) Check(value interface{}, rules string, msgs interface{}, params...map[string]interface{}) *Error {
+    return doCheck(v, value, rules, msgs, params...)
+}
+
+// 同CheckMap，使用校验对象的设置
+func (v *Validator) CheckMap(params interface{}, rules interface{}, msgs...CustomMsg) *Error {
+    return doCheckMap(v, params, rules, msgs...)
+}
+
+// 同CheckStruct，使用校验对象的设置
+func (v *Validator) CheckStruct(object interface{}, rules interface{}, msgs...CustomMsg) *Error {
+    return doCheckStruct(v, object, rules, msgs...)
+}